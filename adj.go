@@ -11,6 +11,9 @@ package graph
 // be left near their use.
 
 import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand"
 	"sort"
 
 	"github.com/soniakeys/bits"
@@ -48,6 +51,30 @@ func (g AdjacencyList) AnyParallel() (has bool, fr, to NI) {
 	return false, -1, -1
 }
 
+// Checksum returns a 64 bit hash of the arc lists of g.
+//
+// The hash depends on the order of g and on the order of arcs within arc
+// lists.  Graphs that are equal in the sense of Equal but with arc lists in
+// different orders will generally have different checksums.
+//
+// Checksum is useful for detecting that a graph has changed, for example to
+// reject results computed on a prior version of a graph.
+func (g AdjacencyList) Checksum() uint64 {
+	h := fnv.New64a()
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(len(g)))
+	h.Write(b[:])
+	for _, to := range g {
+		binary.LittleEndian.PutUint32(b[:], uint32(len(to)))
+		h.Write(b[:])
+		for _, to := range to {
+			binary.LittleEndian.PutUint32(b[:], uint32(to))
+			h.Write(b[:])
+		}
+	}
+	return h.Sum64()
+}
+
 // Complement returns the arc-complement of a simple graph.
 //
 // The result will have an arc for every pair of distinct nodes where there
@@ -110,6 +137,136 @@ func (g AdjacencyList) IsUndirected() (u bool, from, to NI) {
 	return true, -1, -1
 }
 
+// SamplePairsByDistance samples node pairs stratified by path length.
+//
+// Argument buckets lists the path lengths, in arcs, of pairs to sample.
+// Negative lengths are ignored.  SamplePairsByDistance attempts to sample perBucket pairs for each bucket.
+//
+// Sampling proceeds by choosing a random source node and running a
+// breadth first search from the source, bounded by the maximum bucket
+// distance.  For each bucket not yet filled, one target node is chosen at
+// random from those at the bucket distance.  Pairs already sampled are
+// not repeated.  Sampling continues until all buckets are filled or until
+// maxAttempts source nodes have been searched.
+//
+// Pairs are ordered, from source to target.  Returned dists are the path
+// lengths of the pairs, that is, the shortest path length from the first node
+// of the pair to the second.  Returned short has the number of pairs by which
+// each bucket fell short of perBucket, indexed as buckets.
+//
+// If Rand r is nil, the rand package default shared source is used.  Results
+// are deterministic for a seeded r.
+func (g AdjacencyList) SamplePairsByDistance(buckets []int, perBucket int, r *rand.Rand, maxAttempts int) (pairs [][2]NI, dists []int, short []int) {
+	ri := rand.Intn
+	if r != nil {
+		ri = r.Intn
+	}
+	short = make([]int, len(buckets))
+	maxD := -1
+	remain := 0
+	for i, d := range buckets {
+		if d >= 0 {
+			short[i] = perBucket
+			remain += perBucket
+			if d > maxD {
+				maxD = d
+			}
+		}
+	}
+	if len(g) == 0 {
+		return
+	}
+	seen := map[[2]NI]bool{}
+	visited := make([]int, len(g)) // attempt number + 1 when visited
+	var levels [][]NI
+	for a := 1; a <= maxAttempts && remain > 0; a++ {
+		s := NI(ri(len(g)))
+		visited[s] = a
+		levels = append(levels[:0], []NI{s})
+		for d := 0; d < maxD && len(levels[d]) > 0; d++ {
+			var next []NI
+			for _, fr := range levels[d] {
+				for _, to := range g[fr] {
+					if visited[to] != a {
+						visited[to] = a
+						next = append(next, to)
+					}
+				}
+			}
+			levels = append(levels, next)
+		}
+		for i, d := range buckets {
+			if short[i] == 0 || d >= len(levels) || len(levels[d]) == 0 {
+				continue
+			}
+			p := [2]NI{s, levels[d][ri(len(levels[d]))]}
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+			pairs = append(pairs, p)
+			dists = append(dists, d)
+			short[i]--
+			remain--
+		}
+	}
+	return
+}
+
+// SimplePaths enumerates simple paths from node start to node end.
+//
+// A simple path visits no node more than once.  The method calls emit for
+// each simple path from start to end with at most maxLen arcs, passing the
+// nodes of the path starting with start and ending with end.  A maxLen of 0
+// means no limit.  The slice passed to emit is reused on subsequent calls so
+// emit must copy it if it is to be retained.  Enumeration continues as long
+// as emit returns true; if emit returns false, SimplePaths returns
+// immediately.
+//
+// Loops are ignored.  Parallel arcs do not produce distinct paths.  If start
+// and end are the same node, the single node path is emitted.  See Cycles
+// for enumerating paths from a node back to itself.
+//
+// The algorithm is depth first search with a bitmap of nodes on the current
+// path.  The number of paths can be exponential in the size of g.
+//
+// See also LabeledAdjacencyList.SimplePaths.
+func (g AdjacencyList) SimplePaths(start, end NI, maxLen int, emit func([]NI) bool) {
+	on := bits.New(len(g)) // nodes on path
+	path := []NI{start}
+	var df func(NI) bool
+	df = func(n NI) bool {
+		if n == end {
+			return emit(path)
+		}
+		if maxLen > 0 && len(path) > maxLen {
+			return true
+		}
+		on.SetBit(int(n), 1)
+		to := g[n]
+	arc:
+		for i, nb := range to {
+			if on.Bit(int(nb)) == 1 {
+				continue // loop or node already on path
+			}
+			for _, p := range to[:i] {
+				if p == nb {
+					continue arc // parallel arc
+				}
+			}
+			path = append(path, nb)
+			ok := df(nb)
+			path = path[:len(path)-1]
+			if !ok {
+				return false
+			}
+		}
+		on.SetBit(int(n), 0)
+		return true
+	}
+	df(start)
+}
+
 // SortArcLists sorts the arc lists of each node of receiver g.
 //
 // Nodes are not relabeled and the graph remains equivalent.
@@ -137,6 +294,28 @@ func (g LabeledAdjacencyList) ArcsAsEdges() (el []LabeledEdge) {
 	return
 }
 
+// Checksum returns a 64 bit hash of the arc lists of g.
+//
+// The hash covers both arc targets and labels.  As with the unlabeled
+// version, the hash depends on the order of arcs within arc lists.
+func (g LabeledAdjacencyList) Checksum() uint64 {
+	h := fnv.New64a()
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(len(g)))
+	h.Write(b[:])
+	for _, to := range g {
+		binary.LittleEndian.PutUint32(b[:], uint32(len(to)))
+		h.Write(b[:])
+		for _, to := range to {
+			binary.LittleEndian.PutUint32(b[:], uint32(to.To))
+			h.Write(b[:])
+			binary.LittleEndian.PutUint32(b[:], uint32(to.Label))
+			h.Write(b[:])
+		}
+	}
+	return h.Sum64()
+}
+
 // DistanceMatrix constructs a distance matrix corresponding to the arcs
 // of graph g and weight function w.
 //
@@ -159,6 +338,23 @@ func (g LabeledAdjacencyList) DistanceMatrix(w WeightFunc) (d DistanceMatrix) {
 	return
 }
 
+// FloydWarshall finds all pairs shortest paths of g with weight function w.
+//
+// This is a convenience method that constructs a DistanceMatrix with
+// DistanceMatrix(w) and calls DistanceMatrix.FloydWarshallPaths.
+//
+// Returned d[fr][to] is the shortest distance from 'fr' to 'to', +Inf if
+// there is no path.  Returned m encodes the paths.  See PathMatrix.Path.
+//
+// Negative arc weights are allowed.  If g has a negative cycle, results are
+// not meaningful.  Use DistanceMatrix.HasNegativeCycle on the returned d to
+// check.
+func (g LabeledAdjacencyList) FloydWarshall(w WeightFunc) (d DistanceMatrix, m PathMatrix) {
+	d = g.DistanceMatrix(w)
+	m = d.FloydWarshallPaths()
+	return
+}
+
 // HasArcLabel returns true if g has any arc from node `fr` to node `to`
 // with label `l`.
 //
@@ -325,6 +521,49 @@ func (g LabeledAdjacencyList) ParallelArcsLabel(fr, to NI, l LI) (p []int) {
 	return
 }
 
+// SimplePaths enumerates simple paths from node start to node end.
+//
+// A simple path visits no node more than once.  The method calls emit for
+// each simple path from start to end with at most maxLen arcs, passing the
+// arcs of the path, from the arc leaving start to the arc reaching end.
+// A maxLen of 0 means no limit.  The slice passed to emit is reused on
+// subsequent calls so emit must copy it if it is to be retained.  Enumeration
+// continues as long as emit returns true; if emit returns false, SimplePaths
+// returns immediately.
+//
+// Loops are ignored.  Parallel arcs produce distinct paths.  If start and end
+// are the same node, a single empty path is emitted.
+//
+// See AdjacencyList.SimplePaths for more description.
+func (g LabeledAdjacencyList) SimplePaths(start, end NI, maxLen int, emit func([]Half) bool) {
+	on := bits.New(len(g)) // nodes on path
+	var path []Half
+	var df func(NI) bool
+	df = func(n NI) bool {
+		if n == end {
+			return emit(path)
+		}
+		if maxLen > 0 && len(path) >= maxLen {
+			return true
+		}
+		on.SetBit(int(n), 1)
+		for _, h := range g[n] {
+			if on.Bit(int(h.To)) == 1 {
+				continue // loop or node already on path
+			}
+			path = append(path, h)
+			ok := df(h.To)
+			path = path[:len(path)-1]
+			if !ok {
+				return false
+			}
+		}
+		on.SetBit(int(n), 0)
+		return true
+	}
+	df(start)
+}
+
 // Unlabeled constructs the unlabeled graph corresponding to g.
 func (g LabeledAdjacencyList) Unlabeled() AdjacencyList {
 	a := make(AdjacencyList, len(g))
@@ -40,6 +40,26 @@ func (g AdjacencyList) ArcSize() int {
 	return m
 }
 
+// Arcs calls emit for each arc of g.
+//
+// Arcs are visited in order of from-node, and for each from-node, in arc
+// list order.  Arguments to emit are the from-node, the index of the arc
+// within the arc list of the from-node, and the arc itself.  Emit returns
+// true to continue iterating, false to stop.  Arcs returns false if
+// iteration was stopped by emit, true otherwise.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g AdjacencyList) Arcs(emit func(fr NI, x int, to NI) bool) bool {
+	for fr, to := range g {
+		for x, to := range to {
+			if !emit(NI(fr), x, to) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // BoundsOk validates that all arcs in g stay within the slice bounds of g.
 //
 // BoundsOk returns true when no arcs point outside the bounds of g.
@@ -71,10 +91,112 @@ func (g AdjacencyList) BoundsOk() (ok bool, fr NI, to NI) {
 //
 // There are equivalent labeled and unlabeled versions of this method.
 //
-// See also alt.BreadthFirst, a variant with more options, and
+// See also BreadthFirstTraverse, a variant with more options, and
 // alt.BreadthFirst2, a direction optimizing variant.
 func (g AdjacencyList) BreadthFirst(start NI, visit func(NI)) {
-	v := bits.New(len(g))
+	g.bfVisit(start, bits.New(len(g)), visit)
+}
+
+// BreadthFirstTraverse traverses a directed or undirected graph in breadth
+// first order.
+//
+// Traversal starts at node start and visits the nodes reachable from
+// start.  Nodes not reachable from start are not visited.  Argument options
+// can be any number of values returned by TraverseOption functions.  All
+// are supported except PathBits, which is ignored.
+//
+// Nodes are visited level by level.  Visitor functions are called and
+// traversal state is updated in this order:
+//
+// For each level, the level visitors are called with the frontier, the nodes
+// of the level.  At this point each node of the frontier is marked in the
+// Visited bits and has its PathEnd set in the From list, and FromList.MaxLen
+// is the level number.
+//
+// Then for each node n of the frontier the node visitors are called with n,
+// then for each arc from n the arc visitors are called with n and the arc
+// index.  Only after the arc visitors return is the arc followed.  If it
+// leads to a node not yet visited, that node is marked in the Visited bits,
+// has its PathEnd set, and is added to the next frontier.  Thus nodes are
+// marked when discovered, before they are visited.
+//
+// FromList.Leaves is not computed.
+//
+// A false return from any Ok visitor terminates the traversal immediately,
+// with no further state updates.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+//
+// See also alt.BreadthFirst2, a direction optimizing variant.
+func (g AdjacencyList) BreadthFirstTraverse(start NI, options ...TraverseOption) {
+	cf := newTraverseConfig(len(g), start, options)
+	if cf == nil {
+		return
+	}
+	frontier := []NI{start}
+	var next []NI
+	for level := 1; len(frontier) > 0; level++ {
+		if cf.rand != nil {
+			cf.rand.Shuffle(len(frontier), func(i, j int) {
+				frontier[i], frontier[j] = frontier[j], frontier[i]
+			})
+		}
+		if cf.levelVisitor != nil {
+			cf.levelVisitor(level, frontier)
+		}
+		if cf.okLevelVisitor != nil && !cf.okLevelVisitor(level, frontier) {
+			return
+		}
+		for _, n := range frontier {
+			if !cf.visitNode(n) {
+				return
+			}
+			to := g[n]
+			var perm []int
+			if cf.rand != nil {
+				perm = cf.rand.Perm(len(to))
+			}
+			for i := range to {
+				x := i
+				if perm != nil {
+					x = perm[i]
+				}
+				nb := to[x]
+				if cf.hidden(nb) {
+					continue
+				}
+				if !cf.visitArc(n, x) {
+					return
+				}
+				if !cf.visited(nb) {
+					cf.visit(nb, PathEnd{From: n, Len: level + 1})
+					next = append(next, nb)
+				}
+			}
+		}
+		frontier, next = next, nil
+	}
+}
+
+// BreadthFirstMasked traverses the subgraph of g induced by the nodes set
+// in mask in breadth first order.
+//
+// The traversal is that of BreadthFirstTraverse on the induced subgraph,
+// but with node numbers of g and without constructing the subgraph.  Nodes
+// not set in mask are hidden and arcs to them are ignored; arc visitors are
+// not called for them.  If start is not set in mask, no nodes are visited.
+// Argument options are as for BreadthFirstTraverse.  Visited bits and From
+// lists are not marked for hidden nodes.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g AdjacencyList) BreadthFirstMasked(start NI, mask bits.Bits, options ...TraverseOption) {
+	g.BreadthFirstTraverse(start,
+		append([]TraverseOption{masked(mask)}, options...)...)
+}
+
+// bfVisit traverses g breadth first from start, calling visit for each
+// node not already marked in v, and marking it.
+func (g AdjacencyList) bfVisit(start NI, v bits.Bits, visit func(NI)) {
 	v.SetBit(int(start), 1)
 	visit(start)
 	var next []NI
@@ -114,20 +236,167 @@ func (g AdjacencyList) Copy() (c AdjacencyList, ma int) {
 //
 // There are equivalent labeled and unlabeled versions of this method.
 //
-// See also alt.DepthFirst, a variant with more options.
+// See also DepthFirstTraverse, a variant with more options.
 func (g AdjacencyList) DepthFirst(start NI, visit func(NI)) {
-	v := bits.New(len(g))
-	var f func(NI)
-	f = func(n NI) {
-		visit(n)
-		v.SetBit(int(n), 1)
-		for _, to := range g[n] {
-			if v.Bit(int(to)) == 0 {
-				f(to)
+	g.dfPre(start, bits.New(len(g)), visit)
+}
+
+// DepthFirstTraverse traverses a directed or undirected graph in depth
+// first order.
+//
+// Traversal starts at node start and visits the nodes reachable from
+// start.  Nodes not reachable from start are not visited.  Argument options
+// can be any number of values returned by TraverseOption functions.  All
+// are supported except LevelVisitor and OkLevelVisitor, which are ignored.
+//
+// Visitor functions are called and traversal state is updated in this order:
+//
+// When a node n is reached, it is marked in the Visited bits and the
+// PathBits, it has its PathEnd set in the From list, and FromList.MaxLen is
+// updated to include it.  Then the node visitors are called with n.  Then
+// for each arc from n, in order or in random order if Rand is specified,
+// the arc visitors are called with n and the arc index.  Only after the arc
+// visitors return is the arc followed.  If it leads to a node not yet
+// visited, the traversal proceeds from that node.  After all arcs from n
+// are followed, n is cleared in the PathBits.  Thus at any visitor call,
+// PathBits hold exactly the nodes of the path from start to the current
+// node.
+//
+// FromList.Leaves is not computed.
+//
+// A false return from any Ok visitor terminates the traversal immediately,
+// with no further state updates.
+//
+// The traversal is non-recursive, using an explicit stack, so it is
+// suitable for deep graphs such as long paths.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g AdjacencyList) DepthFirstTraverse(start NI, options ...TraverseOption) {
+	cf := newTraverseConfig(len(g), start, options)
+	if cf == nil {
+		return
+	}
+	if cf.pathBits != nil {
+		cf.pathBits.ClearAll()
+	}
+	type frame struct {
+		n    NI    // node
+		i    int   // number of arcs from n traversed
+		perm []int // random order of arcs, if Rand specified
+	}
+	var stack []frame
+	push := func(n NI) bool {
+		if cf.pathBits != nil {
+			cf.pathBits.SetBit(int(n), 1)
+		}
+		if !cf.visitNode(n) {
+			return false
+		}
+		f := frame{n: n}
+		if cf.rand != nil {
+			f.perm = cf.rand.Perm(len(g[n]))
+		}
+		stack = append(stack, f)
+		return true
+	}
+	if !push(start) {
+		return
+	}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		fr := top.n
+		if top.i == len(g[fr]) {
+			if cf.pathBits != nil {
+				cf.pathBits.SetBit(int(fr), 0)
 			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		x := top.i
+		if top.perm != nil {
+			x = top.perm[x]
+		}
+		top.i++
+		to := g[fr][x]
+		if cf.hidden(to) {
+			continue
+		}
+		if !cf.visitArc(fr, x) {
+			return
+		}
+		if cf.visited(to) {
+			continue
+		}
+		cf.visit(to, PathEnd{From: fr, Len: len(stack) + 1})
+		if !push(to) {
+			return
 		}
 	}
-	f(start)
+}
+
+// DepthFirstMasked traverses the subgraph of g induced by the nodes set in
+// mask in depth first order.
+//
+// The traversal is that of DepthFirstTraverse on the induced subgraph, but
+// with node numbers of g and without constructing the subgraph.  Nodes not
+// set in mask are hidden and arcs to them are ignored; arc visitors are not
+// called for them.  If start is not set in mask, no nodes are visited.
+// Argument options are as for DepthFirstTraverse.  Visited bits and From
+// lists are not marked for hidden nodes.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g AdjacencyList) DepthFirstMasked(start NI, mask bits.Bits, options ...TraverseOption) {
+	g.DepthFirstTraverse(start,
+		append([]TraverseOption{masked(mask)}, options...)...)
+}
+
+// dfPre traverses g depth first from start, calling visit for each node in
+// preorder.  Nodes are marked in v as they are visited and nodes already
+// marked are not visited.  The order of visits is that of the conventional
+// recursive algorithm but the traversal uses an explicit stack.
+func (g AdjacencyList) dfPre(start NI, v bits.Bits, visit func(NI)) {
+	type frame struct {
+		n NI  // node
+		x int // index of next arc from n
+	}
+	v.SetBit(int(start), 1)
+	visit(start)
+	stack := []frame{{start, 0}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.x == len(g[top.n]) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		nb := g[top.n][top.x]
+		top.x++
+		if v.Bit(int(nb)) == 0 {
+			v.SetBit(int(nb), 1)
+			visit(nb)
+			stack = append(stack, frame{nb, 0})
+		}
+	}
+}
+
+// Distances returns the number of arcs in a shortest path from start to
+// each node of g.
+//
+// The distance of start is 0.  Nodes not reachable from start have distance
+// -1.  Distances are the levels of TraverseLevels.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g AdjacencyList) Distances(start NI) []int {
+	d := make([]int, len(g))
+	for i := range d {
+		d[i] = -1
+	}
+	g.TraverseLevels(start, func(level int, frontier []NI) bool {
+		for _, n := range frontier {
+			d[n] = level
+		}
+		return true
+	})
+	return d
 }
 
 // Equal compares two graphs for equality.
@@ -176,6 +445,28 @@ func (g AdjacencyList) HasArc(fr, to NI) (bool, int) {
 	return false, -1
 }
 
+// BuildArcIndex builds an index for repeated arc membership queries.
+//
+// Building the index takes O(a log d) time for a arcs and maximum out-degree
+// d.  The index is a snapshot and is invalidated by any later change to g.
+// See ArcIndex.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g AdjacencyList) BuildArcIndex() ArcIndex {
+	x := ArcIndex{start: make([]int, len(g)+1)}
+	for n, to := range g {
+		x.start[n+1] = x.start[n] + len(to)
+	}
+	x.to = make([]NI, 0, x.start[len(g)])
+	for _, to := range g {
+		for _, to := range to {
+			x.to = append(x.to, to)
+		}
+	}
+	x.sort()
+	return x
+}
+
 // AnyLoop identifies if a graph contains a loop, an arc that leads from a
 // a node back to the same node.
 //
@@ -328,6 +619,44 @@ func (g *AdjacencyList) InduceBits(t bits.Bits) *Subgraph {
 		AdjacencyList: g.induceArcs(sub, sup)}
 }
 
+// LiftFromList translates a FromList of subgraph s to its supergraph.
+//
+// The result has a PathEnd for each node of s.Super.  Paths of f are placed
+// at the corresponding supergraph NIs, with From values also translated.
+// Supergraph nodes not in s get From -1 and Len 0.  Leaves, if set in f,
+// are translated as with LiftBits.  MaxLen is copied.
+func (s *Subgraph) LiftFromList(f FromList) FromList {
+	return liftFromList(s.SuperNI, s.Super.Order(), f)
+}
+
+// LiftDist translates a slice of per-node values of subgraph s, such as
+// a distance result, to its supergraph.
+//
+// The result has a value for each node of s.Super.  Supergraph nodes not in
+// s are given the value fill.
+func (s *Subgraph) LiftDist(d []float64, fill float64) []float64 {
+	return liftDist(s.SuperNI, s.Super.Order(), d, fill)
+}
+
+// LiftNodes translates a list of subgraph NIs to supergraph NIs.
+func (s *Subgraph) LiftNodes(l []NI) []NI {
+	return liftNodes(s.SuperNI, l)
+}
+
+// LiftBits translates a bitmap of subgraph nodes to a bitmap of supergraph
+// nodes.
+func (s *Subgraph) LiftBits(b bits.Bits) bits.Bits {
+	return liftBits(s.SuperNI, s.Super.Order(), b)
+}
+
+// PushBits translates a bitmap of supergraph nodes to a bitmap of subgraph
+// nodes.
+//
+// Supergraph nodes not in s are ignored.
+func (s *Subgraph) PushBits(super bits.Bits) bits.Bits {
+	return pushBits(s.SuperNI, super)
+}
+
 // IsSimple checks for loops and parallel arcs.
 //
 // A graph is "simple" if it has no loops or parallel arcs.
@@ -336,7 +665,8 @@ func (g *AdjacencyList) InduceBits(t bits.Bits) *Subgraph {
 // found, simple returns false and a node that represents a counterexample
 // to the graph being simple.
 //
-// See also separate methods AnyLoop and AnyParallel.
+// See also separate methods AnyLoop and AnyParallel, and NonSimpleArc, which
+// identifies an offending arc.
 //
 // There are equivalent labeled and unlabeled versions of this method.
 func (g AdjacencyList) IsSimple() (ok bool, n NI) {
@@ -443,3 +773,30 @@ func (g AdjacencyList) ShuffleArcLists(r *rand.Rand) {
 		}
 	}
 }
+
+// TraverseLevels traverses g in breadth first order, one level at a time.
+//
+// Traversal starts at node start.  The function v is called once for each
+// level with the level number and the frontier, the nodes at that level.
+// If v returns false, the traversal terminates.
+//
+// Levels are numbered by distance from start:  Level 0 is start alone and
+// level k holds the nodes at distance k from start.  Note this differs from
+// the LevelVisitor and OkLevelVisitor options, which number levels by path
+// length in nodes, starting with 1 for start.
+//
+// Nodes of a frontier are in order of discovery, or in random order if the
+// Rand option is given.  The frontier slice is valid only for the duration
+// of the call.
+//
+// Argument options can be any number of values returned by TraverseOption
+// functions, as for BreadthFirstTraverse.  An OkLevelVisitor option is
+// superseded by v.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g AdjacencyList) TraverseLevels(start NI, v func(level int, frontier []NI) bool, options ...TraverseOption) {
+	g.BreadthFirstTraverse(start, append(options[:len(options):len(options)],
+		OkLevelVisitor(func(l int, frontier []NI) bool {
+			return v(l-1, frontier)
+		}))...)
+}
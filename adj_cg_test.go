@@ -126,6 +126,26 @@ func ExampleLabeledAdjacencyList_BreadthFirst() {
 	// 3
 }
 
+func ExampleLabeledAdjacencyList_Distances() {
+	//   <-0->
+	//  /  |  \
+	// v   v   v
+	// 1-->2   4
+	// ^   |   ^
+	// |   v   |
+	// \---3   5
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 2}, {To: 4}},
+		1: {{To: 2}},
+		2: {{To: 3}},
+		3: {{To: 1}},
+		5: {{To: 4}},
+	}
+	fmt.Println(g.Distances(0))
+	// Output:
+	// [0 1 1 2 1 -1]
+}
+
 func ExampleLabeledAdjacencyList_DepthFirst() {
 	//   <-0->
 	//  /  |  \
@@ -536,3 +556,28 @@ func ExampleLabeledSubgraph_AddArc_panic() {
 	// AddArc: NI -1 not in supergraph
 	// AddArc: NI 3 not in supergraph
 }
+
+func ExampleLabeledAdjacencyList_TraverseLevels() {
+	//   <-0->
+	//  /  |  \
+	// v   v   v
+	// 1-->2   4
+	// ^   |   ^
+	// |   v   |
+	// \---3   5
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 2}, {To: 4}},
+		1: {{To: 2}},
+		2: {{To: 3}},
+		3: {{To: 1}},
+		5: {{To: 4}},
+	}
+	g.TraverseLevels(0, func(level int, frontier []graph.NI) bool {
+		fmt.Println(level, frontier)
+		return true
+	})
+	// Output:
+	// 0 [0]
+	// 1 [1 2 4]
+	// 2 [3]
+}
@@ -130,6 +130,26 @@ func ExampleAdjacencyList_BreadthFirst() {
 	// 3
 }
 
+func ExampleAdjacencyList_Distances() {
+	//   <-0->
+	//  /  |  \
+	// v   v   v
+	// 1-->2   4
+	// ^   |   ^
+	// |   v   |
+	// \---3   5
+	g := graph.AdjacencyList{
+		0: {1, 2, 4},
+		1: {2},
+		2: {3},
+		3: {1},
+		5: {4},
+	}
+	fmt.Println(g.Distances(0))
+	// Output:
+	// [0 1 1 2 1 -1]
+}
+
 func ExampleAdjacencyList_DepthFirst() {
 	//   <-0->
 	//  /  |  \
@@ -177,6 +197,20 @@ func ExampleAdjacencyList_HasArc() {
 	// true 1
 }
 
+func ExampleAdjacencyList_BuildArcIndex() {
+	g := graph.AdjacencyList{
+		2: {0, 2, 0, 1, 1},
+	}
+	x := g.BuildArcIndex()
+	fmt.Println(x.HasArc(2, 1), x.ArcCount(2, 1))
+	fmt.Println(x.HasArc(2, 2), x.ArcCount(2, 2)) // test for loop
+	fmt.Println(x.HasArc(0, 2), x.ArcCount(0, 2))
+	// Output:
+	// true 2
+	// true 1
+	// false 0
+}
+
 func ExampleAdjacencyList_InduceBits() {
 	// arcs directed down:
 	//   1
@@ -511,3 +545,28 @@ func ExampleSubgraph_AddArc_panic() {
 	// AddArc: NI -1 not in supergraph
 	// AddArc: NI 3 not in supergraph
 }
+
+func ExampleAdjacencyList_TraverseLevels() {
+	//   <-0->
+	//  /  |  \
+	// v   v   v
+	// 1-->2   4
+	// ^   |   ^
+	// |   v   |
+	// \---3   5
+	g := graph.AdjacencyList{
+		0: {1, 2, 4},
+		1: {2},
+		2: {3},
+		3: {1},
+		5: {4},
+	}
+	g.TraverseLevels(0, func(level int, frontier []graph.NI) bool {
+		fmt.Println(level, frontier)
+		return true
+	})
+	// Output:
+	// 0 [0]
+	// 1 [1 2 4]
+	// 2 [3]
+}
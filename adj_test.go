@@ -5,7 +5,10 @@ package graph_test
 
 import (
 	"fmt"
+	"math/rand"
 	"os"
+	"runtime/debug"
+	"testing"
 	"text/template"
 
 	"github.com/soniakeys/graph"
@@ -121,6 +124,123 @@ func ExampleLabeledAdjacencyList_DistanceMatrix() {
 	// [    2  +Inf  +Inf     0]
 }
 
+func ExampleAdjacencyList_SamplePairsByDistance() {
+	// 0--1--2--3
+	g := graph.AdjacencyList{
+		0: {1},
+		1: {0, 2},
+		2: {1, 3},
+		3: {2},
+	}
+	r := rand.New(rand.NewSource(1))
+	pairs, dists, short := g.SamplePairsByDistance([]int{3, 4}, 2, r, 100)
+	fmt.Println(len(pairs), dists)
+	fmt.Println("short:", short)
+	// Output:
+	// 2 [3 3]
+	// short: [0 2]
+}
+
+// bfsDist returns path lengths from s, -1 for unreachable nodes.
+func bfsDist(g graph.AdjacencyList, s graph.NI) []int {
+	d := make([]int, len(g))
+	for i := range d {
+		d[i] = -1
+	}
+	d[s] = 0
+	q := []graph.NI{s}
+	for len(q) > 0 {
+		fr := q[0]
+		q = q[1:]
+		for _, to := range g[fr] {
+			if d[to] < 0 {
+				d[to] = d[fr] + 1
+				q = append(q, to)
+			}
+		}
+	}
+	return d
+}
+
+func TestSamplePairsByDistance(t *testing.T) {
+	g := graph.GnmUndirected(300, 450, rand.New(rand.NewSource(5)))
+	buckets := []int{1, 2, 3, 5}
+	pairs, dists, short := g.SamplePairsByDistance(buckets, 40, rand.New(rand.NewSource(9)), 2000)
+	got := map[int]int{}
+	seen := map[[2]graph.NI]bool{}
+	for i, p := range pairs {
+		if seen[p] {
+			t.Fatal("duplicate pair", p)
+		}
+		seen[p] = true
+		if d := bfsDist(g.AdjacencyList, p[0])[p[1]]; d != dists[i] {
+			t.Fatal("pair", p, "distance", d, "reported", dists[i])
+		}
+		got[dists[i]]++
+	}
+	for i, b := range buckets {
+		if got[b]+short[i] != 40 {
+			t.Fatal("bucket", b, "count", got[b], "short", short[i])
+		}
+	}
+	// star with 5 leaves: 20 leaf pairs at distance 2, none at distance 3.
+	var star graph.Undirected
+	for n := graph.NI(1); n <= 5; n++ {
+		star.AddEdge(0, n)
+	}
+	_, _, short = star.SamplePairsByDistance([]int{2, 3}, 30, rand.New(rand.NewSource(1)), 1000)
+	if short[0] != 10 || short[1] != 30 {
+		t.Fatal("star shortfall", short)
+	}
+}
+
+func TestArcIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(46))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(30)
+		g := make(graph.LabeledAdjacencyList, n)
+		for m := r.Intn(4 * n); m > 0; m-- {
+			fr := r.Intn(n)
+			g[fr] = append(g[fr], graph.Half{graph.NI(r.Intn(n)), 0})
+		}
+		x := g.BuildArcIndex()
+		for fr := range g {
+			for to := 0; to < n; to++ {
+				has, _ := g.HasArc(graph.NI(fr), graph.NI(to))
+				c := len(g.ParallelArcs(graph.NI(fr), graph.NI(to)))
+				if x.HasArc(graph.NI(fr), graph.NI(to)) != has ||
+					x.ArcCount(graph.NI(fr), graph.NI(to)) != c {
+					t.Fatal(i, fr, to, has, c)
+				}
+			}
+		}
+	}
+}
+
+func ExampleLabeledAdjacencyList_FloydWarshall() {
+	//   (1)   (-1)   (4)
+	//  0---->1---->3---->2
+	//        ^     |     |
+	//        |(2)  |(3)  |(-2)
+	//        |     v     |
+	//        ------4<-----
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}},
+		1: {{To: 3, Label: -1}},
+		2: {{To: 4, Label: -2}},
+		3: {{To: 2, Label: 4}, {To: 4, Label: 3}},
+		4: {{To: 1, Label: 2}},
+	}
+	d, m := g.FloydWarshall(func(l graph.LI) float64 { return float64(l) })
+	fmt.Println(d.HasNegativeCycle())
+	fmt.Println(d[0][4], m.Path(0, 4, nil))
+	fmt.Println(d[4][0], m.Path(4, 0, nil))
+	// Output:
+	// false -1
+	// 2 [0 1 3 2 4]
+	// +Inf []
+}
+
 func ExampleLabeledAdjacencyList_HasArcLabel() {
 	//    /--\
 	//   2<--/
@@ -419,3 +539,274 @@ func ExampleLabeledAdjacencyList_WeightedOutDegree_undirected() {
 	// 1     7                    7
 	// 2     9                    9
 }
+
+// Depth first traversals must not recurse on node depth.  A path graph of a
+// million nodes is traversed with the goroutine stack limited to well below
+// what a recursive traversal would need.
+func TestDeepPath(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	defer debug.SetMaxStack(debug.SetMaxStack(8 << 20))
+	const n = 1e6
+	d := graph.Directed{make(graph.AdjacencyList, n)}
+	u := graph.Undirected{make(graph.AdjacencyList, n)}
+	for i := graph.NI(1); i < n; i++ {
+		d.AdjacencyList[i-1] = []graph.NI{i}
+		u.AddEdge(i-1, i)
+	}
+	nv := 0
+	d.DepthFirst(0, func(graph.NI) { nv++ })
+	if nv != n {
+		t.Fatal("DepthFirst visited", nv)
+	}
+	if o, c := d.Topological(); c != nil || len(o) != n || o[0] != 0 {
+		t.Fatal("Topological", c)
+	}
+	if c, _, _ := d.Cyclic(); c {
+		t.Fatal("Cyclic")
+	}
+	if tr, all := d.IsTree(0); !tr || !all {
+		t.Fatal("directed IsTree", tr, all)
+	}
+	if _, nc := u.ConnectedComponentInts(); nc != 1 {
+		t.Fatal("ConnectedComponentInts", nc)
+	}
+	if l, _ := u.ConnectedComponentLists()(); len(l) != n || l[n-1] != n-1 {
+		t.Fatal("ConnectedComponentLists", len(l))
+	}
+	if o, _, _ := u.ConnectedComponentBits()(); o != n {
+		t.Fatal("ConnectedComponentBits", o)
+	}
+	if r, _, _ := u.ConnectedComponentReps(); len(r) != 1 {
+		t.Fatal("ConnectedComponentReps", r)
+	}
+	if !u.IsConnected() {
+		t.Fatal("IsConnected")
+	}
+	if b, _, ok := u.Bipartite(); !ok || b.N0 != n/2 {
+		t.Fatal("Bipartite", ok)
+	}
+	if tr, all := u.IsTree(0); !tr || !all {
+		t.Fatal("undirected IsTree", tr, all)
+	}
+	// close the path into an odd cycle
+	u.AddEdge(0, n-2)
+	if _, oc, ok := u.Bipartite(); ok || len(oc) != n-1 {
+		t.Fatal("Bipartite odd cycle", len(oc))
+	}
+	d.AdjacencyList[n-1] = []graph.NI{0}
+	if _, c := d.Topological(); len(c) != n {
+		t.Fatal("Topological cycle", len(c))
+	}
+}
+
+func ExampleAdjacencyList_SimplePaths() {
+	//   0-->1-->3
+	//   |\  ^   ^
+	//   | \ |   |
+	//   v  \|   |
+	//   2-->4---'
+	g := graph.AdjacencyList{
+		0: {1, 2, 4, 4},
+		1: {3, 1},
+		2: {4},
+		4: {1, 3},
+	}
+	g.SimplePaths(0, 3, 0, func(p []graph.NI) bool {
+		fmt.Println(p)
+		return true
+	})
+	fmt.Println("at most 2 arcs:")
+	g.SimplePaths(0, 3, 2, func(p []graph.NI) bool {
+		fmt.Println(p)
+		return true
+	})
+	// Output:
+	// [0 1 3]
+	// [0 2 4 1 3]
+	// [0 2 4 3]
+	// [0 4 1 3]
+	// [0 4 3]
+	// at most 2 arcs:
+	// [0 1 3]
+	// [0 4 3]
+}
+
+func ExampleLabeledAdjacencyList_SimplePaths() {
+	//      a     c
+	//   0 ==> 1 --> 2
+	//      b
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 'a'}, {To: 1, Label: 'b'}},
+		1: {{To: 2, Label: 'c'}, {To: 1, Label: 'd'}},
+		2: nil,
+	}
+	g.SimplePaths(0, 2, 0, func(p []graph.Half) bool {
+		fmt.Print(0)
+		for _, h := range p {
+			fmt.Printf(" -%c-> %d", h.Label, h.To)
+		}
+		fmt.Println()
+		return true
+	})
+	// Output:
+	// 0 -a-> 1 -c-> 2
+	// 0 -b-> 1 -c-> 2
+}
+
+func TestSimplePathsStop(t *testing.T) {
+	// complete graph on 6 nodes has many paths from 0 to 5
+	g := make(graph.AdjacencyList, 6)
+	for fr := range g {
+		for to := range g {
+			if to != fr {
+				g[fr] = append(g[fr], graph.NI(to))
+			}
+		}
+	}
+	all := 0
+	g.SimplePaths(0, 5, 0, func([]graph.NI) bool { all++; return true })
+	// sum over k of 4!/(4-k)! intermediate sequences
+	if all != 1+4+12+24+24 {
+		t.Fatal("paths", all)
+	}
+	n := 0
+	g.SimplePaths(0, 5, 0, func([]graph.NI) bool { n++; return n < 3 })
+	if n != 3 {
+		t.Fatal("emit called", n, "times after stop")
+	}
+	n = 0
+	g.SimplePaths(2, 2, 0, func(p []graph.NI) bool {
+		if len(p) != 1 || p[0] != 2 {
+			t.Fatal("start == end path", p)
+		}
+		n++
+		return true
+	})
+	if n != 1 {
+		t.Fatal("start == end paths", n)
+	}
+}
+
+func ExampleAdjacencyList_Arcs() {
+	//   0
+	//  / \
+	// 1-->2-->3
+	//     ^\
+	//     \/
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {2, 3},
+		3: {},
+	}
+	g.Arcs(func(fr graph.NI, x int, to graph.NI) bool {
+		fmt.Printf("%d[%d]: %d->%d\n", fr, x, fr, to)
+		return to != 3 // stop after arc to 3
+	})
+	// Output:
+	// 0[0]: 0->1
+	// 0[1]: 0->2
+	// 1[0]: 1->2
+	// 2[0]: 2->2
+	// 2[1]: 2->3
+}
+
+func TestArcs(t *testing.T) {
+	// empty rows, a loop, and parallel arcs
+	g := graph.LabeledAdjacencyList{
+		1: {{2, 'a'}, {2, 'b'}, {1, 'c'}},
+		2: {},
+		3: {{1, 'd'}},
+		4: nil,
+	}
+	var got []graph.LabeledEdge
+	var xs []int
+	if !g.Arcs(func(fr graph.NI, x int, to graph.Half) bool {
+		got = append(got, graph.LabeledEdge{graph.Edge{fr, to.To}, to.Label})
+		xs = append(xs, x)
+		return true
+	}) {
+		t.Fatal("Arcs returned false")
+	}
+	want := []graph.LabeledEdge{
+		{graph.Edge{1, 2}, 'a'},
+		{graph.Edge{1, 2}, 'b'},
+		{graph.Edge{1, 1}, 'c'},
+		{graph.Edge{3, 1}, 'd'},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) ||
+		fmt.Sprint(xs) != "[0 1 2 0]" {
+		t.Fatal(got, xs)
+	}
+	// early termination, at each possible arc
+	for stop := 1; stop <= len(want); stop++ {
+		n := 0
+		if g.Arcs(func(graph.NI, int, graph.Half) bool {
+			n++
+			return n < stop
+		}) {
+			t.Fatal("stop", stop, "Arcs returned true")
+		}
+		if n != stop {
+			t.Fatal("stop", stop, "emit called", n, "times")
+		}
+	}
+	if !(graph.AdjacencyList{}).Arcs(func(graph.NI, int, graph.NI) bool {
+		t.Fatal("emit called on empty graph")
+		return false
+	}) {
+		t.Fatal("Arcs on empty graph returned false")
+	}
+}
+
+func benchmarkGraph() graph.Directed {
+	return graph.GnmDirected(10000, 100000, rand.New(rand.NewSource(3)))
+}
+
+func BenchmarkInDegreeArcs(b *testing.B) {
+	g := benchmarkGraph()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.InDegree()
+	}
+}
+
+// hand-written loop, for comparison with the InDegree method.
+func BenchmarkInDegreeLoop(b *testing.B) {
+	g := benchmarkGraph()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ind := make([]int, g.Order())
+		for _, to := range g.AdjacencyList {
+			for _, to := range to {
+				ind[to]++
+			}
+		}
+	}
+}
+
+func BenchmarkTransposeArcs(b *testing.B) {
+	g := benchmarkGraph()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Transpose()
+	}
+}
+
+// hand-written loop, for comparison with the Transpose method.
+func BenchmarkTransposeLoop(b *testing.B) {
+	g := benchmarkGraph()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ta := make(graph.AdjacencyList, g.Order())
+		ma := 0
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				ta[to] = append(ta[to], graph.NI(fr))
+				ma++
+			}
+		}
+	}
+}
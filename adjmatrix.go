@@ -0,0 +1,157 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// adjmatrix.go -- dense adjacency matrix representation.
+
+import (
+	mb "math/bits"
+
+	"github.com/soniakeys/bits"
+)
+
+// AdjacencyMatrix represents a graph as an adjacency matrix of bits.
+//
+// For a graph of order n, the matrix is a length n slice of length n
+// bits.Bits values, where m[fr].Bit(to) == 1 represents an arc from fr to
+// to.  It is the representation returned by TransitiveClosure.
+//
+// An adjacency matrix takes n*n bits regardless of the number of arcs and
+// so can take less memory than an AdjacencyList for dense graphs.  It
+// cannot represent parallel arcs.
+type AdjacencyMatrix []bits.Bits
+
+// NewAdjacencyMatrix constructs an AdjacencyMatrix of the given order with
+// no arcs.
+func NewAdjacencyMatrix(order int) AdjacencyMatrix {
+	m := make(AdjacencyMatrix, order)
+	for i := range m {
+		m[i] = bits.New(order)
+	}
+	return m
+}
+
+// FromAdjacencyList constructs an AdjacencyMatrix with the arcs of g.
+//
+// Parallel arcs of g are represented as a single arc.
+func FromAdjacencyList(g AdjacencyList) AdjacencyMatrix {
+	m := NewAdjacencyMatrix(len(g))
+	for fr, to := range g {
+		mf := m[fr]
+		for _, to := range to {
+			mf.SetBit(int(to), 1)
+		}
+	}
+	return m
+}
+
+// ToAdjacencyList constructs an AdjacencyList with the arcs of m.
+//
+// To-lists are in increasing order.
+func (m AdjacencyMatrix) ToAdjacencyList() AdjacencyList {
+	g := make(AdjacencyList, len(m))
+	for fr, mf := range m {
+		if c := mf.OnesCount(); c > 0 {
+			to := make([]NI, 0, c)
+			mf.IterateOnes(func(n int) bool {
+				to = append(to, NI(n))
+				return true
+			})
+			g[fr] = to
+		}
+	}
+	return g
+}
+
+// Order returns the number of nodes of m.
+func (m AdjacencyMatrix) Order() int {
+	return len(m)
+}
+
+// ArcSize returns the number of arcs of m.
+func (m AdjacencyMatrix) ArcSize() (a int) {
+	for _, mf := range m {
+		a += mf.OnesCount()
+	}
+	return
+}
+
+// HasArc returns true if m has an arc from node fr to node to.
+func (m AdjacencyMatrix) HasArc(fr, to NI) bool {
+	return m[fr].Bit(int(to)) == 1
+}
+
+// Transpose constructs a new matrix with all arcs of m reversed.
+func (m AdjacencyMatrix) Transpose() AdjacencyMatrix {
+	t := NewAdjacencyMatrix(len(m))
+	for fr, mf := range m {
+		mf.IterateOnes(func(to int) bool {
+			t[to].SetBit(fr, 1)
+			return true
+		})
+	}
+	return t
+}
+
+// TransitiveClosure returns the transitive closure of m.
+//
+// The algorithm is Warshall's, computed by rows:  For each node k, each row
+// with an arc to k is ORed with row k.  With rows of n bits, time is
+// O(n^3/64) in the worst case and much less for matrices with few arcs
+// into each node.  It is faster than Directed.TransitiveClosure for dense
+// graphs, which builds the matrix from an adjacency list.
+//
+// A new matrix is returned; m is not modified.
+func (m AdjacencyMatrix) TransitiveClosure() AdjacencyMatrix {
+	t := make(AdjacencyMatrix, len(m))
+	for i, mi := range m {
+		ti := bits.New(len(m))
+		copy(ti.Bits, mi.Bits)
+		t[i] = ti
+	}
+	for k, tk := range t {
+		w, b := k>>6, uint64(1)<<uint(k&63)
+		for _, ti := range t {
+			if ti.Bits[w]&b != 0 {
+				for x, y := range tk.Bits {
+					ti.Bits[x] |= y
+				}
+			}
+		}
+	}
+	return t
+}
+
+// Triangles counts triangles of m, considered as an undirected graph.
+//
+// The matrix must be symmetric, with an arc in each direction for each
+// edge, as constructed for example by FromAdjacencyList from an Undirected
+// graph.  Loops are ignored.
+//
+// Returned is the total number of triangles and the number of triangles
+// each node is part of.  For each node i, the common neighbors of i and
+// each neighbor j are counted by ANDing rows, so time is O(m*n/64) for m
+// arcs.
+func (m AdjacencyMatrix) Triangles() (total int, perNode []int) {
+	perNode = make([]int, len(m))
+	for i, mi := range m {
+		c := 0
+		mi.IterateOnes(func(j int) bool {
+			if j == i {
+				return true
+			}
+			mj := m[j]
+			for x, y := range mi.Bits {
+				c += mb.OnesCount64(y & mj.Bits[x])
+			}
+			// loops at i or j are not triangles
+			c -= mi.Bit(i)*mj.Bit(i) + mi.Bit(j)*mj.Bit(j)
+			return true
+		})
+		// each triangle at i is counted from both neighbors
+		perNode[i] = c / 2
+		total += perNode[i]
+	}
+	return total / 3, perNode
+}
@@ -0,0 +1,148 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleAdjacencyMatrix_TransitiveClosure() {
+	// 0 -> 1 -> 2 -> 1
+	m := graph.FromAdjacencyList(graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {1},
+	})
+	for _, row := range m.TransitiveClosure() {
+		fmt.Println(row.Slice())
+	}
+	// Output:
+	// [1 2]
+	// [1 2]
+	// [1 2]
+}
+
+func ExampleAdjacencyMatrix_Triangles() {
+	//   0
+	//  / \
+	// 1---2---3
+	//      \ /
+	//       4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(3, 4)
+	fmt.Println(graph.FromAdjacencyList(g.AdjacencyList).Triangles())
+	// Output:
+	// 2 [1 1 2 1 1]
+}
+
+func TestAdjacencyMatrix(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 20; i++ {
+		g, _ := graph.GnpDirected(70, .05, r)
+		m := graph.FromAdjacencyList(g.AdjacencyList)
+		if m.Order() != g.Order() || m.ArcSize() != g.ArcSize() {
+			t.Fatal("order, arc size", m.Order(), m.ArcSize())
+		}
+		g.AdjacencyList.SortArcLists()
+		if !m.ToAdjacencyList().Equal(g.AdjacencyList) {
+			t.Fatal("round trip")
+		}
+		for n := 0; n < 50; n++ {
+			fr := graph.NI(r.Intn(g.Order()))
+			to := graph.NI(r.Intn(g.Order()))
+			if has, _ := g.HasArc(fr, to); m.HasArc(fr, to) != has {
+				t.Fatal("HasArc", fr, to)
+			}
+		}
+		tr, _ := g.Transpose()
+		tr.SortArcLists()
+		if !m.Transpose().ToAdjacencyList().Equal(tr.AdjacencyList) {
+			t.Fatal("Transpose")
+		}
+		tc := g.TransitiveClosure()
+		for n, row := range m.TransitiveClosure() {
+			if !row.Equal(tc[n]) {
+				t.Fatal("TransitiveClosure row", n)
+			}
+		}
+	}
+}
+
+func TestAdjacencyMatrixTriangles(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 20; i++ {
+		g, _ := graph.GnpUndirected(40, .2, r)
+		// add some loops
+		for n := 0; n < 3; n++ {
+			l := graph.NI(r.Intn(g.Order()))
+			g.AddEdge(l, l)
+		}
+		m := graph.FromAdjacencyList(g.AdjacencyList)
+		total, perNode := m.Triangles()
+		// brute force
+		bt := 0
+		bp := make([]int, g.Order())
+		for a := range m {
+			for b := a + 1; b < len(m); b++ {
+				for c := b + 1; c < len(m); c++ {
+					if m.HasArc(graph.NI(a), graph.NI(b)) &&
+						m.HasArc(graph.NI(b), graph.NI(c)) &&
+						m.HasArc(graph.NI(a), graph.NI(c)) {
+						bt++
+						bp[a]++
+						bp[b]++
+						bp[c]++
+					}
+				}
+			}
+		}
+		if total != bt {
+			t.Fatal("total", total, "brute force", bt)
+		}
+		for n, c := range perNode {
+			if c != bp[n] {
+				t.Fatal("node", n, c, "brute force", bp[n])
+			}
+		}
+	}
+}
+
+func denseBenchmarkGraph() graph.Directed {
+	g, _ := graph.GnpDirected(1000, .2, rand.New(rand.NewSource(3)))
+	return g
+}
+
+func BenchmarkTransitiveClosureList(b *testing.B) {
+	g := denseBenchmarkGraph()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.TransitiveClosure()
+	}
+}
+
+func BenchmarkTransitiveClosureMatrix(b *testing.B) {
+	m := graph.FromAdjacencyList(denseBenchmarkGraph().AdjacencyList)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.TransitiveClosure()
+	}
+}
+
+func BenchmarkTrianglesMatrix(b *testing.B) {
+	g, _ := graph.GnpUndirected(1000, .2, rand.New(rand.NewSource(3)))
+	m := graph.FromAdjacencyList(g.AdjacencyList)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Triangles()
+	}
+}
@@ -11,8 +11,27 @@ import (
 // BreadthFirst2 traverses a graph breadth first using a direction
 // optimizing algorithm pioneered by Scott Beamer.
 //
-// The algorithm is supposed to be faster than the conventional breadth first
-// algorithm but I haven't seen it yet.
+// Each level is computed either top down, following arcs out of frontier
+// nodes, or bottom up, searching arcs into unreached nodes for a frontier
+// node.  Bottom up steps are cheaper when the frontier is large.  As in
+// Beamer's paper, the traversal switches to bottom up when the number of
+// arcs out of the frontier exceeds 1/14 of the number of arcs out of
+// unreached nodes, and back to top down when the frontier holds fewer than
+// 1/24 of the nodes of the graph.  The algorithm is faster than the
+// conventional breadth first algorithm for large low diameter graphs such
+// as Kronecker graphs where most nodes are reached in a few large levels.
+//
+// Argument tr must be the transpose of g, or g itself if g is undirected.
+// If tr is nil, it is computed.  Argument ma is the number of arcs of g.
+// If it is not positive, it is computed.  Argument f, if not nil, is
+// populated with the breadth first spanning tree.  Path lengths are the
+// same as for a conventional breadth first traversal although From nodes
+// may differ.
+//
+// Visitor v is called for each node reached.  BreadthFirst2 returns the
+// number of nodes successfully visited, that is, nodes for which v returned
+// true.  If the traversal is terminated by a false return from v, that node
+// is not counted.
 func BreadthFirst2(g, tr graph.AdjacencyList, ma int, start graph.NI, f *graph.FromList, v func(graph.NI) bool) int {
 	if tr == nil {
 		var d graph.Directed
@@ -29,106 +48,94 @@ func BreadthFirst2(g, tr graph.AdjacencyList, ma int, start graph.NI, f *graph.F
 	if ma <= 0 {
 		ma = g.ArcSize()
 	}
+	const (
+		alpha = 14 // switch to bottom up when mf > mu/alpha
+		beta  = 24 // switch to top down when nf < len(g)/beta
+	)
 	rp := f.Paths
 	level := 1
 	rp[start] = graph.PathEnd{Len: level, From: -1}
 	if !v(start) {
 		f.MaxLen = level
-		return -1
+		return 0
 	}
 	nReached := 1 // accumulated for a return value
-	// the frontier consists of nodes all at the same level
+	// the frontier consists of nodes all at the same level.  it is
+	// represented as a list in top down steps and as bits in bottom up steps.
 	frontier := []graph.NI{start}
-	mf := len(g[start])     // number of arcs leading out from frontier
-	ctb := ma / 10          // threshold change from top-down to bottom-up
-	k14 := 14 * ma / len(g) // 14 * mean degree
-	cbt := len(g) / k14     // threshold change from bottom-up to top-down
-	// well shoot.  part of the speed problem is the bitmap implementation.
-	// big.Ints are slow.  this custom bits package is faster.  faster still
-	// is just a slice of bools. :(
+	var next []graph.NI
 	fBits := bits.New(len(g))
 	nextb := bits.New(len(g))
+	mu := ma - len(g[start]) // number of arcs leading out from unreached nodes
+	topDown := true
 	for {
-		// top down step
 		level++
-		var next []graph.NI
-		for _, n := range frontier {
-			for _, nb := range g[n] {
-				if rp[nb].Len == 0 {
-					rp[nb] = graph.PathEnd{From: n, Len: level}
-					if !v(nb) {
-						f.MaxLen = level
-						return -1
+		if topDown {
+			next = next[:0]
+			mf := 0 // number of arcs leading out from next frontier
+			for _, n := range frontier {
+				for _, nb := range g[n] {
+					if rp[nb].Len == 0 {
+						rp[nb] = graph.PathEnd{From: n, Len: level}
+						if !v(nb) {
+							f.MaxLen = level
+							return nReached
+						}
+						next = append(next, nb)
+						nReached++
+						mf += len(g[nb])
 					}
-					next = append(next, nb)
-					nReached++
 				}
 			}
-		}
-		if len(next) == 0 {
-			break
-		}
-		frontier = next
-		if mf > ctb {
-			// switch to bottom up!
-		} else {
-			// stick with top down
+			if len(next) == 0 {
+				break
+			}
+			frontier, next = next, frontier
+			mu -= mf
+			if mf > mu/alpha {
+				// switch to bottom up
+				for _, n := range frontier {
+					fBits.SetBit(int(n), 1)
+				}
+				topDown = false
+			}
 			continue
 		}
-		// convert frontier representation
-		nf := 0 // number of vertices on the frontier
-		for _, n := range frontier {
-			fBits.SetBit(int(n), 1)
-			nf++
-		}
-	bottomUpLoop:
-		level++
-		nNext := 0
-		for n := range tr {
+		// bottom up step
+		nf := 0 // number of nodes on next frontier
+		for n, to := range tr {
 			if rp[n].Len == 0 {
-				for _, nb := range tr[n] {
-					if fBits.Bit(int(nb)) == 1 {
-						rp[n] = graph.PathEnd{From: nb, Len: level}
-						if !v(nb) {
+				for _, fr := range to {
+					if fBits.Bit(int(fr)) == 1 {
+						rp[n] = graph.PathEnd{From: fr, Len: level}
+						if !v(graph.NI(n)) {
 							f.MaxLen = level
-							return -1
+							return nReached
 						}
 						nextb.SetBit(n, 1)
 						nReached++
-						nNext++
+						nf++
+						mu -= len(g[n])
 						break
 					}
 				}
 			}
 		}
-		if nNext == 0 {
+		if nf == 0 {
 			break
 		}
 		fBits, nextb = nextb, fBits
 		nextb.ClearAll()
-		nf = nNext
-		if nf < cbt {
-			// switch back to top down!
-		} else {
-			// stick with bottom up
-			goto bottomUpLoop
-		}
-		// convert frontier representation
-		mf = 0
-		frontier = frontier[:0]
-		// TODO use exported bits representation here.  quick search for a
-		// non zero word, then convert all bits in the word, then clear the
-		// word.
-		for n := range g {
-			if fBits.Bit(n) == 1 {
+		if nf < len(g)/beta {
+			// switch to top down
+			frontier = frontier[:0]
+			fBits.IterateOnes(func(n int) bool {
 				frontier = append(frontier, graph.NI(n))
-				mf += len(g[n])
-				// fBits.SetBit(n, 0) // alternative to clear all.
-			}
+				return true
+			})
+			fBits.ClearAll()
+			topDown = true
 		}
-		// or clear individual bits, but in bottom up step we expect a
-		// relatively dense frontier, so ClearAll seems better.
-		fBits.ClearAll()
 	}
 	f.MaxLen = level - 1
 	return nReached
@@ -5,6 +5,8 @@ package alt_test
 
 import (
 	"fmt"
+	"math/rand"
+	"testing"
 
 	"github.com/soniakeys/graph"
 	"github.com/soniakeys/graph/alt"
@@ -42,3 +44,117 @@ func ExampleBreadthFirst2_allPaths() {
 	// 5 [1 4 3 5]
 	// 6 [1 4 6]
 }
+
+// TestBreadthFirst2Terminate checks the return value of BreadthFirst2 for
+// traversals terminated at each node.  The start node has enough arcs that
+// the traversal switches to bottom up.
+func TestBreadthFirst2Terminate(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	g := graph.GnmDirected(100, 300, r).AdjacencyList
+	for i := 0; i < 40; i++ {
+		g[0] = append(g[0], graph.NI(1+r.Intn(99)))
+	}
+	all := alt.BreadthFirst2(g, nil, 0, 0, nil, func(graph.NI) bool {
+		return true
+	})
+	reached := 0
+	for _, d := range g.Distances(0) {
+		if d >= 0 {
+			reached++
+		}
+	}
+	if all != reached {
+		t.Fatal("returned", all, "reachable", reached)
+	}
+	for stop := 1; stop <= all; stop++ {
+		calls := 0
+		n := alt.BreadthFirst2(g, nil, 0, 0, nil, func(graph.NI) bool {
+			calls++
+			return calls < stop
+		})
+		if calls != stop || n != stop-1 {
+			t.Fatal("stop", stop, "calls", calls, "returned", n)
+		}
+	}
+}
+
+// TestBreadthFirst2Levels checks that path lengths found by BreadthFirst2
+// are those of a conventional breadth first traversal and that nodes are
+// visited once each.
+func TestBreadthFirst2Levels(t *testing.T) {
+	r := rand.New(rand.NewSource(29))
+	for i := 0; i < 10; i++ {
+		g, ma := graph.KroneckerDirected(10, 8, r)
+		start := graph.NI(r.Intn(g.Order()))
+		var f graph.FromList
+		vis := make([]int, g.Order())
+		alt.BreadthFirst2(g.AdjacencyList, nil, ma, start, &f,
+			func(n graph.NI) bool {
+				vis[n]++
+				return true
+			})
+		maxLen := 0
+		for n, d := range g.AdjacencyList.Distances(start) {
+			if f.Paths[n].Len != d+1 {
+				t.Fatal("node", n, "Len", f.Paths[n].Len, "distance", d)
+			}
+			want := 1
+			if d < 0 {
+				want = 0
+			}
+			if vis[n] != want {
+				t.Fatal("node", n, "visited", vis[n], "times")
+			}
+			if d+1 > maxLen {
+				maxLen = d + 1
+			}
+		}
+		if f.MaxLen != maxLen {
+			t.Fatal("MaxLen", f.MaxLen, "want", maxLen)
+		}
+	}
+}
+
+// kronScale is the scale of the Kronecker graph for breadth first
+// benchmarks.  Scale 22, as in the Graph 500 benchmark, takes minutes to
+// generate and several GB of memory.
+const kronScale = 18
+
+var kronBF struct {
+	g, tr graph.AdjacencyList
+	ma    int
+	start graph.NI
+}
+
+// kronBFInit generates the benchmark graph on first use, an undirected
+// Kronecker graph with edge factor 16, and starts traversals at a node of
+// maximum degree.
+func kronBFInit(b *testing.B) {
+	if kronBF.g == nil {
+		g, _ := graph.KroneckerUndirected(kronScale, 16, rand.New(rand.NewSource(31)))
+		kronBF.g = g.AdjacencyList
+		kronBF.tr = g.AdjacencyList
+		kronBF.ma = g.AdjacencyList.ArcSize()
+		for n, to := range kronBF.g {
+			if len(to) > len(kronBF.g[kronBF.start]) {
+				kronBF.start = graph.NI(n)
+			}
+		}
+	}
+	b.ResetTimer()
+}
+
+func BenchmarkBreadthFirstKronecker(b *testing.B) {
+	kronBFInit(b)
+	for i := 0; i < b.N; i++ {
+		kronBF.g.BreadthFirst(kronBF.start, func(graph.NI) {})
+	}
+}
+
+func BenchmarkBreadthFirst2Kronecker(b *testing.B) {
+	kronBFInit(b)
+	for i := 0; i < b.N; i++ {
+		alt.BreadthFirst2(kronBF.g, kronBF.tr, kronBF.ma, kronBF.start, nil,
+			func(graph.NI) bool { return true })
+	}
+}
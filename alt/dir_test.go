@@ -5,6 +5,9 @@ package alt_test
 
 import (
 	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
 
 	"github.com/soniakeys/graph"
 	"github.com/soniakeys/graph/alt"
@@ -182,3 +185,29 @@ func ExampleSCCTarjan() {
 	// [4 5]
 	// [0]
 }
+
+// SCCs sorted with graph.SortComponents must be identical across the
+// implementations here and in the graph package.
+func TestSCCSorted(t *testing.T) {
+	r := rand.New(rand.NewSource(37))
+	collect := func(scc func(func([]graph.NI) bool)) (cs [][]graph.NI) {
+		graph.SortComponents(scc, func(c []graph.NI) bool {
+			cs = append(cs, c)
+			return true
+		})
+		return
+	}
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(40)
+		g := graph.GnmDirected(n, r.Intn(2*n), r)
+		want := collect(g.StronglyConnectedComponents)
+		for j, scc := range []func(graph.Directed, func([]graph.NI) bool){
+			alt.SCCKosaraju, alt.SCCPathBased, alt.SCCTarjan,
+		} {
+			got := collect(func(emit func([]graph.NI) bool) { scc(g, emit) })
+			if !reflect.DeepEqual(got, want) {
+				t.Fatal(i, "variant", j, got, "want", want)
+			}
+		}
+	}
+}
@@ -360,3 +360,30 @@ func ExampleBreadthFirst_traverseRandom() {
 	// visit 4 level 3
 	// visit 7 level 3
 }
+
+func ExampleDepthFirst_from() {
+	//   0
+	//  / \
+	// 1-->2
+	// ^   |
+	// |   v
+	// \---3
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {3},
+		3: {1},
+	}
+	var f graph.FromList
+	alt.DepthFirst(g, 0, alt.From(&f))
+	fmt.Println("Max path length:", f.MaxLen)
+	for n := range g {
+		fmt.Println(n, f.PathTo(graph.NI(n), nil))
+	}
+	// Output:
+	// Max path length: 4
+	// 0 [0]
+	// 1 [0 1]
+	// 2 [0 1 2]
+	// 3 [0 1 2 3]
+}
@@ -5,6 +5,8 @@ package alt
 
 import (
 	"errors"
+	"math"
+	"math/rand"
 
 	"github.com/soniakeys/bits"
 	"github.com/soniakeys/graph"
@@ -136,3 +138,219 @@ func newUlerian(g graph.Undirected) *ulerian {
 	u.uv.SetAll()
 	return u
 }
+
+// KargerSteinMinCut finds a minimum weight edge cut of an undirected graph
+// by the randomized recursive contraction algorithm of Karger and Stein.
+//
+// Edge weights are given by w and must be non-negative.  Loops are ignored
+// and parallel edges are merged, summing weights.
+//
+// Each trial finds a minimum cut with probability Ω(1/log n).  The minimum
+// over the given number of trials is returned as cutWeight along with the
+// node set of one side of the cut.  If g is disconnected, the cut has weight
+// 0 and cutSet is a connected component.  If g has fewer than two nodes,
+// cutWeight is +Inf.
+//
+// If Rand r is nil, the rand package default shared source is used.  Results
+// are deterministic for a seeded r.
+//
+// The deterministic Stoer-Wagner algorithm of graph.LabeledUndirected
+// MinimumCut is generally a better choice.
+// This independent implementation is useful for cross-checking.
+func KargerSteinMinCut(g graph.LabeledUndirected, w graph.WeightFunc, trials int, r *rand.Rand) (cutWeight float64, cutSet bits.Bits) {
+	n := g.Order()
+	cutWeight = math.Inf(1)
+	cutSet = bits.New(n)
+	if n < 2 {
+		return
+	}
+	rf := rand.Float64
+	if r != nil {
+		rf = r.Float64
+	}
+	var e []kEdge
+	for fr, to := range g.LabeledAdjacencyList {
+		for _, to := range to {
+			if graph.NI(fr) < to.To {
+				e = append(e, kEdge{fr, int(to.To), w(to.Label)})
+			}
+		}
+	}
+	e = mergeKEdges(e, nil)
+	for i := 0; i < trials; i++ {
+		cw, side := ksRec(n, e, rf)
+		if cw < cutWeight {
+			cutWeight = cw
+			cutSet.ClearAll()
+			for n, s := range side {
+				if s {
+					cutSet.SetBit(n, 1)
+				}
+			}
+		}
+	}
+	return
+}
+
+// kEdge is a weighted edge of a contracted multigraph.
+type kEdge struct {
+	u, v int
+	w    float64
+}
+
+// mergeKEdges maps edge ends through m, if m is non-nil, and returns an edge
+// list with loops removed and parallel edges merged.
+func mergeKEdges(e []kEdge, m []int) (r []kEdge) {
+	x := map[[2]int]int{} // index of edge in r
+	for _, e := range e {
+		if m != nil {
+			e.u, e.v = m[e.u], m[e.v]
+		}
+		if e.u == e.v {
+			continue
+		}
+		if e.u > e.v {
+			e.u, e.v = e.v, e.u
+		}
+		if i, ok := x[[2]int{e.u, e.v}]; ok {
+			r[i].w += e.w
+		} else {
+			x[[2]int{e.u, e.v}] = len(r)
+			r = append(r, e)
+		}
+	}
+	return
+}
+
+// ksRec is the recursive step of Karger-Stein.  It returns a cut weight
+// and side of the cut indexed by node of the n node graph with edges e.
+func ksRec(n int, e []kEdge, rf func() float64) (float64, []bool) {
+	// disconnected graphs have a zero weight cut
+	ds := newDS(n)
+	k := n
+	for _, e := range e {
+		if ds.union(e.u, e.v) {
+			k--
+		}
+	}
+	if k > 1 {
+		side := make([]bool, n)
+		r0 := ds.find(0)
+		for i := range side {
+			side[i] = ds.find(i) == r0
+		}
+		return 0, side
+	}
+	if n <= 6 {
+		return bruteCut(n, e)
+	}
+	t := int(math.Ceil(1 + float64(n)/math.Sqrt2))
+	best := math.Inf(1)
+	var side []bool
+	for i := 0; i < 2; i++ {
+		m, k := contract(n, e, t, rf)
+		cw, s := ksRec(k, mergeKEdges(e, m), rf)
+		if cw < best {
+			best = cw
+			side = make([]bool, n)
+			for i, mi := range m {
+				side[i] = s[mi]
+			}
+		}
+	}
+	return best, side
+}
+
+// contract contracts randomly chosen edges, with probability proportional
+// to weight, until t nodes remain.  The graph must be connected.  It returns
+// a mapping from nodes to contracted nodes numbered 0 to k-1.
+func contract(n int, e []kEdge, t int, rf func() float64) (m []int, k int) {
+	ds := newDS(n)
+	for k = n; k > t; k-- {
+		tw := 0.
+		for _, e := range e {
+			if ds.find(e.u) != ds.find(e.v) {
+				tw += e.w
+			}
+		}
+		x := rf() * tw
+		var c kEdge
+		for _, e := range e {
+			if ds.find(e.u) != ds.find(e.v) {
+				c = e
+				if x -= e.w; x < 0 {
+					break
+				}
+			}
+		}
+		ds.union(c.u, c.v)
+	}
+	m = make([]int, n)
+	num := map[int]int{}
+	for i := range m {
+		r := ds.find(i)
+		x, ok := num[r]
+		if !ok {
+			x = len(num)
+			num[r] = x
+		}
+		m[i] = x
+	}
+	return m, len(num)
+}
+
+// bruteCut finds a minimum cut by trying all bipartitions.
+func bruteCut(n int, e []kEdge) (float64, []bool) {
+	best := math.Inf(1)
+	var bm uint
+	for m := uint(1); m < 1<<uint(n-1); m++ {
+		cw := 0.
+		for _, e := range e {
+			if (m>>uint(e.u))&1 != (m>>uint(e.v))&1 {
+				cw += e.w
+			}
+		}
+		if cw < best {
+			best, bm = cw, m
+		}
+	}
+	side := make([]bool, n)
+	for i := range side {
+		side[i] = (bm>>uint(i))&1 == 1
+	}
+	return best, side
+}
+
+// ds is a simple disjoint set for contraction.
+type ds []int
+
+func newDS(n int) ds {
+	d := make(ds, n)
+	for i := range d {
+		d[i] = -1
+	}
+	return d
+}
+
+func (d ds) find(n int) int {
+	for d[n] >= 0 {
+		if d[d[n]] >= 0 {
+			d[n] = d[d[n]] // path halving
+		}
+		n = d[n]
+	}
+	return n
+}
+
+func (d ds) union(a, b int) bool {
+	a, b = d.find(a), d.find(b)
+	if a == b {
+		return false
+	}
+	if d[a] > d[b] { // union by size, sizes stored negated
+		a, b = b, a
+	}
+	d[a] += d[b]
+	d[b] = a
+	return true
+}
@@ -4,6 +4,8 @@
 package alt_test
 
 import (
+	"math"
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -37,3 +39,81 @@ func TestUndirectedEulerianCycle(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestKargerSteinMinCut(t *testing.T) {
+	// two 8-cliques with unit weights joined by two edges of weight 1
+	var g graph.LabeledUndirected
+	for _, base := range []graph.NI{0, 8} {
+		for i := graph.NI(0); i < 8; i++ {
+			for j := i + 1; j < 8; j++ {
+				g.AddEdge(graph.Edge{base + i, base + j}, 1)
+			}
+		}
+	}
+	g.AddEdge(graph.Edge{0, 8}, 1)
+	g.AddEdge(graph.Edge{3, 12}, 1)
+	w := func(l graph.LI) float64 { return float64(l) }
+	cw, cut := alt.KargerSteinMinCut(g, w, 10, rand.New(rand.NewSource(1)))
+	if cw != 2 {
+		t.Fatal("cut weight", cw)
+	}
+	if s := cut.Slice(); len(s) != 8 || s[0] != 0 && s[0] != 8 {
+		t.Fatal("cut set", s)
+	}
+	// determinism
+	cw2, cut2 := alt.KargerSteinMinCut(g, w, 10, rand.New(rand.NewSource(1)))
+	if cw2 != cw || !cut2.Equal(cut) {
+		t.Fatal("results differ with same seed")
+	}
+	// disconnected
+	g.AddEdge(graph.Edge{16, 17}, 5)
+	if cw, _ := alt.KargerSteinMinCut(g, w, 1, nil); cw != 0 {
+		t.Fatal("disconnected graph cut weight", cw)
+	}
+}
+
+// TestKargerSteinBrute cross-checks against all bipartitions of small
+// random weighted graphs.
+func TestKargerSteinBrute(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 30; i++ {
+		n := 2 + r.Intn(9)
+		var g graph.LabeledUndirected
+		var wt []float64
+		for m := n + r.Intn(2*n); m > 0; m-- {
+			e := graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))}
+			g.AddEdge(e, graph.LI(len(wt)))
+			wt = append(wt, float64(1+r.Intn(5)))
+		}
+		w := func(l graph.LI) float64 { return wt[l] }
+		n = g.Order()
+		best := math.Inf(1)
+		for m := 1; m < 1<<uint(n-1); m++ {
+			cw := 0.
+			for fr, to := range g.LabeledAdjacencyList {
+				for _, h := range to {
+					if graph.NI(fr) < h.To && (m>>uint(fr))&1 != (m>>uint(h.To))&1 {
+						cw += w(h.Label)
+					}
+				}
+			}
+			best = math.Min(best, cw)
+		}
+		cw, cut := alt.KargerSteinMinCut(g, w, 20, r)
+		if cw != best {
+			t.Fatal("KargerStein", cw, "brute force", best)
+		}
+		// recompute weight of returned cut
+		cc := 0.
+		for fr, to := range g.LabeledAdjacencyList {
+			for _, h := range to {
+				if graph.NI(fr) < h.To && cut.Bit(fr) != cut.Bit(int(h.To)) {
+					cc += w(h.Label)
+				}
+			}
+		}
+		if cc != cw {
+			t.Fatal("cut set weight", cc, "reported", cw)
+		}
+	}
+}
@@ -11,15 +11,13 @@ import (
 
 func DFSmall() (string, string) {
 	b := bits.New(chungLuSmall.Order())
-	alt.DepthFirst(chungLuSmall.AdjacencyList,
-		chungLuSmallCCRep, alt.Visited(&b))
+	chungLuSmall.AdjacencyList.DepthFirstTraverse(chungLuSmallCCRep, graph.Visited(&b))
 	return "DepthFirst", chungLuSmallCCTag
 }
 
 func DFLarge() (string, string) {
 	b := bits.New(chungLuLarge.Order())
-	alt.DepthFirst(chungLuLarge.AdjacencyList,
-		chungLuLargeCCRep, alt.Visited(&b))
+	chungLuLarge.AdjacencyList.DepthFirstTraverse(chungLuLargeCCRep, graph.Visited(&b))
 	return "DepthFirst", chungLuLargeCCTag
 }
 
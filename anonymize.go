@@ -0,0 +1,179 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// anonymize.go -- k-degree anonymization.
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// kAnonTries is the number of attempts KDegreeAnonymize makes to realize
+// an anonymized degree sequence.
+const kAnonTries = 100
+
+// KDegreeAnonymize adds edges to g so that the result is k-degree
+// anonymous, that is, so that every degree value present is shared by at
+// least k nodes.
+//
+// The algorithm is the two phase approach of Liu and Terzi.  In the first
+// phase, dynamic programming over the degree sequence sorted in decreasing
+// order finds target degrees, not less than the original degrees, that are
+// k-anonymous and that minimize the total degree increase.  In the second
+// phase, the additional degree demand is realized by adding edges, greedily
+// connecting the node of greatest remaining demand to other nodes of greatest
+// remaining demand.  Added edges are never loops, never parallel to an
+// existing edge, and never duplicated.  When the demand cannot be realized,
+// the degree of a random node is incremented and both phases are repeated,
+// up to a bounded number of attempts.
+//
+// Ties among equal degrees are broken using Rand r, so results are
+// deterministic for a seeded r.  If r is nil, the rand package default
+// shared source is used.
+//
+// Returned is the anonymized graph a, a copy of g with added edges, and the
+// list of edges added.  An error is returned if k is greater than the graph
+// order or if anonymization could not be realized.
+func (g Undirected) KDegreeAnonymize(k int, r *rand.Rand) (a Undirected, added []Edge, err error) {
+	a, _ = g.Copy()
+	n := g.Order()
+	if k <= 1 || n == 0 {
+		return a, nil, nil
+	}
+	if k > n {
+		return Undirected{}, nil,
+			fmt.Errorf("k = %d greater than graph order %d", k, n)
+	}
+	rp, ri := rand.Perm, rand.Intn
+	if r != nil {
+		rp, ri = r.Perm, r.Intn
+	}
+	deg := make([]int, n)
+	for i := range deg {
+		deg[i] = g.Degree(NI(i))
+	}
+	// existing edges, stored with N1 <= N2
+	adj := map[Edge]bool{}
+	for fr, to := range g.AdjacencyList {
+		for _, to := range to {
+			adj[ordEdge(NI(fr), to)] = true
+		}
+	}
+	// tie-breaking order, fixed over all attempts
+	tie := rp(n)
+	base := append([]int{}, deg...) // degrees with probing increments
+	for try := 0; try < kAnonTries; try++ {
+		// sort nodes by decreasing base degree
+		ord := make([]NI, n)
+		for i, t := range tie {
+			ord[i] = NI(t)
+		}
+		sort.SliceStable(ord, func(i, j int) bool {
+			return base[ord[i]] > base[ord[j]]
+		})
+		s := make([]int, n)
+		for i, v := range ord {
+			s[i] = base[v]
+		}
+		target := kAnonSequence(s, k)
+		demand := make([]int, n)
+		sum := 0
+		for i, v := range ord {
+			demand[v] = target[i] - deg[v]
+			sum += demand[v]
+		}
+		if sum%2 == 0 {
+			if e, ok := kAnonRealize(demand, ord, adj); ok {
+				for _, e := range e {
+					a.AddEdge(e.N1, e.N2)
+				}
+				return a, e, nil
+			}
+		}
+		// probe: perturb the sequence and try again
+		base[ri(n)]++
+	}
+	return Undirected{}, nil,
+		fmt.Errorf("k-degree anonymization not realized in %d attempts",
+			kAnonTries)
+}
+
+// kAnonSequence returns a k-anonymous degree sequence for decreasing degree
+// sequence s, minimizing the total increase.
+func kAnonSequence(s []int, k int) []int {
+	n := len(s)
+	p := make([]int, n+1) // prefix sums
+	for i, d := range s {
+		p[i+1] = p[i] + d
+	}
+	// cost of group s[i:j+1] raised to s[i]
+	cost := func(i, j int) int { return (j-i+1)*s[i] - (p[j+1] - p[i]) }
+	// da[j] is the minimum cost for s[:j+1], start[j] the start of its last
+	// group.  groups larger than 2k-1 are never needed, as they can be split
+	// at no greater cost.
+	da := make([]int, n)
+	start := make([]int, n)
+	for j := k - 1; j < n; j++ {
+		if j < 2*k-1 {
+			da[j] = cost(0, j)
+			start[j] = 0
+		} else {
+			da[j] = -1
+		}
+		lo := j - 2*k + 2
+		if lo < k {
+			lo = k
+		}
+		for i := lo; i <= j-k+1; i++ {
+			if c := da[i-1] + cost(i, j); da[j] < 0 || c < da[j] {
+				da[j] = c
+				start[j] = i
+			}
+		}
+	}
+	t := make([]int, n)
+	for j := n - 1; j >= 0; {
+		i := start[j]
+		for x := i; x <= j; x++ {
+			t[x] = s[i]
+		}
+		j = i - 1
+	}
+	return t
+}
+
+// kAnonRealize attempts to find edges realizing the degree demand.
+//
+// ord gives tie-breaking order among nodes of equal demand.  adj holds
+// existing edges and is not modified.
+func kAnonRealize(demand []int, ord []NI, adj map[Edge]bool) (added []Edge, ok bool) {
+	d := append([]int{}, demand...)
+	has := map[Edge]bool{}
+	pos := make([]NI, len(ord))
+	for {
+		// nodes by decreasing remaining demand, ties in ord order
+		copy(pos, ord)
+		sort.SliceStable(pos, func(i, j int) bool { return d[pos[i]] > d[pos[j]] })
+		v := pos[0]
+		if d[v] == 0 {
+			return added, true
+		}
+		for _, u := range pos[1:] {
+			if d[v] == 0 || d[u] == 0 {
+				break
+			}
+			if e := ordEdge(v, u); !adj[e] && !has[e] {
+				has[e] = true
+				added = append(added, e)
+				d[v]--
+				d[u]--
+			}
+		}
+		if d[v] > 0 {
+			return nil, false
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_KDegreeAnonymize() {
+	//   0   3
+	//   |   |
+	//   1---2---4---5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(4, 5)
+	a, added, err := g.KDegreeAnonymize(2, rand.New(rand.NewSource(1)))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("added:", added)
+	fmt.Print("degrees:")
+	for n := range a.AdjacencyList {
+		fmt.Print(" ", a.Degree(graph.NI(n)))
+	}
+	fmt.Println()
+	// Output:
+	// added: [{1 4}]
+	// degrees: 1 3 3 1 3 1
+}
+
+// kAnonymous returns true if every degree value of g is shared by at least k
+// nodes.
+func kAnonymous(g graph.Undirected, k int) bool {
+	c := map[int]int{}
+	for n := range g.AdjacencyList {
+		c[g.Degree(graph.NI(n))]++
+	}
+	for _, c := range c {
+		if c < k {
+			return false
+		}
+	}
+	return true
+}
+
+func TestKDegreeAnonymize(t *testing.T) {
+	for _, tc := range []struct {
+		n, m, k int
+		seed    int64
+	}{
+		{10, 15, 2, 1},
+		{50, 120, 3, 2},
+		{100, 300, 5, 3},
+		{200, 1000, 10, 4},
+		{60, 100, 60, 5}, // all nodes the same degree
+	} {
+		g := graph.GnmUndirected(tc.n, tc.m, rand.New(rand.NewSource(tc.seed)))
+		a, added, err := g.KDegreeAnonymize(tc.k, rand.New(rand.NewSource(tc.seed)))
+		if err != nil {
+			t.Fatal(tc, err)
+		}
+		if !kAnonymous(a, tc.k) {
+			t.Fatal(tc, "not k-anonymous")
+		}
+		// all original edges preserved, exactly the added edges added
+		if a.Size() != g.Size()+len(added) {
+			t.Fatal(tc, a.Size(), g.Size(), len(added))
+		}
+		seen := map[graph.Edge]bool{}
+		for _, e := range added {
+			if e.N1 == e.N2 || seen[e] {
+				t.Fatal(tc, "loop or duplicate", e)
+			}
+			seen[e] = true
+			if has, _, _ := g.HasEdge(e.N1, e.N2); has {
+				t.Fatal(tc, "parallel edge", e)
+			}
+			a.RemoveEdge(e.N1, e.N2)
+		}
+		for fr, to := range g.AdjacencyList {
+			if len(a.AdjacencyList[fr]) != len(to) {
+				t.Fatal(tc, "node", fr, "arcs differ")
+			}
+		}
+		// deterministic under a seeded Rand
+		_, added2, _ := g.KDegreeAnonymize(tc.k, rand.New(rand.NewSource(tc.seed)))
+		if !reflect.DeepEqual(added, added2) {
+			t.Fatal(tc, "not deterministic")
+		}
+	}
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	if _, _, err := g.KDegreeAnonymize(3, nil); err == nil {
+		t.Fatal("no error for k > order")
+	}
+	if a, added, err := g.KDegreeAnonymize(1, nil); err != nil ||
+		len(added) != 0 || a.Size() != 1 {
+		t.Fatal(a, added, err)
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// anytime.go -- anytime heuristic search within a deadline.
+
+import (
+	"container/heap"
+	"math"
+	"time"
+)
+
+// AStarAnytime finds a path between two nodes, improving the path found for
+// as long as a deadline allows.
+//
+// The algorithm is ARA*, anytime repairing A* (Likhachev, Gordon, and
+// Thrun).  A first search orders nodes by path distance plus the heuristic
+// estimate inflated by a factor eps, quickly finding a path no longer than
+// eps times the shortest.  Eps is then repeatedly decreased and the search
+// repaired.  Each repair reuses the path distances found so far:  Nodes
+// whose distance improved after they were expanded in the current
+// iteration are held aside as inconsistent rather than expanded again, and
+// only these and the nodes left open are considered by the next iteration.
+// Eps is decreased by halving eps - 1, and set to 1 when eps - 1 falls below
+// .001.  Argument eps is the initial inflation and values less than 1 are
+// taken as 1.
+//
+// The heuristic h must be monotonic, as for AStarM, and arc weights must be
+// non-negative.  Graphs may be directed or undirected.
+//
+// Search stops when the deadline passes or when the path found is shortest.
+// The deadline is checked periodically during search so the method may
+// return shortly after the deadline.
+//
+// Returned is the best path found with its distance dist and a bound on its
+// suboptimality:  dist is at most bound times the shortest path distance.
+// Bound is the lesser of the eps of the last completed iteration and the
+// ratio of the path distance to a lower bound on the shortest distance
+// computed from the nodes remaining open.  If the path is proven shortest,
+// bound is 1 and optimal is true.  If the deadline passes during the first
+// iteration, bound is +Inf even if a path was found.  If the deadline passes
+// before any path is found, AStarAnytime returns an empty path, dist and
+// bound of +Inf, and optimal = false.  If there is no path, it returns an
+// empty path, dist of +Inf, bound of 1, and optimal = true.
+func (g LabeledAdjacencyList) AStarAnytime(w WeightFunc, start, end NI, h Heuristic, eps float64, deadline time.Time) (path LabeledPath, dist, bound float64, optimal bool) {
+	return g.aStarAnytime(w, start, end, h, eps, deadline,
+		func(eps, dist, bound float64) {})
+}
+
+// aStarAnytime implements AStarAnytime, calling solution with the eps,
+// path distance, and bound of each completed iteration that finds a path.
+func (g LabeledAdjacencyList) aStarAnytime(w WeightFunc, start, end NI, h Heuristic, eps float64, deadline time.Time, solution func(eps, dist, bound float64)) (path LabeledPath, dist, bound float64, optimal bool) {
+	if eps < 1 {
+		eps = 1
+	}
+	s := newARASearch(g, w, h, start, end)
+	bound = math.Inf(1)
+	dist = math.Inf(1)
+	for {
+		if !s.improve(eps, deadline) {
+			break
+		}
+		de := s.d[end]
+		if math.IsInf(de, 1) {
+			// open emptied without reaching end
+			return LabeledPath{}, de, 1, true
+		}
+		// the path by previous nodes can be longer than that of an earlier
+		// iteration.  keep the best path found.
+		if pd := s.pathDist(); pd < dist {
+			path, dist = s.path(), pd
+		}
+		b := eps
+		if lb := s.lowerBound(); dist <= lb {
+			b = 1
+		} else if r := dist / lb; r < b {
+			b = r
+		}
+		bound = b
+		solution(eps, dist, bound)
+		if bound <= 1 {
+			break
+		}
+		if eps -= (eps - 1) / 2; eps-1 < .001 {
+			eps = 1
+		}
+		s.repair(eps)
+	}
+	if !math.IsInf(dist, 1) {
+		return path, dist, bound, bound <= 1
+	}
+	// deadline passed during the first iteration
+	if math.IsInf(s.d[end], 1) {
+		return LabeledPath{}, dist, bound, false
+	}
+	return s.path(), s.pathDist(), bound, false
+}
+
+// araSearch holds the state of an AStarAnytime search across iterations.
+type araSearch struct {
+	g          LabeledAdjacencyList
+	w          WeightFunc
+	h          Heuristic
+	start, end NI
+	d          []float64 // path distance from start
+	from       []NI      // previous node on path, -1 for none
+	labels     []LI      // label of arc from previous node
+	r          []rNode   // heap nodes, fx = -1 when not open
+	open       openHeap
+	iter       int   // current iteration, starting at 1
+	closed     []int // iteration in which each node was expanded
+	incons     []NI  // nodes improved after expansion in this iteration
+	inIncons   []bool
+}
+
+func newARASearch(g LabeledAdjacencyList, w WeightFunc, h Heuristic, start, end NI) *araSearch {
+	s := &araSearch{
+		g:        g,
+		w:        w,
+		h:        h,
+		start:    start,
+		end:      end,
+		d:        make([]float64, len(g)),
+		from:     make([]NI, len(g)),
+		labels:   make([]LI, len(g)),
+		r:        make([]rNode, len(g)),
+		iter:     1,
+		closed:   make([]int, len(g)),
+		inIncons: make([]bool, len(g)),
+	}
+	inf := math.Inf(1)
+	for i := range s.d {
+		s.d[i] = inf
+		s.from[i] = -1
+		s.r[i] = rNode{nx: NI(i), fx: -1}
+	}
+	s.d[start] = 0
+	heap.Push(&s.open, &s.r[start])
+	return s
+}
+
+// improve expands open nodes in order of d + eps*h until no open node can
+// improve the path to end.  It returns false if stopped by the deadline.
+func (s *araSearch) improve(eps float64, deadline time.Time) bool {
+	for i := 0; len(s.open) > 0; i++ {
+		if s.d[s.end]+eps*s.h(s.end) <= s.open[0].f {
+			break
+		}
+		if i&63 == 63 && time.Now().After(deadline) {
+			return false
+		}
+		n := heap.Pop(&s.open).(*rNode).nx
+		s.closed[n] = s.iter
+		for _, nb := range s.g[n] {
+			to := nb.To
+			d := s.d[n] + s.w(nb.Label)
+			if d >= s.d[to] {
+				continue
+			}
+			s.d[to] = d
+			s.from[to] = n
+			s.labels[to] = nb.Label
+			if s.closed[to] == s.iter {
+				// distance improved after expansion.  hold for the next
+				// iteration rather than expanding again.
+				if !s.inIncons[to] {
+					s.inIncons[to] = true
+					s.incons = append(s.incons, to)
+				}
+				continue
+			}
+			rt := &s.r[to]
+			rt.f = d + eps*s.h(to)
+			if rt.fx < 0 {
+				heap.Push(&s.open, rt)
+			} else {
+				heap.Fix(&s.open, rt.fx)
+			}
+		}
+	}
+	return true
+}
+
+// lowerBound returns the minimum of d + h over open and inconsistent nodes,
+// a lower bound on the shortest path distance to end.
+func (s *araSearch) lowerBound() float64 {
+	lb := math.Inf(1)
+	for _, r := range s.open {
+		if f := s.d[r.nx] + s.h(r.nx); f < lb {
+			lb = f
+		}
+	}
+	for _, n := range s.incons {
+		if f := s.d[n] + s.h(n); f < lb {
+			lb = f
+		}
+	}
+	return lb
+}
+
+// repair prepares the next iteration with inflation eps, moving inconsistent
+// nodes to open, recomputing priorities, and clearing the closed set.
+func (s *araSearch) repair(eps float64) {
+	for _, n := range s.incons {
+		s.inIncons[n] = false
+		if r := &s.r[n]; r.fx < 0 {
+			s.open = append(s.open, r)
+		}
+	}
+	s.incons = s.incons[:0]
+	for i, r := range s.open {
+		r.fx = i
+		r.f = s.d[r.nx] + eps*s.h(r.nx)
+	}
+	heap.Init(&s.open)
+	s.iter++
+}
+
+// path returns the path to end by previous nodes.
+func (s *araSearch) path() LabeledPath {
+	var p []Half
+	n := s.end
+	for ; n != s.start; n = s.from[n] {
+		p = append(p, Half{n, s.labels[n]})
+	}
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+	return LabeledPath{s.start, p}
+}
+
+// pathDist returns the distance of the path to end by previous nodes.
+//
+// Distances of previous nodes may have improved since they were recorded,
+// so the result may be less than d[end].
+func (s *araSearch) pathDist() float64 {
+	dist := 0.
+	for n := s.end; n != s.start; n = s.from[n] {
+		dist += s.w(s.labels[n])
+	}
+	return dist
+}
@@ -0,0 +1,153 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledAdjacencyList_AStarAnytime() {
+	g := gridMaze(`
+		S..#....
+		.#.#.##.
+		.#...#..
+		.####.#.
+		......#E`, graph.Orthogonal)
+	start, end := g.At(0, 0), g.At(4, 7)
+	deadline := time.Now().Add(50 * time.Millisecond)
+	p, d, bound, optimal := g.G.AStarAnytime(g.Weight, start, end,
+		g.Heuristic(end), 3, deadline)
+	fmt.Println("nodes:", len(p.Path)+1)
+	fmt.Println("distance:", d)
+	fmt.Println("bound:", bound)
+	fmt.Println("optimal:", optimal)
+	// Output:
+	// nodes: 16
+	// distance: 15
+	// bound: 1
+	// optimal: true
+}
+
+// randomGrid returns a grid with about one cell in four impassable, other
+// than the corners.
+func randomGrid(rows, cols int, diag graph.DiagonalPolicy, r *rand.Rand) *graph.Grid {
+	return graph.NewGrid(rows, cols, func(rw, c int) bool {
+		if rw == 0 && c == 0 || rw == rows-1 && c == cols-1 {
+			return true
+		}
+		return r.Intn(4) > 0
+	}, diag)
+}
+
+func TestAStarAnytime(t *testing.T) {
+	r := rand.New(rand.NewSource(21))
+	for i := 0; i < 50; i++ {
+		g := randomGrid(5+r.Intn(40), 5+r.Intn(40),
+			graph.DiagonalPolicy(r.Intn(4)), r)
+		start, end := g.At(0, 0), g.At(g.Rows-1, g.Cols-1)
+		h := g.Heuristic(end)
+		_, _, want, ok := g.G.AStarM(g.Weight, start, end, h)
+		if !ok {
+			want = math.Inf(1)
+		}
+		p, d, bound, optimal := g.G.AStarAnytime(g.Weight, start, end, h,
+			1+4*r.Float64(), time.Now().Add(time.Minute))
+		if !optimal || bound != 1 {
+			t.Fatal(i, "not optimal with generous deadline", bound)
+		}
+		if !ok {
+			if len(p.Path) != 0 || !math.IsInf(d, 1) {
+				t.Fatal(i, "path found", p, d)
+			}
+			continue
+		}
+		if math.Abs(d-want) > 1e-9 {
+			t.Fatal(i, "distance", d, "want", want)
+		}
+		// path is valid and has distance d
+		fr, pd := p.Start, 0.
+		for _, h := range p.Path {
+			if has, _ := g.G.HasArcLabel(fr, h.To, h.Label); !has {
+				t.Fatal(i, "invalid path", p)
+			}
+			pd += g.Weight(h.Label)
+			fr = h.To
+		}
+		if fr != end || math.Abs(pd-d) > 1e-9 {
+			t.Fatal(i, "path", p, "distance", pd, "want", d)
+		}
+	}
+}
+
+// TestAStarAnytimeSolutions checks that the path distance of solutions
+// found by successive iterations never increases.
+func TestAStarAnytimeSolutions(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	for i := 0; i < 50; i++ {
+		g := randomGrid(5+r.Intn(60), 5+r.Intn(60),
+			graph.DiagonalPolicy(r.Intn(4)), r)
+		start, end := g.At(0, 0), g.At(g.Rows-1, g.Cols-1)
+		var dists []float64
+		_, d, _, _ := g.G.AStarAnytimeSolutions(g.Weight, start, end,
+			g.Heuristic(end), 2+8*r.Float64(), time.Now().Add(time.Minute),
+			func(eps, dist, bound float64) {
+				dists = append(dists, dist)
+			})
+		if math.IsInf(d, 1) {
+			if len(dists) != 0 {
+				t.Fatal(i, "no path, solutions", dists)
+			}
+			continue
+		}
+		if len(dists) == 0 || dists[len(dists)-1] != d {
+			t.Fatal(i, "solutions", dists, "returned distance", d)
+		}
+		for j := 1; j < len(dists); j++ {
+			if dists[j] > dists[j-1] {
+				t.Fatal(i, "solution distances increased", dists)
+			}
+		}
+	}
+}
+
+func TestAStarAnytimeDeadline(t *testing.T) {
+	// a deadline stops search early.  whatever is returned must be
+	// consistent:  the distance is within the bound of the shortest, and
+	// optimal only if the bound is 1.
+	r := rand.New(rand.NewSource(22))
+	g := randomGrid(300, 300, graph.DiagonalNoCornerCut, r)
+	start, end := g.At(0, 0), g.At(g.Rows-1, g.Cols-1)
+	h := g.Heuristic(end)
+	_, _, want, _ := g.G.AStarM(g.Weight, start, end, h)
+	p, d, bound, optimal := g.G.AStarAnytime(g.Weight, start, end, h, 3,
+		time.Now().Add(-time.Second))
+	switch {
+	case optimal:
+		t.Fatal("optimal after immediate deadline")
+	case len(p.Path) == 0:
+		if !math.IsInf(d, 1) || !math.IsInf(bound, 1) {
+			t.Fatal("no path, distance", d, "bound", bound)
+		}
+	case d > bound*want+1e-9:
+		t.Fatal("distance", d, "bound", bound, "shortest", want)
+	}
+	for _, dl := range []time.Duration{time.Millisecond, 10 * time.Millisecond,
+		100 * time.Millisecond} {
+		_, d, bound, optimal := g.G.AStarAnytime(g.Weight, start, end, h, 3,
+			time.Now().Add(dl))
+		if d > bound*want+1e-9 {
+			t.Fatal(dl, "distance", d, "bound", bound, "shortest", want)
+		}
+		if optimal != (bound == 1) || optimal && math.Abs(d-want) > 1e-9 {
+			t.Fatal(dl, "optimal", optimal, "bound", bound, "distance", d,
+				"shortest", want)
+		}
+	}
+}
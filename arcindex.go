@@ -0,0 +1,57 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// arcindex.go -- index for repeated arc membership queries.
+
+import "sort"
+
+// ArcIndex answers arc membership queries in O(log d) time for a node of
+// out-degree d.
+//
+// An ArcIndex is built by AdjacencyList.BuildArcIndex or
+// LabeledAdjacencyList.BuildArcIndex.  It holds a sorted copy of the to-nodes
+// of all arcs of the graph, ignoring labels, and so does not reflect changes
+// made to the graph after it is built.  The index must be rebuilt after any
+// mutation of the graph.
+//
+// For an undirected graph, HasArc(n1, n2) tests for an edge between n1 and
+// n2.
+type ArcIndex struct {
+	start []int // arcs from node n are to[start[n]:start[n+1]]
+	to    []NI
+}
+
+// sort sorts the to-nodes of each node.
+func (x ArcIndex) sort() {
+	for n := 0; n+1 < len(x.start); n++ {
+		to := x.to[x.start[n]:x.start[n+1]]
+		sort.Slice(to, func(i, j int) bool { return to[i] < to[j] })
+	}
+}
+
+// search returns the arcs from fr and the index of the first arc to a node
+// >= to.
+func (x ArcIndex) search(fr, to NI) ([]NI, int) {
+	t := x.to[x.start[fr]:x.start[fr+1]]
+	return t, sort.Search(len(t), func(i int) bool { return t[i] >= to })
+}
+
+// HasArc returns true if the indexed graph has any arc from node fr to
+// node to.
+func (x ArcIndex) HasArc(fr, to NI) bool {
+	t, i := x.search(fr, to)
+	return i < len(t) && t[i] == to
+}
+
+// ArcCount returns the number of arcs from node fr to node to in the
+// indexed graph, the number of parallel arcs if more than one.
+func (x ArcIndex) ArcCount(fr, to NI) int {
+	t, i := x.search(fr, to)
+	j := i
+	for j < len(t) && t[j] == to {
+		j++
+	}
+	return j - i
+}
@@ -0,0 +1,279 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// bipartite.go -- bipartite double cover and odd cycle transversal.
+
+import "github.com/soniakeys/bits"
+
+// BipartiteDouble constructs the bipartite double cover of g.
+//
+// The double cover has two copies of each node of g, copy 0 and copy 1.  For
+// a graph g of order n, node v of copy c is node v + c*n of the double cover.
+// BipartiteDoubleNI and BipartiteDoubleOriginal convert between the
+// numberings.  For each edge u-v of g, the double cover has edges joining
+// u of copy 0 to v of copy 1 and v of copy 0 to u of copy 1.  A loop at u
+// becomes a single edge joining the two copies of u.
+//
+// A walk in the double cover alternates copies, so there is a walk of even
+// length from u to v in g if and only if u and v of the same copy are
+// connected in the double cover, and a walk of odd length if and only if u
+// and v of opposite copies are connected.  The double cover of a connected
+// bipartite graph is two disjoint copies of the graph; the double cover of a
+// connected non-bipartite graph is connected.
+func (g Undirected) BipartiteDouble() Undirected {
+	a := g.AdjacencyList
+	n := NI(len(a))
+	d := make(AdjacencyList, 2*len(a))
+	for fr, to := range a {
+		d0 := make([]NI, len(to))
+		d1 := make([]NI, len(to))
+		for x, to := range to {
+			d0[x] = to + n
+			d1[x] = to
+		}
+		d[fr] = d0
+		d[NI(fr)+n] = d1
+	}
+	return Undirected{d}
+}
+
+// BipartiteDoubleNI returns the node of the bipartite double cover of a
+// graph of the given order corresponding to node n of copy c, where c is
+// 0 or 1.
+//
+// See Undirected.BipartiteDouble.
+func BipartiteDoubleNI(order int, n NI, c int) NI {
+	return n + NI(c*order)
+}
+
+// BipartiteDoubleOriginal returns the node n and copy c of the original
+// graph of the given order corresponding to node d of its bipartite double
+// cover.
+//
+// See Undirected.BipartiteDouble.
+func BipartiteDoubleOriginal(order int, d NI) (n NI, c int) {
+	if int(d) >= order {
+		return d - NI(order), 1
+	}
+	return d, 0
+}
+
+// OddCycleTransversalApprox finds a small odd cycle transversal of g, a set
+// of nodes whose removal leaves g bipartite.
+//
+// The algorithm is iterative compression (Reed, Smith, and Vetta) with
+// budget maxSize.  Nodes are added one at a time while maintaining a
+// transversal of at most maxSize nodes of the graph induced so far.  When a
+// node added makes the transversal too large, a compression step tries each
+// way of dividing the transversal into nodes that remain removed and nodes
+// restored with either color, and for each finds a minimum node cut in the
+// bipartite remainder by max flow.  Time is O(3^k k n m) for k = maxSize,
+// n nodes, and m edges.
+//
+// If g has an odd cycle transversal of at most maxSize nodes, the method
+// returns a transversal of at most maxSize nodes, as a bitmap oct of the
+// order of g, and ok = true.  The transversal found is minimal, in that no
+// node can be restored without creating an odd cycle, but it is not
+// necessarily minimum.  If no transversal of at most maxSize nodes exists,
+// the method returns ok = false.
+//
+// A loop is an odd cycle, so nodes with loops are always in the transversal.
+func (g Undirected) OddCycleTransversalApprox(maxSize int) (oct bits.Bits, ok bool) {
+	a := g.AdjacencyList
+	n := len(a)
+	keep := bits.New(n)
+	keep.SetAll()
+	if _, ok := g.octColor(keep); ok {
+		return bits.New(n), true
+	}
+	if maxSize < 1 {
+		return bits.Bits{}, false
+	}
+	in := bits.New(n) // nodes of the induced subgraph so far
+	var s []NI        // transversal of the induced subgraph
+	for v := 0; v < n; v++ {
+		in.SetBit(v, 1)
+		s = append(s, NI(v))
+		if len(s) > maxSize {
+			if s, ok = g.octCompress(in, s, maxSize); !ok {
+				return bits.Bits{}, false
+			}
+		}
+	}
+	// make minimal by restoring nodes where possible
+	for _, v := range s {
+		keep.SetBit(int(v), 0)
+	}
+	for _, v := range s {
+		keep.SetBit(int(v), 1)
+		if _, ok := g.octColor(keep); !ok {
+			keep.SetBit(int(v), 0)
+		}
+	}
+	oct = bits.New(n)
+	oct.Not(keep)
+	return oct, true
+}
+
+// octColor 2-colors the subgraph of g induced by the nodes of keep.
+//
+// Returned color is 0 or 1 for nodes of keep, -1 for other nodes.  If the
+// subgraph is not bipartite, ok is false.
+func (g Undirected) octColor(keep bits.Bits) (color []int8, ok bool) {
+	a := g.AdjacencyList
+	color = make([]int8, len(a))
+	for i := range color {
+		color[i] = -1
+	}
+	var q []NI
+	ok = keep.IterateOnes(func(r int) bool {
+		if color[r] >= 0 {
+			return true
+		}
+		color[r] = 0
+		q = append(q[:0], NI(r))
+		for len(q) > 0 {
+			fr := q[0]
+			q = q[1:]
+			for _, to := range a[fr] {
+				switch {
+				case keep.Bit(int(to)) == 0:
+				case color[to] < 0:
+					color[to] = 1 - color[fr]
+					q = append(q, to)
+				case color[to] == color[fr]:
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return
+}
+
+// octCompress is the compression step of OddCycleTransversalApprox.
+//
+// Argument s is a transversal of k+1 nodes of the subgraph induced by in.
+// If the subgraph has a transversal of at most k nodes, octCompress returns
+// one with ok = true.
+func (g Undirected) octCompress(in bits.Bits, s []NI, k int) (t []NI, ok bool) {
+	a := g.AdjacencyList
+	n := len(a)
+	// h is the bipartite remainder, with coloring c.
+	h := bits.New(n)
+	h.Set(in)
+	for _, v := range s {
+		h.SetBit(int(v), 0)
+	}
+	c, _ := g.octColor(h)
+	// each node of s is removed (-1) or restored with color 0 or 1.
+	sc := make([]int8, len(s))
+	for i := range sc {
+		sc[i] = -1
+	}
+	yc := make([]int8, n) // colors of restored nodes, by node
+	for i := range yc {
+		yc[i] = -1
+	}
+	for {
+		if t, ok = g.octTry(h, c, s, sc, yc, k); ok {
+			return t, true
+		}
+		// next division of s
+		i := 0
+		for ; i < len(sc) && sc[i] == 1; i++ {
+			sc[i] = -1
+		}
+		if i == len(sc) {
+			return nil, false
+		}
+		sc[i]++
+	}
+}
+
+// octTry tries one division of s for octCompress, with the nodes of s
+// removed or restored according to sc.
+func (g Undirected) octTry(h bits.Bits, c []int8, s []NI, sc, yc []int8, k int) (t []NI, ok bool) {
+	a := g.AdjacencyList
+	n := len(a)
+	budget := k
+	for i, v := range s {
+		yc[v] = sc[i]
+		if sc[i] < 0 {
+			budget--
+		}
+	}
+	defer func() {
+		for _, v := range s {
+			yc[v] = -1
+		}
+	}()
+	if budget < 0 {
+		return nil, false
+	}
+	// restored nodes must be properly colored among themselves.  their
+	// neighbors in h must take the opposite color, keeping (keep) or
+	// flipping (flip) their color in c.
+	keep := bits.New(n)
+	flip := bits.New(n)
+	terminals := false
+	for _, v := range s {
+		if yc[v] < 0 {
+			continue
+		}
+		for _, to := range a[v] {
+			switch {
+			case yc[to] == yc[v]:
+				return nil, false // includes loops
+			case h.Bit(int(to)) == 0:
+			case c[to] != yc[v]:
+				keep.SetBit(int(to), 1)
+				terminals = true
+			default:
+				flip.SetBit(int(to), 1)
+				terminals = true
+			}
+		}
+	}
+	for _, v := range s {
+		if yc[v] < 0 {
+			t = append(t, v)
+		}
+	}
+	if !terminals {
+		return t, true
+	}
+	// minimum node cut in h separating keep from flip, where terminals
+	// themselves may be cut.
+	f := make(flowNet, 2*n+2)
+	src, snk := NI(2*n), NI(2*n+1)
+	h.IterateOnes(func(fr int) bool {
+		in, out := NI(2*fr), NI(2*fr+1)
+		f.addArc(in, out, 1)
+		if keep.Bit(fr) == 1 {
+			f.addArc(src, in, flowInf)
+		}
+		if flip.Bit(fr) == 1 {
+			f.addArc(out, snk, flowInf)
+		}
+		for _, to := range a[fr] {
+			if h.Bit(int(to)) == 1 {
+				f.addArc(out, 2*to, flowInf)
+			}
+		}
+		return true
+	})
+	if f.maxFlow(src, snk, float64(budget)) > float64(budget) {
+		return nil, false
+	}
+	r := f.reach(src)
+	h.IterateOnes(func(i int) bool {
+		if r.Bit(2*i) == 1 && r.Bit(2*i+1) == 0 {
+			t = append(t, NI(i))
+		}
+		return true
+	})
+	return t, true
+}
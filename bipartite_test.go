@@ -0,0 +1,165 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_BipartiteDouble() {
+	// 0--1
+	//  \ |
+	//   \2
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 0)
+	d := g.BipartiteDouble()
+	for fr, to := range d.AdjacencyList {
+		n, c := graph.BipartiteDoubleOriginal(g.Order(), graph.NI(fr))
+		fmt.Printf("%d (node %d copy %d): %d\n", fr, n, c, to)
+	}
+	fmt.Println(graph.BipartiteDoubleNI(g.Order(), 2, 1))
+	// Output:
+	// 0 (node 0 copy 0): [4 5]
+	// 1 (node 1 copy 0): [3 5]
+	// 2 (node 2 copy 0): [4 3]
+	// 3 (node 0 copy 1): [1 2]
+	// 4 (node 1 copy 1): [0 2]
+	// 5 (node 2 copy 1): [1 0]
+	// 5
+}
+
+func ExampleUndirected_OddCycleTransversalApprox() {
+	// two triangles sharing node 2, and a square
+	//  0   3
+	//  |\ /|
+	//  | 2 |   5--6
+	//  |/ \|   |  |
+	//  1   4   7--8
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 0)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 2)
+	g.AddEdge(5, 6)
+	g.AddEdge(6, 8)
+	g.AddEdge(8, 7)
+	g.AddEdge(7, 5)
+	oct, ok := g.OddCycleTransversalApprox(1)
+	fmt.Println(oct.Slice(), ok)
+	// Output:
+	// [2] true
+}
+
+func TestBipartiteDoubleBipartite(t *testing.T) {
+	r := rand.New(rand.NewSource(47))
+	for i := 0; i < 50; i++ {
+		// random bipartite graph: edges between even and odd nodes
+		n := 2 + r.Intn(20)
+		g := graph.Undirected{make(graph.AdjacencyList, n)}
+		for m := r.Intn(2 * n); m > 0; m-- {
+			u := r.Intn(n)
+			v := r.Intn(n)
+			if (u+v)%2 == 1 {
+				g.AddEdge(graph.NI(u), graph.NI(v))
+			}
+		}
+		_, nc := g.ConnectedComponentInts()
+		d := g.BipartiteDouble()
+		ci, dc := d.ConnectedComponentInts()
+		if dc != 2*nc {
+			t.Fatal(i, "double cover has", dc, "components, want", 2*nc)
+		}
+		// the two copies of a node are in distinct components
+		for v := 0; v < n; v++ {
+			if ci[v] == ci[v+n] {
+				t.Fatal(i, "copies of node", v, "connected")
+			}
+		}
+	}
+}
+
+func TestBipartiteDoubleOddCycle(t *testing.T) {
+	for n := 3; n < 12; n += 2 {
+		var g graph.Undirected
+		for v := 0; v < n; v++ {
+			g.AddEdge(graph.NI(v), graph.NI((v+1)%n))
+		}
+		d := g.BipartiteDouble()
+		if !d.IsConnected() || d.Order() != 2*n || d.Size() != 2*n {
+			t.Fatal(n, "double cover not a", 2*n, "cycle:", d.AdjacencyList)
+		}
+		for v := range d.AdjacencyList {
+			if d.Degree(graph.NI(v)) != 2 {
+				t.Fatal(n, "node", v, "degree", d.Degree(graph.NI(v)))
+			}
+		}
+	}
+}
+
+// remainderBipartite returns true if g less the nodes of oct is bipartite.
+func remainderBipartite(g graph.Undirected, oct uint) bool {
+	var r graph.Undirected
+	r.AdjacencyList = make(graph.AdjacencyList, g.Order())
+	for fr, to := range g.AdjacencyList {
+		if oct>>uint(fr)&1 == 1 {
+			continue
+		}
+		for _, to := range to {
+			if oct>>uint(to)&1 == 0 {
+				r.AdjacencyList[fr] = append(r.AdjacencyList[fr], to)
+			}
+		}
+	}
+	_, _, ok := r.Bipartite()
+	return ok
+}
+
+func TestOddCycleTransversalApprox(t *testing.T) {
+	r := rand.New(rand.NewSource(48))
+	for i := 0; i < 300; i++ {
+		n := 1 + r.Intn(10)
+		var g graph.Undirected
+		g.AdjacencyList = make(graph.AdjacencyList, n)
+		for m := r.Intn(3 * n); m > 0; m-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		// brute force minimum
+		min := n
+		for s := uint(0); s < 1<<uint(n); s++ {
+			if c := bits.OnesCount(s); c < min && remainderBipartite(g, s) {
+				min = c
+			}
+		}
+		maxSize := r.Intn(4)
+		oct, ok := g.OddCycleTransversalApprox(maxSize)
+		if ok != (min <= maxSize) {
+			t.Fatal(i, "maxSize", maxSize, "min", min, "ok", ok)
+		}
+		if !ok {
+			continue
+		}
+		var s uint
+		for _, v := range oct.Slice() {
+			s |= 1 << uint(v)
+		}
+		if oct.OnesCount() > maxSize || !remainderBipartite(g, s) {
+			t.Fatal(i, "oct", oct.Slice(), "maxSize", maxSize)
+		}
+		// minimal: no node can be restored
+		for _, v := range oct.Slice() {
+			if remainderBipartite(g, s&^(1<<uint(v))) {
+				t.Fatal(i, "oct", oct.Slice(), "node", v, "not needed")
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// branching.go -- maximum weight branching.
+
+// MaxBranching finds a maximum weight branching of g.
+//
+// A branching is a set of arcs forming a forest of arborescences, that is,
+// with no cycles and at most one arc into each node.  There is no fixed
+// root.  The branching of maximum total weight is found with Edmonds'
+// algorithm:  Each node selects its incoming arc of greatest positive
+// weight.  If the selected arcs form cycles, each cycle is contracted to a
+// single node, with the weight of each arc entering the cycle adjusted for
+// the cycle arc it would replace, and the contracted graph is solved
+// recursively.  Arcs of non-positive weight, including arcs of
+// non-positive adjusted weight, are never selected, and loops are ignored.
+// Time is O(nm) for n nodes and m arcs.
+//
+// Arc weights are given by w.  The branching is returned as a FromList with
+// roots having From of -1, the labels of the selected arcs indexed by to
+// node, and the total weight of selected arcs.  Leaves, Len, and MaxLen of
+// the FromList are valid.  Labels of roots are zero.
+func (g LabeledDirected) MaxBranching(w WeightFunc) (f FromList, labels []LI, total float64) {
+	a := g.LabeledAdjacencyList
+	var arcs []brArc
+	var ls []LI
+	for fr, to := range a {
+		for _, to := range to {
+			if to.To != NI(fr) {
+				arcs = append(arcs, brArc{NI(fr), to.To, w(to.Label)})
+				ls = append(ls, to.Label)
+			}
+		}
+	}
+	f = NewFromList(len(a))
+	labels = make([]LI, len(a))
+	for i := range f.Paths {
+		f.Paths[i].From = -1
+	}
+	for _, x := range maxBranching(len(a), arcs) {
+		e := arcs[x]
+		f.Paths[e.to].From = e.fr
+		labels[e.to] = ls[x]
+		total += e.w
+	}
+	f.RecalcLeaves()
+	f.RecalcLen()
+	return
+}
+
+// brArc is an arc of a graph being solved by maxBranching.
+type brArc struct {
+	fr, to NI
+	w      float64
+}
+
+// maxBranching returns the indexes in arcs of the arcs of a maximum weight
+// branching of a graph of n nodes.  Arcs must not be loops.
+func maxBranching(n int, arcs []brArc) (sel []int) {
+	// best incoming arc of each node, -1 for none
+	best := make([]int, n)
+	for i := range best {
+		best[i] = -1
+	}
+	for x, e := range arcs {
+		if e.w > 0 && (best[e.to] < 0 || e.w > arcs[best[e.to]].w) {
+			best[e.to] = x
+		}
+	}
+	// find cycles of best arcs.  cyc is the cycle number of each node on
+	// a cycle, -1 for others.
+	cyc := make([]int, n)
+	mark := make([]int, n) // walk number that visited each node, 0 for none
+	for i := range cyc {
+		cyc[i] = -1
+	}
+	var cycles [][]NI
+	for s := range best {
+		if mark[s] > 0 {
+			continue
+		}
+		v := NI(s)
+		for mark[v] == 0 {
+			mark[v] = s + 1
+			if best[v] < 0 {
+				break
+			}
+			v = arcs[best[v]].fr
+		}
+		if mark[v] != s+1 || best[v] < 0 {
+			continue // reached an earlier walk or a root
+		}
+		// v is on a new cycle
+		c := []NI{v}
+		cyc[v] = len(cycles)
+		for u := arcs[best[v]].fr; u != v; u = arcs[best[u]].fr {
+			c = append(c, u)
+			cyc[u] = len(cycles)
+		}
+		cycles = append(cycles, c)
+	}
+	if len(cycles) == 0 {
+		for _, x := range best {
+			if x >= 0 {
+				sel = append(sel, x)
+			}
+		}
+		return
+	}
+	// contract.  each cycle becomes a node, numbered after other nodes.
+	cn := make([]NI, n)
+	nc := 0
+	for v, c := range cyc {
+		if c < 0 {
+			cn[v] = NI(nc)
+			nc++
+		}
+	}
+	minW := make([]float64, len(cycles)) // minimum arc weight of each cycle
+	for c, nodes := range cycles {
+		minW[c] = arcs[best[nodes[0]]].w
+		for _, v := range nodes {
+			cn[v] = NI(nc + c)
+			if w := arcs[best[v]].w; w < minW[c] {
+				minW[c] = w
+			}
+		}
+	}
+	var sub []brArc
+	var orig []int // index in arcs of each arc of sub
+	for x, e := range arcs {
+		fr, to := cn[e.fr], cn[e.to]
+		if fr == to {
+			continue
+		}
+		w := e.w
+		if c := cyc[e.to]; c >= 0 {
+			w += minW[c] - arcs[best[e.to]].w
+		}
+		sub = append(sub, brArc{fr, to, w})
+		orig = append(orig, x)
+	}
+	// expand.  a cycle entered by a selected arc keeps all cycle arcs but
+	// the one into the entered node.  other cycles keep all arcs but one of
+	// minimum weight.
+	entered := make([]NI, len(cycles))
+	for i := range entered {
+		entered[i] = -1
+	}
+	for _, s := range maxBranching(nc+len(cycles), sub) {
+		x := orig[s]
+		sel = append(sel, x)
+		if c := cyc[arcs[x].to]; c >= 0 {
+			entered[c] = arcs[x].to
+		}
+	}
+	for c, nodes := range cycles {
+		drop := entered[c]
+		if drop < 0 {
+			for _, v := range nodes {
+				if arcs[best[v]].w == minW[c] {
+					drop = v
+					break
+				}
+			}
+		}
+		for _, v := range nodes {
+			if v != drop {
+				sel = append(sel, best[v])
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledDirected_MaxBranching() {
+	// arc labels are weights.  the cycle 0->1->2->0 must be broken.
+	// entering it with arc 3->0 gains more than dropping its lightest arc.
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 5}},
+		1: {{To: 2, Label: 4}},
+		2: {{To: 0, Label: 3}},
+		3: {{To: 0, Label: 2}, {To: 4, Label: -2}},
+		4: nil,
+	}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	f, labels, total := g.MaxBranching(w)
+	for n, e := range f.Paths {
+		if e.From >= 0 {
+			fmt.Printf("%d->%d weight %d\n", e.From, n, labels[n])
+		}
+	}
+	fmt.Println("total", total)
+	// Output:
+	// 3->0 weight 2
+	// 0->1 weight 5
+	// 1->2 weight 4
+	// total 11
+}
+
+// bruteMaxBranching returns the maximum branching weight by trying all
+// choices of incoming arc for each node.
+func bruteMaxBranching(g graph.LabeledAdjacencyList, w graph.WeightFunc) float64 {
+	n := len(g)
+	in := make([][]graph.LabeledEdge, n)
+	for fr, to := range g {
+		for _, h := range to {
+			if h.To != graph.NI(fr) {
+				in[h.To] = append(in[h.To],
+					graph.LabeledEdge{graph.Edge{graph.NI(fr), h.To}, h.Label})
+			}
+		}
+	}
+	best := 0.
+	from := make([]graph.NI, n)
+	var try func(v int, sum float64)
+	try = func(v int, sum float64) {
+		if v == n {
+			f := graph.NewFromList(n)
+			for i, fr := range from {
+				f.Paths[i].From = fr
+			}
+			if cyclic, _ := f.Cyclic(); !cyclic && sum > best {
+				best = sum
+			}
+			return
+		}
+		from[v] = -1
+		try(v+1, sum)
+		for _, e := range in[v] {
+			from[v] = e.N1
+			try(v+1, sum+w(e.LI))
+		}
+	}
+	try(0, 0)
+	return best
+}
+
+func TestMaxBranching(t *testing.T) {
+	r := rand.New(rand.NewSource(24))
+	w := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 500; i++ {
+		n := 1 + r.Intn(6)
+		g := make(graph.LabeledAdjacencyList, n)
+		for m := r.Intn(3 * n); m > 0; m-- {
+			fr, to := r.Intn(n), graph.NI(r.Intn(n))
+			// weights -3 through 9, with ties
+			g[fr] = append(g[fr], graph.Half{to, graph.LI(r.Intn(13) - 3)})
+		}
+		f, labels, total := graph.LabeledDirected{g}.MaxBranching(w)
+		if cyclic, _ := f.Cyclic(); cyclic {
+			t.Fatal(i, "cyclic", f.Paths, g)
+		}
+		sum := 0.
+		for v, e := range f.Paths {
+			if e.From < 0 {
+				continue
+			}
+			if e.From == graph.NI(v) {
+				t.Fatal(i, "loop selected", g)
+			}
+			if labels[v] <= 0 {
+				t.Fatal(i, "non-positive arc selected", labels[v], g)
+			}
+			if has, _ := g.HasArcLabel(e.From, graph.NI(v), labels[v]); !has {
+				t.Fatal(i, "arc", e.From, v, labels[v], "not in graph", g)
+			}
+			sum += w(labels[v])
+		}
+		if sum != total {
+			t.Fatal(i, "total", total, "sum of arcs", sum)
+		}
+		if want := bruteMaxBranching(g, w); math.Abs(total-want) > 1e-9 {
+			t.Fatal(i, "total", total, "want", want, g)
+		}
+		// Len valid
+		for v := range f.Paths {
+			if f.Paths[v].Len != len(f.PathTo(graph.NI(v), nil)) {
+				t.Fatal(i, "Len", v, f.Paths)
+			}
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// ccparallel.go -- connected components by concurrent union-find.
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ccParallelMin is the graph order below which ConnectedComponentsParallel
+// uses the sequential algorithm.
+const ccParallelMin = 1 << 12
+
+// ccChunk is the number of nodes claimed by a worker at a time.
+const ccChunk = 1 << 10
+
+// ConnectedComponentsParallel returns a list of component numbers (ints) for
+// each node of graph g, computed with concurrent worker goroutines.
+//
+// Results are identical to those of ConnectedComponentInts:  The method
+// returns the number of components nc and a list of component numbers ci for
+// each node.  Component numbers run from 1 to nc, numbered in order of the
+// lowest node of each component.
+//
+// The algorithm is a lock-free union-find in the style of hook and compress
+// algorithms such as Shiloach-Vishkin.  Workers claim chunks of nodes and
+// for each edge hook the root of greater node number to the root of lesser
+// node number with an atomic compare and swap, retrying if another worker
+// changed the root concurrently.  Finds halve paths, also with compare and
+// swap.  Because roots are always hooked to lesser roots, the final root of
+// each component is its lowest node, which gives the deterministic
+// numbering.  A final parallel pass compresses all paths.
+//
+// For workers < 2 or for graphs of small order, the sequential
+// ConnectedComponentInts is used.
+func (g Undirected) ConnectedComponentsParallel(workers int) (ci []int, nc int) {
+	a := g.AdjacencyList
+	if workers < 2 || len(a) < ccParallelMin {
+		return g.ConnectedComponentInts()
+	}
+	p := make([]int32, len(a))
+	for i := range p {
+		p[i] = int32(i)
+	}
+	find := func(n int32) int32 {
+		for {
+			pn := atomic.LoadInt32(&p[n])
+			if pn == n {
+				return n
+			}
+			gp := atomic.LoadInt32(&p[pn])
+			if gp != pn {
+				atomic.CompareAndSwapInt32(&p[n], pn, gp) // path halving
+			}
+			n = gp
+		}
+	}
+	parallel := func(f func(lo, hi int)) { ccParallel(len(a), workers, f) }
+	// hook
+	parallel(func(lo, hi int) {
+		for fr := lo; fr < hi; fr++ {
+			for _, to := range a[fr] {
+				if int(to) <= fr {
+					continue // each edge once, from its lesser node
+				}
+				for {
+					r1, r2 := find(int32(fr)), find(int32(to))
+					if r1 == r2 {
+						break
+					}
+					if r1 < r2 {
+						r1, r2 = r2, r1
+					}
+					if atomic.CompareAndSwapInt32(&p[r1], r1, r2) {
+						break
+					}
+				}
+			}
+		}
+	})
+	// compress
+	parallel(func(lo, hi int) {
+		for n := lo; n < hi; n++ {
+			atomic.StoreInt32(&p[n], find(int32(n)))
+		}
+	})
+	// number components.  roots are lowest nodes, so precede their members.
+	ci = make([]int, len(a))
+	for n, r := range p {
+		if int(r) == n {
+			nc++
+			ci[n] = nc
+		} else {
+			ci[n] = ci[r]
+		}
+	}
+	return
+}
+
+// ccParallel calls f concurrently from the given number of worker goroutines
+// for chunks lo, hi of the range 0 to n, returning when all calls return.
+func ccParallel(n, workers int, f func(lo, hi int)) {
+	var next int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				lo := int(atomic.AddInt64(&next, ccChunk) - ccChunk)
+				if lo >= n {
+					return
+				}
+				hi := lo + ccChunk
+				if hi > n {
+					hi = n
+				}
+				f(lo, hi)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// ConnectedComponents calls emit with the node list of each connected
+// component of g, with calls made concurrently from worker goroutines.
+//
+// Components are found as with ConnectedComponentsParallel, then grouped into
+// node lists and passed to emit by the given number of worker goroutines.
+// Function emit must be safe for concurrent calls.  Calls are made in no
+// particular order, but nodes of each list are in increasing order.  Lists
+// share a single backing array and have capacity limited to their length so
+// that an append by emit copies rather than overwriting another list.  The
+// method returns when all calls of emit have returned.
+//
+// For workers < 2, components are found by the sequential
+// ConnectedComponentLists and emit is called from the calling goroutine, in
+// order of lowest node of each component.
+//
+// The set of component lists, each considered as a set of nodes, is the
+// same regardless of the number of workers.
+func (g Undirected) ConnectedComponents(workers int, emit func(c []NI)) {
+	if workers < 2 {
+		f := g.ConnectedComponentLists()
+		for l, _ := f(); l != nil; l, _ = f() {
+			emit(l)
+		}
+		return
+	}
+	ci, nc := g.ConnectedComponentsParallel(workers)
+	// counting sort of nodes by component number
+	start := make([]int, nc+2)
+	for _, c := range ci {
+		start[c+1]++
+	}
+	for c := 1; c <= nc+1; c++ {
+		start[c] += start[c-1]
+	}
+	nodes := make([]NI, len(ci))
+	fill := append([]int{}, start...)
+	for n, c := range ci {
+		nodes[fill[c]] = NI(n)
+		fill[c]++
+	}
+	ccParallel(nc, workers, func(lo, hi int) {
+		for c := lo + 1; c <= hi; c++ {
+			emit(nodes[start[c]:start[c+1]:start[c+1]])
+		}
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_ConnectedComponentsParallel() {
+	//    0   1   2
+	//   / \   \
+	//  3---4   5
+	var g graph.Undirected
+	g.AddEdge(0, 3)
+	g.AddEdge(0, 4)
+	g.AddEdge(3, 4)
+	g.AddEdge(1, 5)
+	fmt.Println(g.ConnectedComponentsParallel(4))
+	// Output:
+	// [1 2 3 1 1 2] 3
+}
+
+func TestConnectedComponentsParallel(t *testing.T) {
+	r := rand.New(rand.NewSource(12))
+	for i := 0; i < 30; i++ {
+		// orders around and above the sequential cutoff, sparse enough for
+		// many components
+		n := 1000 + r.Intn(20000)
+		g := graph.GnmUndirected(n, r.Intn(n), r)
+		ci0, nc0 := g.ConnectedComponentInts()
+		for _, w := range []int{0, 2, 3, 8} {
+			ci, nc := g.ConnectedComponentsParallel(w)
+			if nc != nc0 || !reflect.DeepEqual(ci, ci0) {
+				t.Fatal("order", n, "workers", w, "components", nc, "want", nc0)
+			}
+		}
+	}
+}
+
+func ExampleUndirected_ConnectedComponents() {
+	//    0   1   2
+	//   / \   \
+	//  3---4   5
+	g := graph.Undirected{make(graph.AdjacencyList, 6)}
+	g.AddEdge(0, 3)
+	g.AddEdge(0, 4)
+	g.AddEdge(3, 4)
+	g.AddEdge(1, 5)
+	// emit is called concurrently, so collect under a lock
+	var mu sync.Mutex
+	graph.SortComponents(func(emit func([]graph.NI) bool) {
+		g.ConnectedComponents(4, func(c []graph.NI) {
+			mu.Lock()
+			emit(c)
+			mu.Unlock()
+		})
+	}, func(c []graph.NI) bool {
+		fmt.Println(c)
+		return true
+	})
+	// Output:
+	// [0 3 4]
+	// [1 5]
+	// [2]
+}
+
+// componentSets returns the components emitted by ConnectedComponents,
+// sorted.
+func componentSets(g graph.Undirected, workers int) (cs [][]graph.NI) {
+	var mu sync.Mutex
+	graph.SortComponents(func(emit func([]graph.NI) bool) {
+		g.ConnectedComponents(workers, func(c []graph.NI) {
+			mu.Lock()
+			emit(c)
+			mu.Unlock()
+		})
+	}, func(c []graph.NI) bool {
+		cs = append(cs, c)
+		return true
+	})
+	return
+}
+
+func TestConnectedComponents(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	for i := 0; i < 20; i++ {
+		n := 1000 + r.Intn(20000)
+		g := graph.GnmUndirected(n, r.Intn(n), r)
+		var want [][]graph.NI
+		graph.SortComponents(func(emit func([]graph.NI) bool) {
+			f := g.ConnectedComponentLists()
+			for l, _ := f(); l != nil; l, _ = f() {
+				emit(l)
+			}
+		}, func(c []graph.NI) bool {
+			want = append(want, c)
+			return true
+		})
+		for _, w := range []int{0, 1, 2, 3, 8} {
+			if got := componentSets(g, w); !reflect.DeepEqual(got, want) {
+				t.Fatal("order", n, "workers", w, "components", len(got),
+					"want", len(want))
+			}
+		}
+	}
+}
+
+func BenchmarkConnectedComponentsParallel(b *testing.B) {
+	// random multigraph, built directly for speed
+	const n, m = 1 << 20, 1 << 22
+	r := rand.New(rand.NewSource(7))
+	var g graph.Undirected
+	g.AdjacencyList = make(graph.AdjacencyList, n)
+	for i := 0; i < m; i++ {
+		g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+	}
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			g.ConnectedComponentInts()
+		}
+	})
+	for _, w := range []int{2, 4, 8} {
+		b.Run(fmt.Sprint("workers", w), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				g.ConnectedComponentsParallel(w)
+			}
+		})
+	}
+}
@@ -0,0 +1,423 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// centrality.go -- betweenness, closeness, and harmonic centrality.
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// BetweennessCentrality computes betweenness centrality of all nodes of g.
+//
+// The betweenness of a node v is the sum over ordered pairs of distinct nodes
+// s and t, both different from v, of the fraction of shortest paths from s
+// to t that pass through v.  Path length is the number of arcs.  The
+// algorithm is that of Brandes, a breadth first search from each node, and
+// runs in O(nm) time for n nodes and m arcs.
+//
+// g is taken as directed.  Loops are ignored.  Parallel arcs count as
+// distinct paths.  For undirected graphs see Undirected.BetweennessCentrality.
+// See also NormalizeBetweenness.
+func (g AdjacencyList) BetweennessCentrality() []float64 {
+	bc, _ := g.brandes(false)
+	return bc
+}
+
+// EdgeBetweenness computes betweenness centrality of all arcs of g.
+//
+// The betweenness of an arc is the sum over ordered pairs of nodes s and t
+// of the fraction of shortest paths from s to t that include the arc.  The
+// result is keyed by arc as Edge{from, to}, and contains only arcs on some
+// shortest path.  Parallel arcs share a single key and their betweenness
+// values are summed.
+//
+// See BetweennessCentrality for more description.
+func (g AdjacencyList) EdgeBetweenness() map[Edge]float64 {
+	_, eb := g.brandes(true)
+	return eb
+}
+
+func (g AdjacencyList) brandes(arcs bool) (bc []float64, eb map[Edge]float64) {
+	n := len(g)
+	bc = make([]float64, n)
+	if arcs {
+		eb = map[Edge]float64{}
+	}
+	dist := make([]int, n)
+	sigma := make([]float64, n)
+	delta := make([]float64, n)
+	pred := make([][]NI, n)
+	var stack, queue []NI
+	for s := range g {
+		for i := range dist {
+			dist[i] = -1
+			sigma[i] = 0
+			delta[i] = 0
+			pred[i] = pred[i][:0]
+		}
+		dist[s] = 0
+		sigma[s] = 1
+		stack = stack[:0]
+		queue = append(queue[:0], NI(s))
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, to := range g[v] {
+				if dist[to] < 0 {
+					dist[to] = dist[v] + 1
+					queue = append(queue, to)
+				}
+				if dist[to] == dist[v]+1 {
+					sigma[to] += sigma[v]
+					pred[to] = append(pred[to], v) // once per parallel arc
+				}
+			}
+		}
+		// accumulate dependencies in order of non-increasing distance
+		for i := len(stack) - 1; i >= 0; i-- {
+			t := stack[i]
+			for _, v := range pred[t] {
+				c := sigma[v] / sigma[t] * (1 + delta[t])
+				delta[v] += c
+				if arcs {
+					eb[Edge{v, t}] += c
+				}
+			}
+			if t != NI(s) {
+				bc[t] += delta[t]
+			}
+		}
+	}
+	return
+}
+
+// BetweennessCentrality computes betweenness centrality of all nodes of g.
+//
+// Each unordered pair of nodes is counted once, so values are half of those
+// computed by AdjacencyList.BetweennessCentrality.
+func (g Undirected) BetweennessCentrality() []float64 {
+	bc := g.AdjacencyList.BetweennessCentrality()
+	for i := range bc {
+		bc[i] /= 2
+	}
+	return bc
+}
+
+// EdgeBetweenness computes betweenness centrality of all edges of g.
+//
+// The result is keyed by edge as Edge{n1, n2} with n1 <= n2.  Each unordered
+// pair of nodes is counted once.
+//
+// See AdjacencyList.EdgeBetweenness for more description.
+func (g Undirected) EdgeBetweenness() map[Edge]float64 {
+	eb := map[Edge]float64{}
+	for e, b := range g.AdjacencyList.EdgeBetweenness() {
+		eb[ordEdge(e.N1, e.N2)] += b / 2
+	}
+	return eb
+}
+
+// BetweennessCentrality computes betweenness centrality of all nodes of g,
+// where path length is the sum of arc weights.
+//
+// The algorithm is that of Brandes, with Dijkstra's algorithm in place of
+// breadth first search.  It runs in O(nm + n² log n) time.  Arc weights must
+// be positive.  Paths have equal length only if the sums of weights are
+// exactly equal.
+//
+// See AdjacencyList.BetweennessCentrality for more description.
+func (g LabeledAdjacencyList) BetweennessCentrality(w WeightFunc) []float64 {
+	bc, _ := g.brandes(w, false)
+	return bc
+}
+
+// EdgeBetweenness computes betweenness centrality of all arcs of g, where
+// path length is the sum of arc weights.
+//
+// The result is keyed by arc as LabeledEdge{Edge{from, to}, label}, and
+// contains only arcs on some shortest path.
+//
+// See LabeledAdjacencyList.BetweennessCentrality and
+// AdjacencyList.EdgeBetweenness for more description.
+func (g LabeledAdjacencyList) EdgeBetweenness(w WeightFunc) map[LabeledEdge]float64 {
+	_, eb := g.brandes(w, true)
+	return eb
+}
+
+func (g LabeledAdjacencyList) brandes(w WeightFunc, arcs bool) (bc []float64, eb map[LabeledEdge]float64) {
+	n := len(g)
+	bc = make([]float64, n)
+	if arcs {
+		eb = map[LabeledEdge]float64{}
+	}
+	r := make([]tentResult, n)
+	sigma := make([]float64, n)
+	delta := make([]float64, n)
+	pred := make([][]Half, n) // To is the from-node here
+	reached := make([]bool, n)
+	var stack []NI
+	for s := range g {
+		for i := range r {
+			r[i] = tentResult{nx: NI(i)}
+			sigma[i] = 0
+			delta[i] = 0
+			pred[i] = pred[i][:0]
+			reached[i] = false
+		}
+		sigma[s] = 1
+		reached[s] = true
+		stack = stack[:0]
+		t := tent{&r[s]}
+		r[s].fx = 0
+		for len(t) > 0 {
+			cr := heap.Pop(&t).(*tentResult)
+			cr.done = true
+			v := cr.nx
+			stack = append(stack, v)
+			for _, to := range g[v] {
+				hr := &r[to.To]
+				if hr.done {
+					continue
+				}
+				d := cr.dist + w(to.Label)
+				switch {
+				case !reached[to.To]:
+					reached[to.To] = true
+					hr.dist = d
+					heap.Push(&t, hr)
+				case d < hr.dist:
+					hr.dist = d
+					heap.Fix(&t, hr.fx)
+				case d > hr.dist:
+					continue
+				default:
+					sigma[to.To] += sigma[v]
+					pred[to.To] = append(pred[to.To], Half{v, to.Label})
+					continue
+				}
+				// new shortest distance
+				sigma[to.To] = sigma[v]
+				pred[to.To] = append(pred[to.To][:0], Half{v, to.Label})
+			}
+		}
+		for i := len(stack) - 1; i >= 0; i-- {
+			x := stack[i]
+			for _, p := range pred[x] {
+				c := sigma[p.To] / sigma[x] * (1 + delta[x])
+				delta[p.To] += c
+				if arcs {
+					eb[LabeledEdge{Edge{p.To, x}, p.Label}] += c
+				}
+			}
+			if x != NI(s) {
+				bc[x] += delta[x]
+			}
+		}
+	}
+	return
+}
+
+// BetweennessCentrality computes betweenness centrality of all nodes of g,
+// where path length is the sum of edge weights.
+//
+// Each unordered pair of nodes is counted once, so values are half of those
+// computed by LabeledAdjacencyList.BetweennessCentrality.
+func (g LabeledUndirected) BetweennessCentrality(w WeightFunc) []float64 {
+	bc := g.LabeledAdjacencyList.BetweennessCentrality(w)
+	for i := range bc {
+		bc[i] /= 2
+	}
+	return bc
+}
+
+// EdgeBetweenness computes betweenness centrality of all edges of g, where
+// path length is the sum of edge weights.
+//
+// The result is keyed by edge as LabeledEdge{Edge{n1, n2}, label} with
+// n1 <= n2.  Each unordered pair of nodes is counted once.
+func (g LabeledUndirected) EdgeBetweenness(w WeightFunc) map[LabeledEdge]float64 {
+	eb := map[LabeledEdge]float64{}
+	for e, b := range g.LabeledAdjacencyList.EdgeBetweenness(w) {
+		eb[LabeledEdge{ordEdge(e.N1, e.N2), e.LI}] += b / 2
+	}
+	return eb
+}
+
+// NormalizeBetweenness scales node betweenness values in place to the range
+// 0 to 1.
+//
+// Values are divided by the number of pairs of nodes not including a given
+// node, (n-1)(n-2) for directed graphs, or (n-1)(n-2)/2 for undirected
+// graphs, where n is len(bc).  Values are unchanged for n < 3.  Argument bc
+// is returned as a convenience.
+func NormalizeBetweenness(bc []float64, undirected bool) []float64 {
+	n := float64(len(bc))
+	if n < 3 {
+		return bc
+	}
+	p := (n - 1) * (n - 2)
+	if undirected {
+		p /= 2
+	}
+	for i := range bc {
+		bc[i] /= p
+	}
+	return bc
+}
+
+// ClosenessCentrality computes closeness centrality of all nodes of g.
+//
+// Distance is the number of arcs in a shortest path from a node.  To handle
+// graphs that are not strongly connected, the closeness of a node is
+// computed over the set of nodes reachable from it and then scaled by the
+// fraction of the graph reached.  That is, for a node reaching r other nodes
+// with a sum of distances s, in a graph of n nodes, closeness is
+// (r/s) * r/(n-1).  A node reaching no other nodes has closeness 0.  For a
+// strongly connected graph this is the conventional (n-1)/s.
+//
+// Distances are from each node.  For closeness by distances to each node,
+// use the transpose of g.
+//
+// A breadth first search is run from every node.  Searches are run
+// concurrently by the given number of worker goroutines, each with its own
+// search state.  Results do not depend on the number of workers.
+//
+// See also HarmonicCentrality.
+func (g AdjacencyList) ClosenessCentrality(workers int) []float64 {
+	c := make([]float64, len(g))
+	g.distanceSums(workers, func(n NI, r int, s, _ float64) {
+		c[n] = closeness(len(g), r, s)
+	})
+	return c
+}
+
+// HarmonicCentrality computes harmonic centrality of all nodes of g.
+//
+// The harmonic centrality of a node is the sum over all other nodes of the
+// reciprocal of the distance from the node.  Unreachable nodes, at infinite
+// distance, contribute 0.  Values are not normalized; divide by n-1 for
+// values in the range 0 to 1 for simple graphs.
+//
+// See ClosenessCentrality for more description.
+func (g AdjacencyList) HarmonicCentrality(workers int) []float64 {
+	h := make([]float64, len(g))
+	g.distanceSums(workers, func(n NI, _ int, _, hs float64) {
+		h[n] = hs
+	})
+	return h
+}
+
+// distanceSums runs a breadth first search from each node of g, calling
+// result with the number of other nodes reached, the sum of distances, and
+// the sum of reciprocal distances.
+//
+// result is called concurrently but for distinct nodes.
+func (g AdjacencyList) distanceSums(workers int, result func(n NI, r int, s, h float64)) {
+	forEachSource(len(g), workers, func() func(NI) {
+		dist := make([]int, len(g))
+		var queue []NI
+		return func(start NI) {
+			for i := range dist {
+				dist[i] = -1
+			}
+			dist[start] = 0
+			queue = append(queue[:0], start)
+			r, s, h := 0, 0., 0.
+			for len(queue) > 0 {
+				v := queue[0]
+				queue = queue[1:]
+				for _, to := range g[v] {
+					if dist[to] < 0 {
+						d := dist[v] + 1
+						dist[to] = d
+						queue = append(queue, to)
+						r++
+						s += float64(d)
+						h += 1 / float64(d)
+					}
+				}
+			}
+			result(start, r, s, h)
+		}
+	})
+}
+
+// ClosenessCentrality computes closeness centrality of all nodes of g,
+// where distance is the sum of arc weights of a shortest path.
+//
+// Dijkstra's algorithm is run from every node, so arc weights must be
+// non-negative.  See AdjacencyList.ClosenessCentrality for the handling of
+// unreachable nodes and for concurrency.
+func (g LabeledAdjacencyList) ClosenessCentrality(w WeightFunc, workers int) []float64 {
+	c := make([]float64, len(g))
+	g.distanceSums(w, workers, func(n NI, r int, s, _ float64) {
+		c[n] = closeness(len(g), r, s)
+	})
+	return c
+}
+
+// HarmonicCentrality computes harmonic centrality of all nodes of g,
+// where distance is the sum of arc weights of a shortest path.
+//
+// Dijkstra's algorithm is run from every node, so arc weights must be
+// non-negative.  Nodes at distance 0 from a node other than itself
+// contribute +Inf.  See AdjacencyList.HarmonicCentrality for more
+// description.
+func (g LabeledAdjacencyList) HarmonicCentrality(w WeightFunc, workers int) []float64 {
+	h := make([]float64, len(g))
+	g.distanceSums(w, workers, func(n NI, _ int, _, hs float64) {
+		h[n] = hs
+	})
+	return h
+}
+
+func (g LabeledAdjacencyList) distanceSums(w WeightFunc, workers int, result func(n NI, r int, s, h float64)) {
+	forEachSource(len(g), workers, func() func(NI) {
+		return func(start NI) {
+			f, _, dist, _ := g.Dijkstra(start, -1, w)
+			r, s, h := 0, 0., 0.
+			for n, p := range f.Paths {
+				if p.Len > 1 {
+					r++
+					s += dist[n]
+					h += 1 / dist[n]
+				}
+			}
+			result(start, r, s, h)
+		}
+	})
+}
+
+// closeness scales closeness of a node in a graph of order n reaching r other
+// nodes with distance sum s.
+func closeness(n, r int, s float64) float64 {
+	if r == 0 {
+		return 0
+	}
+	return float64(r) / s * float64(r) / float64(n-1)
+}
+
+// forEachSource calls a search function for each node from 0 to n-1, using
+// the given number of worker goroutines.  newSearch is called once per worker
+// to allocate a search function with its own state.
+func forEachSource(n, workers int, newSearch func() func(NI)) {
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			search := newSearch()
+			for s := w; s < n; s += workers {
+				search(NI(s))
+			}
+		}(w)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,189 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_BetweennessCentrality() {
+	// 0---1---2---3---4
+	var g graph.Undirected
+	for n := graph.NI(0); n < 4; n++ {
+		g.AddEdge(n, n+1)
+	}
+	bc := g.BetweennessCentrality()
+	fmt.Println(bc)
+	fmt.Println(graph.NormalizeBetweenness(bc, true))
+	// Output:
+	// [0 3 4 3 0]
+	// [0 0.5 0.6666666666666666 0.5 0]
+}
+
+func ExampleUndirected_EdgeBetweenness() {
+	// 0---1---2---3---4
+	var g graph.Undirected
+	for n := graph.NI(0); n < 4; n++ {
+		g.AddEdge(n, n+1)
+	}
+	eb := g.EdgeBetweenness()
+	for n := graph.NI(0); n < 4; n++ {
+		e := graph.Edge{n, n + 1}
+		fmt.Println(e, eb[e])
+	}
+	// Output:
+	// {0 1} 4
+	// {1 2} 6
+	// {2 3} 6
+	// {3 4} 4
+}
+
+func ExampleLabeledAdjacencyList_BetweennessCentrality() {
+	//   (1)     (1)
+	// 0 --> 1 --> 3
+	//  \         ^
+	//   `-> 2 --'
+	//   (1)   (1)
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}, {To: 2, Label: 1}},
+		1: {{To: 3, Label: 1}},
+		2: {{To: 3, Label: 1}},
+		3: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	fmt.Println(g.BetweennessCentrality(w))
+	// make the path through 2 longer
+	g[2][0].Label = 2
+	fmt.Println(g.BetweennessCentrality(w))
+	// Output:
+	// [0 0.5 0.5 0]
+	// [0 1 0 0]
+}
+
+func TestBetweennessCentrality(t *testing.T) {
+	// star, center 0
+	var s graph.Undirected
+	for n := graph.NI(1); n <= 4; n++ {
+		s.AddEdge(0, n)
+	}
+	if bc := s.BetweennessCentrality(); !reflect.DeepEqual(bc,
+		[]float64{6, 0, 0, 0, 0}) {
+		t.Fatal("star:", bc)
+	}
+	// cycles
+	for _, tc := range []struct {
+		n  int
+		bc float64
+	}{{4, .5}, {5, 1}, {6, 2}} {
+		var c graph.Undirected
+		for n := 0; n < tc.n; n++ {
+			c.AddEdge(graph.NI(n), graph.NI((n+1)%tc.n))
+		}
+		for n, b := range c.BetweennessCentrality() {
+			if b != tc.bc {
+				t.Fatal("cycle", tc.n, "node", n, "bc", b, "want", tc.bc)
+			}
+		}
+	}
+	// directed path counts only forward pairs
+	d := graph.AdjacencyList{{1}, {2}, {}}
+	if bc := d.BetweennessCentrality(); !reflect.DeepEqual(bc,
+		[]float64{0, 1, 0}) {
+		t.Fatal("directed path:", bc)
+	}
+	if eb := d.EdgeBetweenness(); !reflect.DeepEqual(eb,
+		map[graph.Edge]float64{{0, 1}: 2, {1, 2}: 2}) {
+		t.Fatal("directed path:", eb)
+	}
+}
+
+func TestWeightedBetweenness(t *testing.T) {
+	// weighted results match unweighted for unit weights
+	var g graph.LabeledUndirected
+	var u graph.Undirected
+	for _, e := range []graph.Edge{{0, 1}, {1, 2}, {2, 3}, {3, 0}, {1, 3},
+		{3, 4}, {4, 5}, {5, 3}} {
+		g.AddEdge(e, 0)
+		u.AddEdge(e.N1, e.N2)
+	}
+	w := func(graph.LI) float64 { return 1 }
+	if bw, bu := g.BetweennessCentrality(w), u.BetweennessCentrality(); !reflect.DeepEqual(bw, bu) {
+		t.Fatal(bw, bu)
+	}
+	ew := g.EdgeBetweenness(w)
+	eu := u.EdgeBetweenness()
+	if len(ew) != len(eu) {
+		t.Fatal(ew, eu)
+	}
+	for e, b := range ew {
+		if eu[e.Edge] != b {
+			t.Fatal(e, b, eu[e.Edge])
+		}
+	}
+}
+
+func ExampleAdjacencyList_ClosenessCentrality() {
+	// 0---1---2---3
+	var g graph.Undirected
+	for n := graph.NI(0); n < 3; n++ {
+		g.AddEdge(n, n+1)
+	}
+	fmt.Println(g.ClosenessCentrality(1))
+	// Output:
+	// [0.5 0.75 0.75 0.5]
+}
+
+func ExampleAdjacencyList_HarmonicCentrality() {
+	// 0-->1-->2   3
+	g := graph.AdjacencyList{{1}, {2}, {}, {}}
+	for n, h := range g.HarmonicCentrality(1) {
+		fmt.Printf("%d %.3f\n", n, h)
+	}
+	// Output:
+	// 0 1.500
+	// 1 1.000
+	// 2 0.000
+	// 3 0.000
+}
+
+func TestClosenessCentrality(t *testing.T) {
+	// partial reachability:  0 reaches only 1
+	g := graph.AdjacencyList{{1}, {}, {0}}
+	c := g.ClosenessCentrality(1)
+	// node 0: r=1, s=1, n=3.  node 2: r=2, s=3.
+	if want := []float64{.5, 0, 2. / 3}; !reflect.DeepEqual(c, want) {
+		t.Fatal(c, "want", want)
+	}
+	// weighted with unit weights matches unweighted, and results do not
+	// depend on the number of workers.
+	u := graph.GnmUndirected(60, 150, rand.New(rand.NewSource(4)))
+	var l graph.LabeledAdjacencyList = make([][]graph.Half, u.Order())
+	for fr, to := range u.AdjacencyList {
+		for _, to := range to {
+			l[fr] = append(l[fr], graph.Half{To: to})
+		}
+	}
+	w := func(graph.LI) float64 { return 1 }
+	cu := u.ClosenessCentrality(1)
+	hu := u.HarmonicCentrality(1)
+	for _, workers := range []int{0, 3, 8} {
+		if c := u.ClosenessCentrality(workers); !reflect.DeepEqual(c, cu) {
+			t.Fatal("closeness, workers", workers)
+		}
+		if c := l.ClosenessCentrality(w, workers); !reflect.DeepEqual(c, cu) {
+			t.Fatal("weighted closeness, workers", workers)
+		}
+		for n, h := range l.HarmonicCentrality(w, workers) {
+			if math.Abs(h-hu[n]) > 1e-12 {
+				t.Fatal("weighted harmonic, workers", workers, "node", n)
+			}
+		}
+	}
+}
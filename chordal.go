@@ -0,0 +1,329 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// chordal.go -- chordal graph recognition and algorithms on perfect
+// elimination orderings.
+
+import (
+	"fmt"
+
+	"github.com/soniakeys/bits"
+)
+
+// NotChordalError is returned by chordal graph methods when a graph is not
+// chordal.
+//
+// Cycle is a chordless cycle of four or more nodes, listed in cycle order
+// without repeating the first node.
+type NotChordalError struct {
+	Cycle []NI
+}
+
+func (e *NotChordalError) Error() string {
+	return fmt.Sprint("graph not chordal, chordless cycle ", e.Cycle)
+}
+
+// MaximumCardinalitySearch returns an ordering of the nodes of g by maximum
+// cardinality search.
+//
+// Nodes are ordered by repeatedly choosing an unordered node with the most
+// ordered neighbors.  Loops and parallel edges are ignored.  If g is chordal,
+// the reverse of the returned ordering is a perfect elimination ordering.
+//
+// Time is O(n+m) for n nodes and m edges.
+func (g Undirected) MaximumCardinalitySearch() []NI {
+	a := g.AdjacencyList
+	ordering := make([]NI, 0, len(a))
+	ordered := bits.New(len(a))
+	weight := make([]int, len(a))
+	// buckets of unordered nodes by weight.  nodes are appended to a new
+	// bucket when weight increases and stale entries are skipped on removal.
+	bucket := [][]NI{make([]NI, len(a))}
+	for n := range a {
+		bucket[0][n] = NI(len(a) - 1 - n) // so node 0 is taken first
+	}
+	last := make([]NI, len(a)) // last node to increment weight, for parallel arcs
+	for n := range last {
+		last[n] = -1
+	}
+	for j := 0; len(ordering) < len(a); {
+		b := bucket[j]
+		if len(b) == 0 {
+			j--
+			continue
+		}
+		n := b[len(b)-1]
+		bucket[j] = b[:len(b)-1]
+		if ordered.Bit(int(n)) == 1 || weight[n] != j {
+			continue
+		}
+		ordering = append(ordering, n)
+		ordered.SetBit(int(n), 1)
+		for _, to := range a[n] {
+			if ordered.Bit(int(to)) == 1 || last[to] == n {
+				continue
+			}
+			last[to] = n
+			weight[to]++
+			w := weight[to]
+			if w == len(bucket) {
+				bucket = append(bucket, nil)
+			}
+			bucket[w] = append(bucket[w], to)
+			if w > j {
+				j = w
+			}
+		}
+	}
+	return ordering
+}
+
+// PerfectEliminationOrdering returns a perfect elimination ordering of g.
+//
+// In a perfect elimination ordering, the neighbors of each node that come
+// later in the ordering form a clique.  A graph has a perfect elimination
+// ordering if and only if it is chordal.  The ordering is found by maximum
+// cardinality search and then verified.  If g is not chordal, a
+// *NotChordalError is returned.
+//
+// Loops and parallel edges are ignored.  Time is O(n+m) for n nodes and
+// m edges.
+func (g Undirected) PerfectEliminationOrdering() ([]NI, error) {
+	peo := g.MaximumCardinalitySearch()
+	for i, j := 0, len(peo)-1; i < j; i, j = i+1, j-1 {
+		peo[i], peo[j] = peo[j], peo[i]
+	}
+	if v, u, w := g.peoViolation(peo); v >= 0 {
+		return nil, &NotChordalError{g.chordlessCycle(v, u, w)}
+	}
+	return peo, nil
+}
+
+// peoViolation checks that peo is a perfect elimination ordering.
+//
+// For each node v, the earliest later neighbor u must be adjacent to all
+// other later neighbors of v.  If this fails, peoViolation returns v, u,
+// and a later neighbor w not adjacent to u.  Otherwise it returns -1s.
+func (g Undirected) peoViolation(peo []NI) (v, u, w NI) {
+	a := g.AdjacencyList
+	pos := make([]int, len(a))
+	for i, n := range peo {
+		pos[n] = i
+	}
+	// for each node u, nodes that must be adjacent to u, and the node v
+	// requiring them.
+	type req struct{ v, w NI }
+	need := make([][]req, len(a))
+	for _, v := range peo {
+		u := NI(-1)
+		for _, to := range a[v] {
+			if pos[to] > pos[v] && (u < 0 || pos[to] < pos[u]) {
+				u = to
+			}
+		}
+		if u < 0 {
+			continue
+		}
+		for _, to := range a[v] {
+			if pos[to] > pos[v] && to != u {
+				need[u] = append(need[u], req{v, to})
+			}
+		}
+	}
+	mark := make([]NI, len(a))
+	for n := range mark {
+		mark[n] = -1
+	}
+	for u, rs := range need {
+		for _, to := range a[u] {
+			mark[to] = NI(u)
+		}
+		for _, r := range rs {
+			if mark[r.w] != NI(u) {
+				return r.v, NI(u), r.w
+			}
+		}
+	}
+	return -1, -1, -1
+}
+
+// chordlessCycle returns a chordless cycle through v, u, and w, where u and w
+// are non-adjacent neighbors of v.
+//
+// The cycle is v followed by a shortest path from u to w avoiding v and
+// other neighbors of v.
+func (g Undirected) chordlessCycle(v, u, w NI) []NI {
+	a := g.AdjacencyList
+	avoid := bits.New(len(a))
+	avoid.SetBit(int(v), 1)
+	for _, to := range a[v] {
+		if to != u && to != w {
+			avoid.SetBit(int(to), 1)
+		}
+	}
+	from := make([]NI, len(a))
+	for n := range from {
+		from[n] = -1
+	}
+	from[u] = u
+	q := []NI{u}
+	for len(q) > 0 && from[w] < 0 {
+		n := q[0]
+		q = q[1:]
+		for _, to := range a[n] {
+			if from[to] < 0 && avoid.Bit(int(to)) == 0 {
+				from[to] = n
+				q = append(q, to)
+			}
+		}
+	}
+	var p []NI
+	for n := w; ; n = from[n] {
+		p = append(p, n)
+		if n == u {
+			break
+		}
+	}
+	c := []NI{v}
+	for i := len(p) - 1; i >= 0; i-- {
+		c = append(c, p[i])
+	}
+	return c
+}
+
+// chordalPEO returns peo if non-nil, or otherwise a verified perfect
+// elimination ordering of g.
+func (g Undirected) chordalPEO(peo []NI) ([]NI, error) {
+	if peo != nil {
+		return peo, nil
+	}
+	return g.PerfectEliminationOrdering()
+}
+
+// ChordalColor colors the nodes of a chordal graph with the minimum number
+// of colors.
+//
+// Nodes are colored greedily in the reverse of a perfect elimination
+// ordering.  The colored neighbors of each node then form a clique and so
+// the number of colors used is the size of a maximum clique.
+//
+// If argument peo is nil, a perfect elimination ordering is computed with
+// PerfectEliminationOrdering, and if g is not chordal a *NotChordalError is
+// returned.  Otherwise peo must be a perfect elimination ordering of g and
+// is not checked.  Loops and parallel edges are ignored.
+//
+// Returned are colors indexed by node, with values from 0 to numColors-1.
+// Time is O(n+m) for n nodes and m edges.
+func (g Undirected) ChordalColor(peo []NI) (colors []int, numColors int, err error) {
+	if peo, err = g.chordalPEO(peo); err != nil {
+		return nil, 0, err
+	}
+	a := g.AdjacencyList
+	colors = make([]int, len(a))
+	for n := range colors {
+		colors[n] = -1
+	}
+	used := make([]NI, len(a)+1) // node marking each color used by a neighbor
+	for c := range used {
+		used[c] = -1
+	}
+	for i := len(peo) - 1; i >= 0; i-- {
+		n := peo[i]
+		for _, to := range a[n] {
+			if c := colors[to]; c >= 0 {
+				used[c] = n
+			}
+		}
+		c := 0
+		for used[c] == n {
+			c++
+		}
+		colors[n] = c
+		if c >= numColors {
+			numColors = c + 1
+		}
+	}
+	return
+}
+
+// ChordalMaxClique finds a maximum clique of a chordal graph.
+//
+// Every maximal clique of a chordal graph is a node together with its
+// neighbors later in a perfect elimination ordering.  The largest of these
+// is returned as a bitmap of nodes.
+//
+// Argument peo and the error result are as for ChordalColor.
+// Time is O(n+m) for n nodes and m edges.
+func (g Undirected) ChordalMaxClique(peo []NI) (bits.Bits, error) {
+	peo, err := g.chordalPEO(peo)
+	if err != nil {
+		return bits.Bits{}, err
+	}
+	a := g.AdjacencyList
+	pos := make([]int, len(a))
+	for i, n := range peo {
+		pos[n] = i
+	}
+	clique := bits.New(len(a))
+	// later collects the later neighbors of a node, without duplicates
+	seen := make([]NI, len(a))
+	for n := range seen {
+		seen[n] = -1
+	}
+	later := func(n NI, emit func(NI)) {
+		for _, to := range a[n] {
+			if pos[to] > pos[n] && seen[to] != n {
+				seen[to] = n
+				emit(to)
+			}
+		}
+	}
+	max, best := 0, NI(-1)
+	for _, n := range peo {
+		c := 1
+		later(n, func(NI) { c++ })
+		if c > max {
+			max, best = c, n
+		}
+	}
+	if best < 0 {
+		return clique, nil
+	}
+	clique.SetBit(int(best), 1)
+	for n := range seen {
+		seen[n] = -1
+	}
+	later(best, func(to NI) { clique.SetBit(int(to), 1) })
+	return clique, nil
+}
+
+// ChordalMaximumIndependentSet finds a maximum independent set of a chordal
+// graph.
+//
+// Nodes are taken greedily in perfect elimination ordering, each node being
+// added to the set if none of its neighbors are already in the set.
+// The result is returned as a bitmap of nodes.
+//
+// Argument peo and the error result are as for ChordalColor.
+// Time is O(n+m) for n nodes and m edges.
+func (g Undirected) ChordalMaximumIndependentSet(peo []NI) (bits.Bits, error) {
+	peo, err := g.chordalPEO(peo)
+	if err != nil {
+		return bits.Bits{}, err
+	}
+	a := g.AdjacencyList
+	set := bits.New(len(a))
+	blocked := bits.New(len(a))
+	for _, n := range peo {
+		if blocked.Bit(int(n)) == 1 {
+			continue
+		}
+		set.SetBit(int(n), 1)
+		for _, to := range a[n] {
+			blocked.SetBit(int(to), 1)
+		}
+	}
+	return set, nil
+}
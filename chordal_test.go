@@ -0,0 +1,254 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/bits"
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/graphtest"
+)
+
+func ExampleUndirected_ChordalColor() {
+	// 0---1
+	// | \ |
+	// 3---2---4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 0)
+	g.AddEdge(0, 2)
+	g.AddEdge(2, 4)
+	colors, n, err := g.ChordalColor(nil)
+	fmt.Println(colors, n, err)
+	c, _ := g.ChordalMaxClique(nil)
+	fmt.Println("clique:", c.Slice())
+	s, _ := g.ChordalMaximumIndependentSet(nil)
+	fmt.Println("independent:", s.Slice())
+	// Output:
+	// [0 2 1 2 0] 3 <nil>
+	// clique: [0 1 2]
+	// independent: [1 3 4]
+}
+
+func ExampleNotChordalError() {
+	// 0---1---4
+	// |   |
+	// 3---2
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 0)
+	g.AddEdge(1, 4)
+	_, err := g.PerfectEliminationOrdering()
+	fmt.Println(err)
+	// Output:
+	// graph not chordal, chordless cycle [1 2 3 0]
+}
+
+// intervalGraph returns the interval graph of n random intervals.
+func intervalGraph(n int, r *rand.Rand) graph.Undirected {
+	lo := make([]float64, n)
+	hi := make([]float64, n)
+	for i := range lo {
+		lo[i] = r.Float64()
+		hi[i] = lo[i] + r.Float64()*.3
+	}
+	g := graph.Undirected{make(graph.AdjacencyList, n)}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if lo[i] <= hi[j] && lo[j] <= hi[i] {
+				g.AddEdge(graph.NI(i), graph.NI(j))
+			}
+		}
+	}
+	return g
+}
+
+// bruteCliqueMIS returns the sizes of a maximum clique and a maximum
+// independent set by trying all subsets.
+func bruteCliqueMIS(g graph.Undirected) (clique, mis int) {
+	n := g.Order()
+	adj := make([]uint, n)
+	for fr, to := range g.AdjacencyList {
+		for _, to := range to {
+			adj[fr] |= 1 << uint(to)
+		}
+	}
+	for s := uint(1); s < 1<<uint(n); s++ {
+		isC, isI := true, true
+		c := 0
+		for v := 0; v < n; v++ {
+			if s&(1<<uint(v)) == 0 {
+				continue
+			}
+			c++
+			others := s &^ (1 << uint(v))
+			if adj[v]&others != others {
+				isC = false
+			}
+			if adj[v]&others != 0 {
+				isI = false
+			}
+		}
+		if isC && c > clique {
+			clique = c
+		}
+		if isI && c > mis {
+			mis = c
+		}
+	}
+	return
+}
+
+func TestChordalIntervals(t *testing.T) {
+	r := rand.New(rand.NewSource(34))
+	for i := 0; i < 200; i++ {
+		g := intervalGraph(1+r.Intn(12), r)
+		peo, err := g.PerfectEliminationOrdering()
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		wc, wm := bruteCliqueMIS(g)
+		for _, p := range [][]graph.NI{nil, peo} {
+			colors, nc, err := g.ChordalColor(p)
+			if err != nil {
+				t.Fatal(i, err)
+			}
+			if err := graphtest.CheckProperColoring(g, colors); err != nil {
+				t.Fatal(i, err)
+			}
+			// interval graphs are perfect, so chromatic number = clique number
+			if nc != wc {
+				t.Fatal(i, "colors", nc, "want", wc)
+			}
+			c, err := g.ChordalMaxClique(p)
+			if err != nil {
+				t.Fatal(i, err)
+			}
+			if c.OnesCount() != wc || !isClique(g, c) {
+				t.Fatal(i, "clique", c.Slice(), "want size", wc)
+			}
+			s, err := g.ChordalMaximumIndependentSet(p)
+			if err != nil {
+				t.Fatal(i, err)
+			}
+			if s.OnesCount() != wm || !isIndependent(g, s) {
+				t.Fatal(i, "independent set", s.Slice(), "want size", wm)
+			}
+		}
+	}
+}
+
+func isClique(g graph.Undirected, b bits.Bits) bool {
+	ok := true
+	b.IterateOnes(func(n1 int) bool {
+		b.IterateOnes(func(n2 int) bool {
+			if n1 != n2 {
+				if e, _, _ := g.HasEdge(graph.NI(n1), graph.NI(n2)); !e {
+					ok = false
+				}
+			}
+			return ok
+		})
+		return ok
+	})
+	return ok
+}
+
+func isIndependent(g graph.Undirected, b bits.Bits) bool {
+	ok := true
+	b.IterateOnes(func(n int) bool {
+		for _, to := range g.AdjacencyList[n] {
+			if b.Bit(int(to)) == 1 {
+				ok = false
+			}
+		}
+		return ok
+	})
+	return ok
+}
+
+func TestChordalC4(t *testing.T) {
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 0)
+	if _, _, err := g.ChordalColor(nil); err == nil {
+		t.Fatal("ChordalColor: no error")
+	}
+	if _, err := g.ChordalMaxClique(nil); err == nil {
+		t.Fatal("ChordalMaxClique: no error")
+	}
+	_, err := g.ChordalMaximumIndependentSet(nil)
+	e, ok := err.(*graph.NotChordalError)
+	if !ok {
+		t.Fatal("ChordalMaximumIndependentSet:", err)
+	}
+	checkChordless(t, g, e.Cycle)
+	if len(e.Cycle) != 4 {
+		t.Fatal(e.Cycle)
+	}
+}
+
+// checkChordless fails t unless c is a chordless cycle of g of length 4 or
+// more.
+func checkChordless(t *testing.T, g graph.Undirected, c []graph.NI) {
+	t.Helper()
+	if len(c) < 4 {
+		t.Fatal("cycle too short", c)
+	}
+	for i, n1 := range c {
+		for j := i + 1; j < len(c); j++ {
+			n2 := c[j]
+			if n1 == n2 {
+				t.Fatal("repeated node", c)
+			}
+			e, _, _ := g.HasEdge(n1, n2)
+			if adj := j == i+1 || i == 0 && j == len(c)-1; e != adj {
+				t.Fatal("not a chordless cycle", c, n1, n2)
+			}
+		}
+	}
+}
+
+func TestPerfectEliminationOrderingWitness(t *testing.T) {
+	r := rand.New(rand.NewSource(35))
+	nChordal := 0
+	for i := 0; i < 300; i++ {
+		n := 4 + r.Intn(12)
+		g := graph.GnmUndirected(n, r.Intn(n*(n-1)/2+1), r)
+		peo, err := g.PerfectEliminationOrdering()
+		if err != nil {
+			checkChordless(t, g, err.(*graph.NotChordalError).Cycle)
+			continue
+		}
+		nChordal++
+		// later neighbors of each node must form a clique
+		pos := make([]int, n)
+		for x, v := range peo {
+			pos[v] = x
+		}
+		for _, v := range peo {
+			later := bits.New(n)
+			for _, to := range g.AdjacencyList[v] {
+				if pos[to] > pos[v] {
+					later.SetBit(int(to), 1)
+				}
+			}
+			if !isClique(g, later) {
+				t.Fatal(i, "not a perfect elimination ordering", peo)
+			}
+		}
+	}
+	if nChordal == 0 || nChordal == 300 {
+		t.Fatal("chordal graphs:", nChordal)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// cluster.go -- single linkage clustering from minimum spanning trees.
+
+import "sort"
+
+// Merge is a step of a single linkage dendrogram.
+//
+// Clusters A and B are merged at Height into a new cluster of Size nodes.
+// Cluster ids follow the convention of SciPy linkage matrices:  For a graph
+// of order n, ids 0 through n-1 are singleton clusters of the corresponding
+// nodes, and the cluster formed by the i-th merge has id n+i.  A is always
+// less than B.
+type Merge struct {
+	A, B   int
+	Height float64
+	Size   int
+}
+
+// Linkage computes the single linkage dendrogram of g.
+//
+// Edge weights are taken as distances.  Merges are the edges of a minimum
+// spanning forest, processed in order of increasing weight, with ties
+// broken in the order edges are visited by Edges.  For a connected graph of
+// order n, n-1 merges are returned.  For a graph with nc connected
+// components, n-nc merges are returned and the components remain separate.
+//
+// Since the minimum spanning forest of a forest is itself, a previously
+// computed spanning forest can be passed as g to avoid computing it again.
+func (g LabeledUndirected) Linkage(w WeightFunc) []Merge {
+	n := len(g.LabeledAdjacencyList)
+	id := make([]int, n)   // cluster id by root node
+	size := make([]int, n) // cluster size by root node
+	for i := range id {
+		id[i] = i
+		size[i] = 1
+	}
+	var m []Merge
+	ds := newDisjointSet(n)
+	for _, e := range g.sortedEdges(w) {
+		r1, r2 := ds.find(e.N1), ds.find(e.N2)
+		if r1 == r2 {
+			continue
+		}
+		a, b := id[r1], id[r2]
+		if a > b {
+			a, b = b, a
+		}
+		s := size[r1] + size[r2]
+		m = append(m, Merge{a, b, w(e.LI), s})
+		ds.union(r1, r2)
+		r := ds.find(r1)
+		id[r] = n + len(m) - 1
+		size[r] = s
+	}
+	return m
+}
+
+// MinimumBottleneckSpanningTree returns a spanning forest of g minimizing
+// the maximum edge weight.
+//
+// Every minimum spanning forest is a minimum bottleneck spanning forest and
+// the one returned is that computed by Kruskal.  Also returned is the
+// bottleneck, the maximum edge weight of the forest, or 0 if the forest has
+// no edges.
+func (g LabeledUndirected) MinimumBottleneckSpanningTree(w WeightFunc) (t LabeledUndirected, bottleneck float64) {
+	t, _ = g.Kruskal(w)
+	t.Edges(func(e LabeledEdge) {
+		if wt := w(e.LI); wt > bottleneck {
+			bottleneck = wt
+		}
+	})
+	return
+}
+
+// MSTClusters partitions the nodes of g into k clusters by single linkage.
+//
+// Edge weights are taken as distances.  The clusters are the components of
+// a minimum spanning forest after removing its k-1 heaviest edges, or
+// equivalently, the clusters after the first n-k merges of Linkage for a
+// graph of order n.  Return value ci contains a cluster number for each node.
+// Cluster numbers run from 1 to the number of clusters and are assigned in
+// order of the lowest node of each cluster, as with ConnectedComponentInts.
+// Return value mergeHeights contains the heights of the merges performed, in
+// increasing order.
+//
+// A graph with nc connected components cannot be clustered into fewer than
+// nc clusters.  If k < nc, the nc components are returned as clusters.
+func (g LabeledUndirected) MSTClusters(w WeightFunc, k int) (ci []int, mergeHeights []float64) {
+	n := len(g.LabeledAdjacencyList)
+	ds := newDisjointSet(n)
+	nc := n
+	for _, e := range g.sortedEdges(w) {
+		if nc <= k {
+			break
+		}
+		if ds.union(e.N1, e.N2) {
+			mergeHeights = append(mergeHeights, w(e.LI))
+			nc--
+		}
+	}
+	ci = make([]int, n)
+	num := map[NI]int{}
+	for i := range ci {
+		r := ds.find(NI(i))
+		c, ok := num[r]
+		if !ok {
+			c = len(num) + 1
+			num[r] = c
+		}
+		ci[i] = c
+	}
+	return
+}
+
+// sortedEdges returns the edges of g stably sorted by weight.
+func (g LabeledUndirected) sortedEdges(w WeightFunc) []LabeledEdge {
+	var e []LabeledEdge
+	g.Edges(func(l LabeledEdge) { e = append(e, l) })
+	sort.SliceStable(e, func(i, j int) bool { return w(e[i].LI) < w(e[j].LI) })
+	return e
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+// complete graph on points of a line, with distances as weights
+func linePoints(x []float64) (graph.LabeledUndirected, graph.WeightFunc) {
+	var g graph.LabeledUndirected
+	var d []float64
+	for i := range x {
+		for j := range x[:i] {
+			g.AddEdge(graph.Edge{graph.NI(j), graph.NI(i)}, graph.LI(len(d)))
+			d = append(d, math.Abs(x[i]-x[j]))
+		}
+	}
+	return g, func(l graph.LI) float64 { return d[l] }
+}
+
+func ExampleLabeledUndirected_Linkage() {
+	//  0 1 2         3 4                   5
+	//  |-|-|---------|-|-------------------|
+	g, w := linePoints([]float64{0, 1, 2.5, 10, 11, 30})
+	for _, m := range g.Linkage(w) {
+		fmt.Printf("%d %d %4.1f %d\n", m.A, m.B, m.Height, m.Size)
+	}
+	// Output:
+	// 0 1  1.0 2
+	// 3 4  1.0 2
+	// 2 6  1.5 3
+	// 7 8  7.5 5
+	// 5 9 19.0 6
+}
+
+func ExampleLabeledUndirected_MSTClusters() {
+	//  0 1 2         3 4                   5
+	//  |-|-|---------|-|-------------------|
+	g, w := linePoints([]float64{0, 1, 2.5, 10, 11, 30})
+	ci, h := g.MSTClusters(w, 3)
+	fmt.Println(ci)
+	fmt.Println(h)
+	// Output:
+	// [1 1 1 2 2 3]
+	// [1 1 1.5]
+}
+
+func ExampleLabeledUndirected_MinimumBottleneckSpanningTree() {
+	//  0 1 2         3 4                   5
+	//  |-|-|---------|-|-------------------|
+	g, w := linePoints([]float64{0, 1, 2.5, 10, 11, 30})
+	t, b := g.MinimumBottleneckSpanningTree(w)
+	fmt.Println(t.Size(), "edges, bottleneck", b)
+	// Output:
+	// 5 edges, bottleneck 19
+}
+
+func TestMSTClustersExtremes(t *testing.T) {
+	g, w := linePoints([]float64{3, 1, 4, 1.5, 9, 2.6, 5})
+	n := g.Order()
+	ci, h := g.MSTClusters(w, 1)
+	for _, c := range ci {
+		if c != 1 {
+			t.Fatal("k=1:", ci)
+		}
+	}
+	l := g.Linkage(w)
+	if len(h) != n-1 || len(l) != n-1 {
+		t.Fatal("k=1 merges", len(h), "linkage", len(l))
+	}
+	for i, m := range l {
+		if m.Height != h[i] {
+			t.Fatal("merge", i, "height", m.Height, "want", h[i])
+		}
+	}
+	if l[n-2].Size != n || l[n-2].B != 2*n-3 {
+		t.Fatal("last merge", l[n-2])
+	}
+	ci, h = g.MSTClusters(w, n)
+	if len(h) != 0 {
+		t.Fatal("k=n merges", h)
+	}
+	for i, c := range ci {
+		if c != i+1 {
+			t.Fatal("k=n:", ci)
+		}
+	}
+	// disconnected:  two components can't make one cluster
+	var d graph.LabeledUndirected
+	d.AddEdge(graph.Edge{0, 1}, 0)
+	d.AddEdge(graph.Edge{2, 3}, 0)
+	one := func(graph.LI) float64 { return 1 }
+	if ci, _ := d.MSTClusters(one, 1); ci[0] != 1 || ci[1] != 1 ||
+		ci[2] != 2 || ci[3] != 2 {
+		t.Fatal("disconnected:", ci)
+	}
+	if l := d.Linkage(one); len(l) != 2 {
+		t.Fatal("disconnected linkage", l)
+	}
+}
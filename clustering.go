@@ -0,0 +1,126 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// clustering.go -- triangle counting and clustering coefficients.
+
+import "sort"
+
+// Triangles counts triangles of g.
+//
+// A triangle is a set of three distinct nodes with an edge between each
+// pair.  Loops are ignored and parallel edges count as a single edge, so
+// that results are those of the simple graph with the same adjacencies.
+//
+// Returned is the total number of triangles and the number of triangles
+// each node is part of.
+//
+// The algorithm is the forward algorithm:  Edges are oriented from nodes of
+// lower degree to nodes of higher degree, ties broken by NI, and for each
+// oriented edge the out-neighbors of its two nodes are intersected.  Time
+// is O(m^1.5) for m edges.
+func (g Undirected) Triangles() (total int, perNode []int) {
+	a := g.AdjacencyList
+	nb, _ := simpleNeighbors(a)
+	// rank nodes by degree
+	ord := make([]NI, len(a))
+	for i := range ord {
+		ord[i] = NI(i)
+	}
+	sort.Slice(ord, func(i, j int) bool {
+		di, dj := len(nb[ord[i]]), len(nb[ord[j]])
+		return di < dj || di == dj && ord[i] < ord[j]
+	})
+	rank := make([]int, len(a))
+	for r, n := range ord {
+		rank[n] = r
+	}
+	// orient edges to higher rank
+	out := make(AdjacencyList, len(a))
+	for n, to := range nb {
+		for _, to := range to {
+			if rank[to] > rank[n] {
+				out[n] = append(out[n], to)
+			}
+		}
+	}
+	perNode = make([]int, len(a))
+	mark := make([]bool, len(a))
+	for u, ou := range out {
+		for _, v := range ou {
+			mark[v] = true
+		}
+		for _, v := range ou {
+			for _, w := range out[v] {
+				if mark[w] {
+					total++
+					perNode[u]++
+					perNode[v]++
+					perNode[w]++
+				}
+			}
+		}
+		for _, v := range ou {
+			mark[v] = false
+		}
+	}
+	return
+}
+
+// ClusteringCoefficients computes the local clustering coefficient of each
+// node of g.
+//
+// The local clustering coefficient of a node is the number of edges between
+// its neighbors divided by the number of pairs of neighbors, that is, the
+// number of triangles at the node divided by d(d-1)/2 for a node of degree
+// d.  It is 0 for nodes with fewer than two neighbors.  As for Triangles,
+// loops are ignored and parallel edges count as a single edge.
+func (g Undirected) ClusteringCoefficients() []float64 {
+	_, t := g.Triangles()
+	_, deg := simpleNeighbors(g.AdjacencyList)
+	c := make([]float64, len(t))
+	for n, d := range deg {
+		if d > 1 {
+			c[n] = float64(2*t[n]) / float64(d*(d-1))
+		}
+	}
+	return c
+}
+
+// Transitivity computes the global clustering coefficient of g.
+//
+// Transitivity is three times the number of triangles divided by the number
+// of connected triples, paths of two edges.  It is 0 if there are no
+// connected triples.  As for Triangles, loops are ignored and parallel edges
+// count as a single edge.
+func (g Undirected) Transitivity() float64 {
+	total, _ := g.Triangles()
+	_, deg := simpleNeighbors(g.AdjacencyList)
+	triples := 0
+	for _, d := range deg {
+		triples += d * (d - 1) / 2
+	}
+	if triples == 0 {
+		return 0
+	}
+	return float64(3*total) / float64(triples)
+}
+
+// simpleNeighbors returns the distinct neighbors of each node of a,
+// excluding the node itself, and their number.
+func simpleNeighbors(a AdjacencyList) (nb AdjacencyList, deg []int) {
+	nb = make(AdjacencyList, len(a))
+	deg = make([]int, len(a))
+	last := make([]NI, len(a)) // node most recently listing each node, +1
+	for n, to := range a {
+		for _, to := range to {
+			if to != NI(n) && last[to] != NI(n)+1 {
+				last[to] = NI(n) + 1
+				nb[n] = append(nb[n], to)
+			}
+		}
+		deg[n] = len(nb[n])
+	}
+	return
+}
@@ -0,0 +1,137 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_Triangles() {
+	//   0
+	//  / \
+	// 1---2---3
+	//      \ /
+	//       4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(3, 4)
+	fmt.Println(g.Triangles())
+	// Output:
+	// 2 [1 1 2 1 1]
+}
+
+func ExampleUndirected_ClusteringCoefficients() {
+	//   0
+	//  / \
+	// 1---2---3
+	//      \ /
+	//       4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(3, 4)
+	for n, c := range g.ClusteringCoefficients() {
+		fmt.Printf("%d  %.3f\n", n, c)
+	}
+	fmt.Printf("transitivity %.3f\n", g.Transitivity())
+	// Output:
+	// 0  1.000
+	// 1  1.000
+	// 2  0.333
+	// 3  1.000
+	// 4  1.000
+	// transitivity 0.600
+}
+
+// TestTriangles checks Triangles, ClusteringCoefficients, and Transitivity
+// against brute force on random graphs with loops and parallel edges.
+func TestTriangles(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 30; i++ {
+		g := graph.GnmUndirected(25, 25+r.Intn(100), r)
+		for j := 0; j < 5; j++ {
+			n := graph.NI(r.Intn(25))
+			g.AddEdge(n, n)
+			to := g.AdjacencyList[n]
+			if len(to) > 0 {
+				g.AddEdge(n, to[r.Intn(len(to))])
+			}
+		}
+		adj := func(a, b int) bool {
+			for _, to := range g.AdjacencyList[a] {
+				if to == graph.NI(b) {
+					return true
+				}
+			}
+			return false
+		}
+		n := g.Order()
+		bt := 0
+		bp := make([]int, n)
+		for a := 0; a < n; a++ {
+			for b := a + 1; b < n; b++ {
+				for c := b + 1; c < n; c++ {
+					if adj(a, b) && adj(b, c) && adj(a, c) {
+						bt++
+						bp[a]++
+						bp[b]++
+						bp[c]++
+					}
+				}
+			}
+		}
+		total, perNode := g.Triangles()
+		if total != bt {
+			t.Fatal(i, "total", total, "brute force", bt)
+		}
+		cc := g.ClusteringCoefficients()
+		triples := 0
+		for a := 0; a < n; a++ {
+			if perNode[a] != bp[a] {
+				t.Fatal(i, "node", a, perNode[a], "brute force", bp[a])
+			}
+			d := 0
+			for b := 0; b < n; b++ {
+				if b != a && adj(a, b) {
+					d++
+				}
+			}
+			triples += d * (d - 1) / 2
+			want := 0.
+			if d > 1 {
+				want = float64(bp[a]) / float64(d*(d-1)/2)
+			}
+			if math.Abs(cc[a]-want) > 1e-12 {
+				t.Fatal(i, "node", a, "coefficient", cc[a], "want", want)
+			}
+		}
+		want := 0.
+		if triples > 0 {
+			want = float64(3*bt) / float64(triples)
+		}
+		if tr := g.Transitivity(); math.Abs(tr-want) > 1e-12 {
+			t.Fatal(i, "transitivity", tr, "want", want)
+		}
+	}
+}
+
+func BenchmarkTriangles(b *testing.B) {
+	g, _ := graph.GnpUndirected(1000, .2, rand.New(rand.NewSource(3)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Triangles()
+	}
+}
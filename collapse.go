@@ -0,0 +1,191 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// collapse.go -- collapsing parallel arcs into weighted arcs.
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CollapseToWeighted collapses parallel arcs of g, returning a simple
+// labeled graph and arc counts.
+//
+// Each set of parallel arcs from a node fr to a node to is replaced with a
+// single arc with label l where counts[l] is the number of arcs collapsed.
+// Arcs in the result are in order of first appearance in g.  Loops are
+// preserved and collapsed the same way.
+//
+// See also LabeledDirected.CollapseToWeighted.
+func (g Directed) CollapseToWeighted() (c LabeledDirected, counts []float64) {
+	ca, counts := collapseArcs(g.AdjacencyList, false, func(fr int, x []int) float64 {
+		return float64(len(x))
+	})
+	return LabeledDirected{ca}, counts
+}
+
+// CollapseToWeighted collapses parallel arcs of g, returning a simple
+// labeled graph and combined weights.
+//
+// Each set of parallel arcs from a node fr to a node to is replaced with a
+// single arc with label l where weights[l] is the combined weight of the
+// arcs collapsed.  The weights of the arcs, given by w, are combined in arc
+// list order by repeated calls of combine, with the weight of the first arc
+// as the initial value.  A nil combine sums the weights.  Arcs in the result
+// are in order of first appearance in g.  Loops are preserved and collapsed
+// the same way.
+func (g LabeledDirected) CollapseToWeighted(w WeightFunc, combine func(a, b float64) float64) (c LabeledDirected, weights []float64) {
+	ca, weights := collapseLabeled(g.LabeledAdjacencyList, w, combine, false)
+	return LabeledDirected{ca}, weights
+}
+
+// CollapseToWeighted collapses parallel edges of g, returning a simple
+// labeled graph and edge counts.
+//
+// Each set of parallel edges between a pair of nodes is replaced with a
+// single edge with label l where counts[l] is the number of edges collapsed.
+// Both half arcs of the edge get the same label.  Loops are preserved and
+// collapsed the same way.  To-lists in the result are in order of first
+// appearance in g.
+//
+// A non-nil error is returned if g is not a valid undirected graph, as
+// indicated by a number of arcs from a node n1 to a node n2 different from
+// the number of arcs from n2 to n1.
+//
+// See also LabeledUndirected.CollapseToWeighted.
+func (g Undirected) CollapseToWeighted() (c LabeledUndirected, counts []float64, err error) {
+	a := g.AdjacencyList
+	if err = checkReciprocal(func(f func(fr, to NI, l LI)) {
+		for fr, to := range a {
+			for _, to := range to {
+				f(NI(fr), to, 0)
+			}
+		}
+	}); err != nil {
+		return
+	}
+	ca, counts := collapseArcs(a, true, func(fr int, x []int) float64 {
+		return float64(len(x))
+	})
+	return LabeledUndirected{ca}, counts, nil
+}
+
+// CollapseToWeighted collapses parallel edges of g, returning a simple
+// labeled graph and combined weights.
+//
+// Each set of parallel edges between a pair of nodes is replaced with a
+// single edge with label l where weights[l] is the combined weight of the
+// edges collapsed.  Both half arcs of the edge get the same label.  Weights
+// given by w are combined as described for LabeledDirected.CollapseToWeighted,
+// in the arc list order of the lesser numbered node of the edge.
+//
+// A non-nil error is returned if g is not a valid undirected graph, as
+// indicated by labels of arcs from a node n1 to a node n2 differing as
+// a multiset from labels of arcs from n2 to n1.
+func (g LabeledUndirected) CollapseToWeighted(w WeightFunc, combine func(a, b float64) float64) (c LabeledUndirected, weights []float64, err error) {
+	a := g.LabeledAdjacencyList
+	if err = checkReciprocal(func(f func(fr, to NI, l LI)) {
+		for fr, to := range a {
+			for _, to := range to {
+				f(NI(fr), to.To, to.Label)
+			}
+		}
+	}); err != nil {
+		return
+	}
+	ca, weights := collapseLabeled(a, w, combine, true)
+	return LabeledUndirected{ca}, weights, nil
+}
+
+// checkReciprocal checks that the arcs visited by each have reciprocal arcs
+// with the same labels.
+func checkReciprocal(each func(func(fr, to NI, l LI))) error {
+	lab := map[Edge][]LI{}
+	each(func(fr, to NI, l LI) {
+		if fr != to {
+			e := Edge{fr, to}
+			lab[e] = append(lab[e], l)
+		}
+	})
+	for _, l := range lab {
+		sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+	}
+	var err error
+	each(func(fr, to NI, _ LI) {
+		if err != nil || fr == to {
+			return
+		}
+		l1, l2 := lab[Edge{fr, to}], lab[Edge{to, fr}]
+		if len(l1) != len(l2) {
+			err = fmt.Errorf("%d arcs %d->%d, %d arcs %d->%d",
+				len(l1), fr, to, len(l2), to, fr)
+			return
+		}
+		for i, l := range l1 {
+			if l2[i] != l {
+				err = fmt.Errorf("arcs %d->%d and %d->%d have different labels",
+					fr, to, to, fr)
+				return
+			}
+		}
+	})
+	return err
+}
+
+func collapseLabeled(a LabeledAdjacencyList, w WeightFunc, combine func(a, b float64) float64, reciprocal bool) (LabeledAdjacencyList, []float64) {
+	if combine == nil {
+		combine = func(a, b float64) float64 { return a + b }
+	}
+	u := make(AdjacencyList, len(a))
+	for fr, to := range a {
+		u[fr] = make([]NI, len(to))
+		for i, to := range to {
+			u[fr][i] = to.To
+		}
+	}
+	return collapseArcs(u, reciprocal, func(fr int, x []int) float64 {
+		wt := w(a[fr][x[0]].Label)
+		for _, x := range x[1:] {
+			wt = combine(wt, w(a[fr][x].Label))
+		}
+		return wt
+	})
+}
+
+// collapseArcs collapses parallel arcs of a.
+//
+// Function weight is called once for each set of parallel arcs, with the
+// from-node and the indexes into a[fr] of the arcs.  The returned weight is
+// appended to the weight list and the index of the weight is the label of
+// the collapsed arc.  If reciprocal is true, the reciprocal arc gets the same
+// label and weight is called only for the arcs from the lesser node.
+func collapseArcs(a AdjacencyList, reciprocal bool, weight func(fr int, x []int) float64) (LabeledAdjacencyList, []float64) {
+	c := make(LabeledAdjacencyList, len(a))
+	var weights []float64
+	label := map[Edge]LI{}
+	for fr, to := range a {
+		// arc indexes by to-node, in order of first appearance
+		var ord []NI
+		ix := map[NI][]int{}
+		for i, to := range to {
+			if _, ok := ix[to]; !ok {
+				ord = append(ord, to)
+			}
+			ix[to] = append(ix[to], i)
+		}
+		for _, to := range ord {
+			l, ok := label[Edge{NI(fr), to}]
+			if !ok {
+				l = LI(len(weights))
+				weights = append(weights, weight(fr, ix[to]))
+				if reciprocal {
+					label[Edge{to, NI(fr)}] = l
+				}
+			}
+			c[fr] = append(c[fr], Half{to, l})
+		}
+	}
+	return c, weights
+}
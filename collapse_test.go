@@ -0,0 +1,151 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDirected_CollapseToWeighted() {
+	//   0 ==> 1
+	//   ^     |
+	//   '-----'
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 1},
+		1: {0, 1},
+	}}
+	c, counts := g.CollapseToWeighted()
+	for n, to := range c.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("counts:", counts)
+	// Output:
+	// 0 [{1 0}]
+	// 1 [{0 1} {1 2}]
+	// counts: [2 1 1]
+}
+
+func ExampleUndirected_CollapseToWeighted() {
+	//   0 === 1 --- 2 (loop on 2)
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(0, 1)
+	g.AddEdge(2, 2)
+	g.AddEdge(2, 2)
+	c, counts, err := g.CollapseToWeighted()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for n, to := range c.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("counts:", counts)
+	// Output:
+	// 0 [{1 0}]
+	// 1 [{0 0} {2 1}]
+	// 2 [{1 1} {2 2}]
+	// counts: [2 1 2]
+}
+
+func ExampleLabeledUndirected_CollapseToWeighted() {
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 0)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{0, 1}, 2)
+	weights := []float64{3, 5, 4}
+	c, cw, err := g.CollapseToWeighted(func(l graph.LI) float64 {
+		return weights[l]
+	}, func(a, b float64) float64 {
+		if b < a {
+			return b
+		}
+		return a
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for n, to := range c.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("weights:", cw)
+	// Output:
+	// 0 [{1 0}]
+	// 1 [{0 0} {2 1}]
+	// 2 [{1 1}]
+	// weights: [3 5]
+}
+
+func TestCollapseToWeightedAsymmetric(t *testing.T) {
+	// two arcs 0->1 but only one 1->0
+	g := graph.Undirected{graph.AdjacencyList{
+		0: {1, 1},
+		1: {0},
+	}}
+	if _, _, err := g.CollapseToWeighted(); err == nil {
+		t.Fatal("asymmetric multiplicities not detected")
+	}
+	// labels of reciprocal arcs differ
+	lg := graph.LabeledUndirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}, {To: 1, Label: 1}},
+		1: {{To: 0, Label: 0}, {To: 0, Label: 2}},
+	}}
+	w := func(l graph.LI) float64 { return 1 }
+	if _, _, err := lg.CollapseToWeighted(w, nil); err == nil {
+		t.Fatal("mismatched labels not detected")
+	}
+}
+
+func TestCollapseToWeightedRoundTrip(t *testing.T) {
+	var g graph.LabeledUndirected
+	var weights []float64
+	add := func(n1, n2 graph.NI, wt float64) {
+		g.AddEdge(graph.Edge{n1, n2}, graph.LI(len(weights)))
+		weights = append(weights, wt)
+	}
+	add(0, 1, 1.5)
+	add(1, 0, 2)
+	add(1, 2, 3)
+	add(2, 2, 4)
+	add(2, 3, .25)
+	add(3, 2, .5)
+	add(0, 1, 7)
+	add(2, 2, 1)
+	w := func(l graph.LI) float64 { return weights[l] }
+	c, cw, err := g.CollapseToWeighted(w, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, _, _ := c.IsUndirected(); !u {
+		t.Fatal("result not undirected")
+	}
+	if s, _ := c.IsSimple(); s {
+		t.Fatal("result simple, loop lost")
+	}
+	sum := func(g graph.LabeledUndirected, w graph.WeightFunc) (s float64) {
+		g.Edges(func(e graph.LabeledEdge) { s += w(e.LI) })
+		return
+	}
+	cwf := func(l graph.LI) float64 { return cw[l] }
+	if got, want := sum(c, cwf), sum(g, w); got != want {
+		t.Fatal("total weight", got, "want", want)
+	}
+	if c.Size() != 4 {
+		t.Fatal("size", c.Size(), "want 4")
+	}
+	want := map[graph.Edge]float64{{0, 1}: 10.5, {1, 2}: 3, {2, 2}: 5, {2, 3}: .75}
+	c.Edges(func(e graph.LabeledEdge) {
+		if e.N1 > e.N2 {
+			e.N1, e.N2 = e.N2, e.N1
+		}
+		if cw[e.LI] != want[e.Edge] {
+			t.Fatal(e.Edge, "weight", cw[e.LI], "want", want[e.Edge])
+		}
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// color.go -- node coloring of undirected graphs.
+
+import (
+	"math/rand"
+	"sync"
+
+	"github.com/soniakeys/bits"
+)
+
+// ColoringOk validates a node coloring of an undirected graph.
+//
+// Argument colors must have a color for each node of g.  Colors may be any
+// non-negative integers.  A coloring is proper if no edge joins two nodes of
+// the same color.
+//
+// If the coloring is proper, ColoringOk returns ok = true.  Otherwise it
+// returns ok = false with an edge n1, n2 where both nodes have the same color.
+// Loops always make a coloring improper.  A negative color is reported as a
+// single node with n1 = n2.
+func (g Undirected) ColoringOk(colors []int) (ok bool, n1, n2 NI) {
+	for fr, to := range g.AdjacencyList {
+		if colors[fr] < 0 {
+			return false, NI(fr), NI(fr)
+		}
+		for _, to := range to {
+			if colors[to] == colors[fr] {
+				return false, NI(fr), to
+			}
+		}
+	}
+	return true, -1, -1
+}
+
+// ColorParallelJP colors the nodes of a simple undirected graph using the
+// Jones-Plassmann algorithm.
+//
+// Each node is given a distinct random priority.  Coloring proceeds in rounds.
+// In each round, every uncolored node with a priority higher than that of all
+// of its uncolored neighbors colors itself with the smallest color not used by
+// a neighbor.  Nodes coloring themselves in a round form an independent set
+// and so can be processed concurrently.  Rounds are processed with the given
+// number of worker goroutines.
+//
+// The result is a proper coloring that depends only on the priorities drawn
+// from r and not on the number of workers.  If Rand r is nil, the rand package
+// default shared source is used.
+//
+// Returned are colors indexed by node, with values from 0 to numColors-1.
+// Greedy sequential coloring typically uses somewhat fewer colors.
+//
+// The Luby variant of the algorithm redraws random priorities each round to
+// select a maximal independent set.  It is not implemented here as redrawing
+// would require synchronized access to the random source.
+func (g Undirected) ColorParallelJP(workers int, r *rand.Rand) (colors []int, numColors int) {
+	a := g.AdjacencyList
+	perm := rand.Perm
+	if r != nil {
+		perm = r.Perm
+	}
+	pri := perm(len(a))
+	colors = make([]int, len(a))
+	for i := range colors {
+		colors[i] = -1
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	// chunks of the node range are multiples of 64 so each worker owns whole
+	// words of the uncolored set.
+	chunk := (len(a) + workers - 1) / workers
+	chunk = (chunk + 63) &^ 63
+	uncolored := bits.New(len(a))
+	uncolored.SetAll()
+	cand := make([][]NI, workers)
+	nc := make([]int, workers)
+	var wg sync.WaitGroup
+	parallel := func(f func(w, lo, hi int)) {
+		for w := 0; w < workers; w++ {
+			lo := w * chunk
+			hi := lo + chunk
+			if hi > len(a) {
+				hi = len(a)
+			}
+			if lo >= hi {
+				continue
+			}
+			wg.Add(1)
+			go func(w, lo, hi int) {
+				f(w, lo, hi)
+				wg.Done()
+			}(w, lo, hi)
+		}
+		wg.Wait()
+	}
+	for !uncolored.AllZeros() {
+		// select nodes of highest priority among uncolored neighbors
+		parallel(func(w, lo, hi int) {
+			c := cand[w][:0]
+		nodes:
+			for n := uncolored.OneFrom(lo); n >= 0 && n < hi; n = uncolored.OneFrom(n + 1) {
+				for _, to := range a[n] {
+					if uncolored.Bit(int(to)) == 1 && pri[to] > pri[n] {
+						continue nodes
+					}
+				}
+				c = append(c, NI(n))
+			}
+			cand[w] = c
+		})
+		// color selected nodes
+		parallel(func(w, lo, hi int) {
+			var used []bool
+			for _, n := range cand[w] {
+				if cap(used) < len(a[n])+1 {
+					used = make([]bool, len(a[n])+1)
+				}
+				used = used[:len(a[n])+1]
+				for i := range used {
+					used[i] = false
+				}
+				for _, to := range a[n] {
+					// uncolored is not read here as other workers are
+					// updating it.  Colors of neighbors are stable though.
+					if c := colors[to]; c >= 0 && c < len(used) {
+						used[c] = true
+					}
+				}
+				c := 0
+				for used[c] {
+					c++
+				}
+				colors[n] = c
+				if c >= nc[w] {
+					nc[w] = c + 1
+				}
+				uncolored.SetBit(int(n), 0)
+			}
+		})
+	}
+	for _, c := range nc {
+		if c > numColors {
+			numColors = c
+		}
+	}
+	return
+}
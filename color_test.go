@@ -0,0 +1,94 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/graphtest"
+)
+
+func ExampleUndirected_ColoringOk() {
+	// 0--1
+	// | /
+	// 2
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 0)
+	fmt.Println(g.ColoringOk([]int{0, 1, 2}))
+	fmt.Println(g.ColoringOk([]int{0, 1, 0}))
+	// Output:
+	// true -1 -1
+	// false 0 2
+}
+
+func ExampleUndirected_ColorParallelJP() {
+	// 0--1--2--3--4
+	var g graph.Undirected
+	for n := graph.NI(1); n < 5; n++ {
+		g.AddEdge(n-1, n)
+	}
+	colors, n := g.ColorParallelJP(2, rand.New(rand.NewSource(1)))
+	ok, _, _ := g.ColoringOk(colors)
+	fmt.Println(ok, n <= 3)
+	// Output:
+	// true true
+}
+
+func TestColorParallelJP(t *testing.T) {
+	g := graph.GnmUndirected(1000, 8000, rand.New(rand.NewSource(7)))
+	c1, n1 := g.ColorParallelJP(1, rand.New(rand.NewSource(11)))
+	if err := graphtest.CheckProperColoring(g, c1); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []int{2, 3, 8} {
+		cw, nw := g.ColorParallelJP(w, rand.New(rand.NewSource(11)))
+		if nw != n1 || !reflect.DeepEqual(cw, c1) {
+			t.Fatal("coloring differs with", w, "workers")
+		}
+	}
+}
+
+// greedy colors nodes in node number order, for comparison.
+func greedy(g graph.Undirected) (colors []int, numColors int) {
+	colors = make([]int, g.Order())
+	for i := range colors {
+		colors[i] = -1
+	}
+	for n, to := range g.AdjacencyList {
+		used := map[int]bool{}
+		for _, to := range to {
+			used[colors[to]] = true
+		}
+		c := 0
+		for used[c] {
+			c++
+		}
+		colors[n] = c
+		if c >= numColors {
+			numColors = c + 1
+		}
+	}
+	return
+}
+
+func BenchmarkColorParallelJP(b *testing.B) {
+	g := graph.GnmUndirected(200000, 2000000, rand.New(rand.NewSource(7)))
+	_, ng := greedy(g)
+	for _, w := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprint("workers", w), func(b *testing.B) {
+			var nc int
+			for i := 0; i < b.N; i++ {
+				_, nc = g.ColorParallelJP(w, rand.New(rand.NewSource(11)))
+			}
+			b.ReportMetric(float64(nc), "colors")
+			b.ReportMetric(float64(ng), "greedy-colors")
+		})
+	}
+}
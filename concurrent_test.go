@@ -0,0 +1,94 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+// TestConcurrentSearches runs read-only methods concurrently on a single
+// shared graph and checks that results match sequential runs.  It is most
+// useful run with the race detector, go test -race.
+func TestConcurrentSearches(t *testing.T) {
+	tc := r(300, 1500, 71)
+	w := func(l graph.LI) float64 { return tc.w[l] }
+	l := tc.l
+	searches := []func() string{
+		func() string {
+			f, labels, dist, n := l.Dijkstra(tc.start, -1, w)
+			return fmt.Sprint(f.Paths, labels, dist, n)
+		},
+		func() string {
+			p, d := l.DijkstraPath(tc.start, tc.end, w)
+			return fmt.Sprint(p, d)
+		},
+		func() string {
+			p, d := l.AStarAPath(tc.start, tc.end, tc.h, w)
+			return fmt.Sprint(p, d)
+		},
+		func() string {
+			p, d := l.AStarMPath(tc.start, tc.end, tc.h, w)
+			return fmt.Sprint(p, d)
+		},
+		func() string {
+			f, labels, dist, end := l.BellmanFord(w, tc.start)
+			return fmt.Sprint(f.Paths, labels, dist, end)
+		},
+		func() string {
+			var order []graph.NI
+			tc.g.BreadthFirst(tc.start, func(n graph.NI) {
+				order = append(order, n)
+			})
+			return fmt.Sprint(order)
+		},
+		func() string {
+			var sccs [][]graph.NI
+			tc.g.StronglyConnectedComponents(func(c []graph.NI) bool {
+				sccs = append(sccs, append([]graph.NI{}, c...))
+				return true
+			})
+			return fmt.Sprint(sccs)
+		},
+		func() string {
+			return fmt.Sprint(tc.g.Dominators(tc.start).Immediate)
+		},
+		func() string {
+			p, err := l.EulerianPath()
+			return fmt.Sprint(p, err)
+		},
+		func() string {
+			return fmt.Sprint(l.FloydWarshall(w))
+		},
+	}
+	want := make([]string, len(searches))
+	for i, s := range searches {
+		want[i] = s()
+	}
+	const workers = 4
+	var wg sync.WaitGroup
+	errs := make(chan string, workers*len(searches))
+	for k := 0; k < workers; k++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			// each worker runs all searches, starting at a different one
+			for j := range searches {
+				i := (j + k) % len(searches)
+				if got := searches[i](); got != want[i] {
+					errs <- fmt.Sprint("search ", i, " worker ", k,
+						" result differs from sequential run")
+				}
+			}
+		}(k)
+	}
+	wg.Wait()
+	close(errs)
+	for e := range errs {
+		t.Error(e)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// cycle.go -- canonical cycle representations and cycle sets.
+
+import "strconv"
+
+// CanonicalCycle returns a canonical representation of a directed cycle.
+//
+// Cycle c is a list of nodes as emitted by Directed.Cycles, where an arc
+// leads from each node to the next and from the last node back to the first.
+// The result is a new slice, c rotated to start at its minimum node.
+// The cycle should be elementary, visiting no node more than once.
+//
+// See also CanonicalUndirectedCycle, CanonicalLabeledCycle.
+func CanonicalCycle(c []NI) []NI {
+	r := make([]NI, len(c))
+	i := minNodeIndex(c)
+	copy(r, c[i:])
+	copy(r[len(c)-i:], c[:i])
+	return r
+}
+
+// CanonicalUndirectedCycle returns a canonical representation of a cycle in
+// an undirected graph.
+//
+// The cycle is rotated as by CanonicalCycle, then if it has three or more
+// nodes, oriented so that the second node is the lesser of the two neighbors
+// of the first.  The result is a new slice.
+func CanonicalUndirectedCycle(c []NI) []NI {
+	r := CanonicalCycle(c)
+	if last := len(r) - 1; last >= 2 && r[last] < r[1] {
+		for i, j := 1, last; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+	}
+	return r
+}
+
+// CanonicalLabeledCycle returns a canonical representation of a labeled
+// directed cycle.
+//
+// Cycle c is a list of half arcs as emitted by LabeledDirected.Cycles, where
+// the first half arc leads from the to-node of the last.  The result is a new
+// slice, c rotated so that the first half arc leads from the minimum node.
+// Labels stay with their half arcs.
+func CanonicalLabeledCycle(c []Half) []Half {
+	r := make([]Half, len(c))
+	if len(c) == 0 {
+		return r
+	}
+	i := minHalfIndex(c) + 1 // arc leading from the min node
+	copy(r, c[i:])
+	copy(r[len(c)-i:], c[:i])
+	return r
+}
+
+// CanonicalLabeledUndirectedCycle returns a canonical representation of a
+// labeled cycle in an undirected graph.
+//
+// The cycle is rotated as by CanonicalLabeledCycle, then oriented so that
+// the first half arc leads to the lesser of the two neighbors of the start
+// node.  Where the neighbors are the same node, as for a cycle of two
+// parallel edges, the orientation is chosen so the first label is the lesser.
+// The result is a new slice.
+func CanonicalLabeledUndirectedCycle(c []Half) []Half {
+	r := CanonicalLabeledCycle(c)
+	k := len(r)
+	if k < 2 {
+		return r
+	}
+	first, other := r[0], Half{r[k-2].To, r[k-1].Label}
+	if other.To > first.To ||
+		other.To == first.To && other.Label >= first.Label {
+		return r
+	}
+	// reverse:  half arc j leads to the node before half arc k-1-j
+	s := r[k-1].To // the start node
+	rev := make([]Half, k)
+	for j := range rev {
+		to := s
+		if x := k - 2 - j; x >= 0 {
+			to = r[x].To
+		}
+		rev[j] = Half{to, r[k-1-j].Label}
+	}
+	return rev
+}
+
+func minNodeIndex(c []NI) int {
+	m := 0
+	for i, n := range c {
+		if n < c[m] {
+			m = i
+		}
+	}
+	return m
+}
+
+func minHalfIndex(c []Half) int {
+	m := 0
+	for i, h := range c {
+		if h.To < c[m].To {
+			m = i
+		}
+	}
+	return m
+}
+
+// CycleKey returns a string representation of cycle c suitable as a map key.
+//
+// The key is formed from the nodes of c as given.  Pass a canonical cycle
+// to get a key identifying the cycle regardless of its starting node.
+func CycleKey(c []NI) string {
+	b := make([]byte, 0, len(c)*4)
+	for i, n := range c {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = strconv.AppendInt(b, int64(n), 10)
+	}
+	return string(b)
+}
+
+// LabeledCycleKey returns a string representation of labeled cycle c
+// suitable as a map key.
+//
+// The key is formed from the half arcs of c as given.  Pass a canonical cycle
+// to get a key identifying the cycle regardless of its starting node.
+func LabeledCycleKey(c []Half) string {
+	b := make([]byte, 0, len(c)*8)
+	for i, h := range c {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = strconv.AppendInt(b, int64(h.To), 10)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(h.Label), 10)
+	}
+	return string(b)
+}
+
+// CycleSet is a set of cycles, compared in canonical form.
+//
+// Set Undirected true, before adding cycles, for cycles of an undirected
+// graph.  The zero value is an empty set of directed cycles.
+type CycleSet struct {
+	Undirected bool
+	m          map[string]bool
+}
+
+func (s *CycleSet) key(c []NI) string {
+	if s.Undirected {
+		return CycleKey(CanonicalUndirectedCycle(c))
+	}
+	return CycleKey(CanonicalCycle(c))
+}
+
+// Add adds cycle c to the set.  It returns true if c was added, false if an
+// equivalent cycle was already in the set.
+func (s *CycleSet) Add(c []NI) bool {
+	k := s.key(c)
+	if s.m[k] {
+		return false
+	}
+	if s.m == nil {
+		s.m = map[string]bool{}
+	}
+	s.m[k] = true
+	return true
+}
+
+// Contains returns true if the set contains a cycle equivalent to c.
+func (s *CycleSet) Contains(c []NI) bool {
+	return s.m[s.key(c)]
+}
+
+// Len returns the number of cycles in the set.
+func (s *CycleSet) Len() int {
+	return len(s.m)
+}
+
+// LabeledCycleSet is a set of labeled cycles, compared in canonical form.
+//
+// Set Undirected true, before adding cycles, for cycles of an undirected
+// graph.  The zero value is an empty set of directed cycles.
+type LabeledCycleSet struct {
+	Undirected bool
+	m          map[string]bool
+}
+
+func (s *LabeledCycleSet) key(c []Half) string {
+	if s.Undirected {
+		return LabeledCycleKey(CanonicalLabeledUndirectedCycle(c))
+	}
+	return LabeledCycleKey(CanonicalLabeledCycle(c))
+}
+
+// Add adds cycle c to the set.  It returns true if c was added, false if an
+// equivalent cycle was already in the set.
+func (s *LabeledCycleSet) Add(c []Half) bool {
+	k := s.key(c)
+	if s.m[k] {
+		return false
+	}
+	if s.m == nil {
+		s.m = map[string]bool{}
+	}
+	s.m[k] = true
+	return true
+}
+
+// Contains returns true if the set contains a cycle equivalent to c.
+func (s *LabeledCycleSet) Contains(c []Half) bool {
+	return s.m[s.key(c)]
+}
+
+// Len returns the number of cycles in the set.
+func (s *LabeledCycleSet) Len() int {
+	return len(s.m)
+}
@@ -0,0 +1,150 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleCanonicalCycle() {
+	fmt.Println(graph.CanonicalCycle([]graph.NI{5, 2, 7, 3}))
+	fmt.Println(graph.CanonicalUndirectedCycle([]graph.NI{5, 2, 7, 3}))
+	// Output:
+	// [2 7 3 5]
+	// [2 5 3 7]
+}
+
+func ExampleCanonicalLabeledCycle() {
+	// 4 -b-> 3 -c-> 1 -a-> 4
+	c := []graph.Half{{3, 'b'}, {1, 'c'}, {4, 'a'}}
+	for _, h := range graph.CanonicalLabeledCycle(c) {
+		fmt.Printf(" -%c-> %d", h.Label, h.To)
+	}
+	fmt.Println()
+	for _, h := range graph.CanonicalLabeledUndirectedCycle(c) {
+		fmt.Printf(" -%c- %d", h.Label, h.To)
+	}
+	fmt.Println()
+	// Output:
+	//  -a-> 4 -b-> 3 -c-> 1
+	//  -c- 3 -b- 4 -a- 1
+}
+
+func ExampleCycleSet() {
+	var s graph.CycleSet
+	fmt.Println(s.Add([]graph.NI{1, 2, 3}))
+	fmt.Println(s.Add([]graph.NI{3, 1, 2}))
+	fmt.Println(s.Contains([]graph.NI{2, 3, 1}))
+	fmt.Println(s.Contains([]graph.NI{3, 2, 1}))
+	s = graph.CycleSet{Undirected: true}
+	s.Add([]graph.NI{1, 2, 3})
+	fmt.Println(s.Contains([]graph.NI{3, 2, 1}))
+	// Output:
+	// true
+	// false
+	// true
+	// false
+	// true
+}
+
+func TestCanonicalCycle(t *testing.T) {
+	for _, tc := range []struct{ c, dir, undir []graph.NI }{
+		{nil, []graph.NI{}, []graph.NI{}},
+		{[]graph.NI{4}, []graph.NI{4}, []graph.NI{4}},          // loop
+		{[]graph.NI{4, 2}, []graph.NI{2, 4}, []graph.NI{2, 4}}, // 2-cycle
+		{[]graph.NI{0, 9, 1}, []graph.NI{0, 9, 1}, []graph.NI{0, 1, 9}},
+		{[]graph.NI{3, 1, 6, 2}, []graph.NI{1, 6, 2, 3}, []graph.NI{1, 3, 2, 6}},
+		{[]graph.NI{6, 1, 3, 2}, []graph.NI{1, 3, 2, 6}, []graph.NI{1, 3, 2, 6}},
+	} {
+		c := append([]graph.NI{}, tc.c...)
+		if got := graph.CanonicalCycle(c); !reflect.DeepEqual(got, tc.dir) {
+			t.Fatal(tc.c, "directed", got, "want", tc.dir)
+		}
+		if got := graph.CanonicalUndirectedCycle(c); !reflect.DeepEqual(got, tc.undir) {
+			t.Fatal(tc.c, "undirected", got, "want", tc.undir)
+		}
+		if !reflect.DeepEqual(c, append([]graph.NI{}, tc.c...)) {
+			t.Fatal("argument modified", c)
+		}
+	}
+	// all rotations and reflections canonicalize the same
+	c := []graph.NI{7, 3, 5, 0, 8}
+	want := graph.CanonicalUndirectedCycle(c)
+	for i := range c {
+		r := append(append([]graph.NI{}, c[i:]...), c[:i]...)
+		if got := graph.CanonicalUndirectedCycle(r); !reflect.DeepEqual(got, want) {
+			t.Fatal(r, got, want)
+		}
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		if got := graph.CanonicalUndirectedCycle(r); !reflect.DeepEqual(got, want) {
+			t.Fatal(r, got, want)
+		}
+	}
+}
+
+func TestCanonicalLabeledCycle(t *testing.T) {
+	// loop
+	l := []graph.Half{{3, 9}}
+	if got := graph.CanonicalLabeledUndirectedCycle(l); !reflect.DeepEqual(got, l) {
+		t.Fatal(got)
+	}
+	// two parallel edges between 1 and 5 with labels 8 and 6
+	p := []graph.Half{{1, 8}, {5, 6}}
+	want := []graph.Half{{5, 6}, {1, 8}}
+	if got := graph.CanonicalLabeledCycle(p); !reflect.DeepEqual(got, want) {
+		t.Fatal(got)
+	}
+	if got := graph.CanonicalLabeledUndirectedCycle(p); !reflect.DeepEqual(got, want) {
+		t.Fatal(got)
+	}
+	p = []graph.Half{{5, 8}, {1, 6}}
+	want = []graph.Half{{5, 6}, {1, 8}}
+	if got := graph.CanonicalLabeledUndirectedCycle(p); !reflect.DeepEqual(got, want) {
+		t.Fatal(got)
+	}
+	// reflection of 2 -10- 6 -11- 4 -12- 9 -13- 2 starting from 2
+	c := []graph.Half{{9, 13}, {4, 12}, {6, 11}, {2, 10}}
+	want = []graph.Half{{6, 10}, {4, 11}, {9, 12}, {2, 13}}
+	if got := graph.CanonicalLabeledUndirectedCycle(c); !reflect.DeepEqual(got, want) {
+		t.Fatal(got)
+	}
+	if got := graph.CanonicalLabeledCycle(c); !reflect.DeepEqual(got, c) {
+		t.Fatal(got)
+	}
+}
+
+func TestCycleKey(t *testing.T) {
+	// keys that might collide with a careless encoding
+	cs := [][]graph.NI{{1, 23}, {12, 3}, {123}, {1, 2, 3}, {}}
+	seen := map[string]bool{}
+	for _, c := range cs {
+		k := graph.CycleKey(c)
+		if seen[k] {
+			t.Fatal("collision", c, k)
+		}
+		seen[k] = true
+	}
+	hs := [][]graph.Half{{{1, 23}}, {{12, 3}}, {{1, 2}, {3, 4}}, {{12, 34}}}
+	seen = map[string]bool{}
+	for _, c := range hs {
+		k := graph.LabeledCycleKey(c)
+		if seen[k] {
+			t.Fatal("collision", c, k)
+		}
+		seen[k] = true
+	}
+	var s graph.LabeledCycleSet
+	if !s.Add([]graph.Half{{1, 5}, {2, 6}}) || s.Add([]graph.Half{{2, 6}, {1, 5}}) {
+		t.Fatal("rotation not recognized")
+	}
+	if !s.Add([]graph.Half{{1, 6}, {2, 5}}) || s.Len() != 2 {
+		t.Fatal("labels not distinguished")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// daghash.go -- Merkle-style hashes of ancestor subgraphs of DAGs.
+
+import "sort"
+
+// FNV-1a 64 bit parameters.
+const (
+	fnvOffset64 uint64 = 14695981039346656037
+	fnvPrime64  uint64 = 1099511628211
+)
+
+// fnvUint64 continues FNV-1a hash h with the 8 bytes of x, least significant
+// byte first.
+func fnvUint64(h, x uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		h ^= x & 0xff
+		h *= fnvPrime64
+		x >>= 8
+	}
+	return h
+}
+
+// AncestorHashes computes a hash for each node of directed acyclic graph g
+// that depends on the node and all of its ancestors.
+//
+// The hash of a node combines nodeHash of the node with the hashes of its
+// direct predecessors, and so in turn with nodeHash of every ancestor and
+// the arcs between them.  The hash of a node changes when nodeHash of the
+// node or of any ancestor changes, or when arcs among the node and its
+// ancestors change, but not when other parts of the graph change.
+//
+// The combination is fixed and will remain stable:  For node n with
+// predecessor hashes p1..pk sorted in increasing order, one value for each
+// arc into n, the hash is FNV-1a 64 over the bytes of the uint64 values
+// nodeHash(n), k, p1, ..., pk, each written least significant byte first.
+// Predecessor hashes are sorted so that the hash does not depend on arc
+// order.  Parallel arcs contribute multiple times.
+//
+// Hashes are 64 bits and are not cryptographic.  Different ancestor
+// subgraphs can produce the same hash, although for unrelated subgraphs this
+// is unlikely unless nodeHash itself has collisions.  Applications that
+// cannot tolerate a collision should compare subgraphs when hashes match.
+//
+// If g is cyclic, AncestorHashes returns nil and a cycle as returned by
+// Topological.
+//
+// See also LabeledDirected.AncestorHashes.
+func (g Directed) AncestorHashes(nodeHash func(NI) uint64) (h []uint64, cycle []NI) {
+	ordering, cycle := g.Topological()
+	if cycle != nil {
+		return nil, cycle
+	}
+	tr, _ := g.Transpose()
+	h = make([]uint64, g.Order())
+	var p []uint64
+	for _, n := range ordering {
+		p = p[:0]
+		for _, fr := range tr.AdjacencyList[n] {
+			p = append(p, h[fr])
+		}
+		sort.Slice(p, func(i, j int) bool { return p[i] < p[j] })
+		x := fnvUint64(fnvOffset64, nodeHash(n))
+		x = fnvUint64(x, uint64(len(p)))
+		for _, ph := range p {
+			x = fnvUint64(x, ph)
+		}
+		h[n] = x
+	}
+	return h, nil
+}
+
+// AncestorHashes computes a hash for each node of directed acyclic graph g
+// that depends on the node and all of its ancestors, including arc labels.
+//
+// The method is as described for Directed.AncestorHashes, except that arc
+// labels are also combined:  For node n with in-arcs having predecessor
+// hash and label pairs (p1, l1)..(pk, lk) sorted in increasing order by hash
+// then label, the hash is FNV-1a 64 over the bytes of the uint64 values
+// nodeHash(n), k, p1, l1, ..., pk, lk, each written least significant byte
+// first, with labels converted to uint64 from their unsigned 32 bit
+// representation.
+func (g LabeledDirected) AncestorHashes(nodeHash func(NI) uint64) (h []uint64, cycle []NI) {
+	ordering, cycle := g.Topological()
+	if cycle != nil {
+		return nil, cycle
+	}
+	tr, _ := g.Transpose()
+	h = make([]uint64, g.Order())
+	type pl struct {
+		p uint64
+		l LI
+	}
+	var p []pl
+	for _, n := range ordering {
+		p = p[:0]
+		for _, fr := range tr.LabeledAdjacencyList[n] {
+			p = append(p, pl{h[fr.To], fr.Label})
+		}
+		sort.Slice(p, func(i, j int) bool {
+			return p[i].p < p[j].p || p[i].p == p[j].p && p[i].l < p[j].l
+		})
+		x := fnvUint64(fnvOffset64, nodeHash(n))
+		x = fnvUint64(x, uint64(len(p)))
+		for _, e := range p {
+			x = fnvUint64(x, e.p)
+			x = fnvUint64(x, uint64(uint32(e.l)))
+		}
+		h[n] = x
+	}
+	return h, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDirected_AncestorHashes() {
+	//   0   1
+	//  / \ /
+	// 2   3
+	//  \ /
+	//   4
+	g := graph.Directed{graph.AdjacencyList{
+		0: {2, 3},
+		1: {3},
+		2: {4},
+		3: {4},
+		4: {},
+	}}
+	content := []uint64{10, 11, 12, 13, 14}
+	nodeHash := func(n graph.NI) uint64 { return content[n] }
+	h1, _ := g.AncestorHashes(nodeHash)
+	content[1] = 21 // change node 1
+	h2, _ := g.AncestorHashes(nodeHash)
+	for n := range h1 {
+		fmt.Println(n, h1[n] != h2[n])
+	}
+	g.AdjacencyList[4] = []graph.NI{0}
+	_, cycle := g.AncestorHashes(nodeHash)
+	fmt.Println("cycle:", cycle)
+	// Output:
+	// 0 false
+	// 1 true
+	// 2 false
+	// 3 true
+	// 4 true
+	// cycle: [0 2 4]
+}
+
+// changing nodeHash of any node must change exactly the hashes of the node
+// and its descendants.
+func TestAncestorHashes(t *testing.T) {
+	for _, g := range []graph.Directed{
+		// diamond
+		{graph.AdjacencyList{
+			0: {1, 2},
+			1: {3},
+			2: {3},
+			3: {},
+		}},
+		// multi-root, with parallel arcs
+		{graph.AdjacencyList{
+			0: {2, 2},
+			1: {2, 3},
+			2: {4, 5},
+			3: {5},
+			4: {},
+			5: {},
+			6: {5},
+		}},
+	} {
+		reach := g.TransitiveClosure()
+		base := func(n graph.NI) uint64 { return uint64(n) * 7 }
+		h0, cycle := g.AncestorHashes(base)
+		if cycle != nil {
+			t.Fatal(cycle)
+		}
+		// labeled, with all labels the same
+		lg := graph.LabeledDirected{make(graph.LabeledAdjacencyList, g.Order())}
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				lg.LabeledAdjacencyList[fr] = append(
+					lg.LabeledAdjacencyList[fr], graph.Half{to, 1})
+			}
+		}
+		lh0, _ := lg.AncestorHashes(base)
+		for c := range g.AdjacencyList {
+			changed := func(n graph.NI) uint64 {
+				if n == graph.NI(c) {
+					return 1000
+				}
+				return base(n)
+			}
+			h, _ := g.AncestorHashes(changed)
+			lh, _ := lg.AncestorHashes(changed)
+			for n := range h {
+				want := n == c || reach[c].Bit(n) == 1
+				if (h[n] != h0[n]) != want || (lh[n] != lh0[n]) != want {
+					t.Fatal("changed", c, "node", n, "want change", want)
+				}
+			}
+		}
+		// changing a label changes the arc head and its descendants
+		for fr, to := range lg.LabeledAdjacencyList {
+			for x, h := range to {
+				to[x].Label = 2
+				lh, _ := lg.AncestorHashes(base)
+				to[x].Label = 1
+				for n := range lh {
+					want := n == int(h.To) || reach[h.To].Bit(n) == 1
+					if (lh[n] != lh0[n]) != want {
+						t.Fatal("label", fr, x, "node", n, "want change", want)
+					}
+				}
+			}
+		}
+		// arc order does not matter
+		for _, to := range g.AdjacencyList {
+			for i, j := 0, len(to)-1; i < j; i, j = i+1, j-1 {
+				to[i], to[j] = to[j], to[i]
+			}
+		}
+		if h, _ := g.AncestorHashes(base); fmt.Sprint(h) != fmt.Sprint(h0) {
+			t.Fatal("arc order changed hashes")
+		}
+	}
+}
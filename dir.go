@@ -5,6 +5,10 @@ package graph
 
 import (
 	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/soniakeys/bits"
 )
 
 // dir.go has methods specific to directed graphs, types Directed and
@@ -14,6 +18,29 @@ import (
 // Dominators type and methods are at the end.
 //----------------------------
 
+// AddArc adds an arc from node fr to node to.
+//
+// The pointer receiver allows the method to expand the graph as needed
+// to include the values fr and to.  If fr or to happen to be greater than
+// len(*p) the method does not panic, but simply expands the graph.
+//
+// As with Undirected.AddEdge, consider preallocating the graph if you know
+// or can compute the final graph order.
+func (p *Directed) AddArc(fr, to NI) {
+	// Similar code in LabeledDirected.AddArc.
+	max := fr
+	if to > max {
+		max = to
+	}
+	g := p.AdjacencyList
+	if int(max) >= len(g) {
+		p.AdjacencyList = make(AdjacencyList, max+1)
+		copy(p.AdjacencyList, g)
+		g = p.AdjacencyList
+	}
+	g[fr] = append(g[fr], to)
+}
+
 // Cycles emits all elementary cycles in a directed graph.
 //
 // The algorithm here is Johnson's.  See also the equivalent but generally
@@ -156,6 +183,62 @@ func (g Directed) DAGMaxLenPath(ordering []NI) (path []NI) {
 	return append([]NI{n}, path...)
 }
 
+// DigraphicalSequence tests if a degree sequence can be realized as a simple
+// directed graph.
+//
+// Arguments in and out are in-degrees and out-degrees indexed by node and must
+// have the same length.  The test is by the Fulkerson-Chen-Anstee theorem.
+//
+// See also Directed.SimplifyPreservingDegrees.
+func DigraphicalSequence(in, out []int) bool {
+	if len(in) != len(out) {
+		return false
+	}
+	type pair struct{ out, in int }
+	p := make([]pair, len(in))
+	sumIn, sumOut := 0, 0
+	for i := range p {
+		if in[i] < 0 || out[i] < 0 {
+			return false
+		}
+		p[i] = pair{out[i], in[i]}
+		sumIn += in[i]
+		sumOut += out[i]
+	}
+	if sumIn != sumOut {
+		return false
+	}
+	// lexicographic nonincreasing order
+	sort.Slice(p, func(i, j int) bool {
+		if p[i].out != p[j].out {
+			return p[i].out > p[j].out
+		}
+		return p[i].in > p[j].in
+	})
+	lhs := 0
+	for k := 1; k <= len(p); k++ {
+		lhs += p[k-1].out
+		rhs := 0
+		for i, q := range p {
+			if i < k {
+				if q.in < k-1 {
+					rhs += q.in
+				} else {
+					rhs += k - 1
+				}
+			} else if q.in < k {
+				rhs += q.in
+			} else {
+				rhs += k
+			}
+		}
+		if lhs > rhs {
+			return false
+		}
+	}
+	return true
+}
+
 // FromList creates a spanning forest of a graph.
 //
 // The method populates the From members in f.Paths and returns the FromList.
@@ -187,6 +270,145 @@ func (g Directed) FromList() (f *FromList, simpleForest bool) {
 	return &FromList{Paths: paths}, simpleForest
 }
 
+// RemoveArc removes a single arc from node fr to node to.
+//
+// If g has parallel arcs from fr to to, exactly one is removed.  The order
+// of remaining arcs from fr is not preserved.
+//
+// Returns true if the specified arc is found and successfully removed,
+// false if the arc does not exist.
+func (g Directed) RemoveArc(fr, to NI) (ok bool) {
+	ok, x := g.HasArc(fr, to)
+	if !ok {
+		return
+	}
+	a := g.AdjacencyList
+	t := a[fr]
+	last := len(t) - 1
+	t[x] = t[last]
+	a[fr] = t[:last]
+	return
+}
+
+// RenumberBySCC renumbers nodes so that nodes of each strongly connected
+// component are numbered consecutively, with components in topological
+// order.
+//
+// Components are found with Condensation.  Nodes within a component are
+// numbered in breadth-first order from the first node of the component as
+// returned by Condensation.
+//
+// Returned is the renumbered graph r, the permutation perm where node n of g
+// is node perm[n] of r, and blocks, the node number in r of the first node
+// of each component.  Component c of r is nodes blocks[c] through
+// blocks[c+1]-1, or through len(r)-1 for the last component.  All arcs of r
+// either stay within a component or lead from a component to a later one.
+// Thus if a node of r can reach another, the block of the first is less than
+// or equal to the block of the second.
+//
+// Receiver g is not modified.
+func (g Directed) RenumberBySCC() (r Directed, perm []NI, blocks []int) {
+	a := g.AdjacencyList
+	scc, _, comp := g.CondensationMap()
+	perm = make([]NI, len(a))
+	p := make([]int, len(a)) // perm as []int for Permute
+	blocks = make([]int, len(scc))
+	visited := bits.New(len(a))
+	next := 0
+	// Condensation returns components in reverse topological order
+	for c := len(scc) - 1; c >= 0; c-- {
+		blocks[len(scc)-1-c] = next
+		start := scc[c][0]
+		visited.SetBit(int(start), 1)
+		q := []NI{start}
+		for len(q) > 0 {
+			n := q[0]
+			q = q[1:]
+			perm[n] = NI(next)
+			p[n] = next
+			next++
+			for _, to := range a[n] {
+				if comp[to] == NI(c) && visited.Bit(int(to)) == 0 {
+					visited.SetBit(int(to), 1)
+					q = append(q, to)
+				}
+			}
+		}
+	}
+	r, _ = g.Copy()
+	r.Permute(p)
+	return
+}
+
+// SimplifyPreservingDegrees constructs a simple directed graph with the same
+// in-degree and out-degree sequences as g.
+//
+// Loops and parallel arcs of g are removed by directed 2-swaps:  For an
+// offending arc u->v and another arc x->y, the heads are exchanged to give
+// arcs u->y and x->v.  A swap is made only if it creates no new loop or
+// parallel arc, so each swap reduces the number of offending arcs.
+// Candidate arcs x->y are chosen at random.
+//
+// If Rand r is nil, the rand package default shared source is used.
+//
+// SimplifyPreservingDegrees returns ok = false if the degree sequences of g
+// have no simple realization, as determined by DigraphicalSequence, or if all
+// offending arcs cannot be removed within maxSwaps attempted swaps.
+// Otherwise it returns the new graph and ok = true.  Nodes keep the relative
+// order of their out-arcs.  Receiver g is not modified.
+func (g Directed) SimplifyPreservingDegrees(r *rand.Rand, maxSwaps int) (s Directed, ok bool) {
+	ri := rand.Intn
+	if r != nil {
+		ri = r.Intn
+	}
+	a := g.AdjacencyList
+	in := g.InDegree()
+	out := make([]int, len(a))
+	for n, to := range a {
+		out[n] = len(to)
+	}
+	if !DigraphicalSequence(in, out) {
+		return
+	}
+	// arcs as parallel slices of tails and heads
+	var fr, to []NI
+	cnt := map[[2]NI]int{}
+	for n, t := range a {
+		for _, t := range t {
+			fr = append(fr, NI(n))
+			to = append(to, t)
+			cnt[[2]NI{NI(n), t}]++
+		}
+	}
+	bad := func(i int) bool {
+		return fr[i] == to[i] || cnt[[2]NI{fr[i], to[i]}] > 1
+	}
+	swaps := 0
+	for i := range fr {
+		for bad(i) {
+			if swaps == maxSwaps {
+				return Directed{}, false
+			}
+			swaps++
+			j := ri(len(fr))
+			u, v, x, y := fr[i], to[i], fr[j], to[j]
+			if u == y || x == v || cnt[[2]NI{u, y}] > 0 || cnt[[2]NI{x, v}] > 0 {
+				continue
+			}
+			cnt[[2]NI{u, v}]--
+			cnt[[2]NI{x, y}]--
+			cnt[[2]NI{u, y}]++
+			cnt[[2]NI{x, v}]++
+			to[i], to[j] = y, v
+		}
+	}
+	sa := make(AdjacencyList, len(a))
+	for i, n := range fr {
+		sa[n] = append(sa[n], to[i])
+	}
+	return Directed{sa}, true
+}
+
 // SpanTree builds a tree spanning nodes reachable from the given root.
 //
 // The component is spanned by breadth-first search from root.
@@ -283,15 +505,33 @@ func (g Directed) Undirected() Undirected {
 // in g (equal to the number of arcs in the result.)
 func (g Directed) Transpose() (t Directed, ma int) {
 	ta := make(AdjacencyList, g.Order())
-	for n, nbs := range g.AdjacencyList {
-		for _, nb := range nbs {
-			ta[nb] = append(ta[nb], NI(n))
-			ma++
-		}
-	}
+	g.AdjacencyList.Arcs(func(fr NI, _ int, to NI) bool {
+		ta[to] = append(ta[to], fr)
+		ma++
+		return true
+	})
 	return Directed{ta}, ma
 }
 
+// AddArc adds an arc from node fr to node to.To, with label to.Label.
+//
+// The pointer receiver allows the method to expand the graph as needed
+// to include the values fr and to.To.  See Directed.AddArc.
+func (p *LabeledDirected) AddArc(fr NI, to Half) {
+	// Similar code in Directed.AddArc.
+	max := fr
+	if to.To > max {
+		max = to.To
+	}
+	g := p.LabeledAdjacencyList
+	if int(max) >= len(g) {
+		p.LabeledAdjacencyList = make(LabeledAdjacencyList, max+1)
+		copy(p.LabeledAdjacencyList, g)
+		g = p.LabeledAdjacencyList
+	}
+	g[fr] = append(g[fr], to)
+}
+
 // Cycles emits all elementary cycles in a directed graph.
 //
 // The algorithm here is Johnson's.  See also the equivalent but generally
@@ -425,6 +665,140 @@ func (g LabeledDirected) DAGMaxLenPath(ordering []NI) (n NI, path []Half) {
 	return
 }
 
+// EulerianCycleOrdered finds an Eulerian cycle in a directed multigraph,
+// following arcs in an order given by a comparison function.
+//
+// EulerianCycleOrdered is like EulerianCycle but rather than following arcs
+// in arc list order, each step of the cycle construction follows the least
+// available arc according to less.  For example a less function comparing
+// labels gives a cycle that follows arcs of smaller label first.  Arcs
+// comparing equal are followed in arc list order.
+//
+// The result and any error are as described for EulerianCycle.
+//
+// Internally, EulerianCycleOrdered copies the entire graph g.  Arc lists of
+// the copy are sorted before the cycle is constructed.
+func (g LabeledDirected) EulerianCycleOrdered(less func(a, b Half) bool) ([]Half, error) {
+	c, m := g.Copy()
+	for _, to := range c.LabeledAdjacencyList {
+		sort.SliceStable(to, func(i, j int) bool { return less(to[i], to[j]) })
+	}
+	return c.EulerianCycleD(m)
+}
+
+// EulerianPathOrdered finds an Eulerian path in a directed multigraph,
+// following arcs in an order given by a comparison function.
+//
+// EulerianPathOrdered is like EulerianPath but follows arcs in the order of
+// less as described for EulerianCycleOrdered.
+//
+// Internally, EulerianPathOrdered copies the entire graph g.
+func (g LabeledDirected) EulerianPathOrdered(less func(a, b Half) bool) ([]Half, error) {
+	c, m := g.Copy()
+	start, err := c.EulerianStart()
+	if err != nil {
+		return nil, err
+	}
+	if start < 0 {
+		start = 0
+	}
+	for _, to := range c.LabeledAdjacencyList {
+		sort.SliceStable(to, func(i, j int) bool { return less(to[i], to[j]) })
+	}
+	return c.EulerianPathD(m, start)
+}
+
+// FeasibleCirculation finds a circulation satisfying lower and upper bounds
+// on arc flows.
+//
+// A circulation assigns a flow to each arc such that at every node, total
+// flow in equals total flow out.  Functions lower and upper give bounds on
+// the flow of an arc as a function of arc label.
+//
+// The problem is solved by the standard transformation to max flow:  Lower
+// bounds are subtracted from capacities and the resulting node excesses and
+// deficits are connected to a super source and super sink.
+//
+// If a feasible circulation exists, FeasibleCirculation returns flows indexed
+// as the arcs of g, that is, flow[fr][x] is the flow on arc g[fr][x], and
+// ok = true.  Otherwise it returns ok = false and a certificate cut, a set of
+// nodes S where the sum of lower bounds on arcs entering S exceeds the sum
+// of upper bounds on arcs leaving S.  If some arc has a lower bound greater
+// than its upper bound, cut is returned empty.
+func (g LabeledDirected) FeasibleCirculation(lower, upper func(LI) float64) (flow [][]float64, ok bool, cut bits.Bits) {
+	a := g.LabeledAdjacencyList
+	n := len(a)
+	f := make(flowNet, n+2)
+	s, t := NI(n), NI(n+1)
+	x := make([][]int, n) // index of arc in f, by index in a
+	exc := make([]float64, n)
+	for fr, to := range a {
+		x[fr] = make([]int, len(to))
+		for i, h := range to {
+			l, u := lower(h.Label), upper(h.Label)
+			if l > u {
+				return nil, false, bits.New(n)
+			}
+			x[fr][i] = f.addArc(NI(fr), h.To, u-l)
+			exc[h.To] += l
+			exc[fr] -= l
+		}
+	}
+	demand := 0.
+	for nd, e := range exc {
+		switch {
+		case e > 0:
+			f.addArc(s, NI(nd), e)
+			demand += e
+		case e < 0:
+			f.addArc(NI(nd), t, -e)
+		}
+	}
+	// tolerate rounding in float sums
+	if demand-f.maxFlow(s, t, -1) > 1e-9*demand {
+		r := f.reach(s)
+		cut = bits.New(n)
+		for nd := 0; nd < n; nd++ {
+			if r.Bit(nd) == 1 {
+				cut.SetBit(nd, 1)
+			}
+		}
+		return nil, false, cut
+	}
+	flow = make([][]float64, n)
+	for fr, to := range a {
+		flow[fr] = make([]float64, len(to))
+		for i, h := range to {
+			l, u := lower(h.Label), upper(h.Label)
+			if x[fr][i] < 0 {
+				flow[fr][i] = l // loop
+				continue
+			}
+			flow[fr][i] = u - f[fr][x[fr][i]].cap
+		}
+	}
+	return flow, true, cut
+}
+
+// FilterArcs constructs a new graph with the arcs of g for which keep
+// returns true.
+//
+// The result has the same order as g; nodes are not renumbered and may
+// become isolated.  Arcs are in the same order as in g.
+//
+// See also LabeledUndirected.FilterEdges.
+func (g LabeledDirected) FilterArcs(keep func(fr NI, to Half) bool) LabeledDirected {
+	f := make(LabeledAdjacencyList, g.Order())
+	for fr, to := range g.LabeledAdjacencyList {
+		for _, to := range to {
+			if keep(NI(fr), to) {
+				f[fr] = append(f[fr], to)
+			}
+		}
+	}
+	return LabeledDirected{f}
+}
+
 // FromList creates a spanning forest of a graph.
 //
 // The method populates the From members in f.Paths and returns the FromList.
@@ -459,6 +833,47 @@ func (g LabeledDirected) FromList() (f *FromList, labels []LI, simpleForest bool
 	return &FromList{Paths: paths}, labels, simpleForest
 }
 
+// LabeledCondensation returns strongly connected components and a labeled
+// condensation graph.
+//
+// Return values scc and cond are as for CondensationMap.  Condensation cd
+// has the same arcs as that returned by CondensationMap, but as a
+// LabeledAdjacencyList.  The label of each condensation arc is that of a
+// representative arc of g, the first found between the two components.
+// For each condensation arc cd[c][x], arcs[c][x] lists all arcs of g it
+// represents, each as LabeledEdge{Edge{from, to}, label}.
+func (g LabeledDirected) LabeledCondensation() (scc [][]NI, cd LabeledAdjacencyList, cond []NI, arcs [][][]LabeledEdge) {
+	scc, ucd, cond := g.CondensationMap()
+	cd = make(LabeledAdjacencyList, len(ucd))
+	arcs = make([][][]LabeledEdge, len(ucd))
+	x := make([]int, len(ucd)) // index of arc to each component
+	for cn, c := range scc {
+		to := ucd[cn]
+		if len(to) == 0 {
+			continue
+		}
+		for i, ct := range to {
+			x[ct] = i
+		}
+		cd[cn] = make([]Half, len(to))
+		arcs[cn] = make([][]LabeledEdge, len(to))
+		for _, n := range c {
+			for _, h := range g.LabeledAdjacencyList[n] {
+				ct := cond[h.To]
+				if ct == NI(cn) {
+					continue
+				}
+				i := x[ct]
+				if len(arcs[cn][i]) == 0 {
+					cd[cn][i] = Half{ct, h.Label}
+				}
+				arcs[cn][i] = append(arcs[cn][i], LabeledEdge{Edge{n, h.To}, h.Label})
+			}
+		}
+	}
+	return
+}
+
 // NegativeCycles emits all cycles with negative cycle distance.
 //
 // The emit function is called for each cycle found.  Emit must return true
@@ -792,6 +1207,45 @@ func (nc *negCyc) zΓ(F LabeledPath, wp float64) []Half {
 	return p.Path
 }
 
+// RemoveArc removes a single arc from node fr to node to.
+//
+// If g has parallel arcs from fr to to, exactly one is removed.  The order
+// of remaining arcs from fr is not preserved.
+//
+// If the specified arc is found and successfully removed, RemoveArc returns
+// true and the label of the arc removed.  If no arc exists from fr to to,
+// RemoveArc returns false, 0.
+func (g LabeledDirected) RemoveArc(fr, to NI) (ok bool, label LI) {
+	ok, x := g.HasArc(fr, to)
+	if !ok {
+		return
+	}
+	a := g.LabeledAdjacencyList
+	t := a[fr]
+	label = t[x].Label // return value
+	last := len(t) - 1
+	t[x] = t[last]
+	a[fr] = t[:last]
+	return
+}
+
+// RemoveArcLabel removes a single arc from node fr to node to with label l.
+//
+// Returns true if the specified arc is found and successfully removed,
+// false if the arc does not exist.
+func (g LabeledDirected) RemoveArcLabel(fr, to NI, l LI) (ok bool) {
+	ok, x := g.HasArcLabel(fr, to, l)
+	if !ok {
+		return
+	}
+	a := g.LabeledAdjacencyList
+	t := a[fr]
+	last := len(t) - 1
+	t[x] = t[last]
+	a[fr] = t[:last]
+	return
+}
+
 // SpanTree builds a tree spanning nodes reachable from the given root.
 //
 // The component is spanned by breadth-first search from root.
@@ -865,12 +1319,11 @@ func (g LabeledDirected) SpanTree(root NI, f *FromList, labels []LI) (nSpanned i
 // arcs in g (equal to the number of arcs in the result.)
 func (g LabeledDirected) Transpose() (t LabeledDirected, ma int) {
 	ta := make(LabeledAdjacencyList, g.Order())
-	for n, nbs := range g.LabeledAdjacencyList {
-		for _, nb := range nbs {
-			ta[nb.To] = append(ta[nb.To], Half{To: NI(n), Label: nb.Label})
-			ma++
-		}
-	}
+	g.LabeledAdjacencyList.Arcs(func(fr NI, _ int, to Half) bool {
+		ta[to.To] = append(ta[to.To], Half{To: fr, Label: to.Label})
+		ma++
+		return true
+	})
 	return LabeledDirected{ta}, ma
 }
 
@@ -1057,3 +1510,18 @@ func (e *labEulerian) push() {
 		u = w.To
 	}
 }
+
+// niHeap is a min-heap of NIs.
+type niHeap []NI
+
+// implement container/heap
+func (h niHeap) Len() int            { return len(h) }
+func (h niHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h niHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (p *niHeap) Push(x interface{}) { *p = append(*p, x.(NI)) }
+func (p *niHeap) Pop() interface{} {
+	h := *p
+	last := len(h) - 1
+	*p = h[:last]
+	return h[last]
+}
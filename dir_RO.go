@@ -4,6 +4,7 @@
 package graph
 
 import (
+	"container/heap"
 	"errors"
 	"fmt"
 
@@ -16,6 +17,35 @@ import (
 // The RO means read only and it is upper case RO to slow you down a bit
 // in case you start to edit the file.
 
+// ArcsOf calls emit for each arc of g to node to.
+//
+// ArcsOf finds arcs using the transpose of g.  If t is non-nil and holds an
+// adjacency list, it is taken to be the transpose of g.  Otherwise the
+// transpose is constructed, and if t is non-nil, it is stored in *t where it
+// can be supplied to subsequent calls.
+//
+// Arcs are visited in order of from-node, and for each from-node, in arc
+// list order.  The argument to emit is the from-node of the arc, with the arc
+// label in the case of a labeled graph.  Emit returns true to continue
+// iterating, false to stop.  ArcsOf returns false if iteration was stopped
+// by emit, true otherwise.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) ArcsOf(to NI, t *Directed, emit func(fr NI) bool) bool {
+	if t == nil {
+		t = &Directed{}
+	}
+	if t.AdjacencyList == nil {
+		*t, _ = g.Transpose()
+	}
+	for _, fr := range t.AdjacencyList[to] {
+		if !emit(fr) {
+			return false
+		}
+	}
+	return true
+}
+
 // Balanced returns true if for every node in g, in-degree equals out-degree.
 //
 // There are equivalent labeled and unlabeled versions of this method.
@@ -52,34 +82,38 @@ func (g Directed) Cyclic() (cyclic bool, fr NI, to NI) {
 	fr, to = -1, -1
 	temp := bits.New(len(a))
 	perm := bits.New(len(a))
-	var df func(int)
-	df = func(n int) {
-		switch {
-		case temp.Bit(n) == 1:
-			cyclic = true
-			return
-		case perm.Bit(n) == 1:
-			return
-		}
-		temp.SetBit(n, 1)
-		for _, nb := range a[n] {
-			df(int(nb))
-			if cyclic {
-				if fr < 0 {
-					fr, to = NI(n), nb
-				}
-				return
-			}
-		}
-		temp.SetBit(n, 0)
-		perm.SetBit(n, 1)
+	// depth first with an explicit stack.  temp marks nodes on the stack.
+	type frame struct {
+		n NI  // node
+		x int // index of next arc from n
 	}
+	var stack []frame
 	for n := range a {
 		if perm.Bit(n) == 1 {
 			continue
 		}
-		if df(n); cyclic { // short circuit as soon as a cycle is found
-			break
+		temp.SetBit(n, 1)
+		stack = append(stack[:0], frame{NI(n), 0})
+		for len(stack) > 0 {
+			d := len(stack) - 1
+			top := &stack[d]
+			if top.x == len(a[top.n]) {
+				temp.SetBit(int(top.n), 0)
+				perm.SetBit(int(top.n), 1)
+				stack = stack[:d]
+				continue
+			}
+			nb := a[top.n][top.x]
+			top.x++
+			switch {
+			case temp.Bit(int(nb)) == 1:
+				// short circuit as soon as a cycle is found
+				return true, top.n, nb
+			case perm.Bit(int(nb)) == 1:
+				continue
+			}
+			temp.SetBit(int(nb), 1)
+			stack = append(stack, frame{nb, 0})
 		}
 	}
 	return
@@ -488,6 +522,8 @@ type eulerian struct {
 	// high end is finished path
 	p []NI // stack + path
 	s int  // stack pointer
+	// for undirected graphs, remove reciprocal arcs preserving arc order
+	ordered bool
 }
 
 func newEulerian(g AdjacencyList, m int) *eulerian {
@@ -585,11 +621,10 @@ func (g Directed) MaximalNonBranchingPaths(emit func([]NI) bool) {
 // There are equivalent labeled and unlabeled versions of this method.
 func (g Directed) InDegree() []int {
 	ind := make([]int, g.Order())
-	for _, nbs := range g.AdjacencyList {
-		for _, nb := range nbs {
-			ind[nb]++
-		}
-	}
+	g.AdjacencyList.Arcs(func(_ NI, _ int, to NI) bool {
+		ind[to]++
+		return true
+	})
 	return ind
 }
 
@@ -715,6 +750,67 @@ func (g *Directed) InduceBits(t bits.Bits) *DirectedSubgraph {
 		}}
 }
 
+// LiftFromList translates a FromList of subgraph s to its supergraph.
+//
+// The result has a PathEnd for each node of s.Super.  Paths of f are placed
+// at the corresponding supergraph NIs, with From values also translated.
+// Supergraph nodes not in s get From -1 and Len 0.  Leaves, if set in f,
+// are translated as with LiftBits.  MaxLen is copied.
+func (s *DirectedSubgraph) LiftFromList(f FromList) FromList {
+	return liftFromList(s.SuperNI, s.Super.Order(), f)
+}
+
+// LiftDist translates a slice of per-node values of subgraph s, such as
+// a distance result, to its supergraph.
+//
+// The result has a value for each node of s.Super.  Supergraph nodes not in
+// s are given the value fill.
+func (s *DirectedSubgraph) LiftDist(d []float64, fill float64) []float64 {
+	return liftDist(s.SuperNI, s.Super.Order(), d, fill)
+}
+
+// LiftNodes translates a list of subgraph NIs to supergraph NIs.
+func (s *DirectedSubgraph) LiftNodes(l []NI) []NI {
+	return liftNodes(s.SuperNI, l)
+}
+
+// LiftBits translates a bitmap of subgraph nodes to a bitmap of supergraph
+// nodes.
+func (s *DirectedSubgraph) LiftBits(b bits.Bits) bits.Bits {
+	return liftBits(s.SuperNI, s.Super.Order(), b)
+}
+
+// PushBits translates a bitmap of supergraph nodes to a bitmap of subgraph
+// nodes.
+//
+// Supergraph nodes not in s are ignored.
+func (s *DirectedSubgraph) PushBits(super bits.Bits) bits.Bits {
+	return pushBits(s.SuperNI, super)
+}
+
+// IsOriented returns true if g is an oriented graph, a directed graph with
+// no loops and no pairs of reciprocal arcs.
+//
+// Parallel arcs in the same direction are allowed.  If g is not oriented,
+// IsOriented returns false and an example arc fr->to where either fr == to
+// or g also has an arc to->fr.  Labels are ignored.
+//
+// See also AdjacencyList.IsUndirected, which tests the opposite property,
+// that all arcs are in reciprocal pairs.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) IsOriented() (oriented bool, fr, to NI) {
+	x := g.AdjacencyList.BuildArcIndex()
+	for fr, to := range g.AdjacencyList {
+		for _, to := range to {
+			if x.HasArc(to, NI(fr)) {
+				return false, NI(fr), to
+			}
+		}
+	}
+	return true, -1, -1
+}
+
 // IsTree identifies trees in directed graphs.
 //
 // Return value isTree is true if the subgraph reachable from root is a tree.
@@ -726,21 +822,27 @@ func (g Directed) IsTree(root NI) (isTree, allTree bool) {
 	a := g.AdjacencyList
 	v := bits.New(len(a))
 	v.SetAll()
-	var df func(NI) bool
-	df = func(n NI) bool {
-		if v.Bit(int(n)) == 0 {
-			return false
+	v.SetBit(int(root), 0)
+	type frame struct {
+		n NI  // node
+		x int // index of next arc from n
+	}
+	stack := []frame{{root, 0}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.x == len(a[top.n]) {
+			stack = stack[:len(stack)-1]
+			continue
 		}
-		v.SetBit(int(n), 0)
-		for _, to := range a[n] {
-			if !df(to) {
-				return false
-			}
+		to := a[top.n][top.x]
+		top.x++
+		if v.Bit(int(to)) == 0 {
+			return false, false
 		}
-		return true
+		v.SetBit(int(to), 0)
+		stack = append(stack, frame{to, 0})
 	}
-	isTree = df(root)
-	return isTree, isTree && v.AllZeros()
+	return true, v.AllZeros()
 }
 
 // PageRank computes a significance score for each node of a graph.
@@ -860,10 +962,23 @@ func (g Directed) StronglyConnectedComponents(emit func([]NI) bool) {
 // A condensation represents a directed acyclic graph.
 // Components are ordered in a reverse topological ordering.
 //
-// See also StronglyConnectedComponents, which returns the components only.
+// See also StronglyConnectedComponents, which returns the components only,
+// and CondensationMap, which also returns the component of each node.
 //
 // There are equivalent labeled and unlabeled versions of this method.
 func (g Directed) Condensation() (scc [][]NI, cd AdjacencyList) {
+	scc, cd, _ = g.CondensationMap()
+	return
+}
+
+// CondensationMap returns strongly connected components, their
+// condensation graph, and the component of each node.
+//
+// Return values scc and cd are as for Condensation.  Node n of g is in
+// component cond[n], that is, node cond[n] of cd.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) CondensationMap() (scc [][]NI, cd AdjacencyList, cond []NI) {
 	a := g.AdjacencyList
 	b := make([]NI, len(a)) // backing slice for scc
 	g.StronglyConnectedComponents(func(c []NI) bool {
@@ -873,7 +988,7 @@ func (g Directed) Condensation() (scc [][]NI, cd AdjacencyList) {
 		return true
 	})
 	cd = make(AdjacencyList, len(scc)) // return value
-	cond := make([]NI, len(a))         // mapping from g node to cd node
+	cond = make([]NI, len(a))          // mapping from g node to cd node
 	for cn, c := range scc {
 		for _, n := range c {
 			cond[n] = NI(cn) // map g node to cd node
@@ -894,6 +1009,81 @@ func (g Directed) Condensation() (scc [][]NI, cd AdjacencyList) {
 	return
 }
 
+// Summarize computes overview statistics of g.
+//
+// Node degrees, loops, parallel arcs, and weakly connected components are
+// computed in a single pass over the arcs of g.  If strong is true, strongly
+// connected components and acyclicity are computed as well, taking a second
+// pass.  See DirectedSummary.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) Summarize(strong bool) DirectedSummary {
+	return g.summarize(strong, func() {})
+}
+
+// summarize implements Summarize, calling pass at the start of each pass
+// over the arcs of g.
+func (g Directed) summarize(strong bool, pass func()) (s DirectedSummary) {
+	a := g.AdjacencyList
+	s.Order = len(a)
+	if len(a) == 0 {
+		s.Simple = true
+		s.Strong = strong
+		s.DAG = strong
+		return
+	}
+	in := make([]int, len(a))
+	mark := make([]NI, len(a)) // value fr+1 marks nodes with arcs from fr
+	ds := newDisjointSet(len(a))
+	s.MinOutDegree = len(a[0])
+	pass()
+	for fr, to := range a {
+		s.ArcSize += len(to)
+		if len(to) < s.MinOutDegree {
+			s.MinOutDegree = len(to)
+		}
+		if len(to) > s.MaxOutDegree {
+			s.MaxOutDegree = len(to)
+		}
+		for _, to := range to {
+			if to == NI(fr) {
+				s.Loops++
+			}
+			if mark[to] == NI(fr)+1 {
+				s.Parallel = true
+			}
+			mark[to] = NI(fr) + 1
+			in[to]++
+			ds.union(NI(fr), to)
+		}
+	}
+	s.Simple = s.Loops == 0 && !s.Parallel
+	s.MinInDegree = in[0]
+	for _, d := range in {
+		if d < s.MinInDegree {
+			s.MinInDegree = d
+		}
+		if d > s.MaxInDegree {
+			s.MaxInDegree = d
+		}
+	}
+	s.MeanDegree = float64(s.ArcSize) / float64(len(a))
+	s.WeakComponents, s.LargestWeak = summaryComponents(ds)
+	if strong {
+		s.Strong = true
+		pass()
+		g.StronglyConnectedComponents(func(c []NI) bool {
+			s.StrongComponents++
+			if len(c) > s.LargestStrong {
+				s.LargestStrong = len(c)
+			}
+			return true
+		})
+		s.DAG = s.StrongComponents == len(a) && s.Loops == 0
+	}
+	return
+}
+
 // Topological computes a topological ordering of a directed acyclic graph.
 //
 // For an acyclic graph, return value ordering is a permutation of node numbers
@@ -919,41 +1109,12 @@ func (g Directed) dfTopo(f func() NI) (ordering, cycle []NI) {
 	i := len(ordering)
 	temp := bits.New(len(a))
 	perm := bits.New(len(a))
-	var cycleFound bool
-	var cycleStart NI
-	var df func(NI)
-	df = func(n NI) {
-		switch {
-		case temp.Bit(int(n)) == 1:
-			cycleFound = true
-			cycleStart = n
-			return
-		case perm.Bit(int(n)) == 1:
-			return
-		}
-		temp.SetBit(int(n), 1)
-		for _, nb := range a[n] {
-			df(nb)
-			if cycleFound {
-				if cycleStart >= 0 {
-					// a little hack: orderng won't be needed so repurpose the
-					// slice as cycle.  this is read out in reverse order
-					// as the recursion unwinds.
-					x := len(ordering) - 1 - len(cycle)
-					ordering[x] = n
-					cycle = ordering[x:]
-					if n == cycleStart {
-						cycleStart = -1
-					}
-				}
-				return
-			}
-		}
-		temp.SetBit(int(n), 0)
-		perm.SetBit(int(n), 1)
-		i--
-		ordering[i] = n
+	// depth first with an explicit stack.  temp marks nodes on the stack.
+	type frame struct {
+		n NI  // node
+		x int // index of next arc from n
 	}
+	var stack []frame
 	for {
 		n := f()
 		if n < 0 {
@@ -962,9 +1123,37 @@ func (g Directed) dfTopo(f func() NI) (ordering, cycle []NI) {
 		if perm.Bit(int(n)) == 1 {
 			continue
 		}
-		df(n)
-		if cycleFound {
-			return nil, cycle
+		temp.SetBit(int(n), 1)
+		stack = append(stack[:0], frame{n, 0})
+		for len(stack) > 0 {
+			d := len(stack) - 1
+			top := &stack[d]
+			if top.x == len(a[top.n]) {
+				temp.SetBit(int(top.n), 0)
+				perm.SetBit(int(top.n), 1)
+				i--
+				ordering[i] = top.n
+				stack = stack[:d]
+				continue
+			}
+			nb := a[top.n][top.x]
+			top.x++
+			switch {
+			case temp.Bit(int(nb)) == 1:
+				// the cycle is the stack from nb to the top
+				j := d
+				for stack[j].n != nb {
+					j--
+				}
+				for _, fr := range stack[j:] {
+					cycle = append(cycle, fr.n)
+				}
+				return nil, cycle
+			case perm.Bit(int(nb)) == 1:
+				continue
+			}
+			temp.SetBit(int(nb), 1)
+			stack = append(stack, frame{nb, 0})
 		}
 	}
 }
@@ -1030,6 +1219,46 @@ func (g Directed) TopologicalKahn(tr Directed) (ordering, cycle []NI) {
 	return L, nil
 }
 
+// TopologicalLex computes the lexicographically smallest topological
+// ordering of a directed acyclic graph.
+//
+// Of all topological orderings, the one returned is the least when
+// compared node by node.  Unlike Topological and TopologicalKahn, the result
+// does not depend on the order of arcs in to-lists.  The algorithm is Kahn's
+// algorithm, taking ready nodes from a min-heap.  Time is O(m + n log n) for
+// n nodes and m arcs.
+//
+// For an acyclic graph, return value ordering is the topological ordering
+// and cycle will be nil.  If the graph is found to be cyclic, ordering will
+// be nil and cycle will be the path of a found cycle, as returned by
+// Topological.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) TopologicalLex() (ordering, cycle []NI) {
+	rem := g.InDegree()
+	var ready niHeap
+	for n, in := range rem {
+		if in == 0 {
+			ready = append(ready, NI(n))
+		}
+	}
+	// nodes in increasing order are already a heap
+	ordering = make([]NI, 0, len(rem))
+	for len(ready) > 0 {
+		n := heap.Pop(&ready).(NI)
+		ordering = append(ordering, n)
+		for _, m := range g.AdjacencyList[n] {
+			if rem[m]--; rem[m] == 0 {
+				heap.Push(&ready, m)
+			}
+		}
+	}
+	if len(ordering) < len(rem) {
+		return g.Topological()
+	}
+	return ordering, nil
+}
+
 // TopologicalSubgraph computes a topological ordering of a subgraph of a
 // directed acyclic graph.
 //
@@ -1089,3 +1318,115 @@ func (g Directed) TransitiveClosure() []bits.Bits {
 	}
 	return t
 }
+
+// TransitiveReduction returns the transitive reduction of a directed acyclic
+// graph.
+//
+// The transitive reduction is the unique graph with the fewest arcs having
+// the same reachability relation as g.  It has an arc fr->to for each arc
+// of g where to is not reachable from fr by any longer path.  Parallel arcs
+// are reduced to the first arc in the arc list.  Returned graph r has the
+// same order as g and the arcs of r are in the same order as in g.
+//
+// If g is cyclic, the transitive reduction is not unique and an error is
+// returned.  The graph returned by Condensation is acyclic and can be
+// reduced.
+//
+// The algorithm computes reachability of each node in reverse topological
+// order.  It uses O(n^2) bits of memory for a graph of order n.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) TransitiveReduction() (r Directed, err error) {
+	a := g.AdjacencyList
+	ordering, cycle := g.Topological()
+	if cycle != nil {
+		return r, fmt.Errorf("graph is cyclic: %v", cycle)
+	}
+	// reach[n] is the set of nodes reachable from n by one or more arcs
+	reach := make([]bits.Bits, len(a))
+	indirect := bits.New(len(a)) // reachable from fr by two or more arcs
+	rl := make(AdjacencyList, len(a))
+	for i := len(ordering) - 1; i >= 0; i-- {
+		fr := ordering[i]
+		rf := bits.New(len(a))
+		indirect.ClearAll()
+		for _, to := range a[fr] {
+			rf.SetBit(int(to), 1)
+			indirect.Or(indirect, reach[to])
+		}
+		rf.Or(rf, indirect)
+		reach[fr] = rf
+		for _, to := range a[fr] {
+			if indirect.Bit(int(to)) == 0 {
+				rl[fr] = append(rl[fr], to)
+				indirect.SetBit(int(to), 1) // drop parallel arcs
+			}
+		}
+	}
+	return Directed{rl}, nil
+}
+
+// WeaklyConnectedComponentInts returns a list of weakly connected component
+// numbers (ints) for each node of graph g.
+//
+// Weakly connected components are the connected components of g with arcs
+// taken as undirected.  Components are found with a union-find over the arcs
+// of g, without constructing reciprocal arcs, so memory is O(n) beyond g
+// itself.  The results are the same as those of
+// g.Undirected().ConnectedComponentInts():  The method returns the number of
+// components nc and a list of component numbers ci for each node.  Component
+// numbers run from 1 to nc, numbered in order of the lowest node of each
+// component.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+//
+// See also WeaklyConnectedComponentLists.
+func (g Directed) WeaklyConnectedComponentInts() (ci []int, nc int) {
+	a := g.AdjacencyList
+	ds := newDisjointSet(len(a))
+	for fr, to := range a {
+		for _, to := range to {
+			ds.union(NI(fr), to)
+		}
+	}
+	// number components by lowest node
+	ci = make([]int, len(a))
+	for n := range a {
+		r := ds.find(NI(n))
+		if ci[r] == 0 {
+			nc++
+			ci[r] = nc
+		}
+		ci[n] = ci[r]
+	}
+	return
+}
+
+// WeaklyConnectedComponentLists returns a function that iterates over weakly
+// connected components of g, returning the member list of each.
+//
+// Each call of the returned function returns a node list of a weakly
+// connected component and the arc size of the component, the number of arcs
+// from nodes of the component.  Components are returned in the order
+// numbered by WeaklyConnectedComponentInts, and node lists are in increasing
+// order.  The returned function returns nil, 0 after returning all
+// components.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g Directed) WeaklyConnectedComponentLists() func() (nodes []NI, arcSize int) {
+	ci, nc := g.WeaklyConnectedComponentInts()
+	lists := make([][]NI, nc)
+	ma := make([]int, nc)
+	for n, c := range ci {
+		lists[c-1] = append(lists[c-1], NI(n))
+		ma[c-1] += len(g.AdjacencyList[n])
+	}
+	c := 0
+	return func() ([]NI, int) {
+		if c == nc {
+			return nil, 0
+		}
+		c++
+		return lists[c-1], ma[c-1]
+	}
+}
@@ -13,6 +13,7 @@ package graph_test
 import (
 	"fmt"
 	"log"
+	"math"
 	"math/big"
 	"os"
 	"text/template"
@@ -621,6 +622,45 @@ func ExampleLabeledDirected_Condensation() {
 	// 3 [2 1]
 }
 
+func ExampleLabeledDirected_CondensationMap() {
+	// input:          condensation:
+	// /---0---\      <->  /---3
+	// |   |\--/           |   |
+	// |   v               |   v
+	// |   5<=>4---\  <->  |   2--\
+	// |   |   |   |       |   |  |
+	// v   v   |   |       |   v  |
+	// 7<=>6   |   |  <->  \-->1  |
+	//     |   v   v           |  v
+	//     \-->3<--2  <->      \->0
+	//         |   ^
+	//         |   |
+	//         \-->1
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 0}, {To: 5}, {To: 7}},
+		5: {{To: 4}, {To: 6}},
+		4: {{To: 5}, {To: 2}, {To: 3}},
+		7: {{To: 6}},
+		6: {{To: 7}, {To: 3}},
+		3: {{To: 1}},
+		1: {{To: 2}},
+		2: {{To: 3}},
+	}}
+	_, cd, cond := g.CondensationMap()
+	for n, c := range cond {
+		fmt.Println("node", n, "component", c, "arcs to", cd[c])
+	}
+	// Output:
+	// node 0 component 3 arcs to [2 1]
+	// node 1 component 0 arcs to []
+	// node 2 component 0 arcs to []
+	// node 3 component 0 arcs to []
+	// node 4 component 2 arcs to [0 1]
+	// node 5 component 2 arcs to [0 1]
+	// node 6 component 1 arcs to [0]
+	// node 7 component 1 arcs to [0]
+}
+
 func ExampleLabeledDirected_Topological() {
 	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
 		1: {{To: 2}},
@@ -652,6 +692,34 @@ func ExampleLabeledDirected_TopologicalKahn() {
 	// [] [1 2 3]
 }
 
+func ExampleLabeledDirected_TopologicalLex() {
+	// diamond, arcs directed down
+	//   0
+	//  / \
+	// 1   2
+	//  \ /
+	//   3
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 2}, {To: 1}},
+		1: {{To: 3}},
+		2: {{To: 3}},
+		3: {},
+	}}
+	fmt.Println(g.Topological())
+	fmt.Println(g.TopologicalLex())
+	g.LabeledAdjacencyList[0] = []graph.Half{{To: 1}, {To: 2}}
+	fmt.Println(g.Topological())
+	fmt.Println(g.TopologicalLex())
+	g.LabeledAdjacencyList[3] = []graph.Half{{To: 0}}
+	fmt.Println(g.TopologicalLex())
+	// Output:
+	// [0 1 2 3] []
+	// [0 1 2 3] []
+	// [0 2 1 3] []
+	// [0 1 2 3] []
+	// [] [0 1 3]
+}
+
 func ExampleLabeledDirected_TopologicalSubgraph() {
 	// arcs directected down unless otherwise indicated
 	// 0       1<-\
@@ -714,6 +782,31 @@ func ExampleLabeledDirected_TransitiveClosure() {
 	// 8: 0 0 0 0 0 1 1 1 1
 }
 
+func ExampleLabeledDirected_TransitiveReduction() {
+	//   0--a-->1--b-->2
+	//    \____c_____^
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{1, 'a'}, {2, 'c'}},
+		1: {{2, 'b'}},
+		2: {},
+	}}
+	r, err := g.TransitiveReduction()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for fr, to := range r.LabeledAdjacencyList {
+		fmt.Print(fr, ":")
+		for _, h := range to {
+			fmt.Printf(" --%c--> %d", h.Label, h.To)
+		}
+		fmt.Println()
+	}
+	// Output:
+	// 0: --a--> 1
+	// 1: --b--> 2
+	// 2:
+}
+
 func ExampleLabeledDirectedSubgraph_AddNode() {
 	// supergraph:
 	//    0
@@ -836,3 +929,93 @@ func ExampleLabeledDirectedSubgraph_AddArc_panic() {
 	// AddArc: NI -1 not in supergraph
 	// AddArc: NI 3 not in supergraph
 }
+
+func ExampleLabeledDirectedSubgraph_LiftFromList() {
+	// arcs directed down, weights in parens:
+	//      0
+	//  (1)/ \(5)
+	//    1   2
+	//  (1)\ /(1)
+	//      3
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{1, 1}, {2, 5}},
+		1: {{3, 1}},
+		2: {{3, 1}},
+		3: {},
+	}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	// shortest paths avoiding node 1
+	s := g.InduceBits(bits.NewGivens(0, 2, 3))
+	f, _, d, _ := s.Dijkstra(s.SubNI[0], -1, w)
+	lf := s.LiftFromList(f)
+	ld := s.LiftDist(d, math.Inf(1))
+	for n := range lf.Paths {
+		fmt.Println(n, lf.PathTo(graph.NI(n), nil), ld[n])
+	}
+	// Output:
+	// 0 [0] 0
+	// 1 [] +Inf
+	// 2 [0 2] 5
+	// 3 [0 2 3] 6
+}
+
+func ExampleLabeledDirectedSubgraph_PushBits() {
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{1, -1}, {2, -1}},
+		1: {{3, -1}},
+		2: {{3, -1}},
+		3: {},
+	}}
+	s := g.InduceList([]graph.NI{3, 2, 1})
+	b := s.PushBits(bits.NewGivens(0, 1, 3))
+	fmt.Println("sub:  ", b)
+	fmt.Println("super:", s.LiftBits(b))
+	fmt.Println("nodes:", s.LiftNodes([]graph.NI{0, 2}))
+	// Output:
+	// sub:   101
+	// super: 1010
+	// nodes: [3 1]
+}
+
+func ExampleLabeledDirected_WeaklyConnectedComponentInts() {
+	//    0   1   2
+	//   / ^   \
+	//  v   \   v
+	//  3--->4   5
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 3}},
+		1: {{To: 5}},
+		3: {{To: 4}},
+		4: {{To: 0}},
+		5: {},
+	}}
+	fmt.Println(g.WeaklyConnectedComponentInts())
+	// Output:
+	// [1 2 3 1 1 2] 3
+}
+
+func ExampleLabeledDirected_WeaklyConnectedComponentLists() {
+	//    0   1   2
+	//   / ^   \
+	//  v   \   v
+	//  3--->4   5
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 3}},
+		1: {{To: 5}},
+		3: {{To: 4}},
+		4: {{To: 0}},
+		5: {},
+	}}
+	f := g.WeaklyConnectedComponentLists()
+	for {
+		l, ma := f()
+		if l == nil {
+			break
+		}
+		fmt.Println(l, ma)
+	}
+	// Output:
+	// [0 3 4] 3
+	// [1 5] 1
+	// [2] 0
+}
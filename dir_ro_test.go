@@ -17,7 +17,9 @@ package graph_test
 import (
 	"fmt"
 	"math/big"
+	"math/rand"
 	"os"
+	"reflect"
 	"testing"
 	"text/template"
 
@@ -475,6 +477,21 @@ func ExampleDirected_InduceList() {
 	//   3         2
 }
 
+func ExampleDirected_IsOriented() {
+	// 0-->1<==>2
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {1},
+	}}
+	fmt.Println(g.IsOriented())
+	g.RemoveArc(2, 1)
+	fmt.Println(g.IsOriented())
+	// Output:
+	// false 1 2
+	// true -1 -1
+}
+
 func ExampleDirected_IsTree() {
 	// Example graph
 	// Arcs point down unless otherwise indicated
@@ -640,6 +657,84 @@ func ExampleDirected_Condensation() {
 	// 3 [2 1]
 }
 
+func ExampleDirected_CondensationMap() {
+	// input:          condensation:
+	// /---0---\      <->  /---3
+	// |   |\--/           |   |
+	// |   v               |   v
+	// |   5<=>4---\  <->  |   2--\
+	// |   |   |   |       |   |  |
+	// v   v   |   |       |   v  |
+	// 7<=>6   |   |  <->  \-->1  |
+	//     |   v   v           |  v
+	//     \-->3<--2  <->      \->0
+	//         |   ^
+	//         |   |
+	//         \-->1
+	g := graph.Directed{graph.AdjacencyList{
+		0: {0, 5, 7},
+		5: {4, 6},
+		4: {5, 2, 3},
+		7: {6},
+		6: {7, 3},
+		3: {1},
+		1: {2},
+		2: {3},
+	}}
+	_, cd, cond := g.CondensationMap()
+	for n, c := range cond {
+		fmt.Println("node", n, "component", c, "arcs to", cd[c])
+	}
+	// Output:
+	// node 0 component 3 arcs to [2 1]
+	// node 1 component 0 arcs to []
+	// node 2 component 0 arcs to []
+	// node 3 component 0 arcs to []
+	// node 4 component 2 arcs to [0 1]
+	// node 5 component 2 arcs to [0 1]
+	// node 6 component 1 arcs to [0]
+	// node 7 component 1 arcs to [0]
+}
+
+func ExampleDirected_Summarize() {
+	// /---0---\
+	// |   |\--/
+	// |   v
+	// |   5<=>4---\
+	// |   |   |   |
+	// v   v   |   |
+	// 7<=>6   |   |
+	//     |   v   v
+	//     \-->3<--2
+	//         |   ^
+	//         |   |
+	//         \-->1
+	g := graph.Directed{graph.AdjacencyList{
+		0: {0, 5, 7},
+		5: {4, 6},
+		4: {5, 2, 3},
+		7: {6},
+		6: {7, 3},
+		3: {1},
+		1: {2},
+		2: {3},
+	}}
+	fmt.Print(g.Summarize(true))
+	// Output:
+	// order:               8
+	// arc size:            14
+	// loops:               1
+	// parallel arcs:       false
+	// simple:              false
+	// weak components:     1
+	// largest weak:        8
+	// out-degree:          min 1, mean 1.75, max 3
+	// in-degree:           min 1, mean 1.75, max 3
+	// strong components:   4
+	// largest strong:      3
+	// DAG:                 false
+}
+
 func ExampleDirected_Topological() {
 	g := graph.Directed{graph.AdjacencyList{
 		1: {2},
@@ -671,6 +766,74 @@ func ExampleDirected_TopologicalKahn() {
 	// [] [1 2 3]
 }
 
+func ExampleDirected_TopologicalLex() {
+	// diamond, arcs directed down
+	//   0
+	//  / \
+	// 1   2
+	//  \ /
+	//   3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {2, 1},
+		1: {3},
+		2: {3},
+		3: {},
+	}}
+	fmt.Println(g.Topological())
+	fmt.Println(g.TopologicalLex())
+	g.AdjacencyList[0] = []graph.NI{1, 2}
+	fmt.Println(g.Topological())
+	fmt.Println(g.TopologicalLex())
+	g.AdjacencyList[3] = []graph.NI{0}
+	fmt.Println(g.TopologicalLex())
+	// Output:
+	// [0 1 2 3] []
+	// [0 1 2 3] []
+	// [0 2 1 3] []
+	// [0 1 2 3] []
+	// [] [0 1 3]
+}
+
+// TopologicalLex must match repeatedly taking the least node with no
+// remaining arcs in.
+func TestTopologicalLex(t *testing.T) {
+	r := rand.New(rand.NewSource(36))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(30)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		perm := r.Perm(n)
+		for m := r.Intn(3 * n); m > 0; m-- {
+			a, b := r.Intn(n), r.Intn(n)
+			if a > b {
+				a, b = b, a
+			}
+			if a < b {
+				fr, to := graph.NI(perm[a]), graph.NI(perm[b])
+				g.AdjacencyList[fr] = append(g.AdjacencyList[fr], to)
+			}
+		}
+		in := g.InDegree()
+		done := make([]bool, n)
+		var want []graph.NI
+		for len(want) < n {
+			for v := 0; v < n; v++ {
+				if !done[v] && in[v] == 0 {
+					done[v] = true
+					want = append(want, graph.NI(v))
+					for _, to := range g.AdjacencyList[v] {
+						in[to]--
+					}
+					break
+				}
+			}
+		}
+		got, cycle := g.TopologicalLex()
+		if cycle != nil || !reflect.DeepEqual(got, want) {
+			t.Fatal(i, got, cycle, "want", want)
+		}
+	}
+}
+
 func ExampleDirected_TopologicalSubgraph() {
 	// arcs directected down unless otherwise indicated
 	// 0       1<-\
@@ -733,6 +896,85 @@ func ExampleDirected_TransitiveClosure() {
 	// 8: 0 0 0 0 0 1 1 1 1
 }
 
+func ExampleDirected_TransitiveReduction() {
+	//   0-->1-->2-->3
+	//   |\______^   ^
+	//    \_________/
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2, 3},
+		1: {2},
+		2: {3},
+		3: {},
+	}}
+	r, err := g.TransitiveReduction()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range r.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	g.AdjacencyList[3] = []graph.NI{1}
+	_, err = g.TransitiveReduction()
+	fmt.Println(err)
+	// Output:
+	// 0 [1]
+	// 1 [2]
+	// 2 [3]
+	// 3 []
+	// graph is cyclic: [1 2 3]
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	rnd := rand.New(rand.NewSource(101))
+	for i := 0; i < 100; i++ {
+		// random DAG, arcs from lower to higher node numbers, then permuted
+		n := 1 + rnd.Intn(12)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for j := rnd.Intn(n * n); j > 0; j-- {
+			fr := rnd.Intn(n)
+			to := rnd.Intn(n)
+			if fr < to {
+				g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(to))
+			}
+		}
+		g.Permute(rnd.Perm(n))
+		r, err := g.TransitiveReduction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// same closure
+		tc := g.TransitiveClosure()
+		same := func(c []bits.Bits) bool {
+			for n, b := range c {
+				if !b.Equal(tc[n]) {
+					return false
+				}
+			}
+			return true
+		}
+		if !same(r.TransitiveClosure()) {
+			t.Fatal(g.AdjacencyList, r.AdjacencyList, "closure differs")
+		}
+		// minimal:  removing any arc changes the closure.
+		// also arcs of r must be arcs of g.
+		for fr, to := range r.AdjacencyList {
+			for x, nd := range to {
+				if has, _ := g.HasArc(graph.NI(fr), nd); !has {
+					t.Fatal("arc", fr, nd, "not in g")
+				}
+				c, _ := r.Copy()
+				ct := c.AdjacencyList[fr]
+				c.AdjacencyList[fr] = append(ct[:x:x], ct[x+1:]...)
+				if same(c.TransitiveClosure()) {
+					t.Fatal(g.AdjacencyList, r.AdjacencyList, "arc", fr, nd,
+						"redundant")
+				}
+			}
+		}
+	}
+}
+
 func ExampleDirectedSubgraph_AddNode() {
 	// supergraph:
 	//    0
@@ -854,3 +1096,92 @@ func ExampleDirectedSubgraph_AddArc_panic() {
 	// AddArc: NI -1 not in supergraph
 	// AddArc: NI 3 not in supergraph
 }
+
+func ExampleDirectedSubgraph_LiftFromList() {
+	// arcs directed down:
+	//    0
+	//   / \
+	//  1   2
+	//   \ /
+	//    3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {3},
+		2: {3},
+		3: {},
+	}}
+	// spanning tree avoiding node 1
+	s := g.InduceBits(bits.NewGivens(0, 2, 3))
+	f := graph.NewFromList(s.Order())
+	s.SpanTree(s.SubNI[0], &f)
+	lf := s.LiftFromList(f)
+	for n := range lf.Paths {
+		fmt.Println(n, lf.PathTo(graph.NI(n), nil))
+	}
+	// Output:
+	// 0 [0]
+	// 1 []
+	// 2 [0 2]
+	// 3 [0 2 3]
+}
+
+func ExampleDirectedSubgraph_PushBits() {
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {3},
+		2: {3},
+		3: {},
+	}}
+	s := g.InduceList([]graph.NI{3, 2, 1})
+	b := s.PushBits(bits.NewGivens(0, 1, 3))
+	fmt.Println("sub:  ", b)
+	fmt.Println("super:", s.LiftBits(b))
+	fmt.Println("nodes:", s.LiftNodes([]graph.NI{0, 2}))
+	// Output:
+	// sub:   101
+	// super: 1010
+	// nodes: [3 1]
+}
+
+func ExampleDirected_WeaklyConnectedComponentInts() {
+	//    0   1   2
+	//   / ^   \
+	//  v   \   v
+	//  3--->4   5
+	g := graph.Directed{graph.AdjacencyList{
+		0: {3},
+		1: {5},
+		3: {4},
+		4: {0},
+		5: {},
+	}}
+	fmt.Println(g.WeaklyConnectedComponentInts())
+	// Output:
+	// [1 2 3 1 1 2] 3
+}
+
+func ExampleDirected_WeaklyConnectedComponentLists() {
+	//    0   1   2
+	//   / ^   \
+	//  v   \   v
+	//  3--->4   5
+	g := graph.Directed{graph.AdjacencyList{
+		0: {3},
+		1: {5},
+		3: {4},
+		4: {0},
+		5: {},
+	}}
+	f := g.WeaklyConnectedComponentLists()
+	for {
+		l, ma := f()
+		if l == nil {
+			break
+		}
+		fmt.Println(l, ma)
+	}
+	// Output:
+	// [0 3 4] 3
+	// [1 5] 1
+	// [2] 0
+}
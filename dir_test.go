@@ -6,12 +6,29 @@ package graph_test
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"reflect"
 	"testing"
 
 	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/graphtest"
 )
 
+func ExampleDirected_AddArc() {
+	var g graph.Directed
+	g.AddArc(0, 2)
+	g.AddArc(2, 1)
+	g.AddArc(2, 1) // parallel
+	g.AddArc(1, 1) // loop
+	for fr, to := range g.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [2]
+	// 1 [1]
+	// 2 [1 1]
+}
+
 func ExampleDirected_Cycles() {
 	// 0-->1--->2-\
 	// ^  ^^\  ^|  v
@@ -78,16 +95,21 @@ func TestCycles(t *testing.T) {
 		{2, 3, 6, 4, 5},
 		{8, 9},
 	}
-	i := 0
+	var ws, gs graph.CycleSet
+	for _, c := range want {
+		ws.Add(c)
+	}
 	g.Cycles(func(c []graph.NI) bool {
-		if !reflect.DeepEqual(c, want[i]) {
-			t.Fatalf("cycle %d, got %d, want %d", i, c, want[i])
+		if !ws.Contains(c) {
+			t.Fatalf("unexpected cycle %d", c)
+		}
+		if !gs.Add(c) {
+			t.Fatalf("duplicate cycle %d", c)
 		}
-		i++
 		return true
 	})
-	if i != len(want) {
-		t.Fatalf("only %d cycles.  want %d.", i, len(want))
+	if gs.Len() != len(want) {
+		t.Fatalf("only %d cycles.  want %d.", gs.Len(), len(want))
 	}
 }
 
@@ -136,6 +158,341 @@ func ExampleDirected_FromList() {
 	// 4   -1
 }
 
+func ExampleDigraphicalSequence() {
+	fmt.Println(graph.DigraphicalSequence([]int{1, 1, 1}, []int{1, 1, 1}))
+	fmt.Println(graph.DigraphicalSequence([]int{0, 2}, []int{2, 0}))
+	// Output:
+	// true
+	// false
+}
+
+// TestDigraphicalSequence compares DigraphicalSequence to sequences of
+// all simple digraphs on up to 3 nodes.
+func TestDigraphicalSequence(t *testing.T) {
+	for n := 1; n <= 3; n++ {
+		var arcs [][2]int
+		for fr := 0; fr < n; fr++ {
+			for to := 0; to < n; to++ {
+				if fr != to {
+					arcs = append(arcs, [2]int{fr, to})
+				}
+			}
+		}
+		real := map[string]bool{}
+		for m := 0; m < 1<<uint(len(arcs)); m++ {
+			in := make([]int, n)
+			out := make([]int, n)
+			for i, a := range arcs {
+				if m&(1<<uint(i)) != 0 {
+					out[a[0]]++
+					in[a[1]]++
+				}
+			}
+			real[fmt.Sprint(in, out)] = true
+		}
+		// all sequences with degrees 0..n
+		in := make([]int, n)
+		out := make([]int, n)
+		var gen func(i int)
+		gen = func(i int) {
+			if i == 2*n {
+				if got, want := graph.DigraphicalSequence(in, out), real[fmt.Sprint(in, out)]; got != want {
+					t.Fatal(in, out, "got", got, "want", want)
+				}
+				return
+			}
+			for d := 0; d <= n; d++ {
+				if i < n {
+					in[i] = d
+				} else {
+					out[i-n] = d
+				}
+				gen(i + 1)
+			}
+		}
+		gen(0)
+	}
+}
+
+func TestIsOriented(t *testing.T) {
+	r := rand.New(rand.NewSource(50))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(8)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for m := r.Intn(2 * n); m > 0; m-- {
+			g.AddArc(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		want := true
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				if has, _ := g.HasArc(to, graph.NI(fr)); has {
+					want = false
+				}
+			}
+		}
+		ok, fr, to := g.IsOriented()
+		if ok != want {
+			t.Fatal(i, ok, "want", want, g.AdjacencyList)
+		}
+		if !ok {
+			h1, _ := g.HasArc(fr, to)
+			h2, _ := g.HasArc(to, fr)
+			if !h1 || !h2 {
+				t.Fatal(i, "arc", fr, to, g.AdjacencyList)
+			}
+		}
+	}
+}
+
+func TestIsUndirectedMultiplicity(t *testing.T) {
+	// parallel edge with one reciprocal missing
+	g := graph.Undirected{graph.AdjacencyList{
+		0: {1, 1, 0},
+		1: {0},
+	}}
+	if u, fr, to := g.IsUndirected(); u || fr != 0 || to != 1 {
+		t.Fatal(u, fr, to)
+	}
+	g.AdjacencyList[1] = append(g.AdjacencyList[1], 0)
+	if u, _, _ := g.IsUndirected(); !u {
+		t.Fatal("reciprocal pairs not recognized")
+	}
+	// labels must match
+	l := graph.LabeledUndirected{graph.LabeledAdjacencyList{
+		0: {{1, 5}, {1, 6}},
+		1: {{0, 6}, {0, 7}},
+	}}
+	if u, fr, to := l.IsUndirected(); u || fr != 0 || to != (graph.Half{1, 5}) {
+		t.Fatal(u, fr, to)
+	}
+}
+
+func ExampleDirected_RemoveArc() {
+	//    0
+	//   / ^
+	//  v   \\
+	//  1--->2--\
+	//        ^-/
+	var g graph.Directed
+	g.AddArc(0, 1)
+	g.AddArc(1, 2)
+	g.AddArc(2, 0)
+	g.AddArc(2, 0) // parallel
+	g.AddArc(2, 2) // loop
+
+	fmt.Println(g.RemoveArc(2, 0)) // remove one of the parallel arcs
+	fmt.Println(g.RemoveArc(2, 2)) // remove the loop
+	fmt.Println(g.RemoveArc(0, 2)) // false: no arc in that direction
+
+	for fr, to := range g.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	fmt.Println("arc size:", g.ArcSize())
+	// Output:
+	// true
+	// true
+	// false
+	// 0 [1]
+	// 1 [2]
+	// 2 [0]
+	// arc size: 3
+}
+
+func TestRemoveArc(t *testing.T) {
+	r := rand.New(rand.NewSource(43))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(8)
+		g := graph.LabeledDirected{make(graph.LabeledAdjacencyList, n)}
+		u := graph.LabeledUndirected{make(graph.LabeledAdjacencyList, n)}
+		gu := graph.Directed{make(graph.AdjacencyList, n)}
+		uu := graph.Undirected{make(graph.AdjacencyList, n)}
+		var arcs []graph.LabeledEdge
+		for m := r.Intn(20); m > 0; m-- {
+			e := graph.LabeledEdge{graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(r.Intn(3))}
+			arcs = append(arcs, e)
+			g.AddArc(e.N1, graph.Half{e.N2, e.LI})
+			gu.AddArc(e.N1, e.N2)
+			u.AddEdge(e.Edge, e.LI)
+			uu.AddEdge(e.N1, e.N2)
+		}
+		// remove in random order, checking sizes remain consistent
+		r.Shuffle(len(arcs), func(i, j int) { arcs[i], arcs[j] = arcs[j], arcs[i] })
+		for x, e := range arcs {
+			if !g.RemoveArcLabel(e.N1, e.N2, e.LI) || !gu.RemoveArc(e.N1, e.N2) {
+				t.Fatal(i, "arc", e, "not removed")
+			}
+			if ok, _ := u.RemoveEdge(e.N1, e.N2); !ok || !uu.RemoveEdge(e.N1, e.N2) {
+				t.Fatal(i, "edge", e, "not removed")
+			}
+			want := len(arcs) - x - 1
+			if g.ArcSize() != want || gu.ArcSize() != want ||
+				u.Size() != want || uu.Size() != want {
+				t.Fatal(i, "sizes", g.ArcSize(), gu.ArcSize(), u.Size(), uu.Size(),
+					"want", want)
+			}
+		}
+		if ok, _ := g.RemoveArc(0, 0); ok || gu.RemoveArc(0, 0) {
+			t.Fatal(i, "removed from empty graph")
+		}
+	}
+}
+
+func ExampleDirected_RenumberBySCC() {
+	// same graph as Condensation example
+	g := graph.Directed{graph.AdjacencyList{
+		0: {0, 5, 7},
+		5: {4, 6},
+		4: {5, 2, 3},
+		7: {6},
+		6: {7, 3},
+		3: {1},
+		1: {2},
+		2: {3},
+	}}
+	r, perm, blocks := g.RenumberBySCC()
+	fmt.Println("perm:  ", perm)
+	fmt.Println("blocks:", blocks)
+	for fr, to := range r.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// perm:   [0 6 7 5 1 2 4 3]
+	// blocks: [0 1 3 5]
+	// 0 [0 2 3]
+	// 1 [2 7 5]
+	// 2 [1 4]
+	// 3 [4]
+	// 4 [3 5]
+	// 5 [6]
+	// 6 [7]
+	// 7 [5]
+}
+
+func TestRenumberBySCC(t *testing.T) {
+	rnd := rand.New(rand.NewSource(97))
+	for i := 0; i < 100; i++ {
+		n := 1 + rnd.Intn(30)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for a := rnd.Intn(2 * n); a > 0; a-- {
+			fr := rnd.Intn(n)
+			g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(rnd.Intn(n)))
+		}
+		r, perm, blocks := g.RenumberBySCC()
+		// perm is a permutation and maps arcs of g to arcs of r
+		seen := make([]bool, n)
+		for _, p := range perm {
+			if p < 0 || int(p) >= n || seen[p] {
+				t.Fatal("invalid permutation", perm)
+			}
+			seen[p] = true
+		}
+		for fr, to := range g.AdjacencyList {
+			if len(r.AdjacencyList[perm[fr]]) != len(to) {
+				t.Fatal("arc list of", fr, "not preserved")
+			}
+			for x, to := range to {
+				if r.AdjacencyList[perm[fr]][x] != perm[to] {
+					t.Fatal("arc", fr, to, "not preserved")
+				}
+			}
+		}
+		// blocks partition r into the SCCs of r
+		if len(blocks) == 0 || blocks[0] != 0 {
+			t.Fatal("blocks", blocks)
+		}
+		block := make([]int, n)
+		for b, s := range blocks {
+			e := n
+			if b+1 < len(blocks) {
+				e = blocks[b+1]
+			}
+			if s >= e {
+				t.Fatal("empty block", b, blocks)
+			}
+			for nd := s; nd < e; nd++ {
+				block[nd] = b
+			}
+		}
+		scc, _ := r.Condensation()
+		if err := graphtest.CheckPartition(n, scc); err != nil {
+			t.Fatal(err)
+		}
+		if len(scc) != len(blocks) {
+			t.Fatal(len(scc), "components,", len(blocks), "blocks")
+		}
+		for _, c := range scc {
+			for _, nd := range c {
+				if block[nd] != block[c[0]] {
+					t.Fatal("component", c, "spans blocks")
+				}
+			}
+		}
+		// arcs never lead to an earlier block
+		for fr, to := range r.AdjacencyList {
+			for _, to := range to {
+				if block[to] < block[fr] {
+					t.Fatal("arc", fr, to, "leads to earlier block")
+				}
+			}
+		}
+	}
+}
+
+func ExampleDirected_SimplifyPreservingDegrees() {
+	// 0 has a loop and parallel arcs to 1
+	g := graph.Directed{graph.AdjacencyList{
+		0: {0, 1, 1},
+		1: {2},
+		2: {0, 3},
+		3: {2},
+	}}
+	s, ok := g.SimplifyPreservingDegrees(rand.New(rand.NewSource(1)), 100)
+	fmt.Println(ok)
+	fmt.Println(reflect.DeepEqual(s.InDegree(), g.InDegree()))
+	fmt.Println(s.IsSimple())
+	// Output:
+	// true
+	// true
+	// true -1
+}
+
+func TestSimplifyPreservingDegrees(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 50; i++ {
+		// random multigraph with loops
+		n := 3 + r.Intn(20)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for a := r.Intn(3 * n); a > 0; a-- {
+			fr := r.Intn(n)
+			g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(r.Intn(n)))
+		}
+		s, ok := g.SimplifyPreservingDegrees(r, 10000)
+		in := g.InDegree()
+		out := make([]int, n)
+		for n, to := range g.AdjacencyList {
+			out[n] = len(to)
+		}
+		if !ok {
+			if graph.DigraphicalSequence(in, out) {
+				t.Log("swap limit reached on digraphical sequence")
+			}
+			continue
+		}
+		if simple, _ := s.IsSimple(); !simple {
+			t.Fatal("result not simple")
+		}
+		if !reflect.DeepEqual(s.InDegree(), in) {
+			t.Fatal("in-degrees differ")
+		}
+		for n, to := range s.AdjacencyList {
+			if len(to) != out[n] {
+				t.Fatal("out-degrees differ")
+			}
+		}
+	}
+}
+
 func ExampleDirected_SpanTree() {
 	//    0   5
 	//   / \
@@ -462,6 +819,28 @@ func ExampleDominators_Set() {
 
 // ------- Labeled examples -------
 
+func ExampleLabeledDirected_FilterArcs() {
+	//      10     20
+	// 0 ------> 1 ---> 2
+	//  \---------------^
+	//         5
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{1, 10}, {2, 5}},
+		1: {{2, 20}},
+		2: {},
+	}}
+	f := g.FilterArcs(func(fr graph.NI, to graph.Half) bool {
+		return to.Label < 15
+	})
+	for fr, to := range f.LabeledAdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [{1 10} {2 5}]
+	// 1 []
+	// 2 []
+}
+
 func ExampleLabeledDirected_NegativeCycles() {
 	//     -1      2
 	//  0----->1------>2--\
@@ -614,18 +993,26 @@ func TestNegativeCycles(t *testing.T) {
 		{21, 30, 39, 26, 23, 9, 8, 5, 6, 14}, // C3
 		{21, 30, 39, 26, 23, 9, 4, 6, 14},    // C4 (corrected)
 	}
-	i := 0
+	var ws, gs graph.LabeledCycleSet
+	for _, ls := range want {
+		wc := make([]graph.Half, len(ls))
+		for i, l := range ls {
+			wc[i] = graph.Half{arcs[l].to, l}
+		}
+		ws.Add(wc)
+	}
 	graph.LabeledDirected{a}.NegativeCycles(w, func(c []graph.Half) bool {
-		got := make([]graph.LI, len(c))
-		for i, e := range c {
-			got[i] = e.Label
+		if !ws.Contains(c) {
+			t.Fatal("unexpected cycle", c)
 		}
-		if !reflect.DeepEqual(got, want[i]) {
-			t.Fatal(got, want[i])
+		if !gs.Add(c) {
+			t.Fatal("duplicate cycle", c)
 		}
-		i++
 		return true
 	})
+	if gs.Len() != len(want) {
+		t.Fatal(gs.Len(), "cycles, want", len(want))
+	}
 	if !reflect.DeepEqual(a[1:], c[1:]) {
 		t.Fatal("graph altered")
 	}
@@ -657,16 +1044,21 @@ func TestC6(t *testing.T) {
 		{{4, -1}, {2, 1}, {1, 1}, {5, -1}, {0, -1}},
 	}
 	c, _ := g.Copy()
-	i := 0
+	var ws, gs graph.LabeledCycleSet
+	for _, c := range want {
+		ws.Add(c)
+	}
 	g.NegativeCycles(w, func(c []graph.Half) bool {
-		if !reflect.DeepEqual(c, want[i]) {
-			t.Fatal("i: ", i, " got: ", c, " want: ", want[i])
+		if !ws.Contains(c) {
+			t.Fatal("unexpected cycle: ", c)
+		}
+		if !gs.Add(c) {
+			t.Fatal("duplicate cycle: ", c)
 		}
-		i++
 		return true
 	})
-	if i != len(want) {
-		t.Fatal("only ", i)
+	if gs.Len() != len(want) {
+		t.Fatal("only ", gs.Len())
 	}
 	if !reflect.DeepEqual(g, c) {
 		for fr, to := range g.LabeledAdjacencyList {
@@ -721,6 +1113,286 @@ func ExampleLabeledDirected_DAGMaxLenPath() {
 	// label path: WMP
 }
 
+func ExampleLabeledDirected_EulerianCycleOrdered() {
+	//   /<----------d---\
+	//  /      /<--e---\  \
+	// 0--a-->1--c-->\ /  /
+	//         \--b-->2--/
+	//               / \
+	//              /   \
+	//             /<-f--\
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{1, 'a'}},
+		1: {{2, 'c'}, {2, 'b'}},
+		2: {{1, 'e'}, {0, 'd'}, {2, 'f'}},
+	}}
+	c, err := g.EulerianCycleOrdered(func(a, b graph.Half) bool {
+		return a.Label < b.Label
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(c[0].To)
+	for _, to := range c[1:] {
+		fmt.Printf(" --%c-- %d", to.Label, to.To)
+	}
+	fmt.Println()
+	// Output:
+	// 0 --a-- 1 --b-- 2 --e-- 1 --c-- 2 --f-- 2 --d-- 0
+}
+
+// checkEulerian checks that p is an Eulerian path in g, or an Eulerian
+// cycle if cycle is true.  Arcs of g must have distinct labels.
+func checkEulerian(g graph.LabeledAdjacencyList, p []graph.Half, m int, cycle bool) error {
+	if len(p) != m+1 {
+		return fmt.Errorf("path length %d, want %d", len(p), m+1)
+	}
+	if cycle && p[len(p)-1].To != p[0].To {
+		return fmt.Errorf("cycle ends at %d, not %d", p[len(p)-1].To, p[0].To)
+	}
+	used := map[graph.LI]bool{}
+	for i, h := range p[1:] {
+		if used[h.Label] {
+			return fmt.Errorf("arc %d reused", h.Label)
+		}
+		used[h.Label] = true
+		fr := p[i].To
+		found := false
+		for _, to := range g[fr] {
+			if to == h {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no arc %d->%v", fr, h)
+		}
+	}
+	return nil
+}
+
+func TestEulerianOrdered(t *testing.T) {
+	r := rand.New(rand.NewSource(61))
+	less := func(a, b graph.Half) bool { return a.Label < b.Label }
+	for i := 0; i < 200; i++ {
+		// random walk visiting all nodes, closed for a cycle or with a
+		// random tail for a path.
+		n := 1 + r.Intn(8)
+		walk := []graph.NI{0}
+		for _, n := range r.Perm(n - 1) {
+			walk = append(walk, graph.NI(n+1))
+		}
+		for j := r.Intn(2 * n); j > 0; j-- {
+			walk = append(walk, graph.NI(r.Intn(n)))
+		}
+		walk = append(walk, 0)
+		tail := r.Intn(3)
+		for j := 0; j < tail; j++ {
+			walk = append(walk, graph.NI(r.Intn(n)))
+		}
+		// distinct labels in random order
+		m := len(walk) - 1
+		lab := r.Perm(m)
+		g := graph.LabeledDirected{make(graph.LabeledAdjacencyList, n)}
+		for j, fr := range walk[:m] {
+			g.LabeledAdjacencyList[fr] = append(g.LabeledAdjacencyList[fr],
+				graph.Half{walk[j+1], graph.LI(lab[j])})
+		}
+		c, _ := g.Copy()
+		var p []graph.Half
+		var err error
+		if tail == 0 {
+			p, err = g.EulerianCycleOrdered(less)
+		} else {
+			p, err = g.EulerianPathOrdered(less)
+		}
+		if err != nil {
+			t.Fatal(g, err)
+		}
+		if err := checkEulerian(g.LabeledAdjacencyList, p, m, tail == 0); err != nil {
+			t.Fatal(g, p, err)
+		}
+		if !reflect.DeepEqual(c, g) {
+			t.Fatal("receiver modified")
+		}
+		// result must not depend on arc list order
+		for _, to := range c.LabeledAdjacencyList {
+			r.Shuffle(len(to), func(i, j int) { to[i], to[j] = to[j], to[i] })
+		}
+		var q []graph.Half
+		if tail == 0 {
+			q, _ = c.EulerianCycleOrdered(less)
+		} else {
+			q, _ = c.EulerianPathOrdered(less)
+		}
+		if !reflect.DeepEqual(p, q) {
+			t.Fatal(g, p, q, "result depends on arc order")
+		}
+	}
+}
+
+func ExampleLabeledDirected_FeasibleCirculation() {
+	// arcs with labels as indexes into lower and upper bounds:
+	//   0->1 (0)  1->2 (1)  2->0 (2)  1->0 (3)
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}},
+		1: {{To: 2, Label: 1}, {To: 0, Label: 3}},
+		2: {{To: 0, Label: 2}},
+	}}
+	lo := []float64{4, 1, 0, 2}
+	hi := []float64{6, 2, 5, 3}
+	lower := func(l graph.LI) float64 { return lo[l] }
+	upper := func(l graph.LI) float64 { return hi[l] }
+	flow, ok, _ := g.FeasibleCirculation(lower, upper)
+	fmt.Println(ok, flow)
+	hi[3] = 2.5 // reduce capacity of 1->0
+	hi[1] = 1   // and 1->2
+	_, ok, cut := g.FeasibleCirculation(lower, upper)
+	fmt.Println(ok, cut.Slice())
+	// Output:
+	// true [[4] [1 3] [1]]
+	// false [1]
+}
+
+func TestFeasibleCirculation(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	nOk := 0
+	for i := 0; i < 200; i++ {
+		n := 2 + r.Intn(6)
+		var lo, hi []float64
+		a := make(graph.LabeledAdjacencyList, n)
+		for m := r.Intn(3 * n); m >= 0; m-- {
+			fr := r.Intn(n)
+			l := float64(r.Intn(4))
+			lo = append(lo, l)
+			hi = append(hi, l+float64(r.Intn(5)))
+			a[fr] = append(a[fr], graph.Half{graph.NI(r.Intn(n)), graph.LI(len(lo) - 1)})
+		}
+		lower := func(l graph.LI) float64 { return lo[l] }
+		upper := func(l graph.LI) float64 { return hi[l] }
+		flow, ok, cut := graph.LabeledDirected{a}.FeasibleCirculation(lower, upper)
+		if !ok {
+			// lower bounds into cut must exceed upper bounds out of cut
+			in, out := 0., 0.
+			for fr, to := range a {
+				for _, h := range to {
+					switch {
+					case cut.Bit(fr) == 0 && cut.Bit(int(h.To)) == 1:
+						in += lo[h.Label]
+					case cut.Bit(fr) == 1 && cut.Bit(int(h.To)) == 0:
+						out += hi[h.Label]
+					}
+				}
+			}
+			if in <= out {
+				t.Fatal("cut certificate not violated:", in, out)
+			}
+			continue
+		}
+		nOk++
+		bal := make([]float64, n)
+		for fr, to := range a {
+			for x, h := range to {
+				fl := flow[fr][x]
+				if fl < lo[h.Label] || fl > hi[h.Label] {
+					t.Fatal("flow out of bounds")
+				}
+				bal[fr] -= fl
+				bal[h.To] += fl
+			}
+		}
+		for nd, b := range bal {
+			if b != 0 {
+				t.Fatal("conservation violated at node", nd)
+			}
+		}
+	}
+	if nOk == 0 || nOk == 200 {
+		t.Fatal("expected mix of feasible and infeasible instances, feasible:", nOk)
+	}
+}
+
+func ExampleLabeledDirected_LabeledCondensation() {
+	//        'b','c'
+	//     0 <====== 1
+	//     |         ^
+	//  'a'|         | 'e'
+	//     v         |
+	//     2 -'d'--> 3 -'f'--> 4
+	//     |                   ^
+	//     \-------'g'---------/
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{2, 'a'}},
+		1: {{0, 'b'}, {0, 'c'}},
+		2: {{3, 'd'}, {4, 'g'}},
+		3: {{1, 'e'}, {4, 'f'}},
+		4: {},
+	}}
+	scc, cd, _, arcs := g.LabeledCondensation()
+	for c, to := range cd {
+		fmt.Println("component", c, scc[c])
+		for x, h := range to {
+			fmt.Printf("  arc to %d label %c from:", h.To, h.Label)
+			for _, e := range arcs[c][x] {
+				fmt.Printf(" %d->%d %c", e.N1, e.N2, e.LI)
+			}
+			fmt.Println()
+		}
+	}
+	// Output:
+	// component 0 [4]
+	// component 1 [2 3 1 0]
+	//   arc to 0 label g from: 2->4 g 3->4 f
+}
+
+func TestLabeledCondensation(t *testing.T) {
+	r := rand.New(rand.NewSource(38))
+	for i := 0; i < 100; i++ {
+		u := graph.GnmDirected(20, 40, r).AdjacencyList
+		var g graph.LabeledDirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, len(u))
+		l := graph.LI(0)
+		for fr, to := range u {
+			for _, to := range to {
+				g.LabeledAdjacencyList[fr] =
+					append(g.LabeledAdjacencyList[fr], graph.Half{to, l})
+				l++
+			}
+		}
+		scc, cd, cond, arcs := g.LabeledCondensation()
+		_, ucd, ucond := g.Unlabeled().CondensationMap()
+		if !reflect.DeepEqual(cond, ucond) || len(cd) != len(scc) {
+			t.Fatal(i, "component mapping")
+		}
+		// every arc between components is listed exactly once
+		seen := map[graph.LI]bool{}
+		for c, to := range cd {
+			if len(to) != len(ucd[c]) {
+				t.Fatal(i, "component", c, "arcs", to, ucd[c])
+			}
+			for x, h := range to {
+				if h.To != ucd[c][x] || len(arcs[c][x]) == 0 ||
+					arcs[c][x][0].LI != h.Label {
+					t.Fatal(i, "component", c, "arc", h)
+				}
+				for _, e := range arcs[c][x] {
+					if cond[e.N1] != graph.NI(c) || cond[e.N2] != h.To || seen[e.LI] {
+						t.Fatal(i, "component", c, "arc", e)
+					}
+					seen[e.LI] = true
+				}
+			}
+		}
+		for fr, to := range g.LabeledAdjacencyList {
+			for _, h := range to {
+				if cond[fr] != cond[h.To] && !seen[h.Label] {
+					t.Fatal(i, "arc", fr, h, "not listed")
+				}
+			}
+		}
+	}
+}
+
 func ExampleLabeledDirected_FromList() {
 	//      0
 	// 'A' / \ 'B'
@@ -752,6 +1424,32 @@ func ExampleLabeledDirected_FromList() {
 	// 3    2     C
 }
 
+func ExampleLabeledDirected_RemoveArc() {
+	var g graph.LabeledDirected
+	g.AddArc(0, graph.Half{To: 1, Label: 'a'})
+	g.AddArc(0, graph.Half{To: 1, Label: 'b'}) // parallel
+	g.AddArc(1, graph.Half{To: 0, Label: 'c'})
+
+	ok, l := g.RemoveArc(0, 1) // remove one of the parallel arcs
+	fmt.Printf("%t %c\n", ok, l)
+	fmt.Println(g.RemoveArcLabel(1, 0, 'x')) // false: no arc with label
+	fmt.Println(g.RemoveArcLabel(1, 0, 'c'))
+
+	for fr, to := range g.LabeledAdjacencyList {
+		fmt.Print(fr, ":")
+		for _, h := range to {
+			fmt.Printf(" {%d %c}", h.To, h.Label)
+		}
+		fmt.Println()
+	}
+	// Output:
+	// true a
+	// false
+	// true
+	// 0: {1 b}
+	// 1:
+}
+
 func ExampleLabeledDirected_SpanTree() {
 	//      0       4
 	// 'A' / \ 'B'   \ 'D'
@@ -861,3 +1559,80 @@ func ExampleLabeledDirected_UnlabeledTranspose() {
 	// 2 []
 	// 2 arcs
 }
+
+func TestWeaklyConnectedComponents(t *testing.T) {
+	r := rand.New(rand.NewSource(8))
+	for i := 0; i < 20; i++ {
+		n := 1 + r.Intn(60)
+		g := graph.GnmDirected(n, r.Intn(n+1), r)
+		ci, nc := g.WeaklyConnectedComponentInts()
+		ui, unc := g.Undirected().ConnectedComponentInts()
+		if nc != unc || !reflect.DeepEqual(ci, ui) {
+			t.Fatal(g, ci, nc, "want", ui, unc)
+		}
+		// lists agree with ints and cover all nodes once
+		f := g.WeaklyConnectedComponentLists()
+		seen := 0
+		for c := 1; ; c++ {
+			l, ma := f()
+			if l == nil {
+				if c != nc+1 {
+					t.Fatal(c-1, "lists, want", nc)
+				}
+				break
+			}
+			m := 0
+			for _, x := range l {
+				if ci[x] != c {
+					t.Fatal("node", x, "in list", c, "component", ci[x])
+				}
+				m += len(g.AdjacencyList[x])
+			}
+			if m != ma {
+				t.Fatal("arc size", ma, "want", m)
+			}
+			seen += len(l)
+		}
+		if seen != n {
+			t.Fatal(seen, "nodes in lists, want", n)
+		}
+		// labeled version agrees
+		lg := graph.LabeledDirected{make(graph.LabeledAdjacencyList, n)}
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				lg.LabeledAdjacencyList[fr] = append(lg.LabeledAdjacencyList[fr],
+					graph.Half{To: to})
+			}
+		}
+		if li, lnc := lg.WeaklyConnectedComponentInts(); lnc != nc ||
+			!reflect.DeepEqual(li, ci) {
+			t.Fatal("labeled", li, lnc)
+		}
+	}
+}
+
+func ExampleDirected_ArcsOf() {
+	// 0-->2<--1
+	//     ^\
+	//     \/
+	g := graph.Directed{graph.AdjacencyList{
+		0: {2},
+		1: {2},
+		2: {2},
+	}}
+	var t graph.Directed // transpose, built on first call
+	g.ArcsOf(2, &t, func(fr graph.NI) bool {
+		fmt.Println(fr, "-> 2")
+		return true
+	})
+	fmt.Println("transpose:", t.AdjacencyList)
+	g.ArcsOf(0, &t, func(fr graph.NI) bool {
+		fmt.Println(fr, "-> 0")
+		return true
+	})
+	// Output:
+	// 0 -> 2
+	// 1 -> 2
+	// 2 -> 2
+	// transpose: [[] [] [0 1 2]]
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// disjointset.go -- public union-find.
+
+// DisjointSets is a union-find structure over nodes 0 through order-1.
+//
+// Initially each node is in a set by itself.  Union merges sets, Find
+// returns a representative node of a set, and Connected tests if two nodes
+// are in the same set.  The implementation uses union by rank and path
+// compression, as used internally by Kruskal, so that a sequence of
+// operations takes time nearly linear in the number of operations.
+//
+// DisjointSets is a small struct referencing shared state.  Copies of a
+// DisjointSets value share the same sets.  Find updates the shared state,
+// so methods are not safe for concurrent use, even for queries.
+//
+// Construct with NewDisjointSets or with the DisjointSets method of
+// Undirected or LabeledUndirected.
+type DisjointSets struct {
+	ds disjointSet
+}
+
+// NewDisjointSets constructs a DisjointSets with each node of a graph of the
+// given order in a set by itself.
+func NewDisjointSets(order int) DisjointSets {
+	return DisjointSets{newDisjointSet(order)}
+}
+
+// Order returns the number of nodes of the sets.
+func (s DisjointSets) Order() int {
+	return len(s.ds.set)
+}
+
+// Union merges the sets containing nodes a and b.
+//
+// It returns true if the sets were merged, false if a and b were already in
+// the same set.
+func (s DisjointSets) Union(a, b NI) bool {
+	return s.ds.union(a, b)
+}
+
+// Find returns the representative node of the set containing n.
+//
+// The representative of a set is the same for all nodes of the set but may
+// change when the set is merged with another.
+func (s DisjointSets) Find(n NI) NI {
+	return s.ds.find(n)
+}
+
+// Connected returns true if nodes a and b are in the same set.
+func (s DisjointSets) Connected(a, b NI) bool {
+	return s.ds.find(a) == s.ds.find(b)
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDisjointSets() {
+	s := graph.NewDisjointSets(5)
+	s.Union(0, 1)
+	s.Union(3, 4)
+	fmt.Println(s.Connected(0, 1), s.Connected(1, 3))
+	fmt.Println(s.Union(1, 4), s.Union(0, 3))
+	fmt.Println(s.Connected(0, 4), s.Connected(2, 4))
+	// Output:
+	// true false
+	// true false
+	// true false
+}
+
+func ExampleUndirected_DisjointSets() {
+	//    0   1   2
+	//   / \   \
+	//  3---4   5
+	g := graph.Undirected{graph.AdjacencyList{
+		0: {3, 4},
+		1: {5},
+		3: {0, 4},
+		4: {0, 3},
+		5: {1},
+	}}
+	s := g.DisjointSets()
+	fmt.Println(s.Connected(3, 4), s.Connected(4, 5))
+	// an edge streams in
+	s.Union(4, 1)
+	fmt.Println(s.Connected(3, 4), s.Connected(4, 5))
+	// Output:
+	// true false
+	// true true
+}
+
+func TestDisjointSets(t *testing.T) {
+	// add edges one at a time, comparing connectivity to
+	// ConnectedComponentInts after each.
+	r := rand.New(rand.NewSource(23))
+	for i := 0; i < 30; i++ {
+		n := 1 + r.Intn(30)
+		g := graph.Undirected{make(graph.AdjacencyList, n)}
+		s := graph.NewDisjointSets(n)
+		for m := r.Intn(n * 2); m >= 0; m-- {
+			n1, n2 := graph.NI(r.Intn(n)), graph.NI(r.Intn(n))
+			c0, _ := g.ConnectedComponentInts()
+			if s.Union(n1, n2) != (c0[n1] != c0[n2]) {
+				t.Fatal(i, "union", n1, n2, "merged", c0[n1] != c0[n2])
+			}
+			g.AddEdge(n1, n2)
+			ci, _ := g.ConnectedComponentInts()
+			for a := range ci {
+				for b := range ci {
+					if s.Connected(graph.NI(a), graph.NI(b)) != (ci[a] == ci[b]) {
+						t.Fatal(i, "nodes", a, b, g.AdjacencyList)
+					}
+				}
+				if ci[s.Find(graph.NI(a))] != ci[a] {
+					t.Fatal(i, "find", a)
+				}
+			}
+		}
+		// constructor agrees too
+		ci, _ := g.ConnectedComponentInts()
+		d := g.DisjointSets()
+		if d.Order() != n {
+			t.Fatal(i, "order", d.Order())
+		}
+		for a := range ci {
+			for b := range ci {
+				if d.Connected(graph.NI(a), graph.NI(b)) != (ci[a] == ci[b]) {
+					t.Fatal(i, "DisjointSets", a, b, g.AdjacencyList)
+				}
+			}
+		}
+	}
+}
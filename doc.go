@@ -119,4 +119,30 @@
 //  BellmanFord    Negative arc weights allowed, no negative cycles, all paths.
 //  DAGPath        O(n) algorithm for DAGs, arc weights of any sign.
 //  FloydWarshall  all pairs distances, no negative cycles.
+//
+// Concurrency
+//
+// Methods and functions on graph types keep no state between calls.  Most
+// only read their receiver graph and arguments.  Any number of these may run
+// concurrently on the same graph from separate goroutines, as long as no
+// goroutine modifies the graph.
+//
+// A few types do hold state between calls and are not safe for concurrent
+// use without synchronization.  MaxFlow.Run mutates the residual network
+// held by the MaxFlow, and MinCut reports the cut of the last Run.
+// OfflineConnectivity accumulates edge intervals and queries until Solve,
+// and CycleSet accumulates cycles as they are added.  Separate values of
+// these types may be used concurrently.
+//
+// Methods that do modify their receiver are documented as doing so.  They
+// include AddEdge and RemoveEdge methods, methods reordering arc lists such
+// as SortArcLists and ShuffleArcLists, and "destructive" methods with names
+// ending in D, such as EulerianCycleD.  NegativeCycles also mutates its
+// receiver while it runs, although it restores the graph before returning.
+// None of these may run concurrently with any other method on the same graph.
+//
+// Some non-destructive methods copy the graph internally in order to use a
+// destructive algorithm.  These are documented with the sentence
+// "Internally, <method> copies the entire graph g."  They are safe for
+// concurrent use but need memory proportional to the graph size.
 package graph
@@ -165,6 +165,9 @@ func writeAL(g graph.AdjacencyList, w io.Writer, cf *Config) (err error) {
 			}
 		}
 	}
+	if err = writeNodeAttr(len(g), cf, b); err != nil {
+		return
+	}
 	var iso bits.Bits
 	if cf.Isolated {
 		iso = g.IsolatedNodes()
@@ -199,6 +202,37 @@ func writeHead(cf *Config, b *bufio.Writer) error {
 	return nil
 }
 
+// writeNodeAttr writes node statements for nodes 0 through n-1 with
+// attributes from cf.NodeAttr.
+func writeNodeAttr(n int, cf *Config, b *bufio.Writer) error {
+	if cf.NodeAttr == nil {
+		return nil
+	}
+	for i := 0; i < n; i++ {
+		if a := cf.NodeAttr(graph.NI(i)); len(a) > 0 {
+			_, err := fmt.Fprintf(b, "%s%s %s\n",
+				cf.Indent, cf.NodeID(graph.NI(i)), fmtAttr(a))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// arcAttr returns attributes from cf.ArcAttr, trying the reverse arc as
+// well if undir is true.
+func (cf *Config) arcAttr(fr, to graph.NI, undir bool) []AttrVal {
+	if cf.ArcAttr == nil {
+		return nil
+	}
+	a := cf.ArcAttr(fr, to)
+	if len(a) == 0 && undir {
+		a = cf.ArcAttr(to, fr)
+	}
+	return a
+}
+
 func writeTail(b *bufio.Writer) error {
 	if err := b.WriteByte('}'); err != nil {
 		return err
@@ -217,6 +251,41 @@ func writeALDirected(g graph.AdjacencyList, cf *Config, iso bits.Bits, b *bufio.
 }
 
 func writeALEdgeStmt(fr graph.NI, to []graph.NI, op string, cf *Config, iso bits.Bits, b *bufio.Writer) (err error) {
+	if cf.ArcAttr == nil {
+		return writeALEdgeGroup(fr, to, op, cf, iso, b)
+	}
+	// arcs with attributes are written as separate edge statements
+	var plain, hl []graph.NI
+	var hlAttr [][]AttrVal
+	for _, to := range to {
+		if a := cf.arcAttr(fr, to, op == "--"); len(a) > 0 {
+			hl = append(hl, to)
+			hlAttr = append(hlAttr, a)
+		} else {
+			plain = append(plain, to)
+		}
+	}
+	if len(plain) > 0 || len(hl) == 0 {
+		if err = writeALEdgeGroup(fr, plain, op, cf, iso, b); err != nil {
+			return
+		}
+	}
+	for i, to := range hl {
+		var attr []AttrVal
+		if cf.EdgeAttr != nil {
+			attr = cf.EdgeAttr(0)
+		}
+		attr = append(attr, hlAttr[i]...)
+		_, err = fmt.Fprintf(b, "%s%s %s %s %s\n",
+			cf.Indent, cf.NodeID(fr), op, cf.NodeID(to), fmtAttr(attr))
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func writeALEdgeGroup(fr graph.NI, to []graph.NI, op string, cf *Config, iso bits.Bits, b *bufio.Writer) (err error) {
 	attr := ""
 	if cf.EdgeAttr != nil {
 		attr = " " + fmtAttr(cf.EdgeAttr(0))
@@ -235,51 +304,32 @@ func writeALEdgeStmt(fr graph.NI, to []graph.NI, op string, cf *Config, iso bits
 	// otherwise it's complicated.  we like to use a subgraph rhs to keep
 	// output compact, but graphviz (some version) won't separate parallel
 	// arcs in a subgraph, so in that case we write multiple edge statements.
-	_, err = fmt.Fprintf(b, "%s%s %s ",
-		cf.Indent, cf.NodeID(fr), op)
-	if err != nil {
-		return
-	}
-	s1 := map[graph.NI]bool{}
-	m := map[graph.NI]int{} // multiset of defered duplicates
-	c := "{"
-	// first pass is over the to-list, the slice
-	for _, to := range to {
-		if !s1[to] {
-			if _, err = b.WriteString(c + cf.NodeID(to)); err != nil {
-				return
-			}
-			c = " "
-			s1[to] = true
-		} else {
-			m[to]++
-		}
-	}
-	if _, err = b.WriteString("}" + attr + "\n"); err != nil {
-		return
-	}
-	// make additional passes over the map until it's fully consumed
-	for len(m) > 0 {
+	// each pass writes the distinct nodes remaining in to-list order and
+	// defers duplicates to the next pass.
+	for len(to) > 0 {
 		_, err = fmt.Fprintf(b, "%s%s %s ",
-			cf.Indent, cf.NodeID(graph.NI(fr)), op)
+			cf.Indent, cf.NodeID(fr), op)
 		if err != nil {
 			return
 		}
-		c1 := "{"
-		for n, c := range m {
-			if _, err = b.WriteString(c1 + cf.NodeID(n)); err != nil {
-				return
+		s1 := map[graph.NI]bool{}
+		var dup []graph.NI // defered duplicates
+		c := "{"
+		for _, to := range to {
+			if s1[to] {
+				dup = append(dup, to)
+				continue
 			}
-			if c == 1 {
-				delete(m, n)
-			} else {
-				m[n]--
+			if _, err = b.WriteString(c + cf.NodeID(to)); err != nil {
+				return
 			}
-			c1 = " "
+			c = " "
+			s1[to] = true
 		}
 		if _, err = b.WriteString("}" + attr + "\n"); err != nil {
 			return
 		}
+		to = dup
 	}
 	return
 }
@@ -355,6 +405,9 @@ func writeLAL(g graph.LabeledAdjacencyList, w io.Writer, cf *Config) (err error)
 			}
 		}
 	}
+	if err = writeNodeAttr(len(g), cf, b); err != nil {
+		return
+	}
 	var iso bits.Bits
 	if cf.Isolated {
 		iso = g.IsolatedNodes()
@@ -398,6 +451,7 @@ func writeLALEdgeStmt(fr graph.NI, to []graph.Half, op string, cf *Config, iso b
 		if el := cf.EdgeLabel(to.Label); el > "" {
 			attr = append(attr, AttrVal{"label", cf.EdgeLabel(to.Label)})
 		}
+		attr = append(attr, cf.arcAttr(fr, to.To, op == "--")...)
 		_, err = fmt.Fprintf(b, "%s%s %s %s %s\n",
 			cf.Indent, cf.NodeID(fr), op, cf.NodeID(to.To),
 			fmtAttr(attr))
@@ -468,6 +522,9 @@ func writeFromList(f graph.FromList, w io.Writer, options []Option) error {
 	if err := writeHead(&cf, b); err != nil {
 		return err
 	}
+	if err := writeNodeAttr(len(f.Paths), &cf, b); err != nil {
+		return err
+	}
 	//var iso bits.Bits
 	//if cf.Isolated {
 	iso := f.IsolatedNodes()
@@ -484,8 +541,12 @@ func writeFromList(f graph.FromList, w io.Writer, options []Option) error {
 			}
 			continue
 		}
-		_, err := fmt.Fprintf(b, "%s%s -> %s\n",
-			cf.Indent, cf.NodeID(n), cf.NodeID(fr))
+		attr := ""
+		if a := cf.arcAttr(fr, n, false); len(a) > 0 {
+			attr = " " + fmtAttr(a)
+		}
+		_, err := fmt.Fprintf(b, "%s%s -> %s%s\n",
+			cf.Indent, cf.NodeID(n), cf.NodeID(fr), attr)
 		if err != nil {
 			return err
 		}
@@ -529,6 +590,9 @@ func writeWeightedEdgeList(g graph.WeightedEdgeList, w io.Writer, options []Opti
 	if err := writeHead(&cf, b); err != nil {
 		return err
 	}
+	if err := writeNodeAttr(g.Order, &cf, b); err != nil {
+		return err
+	}
 	wf := writeWELNoRecip
 	if cf.UndirectArcs || cf.Directed {
 		wf = writeWELAllArcs
@@ -548,9 +612,9 @@ edge:
 		for i, u := range u2 {
 			if u.To == e.N1 && u.Label == e.LI { // found reciprocal
 				// write the edge
-				_, err := fmt.Fprintf(b, "%s%s -- %s [label = %s]\n",
+				_, err := fmt.Fprintf(b, "%s%s -- %s %s\n",
 					cf.Indent, cf.NodeID(e.N2), cf.NodeID(e.N1),
-					cf.EdgeLabel(e.LI))
+					fmtAttr(welAttr(e.N2, e.N1, e.LI, true, cf)))
 				if err != nil {
 					return err
 				}
@@ -578,12 +642,18 @@ func writeWELAllArcs(g graph.WeightedEdgeList, cf *Config, b *bufio.Writer) erro
 		op = "->"
 	}
 	for _, e := range g.Edges {
-		_, err := fmt.Fprintf(b, "%s%s %s %s [label = %s]\n",
+		_, err := fmt.Fprintf(b, "%s%s %s %s %s\n",
 			cf.Indent, cf.NodeID(e.N1), op, cf.NodeID(e.N2),
-			cf.EdgeLabel(e.LI))
+			fmtAttr(welAttr(e.N1, e.N2, e.LI, !cf.Directed, cf)))
 		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// welAttr returns the label attribute and any arc attributes for an edge.
+func welAttr(n1, n2 graph.NI, l graph.LI, undir bool, cf *Config) []AttrVal {
+	return append([]AttrVal{{"label", cf.EdgeLabel(l)}},
+		cf.arcAttr(n1, n2, undir)...)
+}
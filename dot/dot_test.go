@@ -218,3 +218,20 @@ func ExampleWrite_weightedEdgeList() {
 	//   1 -- 2 [label = "1.7"]
 	// }
 }
+
+func ExampleWrite_adjacencyListParallelArcsOrder() {
+	// duplicate arcs are written in to-list order, deterministically
+	g := graph.AdjacencyList{
+		0: {3, 1, 3, 2, 1, 3},
+		1: {},
+		2: {},
+		3: {},
+	}
+	dot.Write(g, os.Stdout)
+	// Output:
+	// digraph {
+	//   0 -> {3 1 2}
+	//   0 -> {3 1}
+	//   0 -> {3}
+	// }
+}
@@ -6,6 +6,7 @@ package dot
 import (
 	"strconv"
 
+	"github.com/soniakeys/bits"
 	"github.com/soniakeys/graph"
 )
 
@@ -22,12 +23,14 @@ type AttrVal struct {
 // for each member.  To set a member, pass the option function as an optional
 // argument to a Write or String function.
 type Config struct {
+	ArcAttr      func(fr, to graph.NI) []AttrVal
 	Directed     bool
 	EdgeLabel    func(graph.LI) string
 	EdgeAttr     func(graph.LI) []AttrVal
 	GraphAttr    []AttrVal
 	Indent       string
 	Isolated     bool
+	NodeAttr     func(graph.NI) []AttrVal
 	NodeID       func(graph.NI) string
 	NodePos      func(graph.NI) string
 	UndirectArcs bool
@@ -46,6 +49,21 @@ var Defaults = Config{
 // Options are passed variadic arguments to a function like Write or String.
 type Option func(*Config)
 
+// ArcAttr specifies a function to generate a list of edge attributes for
+// an arc given its from and to nodes.
+//
+// An arc with a non-empty attribute list is written as a separate edge
+// statement.  For an undirected dot format graph, the function is called with
+// the nodes of an edge in the order written, then if that gives no
+// attributes, in the reverse order.  For a FromList, arcs are from the
+// from-node to the node, as in paths returned by PathTo, although they are
+// written in the opposite direction.
+//
+// See also HighlightPath.
+func ArcAttr(f func(fr, to graph.NI) []AttrVal) Option {
+	return func(c *Config) { c.ArcAttr = f }
+}
+
 // Directed specifies whether to write a dot format directected or undirected
 // graph.
 //
@@ -92,6 +110,62 @@ func GraphAttr(attr, val string) Option {
 	}
 }
 
+// HighlightNodes adds a color attribute to nodes of the given set.
+//
+// It composes with any previous NodeAttr function, adding the color
+// attribute after attributes of the previous function.
+func HighlightNodes(nodes bits.Bits, color string) Option {
+	return func(c *Config) {
+		prev := c.NodeAttr
+		c.NodeAttr = func(n graph.NI) (a []AttrVal) {
+			if prev != nil {
+				a = prev(n)
+			}
+			if int(n) < nodes.Num && nodes.Bit(int(n)) == 1 {
+				a = append(a, AttrVal{"color", color})
+			}
+			return
+		}
+	}
+}
+
+// HighlightPath adds a color attribute to the nodes of path p and to arcs
+// between successive nodes of p.
+//
+// It composes with any previous NodeAttr and ArcAttr functions, adding the
+// color attribute after attributes of the previous functions.
+func HighlightPath(p []graph.NI, color string) Option {
+	onPath := map[graph.NI]bool{}
+	arcs := map[[2]graph.NI]bool{}
+	for i, n := range p {
+		onPath[n] = true
+		if i > 0 {
+			arcs[[2]graph.NI{p[i-1], n}] = true
+		}
+	}
+	return func(c *Config) {
+		prevNode, prevArc := c.NodeAttr, c.ArcAttr
+		c.NodeAttr = func(n graph.NI) (a []AttrVal) {
+			if prevNode != nil {
+				a = prevNode(n)
+			}
+			if onPath[n] {
+				a = append(a, AttrVal{"color", color})
+			}
+			return
+		}
+		c.ArcAttr = func(fr, to graph.NI) (a []AttrVal) {
+			if prevArc != nil {
+				a = prevArc(fr, to)
+			}
+			if arcs[[2]graph.NI{fr, to}] {
+				a = append(a, AttrVal{"color", color})
+			}
+			return
+		}
+	}
+}
+
 // Indent specifies an indent string for the body of the dot format.
 //
 // The default is two spaces.
@@ -109,6 +183,16 @@ func Isolated(i bool) Option {
 	return func(c *Config) { c.Isolated = i }
 }
 
+// NodeAttr specifies a function to generate a list of node attributes.
+//
+// For each node with a non-empty attribute list, a node statement is written
+// before any edge statements.
+//
+// See also HighlightNodes, HighlightPath.
+func NodeAttr(f func(graph.NI) []AttrVal) Option {
+	return func(c *Config) { c.NodeAttr = f }
+}
+
 // NodeID specifies a function to generate node ID strings for the
 // dot format given the node integers of graph package.
 //
@@ -126,6 +210,14 @@ func NodePos(f func(graph.NI) string) Option {
 	return func(c *Config) { c.NodePos = f }
 }
 
+// RankDir sets the dot format graph attribute rankdir.
+//
+// Values recognized by Graphviz are "TB", "LR", "BT", and "RL".
+// RankDir(d) is equivalent to GraphAttr("rankdir", d).
+func RankDir(d string) Option {
+	return GraphAttr("rankdir", d)
+}
+
 // UndirectArcs, for the WeightedEdgeList graph type, specifies to write
 // each element of the edge list as a dot file undirected edge.
 //
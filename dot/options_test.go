@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/soniakeys/bits"
 	"github.com/soniakeys/graph"
 	"github.com/soniakeys/graph/dot"
 )
@@ -257,3 +258,114 @@ func ExampleUndirectArcs() {
 	//   1 -- 2 [label = "1.7"]
 	// }
 }
+
+func ExampleArcAttr() {
+	// 0-->1-->2
+	g := graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {},
+	}
+	dot.Write(g, os.Stdout, dot.ArcAttr(func(fr, to graph.NI) []dot.AttrVal {
+		if fr == 1 {
+			return []dot.AttrVal{{"style", "dashed"}}
+		}
+		return nil
+	}))
+	// Output:
+	// digraph {
+	//   0 -> 1
+	//   1 -> 2 [style = dashed]
+	// }
+}
+
+func ExampleHighlightNodes() {
+	//   0
+	//  / \
+	// 1---2---3
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	b := bits.New(g.Order())
+	b.SetBit(0, 1)
+	b.SetBit(3, 1)
+	dot.Write(g, os.Stdout, dot.HighlightNodes(b, "red"))
+	// Output:
+	// graph {
+	//   0 [color = red]
+	//   3 [color = red]
+	//   0 -- {1 2}
+	//   1 -- 2
+	//   2 -- 3
+	// }
+}
+
+func ExampleHighlightPath() {
+	//     0
+	//    / \
+	//   1   2
+	//      / \
+	//     3   4
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1, Len: 1},
+		1: {From: 0, Len: 2},
+		2: {From: 0, Len: 2},
+		3: {From: 2, Len: 3},
+		4: {From: 2, Len: 3},
+	}}
+	f.RecalcLeaves()
+	dot.Write(f, os.Stdout, dot.HighlightPath(f.PathTo(4, nil), "blue"))
+	// Output:
+	// digraph {
+	//   rankdir = BT
+	//   0 [color = blue]
+	//   2 [color = blue]
+	//   4 [color = blue]
+	//   1 -> 0
+	//   2 -> 0 [color = blue]
+	//   3 -> 2
+	//   4 -> 2 [color = blue]
+	//   {rank = same 1 3 4}
+	// }
+}
+
+func ExampleHighlightPath_undirected() {
+	// 0---1---2
+	//  \     /
+	//   --3--
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 10)
+	g.AddEdge(graph.Edge{1, 2}, 20)
+	g.AddEdge(graph.Edge{0, 3}, 30)
+	g.AddEdge(graph.Edge{3, 2}, 40)
+	dot.Write(g, os.Stdout,
+		dot.HighlightPath([]graph.NI{2, 3, 0}, "red"),
+		dot.RankDir("LR"))
+	// Output:
+	// graph {
+	//   rankdir = LR
+	//   0 [color = red]
+	//   2 [color = red]
+	//   3 [color = red]
+	//   0 -- 1 [label = 10]
+	//   0 -- 3 [label = 30, color = red]
+	//   1 -- 2 [label = 20]
+	//   2 -- 3 [label = 40, color = red]
+	// }
+}
+
+func ExampleRankDir() {
+	// 0-->1
+	g := graph.AdjacencyList{
+		0: {1},
+		1: {},
+	}
+	dot.Write(g, os.Stdout, dot.RankDir("LR"))
+	// Output:
+	// digraph {
+	//   rankdir = LR
+	//   0 -> 1
+	// }
+}
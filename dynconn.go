@@ -0,0 +1,174 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// dynconn.go -- connectivity of undirected graphs changing over time.
+
+import "sort"
+
+// OfflineConnectivity answers connectivity queries on an undirected graph
+// where edges are inserted and deleted over time, and where the full
+// timeline of changes and queries is known in advance.
+//
+// Edges are added with AddEdgeInterval giving the time interval over which
+// the edge is present.  Queries are added with AddQuery.  Solve then answers
+// all queries together.
+//
+// Construct with NewOfflineConnectivity.
+type OfflineConnectivity struct {
+	n      int
+	always []Edge // edges present over the entire timeline
+	edges  []Edge
+	iv     [][2]int // time interval of each edge
+	qs     []ocQuery
+}
+
+type ocQuery struct {
+	n1, n2 NI
+	at     int
+}
+
+// NewOfflineConnectivity constructs an OfflineConnectivity for graph g.
+//
+// The graph g gives the node set and edges present over the entire
+// timeline.  G is not retained.
+func NewOfflineConnectivity(g Undirected) *OfflineConnectivity {
+	c := &OfflineConnectivity{n: len(g.AdjacencyList)}
+	g.SimpleEdges(func(e Edge) {
+		c.always = append(c.always, e)
+	})
+	return c
+}
+
+// AddEdgeInterval adds edge e to the timeline, present at times t where
+// from <= t < to.
+//
+// The same edge may be added with multiple intervals.
+func (c *OfflineConnectivity) AddEdgeInterval(e Edge, from, to int) {
+	if from < to {
+		c.edges = append(c.edges, e)
+		c.iv = append(c.iv, [2]int{from, to})
+	}
+}
+
+// AddQuery adds a query of whether nodes n1 and n2 are connected at time at.
+func (c *OfflineConnectivity) AddQuery(n1, n2 NI, at int) {
+	c.qs = append(c.qs, ocQuery{n1, n2, at})
+}
+
+// Solve answers all queries.
+//
+// Returned is a result for each query, in the order queries were added, true
+// if the query nodes are connected at the query time.
+//
+// The algorithm assigns each edge interval to O(log q) nodes of a segment
+// tree over the q distinct query times, then traverses the segment tree
+// maintaining a union-find with rollback.  Time complexity is
+// O(m log q log n + q log n) for m edge intervals and q queries on a graph
+// of n nodes.
+func (c *OfflineConnectivity) Solve() []bool {
+	// distinct query times
+	times := make([]int, len(c.qs))
+	for i, q := range c.qs {
+		times[i] = q.at
+	}
+	sort.Ints(times)
+	k := 0
+	for _, t := range times {
+		if k == 0 || t != times[k-1] {
+			times[k] = t
+			k++
+		}
+	}
+	times = times[:k]
+	res := make([]bool, len(c.qs))
+	if k == 0 {
+		return res
+	}
+	// queries by time index
+	qt := make([][]int, k)
+	for i, q := range c.qs {
+		x := sort.SearchInts(times, q.at)
+		qt[x] = append(qt[x], i)
+	}
+	// segment tree over time indexes.  node 1 is the root covering [0,k),
+	// children of node s are 2s and 2s+1.
+	seg := make([][]int, 4*k)
+	var insert func(s, lo, hi, a, b, e int)
+	insert = func(s, lo, hi, a, b, e int) {
+		if b <= lo || hi <= a {
+			return
+		}
+		if a <= lo && hi <= b {
+			seg[s] = append(seg[s], e)
+			return
+		}
+		mid := (lo + hi) / 2
+		insert(2*s, lo, mid, a, b, e)
+		insert(2*s+1, mid, hi, a, b, e)
+	}
+	for e, iv := range c.iv {
+		a := sort.SearchInts(times, iv[0])
+		b := sort.SearchInts(times, iv[1])
+		if a < b {
+			insert(1, 0, k, a, b, e)
+		}
+	}
+	// union-find by size without path compression, so unions can be
+	// rolled back.
+	parent := make([]NI, c.n)
+	size := make([]int, c.n)
+	for i := range parent {
+		parent[i] = NI(i)
+		size[i] = 1
+	}
+	find := func(x NI) NI {
+		for parent[x] != x {
+			x = parent[x]
+		}
+		return x
+	}
+	union := func(e Edge) (r1 NI, ok bool) {
+		r1, r2 := find(e.N1), find(e.N2)
+		if r1 == r2 {
+			return -1, false
+		}
+		if size[r1] > size[r2] {
+			r1, r2 = r2, r1
+		}
+		parent[r1] = r2
+		size[r2] += size[r1]
+		return r1, true
+	}
+	for _, e := range c.always {
+		union(e)
+	}
+	var hist []NI // roots attached by unions, for rollback
+	var walk func(s, lo, hi int)
+	walk = func(s, lo, hi int) {
+		h := len(hist)
+		for _, e := range seg[s] {
+			if r, ok := union(c.edges[e]); ok {
+				hist = append(hist, r)
+			}
+		}
+		if hi-lo == 1 {
+			for _, i := range qt[lo] {
+				res[i] = find(c.qs[i].n1) == find(c.qs[i].n2)
+			}
+		} else {
+			mid := (lo + hi) / 2
+			walk(2*s, lo, mid)
+			walk(2*s+1, mid, hi)
+		}
+		for len(hist) > h {
+			r1 := hist[len(hist)-1]
+			hist = hist[:len(hist)-1]
+			size[parent[r1]] -= size[r1]
+			parent[r1] = r1
+		}
+	}
+	walk(1, 0, k)
+	return res
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleOfflineConnectivity() {
+	// 0---1   2   3
+	g := graph.Undirected{make(graph.AdjacencyList, 4)}
+	g.AddEdge(0, 1)
+	c := graph.NewOfflineConnectivity(g)
+	c.AddEdgeInterval(graph.Edge{1, 2}, 10, 20) // edge 1-2 present at times 10-19
+	c.AddEdgeInterval(graph.Edge{2, 3}, 15, 30)
+	for _, t := range []int{5, 10, 15, 20} {
+		c.AddQuery(0, 3, t)
+		c.AddQuery(0, 2, t)
+	}
+	fmt.Println(c.Solve())
+	// Output:
+	// [false false false true true true false false]
+}
+
+func TestOfflineConnectivity(t *testing.T) {
+	r := rand.New(rand.NewSource(83))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(15)
+		var g graph.Undirected
+		g.AdjacencyList = make(graph.AdjacencyList, n)
+		for j := r.Intn(n); j > 0; j-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		c := graph.NewOfflineConnectivity(g)
+		const T = 50
+		type interval struct {
+			e        graph.Edge
+			from, to int
+		}
+		var ivs []interval
+		for j := r.Intn(3 * n); j > 0; j-- {
+			iv := interval{
+				graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				r.Intn(T), r.Intn(T),
+			}
+			ivs = append(ivs, iv)
+			c.AddEdgeInterval(iv.e, iv.from, iv.to)
+		}
+		type query struct {
+			n1, n2 graph.NI
+			at     int
+		}
+		var qs []query
+		for j := r.Intn(50); j > 0; j-- {
+			q := query{graph.NI(r.Intn(n)), graph.NI(r.Intn(n)), r.Intn(T)}
+			qs = append(qs, q)
+			c.AddQuery(q.n1, q.n2, q.at)
+		}
+		res := c.Solve()
+		if len(res) != len(qs) {
+			t.Fatal(len(res), "results for", len(qs), "queries")
+		}
+		for j, q := range qs {
+			// brute force:  build graph at time of query
+			h := graph.Undirected{make(graph.AdjacencyList, n)}
+			for fr, to := range g.AdjacencyList {
+				h.AdjacencyList[fr] = append([]graph.NI{}, to...)
+			}
+			for _, iv := range ivs {
+				if iv.from <= q.at && q.at < iv.to {
+					h.AddEdge(iv.e.N1, iv.e.N2)
+				}
+			}
+			want := false
+			h.BreadthFirst(q.n1, func(n graph.NI) {
+				if n == q.n2 {
+					want = true
+				}
+			})
+			if res[j] != want {
+				t.Fatal("query", q, "got", res[j], "want", want)
+			}
+		}
+	}
+}
+
+func BenchmarkOfflineConnectivity(b *testing.B) {
+	// a million event timeline:  500k edge insertions and deletions as
+	// intervals, 500k queries.
+	const n = 100000
+	const events = 1000000
+	r := rand.New(rand.NewSource(89))
+	c := graph.NewOfflineConnectivity(graph.Undirected{make(graph.AdjacencyList, n)})
+	for i := 0; i < events/4; i++ {
+		from := r.Intn(events)
+		c.AddEdgeInterval(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+			from, from+r.Intn(events/10))
+	}
+	for i := 0; i < events/2; i++ {
+		c.AddQuery(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)), r.Intn(events))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Solve()
+	}
+}
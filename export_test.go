@@ -0,0 +1,26 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// export_test.go -- access to internals for tests of package graph_test.
+
+import "time"
+
+// AStarAnytimeSolutions is AStarAnytime, calling solution with the eps, path
+// distance, and bound of each completed iteration that finds a path.
+func (g LabeledAdjacencyList) AStarAnytimeSolutions(w WeightFunc, start, end NI, h Heuristic, eps float64, deadline time.Time, solution func(eps, dist, bound float64)) (path LabeledPath, dist, bound float64, optimal bool) {
+	return g.aStarAnytime(w, start, end, h, eps, deadline, solution)
+}
+
+// SummarizePasses is Summarize, calling pass at the start of each pass over
+// the arcs of g.
+func (g Directed) SummarizePasses(strong bool, pass func()) DirectedSummary {
+	return g.summarize(strong, pass)
+}
+
+// SummarizePasses is Summarize, calling pass at the start of each pass over
+// the arcs of g.
+func (g Undirected) SummarizePasses(pass func()) UndirectedSummary {
+	return g.summarize(pass)
+}
@@ -0,0 +1,199 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// features.go -- structural node features for role discovery.
+
+import "math"
+
+// featureCorrelation is the absolute correlation at or above which a
+// recursive feature column is considered a near-duplicate of an earlier
+// column and is pruned.
+const featureCorrelation = .999
+
+// StructuralFeatures computes a vector of structural features for each node
+// of g.
+//
+// The features are local, recursive features in the style of ReFeX, suitable
+// as input to role discovery, where nodes with similar structural position,
+// such as star centers, leaves, or bridges, are grouped regardless of where
+// they are in the graph.
+//
+// The neighbors of a node are the distinct nodes other than itself that it
+// has arcs to.  Its egonet is the node and its neighbors.  The base features,
+// columns 0 through 5, are:
+//
+//	0  degree, the number of arcs from the node
+//	1  egonet arcs, arcs with both ends in the egonet
+//	2  egonet boundary arcs, arcs from the egonet to nodes outside it
+//	3  clustering coefficient, arcs among neighbors over k(k-1) for k
+//	   neighbors, or 0 if k < 2
+//	4  mean degree of neighbors, or 0 if there are no neighbors
+//	5  max degree of neighbors, or 0 if there are no neighbors
+//
+// Arcs are counted with multiplicity.  For an undirected graph, arcs
+// counted in features 1 and 2 thus count each edge twice, but feature 3 is
+// the usual local clustering coefficient.
+//
+// Each recursion, up to the given number of hops, appends for each column
+// added by the previous recursion (the base features for the first) two
+// columns, the mean and the sum of that column over the node's neighbors.
+// An appended column is pruned if it is constant over all nodes or if its
+// absolute correlation with an earlier column is .999 or more.  Recursion
+// stops early if all appended columns are pruned.
+//
+// Returned is features, a slice of feature vectors indexed by node, and
+// names, a name for each column.  Base feature names are "degree",
+// "egoArcs", "egoBoundary", "clustering", "meanNbrDegree", and
+// "maxNbrDegree".  Recursive column names are formed as "mean(c)" and
+// "sum(c)" where c is the name of the aggregated column.  Results are
+// deterministic for a given g and hops.
+func (g AdjacencyList) StructuralFeatures(hops int) (features [][]float64, names []string) {
+	// neighbor lists
+	nbr := make([][]NI, len(g))
+	mark := make([]int, len(g)) // value n+1 marks nodes while processing node n
+	for n, to := range g {
+		mark[n] = n + 1
+		for _, to := range to {
+			if mark[to] != n+1 {
+				mark[to] = n + 1
+				nbr[n] = append(nbr[n], to)
+			}
+		}
+	}
+	// features are built by column, then transposed for the result
+	cols := make([][]float64, 6)
+	for c := range cols {
+		cols[c] = make([]float64, len(g))
+	}
+	for i := range mark {
+		mark[i] = 0
+	}
+	for n, to := range g {
+		deg, ego, bound, among := len(to), 0, 0, 0
+		stamp := n + 1
+		mark[n] = stamp
+		for _, m := range nbr[n] {
+			mark[m] = stamp
+		}
+		sumDeg, maxDeg := 0, 0
+		for _, to := range to {
+			if mark[to] == stamp {
+				ego++
+			} else {
+				bound++
+			}
+		}
+		for _, m := range nbr[n] {
+			d := len(g[m])
+			sumDeg += d
+			if d > maxDeg {
+				maxDeg = d
+			}
+			for _, to := range g[m] {
+				switch {
+				case mark[to] != stamp:
+					bound++
+				case to != NI(n) && to != m:
+					ego++
+					among++
+				default:
+					ego++
+				}
+			}
+		}
+		cols[0][n] = float64(deg)
+		cols[1][n] = float64(ego)
+		cols[2][n] = float64(bound)
+		if k := len(nbr[n]); k >= 2 {
+			cols[3][n] = float64(among) / float64(k*(k-1))
+		}
+		if k := len(nbr[n]); k > 0 {
+			cols[4][n] = float64(sumDeg) / float64(k)
+			cols[5][n] = float64(maxDeg)
+		}
+	}
+	names = []string{"degree", "egoArcs", "egoBoundary", "clustering",
+		"meanNbrDegree", "maxNbrDegree"}
+	prev := 0 // first column added by the previous recursion
+	for h := 0; h < hops; h++ {
+		last := len(cols)
+		for c := prev; c < last; c++ {
+			mean := make([]float64, len(g))
+			sum := make([]float64, len(g))
+			for n, nb := range nbr {
+				s := 0.
+				for _, m := range nb {
+					s += cols[c][m]
+				}
+				sum[n] = s
+				if len(nb) > 0 {
+					mean[n] = s / float64(len(nb))
+				}
+			}
+			if !featureDuplicate(cols, mean) {
+				cols = append(cols, mean)
+				names = append(names, "mean("+names[c]+")")
+			}
+			if !featureDuplicate(cols, sum) {
+				cols = append(cols, sum)
+				names = append(names, "sum("+names[c]+")")
+			}
+		}
+		if len(cols) == last {
+			break
+		}
+		prev = last
+	}
+	// transpose, with feature vectors in a single allocation
+	features = make([][]float64, len(g))
+	all := make([]float64, len(g)*len(cols))
+	for n := range features {
+		f := all[n*len(cols) : (n+1)*len(cols)]
+		for c, col := range cols {
+			f[c] = col[n]
+		}
+		features[n] = f
+	}
+	return
+}
+
+// featureDuplicate returns true if column col is constant or is highly
+// correlated with any column of cols.
+func featureDuplicate(cols [][]float64, col []float64) bool {
+	mc, sc := featureMeanDev(col)
+	if sc == 0 {
+		return true
+	}
+	for _, x := range cols {
+		mx, sx := featureMeanDev(x)
+		if sx == 0 {
+			continue
+		}
+		cov := 0.
+		for i, v := range x {
+			cov += (v - mx) * (col[i] - mc)
+		}
+		if math.Abs(cov/(sx*sc)) >= featureCorrelation {
+			return true
+		}
+	}
+	return false
+}
+
+// featureMeanDev returns the mean of col and the square root of the sum of
+// squared deviations from the mean.
+func featureMeanDev(col []float64) (mean, dev float64) {
+	if len(col) == 0 {
+		return
+	}
+	for _, v := range col {
+		mean += v
+	}
+	mean /= float64(len(col))
+	for _, v := range col {
+		dev += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(dev)
+}
@@ -0,0 +1,102 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleAdjacencyList_StructuralFeatures() {
+	//    2
+	//   / \
+	//  0---1---3
+	f, names := graph.Undirected{graph.AdjacencyList{
+		0: {1, 2},
+		1: {2, 0, 3},
+		2: {0, 1},
+		3: {1},
+	}}.StructuralFeatures(0)
+	fmt.Println(names)
+	for n, f := range f {
+		fmt.Printf("%d: %.2f\n", n, f)
+	}
+	// Output:
+	// [degree egoArcs egoBoundary clustering meanNbrDegree maxNbrDegree]
+	// 0: [2.00 6.00 1.00 1.00 2.50 3.00]
+	// 1: [3.00 8.00 0.00 0.33 1.67 2.00]
+	// 2: [2.00 6.00 1.00 1.00 2.50 3.00]
+	// 3: [1.00 2.00 2.00 0.00 3.00 3.00]
+}
+
+func TestStructuralFeaturesRoles(t *testing.T) {
+	// a ring of k stars with 5 leaves each, adjacent star centers joined
+	// through a bridge node.  roles are center, bridge, and leaf.
+	const k = 6
+	var g graph.Undirected
+	role := map[graph.NI]string{}
+	center := func(i int) graph.NI { return graph.NI(i * 7) }
+	for i := 0; i < k; i++ {
+		c := center(i)
+		role[c] = "center"
+		for l := c + 1; l <= c+5; l++ {
+			g.AddEdge(c, l)
+			role[l] = "leaf"
+		}
+		b := c + 6
+		role[b] = "bridge"
+		g.AddEdge(c, b)
+		g.AddEdge(b, center((i+1)%k))
+	}
+	f, names := g.StructuralFeatures(2)
+	if len(f) != g.Order() {
+		t.Fatal(len(f), "feature vectors for", g.Order(), "nodes")
+	}
+	dist := func(a, b []float64) float64 {
+		d := 0.
+		for i, x := range a {
+			d += (x - b[i]) * (x - b[i])
+		}
+		return math.Sqrt(d)
+	}
+	for n1, f1 := range f {
+		if len(f1) != len(names) {
+			t.Fatal(len(f1), "features,", len(names), "names")
+		}
+		for n2, f2 := range f {
+			d := dist(f1, f2)
+			if role[graph.NI(n1)] == role[graph.NI(n2)] {
+				if d > 1e-9 {
+					t.Fatal(role[graph.NI(n1)], n1, n2, "differ by", d)
+				}
+			} else if d < 1 {
+				t.Fatal(role[graph.NI(n1)], n1, role[graph.NI(n2)], n2,
+					"differ by only", d)
+			}
+		}
+	}
+}
+
+func TestStructuralFeaturesColumns(t *testing.T) {
+	// regression test on number of columns retained after pruning
+	g := graph.GnmUndirected(100, 300, rand.New(rand.NewSource(3)))
+	for hops, want := range []int{6, 17, 38, 73, 116} {
+		f, names := g.StructuralFeatures(hops)
+		if len(names) != want || len(f[0]) != want {
+			t.Fatal("hops", hops, len(names), "columns, want", want)
+		}
+	}
+	// with all nodes alike, recursive columns are constant and pruned
+	var c graph.Undirected
+	for n := graph.NI(0); n < 5; n++ {
+		c.AddEdge(n, (n+1)%5)
+	}
+	if _, names := c.StructuralFeatures(3); len(names) != 6 {
+		t.Fatal("cycle", names)
+	}
+}
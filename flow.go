@@ -0,0 +1,282 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// flow.go -- residual network machinery for max flow and min cut
+// computations.
+
+import (
+	"math"
+
+	"github.com/soniakeys/bits"
+)
+
+// flowInf is the capacity used for arcs that should never be cut.
+var flowInf = math.Inf(1)
+
+// flowArc is an arc of a residual network.
+type flowArc struct {
+	to  NI
+	rev int     // index of the reverse arc in the arc list of to
+	cap float64 // residual capacity
+}
+
+// flowNet is a residual network.  Each arc added with addArc is paired
+// with a reverse arc of zero initial capacity.
+type flowNet [][]flowArc
+
+// addArc adds an arc with capacity c and returns its index in the arc list
+// of fr.  Loops are ignored and return -1.
+func (f flowNet) addArc(fr, to NI, c float64) int {
+	if fr == to {
+		return -1
+	}
+	f[fr] = append(f[fr], flowArc{to, len(f[to]), c})
+	f[to] = append(f[to], flowArc{fr, len(f[fr]) - 1, 0})
+	return len(f[fr]) - 1
+}
+
+// maxFlow pushes flow from s to t along shortest augmenting paths
+// (Edmonds-Karp) and returns the flow value.
+//
+// Augmentation stops early once the flow exceeds limit.  A negative limit
+// means no limit.  The residual capacities of f reflect the flow pushed.
+// If a path of infinite capacity is found, maxFlow returns +Inf.
+func (f flowNet) maxFlow(s, t NI, limit float64) (flow float64) {
+	type pred struct {
+		fr NI
+		x  int // index of the arc in the arc list of fr
+	}
+	p := make([]pred, len(f))
+	q := make([]NI, 0, len(f))
+	for limit < 0 || flow <= limit {
+		for i := range p {
+			p[i].fr = -1
+		}
+		p[s].fr = s
+		q = append(q[:0], s)
+	bfs:
+		for len(q) > 0 {
+			fr := q[0]
+			q = q[1:]
+			for x, a := range f[fr] {
+				if a.cap > 0 && p[a.to].fr < 0 {
+					p[a.to] = pred{fr, x}
+					if a.to == t {
+						break bfs
+					}
+					q = append(q, a.to)
+				}
+			}
+		}
+		if p[t].fr < 0 {
+			return
+		}
+		b := flowInf
+		for n := t; n != s; n = p[n].fr {
+			if c := f[p[n].fr][p[n].x].cap; c < b {
+				b = c
+			}
+		}
+		if b == flowInf {
+			return flowInf
+		}
+		for n := t; n != s; n = p[n].fr {
+			a := &f[p[n].fr][p[n].x]
+			a.cap -= b
+			f[n][a.rev].cap += b
+		}
+		flow += b
+	}
+	return
+}
+
+// reach returns the set of nodes reachable from s in the residual network.
+func (f flowNet) reach(s NI) bits.Bits {
+	b := bits.New(len(f))
+	b.SetBit(int(s), 1)
+	q := []NI{s}
+	for len(q) > 0 {
+		fr := q[0]
+		q = q[1:]
+		for _, a := range f[fr] {
+			if a.cap > 0 && b.Bit(int(a.to)) == 0 {
+				b.SetBit(int(a.to), 1)
+				q = append(q, a.to)
+			}
+		}
+	}
+	return b
+}
+
+// dinic pushes blocking flows from s to t along level graphs (Dinic's
+// algorithm) and returns the flow value.
+//
+// The residual capacities of f reflect the flow pushed.  If a path of
+// infinite capacity is found, dinic returns +Inf.
+func (f flowNet) dinic(s, t NI) (flow float64) {
+	level := make([]int, len(f))
+	it := make([]int, len(f)) // index of next arc to try, by node
+	q := make([]NI, 0, len(f))
+	var push func(n NI, b float64) float64
+	push = func(n NI, b float64) float64 {
+		if n == t {
+			return b
+		}
+		for ; it[n] < len(f[n]); it[n]++ {
+			a := &f[n][it[n]]
+			if a.cap > 0 && level[a.to] == level[n]+1 {
+				if d := push(a.to, math.Min(b, a.cap)); d > 0 {
+					if d < flowInf {
+						a.cap -= d
+						f[a.to][a.rev].cap += d
+					}
+					return d
+				}
+			}
+		}
+		return 0
+	}
+	for {
+		for i := range level {
+			level[i] = -1
+		}
+		level[s] = 0
+		q = append(q[:0], s)
+		for len(q) > 0 {
+			fr := q[0]
+			q = q[1:]
+			for _, a := range f[fr] {
+				if a.cap > 0 && level[a.to] < 0 {
+					level[a.to] = level[fr] + 1
+					q = append(q, a.to)
+				}
+			}
+		}
+		if level[t] < 0 {
+			return
+		}
+		for i := range it {
+			it[i] = 0
+		}
+		for {
+			d := push(s, flowInf)
+			if d == 0 {
+				break
+			}
+			if d == flowInf {
+				return flowInf
+			}
+			flow += d
+		}
+	}
+}
+
+// MaxFlow computes maximum flows and minimum cuts in a LabeledDirected
+// graph.
+//
+// Arc capacities are given by a function of arc labels.  Each arc has its own
+// capacity, so parallel arcs are supported.  Anti-parallel arcs are not
+// merged; flow on an arc is independent of flow on the arc in the opposite
+// direction.
+//
+// Construct with NewMaxFlow.  A MaxFlow can be reused for multiple source and
+// sink pairs.
+type MaxFlow struct {
+	g   LabeledDirected
+	cap [][]float64 // arc capacities, indexed as the arcs of g
+	net flowNet
+	x   [][]int // index of arc in net, by index in g
+	s   NI      // source of last Run
+}
+
+// NewMaxFlow constructs a MaxFlow for graph g where the capacity of an arc is
+// cap(label).  Capacities must be non-negative.  They may be +Inf.
+//
+// Capacities are evaluated once, at construction.
+func NewMaxFlow(g LabeledDirected, cap func(LI) float64) *MaxFlow {
+	a := g.LabeledAdjacencyList
+	m := &MaxFlow{
+		g:   g,
+		cap: make([][]float64, len(a)),
+		net: make(flowNet, len(a)),
+		x:   make([][]int, len(a)),
+		s:   -1,
+	}
+	for fr, to := range a {
+		m.cap[fr] = make([]float64, len(to))
+		m.x[fr] = make([]int, len(to))
+		for i, h := range to {
+			c := cap(h.Label)
+			m.cap[fr][i] = c
+			m.x[fr][i] = m.net.addArc(NI(fr), h.To, c)
+		}
+	}
+	return m
+}
+
+// Run computes a maximum flow from source to sink, which must be distinct.
+//
+// The algorithm is Dinic's, with time complexity O(n²m) for a graph with n
+// nodes and m arcs.
+//
+// Returned are the flow value and flows indexed as the arcs of the graph,
+// that is, flows[fr][x] is the flow on arc g[fr][x].  Loops carry no flow.
+// If there is a path of infinite capacity from source to sink, Run returns
+// flow = +Inf and flows = nil.
+//
+// See MinCut for the minimum cut corresponding to the last Run.
+func (m *MaxFlow) Run(source, sink NI) (flow float64, flows [][]float64) {
+	// reset residual capacities
+	for fr, to := range m.g.LabeledAdjacencyList {
+		for i, h := range to {
+			if x := m.x[fr][i]; x >= 0 {
+				a := &m.net[fr][x]
+				a.cap = m.cap[fr][i]
+				m.net[h.To][a.rev].cap = 0
+			}
+		}
+	}
+	m.s = source
+	flow = m.net.dinic(source, sink)
+	if flow == flowInf {
+		return
+	}
+	flows = make([][]float64, len(m.net))
+	for fr, to := range m.g.LabeledAdjacencyList {
+		flows[fr] = make([]float64, len(to))
+		for i, h := range to {
+			if x := m.x[fr][i]; x >= 0 {
+				// flow is the residual capacity of the reverse arc.
+				// this works even for infinite capacity arcs.
+				flows[fr][i] = m.net[h.To][m.net[fr][x].rev].cap
+			}
+		}
+	}
+	return
+}
+
+// MinCut returns a minimum cut corresponding to the last Run.
+//
+// The cut is returned as the set of nodes on the source side, the nodes
+// reachable from the source in the residual network.  The capacities of arcs
+// leaving this set sum to the max flow value.
+//
+// If Run has not been called, MinCut returns an empty set.
+func (m *MaxFlow) MinCut() bits.Bits {
+	if m.s < 0 {
+		return bits.New(len(m.net))
+	}
+	return m.net.reach(m.s)
+}
+
+// MaxFlowValue returns the value of a maximum flow from s to t where arc
+// capacities are given by cap(label).
+//
+// It is a convenience method for the common case where only the flow value
+// is needed.  See MaxFlow for details.
+func (g LabeledDirected) MaxFlowValue(s, t NI, cap func(LI) float64) float64 {
+	f, _ := NewMaxFlow(g, cap).Run(s, t)
+	return f
+}
@@ -0,0 +1,170 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleMaxFlow() {
+	// arcs directed right, capacities in parentheses:
+	//       (3)
+	//    1-------3
+	//   /(2)     |\(2)
+	//  0    (1)  | 5
+	//   \(2)     |/(3)
+	//    2-------4
+	//       (3)
+	// with arc 3->4 having capacity 1 and two parallel arcs 0->1.
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 2}, {To: 1, Label: 2}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 3}},
+		2: {{To: 4, Label: 3}},
+		3: {{To: 4, Label: 1}, {To: 5, Label: 2}},
+		4: {{To: 5, Label: 3}},
+		5: {},
+	}}
+	m := graph.NewMaxFlow(g, func(l graph.LI) float64 { return float64(l) })
+	f, flows := m.Run(0, 5)
+	fmt.Println("flow:", f)
+	for fr, to := range g.LabeledAdjacencyList {
+		for x, h := range to {
+			fmt.Printf("%d->%d: %g/%d\n", fr, h.To, flows[fr][x], h.Label)
+		}
+	}
+	fmt.Println("cut:", m.MinCut().Slice())
+	// Output:
+	// flow: 5
+	// 0->1: 2/2
+	// 0->1: 1/2
+	// 0->2: 2/2
+	// 1->3: 3/3
+	// 2->4: 2/3
+	// 3->4: 1/1
+	// 3->5: 2/2
+	// 4->5: 3/3
+	// cut: [0 1]
+}
+
+func ExampleLabeledDirected_MaxFlowValue() {
+	// anti-parallel arcs 1->2 and 2->1 are independent
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 5}, {To: 2, Label: 5}},
+		1: {{To: 2, Label: 4}, {To: 3, Label: 1}},
+		2: {{To: 1, Label: 4}, {To: 3, Label: 2}},
+		3: {},
+	}}
+	fmt.Println(g.MaxFlowValue(0, 3, func(l graph.LI) float64 { return float64(l) }))
+	// Output:
+	// 3
+}
+
+func TestMaxFlowBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(67))
+	cap := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 200; i++ {
+		n := 2 + r.Intn(7)
+		g := graph.LabeledDirected{make(graph.LabeledAdjacencyList, n)}
+		for j := r.Intn(3 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			g.LabeledAdjacencyList[fr] = append(g.LabeledAdjacencyList[fr],
+				graph.Half{To: graph.NI(r.Intn(n)), Label: graph.LI(r.Intn(10))})
+		}
+		s, tn := graph.NI(0), graph.NI(n-1)
+		m := graph.NewMaxFlow(g, cap)
+		f, flows := m.Run(s, tn)
+		// flows respect capacities and are conserved
+		bal := make([]float64, n)
+		for fr, to := range g.LabeledAdjacencyList {
+			for x, h := range to {
+				fl := flows[fr][x]
+				if fl < 0 || fl > cap(h.Label) {
+					t.Fatal("arc", fr, h.To, "flow", fl, "capacity", h.Label)
+				}
+				bal[fr] -= fl
+				bal[h.To] += fl
+			}
+		}
+		for nd, b := range bal {
+			want := 0.
+			switch graph.NI(nd) {
+			case s:
+				want = -f
+			case tn:
+				want = f
+			}
+			if b != want {
+				t.Fatal("node", nd, "imbalance", b, "want", want)
+			}
+		}
+		// cut capacity equals flow
+		c := m.MinCut()
+		if c.Bit(int(s)) != 1 || c.Bit(int(tn)) != 0 {
+			t.Fatal("cut", c.Slice(), "does not separate", s, tn)
+		}
+		cutCap := func(in func(int) bool) (sum float64) {
+			for fr, to := range g.LabeledAdjacencyList {
+				for _, h := range to {
+					if in(fr) && !in(int(h.To)) {
+						sum += cap(h.Label)
+					}
+				}
+			}
+			return
+		}
+		if cc := cutCap(func(nd int) bool { return c.Bit(nd) == 1 }); cc != f {
+			t.Fatal("cut capacity", cc, "flow", f)
+		}
+		// no cut is smaller
+		for sub := 0; sub < 1<<uint(n); sub++ {
+			if sub&1 == 0 || sub>>uint(n-1)&1 == 1 {
+				continue
+			}
+			if cc := cutCap(func(nd int) bool { return sub>>uint(nd)&1 == 1 }); cc < f {
+				t.Fatal("cut", sub, "capacity", cc, "less than flow", f)
+			}
+		}
+		// reuse with a different pair gives same result as fresh MaxFlow
+		if n > 2 {
+			f1, _ := m.Run(1, tn)
+			if f2 := g.MaxFlowValue(1, tn, cap); f1 != f2 {
+				t.Fatal("reused", f1, "fresh", f2)
+			}
+		}
+	}
+}
+
+func TestMaxFlowInf(t *testing.T) {
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: -1}, {To: 2, Label: 3}},
+		1: {{To: 2, Label: -1}, {To: 3, Label: 2}},
+		2: {{To: 3, Label: -1}},
+		3: {},
+	}}
+	cap := func(l graph.LI) float64 {
+		if l < 0 {
+			return math.Inf(1)
+		}
+		return float64(l)
+	}
+	if f := g.MaxFlowValue(0, 3, cap); !math.IsInf(f, 1) {
+		t.Fatal("flow", f, "want +Inf")
+	}
+	m := graph.NewMaxFlow(g, cap)
+	f, flows := m.Run(1, 3)
+	if !math.IsInf(f, 1) || flows != nil {
+		t.Fatal("flow", f, flows)
+	}
+	// finite flow through infinite capacity arcs
+	g.LabeledAdjacencyList[2][0].Label = 4
+	f, flows = graph.NewMaxFlow(g, cap).Run(0, 3)
+	if f != 6 || flows[2][0] != 4 || flows[1][1] != 2 {
+		t.Fatal("flow", f, flows)
+	}
+}
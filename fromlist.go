@@ -80,12 +80,16 @@ func (f FromList) BoundsOk() (ok bool, n NI) {
 
 // CommonStart returns the common start node of minimal paths to a and b.
 //
-// It returns -1 if a and b cannot be traced back to a common node.
+// It returns -1 if a and b cannot be traced back to a common node,
+// including when either has no path, that is, PathEnd.Len 0.
 //
 // The method relies on populated PathEnd.Len members.  Use RecalcLen if
 // the Len members are not known to be present and correct.
 func (f FromList) CommonStart(a, b NI) NI {
 	p := f.Paths
+	if p[a].Len == 0 || p[b].Len == 0 {
+		return -1
+	}
 	if p[a].Len < p[b].Len {
 		a, b = b, a
 	}
@@ -149,6 +153,14 @@ func (f FromList) IsolatedNodes() (iso bits.Bits) {
 	return
 }
 
+// PathLenTo returns the number of nodes in the path to end.
+//
+// This is simply Paths[end].Len, 1 for a root and 0 if there is no path to
+// end.  The number of arcs in the path is one less.
+func (f FromList) PathLenTo(end NI) int {
+	return f.Paths[end].Len
+}
+
 // PathTo decodes a FromList, recovering a single path.
 //
 // The path is returned as a list of nodes where the first element will be
@@ -205,13 +217,21 @@ func PathTo(paths []PathEnd, end NI, p []NI) []NI {
 // FromList do not need to be valid, however the MaxLen member can be useful
 // for allocating argument p.
 //
+// Argument labels gives the label of the arc leading to each node, as
+// returned by labeled search methods.  If end is a root, the returned path
+// has Start end and an empty Path.  If end has no path, that is, has
+// Len 0 in the FromList, the zero value LabeledPath is returned.
+//
 // Argument p can provide the result slice.  If p has capacity for the result
 // it will be used, otherwise a new slice is created for the result.
 //
 // See also function PathTo.
 func (f FromList) PathToLabeled(end NI, labels []LI, p []Half) LabeledPath {
 	n := f.Paths[end].Len - 1
-	if n <= 0 {
+	if n < 0 {
+		return LabeledPath{}
+	}
+	if n == 0 {
 		return LabeledPath{end, p[:0]}
 	}
 	if cap(p) >= n {
@@ -229,6 +249,23 @@ func (f FromList) PathToLabeled(end NI, labels []LI, p []Half) LabeledPath {
 	}
 }
 
+// PathToRoot visits the nodes of the path to end, starting with end and
+// ending with the root of the path.
+//
+// It is an alternative to PathTo that does not allocate.  Visitor function
+// visit returns true to continue, false to stop.  PathToRoot returns false
+// if stopped by visit, true otherwise.  If end has no path, that is, has
+// Len 0 in the FromList, visit is not called.
+func (f FromList) PathToRoot(end NI, visit func(NI) bool) bool {
+	for n := f.Paths[end].Len; n > 0; n-- {
+		if !visit(end) {
+			return false
+		}
+		end = f.Paths[end].From
+	}
+	return true
+}
+
 // Preorder traverses a FromList in preorder.
 //
 // Nodes are visited in order such that for any node n with from node fr,
@@ -420,6 +457,35 @@ func (f FromList) TransposeLabeled(labels []LI, roots *bits.Bits) (forest Labele
 	return LabeledDirected{g}, nRoots
 }
 
+// TreePathBetween returns the path through the tree between nodes a and b.
+//
+// The path starts at a, leads back toward the root to the common start node
+// of the paths to a and b, then on to b.  The common start node is returned
+// as ancestor.  If a and b are in different trees of the forest or either
+// has no path, TreePathBetween returns nil, -1.
+//
+// The method relies on populated PathEnd.Len members, as with CommonStart.
+func (f FromList) TreePathBetween(a, b NI) (path []NI, ancestor NI) {
+	ancestor = f.CommonStart(a, b)
+	if ancestor < 0 {
+		return nil, -1
+	}
+	p := f.Paths
+	la := p[a].Len - p[ancestor].Len
+	lb := p[b].Len - p[ancestor].Len
+	path = make([]NI, la+lb+1)
+	for i, n := 0, a; i < la; i++ {
+		path[i] = n
+		n = p[n].From
+	}
+	path[la] = ancestor
+	for i, n := len(path)-1, b; i > la; i-- {
+		path[i] = n
+		n = p[n].From
+	}
+	return path, ancestor
+}
+
 // Undirected constructs the undirected graph corresponding to FromList f.
 //
 // The resulting graph will be a tree or forest.
@@ -191,6 +191,72 @@ func ExampleFromList_PathToLabeled() {
 	// 4 {1 x} {0 y}
 }
 
+func ExampleFromList_PathToLabeled_roots() {
+	// 1 is a root, 2 has no path
+	//
+	//     1
+	// 'a'/
+	//   0    2
+	t := &graph.FromList{
+		Paths: []graph.PathEnd{
+			0: {From: 1, Len: 2},
+			1: {From: -1, Len: 1},
+			2: {From: 0, Len: 0},
+		},
+	}
+	labels := []graph.LI{0: 'a'}
+	fmt.Printf("%#v\n", t.PathToLabeled(0, labels, nil))
+	fmt.Printf("%#v\n", t.PathToLabeled(1, labels, nil))
+	fmt.Printf("%#v\n", t.PathToLabeled(2, labels, nil))
+	// Output:
+	// graph.LabeledPath{Start:1, Path:[]graph.Half{graph.Half{To:0, Label:97}}}
+	// graph.LabeledPath{Start:1, Path:[]graph.Half(nil)}
+	// graph.LabeledPath{Start:0, Path:[]graph.Half(nil)}
+}
+
+func ExampleFromList_PathLenTo() {
+	//   0
+	//  /
+	// 1   2
+	t := &graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1, Len: 1},
+		1: {From: 0, Len: 2},
+		2: {From: 0, Len: 0},
+	}}
+	for n := range t.Paths {
+		fmt.Println(n, t.PathLenTo(graph.NI(n)))
+	}
+	// Output:
+	// 0 1
+	// 1 2
+	// 2 0
+}
+
+func ExampleFromList_PathToRoot() {
+	//       4  3
+	//      /
+	//     1
+	//    / \
+	//   0   2
+	t := &graph.FromList{
+		Paths: []graph.PathEnd{
+			4: {From: -1, Len: 1},
+			3: {From: -1, Len: 1},
+			1: {From: 4, Len: 2},
+			0: {From: 1, Len: 3},
+			2: {From: 1, Len: 3},
+		},
+	}
+	t.PathToRoot(2, func(n graph.NI) bool {
+		fmt.Println(n)
+		return true
+	})
+	// Output:
+	// 2
+	// 1
+	// 4
+}
+
 func ExampleFromList_Preorder() {
 	//     2
 	//    / \
@@ -492,6 +558,37 @@ func ExampleFromList_TransposeLabeled_roots() {
 	// 2 roots: [0 4]
 }
 
+func ExampleFromList_TreePathBetween() {
+	//   4   5
+	//  /   /
+	// 6   1    7 (no path)
+	//    / \
+	//   0   2
+	//  /
+	// 3
+	t := &graph.FromList{Paths: []graph.PathEnd{
+		4: {From: -1, Len: 1},
+		6: {From: 4, Len: 2},
+		5: {From: -1, Len: 1},
+		1: {From: 5, Len: 2},
+		0: {From: 1, Len: 3},
+		2: {From: 1, Len: 3},
+		3: {From: 0, Len: 4},
+		7: {From: 0, Len: 0},
+	}}
+	fmt.Println(t.TreePathBetween(3, 2))
+	fmt.Println(t.TreePathBetween(5, 3))
+	fmt.Println(t.TreePathBetween(0, 0))
+	fmt.Println(t.TreePathBetween(6, 3))
+	fmt.Println(t.TreePathBetween(7, 3))
+	// Output:
+	// [3 0 1 2] 1
+	// [5 1 0 3] 5
+	// [0] 0
+	// [] -1
+	// [] -1
+}
+
 func ExampleFromList_Undirected() {
 	//    0   3
 	//   / \
@@ -0,0 +1,151 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// girth.go -- girth and shortest cycles.
+
+// Girth returns the girth of g, the length of a shortest cycle, and a
+// shortest cycle.
+//
+// The cycle is a list of nodes as for CanonicalUndirectedCycle, where an
+// edge connects each node to the next and the last node back to the first.
+// Its length is the number of edges, equal to the number of nodes.
+//
+// For multigraphs, a loop is a cycle of length 1 and a pair of parallel
+// edges is a cycle of length 2.  If g has a loop, the girth is 1 and the
+// cycle is the single node of the loop.  Otherwise if g has parallel edges,
+// the girth is 2 and the cycle is the two nodes of the edges.  If g has no
+// cycles, that is, g is a forest, Girth returns -1, nil.
+//
+// Otherwise a breadth first search is run from each node.  A search finds
+// the shortest cycle through its start node, or a cycle no longer, and it
+// stops early when nodes are too far from the start to close a cycle
+// shorter than the shortest found so far.  Time is O(nm) in the worst case.
+func (g Undirected) Girth() (girth int, cycle []NI) {
+	a := g.AdjacencyList
+	if lp, n := a.AnyLoop(); lp {
+		return 1, []NI{n}
+	}
+	if found, fr, x := a.NonSimpleArc(); found {
+		return 2, []NI{fr, a[fr][x]}
+	}
+	girth = -1
+	dist := make([]int, len(a))
+	for i := range dist {
+		dist[i] = -1
+	}
+	parent := make([]NI, len(a))
+	var queue []NI
+	for s := range a {
+		var cx, cy NI // edge closing the shortest cycle from s
+		best := girth
+		dist[s] = 0
+		parent[s] = -1
+		queue = append(queue[:0], NI(s))
+	search:
+		for i := 0; i < len(queue); i++ {
+			x := queue[i]
+			if best > 0 && 2*dist[x]+1 >= best {
+				break // no shorter cycle closes from x or beyond
+			}
+			for _, y := range a[x] {
+				switch {
+				case dist[y] < 0:
+					dist[y] = dist[x] + 1
+					parent[y] = x
+					queue = append(queue, y)
+				case y != parent[x]:
+					if l := dist[x] + dist[y] + 1; best < 0 || l < best {
+						best, cx, cy = l, x, y
+						if 2*dist[x]+1 >= best {
+							break search
+						}
+					}
+				}
+			}
+		}
+		if best != girth {
+			// s..cx, then cy back toward s
+			girth = best
+			cycle = make([]NI, 0, best)
+			for n := cx; n >= 0; n = parent[n] {
+				cycle = append(cycle, n)
+			}
+			for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+				cycle[i], cycle[j] = cycle[j], cycle[i]
+			}
+			for n := cy; n != NI(s); n = parent[n] {
+				cycle = append(cycle, n)
+			}
+		}
+		for _, n := range queue {
+			dist[n] = -1
+		}
+	}
+	return
+}
+
+// ShortestCycle returns the length of a shortest cycle of g and a shortest
+// cycle.
+//
+// The cycle is a list of nodes as for CanonicalCycle, where an arc leads from
+// each node to the next and from the last node back to the first.  Its
+// length is the number of arcs, equal to the number of nodes.
+//
+// A loop is a cycle of length 1.  If g has a loop, ShortestCycle returns 1
+// and the single node of the loop.  Parallel arcs do not form cycles but a
+// pair of arcs in opposite directions is a cycle of length 2.  If g has no
+// cycles, that is, g is a DAG, ShortestCycle returns -1, nil.
+//
+// Otherwise a breadth first search is run from each node to find the
+// shortest cycle through the node.  A search stops early when nodes are too
+// far from the start to close a cycle shorter than the shortest found so
+// far.  Time is O(nm) in the worst case.
+func (g Directed) ShortestCycle() (length int, cycle []NI) {
+	a := g.AdjacencyList
+	if lp, n := a.AnyLoop(); lp {
+		return 1, []NI{n}
+	}
+	length = -1
+	dist := make([]int, len(a))
+	for i := range dist {
+		dist[i] = -1
+	}
+	parent := make([]NI, len(a))
+	var queue []NI
+	for s := range a {
+		last := NI(-1) // node with arc closing a shortest cycle through s
+		dist[s] = 0
+		parent[s] = -1
+		queue = append(queue[:0], NI(s))
+	search:
+		for i := 0; i < len(queue); i++ {
+			x := queue[i]
+			if length > 0 && dist[x]+1 >= length {
+				break // no shorter cycle closes from x or beyond
+			}
+			for _, y := range a[x] {
+				switch {
+				case y == NI(s):
+					length, last = dist[x]+1, x
+					break search
+				case dist[y] < 0:
+					dist[y] = dist[x] + 1
+					parent[y] = x
+					queue = append(queue, y)
+				}
+			}
+		}
+		if last >= 0 {
+			cycle = make([]NI, length)
+			for i, n := length-1, last; i >= 0; i, n = i-1, parent[n] {
+				cycle[i] = n
+			}
+		}
+		for _, n := range queue {
+			dist[n] = -1
+		}
+	}
+	return
+}
@@ -0,0 +1,139 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_Girth() {
+	//   0---1
+	//  /     \
+	// 5   6   2
+	//  \ / \ /
+	//   4   3
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 5)
+	g.AddEdge(5, 0)
+	g.AddEdge(4, 6)
+	g.AddEdge(6, 3)
+	fmt.Println(g.Girth())
+	g.AddEdge(2, 1)
+	fmt.Println(g.Girth())
+	g.AddEdge(5, 5)
+	fmt.Println(g.Girth())
+	// Output:
+	// 3 [3 4 6]
+	// 2 [1 2]
+	// 1 [5]
+}
+
+func ExampleDirected_ShortestCycle() {
+	// 0-->1-->2
+	// ^   ^   |
+	// |   |   v
+	// 5<--4<--3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {3},
+		3: {4},
+		4: {5, 1},
+		5: {0},
+	}}
+	fmt.Println(g.ShortestCycle())
+	// Output:
+	// 4 [1 2 3 4]
+}
+
+// TestGirth checks Girth and ShortestCycle against the minimum over arcs
+// fr->to of the distance from to back to fr, on random graphs.
+func TestGirth(t *testing.T) {
+	r := rand.New(rand.NewSource(41))
+	for i := 0; i < 100; i++ {
+		u := graph.GnmUndirected(30, 25+r.Intn(20), r)
+		want := -1
+		for fr, to := range u.AdjacencyList {
+			for _, to := range to {
+				c, _ := u.Copy()
+				c.RemoveEdge(graph.NI(fr), to)
+				if d := c.AdjacencyList.Distances(to)[fr]; d >= 0 &&
+					(want < 0 || d+1 < want) {
+					want = d + 1
+				}
+			}
+		}
+		girth, cycle := u.Girth()
+		if girth != want {
+			t.Fatal("girth", girth, "want", want)
+		}
+		checkCycle(t, u.AdjacencyList, girth, cycle)
+
+		d := graph.GnmDirected(30, 30+r.Intn(30), r)
+		want = -1
+		for fr, to := range d.AdjacencyList {
+			for _, to := range to {
+				if x := d.AdjacencyList.Distances(to)[fr]; x >= 0 &&
+					(want < 0 || x+1 < want) {
+					want = x + 1
+				}
+			}
+		}
+		length, cycle := d.ShortestCycle()
+		if length != want {
+			t.Fatal("shortest cycle", length, "want", want)
+		}
+		checkCycle(t, d.AdjacencyList, length, cycle)
+	}
+}
+
+// checkCycle checks that cycle is an elementary cycle of g with length
+// nodes, or nil for length -1.
+func checkCycle(t *testing.T, g graph.AdjacencyList, length int, cycle []graph.NI) {
+	if length < 0 {
+		if cycle != nil {
+			t.Fatal("cycle", cycle, "for length -1")
+		}
+		return
+	}
+	if len(cycle) != length {
+		t.Fatal("cycle", cycle, "length", length)
+	}
+	seen := map[graph.NI]bool{}
+	fr := cycle[len(cycle)-1]
+	for _, to := range cycle {
+		if seen[to] {
+			t.Fatal("cycle", cycle, "not elementary")
+		}
+		seen[to] = true
+		if ok, _ := g.HasArc(fr, to); !ok {
+			t.Fatal("cycle", cycle, "no arc", fr, to)
+		}
+		fr = to
+	}
+}
+
+func BenchmarkGirth(b *testing.B) {
+	g := graph.GnmUndirected(10000, 20000, rand.New(rand.NewSource(43)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Girth()
+	}
+}
+
+func BenchmarkShortestCycle(b *testing.B) {
+	g := graph.GnmDirected(10000, 20000, rand.New(rand.NewSource(43)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.ShortestCycle()
+	}
+}
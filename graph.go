@@ -554,6 +554,23 @@ func (d DistanceMatrix) FloydWarshallFromLists() []FromList {
 	return l
 }
 
+// HasNegativeCycle checks a distance matrix processed by one of the
+// FloydWarshall methods for a negative cycle.
+//
+// A negative cycle exists if any diagonal element is negative.  In this case
+// HasNegativeCycle returns true and a node n on a negative cycle.  Otherwise
+// it returns false and n = -1.
+//
+// See LabeledDirected.NegativeCycle for a method returning the cycle.
+func (d DistanceMatrix) HasNegativeCycle() (has bool, n NI) {
+	for i, di := range d {
+		if di[i] < 0 {
+			return true, NI(i)
+		}
+	}
+	return false, -1
+}
+
 // AddEdge adds an edge to a subgraph.
 //
 // For argument e, e.N1 and e.N2 must be NIs in supergraph s.Super.  As with
@@ -756,6 +773,63 @@ func mapBits(t bits.Bits) (sub map[NI]NI, sup []NI) {
 	return
 }
 
+// utility functions called from the Lift and PushBits subgraph methods.
+
+func liftFromList(sup []NI, order int, f FromList) FromList {
+	l := FromList{Paths: make([]PathEnd, order), MaxLen: f.MaxLen}
+	for n := range l.Paths {
+		l.Paths[n].From = -1
+	}
+	for b, e := range f.Paths {
+		if e.From >= 0 {
+			e.From = sup[e.From]
+		}
+		l.Paths[sup[b]] = e
+	}
+	if f.Leaves.Num > 0 {
+		l.Leaves = liftBits(sup, order, f.Leaves)
+	}
+	return l
+}
+
+func liftDist(sup []NI, order int, d []float64, fill float64) []float64 {
+	l := make([]float64, order)
+	for n := range l {
+		l[n] = fill
+	}
+	for b, x := range d {
+		l[sup[b]] = x
+	}
+	return l
+}
+
+func liftNodes(sup []NI, l []NI) []NI {
+	p := make([]NI, len(l))
+	for i, b := range l {
+		p[i] = sup[b]
+	}
+	return p
+}
+
+func liftBits(sup []NI, order int, t bits.Bits) bits.Bits {
+	p := bits.New(order)
+	t.IterateOnes(func(b int) bool {
+		p.SetBit(int(sup[b]), 1)
+		return true
+	})
+	return p
+}
+
+func pushBits(sup []NI, t bits.Bits) bits.Bits {
+	b := bits.New(len(sup))
+	for x, p := range sup {
+		if t.Bit(int(p)) == 1 {
+			b.SetBit(x, 1)
+		}
+	}
+	return b
+}
+
 // OrderMap formats maps for testable examples.
 //
 // OrderMap provides simple, no-frills formatting of maps in sorted order,
@@ -132,6 +132,27 @@ func ExampleDistanceMatrix_FloydWarshallPaths() {
 	// 4->4: [4]
 }
 
+func ExampleDistanceMatrix_HasNegativeCycle() {
+	//   (1)   (-1)   (4)
+	//  0---->1---->3---->2
+	//        ^     |     |
+	//        |(2)  |(3)  |(-6)
+	//        |     v     |
+	//        ------4<-----
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}},
+		1: {{To: 3, Label: -1}},
+		2: {{To: 4, Label: -6}},
+		3: {{To: 2, Label: 4}, {To: 4, Label: 3}},
+		4: {{To: 1, Label: 2}},
+	}
+	d := g.DistanceMatrix(func(l graph.LI) float64 { return float64(l) })
+	d.FloydWarshall()
+	fmt.Println(d.HasNegativeCycle())
+	// Output:
+	// true 1
+}
+
 func ExampleDistanceMatrix_FloydWarshallFromLists() {
 	//   (1)   (-1)   (4)
 	//  0---->1---->3---->2
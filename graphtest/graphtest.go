@@ -0,0 +1,225 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+// Package graphtest has functions for verifying results of graph algorithms.
+//
+// The functions check invariants that recur in testing graph code, for
+// example that a node ordering is a topological ordering or that a FromList
+// is a spanning tree of a graph.  They are intended for use in test suites,
+// of this and other packages.
+//
+// Each function returns nil if the invariant holds and otherwise returns an
+// error describing the first violation found.  Checks are straightforward
+// and make no attempt at efficiency beyond reasonable big-O complexity.
+//
+// The graphtest package is a separate package from graph.  It imports graph;
+// graph knows nothing of graphtest.
+package graphtest
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/soniakeys/graph"
+)
+
+// CheckMatching checks that m is a matching in g.
+//
+// Each edge of m must be an edge of g and not a loop, and no node may be an
+// endpoint of more than one edge of m.
+func CheckMatching(g graph.Undirected, m []graph.Edge) error {
+	matched := map[graph.NI]int{}
+	for i, e := range m {
+		if err := checkNode(len(g.AdjacencyList), e.N1); err != nil {
+			return fmt.Errorf("matching edge %d: %v", i, err)
+		}
+		if err := checkNode(len(g.AdjacencyList), e.N2); err != nil {
+			return fmt.Errorf("matching edge %d: %v", i, err)
+		}
+		if e.N1 == e.N2 {
+			return fmt.Errorf("matching edge %d is a loop at node %d", i, e.N1)
+		}
+		if has, _, _ := g.HasEdge(e.N1, e.N2); !has {
+			return fmt.Errorf("matching edge %d, %d-%d, not in graph",
+				i, e.N1, e.N2)
+		}
+		for _, n := range []graph.NI{e.N1, e.N2} {
+			if j, ok := matched[n]; ok {
+				return fmt.Errorf("node %d matched by edges %d and %d", n, j, i)
+			}
+			matched[n] = i
+		}
+	}
+	return nil
+}
+
+// CheckPartition checks that parts is a partition of the nodes 0 through
+// n-1.
+//
+// Each node must be in exactly one part and parts must not be empty.
+func CheckPartition(n int, parts [][]graph.NI) error {
+	part := make([]int, n)
+	for i := range part {
+		part[i] = -1
+	}
+	for p, nodes := range parts {
+		if len(nodes) == 0 {
+			return fmt.Errorf("part %d empty", p)
+		}
+		for _, nd := range nodes {
+			if err := checkNode(n, nd); err != nil {
+				return fmt.Errorf("part %d: %v", p, err)
+			}
+			if part[nd] >= 0 {
+				return fmt.Errorf("node %d in parts %d and %d", nd, part[nd], p)
+			}
+			part[nd] = p
+		}
+	}
+	for nd, p := range part {
+		if p < 0 {
+			return fmt.Errorf("node %d not in any part", nd)
+		}
+	}
+	return nil
+}
+
+// CheckPath checks that p is a path in g with distance wantDist.
+//
+// Each half arc of p.Path must be an arc of g, with the same label, from the
+// node preceding it in the path.  The distance of the path by weight function
+// w must equal wantDist within a relative tolerance of 1e-9.
+func CheckPath(g graph.LabeledAdjacencyList, p graph.LabeledPath, w graph.WeightFunc, wantDist float64) error {
+	if err := checkNode(len(g), p.Start); err != nil {
+		return fmt.Errorf("path start: %v", err)
+	}
+	fr := p.Start
+	for i, h := range p.Path {
+		if err := checkNode(len(g), h.To); err != nil {
+			return fmt.Errorf("path arc %d: %v", i, err)
+		}
+		if has, _ := g.HasArcLabel(fr, h.To, h.Label); !has {
+			return fmt.Errorf("path arc %d, %d->%d label %d, not in graph",
+				i, fr, h.To, h.Label)
+		}
+		fr = h.To
+	}
+	d := p.Distance(w)
+	if math.Abs(d-wantDist) > 1e-9*math.Max(1, math.Abs(wantDist)) {
+		return fmt.Errorf("path distance %g, want %g", d, wantDist)
+	}
+	return nil
+}
+
+// CheckProperColoring checks that colors is a proper node coloring of g.
+//
+// There must be a color for each node of g and colors must be non-negative.
+// No edge may join two nodes of the same color.  Loops thus make any coloring
+// improper.
+func CheckProperColoring(g graph.Undirected, colors []int) error {
+	if len(colors) != len(g.AdjacencyList) {
+		return fmt.Errorf("%d colors for %d nodes",
+			len(colors), len(g.AdjacencyList))
+	}
+	if ok, n1, n2 := g.ColoringOk(colors); !ok {
+		if n1 == n2 && colors[n1] < 0 {
+			return fmt.Errorf("node %d has negative color %d", n1, colors[n1])
+		}
+		return fmt.Errorf("nodes %d and %d joined by an edge have color %d",
+			n1, n2, colors[n1])
+	}
+	return nil
+}
+
+// CheckSpanningTree checks that f is a spanning tree of g, or a spanning
+// forest if g is not connected.
+//
+// F must have a path end for each node of g and must be acyclic.  Each
+// non-root node must have an edge in g to its From node and each connected
+// component of g must have exactly one root.
+//
+// Len values are checked only if they are set, that is, if Len of node 0 is
+// nonzero.  In this case roots must have Len 1 and other nodes must have Len
+// one more than that of their From node.
+func CheckSpanningTree(g graph.Undirected, f graph.FromList) error {
+	n := len(g.AdjacencyList)
+	if len(f.Paths) != n {
+		return fmt.Errorf("%d path ends for %d nodes", len(f.Paths), n)
+	}
+	if n == 0 {
+		return nil
+	}
+	for nd, p := range f.Paths {
+		if p.From >= graph.NI(n) {
+			return fmt.Errorf("node %d: from node %d out of range", nd, p.From)
+		}
+	}
+	if cyclic, nd := f.Cyclic(); cyclic {
+		return fmt.Errorf("cycle through node %d", nd)
+	}
+	ci, _ := g.ConnectedComponentInts()
+	root := map[int]graph.NI{}
+	lens := f.Paths[0].Len != 0
+	for nd, p := range f.Paths {
+		if p.From < 0 {
+			if r, ok := root[ci[nd]]; ok {
+				return fmt.Errorf("nodes %d and %d both roots of a component",
+					r, nd)
+			}
+			root[ci[nd]] = graph.NI(nd)
+			if lens && p.Len != 1 {
+				return fmt.Errorf("root %d has Len %d, want 1", nd, p.Len)
+			}
+			continue
+		}
+		if has, _, _ := g.HasEdge(graph.NI(nd), p.From); !has {
+			return fmt.Errorf("tree edge %d-%d not in graph", p.From, nd)
+		}
+		if want := f.Paths[p.From].Len + 1; lens && p.Len != want {
+			return fmt.Errorf("node %d has Len %d, want %d", nd, p.Len, want)
+		}
+	}
+	// with f acyclic and tree edges in g, every component has a root.
+	return nil
+}
+
+// CheckTopologicalOrder checks that order is a topological ordering of g.
+//
+// Each node of g must appear in order exactly once, and for each arc of g the
+// from node must appear before the to node.  Loops are thus not allowed.
+func CheckTopologicalOrder(g graph.Directed, order []graph.NI) error {
+	n := len(g.AdjacencyList)
+	if len(order) != n {
+		return fmt.Errorf("%d nodes in order for %d nodes in graph",
+			len(order), n)
+	}
+	pos := make([]int, n)
+	for i := range pos {
+		pos[i] = -1
+	}
+	for i, nd := range order {
+		if err := checkNode(n, nd); err != nil {
+			return fmt.Errorf("order position %d: %v", i, err)
+		}
+		if pos[nd] >= 0 {
+			return fmt.Errorf("node %d at positions %d and %d", nd, pos[nd], i)
+		}
+		pos[nd] = i
+	}
+	for fr, to := range g.AdjacencyList {
+		for _, to := range to {
+			if pos[to] <= pos[fr] {
+				return fmt.Errorf("arc %d->%d leads backward in order", fr, to)
+			}
+		}
+	}
+	return nil
+}
+
+// checkNode checks that nd is a valid node number for a graph of order n.
+func checkNode(n int, nd graph.NI) error {
+	if nd < 0 || int(nd) >= n {
+		return fmt.Errorf("node %d out of range", nd)
+	}
+	return nil
+}
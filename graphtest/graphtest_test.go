@@ -0,0 +1,205 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graphtest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/graphtest"
+)
+
+func ExampleCheckTopologicalOrder() {
+	// 0-->1-->2
+	//  \      ^
+	//   \-----/
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {},
+	}}
+	fmt.Println(graphtest.CheckTopologicalOrder(g, []graph.NI{0, 1, 2}))
+	fmt.Println(graphtest.CheckTopologicalOrder(g, []graph.NI{0, 2, 1}))
+	// Output:
+	// <nil>
+	// arc 1->2 leads backward in order
+}
+
+// check checks an error against want, "" for a nil error or otherwise the
+// expected error message.
+func check(t *testing.T, name string, got error, want string) {
+	switch {
+	case got == nil && want == "":
+	case got == nil:
+		t.Errorf("%s: got nil, want %q", name, want)
+	case got.Error() != want:
+		t.Errorf("%s: got %q, want %q", name, got, want)
+	}
+}
+
+func TestCheckMatching(t *testing.T) {
+	// 0--1--2--3
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	for _, tc := range []struct {
+		name string
+		m    []graph.Edge
+		want string
+	}{
+		{"empty", nil, ""},
+		{"perfect", []graph.Edge{{0, 1}, {3, 2}}, ""},
+		{"range", []graph.Edge{{0, 4}}, "matching edge 0: node 4 out of range"},
+		{"loop", []graph.Edge{{1, 1}}, "matching edge 0 is a loop at node 1"},
+		{"non-edge", []graph.Edge{{0, 3}}, "matching edge 0, 0-3, not in graph"},
+		{"shared", []graph.Edge{{0, 1}, {1, 2}}, "node 1 matched by edges 0 and 1"},
+	} {
+		check(t, tc.name, graphtest.CheckMatching(g, tc.m), tc.want)
+	}
+}
+
+func TestCheckPartition(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		n     int
+		parts [][]graph.NI
+		want  string
+	}{
+		{"empty", 0, nil, ""},
+		{"ok", 4, [][]graph.NI{{2, 0}, {3}, {1}}, ""},
+		{"empty part", 1, [][]graph.NI{{0}, {}}, "part 1 empty"},
+		{"range", 2, [][]graph.NI{{0, -1}}, "part 0: node -1 out of range"},
+		{"overlap", 3, [][]graph.NI{{0, 1}, {2, 1}}, "node 1 in parts 0 and 1"},
+		{"missing", 3, [][]graph.NI{{0}, {2}}, "node 1 not in any part"},
+	} {
+		check(t, tc.name, graphtest.CheckPartition(tc.n, tc.parts), tc.want)
+	}
+}
+
+func TestCheckPath(t *testing.T) {
+	// 0--(10)-->1--(20)-->2
+	//  \                  ^
+	//   \------(30)-------/
+	g := graph.LabeledAdjacencyList{
+		0: {{1, 10}, {2, 30}},
+		1: {{2, 20}},
+		2: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) / 10 }
+	for _, tc := range []struct {
+		name string
+		p    graph.LabeledPath
+		d    float64
+		want string
+	}{
+		{"start only", graph.LabeledPath{Start: 1}, 0, ""},
+		{"ok", graph.LabeledPath{0, []graph.Half{{1, 10}, {2, 20}}}, 3, ""},
+		{"start range", graph.LabeledPath{Start: 3}, 0,
+			"path start: node 3 out of range"},
+		{"arc range", graph.LabeledPath{0, []graph.Half{{5, 10}}}, 1,
+			"path arc 0: node 5 out of range"},
+		{"label", graph.LabeledPath{0, []graph.Half{{1, 10}, {2, 30}}}, 4,
+			"path arc 1, 1->2 label 30, not in graph"},
+		{"non-arc", graph.LabeledPath{1, []graph.Half{{0, 10}}}, 1,
+			"path arc 0, 1->0 label 10, not in graph"},
+		{"distance", graph.LabeledPath{0, []graph.Half{{2, 30}}}, 2,
+			"path distance 3, want 2"},
+	} {
+		check(t, tc.name, graphtest.CheckPath(g, tc.p, w, tc.d), tc.want)
+	}
+}
+
+func TestCheckProperColoring(t *testing.T) {
+	// 0--1--2  3(loop)
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(3, 3)
+	for _, tc := range []struct {
+		name   string
+		colors []int
+		want   string
+	}{
+		{"length", []int{0, 1, 0}, "3 colors for 4 nodes"},
+		{"negative", []int{0, 1, -2, 0}, "node 2 has negative color -2"},
+		{"edge", []int{0, 0, 1, 0}, "nodes 0 and 1 joined by an edge have color 0"},
+		{"loop", []int{0, 1, 0, 0}, "nodes 3 and 3 joined by an edge have color 0"},
+	} {
+		check(t, tc.name, graphtest.CheckProperColoring(g, tc.colors), tc.want)
+	}
+	g.RemoveEdge(3, 3)
+	check(t, "ok", graphtest.CheckProperColoring(g, []int{0, 1, 0, 0}), "")
+}
+
+func TestCheckSpanningTree(t *testing.T) {
+	// 0--1--2  3--4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(3, 4)
+	f := func(pe ...graph.PathEnd) graph.FromList {
+		return graph.FromList{Paths: pe}
+	}
+	for _, tc := range []struct {
+		name string
+		f    graph.FromList
+		want string
+	}{
+		{"ok", f(graph.PathEnd{-1, 0}, graph.PathEnd{0, 0}, graph.PathEnd{1, 0},
+			graph.PathEnd{4, 0}, graph.PathEnd{-1, 0}), ""},
+		{"ok len", f(graph.PathEnd{1, 2}, graph.PathEnd{-1, 1}, graph.PathEnd{1, 2},
+			graph.PathEnd{-1, 1}, graph.PathEnd{3, 2}), ""},
+		{"order", f(graph.PathEnd{-1, 0}), "1 path ends for 5 nodes"},
+		{"range", f(graph.PathEnd{-1, 0}, graph.PathEnd{5, 0}, graph.PathEnd{1, 0},
+			graph.PathEnd{4, 0}, graph.PathEnd{-1, 0}),
+			"node 1: from node 5 out of range"},
+		{"cycle", f(graph.PathEnd{1, 0}, graph.PathEnd{0, 0}, graph.PathEnd{1, 0},
+			graph.PathEnd{4, 0}, graph.PathEnd{-1, 0}),
+			"cycle through node 0"},
+		{"non-edge", f(graph.PathEnd{-1, 0}, graph.PathEnd{0, 0}, graph.PathEnd{0, 0},
+			graph.PathEnd{4, 0}, graph.PathEnd{-1, 0}),
+			"tree edge 0-2 not in graph"},
+		{"two roots", f(graph.PathEnd{-1, 0}, graph.PathEnd{0, 0}, graph.PathEnd{-1, 0},
+			graph.PathEnd{4, 0}, graph.PathEnd{-1, 0}),
+			"nodes 0 and 2 both roots of a component"},
+		{"root len", f(graph.PathEnd{-1, 2}, graph.PathEnd{0, 3}, graph.PathEnd{1, 4},
+			graph.PathEnd{4, 2}, graph.PathEnd{-1, 1}),
+			"root 0 has Len 2, want 1"},
+		{"len", f(graph.PathEnd{-1, 1}, graph.PathEnd{0, 2}, graph.PathEnd{1, 2},
+			graph.PathEnd{4, 2}, graph.PathEnd{-1, 1}),
+			"node 2 has Len 2, want 3"},
+	} {
+		check(t, tc.name, graphtest.CheckSpanningTree(g, tc.f), tc.want)
+	}
+	check(t, "empty", graphtest.CheckSpanningTree(graph.Undirected{}, graph.FromList{}), "")
+}
+
+func TestCheckTopologicalOrder(t *testing.T) {
+	// 0-->1-->2  3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {},
+		3: {},
+	}}
+	for _, tc := range []struct {
+		name  string
+		order []graph.NI
+		want  string
+	}{
+		{"ok", []graph.NI{3, 0, 1, 2}, ""},
+		{"ok2", []graph.NI{0, 3, 1, 2}, ""},
+		{"length", []graph.NI{0, 1, 2}, "3 nodes in order for 4 nodes in graph"},
+		{"range", []graph.NI{0, 1, 2, 4}, "order position 3: node 4 out of range"},
+		{"repeat", []graph.NI{0, 1, 1, 2}, "node 1 at positions 1 and 2"},
+		{"backward", []graph.NI{1, 0, 2, 3}, "arc 0->1 leads backward in order"},
+	} {
+		check(t, tc.name, graphtest.CheckTopologicalOrder(g, tc.order), tc.want)
+	}
+	g.AdjacencyList[3] = []graph.NI{3}
+	check(t, "loop", graphtest.CheckTopologicalOrder(g, []graph.NI{0, 1, 2, 3}),
+		"arc 3->3 leads backward in order")
+}
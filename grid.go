@@ -0,0 +1,220 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// grid.go -- pathfinding on rectangular grids of cells.
+
+import (
+	"math"
+
+	"github.com/soniakeys/bits"
+)
+
+// DiagonalPolicy specifies the diagonal moves allowed on a Grid.
+//
+// A diagonal move between two passable cells passes the corners of the two
+// cells orthogonally adjacent to both.  The policy says how many of these
+// must be passable.
+type DiagonalPolicy int
+
+const (
+	Orthogonal          DiagonalPolicy = iota // no diagonal moves
+	DiagonalNoCornerCut                       // both corner cells passable
+	DiagonalOneCorner                         // at least one passable
+	DiagonalAlways                            // corners not considered
+)
+
+// Grid labels.  Arcs of Grid.G are labeled with these values.
+const (
+	GridOrthogonal LI = iota // orthogonal move, weight 1
+	GridDiagonal             // diagonal move, weight √2
+)
+
+// Grid represents a rectangular grid of cells as an undirected graph.
+//
+// Each cell is a node, numbered in row-major order.  Arcs of G connect
+// passable cells and are labeled GridOrthogonal or GridDiagonal.
+// Impassable cells are nodes without arcs.
+//
+// G can be used directly with methods of this package, with WeightFunc
+// Weight.  G should not be modified except through SetPassable.
+type Grid struct {
+	Rows, Cols int
+	Diag       DiagonalPolicy
+	G          LabeledUndirected
+	pass       bits.Bits
+}
+
+// NewGrid constructs a Grid with the given number of rows and columns.
+//
+// Function passable is called for each cell and should return true if the
+// cell is passable.  If passable is nil, all cells are passable.
+func NewGrid(rows, cols int, passable func(r, c int) bool, diag DiagonalPolicy) *Grid {
+	g := &Grid{
+		Rows: rows,
+		Cols: cols,
+		Diag: diag,
+		G:    LabeledUndirected{make(LabeledAdjacencyList, rows*cols)},
+		pass: bits.New(rows * cols),
+	}
+	if passable == nil {
+		g.pass.SetAll()
+	} else {
+		for r := 0; r < rows; r++ {
+			for c := 0; c < cols; c++ {
+				if passable(r, c) {
+					g.pass.SetBit(r*cols+c, 1)
+				}
+			}
+		}
+	}
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			g.setArcs(r, c)
+		}
+	}
+	return g
+}
+
+// At returns the node of the cell at row r, column c.
+func (g *Grid) At(r, c int) NI {
+	return NI(r*g.Cols + c)
+}
+
+// RC returns the row and column of the cell of node n.
+func (g *Grid) RC(n NI) (r, c int) {
+	return int(n) / g.Cols, int(n) % g.Cols
+}
+
+// Passable returns true if the cell at row r, column c is in the grid and
+// is passable.
+func (g *Grid) Passable(r, c int) bool {
+	return r >= 0 && r < g.Rows && c >= 0 && c < g.Cols &&
+		g.pass.Bit(r*g.Cols+c) == 1
+}
+
+// Weight is the WeightFunc for arcs of G.  It returns 1 for GridOrthogonal
+// and √2 for GridDiagonal.
+func (g *Grid) Weight(l LI) float64 {
+	if l == GridDiagonal {
+		return math.Sqrt2
+	}
+	return 1
+}
+
+// Heuristic returns a heuristic for paths to node end.
+//
+// The heuristic is Manhattan distance for policy Orthogonal and octile
+// distance otherwise.  It is admissible and monotonic.
+func (g *Grid) Heuristic(end NI) Heuristic {
+	er, ec := g.RC(end)
+	diag := g.Diag != Orthogonal
+	return func(n NI) float64 {
+		r, c := g.RC(n)
+		dr := math.Abs(float64(r - er))
+		dc := math.Abs(float64(c - ec))
+		if !diag {
+			return dr + dc
+		}
+		return dr + dc + (math.Sqrt2-2)*math.Min(dr, dc)
+	}
+}
+
+// Path finds a shortest path from the cell at r0, c0 to the cell at r1, c1.
+//
+// The path is found with AStarM using the grid Heuristic.  It is returned
+// as a list of row, column pairs, starting with r0, c0 and ending with r1, c1,
+// along with the path distance.  If there is no path, Path returns nil and
+// +Inf.
+func (g *Grid) Path(r0, c0, r1, c1 int) ([][2]int, float64) {
+	if !g.Passable(r0, c0) || !g.Passable(r1, c1) {
+		return nil, math.Inf(1)
+	}
+	start, end := g.At(r0, c0), g.At(r1, c1)
+	f, _, d, ok := g.G.LabeledAdjacencyList.AStarM(g.Weight, start, end,
+		g.Heuristic(end))
+	if !ok {
+		return nil, math.Inf(1)
+	}
+	p := f.PathTo(end, nil)
+	rc := make([][2]int, len(p))
+	for i, n := range p {
+		rc[i][0], rc[i][1] = g.RC(n)
+	}
+	return rc, d
+}
+
+// SetPassable sets the cell at row r, column c passable or impassable.
+//
+// Arcs of G are updated.  Changing a cell changes its own arcs and with
+// the diagonal policies DiagonalNoCornerCut and DiagonalOneCorner, diagonal
+// arcs between its orthogonal neighbors.  No other arcs are affected and
+// only arc lists of the cell and its eight neighbors are rebuilt.
+func (g *Grid) SetPassable(r, c int, passable bool) {
+	b := 0
+	if passable {
+		b = 1
+	}
+	x := r*g.Cols + c
+	if g.pass.Bit(x) == b {
+		return
+	}
+	g.pass.SetBit(x, b)
+	for nr := r - 1; nr <= r+1; nr++ {
+		for nc := c - 1; nc <= c+1; nc++ {
+			if nr >= 0 && nr < g.Rows && nc >= 0 && nc < g.Cols {
+				g.setArcs(nr, nc)
+			}
+		}
+	}
+}
+
+// gridMoves lists row and column offsets of orthogonal then diagonal moves.
+var gridMoves = [8][2]int{
+	{-1, 0}, {0, 1}, {1, 0}, {0, -1},
+	{-1, 1}, {1, 1}, {1, -1}, {-1, -1},
+}
+
+// setArcs rebuilds the arc list of the cell at row r, column c.
+func (g *Grid) setArcs(r, c int) {
+	a := g.G.LabeledAdjacencyList
+	n := g.At(r, c)
+	to := a[n][:0]
+	if g.Passable(r, c) {
+		for i, m := range gridMoves {
+			tr, tc := r+m[0], c+m[1]
+			if !g.Passable(tr, tc) {
+				continue
+			}
+			if i < 4 {
+				to = append(to, Half{g.At(tr, tc), GridOrthogonal})
+				continue
+			}
+			corners := 0
+			if g.Passable(r, tc) {
+				corners++
+			}
+			if g.Passable(tr, c) {
+				corners++
+			}
+			switch g.Diag {
+			case Orthogonal:
+				continue
+			case DiagonalNoCornerCut:
+				if corners < 2 {
+					continue
+				}
+			case DiagonalOneCorner:
+				if corners < 1 {
+					continue
+				}
+			}
+			to = append(to, Half{g.At(tr, tc), GridDiagonal})
+		}
+	}
+	if len(to) == 0 {
+		to = nil
+	}
+	a[n] = to
+}
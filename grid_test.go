@@ -0,0 +1,131 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleGrid_Path() {
+	maze := []string{
+		"S..#....",
+		".#.#.##.",
+		".#...#..",
+		".####.#.",
+		"......#E",
+	}
+	g := graph.NewGrid(len(maze), len(maze[0]), func(r, c int) bool {
+		return maze[r][c] != '#'
+	}, graph.Orthogonal)
+	p, d := g.Path(0, 0, 4, 7)
+	fmt.Println("distance:", d)
+	m := make([][]byte, len(maze))
+	for r := range maze {
+		m[r] = []byte(maze[r])
+	}
+	for _, rc := range p[1 : len(p)-1] {
+		m[rc[0]][rc[1]] = '*'
+	}
+	for _, r := range m {
+		fmt.Println(string(r))
+	}
+	// Output:
+	// distance: 15
+	// S**#****
+	// .#*#*##*
+	// .#***#.*
+	// .####.#*
+	// ......#E
+}
+
+// gridMaze returns a grid from a picture with '#' for impassable cells.
+func gridMaze(maze string, diag graph.DiagonalPolicy) *graph.Grid {
+	rows := strings.Fields(maze)
+	return graph.NewGrid(len(rows), len(rows[0]), func(r, c int) bool {
+		return rows[r][c] != '#'
+	}, diag)
+}
+
+func TestGridDiagonalPolicy(t *testing.T) {
+	// moving from the top left to the bottom right cell.
+	for _, tc := range []struct {
+		maze string
+		want [4]float64 // distance by policy, 0 for no path
+	}{
+		{`..
+		  ..`, [4]float64{2, math.Sqrt2, math.Sqrt2, math.Sqrt2}},
+		{`.#
+		  ..`, [4]float64{2, 2, math.Sqrt2, math.Sqrt2}},
+		{`.#
+		  #.`, [4]float64{0, 0, 0, math.Sqrt2}},
+		{`...
+		  .#.
+		  ...`, [4]float64{4, 4, 2 + math.Sqrt2, 2 + math.Sqrt2}},
+	} {
+		for diag, want := range tc.want {
+			g := gridMaze(tc.maze, graph.DiagonalPolicy(diag))
+			p, d := g.Path(0, 0, g.Rows-1, g.Cols-1)
+			if want == 0 {
+				if p != nil || !math.IsInf(d, 1) {
+					t.Fatal(tc.maze, diag, p, d)
+				}
+				continue
+			}
+			if math.Abs(d-want) > 1e-9 {
+				t.Fatal(tc.maze, diag, p, d, "want", want)
+			}
+			// path steps must be moves allowed by the policy
+			for i := 1; i < len(p); i++ {
+				fr, to := g.At(p[i-1][0], p[i-1][1]), g.At(p[i][0], p[i][1])
+				if ok, _ := g.G.HasArc(fr, to); !ok {
+					t.Fatal(tc.maze, diag, p, "invalid step", i)
+				}
+			}
+		}
+	}
+}
+
+func TestGridSetPassable(t *testing.T) {
+	r := rand.New(rand.NewSource(30))
+	for diag := graph.Orthogonal; diag <= graph.DiagonalAlways; diag++ {
+		rows, cols := 12, 15
+		pass := make([]bool, rows*cols)
+		for i := range pass {
+			pass[i] = r.Intn(4) > 0
+		}
+		passable := func(r, c int) bool { return pass[r*cols+c] }
+		g := graph.NewGrid(rows, cols, passable, diag)
+		for i := 0; i < 200; i++ {
+			pr, pc := r.Intn(rows), r.Intn(cols)
+			p := r.Intn(3) > 0
+			pass[pr*cols+pc] = p
+			g.SetPassable(pr, pc, p)
+			// arcs must match a grid built from scratch
+			want := graph.NewGrid(rows, cols, passable, diag)
+			if !reflect.DeepEqual(g.G, want.G) {
+				t.Fatal("diag", diag, "toggle", i, pr, pc, p)
+			}
+			// and replanning must match Dijkstra on the rebuilt graph
+			r0, c0, r1, c1 := r.Intn(rows), r.Intn(cols), r.Intn(rows), r.Intn(cols)
+			_, d := g.Path(r0, c0, r1, c1)
+			wd := math.Inf(1)
+			if g.Passable(r0, c0) && g.Passable(r1, c1) {
+				f, _, dist, _ := want.G.Dijkstra(g.At(r0, c0), -1, want.Weight)
+				if end := g.At(r1, c1); f.Paths[end].Len > 0 {
+					wd = dist[end]
+				}
+			}
+			if math.IsInf(wd, 1) != math.IsInf(d, 1) || math.Abs(d-wd) > 1e-9 {
+				t.Fatal("diag", diag, "toggle", i, "path distance", d, "want", wd)
+			}
+		}
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package io
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/soniakeys/graph"
+)
+
+const (
+	binMagicAL     = "gRal"
+	binMagicLAL    = "gRla"
+	binVersion     = 1
+	binAllocChunk  = 1 << 16 // limit on allocation before data is read
+	binHeaderBytes = len(binMagicAL) + 1
+)
+
+// WriteBinary writes an AdjacencyList in a compact binary format.
+//
+// See the description of the format at ReadBinary.
+//
+// Returned is number of bytes written and error.
+func WriteBinary(g graph.AdjacencyList, w io.Writer) (n int, err error) {
+	b := newBinWriter(w, binMagicAL, len(g))
+	for _, to := range g {
+		b.uvarint(uint64(len(to)))
+	}
+	g.Arcs(func(_ graph.NI, _ int, to graph.NI) bool {
+		b.uvarint(uint64(to))
+		return true
+	})
+	return b.done()
+}
+
+// WriteLabeledBinary writes a LabeledAdjacencyList in a compact binary
+// format.
+//
+// See the description of the format at ReadBinary.
+//
+// Returned is number of bytes written and error.
+func WriteLabeledBinary(g graph.LabeledAdjacencyList, w io.Writer) (n int, err error) {
+	b := newBinWriter(w, binMagicLAL, len(g))
+	for _, to := range g {
+		b.uvarint(uint64(len(to)))
+	}
+	g.Arcs(func(_ graph.NI, _ int, to graph.Half) bool {
+		b.uvarint(uint64(to.To))
+		b.varint(int64(to.Label))
+		return true
+	})
+	return b.done()
+}
+
+// binWriter accumulates bytes written and the first error.
+type binWriter struct {
+	lalWriter
+	buf [binary.MaxVarintLen64]byte
+}
+
+func newBinWriter(w io.Writer, magic string, order int) *binWriter {
+	b := &binWriter{lalWriter: lalWriter{b: bufio.NewWriter(w)}}
+	b.ws(magic)
+	if b.err == nil {
+		b.err = b.b.WriteByte(binVersion)
+		b.n++
+	}
+	b.uvarint(uint64(order))
+	return b
+}
+
+func (b *binWriter) uvarint(x uint64) {
+	b.wb(b.buf[:binary.PutUvarint(b.buf[:], x)])
+}
+
+func (b *binWriter) varint(x int64) {
+	b.wb(b.buf[:binary.PutVarint(b.buf[:], x)])
+}
+
+func (b *binWriter) wb(p []byte) {
+	if b.err == nil {
+		var c int
+		c, b.err = b.b.Write(p)
+		b.n += c
+	}
+}
+
+// ReadBinary reads an AdjacencyList in the compact binary format written
+// by WriteBinary.
+//
+// The format is a header followed by varint encoded integers.  The header
+// is a four byte magic string, "gRal" for an AdjacencyList or "gRla" for a
+// LabeledAdjacencyList, then a version byte, currently 1.  Following the
+// header are the graph order, the degree of each node, and then the to-nodes
+// of all nodes, in node order.  In the labeled format, each to-node is
+// immediately followed by its label.  Order, degrees, and to-nodes are
+// unsigned varints, labels are signed varints, both as encoded by package
+// encoding/binary.  The format is independent of byte order and of the sizes
+// of graph.NI and graph.LI.
+//
+// ReadBinary validates that to-nodes are less than the graph order and
+// returns a descriptive error for truncated or invalid input.  If r
+// implements io.ByteReader, ReadBinary reads no further than the end of the
+// graph data.  Otherwise r is buffered and more may be read.
+func ReadBinary(r io.Reader) (graph.AdjacencyList, error) {
+	br := newBinReader(r)
+	deg, err := br.header(binMagicAL)
+	if err != nil {
+		return nil, err
+	}
+	g := make(graph.AdjacencyList, len(deg))
+	var all []graph.NI
+	for fr, d := range deg {
+		for i := uint64(0); i < d; i++ {
+			to, err := br.to(graph.NI(fr), len(deg))
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, to)
+		}
+	}
+	// slice to-lists from a single backing array, leaving empty lists nil
+	for fr, d := range deg {
+		if d > 0 {
+			g[fr] = all[:d:d]
+			all = all[d:]
+		}
+	}
+	return g, nil
+}
+
+// ReadLabeledBinary reads a LabeledAdjacencyList in the compact binary format
+// written by WriteLabeledBinary.
+//
+// See ReadBinary for a description of the format and of validation.
+func ReadLabeledBinary(r io.Reader) (graph.LabeledAdjacencyList, error) {
+	br := newBinReader(r)
+	deg, err := br.header(binMagicLAL)
+	if err != nil {
+		return nil, err
+	}
+	g := make(graph.LabeledAdjacencyList, len(deg))
+	var all []graph.Half
+	for fr, d := range deg {
+		for i := uint64(0); i < d; i++ {
+			to, err := br.to(graph.NI(fr), len(deg))
+			if err != nil {
+				return nil, err
+			}
+			l, err := binary.ReadVarint(br)
+			if err != nil {
+				return nil, binErr(err, "label of arc from node %d", fr)
+			}
+			if int64(graph.LI(l)) != l {
+				return nil, fmt.Errorf("label %d of arc from node %d out of range",
+					l, fr)
+			}
+			all = append(all, graph.Half{To: to, Label: graph.LI(l)})
+		}
+	}
+	for fr, d := range deg {
+		if d > 0 {
+			g[fr] = all[:d:d]
+			all = all[d:]
+		}
+	}
+	return g, nil
+}
+
+// binReader reads graph data as bytes, as needed for varint decoding.
+type binReader struct {
+	io.ByteReader
+}
+
+func newBinReader(r io.Reader) binReader {
+	if br, ok := r.(io.ByteReader); ok {
+		return binReader{br}
+	}
+	return binReader{bufio.NewReader(r)}
+}
+
+// header reads and validates the header, order, and degrees.
+func (b binReader) header(magic string) (deg []uint64, err error) {
+	h := make([]byte, binHeaderBytes)
+	for i := range h {
+		if h[i], err = b.ReadByte(); err != nil {
+			return nil, binErr(err, "header")
+		}
+	}
+	if string(h[:len(magic)]) != magic {
+		return nil, fmt.Errorf("invalid header %q, want %q", h[:len(magic)], magic)
+	}
+	if v := h[len(magic)]; v != binVersion {
+		return nil, fmt.Errorf("unsupported version %d", v)
+	}
+	order, err := binary.ReadUvarint(b)
+	if err != nil {
+		return nil, binErr(err, "graph order")
+	}
+	if order > uint64(1)<<uint(graph.NIBits-1) {
+		return nil, fmt.Errorf("graph order %d too large", order)
+	}
+	// allocation is limited until data is actually read
+	c := order
+	if c > binAllocChunk {
+		c = binAllocChunk
+	}
+	deg = make([]uint64, 0, c)
+	for n := uint64(0); n < order; n++ {
+		d, err := binary.ReadUvarint(b)
+		if err != nil {
+			return nil, binErr(err, "degree of node %d", n)
+		}
+		deg = append(deg, d)
+	}
+	return deg, nil
+}
+
+// to reads a to-node of an arc from node fr and validates it against order.
+func (b binReader) to(fr graph.NI, order int) (graph.NI, error) {
+	to, err := binary.ReadUvarint(b)
+	if err != nil {
+		return -1, binErr(err, "to-node of arc from node %d", fr)
+	}
+	if to >= uint64(order) {
+		return -1, fmt.Errorf("arc from node %d to node %d out of range, order %d",
+			fr, to, order)
+	}
+	return graph.NI(to), nil
+}
+
+// binErr describes an error reading the data described by format and a.
+func binErr(err error, format string, a ...interface{}) error {
+	what := fmt.Sprintf(format, a...)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.New("truncated input reading " + what)
+	}
+	return fmt.Errorf("reading %s: %v", what, err)
+}
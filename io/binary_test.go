@@ -0,0 +1,106 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io_test
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/io"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(59))
+	var b bytes.Buffer
+	for _, g := range []graph.AdjacencyList{
+		{{1}, nil, {0}},
+		graph.GnmDirected(300, 2000, rnd).AdjacencyList,
+	} {
+		b.Reset()
+		if _, err := io.WriteBinary(g, &b); err != nil {
+			t.Fatal(err)
+		}
+		r, err := io.ReadBinary(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(r, g) {
+			t.Fatal(r, "want", g)
+		}
+	}
+	// labeled, with negative and large labels, and with the reader
+	// stopping at the end of graph data
+	l := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: -1}, {To: 1, Label: 1 << 30}},
+		1: {{To: 0, Label: -1 << 31}},
+		2: nil,
+	}
+	b.Reset()
+	if _, err := io.WriteLabeledBinary(l, &b); err != nil {
+		t.Fatal(err)
+	}
+	b.WriteString("more")
+	rl, err := io.ReadLabeledBinary(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rl, l) {
+		t.Fatal(rl)
+	}
+	if b.String() != "more" {
+		t.Fatal("read past graph data")
+	}
+}
+
+func TestReadBinaryErrors(t *testing.T) {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 300}, {To: 2, Label: -5}},
+		2: {{To: 0, Label: 7}},
+	}
+	var b bytes.Buffer
+	io.WriteLabeledBinary(g, &b)
+	data := b.Bytes()
+	// every truncation is an error
+	for i := 0; i < len(data); i++ {
+		_, err := io.ReadLabeledBinary(bytes.NewReader(data[:i]))
+		if err == nil || !strings.HasPrefix(err.Error(), "truncated input") {
+			t.Fatal("length", i, "error", err)
+		}
+	}
+	// wrong magic
+	if _, err := io.ReadBinary(bytes.NewReader(data)); err == nil {
+		t.Fatal("labeled data read as unlabeled")
+	}
+	// bad version
+	v := append([]byte{}, data...)
+	v[4] = 9
+	if _, err := io.ReadLabeledBinary(bytes.NewReader(v)); err == nil ||
+		err.Error() != "unsupported version 9" {
+		t.Fatal(err)
+	}
+	// to-node out of range:  order 2, node 0 has one arc to node 2
+	bad := []byte{'g', 'R', 'a', 'l', 1, 2, 1, 0, 2}
+	_, err := io.ReadBinary(bytes.NewReader(bad))
+	if err == nil ||
+		err.Error() != "arc from node 0 to node 2 out of range, order 2" {
+		t.Fatal(err)
+	}
+	// huge order does not allocate before failing
+	huge := []byte{'g', 'R', 'a', 'l', 1, 0xff, 0xff, 0xff, 0xff, 0x07}
+	if _, err = io.ReadBinary(bytes.NewReader(huge)); err == nil {
+		t.Fatal("no error")
+	}
+	huge = []byte{'g', 'R', 'a', 'l', 1, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}
+	if _, err = io.ReadBinary(bytes.NewReader(huge)); err == nil ||
+		!strings.HasSuffix(err.Error(), "too large") {
+		t.Fatal(err)
+	}
+	if _, err = io.ReadBinary(allErr{}); err == nil {
+		t.Fatal("no error")
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package io
+
+// columns.go -- conversion to and from columnar arc arrays.
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/soniakeys/graph"
+)
+
+// FromColumns constructs a directed graph from columns of arc endpoints.
+//
+// Arc i of the graph is from node src[i] to node dst[i].  Columns src and
+// dst must have the same length and all values must be valid NIs less than
+// order.  If order is negative, the graph order is one more than the largest
+// NI in the columns.  An error is returned for mismatched column lengths or
+// an NI out of range, identifying the first offending row.
+//
+// The graph is built with a counting sort by src, in two passes over the
+// columns.  To-lists share a single backing slice of NIs, with capacities
+// limited so that appending to one does not overwrite another.  Arcs from
+// each node are in the order of the columns.
+//
+// Columns are divided among the given number of worker goroutines for both
+// validation and sorting.  The result does not depend on the number of
+// workers.
+func FromColumns(src, dst []int64, order, workers int) (graph.Directed, error) {
+	c, err := newColumns(src, dst, order, workers)
+	if err != nil {
+		return graph.Directed{}, err
+	}
+	g := make(graph.AdjacencyList, c.order)
+	all := make([]graph.NI, len(src))
+	c.sort(func(fr graph.NI, lo, hi int) {
+		g[fr] = all[lo:hi:hi]
+	}, func(x, i int) {
+		all[x] = graph.NI(dst[i])
+	})
+	return graph.Directed{g}, nil
+}
+
+// FromColumnsWeighted constructs a labeled directed graph from columns of
+// arc endpoints and weights.
+//
+// Column weight must have the same length as src and dst.  The label of
+// each arc is its row index in the columns, so weight serves directly as
+// the weight table of the graph.  It is returned as wt, along with the
+// WeightFunc w returning wt[l] for label l.  The number of rows cannot
+// exceed the range of graph.LI.
+//
+// Other arguments and construction are as for FromColumns.
+func FromColumnsWeighted(src, dst []int64, weight []float64, order, workers int) (
+	g graph.LabeledDirected, w graph.WeightFunc, wt []float64, err error) {
+	if len(weight) != len(src) {
+		return g, nil, nil, fmt.Errorf(
+			"column lengths: %d src, %d weight", len(src), len(weight))
+	}
+	if int64(len(src)) > math.MaxInt32 {
+		return g, nil, nil, fmt.Errorf(
+			"%d rows exceed label range", len(src))
+	}
+	c, err := newColumns(src, dst, order, workers)
+	if err != nil {
+		return g, nil, nil, err
+	}
+	a := make(graph.LabeledAdjacencyList, c.order)
+	all := make([]graph.Half, len(src))
+	c.sort(func(fr graph.NI, lo, hi int) {
+		a[fr] = all[lo:hi:hi]
+	}, func(x, i int) {
+		all[x] = graph.Half{To: graph.NI(dst[i]), Label: graph.LI(i)}
+	})
+	return graph.LabeledDirected{a},
+		func(l graph.LI) float64 { return weight[l] }, weight, nil
+}
+
+// ToColumns returns columns of arc endpoints for the arcs of g.
+//
+// Arcs are ordered by from-node, then by order in the to-list of the
+// from-node.  For a graph constructed by FromColumns, the columns
+// reproduce the original columns stably sorted by src.
+func ToColumns(g graph.AdjacencyList) (src, dst []int64) {
+	m := 0
+	for _, to := range g {
+		m += len(to)
+	}
+	src = make([]int64, 0, m)
+	dst = make([]int64, 0, m)
+	g.Arcs(func(fr graph.NI, _ int, to graph.NI) bool {
+		src = append(src, int64(fr))
+		dst = append(dst, int64(to))
+		return true
+	})
+	return
+}
+
+// ToColumnsWeighted returns columns of arc endpoints and weights for the
+// arcs of g.
+//
+// Weights are computed from arc labels with WeightFunc w.  Arcs are ordered
+// as for ToColumns.
+func ToColumnsWeighted(g graph.LabeledAdjacencyList, w graph.WeightFunc) (
+	src, dst []int64, weight []float64) {
+	m := 0
+	for _, to := range g {
+		m += len(to)
+	}
+	src = make([]int64, 0, m)
+	dst = make([]int64, 0, m)
+	weight = make([]float64, 0, m)
+	g.Arcs(func(fr graph.NI, _ int, to graph.Half) bool {
+		src = append(src, int64(fr))
+		dst = append(dst, int64(to.To))
+		weight = append(weight, w(to.Label))
+		return true
+	})
+	return
+}
+
+// columns holds validated columns and per-worker node counts for a counting
+// sort.
+type columns struct {
+	src     []int64
+	order   int
+	workers int
+	chunk   int     // rows per worker
+	start   [][]int // start[w][n]: position of worker w's first arc from n
+}
+
+// newColumns validates src and dst and counts arcs from each node.
+func newColumns(src, dst []int64, order, workers int) (*columns, error) {
+	if len(src) != len(dst) {
+		return nil, fmt.Errorf("column lengths: %d src, %d dst",
+			len(src), len(dst))
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(src) {
+		workers = len(src)
+	}
+	c := &columns{src: src, workers: workers}
+	if workers > 0 {
+		c.chunk = (len(src) + workers - 1) / workers
+	}
+	// first pass: validate, find the largest NI
+	maxNI := int64(1)<<uint(graph.NIBits-1) - 1
+	max := make([]int64, workers)
+	errs := make([]error, workers)
+	c.parallel(func(w, lo, hi int) {
+		m := int64(-1)
+		for i := lo; i < hi; i++ {
+			s, d := src[i], dst[i]
+			switch {
+			case s < 0 || s > maxNI:
+				errs[w] = fmt.Errorf("row %d: invalid src %d", i, s)
+				return
+			case d < 0 || d > maxNI:
+				errs[w] = fmt.Errorf("row %d: invalid dst %d", i, d)
+				return
+			case order >= 0 && s >= int64(order):
+				errs[w] = fmt.Errorf("row %d: src %d out of range, order %d",
+					i, s, order)
+				return
+			case order >= 0 && d >= int64(order):
+				errs[w] = fmt.Errorf("row %d: dst %d out of range, order %d",
+					i, d, order)
+				return
+			}
+			if s > m {
+				m = s
+			}
+			if d > m {
+				m = d
+			}
+		}
+		max[w] = m
+	})
+	// errors of lower workers are of earlier rows
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.order = order
+	if order < 0 {
+		c.order = 0
+		for _, m := range max {
+			if int(m)+1 > c.order {
+				c.order = int(m) + 1
+			}
+		}
+	}
+	// count arcs from each node, by worker
+	c.start = make([][]int, workers)
+	c.parallel(func(w, lo, hi int) {
+		cnt := make([]int, c.order)
+		for _, s := range src[lo:hi] {
+			cnt[s]++
+		}
+		c.start[w] = cnt
+	})
+	// convert counts to start positions, in node order then worker order
+	x := 0
+	for n := 0; n < c.order; n++ {
+		for _, s := range c.start {
+			s[n], x = x, x+s[n]
+		}
+	}
+	return c, nil
+}
+
+// sort calls slice for each node with arcs, with the range of sorted
+// positions of arcs from the node, then calls place with the sorted position x of each row i.
+// Calls to place are concurrent but for distinct x.
+func (c *columns) sort(slice func(fr graph.NI, lo, hi int), place func(x, i int)) {
+	if len(c.start) == 0 {
+		return
+	}
+	for n := 0; n < c.order; n++ {
+		lo, hi := c.start[0][n], len(c.src)
+		if n+1 < c.order {
+			hi = c.start[0][n+1]
+		}
+		if hi > lo {
+			slice(graph.NI(n), lo, hi)
+		}
+	}
+	// second pass: place rows
+	c.parallel(func(w, lo, hi int) {
+		pos := c.start[w]
+		for i := lo; i < hi; i++ {
+			s := c.src[i]
+			place(pos[s], i)
+			pos[s]++
+		}
+	})
+}
+
+// parallel calls f for each worker with the worker's range of rows.
+func (c *columns) parallel(f func(w, lo, hi int)) {
+	if c.workers == 1 {
+		f(0, 0, len(c.src))
+		return
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < c.workers; w++ {
+		lo := w * c.chunk
+		hi := lo + c.chunk
+		if hi > len(c.src) {
+			hi = len(c.src)
+		}
+		if lo > hi {
+			lo = hi
+		}
+		wg.Add(1)
+		go func(w, lo, hi int) {
+			f(w, lo, hi)
+			wg.Done()
+		}(w, lo, hi)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,210 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/io"
+)
+
+func ExampleFromColumns() {
+	src := []int64{2, 0, 2, 0}
+	dst := []int64{1, 1, 0, 2}
+	g, err := io.FromColumns(src, dst, -1, 1)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	fmt.Println(io.ToColumns(g.AdjacencyList))
+	// Output:
+	// 0 [1 2]
+	// 1 []
+	// 2 [1 0]
+	// [0 0 2 2] [1 2 1 0]
+}
+
+func TestColumnsRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(35))
+	g := graph.GnmDirected(300, 2000, rnd).AdjacencyList
+	// shuffle rows; FromColumns must restore them stably sorted by src
+	src, dst := io.ToColumns(g)
+	rnd.Shuffle(len(src), func(i, j int) {
+		src[i], src[j] = src[j], src[i]
+		dst[i], dst[j] = dst[j], dst[i]
+	})
+	var want graph.AdjacencyList = make(graph.AdjacencyList, len(g))
+	for i, s := range src {
+		want[s] = append(want[s], graph.NI(dst[i]))
+	}
+	for _, w := range []int{1, 2, 3, 7, 5000} {
+		c, err := io.FromColumns(src, dst, len(g), w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(c.AdjacencyList, want) {
+			t.Fatal("workers", w, "graph differs")
+		}
+	}
+	// appending to one to-list must not overwrite another
+	c, _ := io.FromColumns(src, dst, len(g), 1)
+	c.AdjacencyList[0] = append(c.AdjacencyList[0], 1)
+	if !reflect.DeepEqual(c.AdjacencyList[1], want[1]) {
+		t.Fatal("append overwrote node 1")
+	}
+}
+
+func TestColumnsWeightedRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(36))
+	n := 100
+	var src, dst []int64
+	var wt []float64
+	for i := 0; i < 800; i++ {
+		src = append(src, int64(rnd.Intn(n)))
+		dst = append(dst, int64(rnd.Intn(n)))
+		wt = append(wt, rnd.Float64())
+	}
+	g, w, gw, err := io.FromColumnsWeighted(src, dst, wt, -1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if &gw[0] != &wt[0] {
+		t.Fatal("weight column copied")
+	}
+	for fr, to := range g.LabeledAdjacencyList {
+		for _, h := range to {
+			l := h.Label
+			if src[l] != int64(fr) || dst[l] != int64(h.To) || w(l) != wt[l] {
+				t.Fatal("arc", fr, h)
+			}
+		}
+	}
+	s2, d2, w2 := io.ToColumnsWeighted(g.LabeledAdjacencyList, w)
+	g2, _, _, err := io.FromColumnsWeighted(s2, d2, w2, -1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for fr, to := range g2.LabeledAdjacencyList {
+		if len(to) != len(g.LabeledAdjacencyList[fr]) {
+			t.Fatal("node", fr)
+		}
+		for x, h := range to {
+			h1 := g.LabeledAdjacencyList[fr][x]
+			if h.To != h1.To || w2[h.Label] != wt[h1.Label] {
+				t.Fatal("arc", fr, h, h1)
+			}
+		}
+	}
+}
+
+func TestFromColumnsErrors(t *testing.T) {
+	for _, tc := range []struct {
+		src, dst []int64
+		order    int
+		want     string
+	}{
+		{[]int64{0, 1}, []int64{1}, 2, "column lengths"},
+		{[]int64{0, -1}, []int64{1, 0}, 2, "row 1: invalid src -1"},
+		{[]int64{0, 1}, []int64{1, -5}, -1, "row 1: invalid dst -5"},
+		{[]int64{0, 1 << 40}, []int64{1, 0}, -1, "row 1: invalid src"},
+		{[]int64{0, 2}, []int64{1, 0}, 2, "row 1: src 2 out of range"},
+		{[]int64{0, 1, 0}, []int64{1, 0, 3}, 3, "row 2: dst 3 out of range"},
+	} {
+		for _, w := range []int{1, 2, 3} {
+			_, err := io.FromColumns(tc.src, tc.dst, tc.order, w)
+			if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+				t.Fatal(tc.src, tc.dst, w, "got", err, "want", tc.want)
+			}
+		}
+	}
+	if _, _, _, err := io.FromColumnsWeighted(
+		[]int64{0}, []int64{0}, nil, -1, 1); err == nil {
+		t.Fatal("mismatched weight column accepted")
+	}
+	// random corruption must be reported as the first bad row
+	rnd := rand.New(rand.NewSource(37))
+	for i := 0; i < 200; i++ {
+		n := 1 + rnd.Intn(20)
+		src := make([]int64, 1+rnd.Intn(50))
+		dst := make([]int64, len(src))
+		for x := range src {
+			src[x], dst[x] = int64(rnd.Intn(n)), int64(rnd.Intn(n))
+		}
+		bad := -1
+		for x := range src {
+			if rnd.Intn(10) == 0 {
+				if rnd.Intn(2) == 0 {
+					src[x] = -1 - int64(rnd.Intn(3))
+				} else {
+					dst[x] = int64(n + rnd.Intn(3))
+				}
+				if bad < 0 {
+					bad = x
+				}
+			}
+		}
+		_, err := io.FromColumns(src, dst, n, 1+rnd.Intn(4))
+		switch {
+		case bad < 0 && err != nil:
+			t.Fatal(i, err)
+		case bad >= 0 && (err == nil ||
+			!strings.HasPrefix(err.Error(), fmt.Sprintf("row %d:", bad))):
+			t.Fatal(i, "bad row", bad, "got", err)
+		}
+	}
+}
+
+// a large random column set shared by the construction benchmarks,
+// compared with naive construction by appending each arc.
+var (
+	benchColumnsOnce   sync.Once
+	benchSrc, benchDst []int64
+)
+
+const (
+	benchColumnsOrder = 1 << 22
+	benchColumnsArcs  = 50e6
+)
+
+func benchColumns() {
+	benchColumnsOnce.Do(func() {
+		rnd := rand.New(rand.NewSource(38))
+		benchSrc = make([]int64, int(benchColumnsArcs))
+		benchDst = make([]int64, len(benchSrc))
+		for i := range benchSrc {
+			benchSrc[i] = int64(rnd.Intn(benchColumnsOrder))
+			benchDst[i] = int64(rnd.Intn(benchColumnsOrder))
+		}
+	})
+}
+
+func BenchmarkFromColumns(b *testing.B) {
+	benchColumns()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := io.FromColumns(benchSrc, benchDst, benchColumnsOrder, 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFromColumnsAddArc(b *testing.B) {
+	benchColumns()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := make(graph.AdjacencyList, benchColumnsOrder)
+		for x, s := range benchSrc {
+			g[s] = append(g[s], graph.NI(benchDst[x]))
+		}
+	}
+}
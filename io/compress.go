@@ -0,0 +1,62 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// OpenReader opens a file for reading, transparently decompressing gzip or
+// bzip2 data.
+//
+// Compression is detected from magic bytes at the start of the file rather
+// than from the file name.  Data that is neither gzip nor bzip2 is returned
+// as is.  The returned ReadCloser closes the underlying file.
+func OpenReader(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := decompress(bufio.NewReader(f))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return fileReader{r, f}, nil
+}
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+)
+
+// decompress wraps b with a decompressor as indicated by magic bytes.
+func decompress(b *bufio.Reader) (io.Reader, error) {
+	magic, _ := b.Peek(len(bzip2Magic)) // short data is simply not compressed
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzip.NewReader(b)
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return bzip2.NewReader(b), nil
+	}
+	return b, nil
+}
+
+// fileReader reads from a possibly decompressing reader and closes the file.
+type fileReader struct {
+	io.Reader
+	f *os.File
+}
+
+func (r fileReader) Close() error {
+	if c, ok := r.Reader.(io.Closer); ok {
+		c.Close()
+	}
+	return r.f.Close()
+}
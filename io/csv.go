@@ -0,0 +1,252 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io
+
+// csv.go -- arc lists as comma separated values, Format CSV.
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode"
+
+	"github.com/soniakeys/graph"
+)
+
+// csvArcs holds the state of reading CSV arc records.
+type csvArcs struct {
+	t         *Text
+	arc       func(fr, to graph.NI, w float64) error
+	name      *[]string
+	getName   func(string) graph.NI
+	fr, to, w int // column indexes
+}
+
+// readCSV reads CSV arc records, calling arc for each with the weight if
+// weighted.  A record with no to-node is passed with to = -1.
+func (t Text) readCSV(r io.Reader, weighted bool, arc func(fr, to graph.NI, w float64) error) (name []string, ni map[string]graph.NI, err error) {
+	if err = t.fixBase(); err != nil {
+		return nil, nil, err
+	}
+	a := &csvArcs{t: &t, arc: arc, fr: 0, to: 1, w: 2}
+	if t.MapNames {
+		a.name, ni, a.getName = nameMap()
+	}
+	cr := csv.NewReader(r)
+	if t.Comma != 0 {
+		cr.Comma = t.Comma
+	}
+	cr.FieldsPerRecord = -1
+	// leading space is trimmed unless it would trim empty fields
+	cr.TrimLeadingSpace = !unicode.IsSpace(cr.Comma)
+	rec := 0
+	if t.Header {
+		h, err := cr.Read()
+		if err != nil {
+			if err == io.EOF {
+				err = errors.New("missing CSV header")
+			}
+			return nil, nil, err
+		}
+		rec++
+		col := func(name string, x *int) error {
+			if name == "" {
+				return nil
+			}
+			for i, h := range h {
+				if h == name {
+					*x = i
+					return nil
+				}
+			}
+			return fmt.Errorf("column %q not in CSV header", name)
+		}
+		if err = col(t.FromColumn, &a.fr); err == nil {
+			if err = col(t.ToColumn, &a.to); err == nil && weighted {
+				err = col(t.WeightColumn, &a.w)
+			}
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	need := a.fr
+	if a.to > need {
+		need = a.to
+	}
+	if weighted && a.w > need {
+		need = a.w
+	}
+	for {
+		f, err := cr.Read()
+		if err == io.EOF {
+			if a.name != nil {
+				name = *a.name
+			}
+			return name, ni, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		rec++
+		if err = a.record(f, need, weighted); err != nil {
+			return nil, nil, fmt.Errorf("CSV record %d: %v", rec, err)
+		}
+	}
+}
+
+// record passes the arc of CSV record f to a.arc.  Need is the largest
+// column index needed for an arc.
+func (a *csvArcs) record(f []string, need int, weighted bool) error {
+	if len(f) <= a.fr || f[a.fr] == "" {
+		return errors.New("blank from-node")
+	}
+	fr, err := a.getNI(f[a.fr])
+	if err != nil {
+		return err
+	}
+	if len(f) <= a.to || f[a.to] == "" {
+		return a.arc(fr, -1, 0)
+	}
+	if len(f) <= need {
+		return fmt.Errorf("%d fields, want %d", len(f), need+1)
+	}
+	to, err := a.getNI(f[a.to])
+	if err != nil {
+		return err
+	}
+	x := 0.
+	if weighted {
+		if x, err = strconv.ParseFloat(f[a.w], 64); err != nil {
+			return err
+		}
+	}
+	return a.arc(fr, to, x)
+}
+
+func (a *csvArcs) getNI(s string) (graph.NI, error) {
+	if a.getName != nil {
+		return a.getName(s), nil
+	}
+	n, err := strconv.ParseInt(s, a.t.Base, graph.NIBits)
+	if err != nil {
+		return -1, err
+	}
+	if n < 0 {
+		return -1, fmt.Errorf("invalid node: %d", n)
+	}
+	return graph.NI(n), nil
+}
+
+func (t Text) readWeightedCSV(r io.Reader) (
+	g graph.LabeledAdjacencyList, w graph.WeightFunc, wt []float64,
+	name []string, ni map[string]graph.NI, err error) {
+	grow := func(n graph.NI) {
+		for int(n) >= len(g) {
+			g = append(g, nil)
+		}
+	}
+	name, ni, err = t.readCSV(r, true, func(fr, to graph.NI, x float64) error {
+		grow(fr)
+		if to >= 0 {
+			grow(to)
+			g[fr] = append(g[fr], graph.Half{to, graph.LI(len(wt))})
+			wt = append(wt, x)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	w = func(l graph.LI) float64 { return wt[l] }
+	return g, w, wt, name, ni, nil
+}
+
+// countWriter counts bytes written to w.
+type countWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
+// writeCSV writes CSV arc records.  If w is nil, weights are not written.
+func (t Text) writeCSV(g graph.LabeledAdjacencyList, w graph.WeightFunc,
+	wr io.Writer) (int, error) {
+	writeLast := t.lalDefaults()
+	prec := t.Precision
+	if prec == 0 {
+		prec = -1
+	}
+	p := t.arcFilter()
+	cw := &countWriter{w: wr}
+	c := csv.NewWriter(cw)
+	if t.Comma != 0 {
+		c.Comma = t.Comma
+	}
+	if t.Header {
+		h := []string{t.FromColumn, t.ToColumn}
+		if h[0] == "" {
+			h[0] = "from"
+		}
+		if h[1] == "" {
+			h[1] = "to"
+		}
+		if w != nil {
+			h = append(h, t.WeightColumn)
+			if h[2] == "" {
+				h[2] = "weight"
+			}
+		}
+		c.Write(h)
+	}
+	last := len(g) - 1
+	for i, to := range g {
+		fr := graph.NI(i)
+		one := false
+		for _, to := range to {
+			if !p(fr, to.To) {
+				continue
+			}
+			one = true
+			rec := []string{t.NodeName(fr), t.NodeName(to.To)}
+			if w != nil {
+				rec = append(rec,
+					strconv.FormatFloat(w(to.Label), 'g', prec, 64))
+			}
+			c.Write(rec)
+		}
+		if writeLast && i == last && !one {
+			rec := []string{t.NodeName(fr), ""}
+			if w != nil {
+				rec = append(rec, "")
+			}
+			c.Write(rec)
+		}
+	}
+	c.Flush()
+	return cw.n, c.Error()
+}
+
+func (t Text) writeALCSV(g graph.AdjacencyList, w io.Writer) (int, error) {
+	l := make(graph.LabeledAdjacencyList, len(g))
+	for fr, to := range g {
+		l[fr] = make([]graph.Half, len(to))
+		for i, to := range to {
+			l[fr][i].To = to
+		}
+	}
+	return t.writeCSV(l, nil, w)
+}
+
+func (t Text) writeWeightedCSV(g graph.LabeledAdjacencyList,
+	w graph.WeightFunc, wr io.Writer) (int, error) {
+	return t.writeCSV(g, w, wr)
+}
@@ -0,0 +1,143 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/io"
+)
+
+func ExampleText_ReadAdjacencyList_cSV() {
+	r := strings.NewReader(`id,source,target
+1,"Smith, J",Jones
+2,Jones,Lee
+3,Lee,
+`)
+	t := io.Text{
+		Format:     io.CSV,
+		Header:     true,
+		FromColumn: "source",
+		ToColumn:   "target",
+		MapNames:   true,
+	}
+	g, names, _, err := t.ReadAdjacencyList(r)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g {
+		for _, to := range to {
+			fmt.Printf("%s -> %s\n", names[fr], names[to])
+		}
+	}
+	fmt.Println(len(g), "nodes")
+	// Output:
+	// Smith, J -> Jones
+	// Jones -> Lee
+	// 3 nodes
+}
+
+func ExampleText_WriteWeightedAdjacencyList_cSV() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}, {To: 2, Label: 1}},
+		2: {{To: 1, Label: 2}},
+	}
+	wt := []float64{1.5, 3, .25}
+	t := io.Text{Format: io.CSV, Header: true, WeightColumn: "cost"}
+	t.WriteWeightedAdjacencyList(g,
+		func(l graph.LI) float64 { return wt[l] }, os.Stdout)
+	// Output:
+	// from,to,cost
+	// 0,1,1.5
+	// 0,2,3
+	// 2,1,0.25
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		3: {0},
+		4: nil,
+	}
+	for _, tx := range []io.Text{
+		{Format: io.CSV},
+		{Format: io.CSV, Header: true, Comma: ';'},
+		{Format: io.CSV, Header: true, FromColumn: "a", ToColumn: "b"},
+	} {
+		var b bytes.Buffer
+		if _, err := tx.WriteAdjacencyList(g, &b); err != nil {
+			t.Fatal(err)
+		}
+		got, _, _, err := tx.ReadAdjacencyList(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, g) {
+			t.Fatalf("%+v: got %v, want %v", tx, got, g)
+		}
+	}
+}
+
+func TestCSVWeightedRoundTrip(t *testing.T) {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}},
+		1: {{To: 2, Label: 1}, {To: 0, Label: 2}},
+		2: nil,
+	}
+	wt := []float64{-2, 1.0 / 3, 7}
+	names := []string{"a,b", `say "c"`, "d"}
+	tx := io.Text{
+		Format:   io.CSV,
+		Header:   true,
+		NodeName: func(n graph.NI) string { return names[n] },
+	}
+	var b bytes.Buffer
+	_, err := tx.WriteWeightedAdjacencyList(g,
+		func(l graph.LI) float64 { return wt[l] }, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.NodeName = nil
+	tx.MapNames = true
+	got, _, gotWt, gotNames, _, err := tx.ReadWeightedAdjacencyList(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, g) || !reflect.DeepEqual(gotWt, wt) ||
+		!reflect.DeepEqual(gotNames, names) {
+		t.Fatalf("got %v %v %q", got, gotWt, gotNames)
+	}
+}
+
+func TestCSVErrors(t *testing.T) {
+	for _, c := range []struct {
+		tx   io.Text
+		data string
+	}{
+		{io.Text{Format: io.CSV, Header: true}, ""},
+		{io.Text{Format: io.CSV, Header: true, ToColumn: "x"}, "from,to\n"},
+		{io.Text{Format: io.CSV}, "0,a\n"},
+		{io.Text{Format: io.CSV}, "-1,0\n"},
+		{io.Text{Format: io.CSV}, ",1\n"},
+		{io.Text{Format: io.CSV}, "0,\"1\n"},
+	} {
+		if _, _, _, err := c.tx.ReadAdjacencyList(
+			strings.NewReader(c.data)); err == nil {
+			t.Errorf("%q: no error", c.data)
+		}
+	}
+	tx := io.Text{Format: io.CSV}
+	if _, _, _, _, _, err := tx.ReadWeightedAdjacencyList(
+		strings.NewReader("0,1\n")); err == nil {
+		t.Error("missing weight: no error")
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io
+
+// detect.go -- heuristic detection of text formats.
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/soniakeys/graph"
+)
+
+// Confidence is a score from 0 to 1 returned by DetectFormat.
+//
+// It is 1 when all text data was sampled and a single format is consistent
+// with it.  Otherwise it is n/(n+1) for n sampled data lines, as lines not
+// sampled might contradict the detected format.
+type Confidence float64
+
+// Candidate is a configuration consistent with sampled text data.
+//
+// Labeled true means the data should be read with ReadLabeledAdjacencyList,
+// otherwise with ReadAdjacencyList.
+type Candidate struct {
+	Text    Text
+	Labeled bool
+}
+
+func (c Candidate) String() string {
+	s := [...]string{"Sparse", "Dense", "Arcs", "CSV"}[c.Text.Format]
+	if c.Labeled {
+		s = "labeled " + s
+	}
+	if c.Text.FrDelim != "" {
+		s += fmt.Sprintf(" FrDelim %q", c.Text.FrDelim)
+	}
+	if c.Text.MapNames {
+		s += " MapNames"
+	}
+	if c.Text.Comment != "" {
+		s += fmt.Sprintf(" Comment %q", c.Text.Comment)
+	}
+	return s
+}
+
+// AmbiguousFormatError is returned by DetectFormat when sampled text data is
+// consistent with multiple configurations that would read it differently.
+type AmbiguousFormatError struct {
+	Candidates []Candidate
+}
+
+func (e *AmbiguousFormatError) Error() string {
+	c := make([]string, len(e.Candidates))
+	for i, ci := range e.Candidates {
+		c[i] = ci.String()
+	}
+	return "ambiguous text format, candidates: " + strings.Join(c, "; ")
+}
+
+// DetectFormat samples text data and returns a Text for reading it.
+//
+// Up to sampleLines lines are read from the current position of r, or 100
+// if sampleLines is less than 1.  r is then positioned back where it was,
+// ready for a subsequent read with the returned Text.
+//
+// Detection considers Sparse, Dense, and Arcs formats, labeled or not, with
+// numeric NIs in base 10 or with MapNames.  A comment delimiter "#" or "//"
+// is detected from lines starting with one of them.  Then:
+//
+// Data is read with MapNames unless all tokens are decimal integers.  A ":"
+// indicates Sparse format with FrDelim ":".  Parentheses or a legend
+// separator line "== labels ==" indicate labeled data.  Each candidate
+// format and labeling is checked against the number and kind of tokens on
+// each line.  For example labeled Arcs needs three tokens per line with an
+// integer label and labeled Dense needs an even number of integer tokens.
+// Dense requires numeric data and, if all data was sampled, to-nodes less
+// than the number of lines.  Where Sparse and Arcs are both consistent,
+// Arcs is returned.  The two read such data identically except for unlabeled
+// data with numeric NIs, where Sparse takes the graph order from from-nodes
+// only and Arcs also includes to-nodes.  Arcs is preferred as it does not
+// read arcs to nodes outside the graph.  Blank lines between
+// data lines indicate Dense, which writes a blank line for each node
+// without arcs.
+//
+// Returned labeled true means the data should be read with
+// ReadLabeledAdjacencyList.  If more than one candidate remains, an
+// *AmbiguousFormatError listing the candidates is returned rather than a
+// guess.  An error is also returned if no candidate is consistent with the
+// data, or for a read or seek error.
+func DetectFormat(r io.ReadSeeker, sampleLines int) (t Text, labeled bool, c Confidence, err error) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return
+	}
+	if sampleLines < 1 {
+		sampleLines = 100
+	}
+	var lines []string
+	eof := false
+	b := bufio.NewReader(r)
+	for len(lines) < sampleLines {
+		s, err := b.ReadString('\n')
+		if s != "" {
+			lines = append(lines, strings.TrimRight(s, "\r\n"))
+		}
+		if err == io.EOF {
+			eof = true
+			break
+		}
+		if err != nil {
+			return Text{}, false, 0, err
+		}
+	}
+	if _, err = r.Seek(start, io.SeekStart); err != nil {
+		return
+	}
+	cands, n, err := detect(lines, eof)
+	if err != nil {
+		return
+	}
+	switch len(cands) {
+	case 0:
+		return Text{}, false, 0, errors.New("no text format consistent with data")
+	case 1:
+	default:
+		return Text{}, false, 0, &AmbiguousFormatError{cands}
+	}
+	c = Confidence(float64(n) / float64(n+1))
+	if eof {
+		c = 1
+	}
+	return cands[0].Text, cands[0].Labeled, c, nil
+}
+
+// detLine holds the tokens of a line of sampled data.
+type detLine struct {
+	from   []string // tokens before ":", nil if no ":"
+	tok    []string // tokens after ":", or all tokens
+	parens bool
+}
+
+// all returns all tokens of the line.
+func (l detLine) all() []string {
+	return append(append([]string{}, l.from...), l.tok...)
+}
+
+// detect returns candidates consistent with sampled lines and the number
+// of data lines.
+func detect(lines []string, eof bool) (cands []Candidate, n int, err error) {
+	// comment delimiters at the start of lines
+	var comments []string
+	for _, c := range []string{"#", "//"} {
+		for _, s := range lines {
+			if strings.HasPrefix(strings.TrimSpace(s), c) {
+				comments = append(comments, c)
+				break
+			}
+		}
+	}
+	if len(comments) == 0 {
+		return detectComment(lines, eof, "")
+	}
+	for _, c := range comments {
+		cc, cn, cerr := detectComment(lines, eof, c)
+		if cerr == nil {
+			cands = append(cands, cc...)
+			n, err = cn, nil
+		} else if cands == nil {
+			err = cerr
+		}
+	}
+	return
+}
+
+// detectComment returns candidates consistent with sampled lines, using the
+// given comment delimiter, and the number of data lines.
+func detectComment(lines []string, eof bool, comment string) (cands []Candidate, n int, err error) {
+	var data []detLine
+	colon, parens, legend, blankBetween := false, false, false, false
+	numeric := true
+	blank := false
+	for _, s := range lines {
+		if strings.TrimSpace(s) == "" {
+			blank = len(data) > 0
+			continue
+		}
+		if comment != "" {
+			if i := strings.Index(s, comment); i >= 0 {
+				s = s[:i]
+			}
+		}
+		s = strings.TrimSpace(s)
+		if s == "== labels ==" {
+			legend = true
+			break
+		}
+		if s == "" {
+			continue // comment line
+		}
+		if blank {
+			blankBetween = true
+			blank = false
+		}
+		var l detLine
+		if i := strings.Index(s, ":"); i >= 0 {
+			colon = true
+			l.from = strings.Fields(s[:i])
+			s = s[i+1:]
+		}
+		if strings.ContainsAny(s, "()") {
+			l.parens, parens = true, true
+			s = strings.NewReplacer("(", " ", ")", " ").Replace(s)
+		}
+		l.tok = strings.Fields(s)
+		for _, t := range l.all() {
+			if !isInt(t) {
+				numeric = false
+			}
+		}
+		data = append(data, l)
+	}
+	if len(data) == 0 {
+		return nil, 0, errors.New("no data lines")
+	}
+	consistent := func(ok func(detLine) bool) bool {
+		for _, l := range data {
+			if !ok(l) {
+				return false
+			}
+		}
+		return true
+	}
+	// label tokens at odd indexes of to must be integers
+	labels := func(to []string) bool {
+		if len(to)%2 != 0 {
+			return false
+		}
+		for i := 1; i < len(to); i += 2 {
+			if !isInt(to[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	sparse := func(labeled bool) bool {
+		return consistent(func(l detLine) bool {
+			a := l.all()
+			switch {
+			case len(a) == 0:
+				return false
+			case colon && len(l.from) != 1 && !numeric:
+				return false // names need a from-node before every ":"
+			case labeled:
+				return labels(a[1:])
+			}
+			return !l.parens
+		})
+	}
+	arcs := func(labeled bool) bool {
+		return !colon && consistent(func(l detLine) bool {
+			k := len(l.tok)
+			if l.parens {
+				return false
+			}
+			if labeled {
+				return k == 1 || k == 3 && isInt(l.tok[2])
+			}
+			return k == 1 || k == 2
+		})
+	}
+	// with all lines sampled, Dense to-nodes must be less than the number
+	// of lines
+	order := len(lines)
+	if !eof {
+		order = -1
+	}
+	dense := func(labeled bool) bool {
+		return numeric && !colon && consistent(func(l detLine) bool {
+			step := 1
+			if labeled {
+				if !labels(l.tok) {
+					return false
+				}
+				step = 2
+			} else if l.parens {
+				return false
+			}
+			for i := 0; order >= 0 && i < len(l.tok); i += step {
+				if n, _ := strconv.Atoi(l.tok[i]); n >= order {
+					return false
+				}
+			}
+			return true
+		})
+	}
+	t := Text{Comment: comment, MapNames: !numeric}
+	for _, labeled := range []bool{false, true} {
+		if (parens || legend) && !labeled {
+			continue
+		}
+		switch {
+		case arcs(labeled):
+			t.Format = Arcs
+			cands = append(cands, Candidate{t, labeled})
+		case sparse(labeled):
+			t.Format = Sparse
+			if colon {
+				t.FrDelim = ":"
+			}
+			cands = append(cands, Candidate{t, labeled})
+			t.FrDelim = ""
+		}
+		if dense(labeled) {
+			t.Format = Dense
+			cands = append(cands, Candidate{t, labeled})
+		}
+	}
+	if blankBetween && len(cands) > 1 {
+		// keep only Dense
+		d := cands[:0]
+		for _, c := range cands {
+			if c.Text.Format == Dense {
+				d = append(d, c)
+			}
+		}
+		if len(d) > 0 {
+			cands = d
+		}
+	}
+	return cands, len(data), nil
+}
+
+// isInt returns true if s is a decimal integer.
+func isInt(s string) bool {
+	_, err := strconv.ParseInt(s, 10, graph.NIBits)
+	return err == nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io_test
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/io"
+)
+
+func ExampleDetectFormat() {
+	r := strings.NewReader(`# a small road network
+Boston: Albany Hartford
+Albany: Buffalo
+Buffalo:
+`)
+	t, labeled, c, err := io.DetectFormat(r, 10)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Format %d, FrDelim %q, MapNames %t, Comment %q\n",
+		t.Format, t.FrDelim, t.MapNames, t.Comment)
+	fmt.Println("labeled:", labeled, "confidence:", c)
+	g, names, _, err := t.ReadAdjacencyList(r)
+	for fr, to := range g {
+		for _, to := range to {
+			fmt.Println(names[fr], "->", names[to])
+		}
+	}
+	fmt.Println("err:", err)
+	// Output:
+	// Format 0, FrDelim ":", MapNames true, Comment "#"
+	// labeled: false confidence: 1
+	// Boston -> Albany
+	// Boston -> Hartford
+	// Albany -> Buffalo
+	// err: <nil>
+}
+
+func TestDetectFormat(t *testing.T) {
+	for _, c := range []struct {
+		data    string
+		want    io.Candidate
+		partial bool // sample does not reach EOF
+	}{
+		{"0: 1 2\n1: 2\n2:\n",
+			io.Candidate{Text: io.Text{FrDelim: ":"}}, false},
+		{"0 1\n0 5\n// comment\n5 2\n",
+			io.Candidate{Text: io.Text{Format: io.Arcs, Comment: "//"}}, false},
+		{"1 2\n\n0\n",
+			io.Candidate{Text: io.Text{Format: io.Dense}}, false},
+		{"a b\nb c\nc\n",
+			io.Candidate{Text: io.Text{Format: io.Arcs, MapNames: true}}, false},
+		{"a b c d\nb c\n",
+			io.Candidate{Text: io.Text{MapNames: true}}, false},
+		{"0: (1 5) (2 3)\n1: (2 4)\n",
+			io.Candidate{Text: io.Text{FrDelim: ":"}, Labeled: true}, false},
+		{"a: (b 5)\nb: (c 4)\n",
+			io.Candidate{Text: io.Text{FrDelim: ":", MapNames: true},
+				Labeled: true}, false},
+		{"a b 5\nb c 7\n== labels ==\n5 red\n7 blue\n",
+			io.Candidate{Text: io.Text{Format: io.Arcs, MapNames: true},
+				Labeled: true}, false},
+		{"0: 1\n1: 2\n2: 0\n",
+			io.Candidate{Text: io.Text{FrDelim: ":"}}, true},
+	} {
+		n := 10
+		if c.partial {
+			n = 2
+		}
+		r := strings.NewReader(c.data)
+		tx, labeled, conf, err := io.DetectFormat(r, n)
+		if err != nil {
+			t.Fatalf("%q: %v", c.data, err)
+		}
+		got := io.Candidate{tx, labeled}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("%q: got %v, want %v", c.data, got, c.want)
+		}
+		if c.partial && conf != 2./3 || !c.partial && conf != 1 {
+			t.Fatalf("%q: confidence %g", c.data, conf)
+		}
+		if r.Len() != len(c.data) {
+			t.Fatalf("%q: reader not positioned at start", c.data)
+		}
+	}
+}
+
+func TestDetectFormatAmbiguous(t *testing.T) {
+	for _, c := range []struct {
+		data string
+		want []io.Candidate
+	}{
+		// arc list or dense list of to-nodes
+		{"0 1\n1 0\n", []io.Candidate{
+			{Text: io.Text{Format: io.Arcs}},
+			{Text: io.Text{Format: io.Dense}},
+			{Text: io.Text{Format: io.Dense}, Labeled: true},
+		}},
+		// labeled arcs, or unlabeled sparse
+		{"0 1 5\n1 2 4\n", []io.Candidate{
+			{Text: io.Text{}},
+			{Text: io.Text{Format: io.Arcs}, Labeled: true},
+		}},
+		// two comment delimiters
+		{"#a b\n//c d\na b\n", []io.Candidate{
+			{Text: io.Text{Format: io.Arcs, MapNames: true, Comment: "#"}},
+			{Text: io.Text{Format: io.Arcs, MapNames: true, Comment: "//"}},
+		}},
+	} {
+		_, _, _, err := io.DetectFormat(strings.NewReader(c.data), 0)
+		a, ok := err.(*io.AmbiguousFormatError)
+		if !ok {
+			t.Fatalf("%q: got error %v, want AmbiguousFormatError", c.data, err)
+		}
+		if !reflect.DeepEqual(a.Candidates, c.want) {
+			t.Fatalf("%q: got %v, want %v", c.data, a.Candidates, c.want)
+		}
+	}
+	// no consistent format
+	if _, _, _, err := io.DetectFormat(strings.NewReader("a: b\nc d\n"), 0); err == nil {
+		t.Fatal("no error")
+	}
+	if _, _, _, err := io.DetectFormat(strings.NewReader("# x\n"), 0); err == nil {
+		t.Fatal("no data: no error")
+	}
+}
+
+// TestDetectFormatRoundTrip detects formats of data written by writers and
+// checks that data read with detected settings matches data read with the
+// writer settings.  For ambiguous data, one of the candidates must match.
+func TestDetectFormatRoundTrip(t *testing.T) {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 3}, {To: 2, Label: 4}},
+		1: {{To: 2, Label: 3}},
+		2: {{To: 0, Label: 5}, {To: 4, Label: 6}},
+		3: nil,
+		4: nil,
+	}
+	name := func(n graph.NI) string { return string(rune('p' + n)) }
+	for _, w := range []io.Text{
+		{},
+		{Format: io.Dense},
+		{Format: io.Arcs},
+		{NodeName: name},
+		{Format: io.Arcs, NodeName: name},
+	} {
+		for _, labeled := range []bool{false, true} {
+			var b bytes.Buffer
+			var err error
+			if labeled {
+				_, err = w.WriteLabeledAdjacencyList(g, &b)
+			} else {
+				_, err = w.WriteAdjacencyList(g.Unlabeled(), &b)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			data := b.String()
+			rt := w
+			rt.NodeName = nil
+			rt.MapNames = w.NodeName != nil
+			if rt.Format == io.Sparse {
+				rt.FrDelim = ":" // written as ": "
+			}
+			want := read(t, io.Candidate{rt, labeled}, data)
+			tx, l, _, err := io.DetectFormat(strings.NewReader(data), 0)
+			cands := []io.Candidate{{tx, l}}
+			if a, ok := err.(*io.AmbiguousFormatError); ok {
+				cands = a.Candidates
+			} else if err != nil {
+				t.Fatalf("%q: %v", data, err)
+			}
+			found := false
+			for _, c := range cands {
+				if reflect.DeepEqual(read(t, c, data), want) {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("%+v labeled %t %q: no compatible candidate in %v",
+					w, labeled, data, cands)
+			}
+		}
+	}
+}
+
+// read returns a string representation of data read with c, or of the error.
+func read(t *testing.T, c io.Candidate, data string) string {
+	r := strings.NewReader(data)
+	if c.Labeled {
+		g, name, _, _, _, err := c.Text.ReadLabeledAdjacencyList(r)
+		if err != nil {
+			return err.Error()
+		}
+		return fmt.Sprint(g, name)
+	}
+	g, name, _, err := c.Text.ReadAdjacencyList(r)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprint(g, name)
+}
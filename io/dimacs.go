@@ -0,0 +1,232 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io
+
+// dimacs.go -- DIMACS shortest path and maximum flow formats.
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/soniakeys/graph"
+)
+
+// ReadDimacsGr reads a graph in the DIMACS shortest path format.
+//
+// The format is that of the ninth DIMACS implementation challenge, ".gr"
+// files.  Lines starting with "c" are comments and blank lines are ignored.
+// A problem line "p sp n m" gives the number of nodes n and arcs m and must
+// precede arc lines.  Each arc line "a u v w" gives an arc from node u to
+// node v with weight w.  Nodes are numbered from 1 in the file and are read
+// as NIs numbered from 0.  Weights are parsed by strconv.ParseFloat.
+//
+// Arc labels of the returned graph are indexes into the returned weight
+// table wt, assigned in the order arcs are read.  An error is returned for
+// a missing or duplicate problem line, for node numbers out of range, or if
+// the number of arcs read differs from the problem line.
+func ReadDimacsGr(r io.Reader) (g graph.LabeledDirected, wt []float64, err error) {
+	d, err := readDimacs(r, "sp", nil)
+	if err != nil {
+		return graph.LabeledDirected{}, nil, err
+	}
+	return graph.LabeledDirected{d.g}, d.wt, nil
+}
+
+// WriteDimacsGr writes a graph in the DIMACS shortest path format.
+//
+// A problem line is written followed by an arc line for each arc, with
+// nodes numbered from 1 and weights w(label) formatted with
+// strconv.FormatFloat, format 'g', in the minimum number of digits that
+// read back as the exact value.  The graph can be read back by
+// ReadDimacsGr.
+//
+// Returned is number of bytes written and error.
+func WriteDimacsGr(g graph.LabeledDirected, w graph.WeightFunc, wr io.Writer) (n int, err error) {
+	b := newDimacsWriter(wr, "sp", g)
+	b.arcs(g, w)
+	return b.done()
+}
+
+// ReadDimacsMax reads a graph in the DIMACS maximum flow format.
+//
+// The format is that of the first DIMACS implementation challenge, ".max"
+// files.  It is as described at ReadDimacsGr, with problem line "p max n m"
+// and with arc weights read as capacities.  In addition there must be two
+// node lines, "n id s" giving the source node and "n id t" giving the sink
+// node.
+//
+// Returned is the graph, the capacity table indexed by arc label, and the
+// source and sink nodes.  An error is returned for missing or duplicate
+// node lines as well as for the conditions described at ReadDimacsGr.
+func ReadDimacsMax(r io.Reader) (g graph.LabeledDirected, capacity []float64, s, t graph.NI, err error) {
+	s, t = -1, -1
+	d, err := readDimacs(r, "max", func(n graph.NI, kind string) error {
+		p := &s
+		switch kind {
+		case "s":
+		case "t":
+			p = &t
+		default:
+			return fmt.Errorf("invalid node designator %q", kind)
+		}
+		if *p >= 0 {
+			return fmt.Errorf("duplicate node line %q", kind)
+		}
+		*p = n
+		return nil
+	})
+	switch {
+	case err != nil:
+	case s < 0:
+		err = errors.New("missing source node line")
+	case t < 0:
+		err = errors.New("missing sink node line")
+	default:
+		return graph.LabeledDirected{d.g}, d.wt, s, t, nil
+	}
+	return graph.LabeledDirected{}, nil, -1, -1, err
+}
+
+// WriteDimacsMax writes a graph in the DIMACS maximum flow format.
+//
+// A problem line and node lines for source s and sink t are written,
+// followed by arc lines with capacities c(label).  See WriteDimacsGr.  The
+// graph can be read back by ReadDimacsMax.
+//
+// Returned is number of bytes written and error.
+func WriteDimacsMax(g graph.LabeledDirected, c graph.WeightFunc, s, t graph.NI, wr io.Writer) (n int, err error) {
+	b := newDimacsWriter(wr, "max", g)
+	b.ws(fmt.Sprintf("n %d s\nn %d t\n", s+1, t+1))
+	b.arcs(g, c)
+	return b.done()
+}
+
+type dimacs struct {
+	g  graph.LabeledAdjacencyList
+	wt []float64
+}
+
+// readDimacs reads the common line types of DIMACS formats for the given
+// problem.  Node lines are passed to nodeLine, which may be nil if node
+// lines are not valid for the problem.
+func readDimacs(r io.Reader, problem string, nodeLine func(n graph.NI, kind string) error) (d dimacs, err error) {
+	b := bufio.NewReader(r)
+	m := -1 // arcs declared by the problem line
+	getNI := func(s string) (graph.NI, error) {
+		n, err := strconv.ParseInt(s, 10, graph.NIBits)
+		if err != nil {
+			return -1, err
+		}
+		if n < 1 || int(n) > len(d.g) {
+			return -1, fmt.Errorf("node %d out of range 1 to %d", n, len(d.g))
+		}
+		return graph.NI(n - 1), nil
+	}
+	line := func(s string) error {
+		f := strings.Fields(s)
+		if len(f) == 0 || f[0] == "c" {
+			return nil
+		}
+		if f[0] != "p" && m < 0 {
+			return errors.New("missing problem line")
+		}
+		switch f[0] {
+		case "p":
+			if m >= 0 {
+				return errors.New("duplicate problem line")
+			}
+			if len(f) != 4 || f[1] != problem {
+				return fmt.Errorf("want problem line \"p %s n m\"", problem)
+			}
+			n, err := strconv.Atoi(f[2])
+			if err != nil {
+				return err
+			}
+			if m, err = strconv.Atoi(f[3]); err != nil {
+				return err
+			}
+			if n < 0 || m < 0 {
+				return errors.New("negative count in problem line")
+			}
+			d.g = make(graph.LabeledAdjacencyList, n)
+		case "n":
+			if nodeLine == nil {
+				return errors.New("unexpected node line")
+			}
+			if len(f) != 3 {
+				return errors.New("want node line \"n id kind\"")
+			}
+			n, err := getNI(f[1])
+			if err != nil {
+				return err
+			}
+			return nodeLine(n, f[2])
+		case "a":
+			if len(f) != 4 {
+				return errors.New("want arc line \"a u v w\"")
+			}
+			fr, err := getNI(f[1])
+			if err != nil {
+				return err
+			}
+			to, err := getNI(f[2])
+			if err != nil {
+				return err
+			}
+			x, err := strconv.ParseFloat(f[3], 64)
+			if err != nil {
+				return err
+			}
+			d.g[fr] = append(d.g[fr], graph.Half{to, graph.LI(len(d.wt))})
+			d.wt = append(d.wt, x)
+		default:
+			return fmt.Errorf("unknown line type %q", f[0])
+		}
+		return nil
+	}
+	for ln := 1; ; ln++ {
+		s, err := b.ReadString('\n')
+		if len(s) > 0 {
+			if err := line(s); err != nil {
+				return dimacs{}, fmt.Errorf("line %d: %v", ln, err)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return dimacs{}, err
+		}
+	}
+	switch {
+	case m < 0:
+		return dimacs{}, errors.New("missing problem line")
+	case len(d.wt) != m:
+		return dimacs{}, fmt.Errorf("problem line gives %d arcs, %d read",
+			m, len(d.wt))
+	}
+	return d, nil
+}
+
+type dimacsWriter struct {
+	lalWriter
+}
+
+func newDimacsWriter(wr io.Writer, problem string, g graph.LabeledDirected) *dimacsWriter {
+	b := &dimacsWriter{lalWriter{b: bufio.NewWriter(wr)}}
+	b.ws(fmt.Sprintf("p %s %d %d\n", problem, g.Order(), g.ArcSize()))
+	return b
+}
+
+func (b *dimacsWriter) arcs(g graph.LabeledDirected, w graph.WeightFunc) {
+	g.Arcs(func(fr graph.NI, _ int, to graph.Half) bool {
+		b.ws(fmt.Sprintf("a %d %d %s\n", fr+1, to.To+1,
+			strconv.FormatFloat(w(to.Label), 'g', -1, 64)))
+		return true
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io_test
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/io"
+)
+
+func ExampleReadDimacsGr() {
+	r := strings.NewReader(`c a small shortest path instance
+p sp 3 3
+a 1 2 4
+a 2 3 1
+a 1 3 7
+`)
+	g, wt, err := io.ReadDimacsGr(r)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g.LabeledAdjacencyList {
+		for _, to := range to {
+			fmt.Println(fr, "->", to.To, "weight", wt[to.Label])
+		}
+	}
+	// Output:
+	// 0 -> 1 weight 4
+	// 0 -> 2 weight 7
+	// 1 -> 2 weight 1
+}
+
+func ExampleReadDimacsMax() {
+	r := strings.NewReader(`p max 4 5
+n 1 s
+n 4 t
+a 1 2 3
+a 1 3 2
+a 2 3 1
+a 2 4 2
+a 3 4 3
+`)
+	g, c, s, t, err := io.ReadDimacsMax(r)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("source", s, "sink", t)
+	fmt.Println("max flow", g.MaxFlowValue(s, t,
+		func(l graph.LI) float64 { return c[l] }))
+	io.WriteDimacsMax(g, func(l graph.LI) float64 { return c[l] }, s, t,
+		os.Stdout)
+	// Output:
+	// source 0 sink 3
+	// max flow 5
+	// p max 4 5
+	// n 1 s
+	// n 4 t
+	// a 1 2 3
+	// a 1 3 2
+	// a 2 3 1
+	// a 2 4 2
+	// a 3 4 3
+}
+
+func TestDimacsRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(28))
+	for i := 0; i < 20; i++ {
+		n := 1 + r.Intn(20)
+		g := graph.LabeledDirected{make(graph.LabeledAdjacencyList, n)}
+		var wt []float64
+		for m := r.Intn(3 * n); m > 0; m-- {
+			fr := r.Intn(n)
+			g.LabeledAdjacencyList[fr] = append(g.LabeledAdjacencyList[fr],
+				graph.Half{graph.NI(r.Intn(n)), graph.LI(len(wt))})
+			wt = append(wt, r.NormFloat64()*100)
+		}
+		w := func(l graph.LI) float64 { return wt[l] }
+		var b bytes.Buffer
+		if _, err := io.WriteDimacsGr(g, w, &b); err != nil {
+			t.Fatal(err)
+		}
+		g2, wt2, err := io.ReadDimacsGr(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// labels are renumbered in arc order, weights must match
+		if len(g2.LabeledAdjacencyList) != n {
+			t.Fatal(i, "order", len(g2.LabeledAdjacencyList))
+		}
+		for fr, to := range g.LabeledAdjacencyList {
+			to2 := g2.LabeledAdjacencyList[fr]
+			if len(to2) != len(to) {
+				t.Fatal(i, "node", fr, to, to2)
+			}
+			for x, h := range to {
+				if to2[x].To != h.To || wt2[to2[x].Label] != wt[h.Label] {
+					t.Fatal(i, "arc", fr, h, to2[x])
+				}
+			}
+		}
+		s, tn := graph.NI(r.Intn(n)), graph.NI(r.Intn(n))
+		b.Reset()
+		if _, err := io.WriteDimacsMax(g, w, s, tn, &b); err != nil {
+			t.Fatal(err)
+		}
+		g3, c, s3, t3, err := io.ReadDimacsMax(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s3 != s || t3 != tn || !reflect.DeepEqual(g3, g2) ||
+			!reflect.DeepEqual(c, wt2) {
+			t.Fatal(i, "max format round trip")
+		}
+	}
+}
+
+func TestDimacsErrors(t *testing.T) {
+	for _, tc := range []struct{ gr, want string }{
+		{"a 1 2 3\n", "line 1: missing problem line"},
+		{"", "missing problem line"},
+		{"p sp 2 1\np sp 2 1\n", "line 2: duplicate problem line"},
+		{"p max 2 1\n", `line 1: want problem line "p sp n m"`},
+		{"p sp 2 1\na 1 3 1\n", "line 2: node 3 out of range 1 to 2"},
+		{"p sp 2 1\na 0 1 1\n", "line 2: node 0 out of range 1 to 2"},
+		{"p sp 2 2\nc comment\na 1 2 1\n", "problem line gives 2 arcs, 1 read"},
+		{"p sp 2 1\nn 1 s\n", "line 2: unexpected node line"},
+		{"p sp 2 1\nx\n", `line 2: unknown line type "x"`},
+	} {
+		_, _, err := io.ReadDimacsGr(strings.NewReader(tc.gr))
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("%q: got %v, want %s", tc.gr, err, tc.want)
+		}
+	}
+	for _, tc := range []struct{ max, want string }{
+		{"p max 2 0\nn 1 s\n", "missing sink node line"},
+		{"p max 2 0\nn 2 t\n", "missing source node line"},
+		{"p max 2 0\nn 1 s\nn 2 s\n", `line 3: duplicate node line "s"`},
+		{"p max 2 0\nn 1 x\n", `line 2: invalid node designator "x"`},
+	} {
+		_, _, _, _, err := io.ReadDimacsMax(strings.NewReader(tc.max))
+		if err == nil || err.Error() != tc.want {
+			t.Errorf("%q: got %v, want %s", tc.max, err, tc.want)
+		}
+	}
+}
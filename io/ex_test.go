@@ -141,6 +141,31 @@ func ExampleText_ReadAdjacencyList() {
 	// err: <nil>
 }
 
+func ExampleText_ReadArcs() {
+	r := bytes.NewBufferString(`
+0: 2 3 3
+2: 3
+3:
+`)
+	// out-degrees and arc count without building a graph
+	deg := map[graph.NI]int{}
+	arcs := 0
+	_, _, err := io.Text{}.ReadArcs(r, func(fr, to graph.NI, _ graph.LI) error {
+		if to >= 0 {
+			deg[fr]++
+			arcs++
+		}
+		return nil
+	})
+	fmt.Println("arcs:", arcs)
+	fmt.Println("out-degrees:", deg)
+	fmt.Println("err:", err)
+	// Output:
+	// arcs: 4
+	// out-degrees: map[0:3 2:1]
+	// err: <nil>
+}
+
 // Example with zero value Text.  For options see examples under Text.
 func ExampleText_WriteAdjacencyList() {
 	//   0
@@ -254,12 +279,29 @@ func ExampleText_nodeName() {
 	// bytes: 12, err: <nil>
 }
 
-/*
+func ExampleText_ReadLabeledAdjacencyList() {
+	r := bytes.NewBufferString(`
+a: (b 3) (c 5)  # from a, arcs to b and c with labels 3 and 5
+c: (b 2)
+`)
+	t := io.Text{MapNames: true, FrDelim: ":", Comment: "#"}
+	g, names, _, _, _, err := t.ReadLabeledAdjacencyList(r)
+	for n, to := range g {
+		fmt.Println(names[n], to)
+	}
+	fmt.Println("err:", err)
+	// Output:
+	// a [{1 3} {2 5}]
+	// b []
+	// c [{1 2}]
+	// err: <nil>
+}
+
 func ExampleText_WriteLabeledAdjacencyList() {
 	//     0
-	//    / \\
-	// a /  b\\c
-	//  /     \\
+	//    / \
+	// a /  b\c
+	//  /     \
 	// 2------->3
 	//     d
 	g := graph.LabeledAdjacencyList{
@@ -269,10 +311,126 @@ func ExampleText_WriteLabeledAdjacencyList() {
 	}
 	n, err := io.Text{}.WriteLabeledAdjacencyList(g, os.Stdout)
 	fmt.Printf("bytes: %d, err: %v\n\n", n, err)
+
+	name := []string{"w", "x", "y", "z"}
+	t := io.Text{
+		Format:   io.Arcs,
+		NodeName: func(n graph.NI) string { return name[n] },
+	}
+	n, err = t.WriteLabeledAdjacencyList(g, os.Stdout)
+	fmt.Printf("bytes: %d, err: %v\n", n, err)
 	// Output:
-	// 0: (2 96) (3 97) (3 98)
-	// 2: (3 99)
+	// 0: (2 97) (3 98) (3 99)
+	// 2: (3 100)
 	// 3:
-	// bytes: 17, err: <nil>
+	// bytes: 38, err: <nil>
+	//
+	// w y 97
+	// w z 98
+	// w z 99
+	// y z 100
+	// bytes: 29, err: <nil>
+}
+
+func ExampleText_labelName() {
+	g := graph.LabeledAdjacencyList{
+		0: {{1, 0}, {2, 1}},
+		2: {{1, 0}},
+	}
+	label := []string{"road", "ferry"}
+	name := []string{"Oslo", "Kiel", "Bergen"}
+	t := io.Text{
+		FrDelim:        ": ",
+		NodeName:       func(n graph.NI) string { return name[n] },
+		LabelName:      func(l graph.LI) string { return label[l] },
+		SymbolicLabels: true,
+	}
+	var b bytes.Buffer
+	t.WriteLabeledAdjacencyList(g, &b)
+	fmt.Print(b.String())
+
+	t = io.Text{FrDelim: ":", MapNames: true, SymbolicLabels: true}
+	r, nodes, _, labels, _, err := t.ReadLabeledAdjacencyList(&b)
+	fmt.Println()
+	for fr, to := range r {
+		for _, h := range to {
+			fmt.Println(nodes[fr], nodes[h.To], h.Label, labels[h.Label])
+		}
+	}
+	fmt.Println("err:", err)
+	// Output:
+	// Oslo: (Kiel road) (Bergen ferry)
+	// Bergen: (Kiel road)
+	// == labels ==
+	// 0 road
+	// 1 ferry
+	//
+	// Oslo Kiel 0 road
+	// Oslo Bergen 1 ferry
+	// Bergen Kiel 0 road
+	// err: <nil>
+}
+
+func ExampleText_ReadWeightedAdjacencyList() {
+	r := bytes.NewBufferString(`
+a b 1.75  // arc from a to b with weight 1.75
+a c .5
+c b -2e3
+`)
+	t := io.Text{MapNames: true, Comment: "//"}
+	g, w, _, names, _, err := t.ReadWeightedAdjacencyList(r)
+	for fr, to := range g {
+		for _, h := range to {
+			fmt.Println(names[fr], names[h.To], w(h.Label))
+		}
+	}
+	fmt.Println("err:", err)
+	// Output:
+	// a b 1.75
+	// a c 0.5
+	// c b -2000
+	// err: <nil>
+}
+
+func ExampleText_WriteWeightedAdjacencyList() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}, {To: 2, Label: 1}},
+		2: {{To: 1, Label: 2}},
+	}
+	wt := []float64{1.75, 1. / 3, 2e3}
+	w := func(l graph.LI) float64 { return wt[l] }
+	n, err := io.Text{}.WriteWeightedAdjacencyList(g, w, os.Stdout)
+	fmt.Printf("bytes: %d, err: %v\n\n", n, err)
+
+	n, err = io.Text{Precision: 3, FrDelim: " -> ", HalfDelim: ": "}.
+		WriteWeightedAdjacencyList(g, w, os.Stdout)
+	fmt.Printf("bytes: %d, err: %v\n", n, err)
+	// Output:
+	// 0 1 1.75
+	// 0 2 0.3333333333333333
+	// 2 1 2000
+	// bytes: 41, err: <nil>
+	//
+	// 0 -> 1: 1.75
+	// 0 -> 2: 0.333
+	// 2 -> 1: 2e+03
+	// bytes: 41, err: <nil>
+}
+
+func ExampleWriteBinary() {
+	g := graph.AdjacencyList{
+		0: {2, 3, 3},
+		2: {3},
+		3: {},
+	}
+	var b bytes.Buffer
+	n, err := io.WriteBinary(g, &b)
+	fmt.Printf("bytes: %d, err: %v\n", n, err)
+	fmt.Printf("% x\n", b.Bytes())
+	r, err := io.ReadBinary(&b)
+	fmt.Println(r, err)
+	// Output:
+	// bytes: 14, err: <nil>
+	// 67 52 61 6c 01 04 03 00 01 00 02 03 03 03
+	// [[2 3 3] [] [3] []] <nil>
 }
-*/
@@ -0,0 +1,241 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package io
+
+// legend.go -- label legends of labeled adjacency list text.
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/soniakeys/graph"
+)
+
+// legendSep returns the trimmed legend separator line.
+func (t *Text) legendSep() string {
+	if s := strings.TrimSpace(t.LegendSep); s > "" {
+		return s
+	}
+	return "== labels =="
+}
+
+// bodyReader reads lines of b up to a legend separator line, then returns
+// io.EOF.  Field legend is set if the separator was found, in which case the
+// legend can be read from b.
+type bodyReader struct {
+	t      *Text
+	b      *bufio.Reader
+	sep    string
+	line   string // unread part of current line
+	legend bool
+}
+
+func (t *Text) newBodyReader(r io.Reader) *bodyReader {
+	return &bodyReader{t: t, b: bufio.NewReader(r), sep: t.legendSep()}
+}
+
+func (r *bodyReader) Read(p []byte) (int, error) {
+	for r.line == "" {
+		if r.legend {
+			return 0, io.EOF
+		}
+		s, err := r.b.ReadString('\n')
+		if s == "" {
+			return 0, err
+		}
+		// the separator is matched with or without a trailing comment.
+		c := strings.TrimSpace(s)
+		if r.t.Comment > "" && c != r.sep {
+			if i := strings.Index(c, r.t.Comment); i >= 0 {
+				c = strings.TrimSpace(c[:i])
+			}
+		}
+		if c == r.sep {
+			r.legend = true
+			return 0, io.EOF
+		}
+		r.line = s
+	}
+	n := copy(p, r.line)
+	r.line = r.line[n:]
+	return n, nil
+}
+
+// labelParser parses labels of a labeled adjacency list.  If sym is non-nil,
+// labels are names, numbered provisionally in order of first appearance,
+// with the names recorded in name.
+type labelParser struct {
+	base int
+	sym  map[string]graph.LI
+	name []string
+}
+
+func (p *labelParser) parse(s string) (graph.LI, error) {
+	if p.sym == nil {
+		l, err := strconv.ParseInt(s, p.base, liBits)
+		return graph.LI(l), err
+	}
+	l, ok := p.sym[s]
+	if !ok {
+		l = graph.LI(len(p.name))
+		p.sym[s] = l
+		p.name = append(p.name, s)
+	}
+	return l, nil
+}
+
+// resolve replaces provisional labels in g with the labels named in legend li.
+func (p *labelParser) resolve(g graph.LabeledAdjacencyList,
+	li map[string]graph.LI) error {
+	if p.sym == nil {
+		return nil
+	}
+	to := make([]graph.LI, len(p.name))
+	for x, n := range p.name {
+		l, ok := li[n]
+		if !ok {
+			return fmt.Errorf("label %q not in legend", n)
+		}
+		to[x] = l
+	}
+	for _, hs := range g {
+		for x := range hs {
+			hs[x].Label = to[hs[x].Label]
+		}
+	}
+	return nil
+}
+
+// readLegend reads legend lines to EOF.  Each line has a numeric label and
+// the rest of the line, trimmed of whitespace, is the label name.
+func (t *Text) readLegend(b *bufio.Reader) (
+	name []string, li map[string]graph.LI, err error) {
+	li = map[string]graph.LI{}
+	for {
+		s, err := t.readStripComment(b)
+		if err != nil {
+			if err != io.EOF {
+				return nil, nil, err
+			}
+			return name, li, nil
+		}
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		ls, n := s, ""
+		if x := strings.IndexFunc(s, unicode.IsSpace); x >= 0 {
+			ls, n = s[:x], strings.TrimSpace(s[x:])
+		}
+		l, err := strconv.ParseInt(ls, t.Base, liBits)
+		if err != nil {
+			return nil, nil, err
+		}
+		if l < 0 {
+			return nil, nil, fmt.Errorf("invalid legend label: %d", l)
+		}
+		if n == "" {
+			return nil, nil, errors.New("blank label name")
+		}
+		if _, ok := li[n]; ok {
+			return nil, nil, fmt.Errorf("duplicate label name: %q", n)
+		}
+		for int(l) >= len(name) {
+			name = append(name, "")
+		}
+		if name[l] > "" {
+			return nil, nil, fmt.Errorf("duplicate legend label: %d", l)
+		}
+		name[l] = n
+		li[n] = graph.LI(l)
+	}
+}
+
+// legendLabels returns the distinct labels of arcs of g to be written, in
+// increasing order, or nil if LabelName is nil.  It validates that labels
+// are non-negative and label names are non-blank, distinct, and can be read
+// back.  Delimiters must already have their write defaults.
+func (t *Text) legendLabels(g graph.LabeledAdjacencyList) ([]graph.LI, error) {
+	if t.LabelName == nil {
+		return nil, nil
+	}
+	p := t.arcFilter()
+	m := map[graph.LI]bool{}
+	for fr, to := range g {
+		for _, h := range to {
+			if p(graph.NI(fr), h.To) {
+				m[h.Label] = true
+			}
+		}
+	}
+	ls := make([]graph.LI, 0, len(m))
+	for l := range m {
+		if l < 0 {
+			return nil, fmt.Errorf("invalid legend label: %d", l)
+		}
+		ls = append(ls, l)
+	}
+	sort.Slice(ls, func(i, j int) bool { return ls[i] < ls[j] })
+	// strings that would be taken as delimiters on reading
+	var delims []string
+	if t.Comment > "" {
+		delims = append(delims, t.Comment)
+	}
+	symbolic := t.SymbolicLabels && t.Format != Dense
+	if symbolic {
+		for _, d := range []string{t.FrDelim, t.ToDelim, t.HalfDelim,
+			t.Open, t.Close} {
+			if d = strings.TrimSpace(d); d > "" {
+				delims = append(delims, d)
+			}
+		}
+	}
+	names := map[string]bool{}
+	for _, l := range ls {
+		n := strings.TrimSpace(t.LabelName(l))
+		if n == "" || strings.ContainsAny(n, "\r\n") ||
+			symbolic && strings.IndexFunc(n, unicode.IsSpace) >= 0 {
+			return nil, fmt.Errorf("invalid name for label %d: %q", l, n)
+		}
+		for _, d := range delims {
+			if strings.Contains(n, d) {
+				return nil, fmt.Errorf("name for label %d contains delimiter %q: %q", l, d, n)
+			}
+		}
+		if names[n] {
+			return nil, fmt.Errorf("duplicate label name: %q", n)
+		}
+		names[n] = true
+	}
+	return ls, nil
+}
+
+// labelText returns l formatted for the body of a labeled adjacency list.
+func (t *Text) labelText(l graph.LI) string {
+	if t.SymbolicLabels && t.LabelName != nil {
+		return strings.TrimSpace(t.LabelName(l))
+	}
+	return strconv.FormatInt(int64(l), t.Base)
+}
+
+// writeLegend writes the separator line and a legend line for each label
+// of ls.
+func (t *Text) writeLegend(ls []graph.LI, b *lalWriter) {
+	if t.LabelName == nil {
+		return
+	}
+	b.ws(t.legendSep())
+	b.ws("\n")
+	for _, l := range ls {
+		b.ws(strconv.FormatInt(int64(l), t.Base))
+		b.ws(" ")
+		b.ws(strings.TrimSpace(t.LabelName(l)))
+		b.ws("\n")
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io
+
+// matrix.go -- dense adjacency matrices as comma separated values.
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/soniakeys/graph"
+)
+
+// Matrix defines options for reading and writing adjacency matrices.
+//
+// An adjacency matrix is read and written as CSV data, by package
+// encoding/csv, with a record for each row.  Row fr, column to of the matrix
+// holds the arcs from node fr to node to.  A blank cell is read as 0.
+//
+// The zero value is valid and usable by all methods.
+type Matrix struct {
+	// Comma is the field delimiter.  Methods use ',' if Comma is 0.
+	Comma rune
+
+	// Names true means that the first record and the first field of each
+	// following record hold node names.  The first field of the first record
+	// is ignored.  Read methods require the row names to match the column
+	// names in order.
+	Names bool
+}
+
+// ReadAdjacencyMatrix reads an adjacency matrix and returns a Directed
+// graph.
+//
+// Each cell is parsed by strconv.ParseFloat.  A nonzero value gives a single
+// arc and zero gives no arc.  The returned graph has no parallel arcs.  With
+// Matrix.Names true, the method also returns the node names.
+//
+// An error is returned if rows have different numbers of fields or if the
+// matrix is not square.
+func (m Matrix) ReadAdjacencyMatrix(r io.Reader) (g graph.Directed, names []string, err error) {
+	a := graph.AdjacencyList{}
+	names, err = m.read(r, func(fr, to graph.NI, x float64) {
+		a[fr] = append(a[fr], to)
+	}, func(order int) { a = make(graph.AdjacencyList, order) })
+	if err != nil {
+		return graph.Directed{}, nil, err
+	}
+	return graph.Directed{a}, names, nil
+}
+
+// ReadWeightedAdjacencyMatrix reads an adjacency matrix and returns a
+// LabeledDirected graph with a weight table.
+//
+// Each cell is parsed by strconv.ParseFloat.  A nonzero value gives an arc
+// with that weight and zero gives no arc.  Arc labels of the returned graph
+// are indexes into the returned weight table wt, assigned in row-major
+// order.  With Matrix.Names true, the method also returns the node names.
+//
+// An error is returned if rows have different numbers of fields or if the
+// matrix is not square.
+func (m Matrix) ReadWeightedAdjacencyMatrix(r io.Reader) (g graph.LabeledDirected, wt []float64, names []string, err error) {
+	a := graph.LabeledAdjacencyList{}
+	names, err = m.read(r, func(fr, to graph.NI, x float64) {
+		a[fr] = append(a[fr], graph.Half{to, graph.LI(len(wt))})
+		wt = append(wt, x)
+	}, func(order int) { a = make(graph.LabeledAdjacencyList, order) })
+	if err != nil {
+		return graph.LabeledDirected{}, nil, nil, err
+	}
+	return graph.LabeledDirected{a}, wt, names, nil
+}
+
+// read reads a matrix, calling alloc once with the order when it is known
+// and then arc for each nonzero cell in row-major order.
+func (m Matrix) read(r io.Reader, arc func(fr, to graph.NI, x float64), alloc func(order int)) (names []string, err error) {
+	cr := csv.NewReader(r)
+	if m.Comma != 0 {
+		cr.Comma = m.Comma
+	}
+	// leading space is trimmed unless it would trim empty fields
+	cr.TrimLeadingSpace = !unicode.IsSpace(cr.Comma)
+	recs, err := cr.ReadAll() // FieldsPerRecord 0 rejects ragged rows
+	if err != nil {
+		return nil, err
+	}
+	c0 := 0 // column of first cell
+	if m.Names {
+		if len(recs) == 0 {
+			return nil, errors.New("missing names record")
+		}
+		names = recs[0][1:]
+		recs = recs[1:]
+		c0 = 1
+	}
+	order := len(recs)
+	cols := len(names)
+	if order > 0 {
+		cols = len(recs[0]) - c0
+	}
+	if cols != order {
+		return nil, fmt.Errorf("matrix not square: %d rows, %d columns",
+			order, cols)
+	}
+	alloc(order)
+	for fr, rec := range recs {
+		if m.Names && rec[0] != names[fr] {
+			return nil, fmt.Errorf("row %d name %q does not match column name %q",
+				fr+1, rec[0], names[fr])
+		}
+		for to, s := range rec[c0:] {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			x, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("row %d column %d: %v", fr+1, to+1, err)
+			}
+			if x != 0 {
+				arc(graph.NI(fr), graph.NI(to), x)
+			}
+		}
+	}
+	return names, nil
+}
+
+// WriteAdjacencyMatrix writes g as an adjacency matrix.
+//
+// Each cell holds 1 if there is an arc from the row node to the column node
+// and 0 otherwise.  If Matrix.Names is true, names must hold a name for each
+// node of g.
+//
+// An error is returned if g has parallel arcs, as these cannot be
+// represented.
+//
+// Returned is number of bytes written and error.
+func (m Matrix) WriteAdjacencyMatrix(g graph.AdjacencyList, names []string, w io.Writer) (int, error) {
+	return m.write(len(g), names, w, func(fr int, row []string) error {
+		for to := range row {
+			row[to] = "0"
+		}
+		for _, to := range g[fr] {
+			if row[to] != "0" {
+				return fmt.Errorf("parallel arcs %d->%d", fr, to)
+			}
+			row[to] = "1"
+		}
+		return nil
+	})
+}
+
+// WriteWeightedAdjacencyMatrix writes g as an adjacency matrix of weights.
+//
+// Each cell holds the weight w(label) of the arc from the row node to the
+// column node, formatted with strconv.FormatFloat, format 'g', in the
+// minimum number of digits that read back as the exact value, or 0 if there
+// is no arc.  If Matrix.Names is true, names must hold a name for each node
+// of g.
+//
+// An error is returned if g has parallel arcs or an arc of weight 0, as
+// these cannot be represented.
+//
+// Returned is number of bytes written and error.
+func (m Matrix) WriteWeightedAdjacencyMatrix(g graph.LabeledAdjacencyList, w graph.WeightFunc, names []string, wr io.Writer) (int, error) {
+	return m.write(len(g), names, wr, func(fr int, row []string) error {
+		for to := range row {
+			row[to] = "0"
+		}
+		for _, to := range g[fr] {
+			if row[to.To] != "0" {
+				return fmt.Errorf("parallel arcs %d->%d", fr, to.To)
+			}
+			x := w(to.Label)
+			if x == 0 {
+				return fmt.Errorf("arc %d->%d has weight 0", fr, to.To)
+			}
+			row[to.To] = strconv.FormatFloat(x, 'g', -1, 64)
+		}
+		return nil
+	})
+}
+
+// write writes a matrix of the given order, calling row to fill in the
+// cells of each row.
+func (m Matrix) write(order int, names []string, w io.Writer, row func(fr int, cells []string) error) (int, error) {
+	if m.Names && len(names) != order {
+		return 0, fmt.Errorf("%d names for order %d", len(names), order)
+	}
+	cw := &countWriter{w: w}
+	c := csv.NewWriter(cw)
+	if m.Comma != 0 {
+		c.Comma = m.Comma
+	}
+	c0 := 0
+	if m.Names {
+		c0 = 1
+		c.Write(append([]string{""}, names...))
+	}
+	rec := make([]string, c0+order)
+	for fr := 0; fr < order; fr++ {
+		if m.Names {
+			rec[0] = names[fr]
+		}
+		if err := row(fr, rec[c0:]); err != nil {
+			return 0, err
+		}
+		c.Write(rec)
+	}
+	c.Flush()
+	return cw.n, c.Error()
+}
@@ -0,0 +1,144 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/io"
+)
+
+func ExampleMatrix_ReadAdjacencyMatrix() {
+	r := strings.NewReader(`0,1,0
+0,0,2
+1,,0
+`)
+	g, _, err := io.Matrix{}.ReadAdjacencyMatrix(r)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for fr, to := range g.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// 0 [1]
+	// 1 [2]
+	// 2 [0]
+}
+
+func ExampleMatrix_WriteWeightedAdjacencyMatrix() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}},
+		1: {{To: 0, Label: 1}, {To: 2, Label: 2}},
+		2: nil,
+	}
+	wt := []float64{2.5, 1, -4}
+	io.Matrix{Names: true}.WriteWeightedAdjacencyMatrix(g,
+		func(l graph.LI) float64 { return wt[l] },
+		[]string{"x", "y", "z"}, os.Stdout)
+	// Output:
+	// ,x,y,z
+	// x,0,2.5,0
+	// y,1,0,-4
+	// z,0,0,0
+}
+
+func TestMatrixRoundTrip(t *testing.T) {
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {1},
+		2: nil,
+		3: {0},
+	}
+	names := []string{"a", "b", "c, d", "e"}
+	m := io.Matrix{Comma: '\t', Names: true}
+	var b bytes.Buffer
+	if _, err := m.WriteAdjacencyMatrix(g, names, &b); err != nil {
+		t.Fatal(err)
+	}
+	got, gotNames, err := m.ReadAdjacencyMatrix(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.AdjacencyList, g) ||
+		!reflect.DeepEqual(gotNames, names) {
+		t.Fatalf("got %v %q", got.AdjacencyList, gotNames)
+	}
+}
+
+func TestMatrixWeightedRoundTrip(t *testing.T) {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 2, Label: 0}},
+		1: nil,
+		2: {{To: 0, Label: 1}, {To: 1, Label: 2}},
+	}
+	wt := []float64{.1, 1e-9, -3}
+	var b bytes.Buffer
+	_, err := io.Matrix{}.WriteWeightedAdjacencyMatrix(g,
+		func(l graph.LI) float64 { return wt[l] }, nil, &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, gotWt, _, err := io.Matrix{}.ReadWeightedAdjacencyMatrix(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.LabeledAdjacencyList, g) ||
+		!reflect.DeepEqual(gotWt, wt) {
+		t.Fatalf("got %v %v", got.LabeledAdjacencyList, gotWt)
+	}
+}
+
+func TestMatrixCellValues(t *testing.T) {
+	// any nonzero value gives a single arc
+	g, _, err := io.Matrix{}.ReadAdjacencyMatrix(
+		strings.NewReader("1e9,-2,0\n.5,0,3\n0,0,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := graph.AdjacencyList{{0, 1}, {0, 2}, nil}
+	if !reflect.DeepEqual(g.AdjacencyList, want) {
+		t.Fatal(g.AdjacencyList)
+	}
+}
+
+func TestMatrixErrors(t *testing.T) {
+	for _, c := range []struct {
+		m    io.Matrix
+		data string
+	}{
+		{io.Matrix{}, "0,1\n1\n"},             // ragged
+		{io.Matrix{}, "0,1,0\n1,0,0\n"},       // not square
+		{io.Matrix{}, "0,x\n1,0\n"},           // not a number
+		{io.Matrix{Names: true}, ""},          // no names
+		{io.Matrix{Names: true}, ",a\n"},      // no rows
+		{io.Matrix{Names: true}, ",a\nb,0\n"}, // name mismatch
+	} {
+		if _, _, err := c.m.ReadAdjacencyMatrix(
+			strings.NewReader(c.data)); err == nil {
+			t.Errorf("%q: no error", c.data)
+		}
+	}
+	w := func(graph.LI) float64 { return 1 }
+	par := graph.LabeledAdjacencyList{{{To: 0}, {To: 0}}}
+	if _, err := (io.Matrix{}).WriteWeightedAdjacencyMatrix(
+		par, w, nil, &bytes.Buffer{}); err == nil {
+		t.Error("parallel arcs: no error")
+	}
+	if _, err := (io.Matrix{}).WriteAdjacencyMatrix(
+		par.Unlabeled(), nil, &bytes.Buffer{}); err == nil {
+		t.Error("unlabeled parallel arcs: no error")
+	}
+	if _, err := (io.Matrix{Names: true}).WriteAdjacencyMatrix(
+		graph.AdjacencyList{nil}, nil, &bytes.Buffer{}); err == nil {
+		t.Error("missing names: no error")
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io
+
+// namespace.go -- merging graphs read with different node name mappings.
+
+import "github.com/soniakeys/graph"
+
+// NameSpace is a mapping between node names and NIs, as returned by read
+// methods with Text.MapNames true.
+//
+// Names holds node names indexed by NI and NI holds the reverse mapping.
+//
+// Key, if non-nil, normalizes names for matching by Merge.  For example
+// with Key strings.ToLower, names are matched without regard to case.  If
+// Key is nil, names match only if identical.
+type NameSpace struct {
+	Names []string
+	NI    map[string]graph.NI
+	Key   func(string) string
+}
+
+// Merge combines the names of s and other.
+//
+// The combined NameSpace has the names of s with the same NIs, followed by
+// names of other not matching a name of s, in order of their NIs in other.
+// Names match if they are equal after normalizing with s.Key.  Matching
+// names are taken to be the same node.  Where names match but are spelled
+// differently, the combined Names has the spelling of s, and the combined
+// NI maps both spellings to the combined node.  Combined has the Key of s.
+//
+// Returned remapOther is a translation table giving the combined NI of each
+// NI of other, for use with TranslateGraph.
+func (s NameSpace) Merge(other NameSpace) (combined NameSpace, remapOther []graph.NI) {
+	key := s.Key
+	if key == nil {
+		key = func(n string) string { return n }
+	}
+	combined = NameSpace{
+		Names: append([]string{}, s.Names...),
+		NI:    make(map[string]graph.NI, len(s.Names)+len(other.Names)),
+		Key:   s.Key,
+	}
+	k := make(map[string]graph.NI, len(s.Names)+len(other.Names))
+	for n, name := range s.Names {
+		combined.NI[name] = graph.NI(n)
+		k[key(name)] = graph.NI(n)
+	}
+	remapOther = make([]graph.NI, len(other.Names))
+	for n, name := range other.Names {
+		c, ok := k[key(name)]
+		if !ok {
+			c = graph.NI(len(combined.Names))
+			combined.Names = append(combined.Names, name)
+			k[key(name)] = c
+		}
+		combined.NI[name] = c
+		remapOther[n] = c
+	}
+	return
+}
+
+// TranslateGraph returns a copy of g with NIs translated by remap.
+//
+// Node n of g becomes node remap[n] of the result.  Remap must have an entry
+// for each node of g.  The result has order newOrder, or greater if needed
+// to hold the translated nodes.  Nodes of the result not translated from g
+// have no arcs.  Where remap maps multiple nodes of g to the same node, their
+// arcs are combined.
+//
+// See also TranslateLabeledGraph.
+func TranslateGraph(g graph.AdjacencyList, remap []graph.NI, newOrder int) graph.AdjacencyList {
+	t := make(graph.AdjacencyList, translateOrder(len(g), remap, newOrder))
+	for fr, to := range g {
+		tf := remap[fr]
+		for _, to := range to {
+			t[tf] = append(t[tf], remap[to])
+		}
+	}
+	return t
+}
+
+// TranslateLabeledGraph returns a copy of g with NIs translated by remap.
+//
+// Labels are unchanged.  See TranslateGraph.
+func TranslateLabeledGraph(g graph.LabeledAdjacencyList, remap []graph.NI, newOrder int) graph.LabeledAdjacencyList {
+	t := make(graph.LabeledAdjacencyList, translateOrder(len(g), remap, newOrder))
+	for fr, to := range g {
+		tf := remap[fr]
+		for _, to := range to {
+			t[tf] = append(t[tf], graph.Half{remap[to.To], to.Label})
+		}
+	}
+	return t
+}
+
+// translateOrder returns the order of a graph of order n translated by
+// remap, at least newOrder.
+func translateOrder(n int, remap []graph.NI, newOrder int) int {
+	for _, r := range remap[:n] {
+		if int(r) >= newOrder {
+			newOrder = int(r) + 1
+		}
+	}
+	return newOrder
+}
+
+// MergeGraphs merges two graphs read with different name mappings.
+//
+// Name spaces s1 and s2 are merged as by s1.Merge(s2), with nodes of the
+// same name taken to be the same node.  The result graph has the combined
+// order and the arcs of g1 and of g2, translated to combined NIs.  An arc
+// present in both graphs is present twice in the result, as parallel arcs.
+//
+// Nodes of g1 and g2 beyond the lengths of their name lists are not
+// supported.
+func MergeGraphs(g1 graph.AdjacencyList, s1 NameSpace, g2 graph.AdjacencyList, s2 NameSpace) (graph.AdjacencyList, NameSpace) {
+	c, r2 := s1.Merge(s2)
+	g := TranslateGraph(g2, r2, len(c.Names))
+	for fr, to := range g1 {
+		g[fr] = append(append([]graph.NI{}, to...), g[fr]...)
+	}
+	return g, c
+}
+
+// MergeLabeledGraphs merges two labeled graphs read with different name
+// mappings.
+//
+// Labels are unchanged.  See MergeGraphs.
+func MergeLabeledGraphs(g1 graph.LabeledAdjacencyList, s1 NameSpace, g2 graph.LabeledAdjacencyList, s2 NameSpace) (graph.LabeledAdjacencyList, NameSpace) {
+	c, r2 := s1.Merge(s2)
+	g := TranslateLabeledGraph(g2, r2, len(c.Names))
+	for fr, to := range g1 {
+		g[fr] = append(append([]graph.Half{}, to...), g[fr]...)
+	}
+	return g, c
+}
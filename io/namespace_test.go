@@ -0,0 +1,143 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io_test
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/io"
+)
+
+func ExampleMergeGraphs() {
+	t := io.Text{MapNames: true, FrDelim: ": "}
+	g1, n1, m1, _ := t.ReadAdjacencyList(strings.NewReader(`
+a: b c
+b: c`))
+	g2, n2, m2, _ := t.ReadAdjacencyList(strings.NewReader(`
+c: d
+d: a`))
+	g, s := io.MergeGraphs(g1, io.NameSpace{Names: n1, NI: m1},
+		g2, io.NameSpace{Names: n2, NI: m2})
+	fmt.Println(s.Names)
+	t.NodeName = func(n graph.NI) string { return s.Names[n] }
+	t.WriteAdjacencyList(g, os.Stdout)
+	// Output:
+	// [a b c d]
+	// a: b c
+	// b: c
+	// c: d
+	// d: a
+}
+
+func ExampleNameSpace_Merge() {
+	s1 := io.NameSpace{Names: []string{"Ann", "Bob"}, Key: strings.ToLower}
+	s2 := io.NameSpace{Names: []string{"cal", "ANN"}}
+	c, remap := s1.Merge(s2)
+	fmt.Println(c.Names)
+	fmt.Println(remap)
+	fmt.Println(c.NI["ANN"], c.NI["Ann"])
+	// Output:
+	// [Ann Bob cal]
+	// [2 0]
+	// 0 0
+}
+
+func TestMergeGraphs(t *testing.T) {
+	// read two graphs with overlapping and disjoint names and check that
+	// arcs land on the right combined nodes.
+	tx := io.Text{MapNames: true, FrDelim: ":"}
+	g1, n1, m1, err := tx.ReadAdjacencyList(strings.NewReader(`
+x: y
+y: z x
+w:`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2, n2, m2, err := tx.ReadAdjacencyList(strings.NewReader(`
+p: z
+z: x q
+y: x`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	arcs := func(g graph.AdjacencyList, names []string) (a []string) {
+		for fr, to := range g {
+			for _, to := range to {
+				a = append(a, names[fr]+">"+names[to])
+			}
+		}
+		sort.Strings(a)
+		return
+	}
+	want := append(arcs(g1, n1), arcs(g2, n2)...)
+	sort.Strings(want)
+	g, s := io.MergeGraphs(g1, io.NameSpace{Names: n1, NI: m1},
+		g2, io.NameSpace{Names: n2, NI: m2})
+	if got := arcs(g, s.Names); !reflect.DeepEqual(got, want) {
+		t.Fatal("arcs", got, "want", want)
+	}
+	if len(g) != len(s.Names) || len(s.Names) != 6 {
+		t.Fatal("order", len(g), "names", s.Names)
+	}
+	for n, name := range s.Names {
+		if s.NI[name] != graph.NI(n) {
+			t.Fatal("name", name, "NI", s.NI[name], "want", n)
+		}
+	}
+	// g1 nodes keep their NIs
+	for n, name := range n1 {
+		if s.Names[n] != name {
+			t.Fatal("g1 node", n, "renamed", s.Names[n])
+		}
+	}
+	// inputs not modified
+	if got := arcs(g1, n1); !reflect.DeepEqual(got,
+		[]string{"x>y", "y>x", "y>z"}) {
+		t.Fatal("g1 modified", got)
+	}
+}
+
+func TestMergeLabeledGraphs(t *testing.T) {
+	g1 := graph.LabeledAdjacencyList{0: {{1, 7}}}
+	s1 := io.NameSpace{Names: []string{"a", "b"}}
+	g2 := graph.LabeledAdjacencyList{0: {{1, 8}}, 1: {{2, 9}}}
+	s2 := io.NameSpace{Names: []string{"B", "c", "a"}, Key: strings.ToUpper}
+	// s1 Key is nil so "B" does not match "b"
+	g, s := io.MergeLabeledGraphs(g1, s1, g2, s2)
+	want := graph.LabeledAdjacencyList{
+		0: {{1, 7}},
+		2: {{3, 8}},
+		3: {{0, 9}},
+	}
+	want = append(want, nil)[:4]
+	if !reflect.DeepEqual(g, want) {
+		t.Fatal(g, s.Names)
+	}
+	s1.Key = strings.ToUpper
+	g, s = io.MergeLabeledGraphs(g1, s1, g2, s2)
+	want = graph.LabeledAdjacencyList{
+		0: {{1, 7}},
+		1: {{2, 8}},
+		2: {{0, 9}},
+	}
+	if !reflect.DeepEqual(g, want) || !reflect.DeepEqual(s.Names,
+		[]string{"a", "b", "c"}) || s.NI["B"] != 1 {
+		t.Fatal(g, s.Names, s.NI)
+	}
+}
+
+func TestTranslateGraph(t *testing.T) {
+	g := graph.AdjacencyList{0: {1}, 1: {0, 1}}
+	got := io.TranslateGraph(g, []graph.NI{3, 1}, 2)
+	want := graph.AdjacencyList{1: {3, 1}, 3: {1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatal(got)
+	}
+}
@@ -6,83 +6,411 @@ package io
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
-	"regexp"
+	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/soniakeys/graph"
 )
 
+var liBits = reflect.TypeOf(graph.LI(0)).Bits()
+
 // ReadLabeledAdjacencyList reads text data and returns a LabeledAdjacencyList.
 //
 // Fields of the receiver Text define how the text data is interpreted.
-// See documentation of the Text struct.
+// See documentation of the Text struct.  Labels are numeric LIs, parsed in
+// the numeric base of field Base, unless read as symbolic labels as
+// described below.
+//
+// In Sparse format, a from-node is followed by a list of half arcs, each a
+// to-node and a label.  In Dense format, lines are lists of half arcs.  In
+// Arcs format each line has a from-node, a to-node, and a label.
+//
+// When MapNames is true, Open and Close strings are removed from the text
+// before half arcs are split.  As for writing, "(" and ")" are used if both
+// are blank.  Half arcs are then split on ToDelim and the
+// to-node and label of a half arc on HalfDelim, following the rules for
+// FrDelim and ToDelim described at Type Text.  When ToDelim and HalfDelim
+// are both blank, whitespace delimited fields are taken as alternating
+// to-nodes and labels.  In Arcs format, the to-node and label are split on
+// HalfDelim.
+//
+// The adjacency list may be followed by a legend of label names.  The legend
+// begins with a line consisting of LegendSep, or "== labels ==" if LegendSep
+// is blank.  Each following non-blank line has a numeric label, whitespace,
+// and a label name which is the rest of the line trimmed of whitespace.
+// Labels in the legend must be non-negative and label names must be
+// distinct.  Comments are stripped from legend lines as from other lines.
+// When MapNames and SymbolicLabels are both true, labels of the adjacency
+// list are read as label names and resolved through the legend.
 //
 // ReadLabeledAdjacencyList reads to EOF.
 //
-// On successful read, a valid AdjacencyList is returned with error = nil.
-// In addition, with Text.MapNames true, the method returns a list
-// of node names indexed by NI and the reverse mapping of NI by name.
-func (t Text) ReadLabeledAdjacencyList(r io.Reader) (g graph.LabeledAdjacencyList, err error) {
+// On successful read, a valid LabeledAdjacencyList is returned with
+// error = nil.  In addition, with Text.MapNames true, the method returns a
+// list of node names indexed by NI and the reverse mapping of NI by name.
+// If a legend is read, the method returns a list of label names indexed by
+// LI and the reverse mapping of LI by name.  Label names are empty strings
+// for LIs not in the legend.
+func (t Text) ReadLabeledAdjacencyList(r io.Reader) (
+	g graph.LabeledAdjacencyList, name []string, ni map[string]graph.NI,
+	labelName []string, labelLI map[string]graph.LI, err error) {
+	if err = t.fixBase(); err != nil {
+		return
+	}
+	br := t.newBodyReader(r)
+	lp := &labelParser{base: t.Base}
+	if t.MapNames && t.SymbolicLabels {
+		lp.sym = map[string]graph.LI{}
+	}
+	switch t.Format {
+	case Sparse:
+		g, name, ni, err = t.readLALSparse(br, lp)
+	case Dense:
+		g, name, ni, err = t.readLALDense(br)
+	case Arcs:
+		g, name, ni, err = t.readLALArcs(br, lp)
+	default:
+		err = fmt.Errorf("format %d invalid", t.Format)
+	}
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	if !br.legend {
+		if len(lp.name) > 0 {
+			return nil, nil, nil, nil, nil,
+				errors.New("symbolic labels without legend")
+		}
+		return
+	}
+	if labelName, labelLI, err = t.readLegend(br.b); err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	if err = lp.resolve(g, labelLI); err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	return
+}
+
+func (t Text) readLALSparse(r io.Reader, lp *labelParser) (
+	g graph.LabeledAdjacencyList, name []string, ni map[string]graph.NI, err error) {
+	if t.MapNames {
+		return t.readLALSparseNames(r, lp)
+	}
 	sep, err := t.sep()
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
+	}
+	b := bufio.NewReader(r)
+	for {
+		f, err := t.readSplitInts(b, sep)
+		if err != nil {
+			if err != io.EOF {
+				return nil, nil, nil, err
+			}
+			return g, nil, nil, nil
+		}
+		if len(f) == 0 {
+			continue
+		}
+		fr, err := strconv.ParseInt(f[0], t.Base, graph.NIBits)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if fr < 0 {
+			return nil, nil, nil, fmt.Errorf("invalid from: %d", fr)
+		}
+		for int(fr) >= len(g) {
+			g = append(g, nil)
+		}
+		to, err := t.parseHalfs(f[1:])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, h := range to {
+			for int(h.To) >= len(g) {
+				g = append(g, nil)
+			}
+		}
+		g[fr] = append(g[fr], to...)
+	}
+}
+
+func (t Text) readLALSparseNames(r io.Reader, lp *labelParser) (
+	g graph.LabeledAdjacencyList, name []string, ni map[string]graph.NI, err error) {
+	if t.Open == "" && t.Close == "" {
+		t.Open, t.Close = "(", ")"
+	}
+	ni = map[string]graph.NI{}
+	getNI := func(s string) graph.NI {
+		n, ok := ni[s]
+		if !ok {
+			n = graph.NI(len(g))
+			g = append(g, nil)
+			name = append(name, s)
+			ni[s] = n
+		}
+		return n
+	}
+	split := t.arcNameSplitter() // splits from-node from to-list
+	splitHalfs := t.halfNameSplitter()
+	s := ""
+	b := bufio.NewReader(r)
+	for {
+		s, err = t.readStripComment(b)
+		if err != nil {
+			if err != io.EOF {
+				return nil, nil, nil, err
+			}
+			err = nil
+			return
+		}
+		fs, ts := split(t.stripOpenClose(s))
+		if fs == "" {
+			if ts > "" {
+				return nil, nil, nil, errors.New("blank node name")
+			}
+			continue
+		}
+		fr := getNI(fs)
+		hs, err := splitHalfs(ts)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, h := range hs {
+			l, err := lp.parse(h[1])
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			g[fr] = append(g[fr], graph.Half{getNI(h[0]), l})
+		}
+	}
+}
+
+// stripOpenClose replaces Open and Close strings in s with spaces.
+func (t *Text) stripOpenClose(s string) string {
+	for _, d := range []string{t.Open, t.Close} {
+		if d = strings.TrimSpace(d); d > "" {
+			s = strings.Replace(s, d, " ", -1)
+		}
+	}
+	return s
+}
+
+// delimSplitter returns a function splitting on a delimiter d following the
+// rules for FrDelim and ToDelim at Type Text, or nil if d is blank.
+// Fields are trimmed of whitespace and empty fields are dropped.
+func delimSplitter(d string) func(string) []string {
+	if d == "" {
+		return nil
+	}
+	td := strings.TrimSpace(d)
+	if td == "" {
+		td = d
+	}
+	return func(s string) []string {
+		f := strings.Split(s, td)
+		nb := 0
+		for _, fi := range f {
+			if fi = strings.TrimSpace(fi); fi > "" {
+				f[nb] = fi
+				nb++
+			}
+		}
+		return f[:nb]
+	}
+}
+
+// halfNameSplitter returns a function that splits a to-list, with Open and
+// Close already removed, into node name and label string pairs.
+func (t *Text) halfNameSplitter() func(string) ([][2]string, error) {
+	toSplit := delimSplitter(t.ToDelim)
+	halfSplit := delimSplitter(t.HalfDelim)
+	if toSplit == nil && halfSplit == nil {
+		// alternating names and labels
+		return func(s string) ([][2]string, error) {
+			f := strings.Fields(s)
+			if len(f)%2 != 0 {
+				return nil, errors.New("odd data")
+			}
+			h := make([][2]string, len(f)/2)
+			for i := range h {
+				h[i] = [2]string{f[2*i], f[2*i+1]}
+			}
+			return h, nil
+		}
+	}
+	if toSplit == nil {
+		toSplit = strings.Fields
+	}
+	if halfSplit == nil {
+		halfSplit = strings.Fields
+	}
+	return func(s string) ([][2]string, error) {
+		f := toSplit(s)
+		h := make([][2]string, len(f))
+		for i, fi := range f {
+			p := halfSplit(fi)
+			if len(p) != 2 {
+				return nil, fmt.Errorf("invalid half arc: %q", fi)
+			}
+			h[i] = [2]string{p[0], p[1]}
+		}
+		return h, nil
+	}
+}
+
+func (t Text) readLALDense(r io.Reader) (
+	g graph.LabeledAdjacencyList, name []string, ni map[string]graph.NI, err error) {
+	if t.MapNames {
+		return nil, nil, nil,
+			fmt.Errorf("name translation not valid for dense format")
+	}
+	sep, err := t.sep()
+	if err != nil {
+		return nil, nil, nil, err
 	}
 	for b := bufio.NewReader(r); ; {
-		to, err := t.readHalf(b, sep)
+		f, err := t.readSplitInts(b, sep)
 		if err != nil {
 			if err != io.EOF {
-				return nil, err
+				return nil, nil, nil, err
 			}
-			return g, nil
+			return g, nil, nil, nil
+		}
+		to, err := t.parseHalfs(f)
+		if err != nil {
+			return nil, nil, nil, err
 		}
 		g = append(g, to)
 	}
 }
 
-// read and parse a single line of a to-list.  if the last line is missing
-// the newline, it returns the data and err == nil.  a subsequent call will
-// return io.EOF.
-func (t *Text) readHalf(r *bufio.Reader, sep *regexp.Regexp) (to []graph.Half, err error) {
-	s, err := r.ReadString('\n') // but allow last line without \n
+func (t Text) readLALArcs(r io.Reader, lp *labelParser) (
+	g graph.LabeledAdjacencyList, name []string, ni map[string]graph.NI, err error) {
+	if t.MapNames {
+		return t.readLALArcNames(r, lp)
+	}
+	sep, err := t.sep()
 	if err != nil {
-		if err != io.EOF || s == "" {
-			return
+		return nil, nil, nil, err
+	}
+	for b := bufio.NewReader(r); ; {
+		f, err := t.readSplitInts(b, sep)
+		if err != nil {
+			if err != io.EOF {
+				return nil, nil, nil, err
+			}
+			return g, nil, nil, nil
+		}
+		switch len(f) {
+		case 0:
+			continue
+		case 1, 3:
+		default:
+			return nil, nil, nil,
+				fmt.Errorf("arc must have two nodes and a label")
+		}
+		fr, err := strconv.ParseInt(f[0], t.Base, graph.NIBits)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if fr < 0 {
+			return nil, nil, nil, fmt.Errorf("invalid from: %d", fr)
+		}
+		for int(fr) >= len(g) {
+			g = append(g, nil)
+		}
+		to, err := t.parseHalfs(f[1:])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		for _, h := range to {
+			for int(h.To) >= len(g) {
+				g = append(g, nil)
+			}
+			g[fr] = append(g[fr], h)
 		}
 	}
-	if s == "\n" { // fast path for blank line
-		return
+}
+
+func (t Text) readLALArcNames(r io.Reader, lp *labelParser) (
+	g graph.LabeledAdjacencyList, name []string, ni map[string]graph.NI, err error) {
+	ni = map[string]graph.NI{}
+	getNI := func(s string) graph.NI {
+		n, ok := ni[s]
+		if !ok {
+			n = graph.NI(len(g))
+			g = append(g, nil)
+			name = append(name, s)
+			ni[s] = n
+		}
+		return n
 	}
-	// s is non-empty at this point
-	f := sep.Split(s, -1)
-	// some minimal possibilities:
-	// single sep: "\n" -> ["", ""], the empty strings before and after sep
-	// single non-sep: "0" -> ["0"], a non-empty string
-	if f[0] == "" {
-		f = f[1:] // toss "" before leading any separator
+	split := t.arcNameSplitter()
+	halfSplit := delimSplitter(t.HalfDelim)
+	if halfSplit == nil {
+		halfSplit = strings.Fields
 	}
-	last := len(f) - 1
-	if f[last] == "" {
-		f = f[:last] // toss "" after trailing separator, usually the \n
+	b := bufio.NewReader(r)
+	s := ""
+	for {
+		s, err = t.readStripComment(b)
+		if err != nil {
+			if err != io.EOF {
+				return nil, nil, nil, err
+			}
+			err = nil
+			return
+		}
+		fs, ts := split(s)
+		if fs == "" {
+			if len(ts) > 0 {
+				return nil, nil, nil, errors.New("blank from-node")
+			}
+			continue
+		}
+		fr := getNI(fs)
+		if ts == "" {
+			continue
+		}
+		h := halfSplit(ts)
+		if len(h) != 2 {
+			return nil, nil, nil, fmt.Errorf("invalid half arc: %q", ts)
+		}
+		l, err := lp.parse(h[1])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		g[fr] = append(g[fr], graph.Half{getNI(h[0]), l})
 	}
+}
+
+// parse a slice of strings as alternating NIs and LIs.
+func (t *Text) parseHalfs(f []string) (to []graph.Half, err error) {
 	if len(f)%2 != 0 {
-		return nil, fmt.Errorf("odd data")
+		return nil, errors.New("odd data")
+	}
+	if len(f) == 0 {
+		return nil, nil
 	}
 	to = make([]graph.Half, len(f)/2)
-	y := 0
 	for x := range to {
-		ni, err := strconv.ParseInt(f[y], t.Base, graph.NIBits)
+		n, err := strconv.ParseInt(f[2*x], t.Base, graph.NIBits)
 		if err != nil {
 			return nil, err
 		}
-		y++
-		li, err := strconv.ParseInt(f[y], t.Base, graph.NIBits)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid to: %d", n)
+		}
+		l, err := strconv.ParseInt(f[2*x+1], t.Base, liBits)
 		if err != nil {
 			return nil, err
 		}
-		y++
-		to[x] = graph.Half{graph.NI(ni), graph.LI(li)}
+		to[x] = graph.Half{graph.NI(n), graph.LI(l)}
 	}
 	return to, nil
 }
@@ -27,104 +27,169 @@ import (
 // On successful read, a valid AdjacencyList is returned with error = nil.
 // In addition, with Text.MapNames true, the method returns a list
 // of node names indexed by NI and the reverse mapping of NI by name.
+//
+// ReadAdjacencyList is implemented with ReadArcs and so parses text data
+// identically.
 func (t Text) ReadAdjacencyList(r io.Reader) (
 	graph.AdjacencyList, []string, map[string]graph.NI, error) {
+	var g graph.AdjacencyList
+	grow := func(n graph.NI) {
+		for int(n) >= len(g) {
+			g = append(g, nil)
+		}
+	}
+	// With numeric Sparse and Dense formats, order is determined by
+	// from-nodes.  Otherwise to-nodes are nodes of the graph as well.
+	growTo := t.MapNames || t.Format == Arcs || t.Format == CSV
+	name, ni, err := t.ReadArcs(r, func(fr, to graph.NI, _ graph.LI) error {
+		grow(fr)
+		if to >= 0 {
+			if growTo {
+				grow(to)
+			}
+			g[fr] = append(g[fr], to)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return g, name, ni, nil
+}
+
+// ReadArcs reads text data and calls f for each arc, without building a
+// graph.
+//
+// Text data is parsed as by ReadAdjacencyList, following the Format,
+// MapNames, Comment, and other fields of the receiver Text.  Arcs are passed
+// to f in the order they are read, with label 0.  A from-node listed with no
+// to-nodes, or in Dense format a blank line, is passed to f with to = -1.
+// Nodes thus reach f either as from-nodes or to-nodes of arcs, or with
+// to = -1, so that f sees every node of the graph ReadAdjacencyList would
+// return.
+//
+// ReadArcs reads to EOF unless f returns a non-nil error, in which case
+// ReadArcs stops and returns the error.  With Text.MapNames true, ReadArcs
+// returns a list of node names indexed by NI and the reverse mapping of NI
+// by name.
+func (t Text) ReadArcs(r io.Reader, f func(fr, to graph.NI, label graph.LI) error) (
+	name []string, ni map[string]graph.NI, err error) {
+	arc := func(fr, to graph.NI) error { return f(fr, to, 0) }
 	switch t.Format {
 	case Sparse:
-		return t.readALSparse(r)
+		if t.MapNames {
+			return t.readArcsSparseNames(r, arc)
+		}
+		return nil, nil, t.readArcsSparse(r, arc)
 	case Dense:
-		return t.readALDense(r)
+		return nil, nil, t.readArcsDense(r, arc)
 	case Arcs:
-		return t.readALArcs(r)
+		if t.MapNames {
+			return t.readArcsArcNames(r, arc)
+		}
+		return nil, nil, t.readArcsArcs(r, arc)
+	case CSV:
+		return t.readCSV(r, false, func(fr, to graph.NI, _ float64) error {
+			return arc(fr, to)
+		})
 	}
-	return nil, nil, nil, fmt.Errorf("format %d invalid", t.Format)
+	return nil, nil, fmt.Errorf("format %d invalid", t.Format)
 }
 
-func (t Text) readALSparse(r io.Reader) (
-	g graph.AdjacencyList, name []string, ni map[string]graph.NI, err error) {
-	if t.MapNames {
-		return t.readALSparseNames(r)
+// arcNIs parses strings of NIs, returning an error for negative NIs.
+func (t *Text) arcNIs(f []string) ([]graph.NI, error) {
+	a := parseNIs(f, t.Base)
+	for _, n := range a {
+		if n < 0 {
+			return nil, fmt.Errorf("invalid node: %d", n)
+		}
+	}
+	return a, nil
+}
+
+// nameMap returns a function returning the NI of a node name, assigning
+// NIs in order of first appearance.
+func nameMap() (name *[]string, ni map[string]graph.NI, getNI func(string) graph.NI) {
+	name = new([]string)
+	ni = map[string]graph.NI{}
+	return name, ni, func(s string) graph.NI {
+		n, ok := ni[s]
+		if !ok {
+			n = graph.NI(len(*name))
+			*name = append(*name, s)
+			ni[s] = n
+		}
+		return n
 	}
+}
+
+func (t Text) readArcsSparse(r io.Reader, arc func(fr, to graph.NI) error) error {
 	sep, err := t.sep()
 	if err != nil {
-		return nil, nil, nil, err
+		return err
 	}
 	b := bufio.NewReader(r)
 	for {
 		f, err := t.readSplitInts(b, sep)
 		if err != nil {
 			if err != io.EOF {
-				return nil, nil, nil, err
+				return err
 			}
-			return g, nil, nil, nil
+			return nil
 		}
 		if len(f) == 0 {
 			continue
 		}
-		fr, err := strconv.ParseInt(f[0], t.Base, graph.NIBits)
+		a, err := t.arcNIs(f)
 		if err != nil {
-			panic(fmt.Sprintf("in readALSparse: %v", err))
-		}
-		for int(fr) >= len(g) {
-			g = append(g, nil)
+			return err
 		}
-		if len(f) == 1 {
-			continue // from-NI with no to-list is allowed.
+		fr := a[0]
+		if len(a) == 1 {
+			// from-NI with no to-list is allowed.
+			if err = arc(fr, -1); err != nil {
+				return err
+			}
+			continue
 		}
-		to := parseNIs(f[1:], t.Base)
-		if g[fr] == nil {
-			g[fr] = to
-		} else {
-			g[fr] = append(g[fr], to...)
+		for _, to := range a[1:] {
+			if err = arc(fr, to); err != nil {
+				return err
+			}
 		}
 	}
 }
 
-func (t Text) readALSparseNames(r io.Reader) (
-	g graph.AdjacencyList, name []string, ni map[string]graph.NI, err error) {
-	ni = map[string]graph.NI{}
-	getNI := func(s string) graph.NI {
-		n, ok := ni[s]
-		if !ok {
-			n = graph.NI(len(g))
-			g = append(g, nil)
-			name = append(name, s)
-			ni[s] = n
-		}
-		return n
-	}
+func (t Text) readArcsSparseNames(r io.Reader, arc func(fr, to graph.NI) error) (
+	name []string, ni map[string]graph.NI, err error) {
+	np, ni, getNI := nameMap()
 	split := t.sparseNameSplitter()
-	s := ""
 	b := bufio.NewReader(r)
-	for line := 1; ; line++ {
-		s, err = t.readStripComment(b)
+	for {
+		s, err := t.readStripComment(b)
 		if err != nil {
 			if err != io.EOF {
-				return nil, nil, nil, err
+				return nil, nil, err
 			}
-			err = nil
-			return
+			return *np, ni, nil
 		}
 		fs, ts := split(s)
 		if fs == "" {
 			if len(ts) > 0 {
-				return nil, nil, nil, errors.New("blank node name")
+				return nil, nil, errors.New("blank node name")
 			}
 			continue
 		}
 		fr := getNI(fs)
 		if len(ts) == 0 {
+			if err = arc(fr, -1); err != nil {
+				return nil, nil, err
+			}
 			continue
 		}
-		if g[fr] == nil {
-			to := make([]graph.NI, len(ts))
-			for i, s := range ts {
-				to[i] = getNI(s)
-			}
-			g[fr] = to
-		} else {
-			for _, s := range ts {
-				g[fr] = append(g[fr], getNI(s))
+		for _, s := range ts {
+			if err = arc(fr, getNI(s)); err != nil {
+				return nil, nil, err
 			}
 		}
 	}
@@ -185,25 +250,37 @@ func (t *Text) sparseNameSplitter() func(string) (string, []string) {
 	}
 }
 
-func (t Text) readALDense(r io.Reader) (
-	g graph.AdjacencyList, name []string, ni map[string]graph.NI, err error) {
+func (t Text) readArcsDense(r io.Reader, arc func(fr, to graph.NI) error) error {
 	if t.MapNames {
-		return nil, nil, nil,
-			fmt.Errorf("name translation not valid for dense format")
+		return fmt.Errorf("name translation not valid for dense format")
 	}
 	sep, err := t.sep()
 	if err != nil {
-		return nil, nil, nil, err
+		return err
 	}
-	for b := bufio.NewReader(r); ; {
+	b := bufio.NewReader(r)
+	for fr := graph.NI(0); ; fr++ {
 		f, err := t.readSplitInts(b, sep)
 		if err != nil {
 			if err != io.EOF {
-				return nil, nil, nil, err
+				return err
+			}
+			return nil
+		}
+		to, err := t.arcNIs(f)
+		if err != nil {
+			return err
+		}
+		if len(to) == 0 {
+			if err = arc(fr, -1); err != nil {
+				return err
+			}
+		}
+		for _, to := range to {
+			if err = arc(fr, to); err != nil {
+				return err
 			}
-			return g, nil, nil, nil
 		}
-		g = append(g, parseNIs(f, t.Base))
 	}
 }
 
@@ -311,89 +388,66 @@ func (t *Text) readStripComment(r *bufio.Reader) (s string, err error) {
 	return s, nil
 }
 
-func (t Text) readALArcs(r io.Reader) (
-	g graph.AdjacencyList, name []string, ni map[string]graph.NI, err error) {
-	if t.MapNames {
-		return t.readALArcNames(r)
-	}
+func (t Text) readArcsArcs(r io.Reader, arc func(fr, to graph.NI) error) error {
 	sep, err := t.sep()
 	if err != nil {
-		return nil, nil, nil, err
+		return err
 	}
-	var max graph.NI
-	e := map[int][]graph.NI{} // full graph with to-lists as multisets.
 	for b := bufio.NewReader(r); ; {
 		f, err := t.readSplitInts(b, sep)
 		if err != nil {
 			if err != io.EOF {
-				return nil, nil, nil, err
+				return err
 			}
-			// normal return
-			g = make(graph.AdjacencyList, max+1)
-			for fr := range g {
-				g[fr] = e[fr]
-			}
-			return g, nil, nil, nil
+			return nil
 		}
 		if len(f) == 0 {
 			continue
 		}
 		if len(f) > 2 {
-			return nil, nil, nil, fmt.Errorf("Arc can only have two nodes")
+			return fmt.Errorf("Arc can only have two nodes")
 		}
-		a := parseNIs(f, t.Base)
-		fr := a[0]
-		if fr < 0 {
-			return nil, nil, nil, fmt.Errorf("invalid from: %d", fr)
+		a, err := t.arcNIs(f)
+		if err != nil {
+			return err
 		}
-		if fr > max {
-			max = fr
+		to := graph.NI(-1)
+		if len(a) == 2 {
+			to = a[1]
 		}
-		if len(f) == 2 {
-			to := a[1]
-			if to > max {
-				max = to
-			}
-			e[int(fr)] = append(e[int(fr)], graph.NI(to))
+		if err = arc(a[0], to); err != nil {
+			return err
 		}
 	}
 }
 
-func (t Text) readALArcNames(r io.Reader) (
-	g graph.AdjacencyList, name []string, ni map[string]graph.NI, err error) {
-	ni = map[string]graph.NI{}
-	getNI := func(s string) graph.NI {
-		n, ok := ni[s]
-		if !ok {
-			n = graph.NI(len(g))
-			g = append(g, nil)
-			name = append(name, s)
-			ni[s] = n
-		}
-		return n
-	}
+func (t Text) readArcsArcNames(r io.Reader, arc func(fr, to graph.NI) error) (
+	name []string, ni map[string]graph.NI, err error) {
+	np, ni, getNI := nameMap()
 	split := t.arcNameSplitter()
 	b := bufio.NewReader(r)
-	s := ""
-	for line := 1; ; line++ {
-		s, err = t.readStripComment(b)
+	for {
+		s, err := t.readStripComment(b)
 		if err != nil {
 			if err != io.EOF {
-				return nil, nil, nil, err
+				return nil, nil, err
 			}
-			err = nil
-			return
+			return *np, ni, nil
 		}
 		fs, ts := split(s)
 		if fs == "" {
 			if len(ts) > 0 {
-				return nil, nil, nil, errors.New("blank from-node")
+				return nil, nil, errors.New("blank from-node")
 			}
 			continue
 		}
 		fr := getNI(fs)
+		to := graph.NI(-1)
 		if len(ts) > 0 {
-			g[fr] = append(g[fr], getNI(ts))
+			to = getNI(ts)
+		}
+		if err = arc(fr, to); err != nil {
+			return nil, nil, err
 		}
 	}
 }
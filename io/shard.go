@@ -0,0 +1,99 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package io
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/soniakeys/graph"
+)
+
+// ReadAdjacencyListShards reads text data from multiple readers and returns
+// a single AdjacencyList.
+//
+// The result is the same as that of ReadAdjacencyList reading the
+// concatenation of the shards in order, given that each shard ends with a
+// newline.  With Text.MapNames true, node names may appear in any number of
+// shards and NIs are assigned in order of first appearance over all shards.
+// With Format Dense, shards are taken as consecutive ranges of nodes.
+//
+// Shards are parsed concurrently by the given number of worker goroutines,
+// each shard into a partial graph with its own name mapping.  Partial graphs
+// are then merged in shard order, so the result does not depend on the
+// number of workers or their scheduling.
+//
+// Each reader is read to EOF.  If any shard fails to parse, the error of the
+// first such shard is returned, identifying the shard by index.
+func (t Text) ReadAdjacencyListShards(readers []io.Reader, workers int) (
+	graph.AdjacencyList, []string, map[string]graph.NI, error) {
+	type part struct {
+		g    graph.AdjacencyList
+		name []string
+		err  error
+	}
+	parts := make([]part, len(readers))
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(readers); i += workers {
+				p := &parts[i]
+				p.g, p.name, _, p.err = t.ReadAdjacencyList(readers[i])
+			}
+		}(w)
+	}
+	wg.Wait()
+	for i, p := range parts {
+		if p.err != nil {
+			return nil, nil, nil, fmt.Errorf("shard %d: %v", i, p.err)
+		}
+	}
+	var g graph.AdjacencyList
+	switch {
+	case t.MapNames:
+		var name []string
+		ni := map[string]graph.NI{}
+		for _, p := range parts {
+			// remap shard NIs to merged NIs
+			m := make([]graph.NI, len(p.name))
+			for i, s := range p.name {
+				n, ok := ni[s]
+				if !ok {
+					n = graph.NI(len(name))
+					name = append(name, s)
+					ni[s] = n
+					g = append(g, nil)
+				}
+				m[i] = n
+			}
+			for fr, to := range p.g {
+				mf := m[fr]
+				for _, to := range to {
+					g[mf] = append(g[mf], m[to])
+				}
+			}
+		}
+		return g, name, ni, nil
+	case t.Format == Dense:
+		for _, p := range parts {
+			g = append(g, p.g...)
+		}
+	default:
+		for _, p := range parts {
+			for len(g) < len(p.g) {
+				g = append(g, nil)
+			}
+			for fr, to := range p.g {
+				g[fr] = append(g[fr], to...)
+			}
+		}
+	}
+	return g, nil, nil, nil
+}
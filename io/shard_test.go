@@ -0,0 +1,151 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package io_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	stdio "io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/io"
+)
+
+// bzip2 compression of "a b c\nb c\n".  The standard library has no bzip2
+// writer.
+var bz2ABC = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x64, 0xc8,
+	0xfb, 0x7f, 0x00, 0x00, 0x02, 0xd1, 0x00, 0x00, 0x10, 0x40, 0x00, 0x38,
+	0x00, 0x20, 0x00, 0x30, 0xcd, 0x00, 0x92, 0x66, 0xa2, 0x64, 0xc3, 0x3c,
+	0x5d, 0xc9, 0x14, 0xe1, 0x42, 0x41, 0x93, 0x23, 0xed, 0xfc,
+}
+
+func TestOpenReader(t *testing.T) {
+	dir := t.TempDir()
+	const text = "a b c\nb c\n"
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(text))
+	zw.Close()
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{"plain", []byte(text)},
+		{"gz", gz.Bytes()},
+		{"bz2", bz2ABC},
+	} {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, f.data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		r, err := io.OpenReader(path)
+		if err != nil {
+			t.Fatal(f.name, err)
+		}
+		got, err := stdio.ReadAll(r)
+		if err != nil {
+			t.Fatal(f.name, err)
+		}
+		if err := r.Close(); err != nil {
+			t.Fatal(f.name, err)
+		}
+		if string(got) != text {
+			t.Fatalf("%s: got %q", f.name, got)
+		}
+	}
+	// short file is not compressed
+	path := filepath.Join(dir, "short")
+	os.WriteFile(path, []byte{0x1f}, 0644)
+	r, err := io.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := stdio.ReadAll(r); !bytes.Equal(got, []byte{0x1f}) {
+		t.Fatalf("short: got %q", got)
+	}
+	r.Close()
+	if _, err := io.OpenReader(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("expected error opening missing file")
+	}
+}
+
+func TestReadAdjacencyListShards(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	// names overlap across shards
+	names := strings.Fields("ant bee cat dog eel fox gnu hen ibis jay")
+	var shards []string
+	for i := 0; i < 7; i++ {
+		var b strings.Builder
+		for l := rnd.Intn(6); l >= 0; l-- {
+			b.WriteString(names[rnd.Intn(len(names))])
+			for k := rnd.Intn(4); k > 0; k-- {
+				b.WriteString(" " + names[rnd.Intn(len(names))])
+			}
+			b.WriteString("\n")
+		}
+		shards = append(shards, b.String())
+	}
+	tx := io.Text{MapNames: true}
+	testShards(t, tx, shards)
+	tx.Format = io.Arcs
+	testShards(t, tx, shards[:1]) // arcs need exactly two fields per line
+	// numeric, all formats
+	g := graph.GnmDirected(40, 150, rnd).AdjacencyList
+	for _, f := range []io.Format{io.Sparse, io.Dense, io.Arcs} {
+		tx := io.Text{Format: f}
+		var b bytes.Buffer
+		if _, err := tx.WriteAdjacencyList(g, &b); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.SplitAfter(b.String(), "\n")
+		var shards []string
+		for i := 0; i < len(lines); i += 9 {
+			j := i + 9
+			if j > len(lines) {
+				j = len(lines)
+			}
+			shards = append(shards, strings.Join(lines[i:j], ""))
+		}
+		testShards(t, tx, shards)
+	}
+	// errors identify the shard
+	tx = io.Text{MapNames: true, Format: io.Dense}
+	_, _, _, err := tx.ReadAdjacencyListShards(
+		[]stdio.Reader{strings.NewReader("a b\n")}, 2)
+	if err == nil || !strings.HasPrefix(err.Error(), "shard 0:") {
+		t.Fatal("expected shard 0 error, got", err)
+	}
+}
+
+func testShards(t *testing.T, tx io.Text, shards []string) {
+	t.Helper()
+	g0, n0, m0, err := tx.ReadAdjacencyList(
+		strings.NewReader(strings.Join(shards, "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, workers := range []int{1, 3, 16} {
+		rs := make([]stdio.Reader, len(shards))
+		for i, s := range shards {
+			rs[i] = strings.NewReader(s)
+		}
+		g, n, m, err := tx.ReadAdjacencyListShards(rs, workers)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(g, g0) || !reflect.DeepEqual(n, n0) ||
+			!reflect.DeepEqual(m, m0) {
+			t.Fatal(fmt.Sprintf("format %d, workers %d:\n%v %v\nwant\n%v %v",
+				tx.Format, workers, g, n, g0, n0))
+		}
+	}
+}
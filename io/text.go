@@ -48,6 +48,13 @@ const (
 	// Arc format is not actually adjacency list, but arc list or edge list.
 	// There are exactly two nodes per line, a from-node and a to-node.
 	Arcs
+
+	// CSV is an arc list in comma separated values, as read and written by
+	// package encoding/csv.  Each record has a from-node and a to-node, and
+	// for weighted adjacency lists a weight.  Quoted fields may contain the
+	// delimiter.  A record with an empty to-node adds the from-node without
+	// an arc.  See fields Comma, Header, and FromColumn of type Text.
+	CSV
 )
 
 // Type Text defines options for reading and writing simple text formats.
@@ -87,11 +94,13 @@ type Text struct {
 
 	// HalfDelim separates the NI and LI of a half arc in a labeled adjacency
 	// list.  WriteLabeledAdjacencyList writes " " if HalfDelim is blank.
+	// For read behavior see ReadLabeledAdjacencyList.
 	HalfDelim string
 
 	// Open and Close surround the NI LI pair of a half arc in a labeled
-	// adjacency list.  WriteLabeledAdjacenyList writes "(" and ")" if both
-	// are blank.
+	// adjacency list, Sparse format.  WriteLabeledAdjacencyList writes "("
+	// and ")" if both are blank.  For read behavior see
+	// ReadLabeledAdjacencyList.
 	Open, Close string
 
 	// Base is the numeric base for NIs and LIs.  Methods pass this to strconv
@@ -109,9 +118,46 @@ type Text struct {
 	// NIs.
 	NodeName func(graph.NI) string
 
+	// A non-nil LabelName is used by WriteLabeledAdjacencyList to write
+	// a legend of label names following the adjacency list.  See
+	// WriteLabeledAdjacencyList.
+	LabelName func(graph.LI) string
+
+	// SymbolicLabels true means that labels of a labeled adjacency list are
+	// written as names given by LabelName, and read as names to be resolved
+	// through the legend.  See ReadLabeledAdjacencyList.
+	SymbolicLabels bool
+
+	// LegendSep is the line separating a labeled adjacency list from a
+	// legend of label names.  Methods use "== labels ==" if LegendSep is
+	// blank.
+	LegendSep string
+
+	// Precision is the number of significant digits for writing weights
+	// with WriteWeightedAdjacencyList.  Zero means to write the minimum
+	// number of digits that read back as the exact value.
+	Precision int
+
 	// WriteArcs can specify to write only a single arc of an undirected
 	// graph.  See definition of ArcDir.
 	WriteArcs ArcDir
+
+	// Comma is the field delimiter for Format CSV.  Methods use ',' if
+	// Comma is 0.
+	Comma rune
+
+	// Header true means that CSV data starts with a header record of
+	// column names.  Write methods write a header with names given by
+	// FromColumn, ToColumn, and WeightColumn, or "from", "to", and "weight"
+	// for those that are blank.
+	Header bool
+
+	// FromColumn, ToColumn, and WeightColumn name the columns of CSV data
+	// holding from-nodes, to-nodes, and weights.  Read methods find the
+	// named columns in the header, and other columns are ignored.  Blank
+	// names, or any names when Header is false, select the first, second,
+	// and third columns respectively.
+	FromColumn, ToColumn, WeightColumn string
 }
 
 // NewText is a small convenience constructor.
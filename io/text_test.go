@@ -6,6 +6,8 @@ package io_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -253,6 +255,14 @@ func TestReadALSparse(t *testing.T) {
 		}
 		t.Fail()
 	}
+	// order is determined by from-nodes, not to-nodes
+	got, _, _, err = io.Text{}.ReadAdjacencyList(bytes.NewBufferString("0 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatal("order", len(got), "want 1")
+	}
 }
 
 func TestReadALDense(t *testing.T) {
@@ -348,6 +358,78 @@ func TestReadAdjacencyList(t *testing.T) {
 	}
 }
 
+func TestReadArcs(t *testing.T) {
+	g := graph.AdjacencyList{
+		0: {2, 1, 1},
+		2: {1, 4},
+		4: nil,
+		5: nil,
+	}
+	for _, tx := range []io.Text{
+		{},
+		{Format: io.Arcs},
+		{Format: io.Dense},
+		{Format: io.CSV},
+		{MapNames: true, NodeName: func(n graph.NI) string {
+			return string(rune('a' + n))
+		}},
+	} {
+		var b bytes.Buffer
+		if _, err := tx.WriteAdjacencyList(g, &b); err != nil {
+			t.Fatal(err)
+		}
+		text := b.String()
+		// ReadArcs sees each arc of ReadAdjacencyList, in order
+		want, _, _, err := tx.ReadAdjacencyList(bytes.NewBufferString(text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := graph.AdjacencyList{}
+		name, _, err := tx.ReadArcs(bytes.NewBufferString(text),
+			func(fr, to graph.NI, l graph.LI) error {
+				if l != 0 {
+					t.Fatal("label", l)
+				}
+				for int(fr) >= len(got) || int(to) >= len(got) {
+					got = append(got, nil)
+				}
+				if to >= 0 {
+					got[fr] = append(got[fr], to)
+				}
+				return nil
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.MapNames && len(name) != len(want) {
+			t.Fatalf("%d names, order %d", len(name), len(want))
+		}
+		if !got.Equal(want) {
+			t.Fatalf("format %d: ReadArcs %v, ReadAdjacencyList %v",
+				tx.Format, got, want)
+		}
+	}
+	// callback error stops the read
+	stop := errors.New("stop")
+	n := 0
+	_, _, err := io.Text{}.ReadArcs(bytes.NewBufferString("0: 1 2 3\n1: 2\n"),
+		func(fr, to graph.NI, _ graph.LI) error {
+			n++
+			if n == 2 {
+				return stop
+			}
+			return nil
+		})
+	if err != stop || n != 2 {
+		t.Fatal(err, n)
+	}
+	// negative NIs
+	if _, _, err = (io.Text{}).ReadArcs(bytes.NewBufferString("0: -1\n"),
+		func(graph.NI, graph.NI, graph.LI) error { return nil }); err == nil {
+		t.Fatal("ReadArcs allowed negative NI")
+	}
+}
+
 func TestWriteAdjacencyList(t *testing.T) {
 	// test bad format
 	_, err := io.Text{Format: -1}.WriteAdjacencyList(nil, nil)
@@ -455,3 +537,378 @@ func TestWriteALDenseTriangle(t *testing.T) {
 		t.Fatalf("got %q", got)
 	}
 }
+
+func TestLabeledRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	same := func(a, b graph.LabeledAdjacencyList) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for fr, to := range a {
+			if len(to) != len(b[fr]) {
+				return false
+			}
+			for x, h := range to {
+				if b[fr][x] != h {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	for i := 0; i < 50; i++ {
+		n := 1 + rnd.Intn(10)
+		g := make(graph.LabeledAdjacencyList, n)
+		for j := rnd.Intn(3 * n); j > 0; j-- {
+			fr := rnd.Intn(n)
+			g[fr] = append(g[fr], graph.Half{
+				To:    graph.NI(rnd.Intn(n)),
+				Label: graph.LI(rnd.Intn(2000) - 1000)})
+		}
+		for _, tx := range []io.Text{
+			{},
+			{Format: io.Dense},
+			{Format: io.Arcs},
+			{Base: 16, FrDelim: " => ", ToDelim: ", ", Open: "[", Close: "]"},
+			{Format: io.Arcs, Base: 3, HalfDelim: ","},
+		} {
+			var b bytes.Buffer
+			if _, err := tx.WriteLabeledAdjacencyList(g, &b); err != nil {
+				t.Fatal(err)
+			}
+			s := b.String()
+			r, _, _, _, _, err := tx.ReadLabeledAdjacencyList(&b)
+			if err != nil {
+				t.Fatal(tx, s, err)
+			}
+			if !same(g, r) {
+				t.Fatalf("%+v\n%v\n%s\n%v", tx, g, s, r)
+			}
+		}
+		// with names, nodes are renumbered
+		name := func(n graph.NI) string { return fmt.Sprint("node", n) }
+		for _, tx := range []io.Text{
+			{FrDelim: ":"},
+			{FrDelim: "->", ToDelim: ";", HalfDelim: "/"},
+			{FrDelim: ":", ToDelim: ",", Open: "<", Close: ">"},
+			{Format: io.Arcs},
+			{Format: io.Arcs, FrDelim: ",", HalfDelim: ","},
+		} {
+			tx.NodeName = name
+			var b bytes.Buffer
+			if _, err := tx.WriteLabeledAdjacencyList(g, &b); err != nil {
+				t.Fatal(err)
+			}
+			s := b.String()
+			tx.NodeName = nil
+			tx.MapNames = true
+			r, _, m, _, _, err := tx.ReadLabeledAdjacencyList(&b)
+			if err != nil {
+				t.Fatal(tx, s, err)
+			}
+			for fr, to := range g {
+				rf, ok := m[name(graph.NI(fr))]
+				if !ok {
+					if len(to) > 0 {
+						t.Fatal("node", fr, "missing")
+					}
+					continue
+				}
+				if len(r[rf]) != len(to) {
+					t.Fatalf("%+v\n%s\nnode %d arcs %v", tx, s, fr, r[rf])
+				}
+				for x, h := range to {
+					if r[rf][x] != (graph.Half{m[name(h.To)], h.Label}) {
+						t.Fatalf("%+v\n%s\nnode %d arcs %v", tx, s, fr, r[rf])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLabeledLegendRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(11))
+	// names containing delimiters, and names that collide textually with
+	// numeric labels.
+	delim := []string{"a: (b, c)", "3", "x  y", "0", "(5 1)", "->"}
+	sym := []string{"1", "0", "x", "5", "2", "-1"}
+	name := func(n graph.NI) string { return fmt.Sprint("n", n) }
+	for i := 0; i < 30; i++ {
+		n := 1 + rnd.Intn(8)
+		g := make(graph.LabeledAdjacencyList, n)
+		for j := rnd.Intn(3 * n); j > 0; j-- {
+			fr := rnd.Intn(n)
+			g[fr] = append(g[fr], graph.Half{
+				To:    graph.NI(rnd.Intn(n)),
+				Label: graph.LI(rnd.Intn(len(delim)))})
+		}
+		for _, tc := range []struct {
+			tx     io.Text
+			labels []string
+		}{
+			{io.Text{}, delim},
+			{io.Text{Format: io.Dense}, delim},
+			{io.Text{Format: io.Arcs, LegendSep: "--"}, delim},
+			{io.Text{NodeName: name, FrDelim: ":", ToDelim: ","}, delim},
+			{io.Text{NodeName: name, Format: io.Arcs}, delim},
+			{io.Text{NodeName: name, SymbolicLabels: true, FrDelim: ":"}, sym},
+			{io.Text{NodeName: name, SymbolicLabels: true, Base: 16,
+				FrDelim: "->", ToDelim: ";", HalfDelim: "/"}, sym},
+			{io.Text{NodeName: name, SymbolicLabels: true, Format: io.Arcs,
+				Comment: "#", LegendSep: "# legend"}, sym},
+		} {
+			tx := tc.tx
+			labels := tc.labels
+			tx.LabelName = func(l graph.LI) string { return labels[l] }
+			var b bytes.Buffer
+			if _, err := tx.WriteLabeledAdjacencyList(g, &b); err != nil {
+				t.Fatal(err)
+			}
+			s := b.String()
+			mapped := tx.NodeName != nil
+			tx.NodeName = nil
+			tx.LabelName = nil
+			tx.MapNames = mapped
+			r, _, m, ln, lm, err := tx.ReadLabeledAdjacencyList(&b)
+			if err != nil {
+				t.Fatalf("%+v\n%s\n%v", tx, s, err)
+			}
+			node := func(n graph.NI) graph.NI {
+				if mapped {
+					return m[name(n)]
+				}
+				return n
+			}
+			for fr, to := range g {
+				if len(to) > 0 && len(r[node(graph.NI(fr))]) != len(to) {
+					t.Fatalf("%+v\n%s\n%v", tx, s, r)
+				}
+				for x, h := range to {
+					if got := r[node(graph.NI(fr))][x]; got !=
+						(graph.Half{node(h.To), h.Label}) {
+						t.Fatalf("%+v\n%s\nnode %d arcs %v", tx, s, fr, got)
+					}
+					if ln[h.Label] != labels[h.Label] ||
+						lm[labels[h.Label]] != h.Label {
+						t.Fatalf("%+v\n%s\nlegend %q %v", tx, s, ln, lm)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLabeledLegendErrors(t *testing.T) {
+	// files without a legend return no legend
+	_, _, _, ln, lm, err := io.Text{}.ReadLabeledAdjacencyList(
+		bytes.NewBufferString("0: (1 5)\n"))
+	if err != nil || ln != nil || lm != nil {
+		t.Fatal(ln, lm, err)
+	}
+	for _, tc := range []struct {
+		tx   io.Text
+		text string
+	}{
+		{io.Text{}, "0: (1 5)\n== labels ==\n5\n"},
+		{io.Text{}, "0: (1 5)\n== labels ==\n-1 x\n"},
+		{io.Text{}, "0: (1 5)\n== labels ==\nx 5\n"},
+		{io.Text{}, "0: (1 5)\n== labels ==\n5 x\n6 x\n"},
+		{io.Text{}, "0: (1 5)\n== labels ==\n5 x\n5 y\n"},
+		{io.Text{MapNames: true, SymbolicLabels: true}, "a: (b x)\n"},
+		{io.Text{MapNames: true, SymbolicLabels: true},
+			"a: (b x)\n== labels ==\n0 y\n"},
+	} {
+		_, _, _, _, _, err := tc.tx.ReadLabeledAdjacencyList(
+			bytes.NewBufferString(tc.text))
+		if err == nil {
+			t.Fatalf("%+v %q: no error", tc.tx, tc.text)
+		}
+	}
+	g := graph.LabeledAdjacencyList{{{0, 0}, {0, 1}, {0, -1}}}
+	for _, ln := range []func(graph.LI) string{
+		func(graph.LI) string { return "x" },
+		func(l graph.LI) string { return fmt.Sprint(l) },
+		func(l graph.LI) string { return fmt.Sprint(l, "\n") + "x" },
+	} {
+		g[0] = g[0][:2]
+		if ln(0) != "x" {
+			g[0] = append(g[0], graph.Half{0, -1})
+		}
+		var b bytes.Buffer
+		_, err := io.Text{LabelName: ln}.WriteLabeledAdjacencyList(g, &b)
+		if err == nil {
+			t.Fatalf("no error, wrote %q", b.String())
+		}
+	}
+	// symbolic names that would not read back
+	g = graph.LabeledAdjacencyList{{{0, 0}}}
+	for _, tc := range []struct {
+		tx   io.Text
+		name string
+	}{
+		{io.Text{SymbolicLabels: true}, "x y"},
+		{io.Text{SymbolicLabels: true}, "x)"},
+		{io.Text{SymbolicLabels: true}, "x:"},
+		{io.Text{SymbolicLabels: true, ToDelim: ";"}, "x;y"},
+		{io.Text{SymbolicLabels: true, HalfDelim: "/"}, "x/y"},
+		{io.Text{SymbolicLabels: true, Format: io.Arcs}, "x\ty"},
+		{io.Text{Comment: "#"}, "x # y"},
+	} {
+		tx := tc.tx
+		name := tc.name
+		tx.LabelName = func(graph.LI) string { return name }
+		var b bytes.Buffer
+		if _, err := tx.WriteLabeledAdjacencyList(g, &b); err == nil {
+			t.Fatalf("%+v %q: no error, wrote %q", tc.tx, name, b.String())
+		}
+	}
+}
+
+func TestReadLabeledAdjacencyList(t *testing.T) {
+	// comments, and lines for the same from-node concatenated
+	r := bytes.NewBufferString(`
+0: (1 5)  // comment
+1:
+0: (2 -3)
+`)
+	g, _, _, _, _, err := io.NewText().ReadLabeledAdjacencyList(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := graph.LabeledAdjacencyList{
+		0: {{1, 5}, {2, -3}},
+		1: nil,
+		2: nil,
+	}
+	if !reflect.DeepEqual(g, want) {
+		t.Fatal(g)
+	}
+	for _, tc := range []struct {
+		tx   io.Text
+		text string
+	}{
+		{io.Text{Format: -1}, ""},
+		{io.Text{}, "0: 1"},
+		{io.Text{}, "-1: 1 2"},
+		{io.Text{}, "0: -1 2"},
+		{io.Text{Format: io.Dense}, "1 2 3"},
+		{io.Text{Format: io.Dense, MapNames: true}, "a b 1"},
+		{io.Text{Format: io.Arcs}, "0 1"},
+		{io.Text{MapNames: true}, "a b 1 c"},
+		{io.Text{MapNames: true}, "a b x"},
+		{io.Text{MapNames: true, FrDelim: ":"}, ":(b 1)"},
+		{io.Text{MapNames: true, ToDelim: ","}, "a (b 1), (c)"},
+		{io.Text{MapNames: true, Format: io.Arcs}, "a b"},
+		{io.Text{MapNames: true, Format: io.Arcs}, "a b 1 2"},
+		{io.Text{MapNames: true, Format: io.Arcs, FrDelim: ","}, ",b 1"},
+	} {
+		_, _, _, _, _, err := tc.tx.ReadLabeledAdjacencyList(
+			bytes.NewBufferString(tc.text))
+		if err == nil {
+			t.Fatalf("%+v %q: no error", tc.tx, tc.text)
+		}
+	}
+	tx := io.Text{MapNames: true}
+	if _, _, _, _, _, err := tx.ReadLabeledAdjacencyList(allErr{}); err == nil {
+		t.Fatal("read error not returned")
+	}
+}
+
+func TestWriteLabeledAdjacencyList(t *testing.T) {
+	// test bad format
+	_, err := io.Text{Format: -1}.WriteLabeledAdjacencyList(nil, nil)
+	if err == nil {
+		t.Fatal("WriteLabeledAdjacencyList no err from bad Format")
+	}
+	// WriteArcs
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 7)
+	g.AddEdge(graph.Edge{1, 2}, 8)
+	g.AddEdge(graph.Edge{2, 2}, 9)
+	for _, tc := range []struct {
+		tx   io.Text
+		want string
+	}{
+		{io.Text{WriteArcs: io.Upper}, "0: (1 7)\n1: (2 8)\n2: (2 9)\n"},
+		{io.Text{WriteArcs: io.Lower}, "1: (0 7)\n2: (1 8) (2 9)\n"},
+		{io.Text{Format: io.Arcs, WriteArcs: io.Upper}, "0 1 7\n1 2 8\n2 2 9\n"},
+		{io.Text{Format: io.Dense, WriteArcs: io.Lower}, "\n0 7\n1 8 2 9\n"},
+	} {
+		var b bytes.Buffer
+		n, err := tc.tx.WriteLabeledAdjacencyList(g.LabeledAdjacencyList, &b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != tc.want || n != len(tc.want) {
+			t.Fatalf("%+v: got %d %q, want %q", tc.tx, n, b.String(), tc.want)
+		}
+	}
+}
+
+func TestWeightedRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(59))
+	g := make(graph.LabeledAdjacencyList, 10)
+	var wt []float64
+	for i := 0; i < 30; i++ {
+		fr := rnd.Intn(len(g) - 1) // no arcs from last node
+		g[fr] = append(g[fr], graph.Half{
+			To: graph.NI(rnd.Intn(len(g))), Label: graph.LI(len(wt))})
+		wt = append(wt, rnd.NormFloat64()*100)
+	}
+	w := func(l graph.LI) float64 { return wt[l] }
+	for _, tx := range []io.Text{
+		{},
+		{Base: 16, FrDelim: ",", HalfDelim: ","},
+		{FrDelim: "->", HalfDelim: " "},
+	} {
+		var b bytes.Buffer
+		if _, err := tx.WriteWeightedAdjacencyList(g, w, &b); err != nil {
+			t.Fatal(err)
+		}
+		s := b.String()
+		r, rw, rwt, _, _, err := tx.ReadWeightedAdjacencyList(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r) != len(g) || len(rwt) != len(wt) {
+			t.Fatal(tx, len(r), len(rwt), "\n", s)
+		}
+		for fr, to := range g {
+			if len(r[fr]) != len(to) {
+				t.Fatal(tx, "node", fr, r[fr], to)
+			}
+			for i, h := range to {
+				if r[fr][i].To != h.To || rw(r[fr][i].Label) != w(h.Label) {
+					t.Fatal(tx, "node", fr, r[fr][i], h)
+				}
+			}
+		}
+	}
+}
+
+func TestReadWeightedAdjacencyList(t *testing.T) {
+	for _, s := range []string{
+		"0 1",      // missing weight
+		"0 1 x",    // bad weight
+		"0 -1 2.5", // negative node
+		"a 1 2.5",  // non-numeric node
+	} {
+		_, _, _, _, _, err := io.Text{}.ReadWeightedAdjacencyList(
+			bytes.NewBufferString(s))
+		if err == nil {
+			t.Fatal("no error for", s)
+		}
+	}
+	_, _, _, _, _, err := io.Text{}.ReadWeightedAdjacencyList(allErr{})
+	if err == nil {
+		t.Fatal("read error not returned")
+	}
+	// names and a node without arcs
+	g, w, wt, name, ni, err := io.Text{MapNames: true}.
+		ReadWeightedAdjacencyList(bytes.NewBufferString("x y 1\nz\n"))
+	if err != nil || len(g) != 3 || len(wt) != 1 || w(0) != 1 ||
+		!reflect.DeepEqual(name, []string{"x", "y", "z"}) || ni["z"] != 2 {
+		t.Fatal(g, wt, name, ni, err)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package io
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/soniakeys/graph"
+)
+
+// ReadWeightedAdjacencyList reads arcs with floating point weights and returns
+// a LabeledAdjacencyList with a weight table.
+//
+// Text data is read as arcs, one per line, regardless of Format.  Each line
+// has a from-node, a to-node, and a weight, split following the rules
+// for FrDelim at type Text and HalfDelim at ReadLabeledAdjacencyList.  When
+// MapNames is false, nodes are parsed as NIs in the base of field Base.
+// Weights are always parsed by strconv.ParseFloat.  A line with only a
+// from-node adds the node without an arc.  The exception is Format CSV,
+// where arcs are read as CSV records as described at CSV.
+//
+// Arc labels of the returned graph are indexes into the returned weight table
+// wt, assigned in the order arcs are read.  The returned WeightFunc w
+// returns wt[l] for label l.  With MapNames true, the method also returns
+// a list of node names indexed by NI and the reverse mapping of NI by name.
+//
+// ReadWeightedAdjacencyList reads to EOF.
+func (t Text) ReadWeightedAdjacencyList(r io.Reader) (
+	g graph.LabeledAdjacencyList, w graph.WeightFunc, wt []float64,
+	name []string, ni map[string]graph.NI, err error) {
+	if t.Format == CSV {
+		return t.readWeightedCSV(r)
+	}
+	if err = t.fixBase(); err != nil {
+		return
+	}
+	getNI := func(s string) (graph.NI, error) {
+		n, err := strconv.ParseInt(s, t.Base, graph.NIBits)
+		if err != nil {
+			return -1, err
+		}
+		if n < 0 {
+			return -1, fmt.Errorf("invalid node: %d", n)
+		}
+		for int(n) >= len(g) {
+			g = append(g, nil)
+		}
+		return graph.NI(n), nil
+	}
+	if t.MapNames {
+		ni = map[string]graph.NI{}
+		getNI = func(s string) (graph.NI, error) {
+			n, ok := ni[s]
+			if !ok {
+				n = graph.NI(len(g))
+				g = append(g, nil)
+				name = append(name, s)
+				ni[s] = n
+			}
+			return n, nil
+		}
+	}
+	split := t.arcNameSplitter()
+	halfSplit := delimSplitter(t.HalfDelim)
+	if halfSplit == nil {
+		halfSplit = strings.Fields
+	}
+	b := bufio.NewReader(r)
+	for {
+		s, err := t.readStripComment(b)
+		if err != nil {
+			if err != io.EOF {
+				return nil, nil, nil, nil, nil, err
+			}
+			break
+		}
+		fs, ts := split(s)
+		if fs == "" {
+			if ts > "" {
+				return nil, nil, nil, nil, nil, errors.New("blank from-node")
+			}
+			continue
+		}
+		fr, err := getNI(fs)
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		if ts == "" {
+			continue
+		}
+		h := halfSplit(ts)
+		if len(h) != 2 {
+			return nil, nil, nil, nil, nil,
+				fmt.Errorf("invalid weighted half arc: %q", ts)
+		}
+		to, err := getNI(h[0])
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		x, err := strconv.ParseFloat(h[1], 64)
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		g[fr] = append(g[fr], graph.Half{to, graph.LI(len(wt))})
+		wt = append(wt, x)
+	}
+	w = func(l graph.LI) float64 { return wt[l] }
+	return g, w, wt, name, ni, nil
+}
+
+// WriteWeightedAdjacencyList writes a labeled adjacency list as arcs with
+// floating point weights.
+//
+// Each arc is written on a line as a from-node, FrDelim, a to-node,
+// HalfDelim, and the weight w(label) formatted with strconv.FormatFloat,
+// format 'g' and the number of significant digits of field Precision.
+// FrDelim and HalfDelim default to " ".  Format is ignored except that
+// Format CSV writes CSV records.  NodeName and WriteArcs are observed.
+//
+// Text written by WriteWeightedAdjacencyList can be read back by
+// ReadWeightedAdjacencyList using the same Text, except that when writing
+// with NodeName, reading must be done with MapNames true.
+//
+// Returned is number of bytes written and error.
+func (t Text) WriteWeightedAdjacencyList(g graph.LabeledAdjacencyList,
+	w graph.WeightFunc, wr io.Writer) (n int, err error) {
+	if t.Format == CSV {
+		return t.writeWeightedCSV(g, w, wr)
+	}
+	if t.FrDelim == "" {
+		t.FrDelim = " "
+	}
+	writeLast := t.lalDefaults()
+	prec := t.Precision
+	if prec == 0 {
+		prec = -1
+	}
+	p := t.arcFilter()
+	b := &lalWriter{b: bufio.NewWriter(wr)}
+	last := len(g) - 1
+	for i, to := range g {
+		fr := graph.NI(i)
+		one := false
+		for _, to := range to {
+			if !p(fr, to.To) {
+				continue
+			}
+			one = true
+			b.ws(t.NodeName(fr))
+			b.ws(t.FrDelim)
+			b.ws(t.NodeName(to.To))
+			b.ws(t.HalfDelim)
+			b.ws(strconv.FormatFloat(w(to.Label), 'g', prec, 64))
+			b.ws("\n")
+		}
+		if writeLast && i == last && !one {
+			b.ws(t.NodeName(fr))
+			b.ws("\n")
+		}
+	}
+	return b.done()
+}
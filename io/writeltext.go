@@ -9,15 +9,39 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
+	"unicode"
 
 	"github.com/soniakeys/graph"
 )
 
-// WriteLabeledAdjacencyList writes a lableed adjacency list as text.
+// WriteLabeledAdjacencyList writes a labeled adjacency list as text.
 //
 // Fields of the receiver Text define how the text data is formatted.
 // See documentation of the Text struct.
 //
+// In Sparse format, half arcs are written as to-node and label separated by
+// HalfDelim and surrounded by Open and Close.  In Dense format, half arcs are
+// written as to-node and label separated by a space.  In Arcs format, each
+// line has a from-node, FrDelim, a to-node, HalfDelim, and a label.  In Arcs
+// format FrDelim defaults to " ".
+//
+// If LabelName is non-nil, a legend is written following the adjacency
+// list.  The legend is a line of LegendSep, or "== labels ==" if LegendSep is
+// blank, followed by a line for each distinct label written, in increasing
+// order, with the numeric label, a space, and the label name.  Labels must
+// be non-negative and label names must be distinct, non-blank, and must not
+// contain newlines or the Comment delimiter.  With SymbolicLabels true,
+// labels in Sparse and Arcs formats are also written as label names rather
+// than as numeric LIs.  Label names written this way must also not contain
+// whitespace or the delimiters FrDelim, ToDelim, HalfDelim, Open, or Close.
+// An error is returned for an invalid label name and nothing is written.
+//
+// Text written by WriteLabeledAdjacencyList can be read back by
+// ReadLabeledAdjacencyList using the same Text, except that when writing
+// with NodeName or with symbolic labels, reading must be done with MapNames
+// true.
+//
 // Returned is number of bytes written and error.
 func (t Text) WriteLabeledAdjacencyList(g graph.LabeledAdjacencyList,
 	w io.Writer) (n int, err error) {
@@ -32,58 +56,170 @@ func (t Text) WriteLabeledAdjacencyList(g graph.LabeledAdjacencyList,
 	return 0, fmt.Errorf("format %d invalid", t.Format)
 }
 
+// lalWriter accumulates bytes written and the first error.
+type lalWriter struct {
+	b   *bufio.Writer
+	n   int
+	err error
+}
+
+func (w *lalWriter) ws(s string) {
+	if w.err == nil {
+		var c int
+		c, w.err = w.b.WriteString(s)
+		w.n += c
+	}
+}
+
+func (w *lalWriter) done() (int, error) {
+	if w.err == nil {
+		w.err = w.b.Flush()
+	}
+	return w.n, w.err
+}
+
+// arcFilter returns a function selecting arcs according to WriteArcs.
+func (t *Text) arcFilter() func(fr, to graph.NI) bool {
+	switch t.WriteArcs {
+	case Upper:
+		return func(fr, to graph.NI) bool { return to >= fr }
+	case Lower:
+		return func(fr, to graph.NI) bool { return to <= fr }
+	}
+	return func(fr, to graph.NI) bool { return true }
+}
+
+// lalDefaults supplies defaults for writing labeled adjacency lists and
+// returns true if node numbers are written as NIs.
+func (t *Text) lalDefaults() (numeric bool) {
+	t.fixBase()
+	if t.ToDelim == "" {
+		t.ToDelim = " "
+	}
+	if t.HalfDelim == "" {
+		t.HalfDelim = " "
+	}
+	numeric = t.NodeName == nil
+	if numeric {
+		t.NodeName = func(n graph.NI) string {
+			return strconv.FormatInt(int64(n), t.Base)
+		}
+	}
+	return
+}
+
 func (t Text) writeLALSparse(g graph.LabeledAdjacencyList,
 	w io.Writer) (n int, err error) {
-	return
+	if t.FrDelim == "" {
+		t.FrDelim = ": "
+	}
+	if t.Open == "" && t.Close == "" {
+		t.Open, t.Close = "(", ")"
+	}
+	writeLast := t.lalDefaults()
+	ls, err := t.legendLabels(g)
+	if err != nil {
+		return 0, err
+	}
+	p := t.arcFilter()
+	b := &lalWriter{b: bufio.NewWriter(w)}
+	last := len(g) - 1
+	for i, to := range g {
+		fr := graph.NI(i)
+		one := false
+		for _, to := range to {
+			if !p(fr, to.To) {
+				continue
+			}
+			if one {
+				b.ws(t.ToDelim)
+			} else {
+				one = true
+				b.ws(t.NodeName(fr))
+				b.ws(t.FrDelim)
+			}
+			b.ws(t.Open)
+			b.ws(t.NodeName(to.To))
+			b.ws(t.HalfDelim)
+			b.ws(t.labelText(to.Label))
+			b.ws(t.Close)
+		}
+		if writeLast && i == last && !one {
+			one = true
+			b.ws(t.NodeName(fr))
+			b.ws(strings.TrimRightFunc(t.FrDelim, unicode.IsSpace))
+		}
+		if one {
+			b.ws("\n")
+		}
+	}
+	t.writeLegend(ls, b)
+	return b.done()
 }
+
 func (t Text) writeLALArcs(g graph.LabeledAdjacencyList,
 	w io.Writer) (n int, err error) {
-	return
+	if t.FrDelim == "" {
+		t.FrDelim = " "
+	}
+	writeLast := t.lalDefaults()
+	ls, err := t.legendLabels(g)
+	if err != nil {
+		return 0, err
+	}
+	p := t.arcFilter()
+	b := &lalWriter{b: bufio.NewWriter(w)}
+	last := len(g) - 1
+	for i, to := range g {
+		fr := graph.NI(i)
+		one := false
+		for _, to := range to {
+			if !p(fr, to.To) {
+				continue
+			}
+			one = true
+			b.ws(t.NodeName(fr))
+			b.ws(t.FrDelim)
+			b.ws(t.NodeName(to.To))
+			b.ws(t.HalfDelim)
+			b.ws(t.labelText(to.Label))
+			b.ws("\n")
+		}
+		if writeLast && i == last && !one {
+			b.ws(t.NodeName(fr))
+			b.ws("\n")
+		}
+	}
+	t.writeLegend(ls, b)
+	return b.done()
 }
+
 func (t Text) writeLALDense(g graph.LabeledAdjacencyList,
 	w io.Writer) (n int, err error) {
 	t.fixBase()
-	b := bufio.NewWriter(w)
-	var c int
-	for _, to := range g {
-		if len(to) > 0 {
-			c, err = b.WriteString(strconv.FormatInt(int64(to[0].To), t.Base))
-			n += c
-			if err != nil {
-				return
-			}
-			if err = b.WriteByte(' '); err != nil {
-				return
-			}
-			c, err = b.WriteString(strconv.FormatInt(int64(to[0].Label), t.Base))
-			n += c + 1
-			if err != nil {
-				return
+	ls, err := t.legendLabels(g)
+	if err != nil {
+		return 0, err
+	}
+	p := t.arcFilter()
+	b := &lalWriter{b: bufio.NewWriter(w)}
+	for i, to := range g {
+		fr := graph.NI(i)
+		one := false
+		for _, to := range to {
+			if !p(fr, to.To) {
+				continue
 			}
-			for _, to := range to[1:] {
-				if err = b.WriteByte(' '); err != nil {
-					return
-				}
-				c, err = b.WriteString(strconv.FormatInt(int64(to.To), t.Base))
-				n += c + 1
-				if err != nil {
-					return
-				}
-				if err = b.WriteByte(' '); err != nil {
-					return
-				}
-				c, err = b.WriteString(strconv.FormatInt(int64(to.Label), t.Base))
-				n += c + 1
-				if err != nil {
-					return
-				}
+			if one {
+				b.ws(" ")
 			}
+			one = true
+			b.ws(strconv.FormatInt(int64(to.To), t.Base))
+			b.ws(" ")
+			b.ws(strconv.FormatInt(int64(to.Label), t.Base))
 		}
-		if err = b.WriteByte('\n'); err != nil {
-			return
-		}
-		n++
+		b.ws("\n")
 	}
-	b.Flush()
-	return
+	t.writeLegend(ls, b)
+	return b.done()
 }
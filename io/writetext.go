@@ -30,6 +30,8 @@ func (t Text) WriteAdjacencyList(g graph.AdjacencyList, w io.Writer) (
 		return t.writeALDense(g, w)
 	case Arcs:
 		return t.writeALArcs(g, w)
+	case CSV:
+		return t.writeALCSV(g, w)
 	}
 	return 0, fmt.Errorf("format %d invalid", t.Format)
 }
@@ -159,28 +161,29 @@ func (t Text) writeALArcs(g graph.AdjacencyList, w io.Writer) (
 	}
 	b := bufio.NewWriter(w)
 	var c int
-	for fr, to := range g {
-		for _, to := range to {
-			c, err = b.WriteString(t.NodeName(graph.NI(fr)))
-			n += c
-			if err != nil {
-				return
-			}
-			c, err = b.WriteString(t.FrDelim)
-			n += c
-			if err != nil {
-				return
-			}
-			c, err = b.WriteString(t.NodeName(to))
-			n += c
-			if err != nil {
-				return
-			}
-			if err = b.WriteByte('\n'); err != nil {
-				return
-			}
-			n++
+	if !g.Arcs(func(fr graph.NI, _ int, to graph.NI) bool {
+		c, err = b.WriteString(t.NodeName(fr))
+		n += c
+		if err != nil {
+			return false
 		}
+		c, err = b.WriteString(t.FrDelim)
+		n += c
+		if err != nil {
+			return false
+		}
+		c, err = b.WriteString(t.NodeName(to))
+		n += c
+		if err != nil {
+			return false
+		}
+		if err = b.WriteByte('\n'); err != nil {
+			return false
+		}
+		n++
+		return true
+	}) {
+		return
 	}
 	b.Flush()
 	return
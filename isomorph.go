@@ -0,0 +1,329 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// isomorph.go -- graph isomorphism by backtracking search.
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Isomorphic tests whether undirected graphs g and h are isomorphic.
+//
+// If they are, it returns true and a mapping m where m[n] is the node of h
+// corresponding to node n of g.  Loops and parallel edges are allowed and
+// must correspond in number.
+//
+// The algorithm is a backtracking search in the manner of VF2.  Nodes of g
+// are matched in an order where each next node has as many neighbors as
+// possible already matched, and candidate nodes of h are pruned by checking
+// consistency with all matched neighbors.  Before the search, nodes of both
+// graphs are partitioned by color refinement, iteratively distinguishing
+// nodes by the multisets of colors of their neighbors, and only nodes of the
+// same color are considered as candidates.  Refinement alone cannot
+// distinguish nodes of regular graphs, for example, but the search is
+// complete and results are correct regardless.  The worst case time is
+// exponential but the search is practical for graphs of up to a few hundred
+// nodes.
+//
+// See also IsomorphicDirected and IsomorphicLabeled.
+func Isomorphic(g, h Undirected) (bool, []NI) {
+	return isomorphic(newIsoGraph(g.AdjacencyList, nil),
+		newIsoGraph(h.AdjacencyList, nil), nil)
+}
+
+// IsomorphicDirected tests whether directed graphs g and h are isomorphic.
+//
+// Arcs must correspond in direction.  See Isomorphic for more description.
+func IsomorphicDirected(g, h Directed) (bool, []NI) {
+	return isomorphic(newIsoGraph(g.AdjacencyList, nil),
+		newIsoGraph(h.AdjacencyList, nil), nil)
+}
+
+// IsomorphicLabeled tests whether labeled graphs g and h are isomorphic,
+// with corresponding arcs having compatible labels.
+//
+// Graphs may be directed or undirected.  The function compat is called with
+// a label of g and a label of h and should return true if an arc with the
+// label from g may correspond to an arc with the label from h.  Where there
+// are parallel arcs, labels must be compatible under some one-to-one
+// correspondence of the arcs.
+//
+// See Isomorphic for more description.
+func IsomorphicLabeled(g, h LabeledAdjacencyList, compat func(LI, LI) bool) (bool, []NI) {
+	ug := make(AdjacencyList, len(g))
+	lg := map[Edge][]LI{}
+	for fr, to := range g {
+		for _, to := range to {
+			ug[fr] = append(ug[fr], to.To)
+			e := Edge{NI(fr), to.To}
+			lg[e] = append(lg[e], to.Label)
+		}
+	}
+	uh := make(AdjacencyList, len(h))
+	lh := map[Edge][]LI{}
+	for fr, to := range h {
+		for _, to := range to {
+			uh[fr] = append(uh[fr], to.To)
+			e := Edge{NI(fr), to.To}
+			lh[e] = append(lh[e], to.Label)
+		}
+	}
+	return isomorphic(newIsoGraph(ug, lg), newIsoGraph(uh, lh), compat)
+}
+
+// isoGraph holds graph data for isomorphism search.
+type isoGraph struct {
+	out, in [][]NI
+	mult    []int32 // arc multiplicity, n*n matrix
+	lab     map[Edge][]LI
+}
+
+func newIsoGraph(a AdjacencyList, lab map[Edge][]LI) *isoGraph {
+	n := len(a)
+	g := &isoGraph{
+		out:  a,
+		in:   make([][]NI, n),
+		mult: make([]int32, n*n),
+		lab:  lab,
+	}
+	for fr, to := range a {
+		for _, to := range to {
+			g.in[to] = append(g.in[to], NI(fr))
+			g.mult[fr*n+int(to)]++
+		}
+	}
+	return g
+}
+
+// isoColors computes stable colors of nodes of g and h by color refinement.
+//
+// Colors are consistent between the two graphs.  ok is false if the color
+// class sizes differ, in which case the graphs are not isomorphic.
+func isoColors(g, h *isoGraph) (cg, ch []int, ok bool) {
+	n := len(g.out)
+	cg = make([]int, n)
+	ch = make([]int, n)
+	for nc := 1; ; {
+		// signature of a node:  color, sorted out colors, sorted in colors
+		sig := func(x *isoGraph, c []int, v int) string {
+			oc := make([]int, len(x.out[v]))
+			for i, to := range x.out[v] {
+				oc[i] = c[to]
+			}
+			ic := make([]int, len(x.in[v]))
+			for i, fr := range x.in[v] {
+				ic[i] = c[fr]
+			}
+			sort.Ints(oc)
+			sort.Ints(ic)
+			return fmt.Sprint(c[v], oc, ic)
+		}
+		sg := make([]string, n)
+		sh := make([]string, n)
+		var all []string
+		for v := 0; v < n; v++ {
+			sg[v] = sig(g, cg, v)
+			sh[v] = sig(h, ch, v)
+			all = append(all, sg[v], sh[v])
+		}
+		sort.Strings(all)
+		id := map[string]int{}
+		count := map[string]int{}
+		for _, s := range all {
+			if _, ok := id[s]; !ok {
+				id[s] = len(id)
+			}
+		}
+		for v := 0; v < n; v++ {
+			cg[v] = id[sg[v]]
+			ch[v] = id[sh[v]]
+			count[sg[v]]++
+			count[sh[v]]--
+		}
+		for _, c := range count {
+			if c != 0 {
+				return nil, nil, false
+			}
+		}
+		if len(id) == nc {
+			return cg, ch, true
+		}
+		nc = len(id)
+	}
+}
+
+func isomorphic(g, h *isoGraph, compat func(LI, LI) bool) (bool, []NI) {
+	n := len(g.out)
+	if len(h.out) != n {
+		return false, nil
+	}
+	if n == 0 {
+		return true, []NI{}
+	}
+	cg, ch, ok := isoColors(g, h)
+	if !ok {
+		return false, nil
+	}
+	// class sizes, for ordering
+	size := map[int]int{}
+	for _, c := range cg {
+		size[c]++
+	}
+	// matching order.  each next node maximizes the number of arcs to
+	// ordered nodes, then prefers small color classes and high degree.
+	order := make([]NI, 0, n)
+	parent := make([]NI, n) // an ordered neighbor, or -1
+	inOrder := make([]bool, n)
+	conn := make([]int, n)
+	for len(order) < n {
+		best := -1
+		for v := 0; v < n; v++ {
+			if inOrder[v] {
+				continue
+			}
+			if best < 0 || conn[v] > conn[best] ||
+				conn[v] == conn[best] && (size[cg[v]] < size[cg[best]] ||
+					size[cg[v]] == size[cg[best]] &&
+						len(g.out[v])+len(g.in[v]) > len(g.out[best])+len(g.in[best])) {
+				best = v
+			}
+		}
+		inOrder[best] = true
+		order = append(order, NI(best))
+		parent[best] = -1
+		for _, nb := range [][]NI{g.out[best], g.in[best]} {
+			for _, x := range nb {
+				if !inOrder[x] {
+					conn[x]++
+				}
+			}
+		}
+		// find an ordered neighbor to seed candidates
+		for _, nb := range [][]NI{g.in[best], g.out[best]} {
+			for _, x := range nb {
+				if inOrder[x] && x != NI(best) {
+					parent[best] = x
+				}
+			}
+		}
+	}
+	m := make([]NI, n)   // g node -> h node
+	inv := make([]NI, n) // h node -> g node
+	for i := range m {
+		m[i] = -1
+		inv[i] = -1
+	}
+	// arcs u->v in g and x->y in h correspond?
+	arcsMatch := func(u, v, x, y NI) bool {
+		c := g.mult[int(u)*n+int(v)]
+		if c != h.mult[int(x)*n+int(y)] {
+			return false
+		}
+		if c == 0 || compat == nil {
+			return true
+		}
+		return isoLabelsMatch(g.lab[Edge{u, v}], h.lab[Edge{x, y}], compat)
+	}
+	feasible := func(u, x NI) bool {
+		if cg[u] != ch[x] || inv[x] >= 0 {
+			return false
+		}
+		if !arcsMatch(u, u, x, x) {
+			return false
+		}
+		for _, v := range g.out[u] {
+			if m[v] >= 0 && !arcsMatch(u, v, x, m[v]) {
+				return false
+			}
+		}
+		for _, v := range g.in[u] {
+			if m[v] >= 0 && !arcsMatch(v, u, m[v], x) {
+				return false
+			}
+		}
+		// arcs in h to matched nodes must have counterparts in g
+		for _, y := range h.out[x] {
+			if inv[y] >= 0 && g.mult[int(u)*n+int(inv[y])] == 0 {
+				return false
+			}
+		}
+		for _, y := range h.in[x] {
+			if inv[y] >= 0 && g.mult[int(inv[y])*n+int(u)] == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	all := make([]NI, n)
+	for i := range all {
+		all[i] = NI(i)
+	}
+	var search func(d int) bool
+	search = func(d int) bool {
+		if d == n {
+			return true
+		}
+		u := order[d]
+		cand := all
+		if p := parent[u]; p >= 0 {
+			// candidates are neighbors of the image of the parent
+			if g.mult[int(p)*n+int(u)] > 0 {
+				cand = h.out[m[p]]
+			} else {
+				cand = h.in[m[p]]
+			}
+		}
+	next:
+		for i, x := range cand {
+			if !feasible(u, x) {
+				continue
+			}
+			for _, y := range cand[:i] {
+				if y == x {
+					continue next // repeat from parallel arcs
+				}
+			}
+			m[u] = x
+			inv[x] = u
+			if search(d + 1) {
+				return true
+			}
+			m[u] = -1
+			inv[x] = -1
+		}
+		return false
+	}
+	if !search(0) {
+		return false, nil
+	}
+	return true, m
+}
+
+// isoLabelsMatch tests whether labels l1 and l2 of parallel arcs can be put
+// in one-to-one correspondence with compatible labels.
+func isoLabelsMatch(l1, l2 []LI, compat func(LI, LI) bool) bool {
+	if len(l1) != len(l2) {
+		return false
+	}
+	used := make([]bool, len(l2))
+	var match func(i int) bool
+	match = func(i int) bool {
+		if i == len(l1) {
+			return true
+		}
+		for j, l := range l2 {
+			if !used[j] && compat(l1[i], l) {
+				used[j] = true
+				if match(i + 1) {
+					return true
+				}
+				used[j] = false
+			}
+		}
+		return false
+	}
+	return match(0)
+}
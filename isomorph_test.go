@@ -0,0 +1,166 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleIsomorphic() {
+	// 0---1---2      0---4---2
+	// |       |      |       |
+	// 5---4---3      3---5---1
+	var g, h graph.Undirected
+	for i := graph.NI(0); i < 6; i++ {
+		g.AddEdge(i, (i+1)%6)
+	}
+	for _, e := range []graph.Edge{{0, 4}, {4, 2}, {2, 1}, {1, 5}, {5, 3}, {3, 0}} {
+		h.AddEdge(e.N1, e.N2)
+	}
+	fmt.Println(graph.Isomorphic(g, h))
+	// two triangles.  every node has degree 2, as in g.
+	var t graph.Undirected
+	for _, e := range []graph.Edge{{0, 1}, {1, 2}, {2, 0}, {3, 4}, {4, 5}, {5, 3}} {
+		t.AddEdge(e.N1, e.N2)
+	}
+	fmt.Println(graph.Isomorphic(g, t))
+	// Output:
+	// true [0 4 2 1 5 3]
+	// false []
+}
+
+// permuteArcs returns a copy of g with nodes renumbered by p.
+func permuteArcs(g graph.AdjacencyList, p []int) graph.AdjacencyList {
+	h := make(graph.AdjacencyList, len(g))
+	for fr, to := range g {
+		for _, to := range to {
+			h[p[fr]] = append(h[p[fr]], graph.NI(p[to]))
+		}
+	}
+	return h
+}
+
+// mapsArcs tests that m maps the arcs of g exactly onto the arcs of h.
+func mapsArcs(g, h graph.AdjacencyList, m []graph.NI) bool {
+	if len(m) != len(g) {
+		return false
+	}
+	for _, x := range m {
+		if x < 0 || int(x) >= len(h) {
+			return false
+		}
+	}
+	for fr, to := range g {
+		mt := make([]graph.NI, len(to))
+		for i, to := range to {
+			mt[i] = m[to]
+		}
+		ht := append([]graph.NI{}, h[m[fr]]...)
+		sort.Slice(mt, func(i, j int) bool { return mt[i] < mt[j] })
+		sort.Slice(ht, func(i, j int) bool { return ht[i] < ht[j] })
+		if len(mt) != len(ht) || len(mt) > 0 && !reflect.DeepEqual(mt, ht) {
+			return false
+		}
+	}
+	return true
+}
+
+func petersen() graph.Undirected {
+	var g graph.Undirected
+	for i := graph.NI(0); i < 5; i++ {
+		g.AddEdge(i, (i+1)%5)     // outer cycle
+		g.AddEdge(i, i+5)         // spokes
+		g.AddEdge(i+5, (i+2)%5+5) // inner pentagram
+	}
+	return g
+}
+
+func TestIsomorphic(t *testing.T) {
+	r := rand.New(rand.NewSource(9))
+	for i := 0; i < 30; i++ {
+		n := 1 + r.Intn(40)
+		g := graph.GnmUndirected(n, r.Intn(2*n), r)
+		h := graph.Undirected{permuteArcs(g.AdjacencyList, r.Perm(n))}
+		ok, m := graph.Isomorphic(g, h)
+		if !ok || !mapsArcs(g.AdjacencyList, h.AdjacencyList, m) {
+			t.Fatal("undirected", n, ok, m)
+		}
+		d := graph.GnmDirected(n, r.Intn(3*n), r)
+		dh := graph.Directed{permuteArcs(d.AdjacencyList, r.Perm(n))}
+		ok, m = graph.IsomorphicDirected(d, dh)
+		if !ok || !mapsArcs(d.AdjacencyList, dh.AdjacencyList, m) {
+			t.Fatal("directed", n, ok, m)
+		}
+	}
+	// regular graphs:  Petersen graph and pentagonal prism are both 3-regular
+	// on 10 nodes, indistinguishable by color refinement.
+	p := petersen()
+	var prism graph.Undirected
+	for i := graph.NI(0); i < 5; i++ {
+		prism.AddEdge(i, (i+1)%5)
+		prism.AddEdge(i, i+5)
+		prism.AddEdge(i+5, (i+1)%5+5)
+	}
+	if ok, _ := graph.Isomorphic(p, prism); ok {
+		t.Fatal("Petersen isomorphic to prism")
+	}
+	ph := graph.Undirected{permuteArcs(p.AdjacencyList, r.Perm(10))}
+	if ok, m := graph.Isomorphic(p, ph); !ok ||
+		!mapsArcs(p.AdjacencyList, ph.AdjacencyList, m) {
+		t.Fatal("Petersen not isomorphic to permutation")
+	}
+	// direction matters
+	if ok, _ := graph.IsomorphicDirected(
+		graph.Directed{graph.AdjacencyList{{1}, {2}, {}}},
+		graph.Directed{graph.AdjacencyList{{1}, {}, {1}}}); ok {
+		t.Fatal("path isomorphic to in-star")
+	}
+	// order and empty graphs
+	if ok, _ := graph.Isomorphic(graph.Undirected{}, graph.Undirected{}); !ok {
+		t.Fatal("empty graphs")
+	}
+	if ok, _ := graph.Isomorphic(p, graph.Undirected{}); ok {
+		t.Fatal("Petersen isomorphic to empty graph")
+	}
+}
+
+func TestIsomorphicLabeled(t *testing.T) {
+	// triangle with edge types, and parallel edges
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 1)
+	g.AddEdge(graph.Edge{1, 2}, 2)
+	g.AddEdge(graph.Edge{2, 0}, 2)
+	g.AddEdge(graph.Edge{2, 0}, 3)
+	// same structure, relabeled nodes
+	var h graph.LabeledUndirected
+	h.AddEdge(graph.Edge{2, 0}, 1)
+	h.AddEdge(graph.Edge{0, 1}, 2)
+	h.AddEdge(graph.Edge{1, 2}, 3)
+	h.AddEdge(graph.Edge{1, 2}, 2)
+	eq := func(a, b graph.LI) bool { return a == b }
+	ok, m := graph.IsomorphicLabeled(g.LabeledAdjacencyList,
+		h.LabeledAdjacencyList, eq)
+	if !ok || !reflect.DeepEqual(m, []graph.NI{2, 0, 1}) {
+		t.Fatal(ok, m)
+	}
+	// change an edge type
+	h.LabeledAdjacencyList[2][0].Label = 4
+	h.LabeledAdjacencyList[0][1].Label = 4
+	if ok, _ := graph.IsomorphicLabeled(g.LabeledAdjacencyList,
+		h.LabeledAdjacencyList, eq); ok {
+		t.Fatal("isomorphic with different labels")
+	}
+	// but any labels match with a permissive compat
+	if ok, _ := graph.IsomorphicLabeled(g.LabeledAdjacencyList,
+		h.LabeledAdjacencyList,
+		func(graph.LI, graph.LI) bool { return true }); !ok {
+		t.Fatal("not isomorphic ignoring labels")
+	}
+}
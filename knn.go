@@ -0,0 +1,171 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// knn.go -- k-nearest-neighbor graphs.
+
+import "sync"
+
+// BuildKNN constructs a k-nearest-neighbor graph over n items.
+//
+// Items are nodes 0 through n-1 and dist is a distance function between
+// items.  Each node gets arcs to the k other nodes nearest to it by dist, or
+// to all other nodes if k >= n-1.  Arcs are ordered by increasing distance,
+// with ties broken by lower node number.
+//
+// Arc label values in the returned graph g are indexes into the return value
+// wt where wt[l] is dist(fr, to) for the arc with label l.  A WeightFunc for
+// the graph is thus func(l LI) float64 { return wt[l] }.
+//
+// The algorithm is brute force, calling dist for every ordered pair of
+// distinct nodes, and so is O(n²) in calls to dist.  It keeps a bounded heap
+// of size k for each node.  Nodes are processed concurrently by the given
+// number of worker goroutines, so dist must be safe for concurrent use.
+// Results do not depend on the number of workers.
+//
+// See also KNNUndirected.
+func BuildKNN(n, k int, dist func(i, j int) float64, workers int) (g LabeledDirected, wt []float64) {
+	if k > n-1 {
+		k = n - 1
+	}
+	if k < 0 {
+		k = 0
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	a := make(LabeledAdjacencyList, n)
+	wt = make([]float64, n*k)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			h := make(knnHeap, 0, k)
+			for i := w; i < n; i += workers {
+				h = h[:0]
+				for j := 0; j < n && k > 0; j++ {
+					if j != i {
+						h.offer(knnItem{j, dist(i, j)}, k)
+					}
+				}
+				// sort by popping the max-heap from the end
+				to := make([]Half, k)
+				for x := k - 1; x >= 0; x-- {
+					it := h.pop()
+					l := i*k + x
+					to[x] = Half{NI(it.j), LI(l)}
+					wt[l] = it.d
+				}
+				a[i] = to
+			}
+		}(w)
+	}
+	wg.Wait()
+	return LabeledDirected{a}, wt
+}
+
+// KNNUndirected constructs an undirected graph from a k-nearest-neighbor
+// graph such as returned by BuildKNN.
+//
+// If mutual is false, the result has an edge between each pair of nodes
+// where either node is among the nearest neighbors of the other.  If mutual
+// is true, the result has an edge only where each node is among the nearest
+// neighbors of the other.
+//
+// The label of each edge is that of an arc from the lower numbered node if
+// there is one, otherwise that of the arc from the higher numbered node.
+// Labels thus remain valid for the wt slice returned by BuildKNN.
+func KNNUndirected(g LabeledDirected, mutual bool) LabeledUndirected {
+	a := g.LabeledAdjacencyList
+	u := make(LabeledAdjacencyList, len(a))
+	for fr, to := range a {
+		for _, h := range to {
+			if h.To == NI(fr) {
+				continue
+			}
+			rev := false
+			for _, r := range a[h.To] {
+				if r.To == NI(fr) {
+					rev = true
+					break
+				}
+			}
+			switch {
+			case rev && NI(fr) > h.To:
+				continue // edge added from the lower node
+			case !rev && mutual:
+				continue
+			}
+			u[fr] = append(u[fr], h)
+			u[h.To] = append(u[h.To], Half{NI(fr), h.Label})
+		}
+	}
+	return LabeledUndirected{u}
+}
+
+type knnItem struct {
+	j int
+	d float64
+}
+
+// knnHeap is a max-heap of items by distance, then node.
+type knnHeap []knnItem
+
+func (h knnHeap) less(a, b knnItem) bool {
+	return a.d < b.d || a.d == b.d && a.j < b.j
+}
+
+// offer adds it to h if h has fewer than k items or if it is less than the
+// max item, which it then replaces.
+func (h *knnHeap) offer(it knnItem, k int) {
+	s := *h
+	if len(s) < k {
+		s = append(s, it)
+		for c := len(s) - 1; c > 0; {
+			p := (c - 1) / 2
+			if !s.less(s[p], s[c]) {
+				break
+			}
+			s[p], s[c] = s[c], s[p]
+			c = p
+		}
+		*h = s
+		return
+	}
+	if !s.less(it, s[0]) {
+		return
+	}
+	s[0] = it
+	s.down()
+}
+
+// pop removes and returns the max item.
+func (h *knnHeap) pop() knnItem {
+	s := *h
+	it := s[0]
+	last := len(s) - 1
+	s[0] = s[last]
+	s = s[:last]
+	s.down()
+	*h = s
+	return it
+}
+
+func (h knnHeap) down() {
+	for p := 0; ; {
+		c := 2*p + 1
+		if c >= len(h) {
+			return
+		}
+		if c+1 < len(h) && h.less(h[c], h[c+1]) {
+			c++
+		}
+		if !h.less(h[p], h[c]) {
+			return
+		}
+		h[p], h[c] = h[c], h[p]
+		p = c
+	}
+}
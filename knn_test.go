@@ -0,0 +1,137 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleBuildKNN() {
+	// points on a line
+	x := []float64{0, 1, 3, 7, 8}
+	dist := func(i, j int) float64 { return math.Abs(x[i] - x[j]) }
+	g, wt := graph.BuildKNN(len(x), 2, dist, 2)
+	for fr, to := range g.LabeledAdjacencyList {
+		fmt.Print(fr, ":")
+		for _, h := range to {
+			fmt.Print(" ", h.To, "(", wt[h.Label], ")")
+		}
+		fmt.Println()
+	}
+	fmt.Println("union:")
+	for fr, to := range graph.KNNUndirected(g, false).LabeledAdjacencyList {
+		fmt.Print(fr, ":")
+		for _, h := range to {
+			fmt.Print(" ", h.To)
+		}
+		fmt.Println()
+	}
+	fmt.Println("mutual:")
+	for fr, to := range graph.KNNUndirected(g, true).LabeledAdjacencyList {
+		fmt.Print(fr, ":")
+		for _, h := range to {
+			fmt.Print(" ", h.To)
+		}
+		fmt.Println()
+	}
+	// Output:
+	// 0: 1(1) 2(3)
+	// 1: 0(1) 2(2)
+	// 2: 1(2) 0(3)
+	// 3: 4(1) 2(4)
+	// 4: 3(1) 2(5)
+	// union:
+	// 0: 1 2
+	// 1: 0 2
+	// 2: 0 1 3 4
+	// 3: 4 2
+	// 4: 3 2
+	// mutual:
+	// 0: 1 2
+	// 1: 0 2
+	// 2: 0 1
+	// 3: 4
+	// 4: 3
+}
+
+func TestBuildKNN(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	pos := make([]struct{ X, Y float64 }, 200)
+	for i := range pos {
+		pos[i].X = r.Float64()
+		pos[i].Y = r.Float64()
+	}
+	dist := func(i, j int) float64 {
+		return math.Hypot(pos[i].X-pos[j].X, pos[i].Y-pos[j].Y)
+	}
+	for _, k := range []int{0, 1, 5, 199, 300} {
+		g, wt := graph.BuildKNN(len(pos), k, dist, 1)
+		kk := k
+		if kk > len(pos)-1 {
+			kk = len(pos) - 1
+		}
+		for fr, to := range g.LabeledAdjacencyList {
+			if len(to) != kk {
+				t.Fatal("k", k, "node", fr, len(to), "neighbors")
+			}
+			// labels reproduce distances exactly
+			for _, h := range to {
+				if int(h.To) == fr {
+					t.Fatal("loop")
+				}
+				if wt[h.Label] != dist(fr, int(h.To)) {
+					t.Fatal("label distance")
+				}
+			}
+			// brute force:  no other node is nearer than the farthest
+			if kk == 0 {
+				continue
+			}
+			far := wt[to[kk-1].Label]
+			in := map[graph.NI]bool{}
+			for x, h := range to {
+				in[h.To] = true
+				if x > 0 && wt[to[x-1].Label] > wt[h.Label] {
+					t.Fatal("not ordered")
+				}
+			}
+			for j := range pos {
+				if j != fr && !in[graph.NI(j)] && dist(fr, j) < far {
+					t.Fatal("node", j, "nearer to", fr)
+				}
+			}
+		}
+		// same result with multiple workers
+		g4, wt4 := graph.BuildKNN(len(pos), k, dist, 4)
+		if !reflect.DeepEqual(g, g4) || !reflect.DeepEqual(wt, wt4) {
+			t.Fatal("k", k, "result differs with workers")
+		}
+		// undirected variants
+		w := func(l graph.LI) float64 { return wt[l] }
+		for _, mutual := range []bool{false, true} {
+			u := graph.KNNUndirected(g, mutual)
+			if ok, _, _ := u.IsUndirected(); !ok {
+				t.Fatal("not undirected")
+			}
+			for fr, to := range u.LabeledAdjacencyList {
+				for _, h := range to {
+					if w(h.Label) != dist(fr, int(h.To)) {
+						t.Fatal("undirected label distance")
+					}
+					f, _ := g.HasArc(graph.NI(fr), h.To)
+					b, _ := g.HasArc(h.To, graph.NI(fr))
+					if mutual && !(f && b) || !(f || b) {
+						t.Fatal("edge", fr, h.To, "mutual", mutual)
+					}
+				}
+			}
+		}
+	}
+}
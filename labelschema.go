@@ -0,0 +1,441 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// labelschema.go -- weight functions compiled from bit fields of labels.
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A LabelField describes a bit field packed into an LI.
+//
+// The raw value of the field is the unsigned integer of Width bits starting
+// at bit Offset.  If Table is non-nil, the value of the field is Table[raw].
+// Otherwise it is raw * Scale, where a Scale of 0 is taken as 1.
+type LabelField struct {
+	Name   string
+	Offset uint
+	Width  uint
+	Scale  float64
+	Table  []float64
+}
+
+// raw extracts the raw field value from label l.
+func (f LabelField) raw(l LI) uint32 {
+	return uint32(l) >> f.Offset & (1<<f.Width - 1)
+}
+
+// value returns the field value of label l.
+func (f LabelField) value(l LI) float64 {
+	r := f.raw(l)
+	if f.Table != nil {
+		if int(r) >= len(f.Table) {
+			return math.NaN()
+		}
+		return f.Table[r]
+	}
+	if f.Scale == 0 {
+		return float64(r)
+	}
+	return float64(r) * f.Scale
+}
+
+// A LabelSchema describes labels as a set of packed bit fields.
+//
+// With a schema, weight functions can be compiled from expressions over the
+// fields rather than written as code.  See Compile.
+type LabelSchema []LabelField
+
+// Decode returns field values of label l, keyed by field name.
+//
+// It is intended for debugging.
+func (s LabelSchema) Decode(l LI) map[string]float64 {
+	m := make(map[string]float64, len(s))
+	for _, f := range s {
+		m[f.Name] = f.value(l)
+	}
+	return m
+}
+
+// Validate checks the schema and checks that all arc labels of g fit it.
+//
+// The schema is valid if field names are unique identifiers and if fields
+// are non-empty, fit in 32 bits, and do not overlap.  Labels fit the schema
+// if they have no bits set outside of fields and if table fields have raw
+// values within their tables.
+//
+// A nil error means the schema and labels are valid.
+func (s LabelSchema) Validate(g LabeledAdjacencyList) error {
+	var used uint32
+	names := map[string]bool{}
+	for _, f := range s {
+		if !isIdent(f.Name) {
+			return fmt.Errorf("label schema: invalid field name %q", f.Name)
+		}
+		if names[f.Name] {
+			return fmt.Errorf("label schema: duplicate field %s", f.Name)
+		}
+		names[f.Name] = true
+		if f.Width == 0 || f.Offset+f.Width > 32 {
+			return fmt.Errorf("label schema: field %s does not fit 32 bits", f.Name)
+		}
+		m := uint32(1<<f.Width-1) << f.Offset
+		if used&m != 0 {
+			return fmt.Errorf("label schema: field %s overlaps another field", f.Name)
+		}
+		used |= m
+	}
+	for fr, to := range g {
+		for _, to := range to {
+			if uint32(to.Label)&^used != 0 {
+				return fmt.Errorf("label schema: arc %d->%d label %#x has bits outside fields",
+					fr, to.To, uint32(to.Label))
+			}
+			for _, f := range s {
+				if f.Table != nil && int(f.raw(to.Label)) >= len(f.Table) {
+					return fmt.Errorf("label schema: arc %d->%d field %s value %d exceeds table",
+						fr, to.To, f.Name, f.raw(to.Label))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Compile compiles an expression over the fields of s into a WeightFunc.
+//
+// Expressions use field names, numeric constants, parentheses and the
+// operators, from lowest to highest precedence,
+//
+//	c ? a : b          conditional
+//	||                 logical or
+//	&&                 logical and
+//	== != < <= > >=    comparison
+//	+ -                addition, subtraction
+//	* /                multiplication, division
+//	- !                unary negation, logical not
+//
+// and the functions min(a, b), max(a, b) and abs(a).  Logical and comparison
+// operators produce 1 for true, 0 for false.  Any non-zero value is true.
+//
+// For example with fields "length" and "class", the expression
+//
+//	class == 3 ? length * 2 : length
+//
+// gives a weight of twice the length for arcs of class 3.
+func (s LabelSchema) Compile(expr string) (WeightFunc, error) {
+	p := &lsParser{s: s}
+	if err := p.lex(expr); err != nil {
+		return nil, err
+	}
+	e, err := p.cond()
+	if err != nil {
+		return nil, err
+	}
+	if p.x < len(p.t) {
+		return nil, fmt.Errorf("label schema: unexpected %q", p.t[p.x])
+	}
+	return WeightFunc(e), nil
+}
+
+func isIdent(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || unicode.IsLetter(r) || i > 0 && unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// lsParser is a recursive descent parser for LabelSchema.Compile.
+// Parse functions return closures evaluating the parsed expression.
+type lsParser struct {
+	s LabelSchema
+	t []string // tokens
+	x int      // index of next token
+}
+
+type lsExpr func(LI) float64
+
+func (p *lsParser) lex(s string) error {
+	for i := 0; i < len(s); {
+		c := s[i]
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.' ||
+				s[j] == 'e' || s[j] == 'E' ||
+				(s[j] == '-' || s[j] == '+') && (s[j-1] == 'e' || s[j-1] == 'E')) {
+				j++
+			}
+			p.t = append(p.t, s[i:j])
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			// identifiers as accepted by isIdent
+			j := i + size
+			for j < len(s) {
+				r, size := utf8.DecodeRuneInString(s[j:])
+				if !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+					break
+				}
+				j += size
+			}
+			p.t = append(p.t, s[i:j])
+			i = j
+		case strings.HasPrefix(s[i:], "==") || strings.HasPrefix(s[i:], "!=") ||
+			strings.HasPrefix(s[i:], "<=") || strings.HasPrefix(s[i:], ">=") ||
+			strings.HasPrefix(s[i:], "&&") || strings.HasPrefix(s[i:], "||"):
+			p.t = append(p.t, s[i:i+2])
+			i += 2
+		case strings.IndexByte("+-*/()<>!?:,", c) >= 0:
+			p.t = append(p.t, s[i:i+1])
+			i++
+		default:
+			return fmt.Errorf("label schema: invalid character %q", r)
+		}
+	}
+	return nil
+}
+
+func (p *lsParser) peek() string {
+	if p.x < len(p.t) {
+		return p.t[p.x]
+	}
+	return ""
+}
+
+func (p *lsParser) expect(t string) error {
+	if p.peek() != t {
+		if p.x == len(p.t) {
+			return fmt.Errorf("label schema: expected %q at end of expression", t)
+		}
+		return fmt.Errorf("label schema: expected %q, found %q", t, p.peek())
+	}
+	p.x++
+	return nil
+}
+
+func lsBool(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (p *lsParser) cond() (lsExpr, error) {
+	c, err := p.or()
+	if err != nil || p.peek() != "?" {
+		return c, err
+	}
+	p.x++
+	a, err := p.cond()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(":"); err != nil {
+		return nil, err
+	}
+	b, err := p.cond()
+	if err != nil {
+		return nil, err
+	}
+	return func(l LI) float64 {
+		if c(l) != 0 {
+			return a(l)
+		}
+		return b(l)
+	}, nil
+}
+
+func (p *lsParser) or() (lsExpr, error) {
+	a, err := p.and()
+	for err == nil && p.peek() == "||" {
+		p.x++
+		var b lsExpr
+		if b, err = p.and(); err == nil {
+			a0 := a
+			a = func(l LI) float64 { return lsBool(a0(l) != 0 || b(l) != 0) }
+		}
+	}
+	return a, err
+}
+
+func (p *lsParser) and() (lsExpr, error) {
+	a, err := p.cmp()
+	for err == nil && p.peek() == "&&" {
+		p.x++
+		var b lsExpr
+		if b, err = p.cmp(); err == nil {
+			a0 := a
+			a = func(l LI) float64 { return lsBool(a0(l) != 0 && b(l) != 0) }
+		}
+	}
+	return a, err
+}
+
+func (p *lsParser) cmp() (lsExpr, error) {
+	a, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	op := p.peek()
+	var f func(x, y float64) bool
+	switch op {
+	case "==":
+		f = func(x, y float64) bool { return x == y }
+	case "!=":
+		f = func(x, y float64) bool { return x != y }
+	case "<":
+		f = func(x, y float64) bool { return x < y }
+	case "<=":
+		f = func(x, y float64) bool { return x <= y }
+	case ">":
+		f = func(x, y float64) bool { return x > y }
+	case ">=":
+		f = func(x, y float64) bool { return x >= y }
+	default:
+		return a, nil
+	}
+	p.x++
+	b, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	return func(l LI) float64 { return lsBool(f(a(l), b(l))) }, nil
+}
+
+func (p *lsParser) sum() (lsExpr, error) {
+	a, err := p.term()
+	for err == nil && (p.peek() == "+" || p.peek() == "-") {
+		op := p.t[p.x]
+		p.x++
+		var b lsExpr
+		if b, err = p.term(); err == nil {
+			a0 := a
+			if op == "+" {
+				a = func(l LI) float64 { return a0(l) + b(l) }
+			} else {
+				a = func(l LI) float64 { return a0(l) - b(l) }
+			}
+		}
+	}
+	return a, err
+}
+
+func (p *lsParser) term() (lsExpr, error) {
+	a, err := p.unary()
+	for err == nil && (p.peek() == "*" || p.peek() == "/") {
+		op := p.t[p.x]
+		p.x++
+		var b lsExpr
+		if b, err = p.unary(); err == nil {
+			a0 := a
+			if op == "*" {
+				a = func(l LI) float64 { return a0(l) * b(l) }
+			} else {
+				a = func(l LI) float64 { return a0(l) / b(l) }
+			}
+		}
+	}
+	return a, err
+}
+
+func (p *lsParser) unary() (lsExpr, error) {
+	switch p.peek() {
+	case "-":
+		p.x++
+		a, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(l LI) float64 { return -a(l) }, nil
+	case "!":
+		p.x++
+		a, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(l LI) float64 { return lsBool(a(l) == 0) }, nil
+	}
+	return p.primary()
+}
+
+func (p *lsParser) primary() (lsExpr, error) {
+	t := p.peek()
+	switch {
+	case t == "":
+		return nil, fmt.Errorf("label schema: unexpected end of expression")
+	case t == "(":
+		p.x++
+		a, err := p.cond()
+		if err != nil {
+			return nil, err
+		}
+		return a, p.expect(")")
+	case t[0] >= '0' && t[0] <= '9' || t[0] == '.':
+		v, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return nil, fmt.Errorf("label schema: invalid number %q", t)
+		}
+		p.x++
+		return func(LI) float64 { return v }, nil
+	case !isIdent(t):
+		return nil, fmt.Errorf("label schema: unexpected %q", t)
+	}
+	p.x++
+	if p.peek() == "(" {
+		return p.call(t)
+	}
+	for _, f := range p.s {
+		if f.Name == t {
+			return f.value, nil
+		}
+	}
+	return nil, fmt.Errorf("label schema: unknown field %s", t)
+}
+
+func (p *lsParser) call(fn string) (lsExpr, error) {
+	p.x++ // (
+	var args []lsExpr
+	for p.peek() != ")" {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		a, err := p.cond()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, a)
+	}
+	p.x++ // )
+	nArgs := map[string]int{"min": 2, "max": 2, "abs": 1}
+	n, ok := nArgs[fn]
+	if !ok {
+		return nil, fmt.Errorf("label schema: unknown function %s", fn)
+	}
+	if len(args) != n {
+		return nil, fmt.Errorf("label schema: %s takes %d arguments", fn, n)
+	}
+	switch fn {
+	case "min":
+		a, b := args[0], args[1]
+		return func(l LI) float64 { return math.Min(a(l), b(l)) }, nil
+	case "max":
+		a, b := args[0], args[1]
+		return func(l LI) float64 { return math.Max(a(l), b(l)) }, nil
+	}
+	a := args[0]
+	return func(l LI) float64 { return math.Abs(a(l)) }, nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+// road labels:  low 16 bits are length in meters, next 8 bits are road type.
+// road type 0 is a path, 1 a street, 2 a highway.  The table gives speed in
+// km/h.
+var roadSchema = graph.LabelSchema{
+	{Name: "length", Offset: 0, Width: 16},
+	{Name: "speed", Offset: 16, Width: 8, Table: []float64{20, 50, 100}},
+}
+
+func road(length, typ int) graph.LI { return graph.LI(typ<<16 | length) }
+
+func ExampleLabelSchema_Compile() {
+	//        (highway 10km)
+	//       1--------------
+	//      /(highway 10km) \
+	//     /                 \
+	//    0-------------------3
+	//     \ (street 30km)   /
+	//      \               /(path 4km)
+	//       2--------------
+	//          (street 5km)
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: road(10000, 2)},
+			{To: 2, Label: road(5000, 1)},
+			{To: 3, Label: road(30000, 1)}},
+		1: {{To: 3, Label: road(10000, 2)}},
+		2: {{To: 3, Label: road(4000, 0)}},
+		3: {},
+	}
+	if err := roadSchema.Validate(g); err != nil {
+		fmt.Println(err)
+		return
+	}
+	profiles := []struct{ name, expr string }{
+		// cars don't use paths
+		{"car", "speed == 20 ? 1e9 : length / speed"},
+		// bikes don't use highways, and don't go faster than 20
+		{"bike", "speed == 100 ? 1e9 : length / min(speed, 20)"},
+	}
+	for _, p := range profiles {
+		w, err := roadSchema.Compile(p.expr)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		path, d := g.DijkstraPath(0, 3, w)
+		fmt.Printf("%s: %v %.1f hours\n", p.name, path.Path, d/1000)
+	}
+	// Output:
+	// car: [{1 141072} {3 141072}] 0.2 hours
+	// bike: [{2 70536} {3 4000}] 0.5 hours
+}
+
+func ExampleLabelSchema_Decode() {
+	fmt.Println(roadSchema.Decode(road(1234, 1)))
+	// Output:
+	// map[length:1234 speed:50]
+}
+
+func TestLabelSchemaCompile(t *testing.T) {
+	s := graph.LabelSchema{
+		{Name: "a", Offset: 0, Width: 10, Scale: .5},
+		{Name: "b", Offset: 10, Width: 3},
+		{Name: "c", Offset: 13, Width: 2, Table: []float64{1.5, 2, 4, 8}},
+	}
+	field := func(l graph.LI, off, w uint) float64 {
+		return float64(uint32(l) >> off & (1<<w - 1))
+	}
+	fa := func(l graph.LI) float64 { return field(l, 0, 10) * .5 }
+	fb := func(l graph.LI) float64 { return field(l, 10, 3) }
+	fc := func(l graph.LI) float64 { return []float64{1.5, 2, 4, 8}[int(field(l, 13, 2))] }
+	b2f := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	for _, tc := range []struct {
+		expr string
+		want graph.WeightFunc
+	}{
+		{"a", fa},
+		{"2*a + 3*b - c", func(l graph.LI) float64 { return 2*fa(l) + 3*fb(l) - fc(l) }},
+		{"a * (b + 1) / c", func(l graph.LI) float64 { return fa(l) * (fb(l) + 1) / fc(l) }},
+		{"-a - -b", func(l graph.LI) float64 { return -fa(l) + fb(l) }},
+		{"b == 3 ? a : b > 4 ? c : 1e2",
+			func(l graph.LI) float64 {
+				switch {
+				case fb(l) == 3:
+					return fa(l)
+				case fb(l) > 4:
+					return fc(l)
+				}
+				return 100
+			}},
+		{"b >= 2 && b <= 5 || !(c != 4)",
+			func(l graph.LI) float64 {
+				return b2f(fb(l) >= 2 && fb(l) <= 5 || fc(l) == 4)
+			}},
+		{"max(a, 10) + abs(b - c) + min(1, b < 3)",
+			func(l graph.LI) float64 {
+				return math.Max(fa(l), 10) + math.Abs(fb(l)-fc(l)) +
+					math.Min(1, b2f(fb(l) < 3))
+			}},
+	} {
+		w, err := s.Compile(tc.expr)
+		if err != nil {
+			t.Fatal(tc.expr, err)
+		}
+		r := rand.New(rand.NewSource(59))
+		for i := 0; i < 1000; i++ {
+			l := graph.LI(r.Intn(1 << 15))
+			if got, want := w(l), tc.want(l); got != want {
+				t.Fatalf("%s, label %#x: got %g, want %g", tc.expr, l, got, want)
+			}
+		}
+	}
+}
+
+func TestLabelSchemaCompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"length +",
+		"(length",
+		"length)",
+		"width",
+		"length ? 1",
+		"min(length)",
+		"sqrt(length)",
+		"length # 2",
+		"1.2.3",
+	} {
+		if _, err := roadSchema.Compile(expr); err == nil {
+			t.Fatalf("%q: no error", expr)
+		}
+	}
+}
+
+func TestLabelSchemaCompileUnicode(t *testing.T) {
+	// identifiers are lexed by rune, as field names are validated
+	s := graph.LabelSchema{
+		{Name: "λ", Offset: 0, Width: 4},
+		{Name: "größe", Offset: 4, Width: 4},
+	}
+	w, err := s.Compile("2*λ + größe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := w(0x35); got != 13 {
+		t.Fatal("got", got, "want 13")
+	}
+	if _, err := s.Compile("λ × 2"); err == nil {
+		t.Fatal("no error for ×")
+	}
+}
+
+func TestLabelSchemaProfiles(t *testing.T) {
+	// random road network with paths differing by profile
+	r := rand.New(rand.NewSource(61))
+	g := make(graph.LabeledAdjacencyList, 200)
+	for fr := range g {
+		for i := 0; i < 4; i++ {
+			g[fr] = append(g[fr], graph.Half{
+				To:    graph.NI(r.Intn(len(g))),
+				Label: road(100+r.Intn(20000), r.Intn(3)),
+			})
+		}
+	}
+	if err := roadSchema.Validate(g); err != nil {
+		t.Fatal(err)
+	}
+	speeds := []float64{20, 50, 100}
+	car := func(l graph.LI) float64 {
+		length, typ := float64(l&0xffff), l>>16
+		if typ == 0 {
+			return 1e9
+		}
+		return length / speeds[typ]
+	}
+	bike := func(l graph.LI) float64 {
+		length, typ := float64(l&0xffff), l>>16
+		if typ == 2 {
+			return 1e9
+		}
+		return length / 20
+	}
+	var paths [2]graph.LabeledPath
+	for i, p := range []struct {
+		expr string
+		w    graph.WeightFunc
+	}{
+		{"speed == 20 ? 1e9 : length / speed", car},
+		{"speed == 100 ? 1e9 : length / min(speed, 20)", bike},
+	} {
+		w, err := roadSchema.Compile(p.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _, dc, _ := g.Dijkstra(0, -1, w)
+		_, _, dh, _ := g.Dijkstra(0, -1, p.w)
+		for n := range dc {
+			if dc[n] != dh[n] {
+				t.Fatalf("%s: node %d distance %g, want %g", p.expr, n, dc[n], dh[n])
+			}
+		}
+		paths[i], _ = g.DijkstraPath(0, 199, w)
+	}
+	if fmt.Sprint(paths[0]) == fmt.Sprint(paths[1]) {
+		t.Fatal("profiles give same path", paths[0])
+	}
+}
+
+func TestLabelSchemaValidate(t *testing.T) {
+	g := graph.LabeledAdjacencyList{{{To: 0, Label: road(5, 2)}}}
+	if err := roadSchema.Validate(g); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		s graph.LabelSchema
+		g graph.LabeledAdjacencyList
+	}{
+		{roadSchema, graph.LabeledAdjacencyList{{{To: 0, Label: road(5, 3)}}}},
+		{roadSchema, graph.LabeledAdjacencyList{{{To: 0, Label: 1 << 24}}}},
+		{graph.LabelSchema{{Name: "a", Width: 4}, {Name: "a", Offset: 4, Width: 4}}, nil},
+		{graph.LabelSchema{{Name: "a", Width: 4}, {Name: "b", Offset: 3, Width: 4}}, nil},
+		{graph.LabelSchema{{Name: "a", Offset: 30, Width: 4}}, nil},
+		{graph.LabelSchema{{Name: "a"}}, nil},
+		{graph.LabelSchema{{Name: "a b", Width: 1}}, nil},
+	} {
+		if err := tc.s.Validate(tc.g); err == nil {
+			t.Fatal("no error for", tc.s, tc.g)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// loop.go -- loop analysis of control flow graphs using dominators.
+
+import "github.com/soniakeys/bits"
+
+// A Loop is a natural loop of a directed graph.
+//
+// The loop is defined by a back arc, an arc whose head dominates its tail.
+// The head of the back arc is the loop header.
+type Loop struct {
+	Header NI        // loop header, the head of the back arc
+	Fr     NI        // tail of the back arc
+	X      int       // index of the back arc in the arc list of Fr
+	Body   bits.Bits // nodes of the loop, including Header and Fr
+}
+
+// domIntervals numbers nodes of the dominator tree in preorder and
+// postorder, allowing constant time dominance queries with method dominates.
+type domIntervals struct {
+	pre, post []int // -1 for nodes not in the dominator tree
+}
+
+func (d Dominators) intervals() domIntervals {
+	im := d.Immediate
+	ch := make(AdjacencyList, len(im))
+	root := NI(-1)
+	for n, p := range im {
+		switch {
+		case p < 0:
+		case p == NI(n):
+			root = p
+		default:
+			ch[p] = append(ch[p], NI(n))
+		}
+	}
+	iv := domIntervals{make([]int, len(im)), make([]int, len(im))}
+	for n := range im {
+		iv.pre[n] = -1
+		iv.post[n] = -1
+	}
+	if root < 0 {
+		return iv
+	}
+	pre, post := 0, 0
+	var df func(NI)
+	df = func(n NI) {
+		iv.pre[n] = pre
+		pre++
+		for _, c := range ch[n] {
+			df(c)
+		}
+		iv.post[n] = post
+		post++
+	}
+	df(root)
+	return iv
+}
+
+// dominates returns true if a dominates b.
+func (iv domIntervals) dominates(a, b NI) bool {
+	return iv.pre[a] >= 0 && iv.pre[b] >= 0 &&
+		iv.pre[a] <= iv.pre[b] && iv.post[b] <= iv.post[a]
+}
+
+// IrreducibleArcs finds arcs that make a graph irreducible.
+//
+// Argument d must be dominators of g, as computed by g.Dominators(start).
+//
+// A retreating arc is an arc to an ancestor in a depth-first spanning tree
+// from start.  In a reducible graph, every retreating arc is a back arc, that
+// is, its head dominates its tail.  IrreducibleArcs emits retreating arcs
+// that are not back arcs, each as the arc g[fr][x].  Such arcs enter a cycle
+// with more than one entry node and so are not part of any natural loop.
+//
+// Only the subgraph reachable from start is considered.  Emitting stops
+// early if emit returns false.  IrreducibleArcs returns true if all arcs
+// were emitted, false if emitting was stopped early.  In particular, for a
+// reducible graph nothing is emitted and the return value is true.
+func (g Directed) IrreducibleArcs(d Dominators, emit func(fr NI, x int) bool) bool {
+	a := g.AdjacencyList
+	iv := d.intervals()
+	start := NI(-1)
+	for n, p := range d.Immediate {
+		if p == NI(n) {
+			start = p
+		}
+	}
+	if start < 0 {
+		return true
+	}
+	visited := bits.New(len(a))
+	onStack := bits.New(len(a))
+	var df func(NI) bool
+	df = func(fr NI) bool {
+		visited.SetBit(int(fr), 1)
+		onStack.SetBit(int(fr), 1)
+		for x, to := range a[fr] {
+			switch {
+			case onStack.Bit(int(to)) == 1:
+				if !iv.dominates(to, fr) && !emit(fr, x) {
+					return false
+				}
+			case visited.Bit(int(to)) == 0:
+				if !df(to) {
+					return false
+				}
+			}
+		}
+		onStack.SetBit(int(fr), 0)
+		return true
+	}
+	return df(start)
+}
+
+// LoopNestForest organizes loops by containment.
+//
+// Argument loops should be natural loops of g, as found by g.NaturalLoops.
+// Loops with the same header are merged.  Loops with different headers are
+// then either nested or disjoint.
+//
+// The result is a FromList over all nodes of g.  For each node n, From is
+// the header of the innermost loop containing n, not counting a loop with
+// header n.  Nodes not contained in any loop, such as the headers of
+// outermost loops, are roots.  Len, Leaves, and MaxLen are populated.
+func (g Directed) LoopNestForest(loops []Loop) FromList {
+	n := len(g.AdjacencyList)
+	body := map[NI]bits.Bits{}
+	for _, l := range loops {
+		b, ok := body[l.Header]
+		if !ok {
+			b = bits.New(n)
+		}
+		b.Or(b, l.Body)
+		body[l.Header] = b
+	}
+	size := map[NI]int{}
+	for h, b := range body {
+		size[h] = b.OnesCount()
+	}
+	f := NewFromList(n)
+	for nd := range f.Paths {
+		f.Paths[nd].From = -1
+		min := n + 1
+		for h, b := range body {
+			if h != NI(nd) && b.Bit(nd) == 1 && size[h] < min {
+				min = size[h]
+				f.Paths[nd].From = h
+			}
+		}
+	}
+	f.RecalcLeaves()
+	f.RecalcLen()
+	return f
+}
+
+// NaturalLoops finds natural loops of a graph.
+//
+// Argument d must be dominators of g, as computed by g.Dominators(start).
+//
+// A back arc is an arc whose head dominates its tail.  Each back arc defines
+// a natural loop, the header (the head of the back arc) together with all
+// nodes that can reach the tail of the back arc without passing through the
+// header.  All nodes of a natural loop are dominated by the header.
+//
+// A Loop is returned for each back arc of the subgraph reachable from start,
+// in order of the arcs in g.  Multiple back arcs to the same header give
+// multiple loops with the same header.  See LoopNestForest to merge these
+// and to organize loops by containment.
+//
+// Retreating arcs which are not back arcs do not define natural loops.
+// See IrreducibleArcs.
+func (g Directed) NaturalLoops(d Dominators) (loops []Loop) {
+	a := g.AdjacencyList
+	iv := d.intervals()
+	var tr Directed
+	for fr, to := range a {
+		for x, h := range to {
+			if !iv.dominates(h, NI(fr)) {
+				continue
+			}
+			if tr.AdjacencyList == nil {
+				tr, _ = g.Transpose()
+			}
+			b := bits.New(len(a))
+			b.SetBit(int(h), 1)
+			b.SetBit(fr, 1)
+			s := []NI{NI(fr)}
+			for len(s) > 0 {
+				n := s[len(s)-1]
+				s = s[:len(s)-1]
+				if n == h {
+					continue
+				}
+				for _, p := range tr.AdjacencyList[n] {
+					if b.Bit(int(p)) == 0 && iv.dominates(h, p) {
+						b.SetBit(int(p), 1)
+						s = append(s, p)
+					}
+				}
+			}
+			loops = append(loops, Loop{h, NI(fr), x, b})
+		}
+	}
+	return
+}
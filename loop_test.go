@@ -0,0 +1,217 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/bits"
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDirected_NaturalLoops() {
+	//   0
+	//   |
+	//   v
+	//   1<-----
+	//   |      \
+	//   v       \
+	//   2<--     |
+	//   |   \    |
+	//   v   /    |
+	//   3---     |
+	//   |       /
+	//   v      /
+	//   4------
+	//   |
+	//   v
+	//   5
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {3},
+		3: {2, 4},
+		4: {1, 5},
+		5: {},
+	}}
+	loops := g.NaturalLoops(g.Dominators(0))
+	for _, l := range loops {
+		fmt.Printf("header %d, back arc %d->%d, body %v\n",
+			l.Header, l.Fr, g.AdjacencyList[l.Fr][l.X], l.Body.Slice())
+	}
+	f := g.LoopNestForest(loops)
+	for n, p := range f.Paths {
+		fmt.Println(n, "in loop", p.From)
+	}
+	// Output:
+	// header 2, back arc 3->2, body [2 3]
+	// header 1, back arc 4->1, body [1 2 3 4]
+	// 0 in loop -1
+	// 1 in loop -1
+	// 2 in loop 1
+	// 3 in loop 2
+	// 4 in loop 1
+	// 5 in loop -1
+}
+
+func ExampleDirected_NaturalLoops_sharedHeader() {
+	//   0
+	//   |
+	//   v
+	//   1<---
+	//  / ^   \
+	// v  |    |
+	// 2--     |
+	//  \      |
+	//   v     |
+	//    3----
+	//    |
+	//    v
+	//    4
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {1, 3},
+		3: {1, 4},
+		4: {},
+	}}
+	loops := g.NaturalLoops(g.Dominators(0))
+	for _, l := range loops {
+		fmt.Printf("header %d, back arc %d->%d, body %v\n",
+			l.Header, l.Fr, g.AdjacencyList[l.Fr][l.X], l.Body.Slice())
+	}
+	f := g.LoopNestForest(loops)
+	for n, p := range f.Paths {
+		fmt.Println(n, "in loop", p.From)
+	}
+	// Output:
+	// header 1, back arc 2->1, body [1 2]
+	// header 1, back arc 3->1, body [1 2 3]
+	// 0 in loop -1
+	// 1 in loop -1
+	// 2 in loop 1
+	// 3 in loop 1
+	// 4 in loop -1
+}
+
+func ExampleDirected_IrreducibleArcs() {
+	// a cycle 1, 2 with two entries from 0
+	//   0
+	//  / \
+	// v   v
+	// 1<->2
+	//     |
+	//     v
+	//     3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {1, 3},
+		3: {},
+	}}
+	d := g.Dominators(0)
+	fmt.Println("natural loops:", len(g.NaturalLoops(d)))
+	g.IrreducibleArcs(d, func(fr graph.NI, x int) bool {
+		fmt.Println("irreducible arc:", fr, "->", g.AdjacencyList[fr][x])
+		return true
+	})
+	// Output:
+	// natural loops: 0
+	// irreducible arc: 2 -> 1
+}
+
+func TestNaturalLoops(t *testing.T) {
+	r := rand.New(rand.NewSource(79))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(12)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for j := r.Intn(2 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(r.Intn(n)))
+		}
+		d := g.Dominators(0)
+		dominates := func(a, b graph.NI) bool {
+			for _, s := range d.Set(b) {
+				if s == a {
+					return true
+				}
+			}
+			return false
+		}
+		loops := g.NaturalLoops(d)
+		nBack := 0
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				if dominates(to, graph.NI(fr)) {
+					nBack++
+				}
+			}
+		}
+		if len(loops) != nBack {
+			t.Fatal(len(loops), "loops,", nBack, "back arcs")
+		}
+		for _, l := range loops {
+			if g.AdjacencyList[l.Fr][l.X] != l.Header {
+				t.Fatal("back arc", l.Fr, l.X, "not to header", l.Header)
+			}
+			// body is closed under predecessors, except at the header,
+			// and all body nodes are dominated by the header.
+			l.Body.IterateOnes(func(b int) bool {
+				if !dominates(l.Header, graph.NI(b)) {
+					t.Fatal("body node", b, "not dominated by", l.Header)
+				}
+				return true
+			})
+			for fr, to := range g.AdjacencyList {
+				for _, to := range to {
+					if to != l.Header && l.Body.Bit(int(to)) == 1 &&
+						d.Immediate[fr] >= 0 && l.Body.Bit(fr) == 0 {
+						t.Fatal("arc", fr, to, "enters loop", l.Body.Slice(),
+							"not at header", l.Header)
+					}
+				}
+			}
+		}
+		// loop nest: From is the innermost enclosing header
+		f := g.LoopNestForest(loops)
+		if c, _ := f.Cyclic(); c {
+			t.Fatal("cyclic loop nest")
+		}
+		merged := map[graph.NI]bits.Bits{}
+		for _, l := range loops {
+			b, ok := merged[l.Header]
+			if !ok {
+				b = bits.New(n)
+			}
+			b.Or(b, l.Body)
+			merged[l.Header] = b
+		}
+		for nd, p := range f.Paths {
+			if p.From >= 0 && merged[p.From].Bit(nd) == 0 {
+				t.Fatal("node", nd, "not in loop", p.From)
+			}
+			for h, b := range merged {
+				if h == graph.NI(nd) || b.Bit(nd) == 0 {
+					continue
+				}
+				if p.From < 0 {
+					t.Fatal("node", nd, "in loop", h, "but has no loop")
+				}
+				if m := merged[p.From]; m.OnesCount() > b.OnesCount() {
+					t.Fatal("node", nd, "loop", p.From, "not innermost")
+				}
+			}
+		}
+		// irreducible arcs are retreating arcs but not back arcs
+		g.IrreducibleArcs(d, func(fr graph.NI, x int) bool {
+			to := g.AdjacencyList[fr][x]
+			if dominates(to, fr) {
+				t.Fatal("back arc", fr, to, "reported irreducible")
+			}
+			return true
+		})
+	}
+}
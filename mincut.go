@@ -0,0 +1,204 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// mincut.go -- global minimum cut of undirected graphs.
+
+import (
+	"math"
+
+	"github.com/soniakeys/bits"
+)
+
+// MinimumCut finds a minimum weight edge cut of an undirected graph by the
+// Stoer-Wagner algorithm.
+//
+// Edge weights are given by w and must be non-negative.  Loops are ignored
+// and parallel edges are merged, summing weights.
+//
+// Returned is the weight of the cut and the node set of one side of the cut.
+// If g is disconnected, the cut has weight 0 and side is a connected
+// component.  If g has fewer than two nodes, cutWeight is +Inf and side is
+// empty.
+//
+// Time is O(n(n+m)) for n nodes and m edges.
+//
+// See also alt.KargerSteinMinCut, a randomized algorithm.
+func (g LabeledUndirected) MinimumCut(w WeightFunc) (cutWeight float64, side bits.Bits) {
+	if cutWeight, side, ok := g.minCutTrivial(); ok {
+		return cutWeight, side
+	}
+	return stoerWagner(len(g.LabeledAdjacencyList), func(n NI, f func(NI, float64)) {
+		for _, to := range g.LabeledAdjacencyList[n] {
+			f(to.To, w(to.Label))
+		}
+	})
+}
+
+// MinimumCut finds a minimum edge cut of an undirected graph by the
+// Stoer-Wagner algorithm.
+//
+// Edges have unit weight.  Loops are ignored and parallel edges count
+// separately, so cutWeight is the number of edges cut.
+//
+// Return values and time are as for the labeled version with a WeightFunc.
+func (g Undirected) MinimumCut() (cutWeight float64, side bits.Bits) {
+	if cutWeight, side, ok := g.minCutTrivial(); ok {
+		return cutWeight, side
+	}
+	return stoerWagner(len(g.AdjacencyList), func(n NI, f func(NI, float64)) {
+		for _, to := range g.AdjacencyList[n] {
+			f(to, 1)
+		}
+	})
+}
+
+// minCutTrivial handles graphs with fewer than two nodes or disconnected
+// graphs.  If ok is false, g is connected with at least two nodes.
+func (g LabeledUndirected) minCutTrivial() (cutWeight float64, side bits.Bits, ok bool) {
+	return minCutTrivial(g.Order(), g.ConnectedComponentBits())
+}
+
+func (g Undirected) minCutTrivial() (cutWeight float64, side bits.Bits, ok bool) {
+	return minCutTrivial(g.Order(), g.ConnectedComponentBits())
+}
+
+func minCutTrivial(n int, cc func() (int, int, bits.Bits)) (cutWeight float64, side bits.Bits, ok bool) {
+	if n < 2 {
+		return math.Inf(1), bits.New(n), true
+	}
+	if order, _, b := cc(); order < n {
+		side = bits.New(n)
+		side.Set(b)
+		return 0, side, true
+	}
+	return 0, bits.Bits{}, false
+}
+
+// swEdge is an edge to supernode to with total weight w.
+type swEdge struct {
+	to NI
+	w  float64
+}
+
+// stoerWagner finds a minimum cut of a connected graph of n nodes.  Function
+// arcs calls f with the to-node and weight of each arc from node n.
+func stoerWagner(n int, arcs func(n NI, f func(NI, float64))) (float64, bits.Bits) {
+	// adj[s] lists supernodes adjacent to supernode s, each once, with total
+	// weight.  x is scratch space for merging, indexed by supernode.
+	adj := make([][]swEdge, n)
+	x := make([]int, n)
+	for i := range x {
+		x[i] = -1
+	}
+	for fr := range adj {
+		var a []swEdge
+		arcs(NI(fr), func(to NI, w float64) {
+			if to == NI(fr) {
+				return
+			}
+			if x[to] < 0 {
+				x[to] = len(a)
+				a = append(a, swEdge{to, 0})
+			}
+			a[x[to]].w += w
+		})
+		for _, e := range a {
+			x[e.to] = -1
+		}
+		adj[fr] = a
+	}
+	members := make([][]NI, n)
+	active := make([]NI, n)
+	for i := range members {
+		members[i] = []NI{NI(i)}
+		active[i] = NI(i)
+	}
+	best := math.Inf(1)
+	var bestSide []NI
+	key := make([]float64, n)
+	inA := bits.New(n)
+	for len(active) > 1 {
+		// maximum adjacency ordering, found by linear scan
+		for _, v := range active {
+			key[v] = 0
+			inA.SetBit(int(v), 0)
+		}
+		var s, t NI = -1, -1
+		for range active {
+			u, ku := NI(-1), -1.
+			for _, v := range active {
+				if inA.Bit(int(v)) == 0 && key[v] > ku {
+					u, ku = v, key[v]
+				}
+			}
+			inA.SetBit(int(u), 1)
+			for _, e := range adj[u] {
+				key[e.to] += e.w
+			}
+			s, t = t, u
+		}
+		// cut of the phase separates t from the rest
+		if key[t] < best {
+			best = key[t]
+			bestSide = append(bestSide[:0], members[t]...)
+		}
+		// merge t into s.  first, edges of s, less the edge to t.
+		a := adj[s][:0]
+		for _, e := range adj[s] {
+			if e.to != t {
+				x[e.to] = len(a)
+				a = append(a, e)
+			}
+		}
+		// then edges of t, redirecting neighbors' edges from t to s.
+		for _, e := range adj[t] {
+			if e.to == s {
+				continue
+			}
+			if x[e.to] < 0 {
+				x[e.to] = len(a)
+				a = append(a, swEdge{e.to, 0})
+			}
+			a[x[e.to]].w += e.w
+			na := adj[e.to][:0]
+			var toS *swEdge
+			for _, f := range adj[e.to] {
+				if f.to == t {
+					continue
+				}
+				na = append(na, f)
+				if f.to == s {
+					toS = &na[len(na)-1]
+				}
+			}
+			if toS != nil {
+				toS.w += e.w
+			} else {
+				na = append(na, swEdge{s, e.w})
+			}
+			adj[e.to] = na
+		}
+		for _, e := range a {
+			x[e.to] = -1
+		}
+		adj[s] = a
+		adj[t] = nil
+		members[s] = append(members[s], members[t]...)
+		members[t] = nil
+		for i, v := range active {
+			if v == t {
+				last := len(active) - 1
+				active[i] = active[last]
+				active = active[:last]
+				break
+			}
+		}
+	}
+	side := bits.New(n)
+	for _, v := range bestSide {
+		side.SetBit(int(v), 1)
+	}
+	return best, side
+}
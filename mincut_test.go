@@ -0,0 +1,156 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/bits"
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/alt"
+)
+
+func ExampleLabeledUndirected_MinimumCut() {
+	// weights in parens:
+	//     (3)     (1)     (3)
+	//  0 ----- 1 ----- 2 ----- 3
+	//   \     /         \     /
+	//   (2)(2)          (2)(2)
+	//     \ /             \ /
+	//      4               5
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 3)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{2, 3}, 3)
+	g.AddEdge(graph.Edge{0, 4}, 2)
+	g.AddEdge(graph.Edge{1, 4}, 2)
+	g.AddEdge(graph.Edge{2, 5}, 2)
+	g.AddEdge(graph.Edge{3, 5}, 2)
+	w := func(l graph.LI) float64 { return float64(l) }
+	cw, side := g.MinimumCut(w)
+	fmt.Println(cw, side.Slice())
+	// Output:
+	// 1 [2 3 5]
+}
+
+// bruteMinCut returns the minimum weight of a cut of g by trying all
+// bipartitions.
+func bruteMinCut(g graph.LabeledUndirected, w graph.WeightFunc) float64 {
+	n := g.Order()
+	best := math.Inf(1)
+	for s := uint(1); s < 1<<uint(n-1); s++ {
+		c := 0.
+		for fr, to := range g.LabeledAdjacencyList {
+			for _, to := range to {
+				if s>>uint(fr)&1 == 1 && s>>uint(to.To)&1 == 0 {
+					c += w(to.Label)
+				}
+			}
+		}
+		if c < best {
+			best = c
+		}
+	}
+	return best
+}
+
+// sideCutWeight returns the weight of edges of g crossing the cut given by
+// side.
+func sideCutWeight(g graph.LabeledUndirected, w graph.WeightFunc, side bits.Bits) float64 {
+	c := 0.
+	for fr, to := range g.LabeledAdjacencyList {
+		for _, to := range to {
+			if side.Bit(fr) == 1 && side.Bit(int(to.To)) == 0 {
+				c += w(to.Label)
+			}
+		}
+	}
+	return c
+}
+
+func TestMinimumCutBrute(t *testing.T) {
+	r := rand.New(rand.NewSource(35))
+	wt := make([]float64, 20)
+	for i := range wt {
+		wt[i] = float64(r.Intn(10))
+	}
+	w := func(l graph.LI) float64 { return wt[l] }
+	for i := 0; i < 300; i++ {
+		n := 2 + r.Intn(11)
+		var g graph.LabeledUndirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		// includes loops and parallel edges
+		for m := r.Intn(n * 3); m > 0; m-- {
+			g.AddEdge(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(r.Intn(len(wt))))
+		}
+		want := bruteMinCut(g, w)
+		cw, side := g.MinimumCut(w)
+		if cw != want {
+			t.Fatal(i, "cut weight", cw, "want", want)
+		}
+		if k := side.OnesCount(); k == 0 || k == n {
+			t.Fatal(i, "side", side.Slice())
+		}
+		if c := sideCutWeight(g, w, side); c != cw {
+			t.Fatal(i, "side", side.Slice(), "has cut weight", c, "returned", cw)
+		}
+		// unlabeled, unit weights
+		u := graph.Undirected{g.Unlabeled()}
+		one := func(graph.LI) float64 { return 1 }
+		want = bruteMinCut(g, one)
+		cw, side = u.MinimumCut()
+		if cw != want || sideCutWeight(g, one, side) != cw {
+			t.Fatal(i, "unlabeled cut", cw, side.Slice(), "want", want)
+		}
+	}
+}
+
+// TestMinimumCutKargerStein compares MinimumCut with the independent
+// randomized implementation in package alt on graphs too large for
+// bruteMinCut.  With many trials, Karger-Stein finds a minimum cut with
+// high probability, and for a seeded Rand the test is deterministic.
+func TestMinimumCutKargerStein(t *testing.T) {
+	r := rand.New(rand.NewSource(36))
+	wt := make([]float64, 20)
+	for i := range wt {
+		wt[i] = float64(1 + r.Intn(10))
+	}
+	w := func(l graph.LI) float64 { return wt[l] }
+	for i := 0; i < 30; i++ {
+		n := 10 + r.Intn(20)
+		var g graph.LabeledUndirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		for m := 2*n + r.Intn(n*2); m > 0; m-- {
+			g.AddEdge(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(r.Intn(len(wt))))
+		}
+		cw, side := g.MinimumCut(w)
+		if c := sideCutWeight(g, w, side); c != cw {
+			t.Fatal(i, "side", side.Slice(), "has cut weight", c, "returned", cw)
+		}
+		want, _ := alt.KargerSteinMinCut(g, w, 100, r)
+		if cw != want {
+			t.Fatal(i, "cut weight", cw, "Karger-Stein", want)
+		}
+	}
+}
+
+func TestMinimumCutDisconnected(t *testing.T) {
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 4)
+	cw, side := g.MinimumCut()
+	if cw != 0 || side.Slice()[0] != 0 || side.OnesCount() != 2 {
+		t.Fatal(cw, side.Slice())
+	}
+	g = graph.Undirected{graph.AdjacencyList{nil}}
+	if cw, side := g.MinimumCut(); !math.IsInf(cw, 1) || side.OnesCount() != 0 {
+		t.Fatal(cw, side.Slice())
+	}
+}
@@ -5,6 +5,7 @@ package graph_test
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/soniakeys/bits"
@@ -257,3 +258,67 @@ func TestPrim100(t *testing.T) {
 		}
 	}
 }
+
+// TestMSTBruteForce compares Prim and Kruskal spanning forest weights to
+// a brute force minimum on small random multigraphs with duplicate weights,
+// parallel edges, loops, and multiple components.
+func TestMSTBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(6)
+		var g graph.LabeledUndirected
+		var edges []graph.Edge
+		var wt []float64
+		for m := r.Intn(10); m >= 0; m-- {
+			e := graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))}
+			g.AddEdge(e, graph.LI(len(wt)))
+			edges = append(edges, e)
+			wt = append(wt, float64(r.Intn(3))) // few distinct weights
+		}
+		w := func(l graph.LI) float64 { return wt[l] }
+		// brute force: maximal forests have the most edges, find the
+		// minimum weight among them.
+		best, bestSize := 0., -1
+		for s := 0; s < 1<<uint(len(edges)); s++ {
+			c := make([]int, g.Order()) // naive component labels
+			for i := range c {
+				c[i] = i
+			}
+			d, size, forest := 0., 0, true
+			for x, e := range edges {
+				if s&(1<<uint(x)) == 0 {
+					continue
+				}
+				c1, c2 := c[e.N1], c[e.N2]
+				if c1 == c2 {
+					forest = false
+					break
+				}
+				for i := range c {
+					if c[i] == c2 {
+						c[i] = c1
+					}
+				}
+				d += wt[x]
+				size++
+			}
+			if forest && (size > bestSize || size == bestSize && d < best) {
+				best, bestSize = d, size
+			}
+		}
+		_, kd := g.Kruskal(w)
+		if kd != best {
+			t.Fatal("Kruskal", kd, "brute force", best)
+		}
+		var f graph.FromList
+		pd := 0.
+		reps, _, _ := g.ConnectedComponentReps()
+		for _, rep := range reps {
+			_, d := g.Prim(rep, w, &f, nil, nil)
+			pd += d
+		}
+		if pd != best {
+			t.Fatal("Prim", pd, "brute force", best)
+		}
+	}
+}
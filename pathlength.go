@@ -0,0 +1,167 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// pathlength.go -- average shortest path length, exact or sampled.
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// PathLengthOptions are options for AveragePathLength.
+//
+// The zero value selects an exact computation with a single worker.
+type PathLengthOptions struct {
+	// Samples is the number of source nodes to sample.  Zero, or a number
+	// not less than the graph order, means all nodes are sources and the
+	// result is exact.
+	Samples int
+
+	// Rand chooses sampled source nodes.  If nil, the rand package default
+	// shared source is used.  Otherwise the sample is determined by Rand.
+	Rand *rand.Rand
+
+	// Workers is the number of goroutines running searches concurrently.
+	// Values less than 1 mean 1.  Results do not depend on Workers.
+	Workers int
+
+	// BinWidth is the width of histogram bins for weighted distances.
+	// Zero means 1.  It is ignored for unweighted graphs.
+	BinWidth float64
+}
+
+// AveragePathLength computes the average shortest path length of g.
+//
+// The average is over ordered pairs of distinct nodes (u, v) where v is
+// reachable from u, of the number of arcs in a shortest path from u to v.
+// A breadth first search is run from each source node u.  Sources are all
+// nodes of g or a random sample of them as selected by opt.  Searches are
+// run concurrently as described at PathLengthOptions.
+//
+// Returned hist is the distribution of pairwise distances, where hist[d]
+// is the number of pairs at distance d.  For sampled sources the counts
+// are of pairs from sampled sources; they are not scaled.  Returned exact
+// is true if all nodes were sources.
+//
+// Pairs where v is not reachable from u are excluded from mean and hist.
+// Returned unreachable is the fraction of pairs from sources that are not
+// reachable.  If no pair is reachable, mean is NaN.
+func (g AdjacencyList) AveragePathLength(opt PathLengthOptions) (mean float64, hist []int64, exact bool, unreachable float64) {
+	return avgPathLength(len(g), opt, func() func(NI, func(float64)) {
+		dist := make([]int, len(g))
+		var queue []NI
+		return func(start NI, pair func(float64)) {
+			for i := range dist {
+				dist[i] = -1
+			}
+			dist[start] = 0
+			queue = append(queue[:0], start)
+			for len(queue) > 0 {
+				v := queue[0]
+				queue = queue[1:]
+				for _, to := range g[v] {
+					if dist[to] < 0 {
+						dist[to] = dist[v] + 1
+						queue = append(queue, to)
+						pair(float64(dist[to]))
+					}
+				}
+			}
+		}
+	}, 1)
+}
+
+// AveragePathLength computes the average shortest path length of g, where
+// length is the sum of arc weights of a shortest path.
+//
+// Dijkstra's algorithm is run from each source, so arc weights must be
+// non-negative.  Returned hist[i] is the number of pairs at distance d
+// where i = floor(d/opt.BinWidth).  See AdjacencyList.AveragePathLength.
+func (g LabeledAdjacencyList) AveragePathLength(w WeightFunc, opt PathLengthOptions) (mean float64, hist []int64, exact bool, unreachable float64) {
+	bin := opt.BinWidth
+	if bin == 0 {
+		bin = 1
+	}
+	return avgPathLength(len(g), opt, func() func(NI, func(float64)) {
+		return func(start NI, pair func(float64)) {
+			f, _, dist, _ := g.Dijkstra(start, -1, w)
+			for n, p := range f.Paths {
+				if p.Len > 0 && NI(n) != start {
+					pair(dist[n])
+				}
+			}
+		}
+	}, bin)
+}
+
+// avgPathLength implements AveragePathLength for graphs of order n.
+//
+// newSearch is called once per worker to allocate a search function with
+// its own state.  The search function calls pair with the distance of each
+// node other than start reachable from start.
+func avgPathLength(n int, opt PathLengthOptions, newSearch func() func(NI, func(float64)), bin float64) (mean float64, hist []int64, exact bool, unreachable float64) {
+	src := make([]NI, n)
+	for i := range src {
+		src[i] = NI(i)
+	}
+	exact = opt.Samples <= 0 || opt.Samples >= n
+	if !exact {
+		perm := rand.Perm
+		if opt.Rand != nil {
+			perm = opt.Rand.Perm
+		}
+		for i, p := range perm(n)[:opt.Samples] {
+			src[i] = NI(p)
+		}
+		src = src[:opt.Samples]
+	}
+	// per source results, summed in source order for a result independent
+	// of workers
+	sum := make([]float64, len(src))
+	reached := make([]int, len(src))
+	var mu sync.Mutex
+	var hists []*[]int64 // histogram of each worker
+	forEachSource(len(src), opt.Workers, func() func(NI) {
+		search := newSearch()
+		h := &[]int64{}
+		mu.Lock()
+		hists = append(hists, h)
+		mu.Unlock()
+		return func(i NI) {
+			search(src[i], func(d float64) {
+				sum[i] += d
+				reached[i]++
+				b := int(d / bin)
+				for b >= len(*h) {
+					*h = append(*h, 0)
+				}
+				(*h)[b]++
+			})
+		}
+	})
+	s, r := 0., 0
+	for i := range src {
+		s += sum[i]
+		r += reached[i]
+	}
+	for _, h := range hists {
+		h := *h
+		for len(hist) < len(h) {
+			hist = append(hist, 0)
+		}
+		for d, c := range h {
+			hist[d] += c
+		}
+	}
+	mean = math.NaN()
+	if r > 0 {
+		mean = s / float64(r)
+	}
+	if pairs := len(src) * (n - 1); pairs > 0 {
+		unreachable = float64(pairs-r) / float64(pairs)
+	}
+	return
+}
@@ -0,0 +1,147 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleAdjacencyList_AveragePathLength() {
+	// 0--1--2--3
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	mean, hist, exact, unreachable := g.AveragePathLength(
+		graph.PathLengthOptions{})
+	fmt.Printf("%.4f %v %t %g\n", mean, hist, exact, unreachable)
+	// Output:
+	// 1.6667 [0 6 4 2] true 0
+}
+
+func ExampleLabeledAdjacencyList_AveragePathLength() {
+	// 0--1--2, weights 1.5 and 2
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 0)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	wt := []float64{1.5, 2}
+	mean, hist, _, _ := g.AveragePathLength(
+		func(l graph.LI) float64 { return wt[l] }, graph.PathLengthOptions{})
+	fmt.Printf("%.4f %v\n", mean, hist)
+	// Output:
+	// 2.3333 [0 2 2 2]
+}
+
+func TestAveragePathLengthHand(t *testing.T) {
+	var cycle, star, two graph.Undirected
+	for i := 0; i < 6; i++ {
+		cycle.AddEdge(graph.NI(i), graph.NI((i+1)%6))
+	}
+	for i := 1; i <= 4; i++ {
+		star.AddEdge(0, graph.NI(i))
+	}
+	two.AddEdge(0, 1)
+	two.AddEdge(2, 3)
+	two.AddEdge(3, 4)
+	for _, c := range []struct {
+		name        string
+		g           graph.Undirected
+		mean        float64
+		hist        []int64
+		unreachable float64
+	}{
+		{"cycle", cycle, 1.8, []int64{0, 12, 12, 6}, 0},
+		{"star", star, 1.6, []int64{0, 8, 12}, 0},
+		{"two components", two, 1.25, []int64{0, 6, 2}, .6},
+	} {
+		mean, hist, exact, u := c.g.AveragePathLength(
+			graph.PathLengthOptions{Workers: 2})
+		if math.Abs(mean-c.mean) > 1e-12 || !reflect.DeepEqual(hist, c.hist) ||
+			!exact || math.Abs(u-c.unreachable) > 1e-12 {
+			t.Fatal(c.name, mean, hist, exact, u)
+		}
+	}
+	// no pairs
+	mean, hist, _, u := graph.AdjacencyList{nil}.AveragePathLength(
+		graph.PathLengthOptions{})
+	if !math.IsNaN(mean) || hist != nil || u != 0 {
+		t.Fatal("order 1:", mean, hist, u)
+	}
+}
+
+// TestAveragePathLengthSampled checks that sampled estimates converge to
+// exact values.
+func TestAveragePathLengthSampled(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	g := graph.GnmUndirected(1000, 1500, r) // not connected
+	exact, _, _, exactU := g.AveragePathLength(
+		graph.PathLengthOptions{Workers: 4})
+	prev := math.Inf(1)
+	for _, s := range []int{10, 100, 500} {
+		// average error over several samples
+		var e, eu float64
+		const trials = 5
+		for i := 0; i < trials; i++ {
+			mean, _, isExact, u := g.AveragePathLength(graph.PathLengthOptions{
+				Samples: s,
+				Rand:    r,
+				Workers: 3,
+			})
+			if isExact {
+				t.Fatal("sampled result reported exact")
+			}
+			e += math.Abs(mean - exact)
+			eu += math.Abs(u - exactU)
+		}
+		e /= trials
+		eu /= trials
+		if e/exact > 50/float64(s) || eu > 5/math.Sqrt(float64(s)) {
+			t.Fatal(s, "samples: error", e, eu)
+		}
+		if e > prev*2 {
+			t.Fatal(s, "samples: error", e, "not converging, previous", prev)
+		}
+		prev = e
+	}
+}
+
+func TestAveragePathLengthDeterministic(t *testing.T) {
+	g := graph.GnmUndirected(200, 400, rand.New(rand.NewSource(3)))
+	lg := make(graph.LabeledAdjacencyList, g.Order())
+	var wt []float64
+	for fr, to := range g.AdjacencyList {
+		for _, to := range to {
+			lg[fr] = append(lg[fr], graph.Half{to, graph.LI(len(wt))})
+			wt = append(wt, 1)
+		}
+	}
+	w := func(l graph.LI) float64 { return wt[l] }
+	var first []interface{}
+	for workers := 1; workers <= 4; workers++ {
+		o := graph.PathLengthOptions{
+			Samples: 50,
+			Rand:    rand.New(rand.NewSource(9)),
+			Workers: workers,
+		}
+		m, h, _, u := g.AveragePathLength(o)
+		o.Rand = rand.New(rand.NewSource(9))
+		lm, lh, _, lu := lg.AveragePathLength(w, o)
+		// unit weights give the unweighted result
+		if lm != m || !reflect.DeepEqual(lh, h) || lu != u {
+			t.Fatal("weighted", lm, lh, lu, "unweighted", m, h, u)
+		}
+		res := []interface{}{m, h, u}
+		if first == nil {
+			first = res
+		} else if !reflect.DeepEqual(res, first) {
+			t.Fatal(workers, "workers:", res, "1 worker:", first)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// poset.go -- directed acyclic graphs as partial orders.
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/soniakeys/bits"
+)
+
+// MaximalElements returns the maximal elements of the partial order
+// represented by directed acyclic graph g.
+//
+// An arc fr->to represents fr < to.  The maximal elements are the nodes with
+// no out-arcs, the sinks of g, which are the same whether or not g is
+// transitively closed or reduced.  Nodes are returned in increasing order.
+func (g Directed) MaximalElements() (max []NI) {
+	for n, to := range g.AdjacencyList {
+		if len(to) == 0 {
+			max = append(max, NI(n))
+		}
+	}
+	return
+}
+
+// MinimalElements returns the minimal elements of the partial order
+// represented by directed acyclic graph g.
+//
+// An arc fr->to represents fr < to.  The minimal elements are the nodes with
+// no in-arcs, the sources of g.  Nodes are returned in increasing order.
+func (g Directed) MinimalElements() (min []NI) {
+	in := g.InDegree()
+	for n, d := range in {
+		if d == 0 {
+			min = append(min, NI(n))
+		}
+	}
+	return
+}
+
+// CoveringRelation returns the Hasse diagram of the partial order represented
+// by directed acyclic graph g.
+//
+// Graph g need not be transitively closed or reduced.  The result has an arc
+// fr->to for each pair where to covers fr, that is, where fr < to with no
+// element between.  The covering relation of a finite partial order is the
+// transitive reduction of any DAG representing it, and this method simply
+// calls TransitiveReduction.  An error is returned if g is cyclic.
+func (g Directed) CoveringRelation() (Directed, error) {
+	return g.TransitiveReduction()
+}
+
+// LinearExtensionSample returns a random linear extension of the partial
+// order represented by directed acyclic graph g.
+//
+// A linear extension is a topological ordering.  The method is Kahn's
+// algorithm choosing uniformly at random among the available minimal
+// elements at each step.  Every linear extension has nonzero probability
+// but the distribution is not uniform in general.  For example for the order
+// with a < b and an incomparable element c, the extension c a b has
+// probability 1/2 while a b c and a c b have probability 1/4 each.
+//
+// If g is cyclic, nil is returned.
+//
+// If Rand r is nil, the rand package default shared source is used.
+// Otherwise the result is determined by r.
+func (g Directed) LinearExtensionSample(r *rand.Rand) []NI {
+	ri := rand.Intn
+	if r != nil {
+		ri = r.Intn
+	}
+	a := g.AdjacencyList
+	rem := g.InDegree()
+	var s []NI
+	for n, d := range rem {
+		if d == 0 {
+			s = append(s, NI(n))
+		}
+	}
+	ordering := make([]NI, 0, len(a))
+	for len(s) > 0 {
+		i := ri(len(s))
+		n := s[i]
+		last := len(s) - 1
+		s[i] = s[last]
+		s = s[:last]
+		ordering = append(ordering, n)
+		for _, to := range a[n] {
+			if rem[to]--; rem[to] == 0 {
+				s = append(s, to)
+			}
+		}
+	}
+	if len(ordering) < len(a) {
+		return nil
+	}
+	return ordering
+}
+
+// Poset is a reachability index over a directed acyclic graph, supporting
+// queries of the partial order it represents.
+//
+// An arc fr->to of G represents fr < to.  Reach[n] holds the nodes reachable
+// from n by one or more arcs, the elements greater than n.
+type Poset struct {
+	G     Directed
+	Reach []bits.Bits
+}
+
+// Poset constructs the reachability index of directed acyclic graph g.
+//
+// An error is returned if g is cyclic.  Memory is O(n^2) bits for a graph of
+// order n.
+func (g Directed) Poset() (*Poset, error) {
+	if _, cycle := g.Topological(); cycle != nil {
+		return nil, fmt.Errorf("graph is cyclic: %v", cycle)
+	}
+	if len(g.AdjacencyList) == 0 {
+		return &Poset{G: g}, nil
+	}
+	return &Poset{g, g.TransitiveClosure()}, nil
+}
+
+// Less returns true if u < v in the partial order, that is, if v is reachable
+// from u.
+func (p *Poset) Less(u, v NI) bool {
+	return p.Reach[u].Bit(int(v)) == 1
+}
+
+// Comparable returns true if u and v are comparable in the partial order,
+// that is, if u == v, u < v, or v < u.
+func (p *Poset) Comparable(u, v NI) bool {
+	return u == v || p.Less(u, v) || p.Less(v, u)
+}
+
+// MinimumChainCover returns a minimum number of chains partitioning the
+// elements of the partial order.
+//
+// Each chain is returned as a list of elements in increasing order.  By
+// Dilworth's theorem the number of chains equals the size of a maximum
+// antichain, as returned by MaximumAntichain.
+//
+// The algorithm is Fulkerson's reduction to maximum bipartite matching on
+// the comparability relation, with matching by augmenting paths.  Time is
+// O(n^3) for a poset of n elements.
+func (p *Poset) MinimumChainCover() [][]NI {
+	next, prev := p.match()
+	var chains [][]NI
+	for n, pr := range prev {
+		if pr >= 0 {
+			continue
+		}
+		var c []NI
+		for x := NI(n); x >= 0; x = next[x] {
+			c = append(c, x)
+		}
+		chains = append(chains, c)
+	}
+	return chains
+}
+
+// MaximumAntichain returns a maximum set of pairwise incomparable elements
+// of the partial order.
+//
+// Elements are returned in increasing order.  The antichain is found from
+// the maximum matching used by MinimumChainCover by König's theorem.
+// Time is O(n^3) for a poset of n elements.
+func (p *Poset) MaximumAntichain() (ac []NI) {
+	n := len(p.Reach)
+	next, prev := p.match()
+	// alternating search from unmatched left vertices.  zl, zr are the
+	// left and right vertices reached.
+	zl := bits.New(n)
+	zr := bits.New(n)
+	var visit func(NI)
+	visit = func(u NI) {
+		zl.SetBit(int(u), 1)
+		p.Reach[u].IterateOnes(func(v int) bool {
+			if zr.Bit(v) == 0 {
+				zr.SetBit(v, 1)
+				if w := prev[v]; w >= 0 && zl.Bit(int(w)) == 0 {
+					visit(w)
+				}
+			}
+			return true
+		})
+	}
+	for u, v := range next {
+		if v < 0 && zl.Bit(u) == 0 {
+			visit(NI(u))
+		}
+	}
+	// the minimum vertex cover is left vertices not reached and right
+	// vertices reached.  elements with neither copy in the cover form
+	// the antichain.
+	for x := 0; x < n; x++ {
+		if zl.Bit(x) == 1 && zr.Bit(x) == 0 {
+			ac = append(ac, NI(x))
+		}
+	}
+	return
+}
+
+// match computes a maximum matching of the bipartite graph with an edge
+// u-v for each pair u < v.  next[u] is the element matched to u as its
+// successor, prev[v] the element matched to v as its predecessor, -1 if none.
+func (p *Poset) match() (next, prev []NI) {
+	n := len(p.Reach)
+	next = make([]NI, n)
+	prev = make([]NI, n)
+	for i := range next {
+		next[i] = -1
+		prev[i] = -1
+	}
+	seen := bits.New(n)
+	var augment func(NI) bool
+	augment = func(u NI) (found bool) {
+		p.Reach[u].IterateOnes(func(v int) bool {
+			if seen.Bit(v) == 1 {
+				return true
+			}
+			seen.SetBit(v, 1)
+			if prev[v] < 0 || augment(prev[v]) {
+				next[u] = NI(v)
+				prev[v] = u
+				found = true
+				return false
+			}
+			return true
+		})
+		return
+	}
+	for u := range next {
+		seen.ClearAll()
+		augment(NI(u))
+	}
+	return
+}
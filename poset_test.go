@@ -0,0 +1,144 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/graphtest"
+)
+
+// boolean lattice of subsets of a 4-set, with arcs for all proper subsets,
+// not just covers.
+func booleanLattice() graph.Directed {
+	g := graph.Directed{make(graph.AdjacencyList, 16)}
+	for s := graph.NI(0); s < 16; s++ {
+		for t := graph.NI(0); t < 16; t++ {
+			if s != t && s&t == s {
+				g.AdjacencyList[s] = append(g.AdjacencyList[s], t)
+			}
+		}
+	}
+	return g
+}
+
+func ExamplePoset_MaximumAntichain() {
+	//   0 < 1 < 3
+	//   0 < 2
+	//   4 < 3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {3},
+		4: {3},
+	}}
+	p, err := g.Poset()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("minimal:", g.MinimalElements())
+	fmt.Println("maximal:", g.MaximalElements())
+	fmt.Println("antichain:", p.MaximumAntichain())
+	fmt.Println("chains:", p.MinimumChainCover())
+	// Output:
+	// minimal: [0 4]
+	// maximal: [2 3]
+	// antichain: [1 2 4]
+	// chains: [[0 1 3] [2] [4]]
+}
+
+func TestPosetBooleanLattice(t *testing.T) {
+	g := booleanLattice()
+	if max := g.MaximalElements(); len(max) != 1 || max[0] != 15 {
+		t.Fatal("maximal", max)
+	}
+	if min := g.MinimalElements(); len(min) != 1 || min[0] != 0 {
+		t.Fatal("minimal", min)
+	}
+	h, err := g.CoveringRelation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// covers add exactly one element
+	for s, to := range h.AdjacencyList {
+		if len(to) != 4-bits.OnesCount(uint(s)) {
+			t.Fatal(s, "covers", to)
+		}
+		for _, u := range to {
+			if d := uint(u) &^ uint(s); bits.OnesCount(d) != 1 || u&graph.NI(s) != graph.NI(s) {
+				t.Fatal(s, "covered by", u)
+			}
+		}
+	}
+	// covering relation of the covering relation is itself
+	if h2, _ := h.CoveringRelation(); !h2.Equal(h.AdjacencyList) {
+		t.Fatal("covering relation not idempotent")
+	}
+	p, err := g.Poset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ph, _ := h.Poset()
+	for u := graph.NI(0); u < 16; u++ {
+		for v := graph.NI(0); v < 16; v++ {
+			want := u&v == u || u&v == v
+			if p.Comparable(u, v) != want || ph.Comparable(u, v) != want {
+				t.Fatal("comparable", u, v, "want", want)
+			}
+		}
+	}
+	// Dilworth:  width of the lattice is C(4,2) = 6
+	ac := p.MaximumAntichain()
+	if len(ac) != 6 {
+		t.Fatal("antichain", ac)
+	}
+	for i, u := range ac {
+		for _, v := range ac[:i] {
+			if p.Comparable(u, v) {
+				t.Fatal("antichain", ac, "has comparable", u, v)
+			}
+		}
+	}
+	chains := p.MinimumChainCover()
+	if len(chains) != 6 {
+		t.Fatal("chains", chains)
+	}
+	seen := map[graph.NI]bool{}
+	for _, c := range chains {
+		for i, u := range c {
+			if seen[u] {
+				t.Fatal("chains", chains, "repeat", u)
+			}
+			seen[u] = true
+			if i > 0 && !p.Less(c[i-1], u) {
+				t.Fatal("chain", c, "not increasing")
+			}
+		}
+	}
+	if len(seen) != 16 {
+		t.Fatal("chains", chains, "do not cover")
+	}
+}
+
+func TestLinearExtensionSample(t *testing.T) {
+	g := booleanLattice()
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		o := g.LinearExtensionSample(r)
+		if err := graphtest.CheckTopologicalOrder(g, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.AdjacencyList[15] = []graph.NI{0}
+	if o := g.LinearExtensionSample(r); o != nil {
+		t.Fatal("cyclic graph gave", o)
+	}
+	if _, err := g.Poset(); err == nil {
+		t.Fatal("cyclic graph accepted")
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// postman.go -- minimum weight Eulerian completion, the augmentation step
+// of the Chinese postman problem.
+
+import (
+	"fmt"
+	"math"
+	mbits "math/bits"
+
+	"github.com/soniakeys/bits"
+)
+
+// EulerianCompletionMaxOdd is the maximum number of odd degree nodes
+// supported by LabeledUndirected.EulerianCompletion.
+//
+// Time and memory of the matching step are exponential in the number of odd
+// degree nodes.
+const EulerianCompletionMaxOdd = 22
+
+// EulerianCompletion finds a minimum weight set of edges to duplicate to
+// make g Eulerian.
+//
+// Edge weights are given by w and must be non-negative.  Nodes of odd degree
+// are paired by a minimum weight perfect matching over shortest path
+// distances, and the edges of a shortest path between each pair are
+// duplicated.  Returned addedEdges lists the edges to add, each a copy of an
+// edge of g with its label.  An edge may be listed more than once.  Cost is
+// the total weight of addedEdges.  After adding the edges, as with AddEdge,
+// g will have an Eulerian cycle.
+//
+// An error is returned if edges of g are in more than one connected
+// component, naming a node of each of two such components, or if g has more
+// than EulerianCompletionMaxOdd nodes of odd degree.  Isolated nodes are
+// allowed.
+func (g LabeledUndirected) EulerianCompletion(w WeightFunc) (addedEdges []LabeledEdge, cost float64, err error) {
+	a := g.LabeledAdjacencyList
+	reps, _, arcSizes := g.ConnectedComponentReps()
+	r1 := NI(-1)
+	for i, r := range reps {
+		if arcSizes[i] == 0 {
+			continue
+		}
+		if r1 >= 0 {
+			return nil, 0, fmt.Errorf(
+				"nodes %d and %d are in disconnected components", r1, r)
+		}
+		r1 = r
+	}
+	var odd []NI
+	for n := range a {
+		if g.Degree(NI(n))%2 == 1 {
+			odd = append(odd, NI(n))
+		}
+	}
+	if len(odd) == 0 {
+		return nil, 0, nil
+	}
+	if len(odd) > EulerianCompletionMaxOdd {
+		return nil, 0, fmt.Errorf("%d odd degree nodes, maximum %d",
+			len(odd), EulerianCompletionMaxOdd)
+	}
+	// shortest paths from each odd node
+	targets := bits.New(len(a))
+	for _, n := range odd {
+		targets.SetBit(int(n), 1)
+	}
+	fs := make([]FromList, len(odd))
+	ls := make([][]LI, len(odd))
+	d := make([][]float64, len(odd))
+	for i, n := range odd {
+		f, l, dist, _ := a.DijkstraTargets(n, targets, w)
+		fs[i], ls[i] = f, l
+		d[i] = make([]float64, len(odd))
+		for j, o := range odd {
+			d[i][j] = dist[o]
+		}
+	}
+	for _, p := range minPerfectMatching(d) {
+		i, j := p[0], p[1]
+		path := fs[i].PathToLabeled(odd[j], ls[i], nil)
+		fr := path.Start
+		for _, h := range path.Path {
+			addedEdges = append(addedEdges, LabeledEdge{Edge{fr, h.To}, h.Label})
+			cost += w(h.Label)
+			fr = h.To
+		}
+	}
+	return
+}
+
+// minPerfectMatching returns a minimum weight perfect matching of a
+// complete graph of an even number of nodes with edge weights d.
+//
+// The algorithm is dynamic programming over subsets of nodes remaining to be
+// matched, in time O(2^k k) for k nodes.
+func minPerfectMatching(d [][]float64) (pairs [][2]int) {
+	k := len(d)
+	full := 1<<uint(k) - 1
+	best := make([]float64, full+1)
+	mate := make([]int8, full+1) // mate of the lowest node in the subset
+	for s := 1; s <= full; s++ {
+		if mbits.OnesCount(uint(s))%2 == 1 {
+			continue
+		}
+		i := mbits.TrailingZeros(uint(s))
+		best[s] = math.Inf(1)
+		for r := s &^ (1 << uint(i)); r > 0; r &= r - 1 {
+			j := mbits.TrailingZeros(uint(r))
+			if c := best[s&^(1<<uint(i)|1<<uint(j))] + d[i][j]; c < best[s] {
+				best[s] = c
+				mate[s] = int8(j)
+			}
+		}
+	}
+	for s := full; s > 0; {
+		i := mbits.TrailingZeros(uint(s))
+		j := int(mate[s])
+		pairs = append(pairs, [2]int{i, j})
+		s &^= 1<<uint(i) | 1<<uint(j)
+	}
+	return
+}
+
+// EulerianCompletion finds a minimum weight set of arcs to duplicate to
+// make g Eulerian.
+//
+// Arc weights are given by w and must be non-negative.  Nodes with excess
+// in-degree must be the starts of added paths and nodes with excess
+// out-degree must be the ends.  The pairing is found as a transportation
+// problem over shortest path distances, solved by successive shortest
+// augmenting paths, and the arcs of a shortest path between each pair are
+// duplicated once for each unit of flow between them.  Returned addedArcs
+// lists the arcs to add, each as the from and to nodes and label of an arc
+// of g.  An arc may be listed more than once.  Cost is the total weight of
+// addedArcs.  After adding the arcs, g will have an Eulerian cycle.
+//
+// An error is returned if arcs of g are in more than one strongly connected
+// component, naming a node of each of two such components.  Isolated nodes
+// are allowed.
+func (g LabeledDirected) EulerianCompletion(w WeightFunc) (addedArcs []LabeledEdge, cost float64, err error) {
+	a := g.LabeledAdjacencyList
+	ind := g.InDegree()
+	_, _, cond := g.CondensationMap()
+	n1 := NI(-1)
+	for n, to := range a {
+		if len(to) == 0 && ind[n] == 0 {
+			continue
+		}
+		switch {
+		case n1 < 0:
+			n1 = NI(n)
+		case cond[n] != cond[n1]:
+			return nil, 0, fmt.Errorf(
+				"nodes %d and %d are not strongly connected", n1, n)
+		}
+	}
+	// sources have excess in-degree, sinks excess out-degree
+	var src, snk []NI
+	var supply, demand []int
+	targets := bits.New(len(a))
+	for n, to := range a {
+		switch {
+		case ind[n] > len(to):
+			src = append(src, NI(n))
+			supply = append(supply, ind[n]-len(to))
+		case len(to) > ind[n]:
+			snk = append(snk, NI(n))
+			demand = append(demand, len(to)-ind[n])
+			targets.SetBit(n, 1)
+		}
+	}
+	if len(src) == 0 {
+		return nil, 0, nil
+	}
+	fs := make([]FromList, len(src))
+	ls := make([][]LI, len(src))
+	c := make([][]float64, len(src))
+	for i, n := range src {
+		f, l, dist, _ := a.DijkstraTargets(n, targets, w)
+		fs[i], ls[i] = f, l
+		c[i] = make([]float64, len(snk))
+		for j, t := range snk {
+			c[i][j] = dist[t]
+		}
+	}
+	flow := minCostTransport(supply, demand, c)
+	for i, fi := range flow {
+		for j, f := range fi {
+			if f == 0 {
+				continue
+			}
+			path := fs[i].PathToLabeled(snk[j], ls[i], nil)
+			for ; f > 0; f-- {
+				fr := path.Start
+				for _, h := range path.Path {
+					addedArcs = append(addedArcs,
+						LabeledEdge{Edge{fr, h.To}, h.Label})
+					cost += w(h.Label)
+					fr = h.To
+				}
+			}
+		}
+	}
+	return
+}
+
+// minCostTransport solves a balanced transportation problem.
+//
+// Supply and demand give amounts at sources and sinks, with equal totals.
+// Cost c[i][j] is the unit cost of shipping from source i to sink j.
+// Returned is flow[i][j], the amount shipped from source i to sink j at
+// minimum total cost.
+//
+// The algorithm is successive shortest paths in the residual network, with
+// Bellman-Ford used to find each path since reverse arcs have negative cost.
+func minCostTransport(supply, demand []int, c [][]float64) (flow [][]int) {
+	ns, nt := len(supply), len(demand)
+	flow = make([][]int, ns)
+	for i := range flow {
+		flow[i] = make([]int, nt)
+	}
+	rs := append([]int{}, supply...)
+	rd := append([]int{}, demand...)
+	// residual network nodes are sources 0..ns-1 and sinks ns..ns+nt-1.
+	// paths start at any source with remaining supply and end at any sink
+	// with remaining demand.
+	dist := make([]float64, ns+nt)
+	from := make([]int, ns+nt)
+	for {
+		for i := range dist {
+			dist[i] = math.Inf(1)
+			from[i] = -1
+		}
+		for i, s := range rs {
+			if s > 0 {
+				dist[i] = 0
+			}
+		}
+		for changed := true; changed; {
+			changed = false
+			for i := 0; i < ns; i++ {
+				if math.IsInf(dist[i], 1) {
+					continue
+				}
+				for j := 0; j < nt; j++ {
+					if d := dist[i] + c[i][j]; d < dist[ns+j] {
+						dist[ns+j] = d
+						from[ns+j] = i
+						changed = true
+					}
+				}
+			}
+			for j := 0; j < nt; j++ {
+				if math.IsInf(dist[ns+j], 1) {
+					continue
+				}
+				for i := 0; i < ns; i++ {
+					if flow[i][j] == 0 {
+						continue
+					}
+					if d := dist[ns+j] - c[i][j]; d < dist[i] {
+						dist[i] = d
+						from[i] = ns + j
+						changed = true
+					}
+				}
+			}
+		}
+		t := -1
+		for j, r := range rd {
+			if r > 0 && (t < 0 || dist[ns+j] < dist[ns+t]) {
+				t = j
+			}
+		}
+		if t < 0 || math.IsInf(dist[ns+t], 1) {
+			return
+		}
+		// find bottleneck, then augment
+		amt := rd[t]
+		x := ns + t
+		for from[x] >= 0 {
+			p := from[x]
+			if x < ns { // reverse arc from sink p to source x
+				if f := flow[x][p-ns]; f < amt {
+					amt = f
+				}
+			}
+			x = p
+		}
+		if rs[x] < amt {
+			amt = rs[x]
+		}
+		rs[x] -= amt
+		rd[t] -= amt
+		for x = ns + t; from[x] >= 0; x = from[x] {
+			p := from[x]
+			if x < ns {
+				flow[x][p-ns] -= amt
+			} else {
+				flow[p][x-ns] += amt
+			}
+		}
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledUndirected_EulerianCompletion() {
+	// weights in parens:
+	//      (1)      (5)
+	//   0 ----- 1 ----- 2
+	//   |       |       |
+	//  (2)     (1)     (1)
+	//   |       |       |
+	//   3 ----- 4 ----- 5
+	//      (1)      (1)
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 1)
+	g.AddEdge(graph.Edge{1, 2}, 5)
+	g.AddEdge(graph.Edge{0, 3}, 2)
+	g.AddEdge(graph.Edge{1, 4}, 1)
+	g.AddEdge(graph.Edge{2, 5}, 1)
+	g.AddEdge(graph.Edge{3, 4}, 1)
+	g.AddEdge(graph.Edge{4, 5}, 1)
+	w := func(l graph.LI) float64 { return float64(l) }
+	added, cost, err := g.EulerianCompletion(w)
+	fmt.Println(added, cost, err)
+	for _, e := range added {
+		g.AddEdge(e.Edge, e.LI)
+	}
+	fmt.Println(g.Eulerian())
+	// Output:
+	// [{{1 4} 1}] 1 <nil>
+	// -1 -1 <nil>
+}
+
+func ExampleLabeledDirected_EulerianCompletion() {
+	// weights in parens:
+	//      (1)
+	//   0 ----> 1
+	//   ^     / ^
+	//  (1) (1)  (4)
+	//   | v     |
+	//   2 ----> 3
+	//      (1)
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}},
+		1: {{To: 2, Label: 1}},
+		2: {{To: 0, Label: 1}, {To: 3, Label: 1}},
+		3: {{To: 1, Label: 4}},
+	}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	added, cost, err := g.EulerianCompletion(w)
+	fmt.Println(added, cost, err)
+	for _, e := range added {
+		g.AddArc(e.N1, graph.Half{e.N2, e.LI})
+	}
+	fmt.Println(g.Eulerian())
+	// Output:
+	// [{{1 2} 1}] 1 <nil>
+	// -1 -1 <nil>
+}
+
+func ExampleLabeledUndirected_EulerianCompletion_disconnected() {
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 0)
+	g.AddEdge(graph.Edge{3, 4}, 0)
+	g.AddEdge(graph.Edge{2, 2}, 0)
+	_, _, err := g.EulerianCompletion(func(graph.LI) float64 { return 1 })
+	fmt.Println(err)
+	// Output:
+	// nodes 0 and 2 are in disconnected components
+}
+
+// bruteUndirectedCompletion finds the minimum cost of duplicating a subset
+// of edges of g so all degrees are even.
+func bruteUndirectedCompletion(edges []graph.LabeledEdge, n int, w graph.WeightFunc) float64 {
+	best := math.Inf(1)
+	for s := 0; s < 1<<uint(len(edges)); s++ {
+		deg := make([]int, n)
+		c := 0.
+		for x, e := range edges {
+			k := 1 + s>>uint(x)&1
+			deg[e.N1] += k
+			deg[e.N2] += k
+			if k == 2 {
+				c += w(e.LI)
+			}
+		}
+		ok := true
+		for _, d := range deg {
+			if d%2 == 1 {
+				ok = false
+				break
+			}
+		}
+		if ok && c < best {
+			best = c
+		}
+	}
+	return best
+}
+
+func TestLabeledUndirectedEulerianCompletion(t *testing.T) {
+	r := rand.New(rand.NewSource(44))
+	wt := make([]float64, 10)
+	for i := range wt {
+		wt[i] = float64(r.Intn(10))
+	}
+	w := func(l graph.LI) float64 { return wt[l] }
+	for i := 0; i < 300; i++ {
+		n := 2 + r.Intn(5)
+		var g graph.LabeledUndirected
+		var edges []graph.LabeledEdge
+		// spanning path keeps g connected
+		for fr := 1; fr < n; fr++ {
+			edges = append(edges, graph.LabeledEdge{
+				graph.Edge{graph.NI(r.Intn(fr)), graph.NI(fr)},
+				graph.LI(r.Intn(len(wt)))})
+		}
+		for m := r.Intn(6); m > 0; m-- {
+			edges = append(edges, graph.LabeledEdge{
+				graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(r.Intn(len(wt)))})
+		}
+		for _, e := range edges {
+			g.AddEdge(e.Edge, e.LI)
+		}
+		added, cost, err := g.EulerianCompletion(w)
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if want := bruteUndirectedCompletion(edges, n, w); cost != want {
+			t.Fatal(i, "cost", cost, "want", want)
+		}
+		c := 0.
+		for _, e := range added {
+			if ok, _, _ := g.HasEdgeLabel(e.N1, e.N2, e.LI); !ok {
+				t.Fatal(i, "added edge", e, "not in graph")
+			}
+			c += w(e.LI)
+			g.AddEdge(e.Edge, e.LI)
+		}
+		if c != cost {
+			t.Fatal(i, "added edges cost", c, "returned", cost)
+		}
+		if s, e, err := g.Eulerian(); s != -1 || e != -1 || err != nil {
+			t.Fatal(i, "not Eulerian", s, e, err)
+		}
+	}
+}
+
+// bruteDirectedCompletion finds the minimum cost of duplicating arcs of g,
+// each up to max times, so all nodes are balanced.
+func bruteDirectedCompletion(arcs []graph.LabeledEdge, n, max int, w graph.WeightFunc) float64 {
+	best := math.Inf(1)
+	k := make([]int, len(arcs))
+	var f func(x int)
+	f = func(x int) {
+		if x < len(arcs) {
+			for k[x] = 0; k[x] <= max; k[x]++ {
+				f(x + 1)
+			}
+			return
+		}
+		bal := make([]int, n)
+		c := 0.
+		for x, e := range arcs {
+			bal[e.N1] += 1 + k[x]
+			bal[e.N2] -= 1 + k[x]
+			c += float64(k[x]) * w(e.LI)
+		}
+		for _, b := range bal {
+			if b != 0 {
+				return
+			}
+		}
+		if c < best {
+			best = c
+		}
+	}
+	f(0)
+	return best
+}
+
+func TestLabeledDirectedEulerianCompletion(t *testing.T) {
+	r := rand.New(rand.NewSource(45))
+	wt := make([]float64, 10)
+	for i := range wt {
+		wt[i] = float64(r.Intn(10))
+	}
+	w := func(l graph.LI) float64 { return wt[l] }
+	for i := 0; i < 300; i++ {
+		n := 2 + r.Intn(3)
+		g := graph.LabeledDirected{make(graph.LabeledAdjacencyList, n)}
+		var arcs []graph.LabeledEdge
+		// a cycle through all nodes keeps g strongly connected
+		for fr := 0; fr < n; fr++ {
+			arcs = append(arcs, graph.LabeledEdge{
+				graph.Edge{graph.NI(fr), graph.NI((fr + 1) % n)},
+				graph.LI(r.Intn(len(wt)))})
+		}
+		for m := r.Intn(4); m > 0; m-- {
+			arcs = append(arcs, graph.LabeledEdge{
+				graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(r.Intn(len(wt)))})
+		}
+		for _, e := range arcs {
+			g.AddArc(e.N1, graph.Half{e.N2, e.LI})
+		}
+		// an optimal completion duplicates no arc more than the total
+		// imbalance.
+		ind := g.InDegree()
+		max := 0
+		for n, to := range g.LabeledAdjacencyList {
+			if d := len(to) - ind[n]; d > 0 {
+				max += d
+			}
+		}
+		added, cost, err := g.EulerianCompletion(w)
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if want := bruteDirectedCompletion(arcs, n, max, w); cost != want {
+			t.Fatal(i, "cost", cost, "want", want)
+		}
+		c := 0.
+		for _, e := range added {
+			if ok, _ := g.HasArcLabel(e.N1, e.N2, e.LI); !ok {
+				t.Fatal(i, "added arc", e, "not in graph")
+			}
+			c += w(e.LI)
+			g.AddArc(e.N1, graph.Half{e.N2, e.LI})
+		}
+		if c != cost {
+			t.Fatal(i, "added arcs cost", c, "returned", cost)
+		}
+		if s, e, err := g.Eulerian(); s != -1 || e != -1 || err != nil {
+			t.Fatal(i, "not Eulerian", s, e, err)
+		}
+	}
+}
+
+func TestLabeledDirectedEulerianCompletionDisconnected(t *testing.T) {
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1}},
+		1: {{To: 0}, {To: 2}},
+		2: {{To: 3}},
+		3: {{To: 2}},
+		4: {},
+	}}
+	_, _, err := g.EulerianCompletion(func(graph.LI) float64 { return 1 })
+	if err == nil || err.Error() != "nodes 0 and 2 are not strongly connected" {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// quotient.go -- contraction of node sets into a quotient graph.
+
+// PartitionInts returns partition ids for the nodes of a graph of the given
+// order, as taken by QuotientGraph.
+//
+// Nodes in sets[i] get id i.  Nodes in no set get id -1.  If a node is in
+// more than one set, the last set listing it determines its id.
+func PartitionInts(order int, sets [][]NI) (part []int) {
+	part = make([]int, order)
+	for i := range part {
+		part[i] = -1
+	}
+	for i, s := range sets {
+		for _, n := range s {
+			part[n] = i
+		}
+	}
+	return
+}
+
+// QuotientGraph contracts each set of nodes with the same partition id into
+// a single node.
+//
+// Argument part gives a partition id for each node of g, for example as
+// returned by WeaklyConnectedComponentInts or PartitionInts.  Ids need not
+// be consecutive.  Nodes with a negative id are excluded from the result
+// along with their arcs.
+//
+// Each arc fr->to of g becomes an arc between the nodes containing fr and to.
+// Arcs between nodes of the same set become loops.  If simple is true, loops
+// are dropped and parallel arcs are merged into a single arc.  Otherwise all
+// arcs are kept.
+//
+// Nodes of q are numbered in order of their lowest member node.  Returned
+// members lists the nodes of g contracted into each node of q, in
+// increasing order.
+func (g Directed) QuotientGraph(part []int, simple bool) (q Directed, members [][]NI) {
+	qn, members := quotientNodes(part)
+	a := make(AdjacencyList, len(members))
+	for fr, to := range g.AdjacencyList {
+		qf := qn[fr]
+		if qf < 0 {
+			continue
+		}
+		for _, to := range to {
+			if qt := qn[to]; qt >= 0 {
+				a[qf] = append(a[qf], qt)
+			}
+		}
+	}
+	if simple {
+		a.SimplifyInPlace()
+	}
+	return Directed{a}, members
+}
+
+// QuotientGraph contracts each set of nodes with the same partition id into
+// a single node.
+//
+// Arcs keep their labels.  If simple is true, loops are dropped and parallel
+// arcs are merged into a single arc with the label chosen by keep, as
+// described at LabeledAdjacencyList.Simplify.  See Directed.QuotientGraph.
+func (g LabeledDirected) QuotientGraph(part []int, simple bool, keep ParallelPolicy) (q LabeledDirected, members [][]NI) {
+	qn, members := quotientNodes(part)
+	a := make(LabeledAdjacencyList, len(members))
+	for fr, to := range g.LabeledAdjacencyList {
+		qf := qn[fr]
+		if qf < 0 {
+			continue
+		}
+		for _, to := range to {
+			if qt := qn[to.To]; qt >= 0 {
+				a[qf] = append(a[qf], Half{To: qt, Label: to.Label})
+			}
+		}
+	}
+	if simple {
+		a.SimplifyInPlace(keep)
+	}
+	return LabeledDirected{a}, members
+}
+
+// quotientNodes numbers the distinct non-negative ids of part in order of
+// first appearance.  It returns the quotient node of each node, -1 for
+// negative ids, and the members of each quotient node.
+func quotientNodes(part []int) (qn []NI, members [][]NI) {
+	qn = make([]NI, len(part))
+	ids := map[int]NI{}
+	for n, id := range part {
+		if id < 0 {
+			qn[n] = -1
+			continue
+		}
+		q, ok := ids[id]
+		if !ok {
+			q = NI(len(members))
+			ids[id] = q
+			members = append(members, nil)
+		}
+		qn[n] = q
+		members[q] = append(members[q], NI(n))
+	}
+	return
+}
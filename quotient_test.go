@@ -0,0 +1,85 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDirected_QuotientGraph() {
+	// 0 -> 1 -> 2 -> 3
+	//  \-------^
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {3},
+		3: {},
+	}}
+	part := graph.PartitionInts(4, [][]graph.NI{{0, 1}, {2, 3}})
+	q, members := g.QuotientGraph(part, false)
+	fmt.Println(q.AdjacencyList, members)
+	q, _ = g.QuotientGraph(part, true)
+	fmt.Println(q.AdjacencyList)
+	// Output:
+	// [[0 1 1] [1]] [[0 1] [2 3]]
+	// [[1] []]
+}
+
+func ExampleLabeledDirected_QuotientGraph() {
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{2, 5}, {3, 7}},
+		1: {{2, 4}},
+		2: {},
+		3: {},
+	}}
+	sum := func(ls []graph.LI) (s graph.LI) {
+		for _, l := range ls {
+			s += l
+		}
+		return
+	}
+	q, members := g.QuotientGraph([]int{10, 10, 20, 20}, true, sum)
+	fmt.Println(q.LabeledAdjacencyList, members)
+	// Output:
+	// [[{1 16}] []] [[0 1] [2 3]]
+}
+
+// TestQuotientGraph checks that contracting strongly connected components
+// gives the condensation.
+func TestQuotientGraph(t *testing.T) {
+	r := rand.New(rand.NewSource(19))
+	for i := 0; i < 20; i++ {
+		g := graph.GnmDirected(30, 45, r)
+		scc, cd, cond := g.CondensationMap()
+		part := make([]int, len(cond))
+		for n, c := range cond {
+			part[n] = int(c)
+		}
+		q, members := g.QuotientGraph(part, true)
+		if len(members) != len(scc) {
+			t.Fatal("members", len(members), "scc", len(scc))
+		}
+		// map quotient nodes to condensation nodes
+		qc := make([]graph.NI, len(members))
+		for qn, m := range members {
+			qc[qn] = cond[m[0]]
+			if len(m) != len(scc[qc[qn]]) {
+				t.Fatal("members", m, "scc", scc[qc[qn]])
+			}
+		}
+		a := make(graph.AdjacencyList, len(cd))
+		for qn, to := range q.AdjacencyList {
+			for _, to := range to {
+				a[qc[qn]] = append(a[qc[qn]], qc[to])
+			}
+		}
+		if !a.Equal(cd) {
+			t.Fatal("quotient", a, "condensation", cd)
+		}
+	}
+}
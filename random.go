@@ -7,6 +7,7 @@ import (
 	"errors"
 	"math"
 	"math/rand"
+	"sort"
 
 	"github.com/soniakeys/bits"
 )
@@ -27,6 +28,8 @@ import (
 // Also returned is the actual size m of constructed graph g.
 //
 // If Rand r is nil, the rand package default shared source is used.
+// Draws from r choose which node pairs are considered and which of those
+// are kept, so the same w and a Rand with the same seed give the same graph.
 func ChungLu(w []float64, rr *rand.Rand) (g Undirected, m int) {
 	// Ref: "Efficient Generation of Networks with Given Expected Degrees"
 	// Joel C. Miller and Aric Hagberg
@@ -87,6 +90,9 @@ func ChungLu(w []float64, rr *rand.Rand) (g Undirected, m int) {
 // patience given that the returned graph must be simple.
 //
 // If Rand r is nil, the rand package default shared source is used.
+// Positions, candidate arcs, and affinity tests all draw from r, so a Rand
+// with the same seed and the same arguments gives the same positions, the
+// same graph, and the same error if patience runs out.
 //
 // Returned is a directed simple graph and associated positions indexed by
 // node number.  In the arc list for each node, to-nodes are in random
@@ -218,6 +224,8 @@ arc:
 // ordered.  Consider using ShuffleArcLists if random order is important.
 //
 // If Rand r is nil, the rand package default shared source is used.
+// Only node positions are drawn from r.  Edges follow from the positions,
+// so a Rand with the same seed gives the same graph.
 //
 // See also LabeledGeometric.
 func Geometric(nNodes int, radius float64, rr *rand.Rand) (g Undirected, pos []struct{ X, Y float64 }, m int) {
@@ -295,6 +303,9 @@ func LabeledGeometric(nNodes int, radius float64, rr *rand.Rand) (g LabeledUndir
 // Argument n is number of nodes, m is number of edges and must be <= n(n-1)/2.
 //
 // If Rand r is nil, the rand package default shared source is used.
+// Edges selected with r are collected in a set but added to arc lists in
+// order of edge number, not set iteration order, so a Rand with the same
+// seed gives the same graph.
 //
 // In the generated arc list for each node, to-nodes are ordered.
 // Consider using ShuffleArcLists if random order is important.
@@ -334,7 +345,7 @@ func GnmUndirected(n, m int, rr *rand.Rand) Undirected {
 			}
 		}
 	} else {
-		for i := range e {
+		for _, i := range sortedKeys(e) {
 			v := 1 + int(math.Sqrt(.25+float64(2*i))-.5)
 			w := i - (v * (v - 1) / 2)
 			a[v] = append(a[v], NI(w))
@@ -352,6 +363,8 @@ func GnmUndirected(n, m int, rr *rand.Rand) Undirected {
 // Argument n is number of nodes, ma is number of arcs and must be <= n(n-1).
 //
 // If Rand r is nil, the rand package default shared source is used.
+// As with GnmUndirected, selected arcs are added in order of arc number,
+// so a Rand with the same seed gives the same graph.
 //
 // In the generated arc list for each node, to-nodes are ordered.
 // Consider using ShuffleArcLists if random order is important.
@@ -393,7 +406,7 @@ func GnmDirected(n, ma int, rr *rand.Rand) Directed {
 			}
 		}
 	} else {
-		for i := range e {
+		for _, i := range sortedKeys(e) {
 			v := i / (n - 1)
 			w := i % (n - 1)
 			if w >= v {
@@ -405,6 +418,19 @@ func GnmDirected(n, ma int, rr *rand.Rand) Directed {
 	return Directed{a}
 }
 
+// sortedKeys returns the keys of e in increasing order.
+//
+// Iterating the keys in order, rather than in map order, keeps generated
+// graphs deterministic for a given Rand.
+func sortedKeys(e map[int]struct{}) []int {
+	k := make([]int, 0, len(e))
+	for i := range e {
+		k = append(k, i)
+	}
+	sort.Ints(k)
+	return k
+}
+
 // Gnm3Undirected constructs a random simple undirected graph.
 //
 // Construction is by the Erdős–Rényi model where the specified number of
@@ -413,6 +439,8 @@ func GnmDirected(n, ma int, rr *rand.Rand) Directed {
 // Argument n is number of nodes, m is number of edges and must be <= n(n-1)/2.
 //
 // If Rand r is nil, the rand package default shared source is used.
+// Exactly m values are drawn from r, one per edge, so a Rand with the same
+// seed gives the same edges, appended in the same order.
 //
 // In the generated arc list for each node, to-nodes are ordered.
 // Consider using ShuffleArcLists if random order is important.
@@ -463,6 +491,8 @@ func Gnm3Undirected(n, m int, rr *rand.Rand) Undirected {
 // Argument n is number of nodes, ma is number of arcs and must be <= n(n-1).
 //
 // If Rand r is nil, the rand package default shared source is used.
+// Exactly ma values are drawn from r, one per arc, so a Rand with the same
+// seed gives the same arcs, appended in the same order.
 //
 // In the generated arc list for each node, to-nodes are ordered.
 // Consider using ShuffleArcLists if random order is important.
@@ -513,6 +543,9 @@ func Gnm3Directed(n, ma int, rr *rand.Rand) Directed {
 // Argument n is number of nodes, p is probability for selecting an edge.
 //
 // If Rand r is nil, the rand package default shared source is used.
+// Rather than testing each possible edge, the generator draws from r the
+// gap to the next selected edge, about one draw per edge.  A Rand with the
+// same seed gives the same graph.
 //
 // In the generated arc list for each node, to-nodes are ordered.
 // Consider using ShuffleArcLists if random order is important.
@@ -520,8 +553,39 @@ func Gnm3Directed(n, ma int, rr *rand.Rand) Directed {
 // Also returned is the actual size m of constructed graph g.
 func GnpUndirected(n int, p float64, rr *rand.Rand) (g Undirected, m int) {
 	a := make(AdjacencyList, n)
+	gnpUndirected(n, p, rr, func(v, w NI) {
+		a[v] = append(a[v], w)
+		a[w] = append(a[w], v)
+		m++
+	})
+	return Undirected{a}, m
+}
+
+// LabeledGnpUndirected constructs a random simple labeled undirected graph.
+//
+// The graph is constructed as by GnpUndirected, and with the same Rand
+// produces the same edges in the same order.  Edges are labeled 0 through
+// m-1 in the order they are generated, with both arcs of an edge having the
+// same label.  Labels can thus be used directly as indexes into a weight
+// table of size m.
+//
+// If Rand r is nil, the rand package default shared source is used.
+//
+// Also returned is the actual size m of constructed graph g.
+func LabeledGnpUndirected(n int, p float64, rr *rand.Rand) (g LabeledUndirected, m int) {
+	a := make(LabeledAdjacencyList, n)
+	gnpUndirected(n, p, rr, func(v, w NI) {
+		a[v] = append(a[v], Half{w, LI(m)})
+		a[w] = append(a[w], Half{v, LI(m)})
+		m++
+	})
+	return LabeledUndirected{a}, m
+}
+
+// gnpUndirected calls edge for each edge v, w of a Gnp graph, v > w.
+func gnpUndirected(n int, p float64, rr *rand.Rand, edge func(v, w NI)) {
 	if n < 2 {
-		return Undirected{a}, 0
+		return
 	}
 	rf := rand.Float64
 	if rr != nil {
@@ -536,9 +600,7 @@ g:
 		w += 1 + NI(c*math.Log(1-rf()))
 		for {
 			if w < v {
-				a[v] = append(a[v], w)
-				a[w] = append(a[w], v)
-				m++
+				edge(v, w)
 				continue g
 			}
 			w -= v
@@ -548,7 +610,6 @@ g:
 			}
 		}
 	}
-	return Undirected{a}, m
 }
 
 // GnpDirected constructs a random simple directed graph.
@@ -560,6 +621,8 @@ g:
 // Argument n is number of nodes, p is probability for selecting an arc.
 //
 // If Rand r is nil, the rand package default shared source is used.
+// As with GnpUndirected, gaps between selected arcs are drawn from r, and a
+// Rand with the same seed gives the same graph.
 //
 // In the generated arc list for each node, to-nodes are ordered.
 // Consider using ShuffleArcLists if random order is important.
@@ -567,8 +630,36 @@ g:
 // Also returned is the actual arc size m of constructed graph g.
 func GnpDirected(n int, p float64, rr *rand.Rand) (g Directed, ma int) {
 	a := make(AdjacencyList, n)
+	gnpDirected(n, p, rr, func(v, w NI) {
+		a[v] = append(a[v], w)
+		ma++
+	})
+	return Directed{a}, ma
+}
+
+// LabeledGnpDirected constructs a random simple labeled directed graph.
+//
+// The graph is constructed as by GnpDirected, and with the same Rand
+// produces the same arcs in the same order.  Arcs are labeled 0 through ma-1
+// in the order they are generated.  Labels can thus be used directly as
+// indexes into a weight table of size ma.
+//
+// If Rand r is nil, the rand package default shared source is used.
+//
+// Also returned is the actual arc size ma of constructed graph g.
+func LabeledGnpDirected(n int, p float64, rr *rand.Rand) (g LabeledDirected, ma int) {
+	a := make(LabeledAdjacencyList, n)
+	gnpDirected(n, p, rr, func(v, w NI) {
+		a[v] = append(a[v], Half{w, LI(ma)})
+		ma++
+	})
+	return LabeledDirected{a}, ma
+}
+
+// gnpDirected calls arc for each arc v->w of a Gnp graph.
+func gnpDirected(n int, p float64, rr *rand.Rand, arc func(v, w NI)) {
 	if n < 2 {
-		return Directed{a}, 0
+		return
 	}
 	rf := rand.Float64
 	if rr != nil {
@@ -586,8 +677,7 @@ g:
 				w++
 			}
 			if w < NI(n) {
-				a[v] = append(a[v], w)
-				ma++
+				arc(v, w)
 				continue g
 			}
 			v++
@@ -596,7 +686,6 @@ g:
 			}
 		}
 	}
-	return Directed{a}, ma
 }
 
 // KroneckerDirected generates a Kronecker-like random directed graph.
@@ -609,6 +698,8 @@ g:
 // order.
 //
 // If Rand r is nil, the rand package default shared source is used.
+// The random order of arc lists is also drawn from r, so a Rand with the
+// same seed reproduces arc list order as well as the arcs.
 //
 // Return value ma is the number of arcs retained in the result graph.
 func KroneckerDirected(scale uint, arcFactor float64, rr *rand.Rand) (g Directed, ma int) {
@@ -626,6 +717,8 @@ func KroneckerDirected(scale uint, arcFactor float64, rr *rand.Rand) (g Directed
 // order.
 //
 // If Rand r is nil, the rand package default shared source is used.
+// Edges, their order in arc lists, and the shuffled node numbers are all
+// drawn from r, so a Rand with the same seed gives the same graph.
 //
 // Return value m is the true number of edges--not arcs--retained in the result
 // graph.
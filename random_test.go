@@ -6,6 +6,7 @@ package graph_test
 import (
 	"fmt"
 	"math/rand"
+	"reflect"
 	"testing"
 	"time"
 
@@ -319,3 +320,117 @@ func TestChungLu(t *testing.T) {
 		t.Fatal("ChungLu returned non-simple graph")
 	}
 }
+
+func ExampleLabeledGnpDirected() {
+	g, ma := graph.LabeledGnpDirected(5, .3, rand.New(rand.NewSource(3)))
+	fmt.Println("arcs:", ma)
+	for fr, to := range g.LabeledAdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// arcs: 4
+	// 0 [{3 0}]
+	// 1 [{2 1}]
+	// 2 []
+	// 3 [{0 2}]
+	// 4 [{0 3}]
+}
+
+func ExampleLabeledGnpUndirected() {
+	g, m := graph.LabeledGnpUndirected(5, .4, rand.New(rand.NewSource(3)))
+	fmt.Println("edges:", m)
+	for fr, to := range g.LabeledAdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// edges: 2
+	// 0 []
+	// 1 [{2 0}]
+	// 2 [{1 0} {3 1}]
+	// 3 [{2 1}]
+	// 4 []
+}
+
+func TestGnmPinned(t *testing.T) {
+	// fixed seeds produce fixed graphs, with ordered to-lists
+	u := graph.GnmUndirected(6, 5, rand.New(rand.NewSource(7)))
+	want := graph.AdjacencyList{{1, 3}, {0, 2, 5}, {1, 4}, {0}, {2}, {1}}
+	if !reflect.DeepEqual(u.AdjacencyList, want) {
+		t.Fatal(u.AdjacencyList)
+	}
+	d := graph.GnmDirected(5, 6, rand.New(rand.NewSource(7)))
+	want = graph.AdjacencyList{{4}, {3}, {0, 3}, {0, 1}, nil}
+	if !reflect.DeepEqual(d.AdjacencyList, want) {
+		t.Fatal(d.AdjacencyList)
+	}
+}
+
+func TestGeneratorsDeterministic(t *testing.T) {
+	gen := map[string]func(r *rand.Rand) interface{}{
+		"GnmUndirected": func(r *rand.Rand) interface{} {
+			return graph.GnmUndirected(50, 200, r)
+		},
+		"GnmDirected": func(r *rand.Rand) interface{} {
+			return graph.GnmDirected(50, 1800, r)
+		},
+		"Gnm3Undirected": func(r *rand.Rand) interface{} {
+			return graph.Gnm3Undirected(50, 200, r)
+		},
+		"GnpDirected": func(r *rand.Rand) interface{} {
+			g, _ := graph.GnpDirected(50, .1, r)
+			return g
+		},
+		"Geometric": func(r *rand.Rand) interface{} {
+			g, _, _ := graph.Geometric(50, .2, r)
+			return g
+		},
+		"KroneckerUndirected": func(r *rand.Rand) interface{} {
+			g, _ := graph.KroneckerUndirected(6, 4, r)
+			return g
+		},
+	}
+	for name, f := range gen {
+		g1 := f(rand.New(rand.NewSource(11)))
+		g2 := f(rand.New(rand.NewSource(11)))
+		if !reflect.DeepEqual(g1, g2) {
+			t.Fatal(name, "not deterministic")
+		}
+	}
+}
+
+func TestLabeledGnp(t *testing.T) {
+	// same structure as unlabeled, labels in creation order
+	lu, m := graph.LabeledGnpUndirected(30, .2, rand.New(rand.NewSource(5)))
+	u, um := graph.GnpUndirected(30, .2, rand.New(rand.NewSource(5)))
+	if m != um || !reflect.DeepEqual(lu.Unlabeled(), u.AdjacencyList) {
+		t.Fatal("undirected structure differs")
+	}
+	seen := make([]int, m)
+	for fr, to := range lu.LabeledAdjacencyList {
+		for _, h := range to {
+			seen[h.Label]++
+			if has, _, _ := lu.HasEdgeLabel(h.To, graph.NI(fr), h.Label); !has {
+				t.Fatal("no reciprocal with label", h.Label)
+			}
+		}
+	}
+	for l, c := range seen {
+		if c != 2 {
+			t.Fatal("label", l, "on", c, "arcs")
+		}
+	}
+	ld, ma := graph.LabeledGnpDirected(30, .2, rand.New(rand.NewSource(5)))
+	d, dma := graph.GnpDirected(30, .2, rand.New(rand.NewSource(5)))
+	if ma != dma || !reflect.DeepEqual(ld.Unlabeled(), d) {
+		t.Fatal("directed structure differs")
+	}
+	l := graph.LI(0)
+	for _, to := range ld.LabeledAdjacencyList {
+		for _, h := range to {
+			if h.Label != l {
+				t.Fatal("label", h.Label, "want", l)
+			}
+			l++
+		}
+	}
+}
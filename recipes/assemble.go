@@ -0,0 +1,78 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package recipes
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/soniakeys/graph"
+)
+
+// AssembleReads assembles a sequence from reads by finding an Eulerian path
+// in a de Bruijn graph of k-mers.
+//
+// The distinct k-mers, substrings of length k, of all reads are collected.
+// Each k-mer is an arc from its prefix (k-1)-mer to its suffix (k-1)-mer.
+// The assembled sequence spells an Eulerian path through this graph,
+// obtained with graph.LabeledDirected.EulerianPath.  Reads shorter than k
+// contribute no k-mers.  Reads are taken as byte strings.
+//
+// The result is deterministic.  Nodes are numbered in sorted order of
+// (k-1)-mers and arcs are added in sorted order of k-mers.  Where the
+// sequence is a cycle, the assembly starts at the least (k-1)-mer.
+//
+// An error is returned if k < 2, if there are no k-mers, or if the k-mers do
+// not form a single Eulerian path, as happens for example with insufficient
+// coverage or when k is too small to resolve repeats.
+func AssembleReads(reads []string, k int) (string, error) {
+	if k < 2 {
+		return "", fmt.Errorf("recipes: k = %d, must be at least 2", k)
+	}
+	km := map[string]bool{}
+	for _, r := range reads {
+		for i := 0; i+k <= len(r); i++ {
+			km[r[i:i+k]] = true
+		}
+	}
+	if len(km) == 0 {
+		return "", errors.New("recipes: no k-mers in reads")
+	}
+	kmers := make([]string, 0, len(km))
+	nodes := map[string]graph.NI{}
+	var names []string
+	for s := range km {
+		kmers = append(kmers, s)
+		for _, n := range []string{s[:k-1], s[1:]} {
+			if _, ok := nodes[n]; !ok {
+				nodes[n] = 0
+				names = append(names, n)
+			}
+		}
+	}
+	sort.Strings(kmers)
+	sort.Strings(names)
+	for i, n := range names {
+		nodes[n] = graph.NI(i)
+	}
+	a := make(graph.LabeledAdjacencyList, len(names))
+	for i, s := range kmers {
+		fr := nodes[s[:k-1]]
+		a[fr] = append(a[fr], graph.Half{To: nodes[s[1:]], Label: graph.LI(i)})
+	}
+	g := graph.LabeledDirected{a}
+	p, err := g.EulerianPath()
+	if err != nil {
+		return "", fmt.Errorf("recipes: k-mers do not assemble: %v", err)
+	}
+	if len(p) != len(kmers)+1 {
+		return "", errors.New("recipes: k-mers do not assemble: graph not connected")
+	}
+	b := []byte(names[p[0].To])
+	for _, h := range p[1:] {
+		b = append(b, kmers[h.Label][k-1])
+	}
+	return string(b), nil
+}
@@ -0,0 +1,229 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package recipes_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/recipes"
+)
+
+// routing fixture:  labels hold an 8 bit length and a 2 bit road class.
+var schema = graph.LabelSchema{
+	{Name: "length", Offset: 0, Width: 8},
+	{Name: "class", Offset: 8, Width: 2},
+}
+
+func lab(length, class int) graph.LI { return graph.LI(class<<8 | length) }
+
+func ExampleRouteWithProfiles() {
+	//      (10, class 1)
+	//   0 ---------------> 1
+	//   |                  |
+	//   | (4, class 0)     | (4, class 0)
+	//   v                  v
+	//   2 ---------------> 3
+	//      (12, class 0)
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: lab(10, 1)}, {To: 2, Label: lab(4, 0)}},
+		1: {{To: 3, Label: lab(4, 0)}},
+		2: {{To: 3, Label: lab(12, 0)}},
+		3: {},
+	}}
+	for _, profile := range []string{
+		"length",                           // shortest
+		"class == 1 ? length * 3 : length", // avoid class 1
+	} {
+		p, d, err := recipes.RouteWithProfiles(g, schema, profile, 0, 3)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(p.Start, p.Path, d)
+	}
+	// Output:
+	// 0 [{1 266} {3 4}] 14
+	// 0 [{2 4} {3 12}] 16
+}
+
+func TestRouter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	d, _ := graph.GnpDirected(60, .08, rnd)
+	g := graph.LabeledDirected{make(graph.LabeledAdjacencyList, d.Order())}
+	for fr, to := range d.AdjacencyList {
+		for _, to := range to {
+			g.LabeledAdjacencyList[fr] = append(g.LabeledAdjacencyList[fr],
+				graph.Half{To: to, Label: lab(1+rnd.Intn(50), rnd.Intn(3))})
+		}
+	}
+	const profile = "class == 2 ? length * 2 : length + class"
+	w, _ := schema.Compile(profile)
+	r, err := recipes.NewRouter(g, schema, profile,
+		&recipes.RouteOptions{Landmarks: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for s := graph.NI(0); s < 60; s += 7 {
+		f, _, dist, _ := g.Dijkstra(s, -1, w)
+		for e := graph.NI(0); e < 60; e++ {
+			p, pd, err := r.Route(s, e)
+			if f.Paths[e].Len == 0 {
+				if err != recipes.ErrNoPath {
+					t.Fatal(s, e, "expected ErrNoPath, got", err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatal(s, e, err)
+			}
+			if pd != dist[e] || p.Distance(w) != pd {
+				t.Fatal(s, e, "distance", pd, "want", dist[e])
+			}
+		}
+	}
+	// errors
+	if _, _, err := recipes.RouteWithProfiles(g, schema, "length +", 0, 1); err == nil {
+		t.Fatal("expected compile error")
+	}
+	if _, _, err := recipes.RouteWithProfiles(g, schema, "-length", 0, 1); err == nil {
+		t.Fatal("expected negative weight error")
+	}
+	if _, _, err := r.Route(0, 60); err == nil {
+		t.Fatal("expected range error")
+	}
+	bad := graph.LabeledDirected{graph.LabeledAdjacencyList{{{To: 0, Label: 1 << 12}}}}
+	if _, _, err := recipes.RouteWithProfiles(bad, schema, "length", 0, 0); err == nil {
+		t.Fatal("expected schema error")
+	}
+}
+
+func ExampleAssembleReads() {
+	reads := []string{"GATTAC", "TTACAG", "ACAGGC", "AGGCTT"}
+	s, err := recipes.AssembleReads(reads, 4)
+	fmt.Println(s, err)
+	// Output:
+	// GATTACAGGCTT <nil>
+}
+
+func TestAssembleReads(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	seq := make([]byte, 300)
+	for i := range seq {
+		seq[i] = "ACGT"[rnd.Intn(4)]
+	}
+	var reads []string
+	for i := 0; i+40 <= len(seq); i += 13 {
+		reads = append(reads, string(seq[i:i+40]))
+	}
+	reads = append(reads, string(seq[len(seq)-40:]))
+	rnd.Shuffle(len(reads), func(i, j int) { reads[i], reads[j] = reads[j], reads[i] })
+	s, err := recipes.AssembleReads(reads, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != string(seq) {
+		t.Fatal("assembled\n", s, "\nwant\n", string(seq))
+	}
+	// errors
+	if _, err := recipes.AssembleReads(reads, 1); err == nil {
+		t.Fatal("expected error for k = 1")
+	}
+	if _, err := recipes.AssembleReads([]string{"AC"}, 3); err == nil {
+		t.Fatal("expected error for no k-mers")
+	}
+	if _, err := recipes.AssembleReads([]string{"AAAC", "GGGT"}, 3); err == nil {
+		t.Fatal("expected error for disconnected reads")
+	}
+}
+
+func ExampleScheduleTasks() {
+	// task 0 precedes 1 and 2, which both precede 3.
+	dag := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {3},
+		2: {3},
+		3: {},
+		4: {},
+	}}
+	dur := []float64{2, 3, 1, 2, 4}
+	s, err := recipes.ScheduleTasks(dag, dur, 2)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("start:  ", s.Start)
+	fmt.Println("machine:", s.Machine)
+	fmt.Println("makespan:", s.Makespan, "lower bound:", s.LowerBound)
+	// Output:
+	// start:   [0 2 4 5 0]
+	// machine: [0 0 1 0 1]
+	// makespan: 7 lower bound: 7
+}
+
+func TestScheduleTasks(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	// random DAG, arcs from lower to higher nodes
+	const n = 40
+	dag := graph.Directed{make(graph.AdjacencyList, n)}
+	dur := make([]float64, n)
+	for fr := 0; fr < n; fr++ {
+		dur[fr] = float64(1 + rnd.Intn(9))
+		for to := fr + 1; to < n; to++ {
+			if rnd.Intn(10) == 0 {
+				dag.AdjacencyList[fr] = append(dag.AdjacencyList[fr], graph.NI(to))
+			}
+		}
+	}
+	const m = 3
+	s, err := recipes.ScheduleTasks(dag, dur, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Makespan < s.LowerBound {
+		t.Fatal("makespan", s.Makespan, "below bound", s.LowerBound)
+	}
+	// precedence respected
+	for fr, to := range dag.AdjacencyList {
+		for _, to := range to {
+			if s.Start[to] < s.Start[fr]+dur[fr] {
+				t.Fatal("task", to, "starts before", fr, "finishes")
+			}
+		}
+	}
+	// no machine runs two tasks at once
+	for a := 0; a < n; a++ {
+		for b := a + 1; b < n; b++ {
+			if s.Machine[a] == s.Machine[b] &&
+				math.Max(s.Start[a], s.Start[b]) <
+					math.Min(s.Start[a]+dur[a], s.Start[b]+dur[b]) {
+				t.Fatal("tasks", a, b, "overlap on machine", s.Machine[a])
+			}
+		}
+	}
+	s2, _ := recipes.ScheduleTasks(dag, dur, m)
+	if !reflect.DeepEqual(s, s2) {
+		t.Fatal("not deterministic")
+	}
+	// errors
+	cyc := graph.Directed{graph.AdjacencyList{{1}, {0}}}
+	for _, tc := range []struct {
+		dag graph.Directed
+		dur []float64
+		m   int
+	}{
+		{cyc, []float64{1, 1}, 1},
+		{dag, dur[1:], 1},
+		{dag, dur, 0},
+		{graph.Directed{graph.AdjacencyList{{}}}, []float64{-1}, 1},
+	} {
+		if _, err := recipes.ScheduleTasks(tc.dag, tc.dur, tc.m); err == nil {
+			t.Fatal("expected error", tc)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+// Package recipes provides small pipelines composed from graph package APIs.
+//
+// Each recipe is a function that can be called directly, or read as a
+// starting point for similar work.  Recipes validate their inputs and return
+// errors rather than panicking.
+package recipes
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/soniakeys/graph"
+)
+
+// ErrNoPath is returned by routing functions when the end node is not
+// reachable from the start node.
+var ErrNoPath = errors.New("recipes: no path")
+
+// DefaultLandmarks is the number of ALT landmarks used when
+// RouteOptions.Landmarks is 0.
+const DefaultLandmarks = 4
+
+// RouteOptions are options for NewRouter.
+type RouteOptions struct {
+	// Landmarks is the number of landmarks for the ALT heuristic.  Zero
+	// means DefaultLandmarks.  Landmarks are limited to the graph order.
+	Landmarks int
+}
+
+// A Router finds shortest routes in a graph with arc weights given by a
+// routing profile.
+//
+// A Router holds landmark distances for the ALT heuristic, computed once by
+// NewRouter and reused by each call to Route.  A Router is safe for
+// concurrent use as long as the graph is not modified.
+type Router struct {
+	g          graph.LabeledDirected
+	w          graph.WeightFunc
+	landmarks  []graph.NI
+	from, to   [][]float64 // from[i][n]: landmark i to n; to[i][n]: n to i
+	fromR, toR [][]bool    // reachability for from, to
+}
+
+// NewRouter validates graph g against schema, compiles profile, and
+// precomputes landmark distances.
+//
+// Argument profile is an expression over the fields of schema as accepted
+// by graph.LabelSchema.Compile.  An error is returned if labels of g do not
+// fit the schema, if the profile does not compile, or if the profile gives
+// a negative or NaN weight for any arc of g.
+//
+// Landmarks are chosen by farthest point selection:  Starting from node 0,
+// each next landmark is a node farthest from the landmarks already chosen.
+func NewRouter(g graph.LabeledDirected, schema graph.LabelSchema, profile string, opt *RouteOptions) (*Router, error) {
+	if err := schema.Validate(g.LabeledAdjacencyList); err != nil {
+		return nil, err
+	}
+	w, err := schema.Compile(profile)
+	if err != nil {
+		return nil, err
+	}
+	for fr, to := range g.LabeledAdjacencyList {
+		for _, to := range to {
+			if x := w(to.Label); !(x >= 0) {
+				return nil, fmt.Errorf("recipes: profile weight %g for arc %d->%d",
+					x, fr, to.To)
+			}
+		}
+	}
+	nl := DefaultLandmarks
+	if opt != nil && opt.Landmarks > 0 {
+		nl = opt.Landmarks
+	}
+	if nl > g.Order() {
+		nl = g.Order()
+	}
+	r := &Router{g: g, w: w}
+	if nl == 0 {
+		return r, nil
+	}
+	tr, _ := g.Transpose()
+	// min distance to any chosen landmark, for farthest point selection
+	near := make([]float64, g.Order())
+	for i := range near {
+		near[i] = math.Inf(1)
+	}
+	next := graph.NI(0)
+	for len(r.landmarks) < nl {
+		r.landmarks = append(r.landmarks, next)
+		from, fromR := sssp(g.LabeledAdjacencyList, next, w)
+		to, toR := sssp(tr.LabeledAdjacencyList, next, w)
+		r.from = append(r.from, from)
+		r.fromR = append(r.fromR, fromR)
+		r.to = append(r.to, to)
+		r.toR = append(r.toR, toR)
+		far := -1.
+		for n := range near {
+			if fromR[n] && from[n] < near[n] {
+				near[n] = from[n]
+			}
+			// prefer unreached nodes, then the farthest.  ties to low NIs.
+			if near[n] > far {
+				far = near[n]
+				next = graph.NI(n)
+			}
+		}
+		if far == 0 {
+			break // all nodes are landmarks
+		}
+	}
+	return r, nil
+}
+
+// sssp returns distances from start and reachability.
+func sssp(g graph.LabeledAdjacencyList, start graph.NI, w graph.WeightFunc) ([]float64, []bool) {
+	f, _, dist, _ := g.Dijkstra(start, -1, w)
+	reached := make([]bool, len(g))
+	for n, p := range f.Paths {
+		reached[n] = p.Len > 0
+	}
+	return dist, reached
+}
+
+// Heuristic returns the ALT heuristic for end node t.
+//
+// The heuristic uses the triangle inequality over landmark distances and is
+// admissible.
+func (r *Router) Heuristic(t graph.NI) graph.Heuristic {
+	return func(n graph.NI) float64 {
+		h := 0.
+		for i := range r.landmarks {
+			// d(n, t) >= d(L, t) - d(L, n)
+			if r.fromR[i][n] && r.fromR[i][t] {
+				h = math.Max(h, r.from[i][t]-r.from[i][n])
+			}
+			// d(n, t) >= d(n, L) - d(t, L)
+			if r.toR[i][n] && r.toR[i][t] {
+				h = math.Max(h, r.to[i][n]-r.to[i][t])
+			}
+		}
+		return h
+	}
+}
+
+// Route finds a shortest route from s to t by A* search with the ALT
+// heuristic.
+//
+// Returned is the path and its distance.  ErrNoPath is returned if t is not
+// reachable from s.
+func (r *Router) Route(s, t graph.NI) (graph.LabeledPath, float64, error) {
+	n := graph.NI(r.g.Order())
+	if s < 0 || s >= n || t < 0 || t >= n {
+		return graph.LabeledPath{}, 0,
+			fmt.Errorf("recipes: route %d->%d out of range, order %d", s, t, n)
+	}
+	f, labels, d, ok := r.g.AStarA(r.w, s, t, r.Heuristic(t))
+	if !ok {
+		return graph.LabeledPath{}, 0, ErrNoPath
+	}
+	return f.PathToLabeled(t, labels, nil), d, nil
+}
+
+// RouteWithProfiles finds a shortest route from s to t in g, with arc weights
+// given by a profile expression over the label fields of schema.
+//
+// It is a convenience function constructing a Router with default options
+// and calling Route once.  To route repeatedly with the same profile, use
+// NewRouter and Router.Route.
+func RouteWithProfiles(g graph.LabeledDirected, schema graph.LabelSchema, profile string, s, t graph.NI) (graph.LabeledPath, float64, error) {
+	r, err := NewRouter(g, schema, profile, nil)
+	if err != nil {
+		return graph.LabeledPath{}, 0, err
+	}
+	return r.Route(s, t)
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package recipes
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/soniakeys/graph"
+)
+
+// A Schedule is a schedule of tasks on identical machines.
+type Schedule struct {
+	Start      []float64 // start time of each task
+	Machine    []int     // machine of each task, from 0 to m-1
+	Makespan   float64   // finish time of the last task
+	LowerBound float64   // lower bound on makespan of any schedule
+	Ordering   []graph.NI
+}
+
+// ScheduleTasks schedules a directed acyclic graph of tasks on m identical
+// machines.
+//
+// Nodes of dag are tasks, arcs are precedence constraints, and dur gives
+// non-negative task durations indexed by node.  The recipe computes a
+// topological ordering with graph.Directed.Topological, priorities with
+// graph.Directed.CriticalPathPriority, and a schedule with
+// graph.Directed.ListSchedule.  Tasks are then assigned to machines in order
+// of start time, each to the lowest numbered free machine.
+//
+// Returned Ordering is the topological ordering used.  LowerBound is from
+// graph.Directed.ScheduleLowerBound and is useful for judging the quality of
+// the schedule.
+//
+// An error is returned if dag has a cycle, if dur does not match the order
+// of dag, if a duration is negative, or if m < 1.
+func ScheduleTasks(dag graph.Directed, dur []float64, m int) (*Schedule, error) {
+	if m < 1 {
+		return nil, fmt.Errorf("recipes: %d machines", m)
+	}
+	if len(dur) != dag.Order() {
+		return nil, fmt.Errorf("recipes: %d durations for %d tasks",
+			len(dur), dag.Order())
+	}
+	for n, d := range dur {
+		if !(d >= 0) {
+			return nil, fmt.Errorf("recipes: task %d duration %g", n, d)
+		}
+	}
+	ord, cycle := dag.Topological()
+	if cycle != nil {
+		return nil, fmt.Errorf("recipes: tasks not acyclic, cycle %v", cycle)
+	}
+	s := &Schedule{Ordering: ord}
+	s.Start, s.Makespan, _ = dag.ListSchedule(ord, dur, m,
+		dag.CriticalPathPriority(ord, dur)) // m checked above
+	s.LowerBound = dag.ScheduleLowerBound(ord, dur, m)
+	// assign machines
+	byStart := append([]graph.NI{}, ord...)
+	sort.SliceStable(byStart, func(i, j int) bool {
+		a, b := byStart[i], byStart[j]
+		if s.Start[a] != s.Start[b] {
+			return s.Start[a] < s.Start[b]
+		}
+		return a < b
+	})
+	free := make([]float64, m)
+	s.Machine = make([]int, len(dur))
+	for _, n := range byStart {
+		for x, f := range free {
+			if f <= s.Start[n] {
+				s.Machine[n] = x
+				free[x] = s.Start[n] + dur[n]
+				break
+			}
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,227 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// reliability.go -- two-terminal reliability under random edge failures.
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// ReliabilityMC estimates the probability that nodes s and t remain connected
+// when edges of g fail independently.
+//
+// Function failProb gives the failure probability of an edge from its label.
+// Loops are ignored.  The estimate is the fraction of sampled failure
+// scenarios in which s and t are connected by surviving edges.  Connectivity
+// of each sample is determined by union-find over surviving edges.
+// Returned is the estimate p and its standard error.  If samples < 1, both
+// are NaN.
+//
+// Samples are divided among the given number of worker goroutines.  With
+// workers < 2, a single worker draws from r.  If Rand r is nil, the rand
+// package default shared source is used.  Otherwise, with a single worker
+// the result is determined by r.  With multiple workers, each worker draws
+// from its own source seeded from r and the result is determined by r and
+// the number of workers.
+//
+// See also ReliabilityIS for low failure probabilities and ReliabilityExact
+// for small graphs.
+func (g LabeledUndirected) ReliabilityMC(s, t NI, failProb func(LI) float64, samples int, r *rand.Rand, workers int) (p, stderr float64) {
+	if s == t {
+		return 1, 0
+	}
+	e, f := g.relEdges(failProb)
+	return relEstimate(g.Order(), samples, r, workers,
+		func(rf func() float64, ds disjointSet) float64 {
+			for i, e := range e {
+				if rf() >= f[i] && ds.union(e.N1, e.N2) &&
+					ds.find(s) == ds.find(t) {
+					return 1
+				}
+			}
+			return 0
+		})
+}
+
+// ReliabilityIS estimates the probability that nodes s and t remain connected
+// when edges of g fail independently, using importance sampling.
+//
+// When failure probabilities are very small, disconnection of s and t is a
+// rare event and ReliabilityMC sees few or no disconnected samples.  Its
+// estimate of the probability of disconnection then has a large relative
+// error.  ReliabilityIS instead samples edge failures with the larger
+// probabilities given by biasProb and weights each disconnected sample
+// by its likelihood ratio, the ratio of its probability under failProb to
+// its probability under biasProb.  The mean weight estimates the probability
+// of disconnection, and p is one minus this estimate.
+//
+// The estimate is unbiased as long as biasProb(l) > 0 wherever
+// failProb(l) > 0, and biasProb(l) < 1 wherever failProb(l) < 1.  Its
+// variance however depends strongly on biasProb.  Biasing failure
+// probabilities too little gives few disconnected samples, as with
+// ReliabilityMC.  Biasing them too much gives likelihood ratios that vary
+// over many orders of magnitude so that the estimate is dominated by a few
+// samples, and the returned standard error may itself be a poor estimate.
+// A reasonable choice is to bias failure probabilities such that a
+// moderate fraction of samples, perhaps 10 to 50 percent, are disconnected.
+//
+// Other arguments and return values are as for ReliabilityMC.
+func (g LabeledUndirected) ReliabilityIS(s, t NI, failProb, biasProb func(LI) float64, samples int, r *rand.Rand, workers int) (p, stderr float64) {
+	if s == t {
+		return 1, 0
+	}
+	e, f := g.relEdges(failProb)
+	_, q := g.relEdges(biasProb)
+	u, stderr := relEstimate(g.Order(), samples, r, workers,
+		func(rf func() float64, ds disjointSet) float64 {
+			lr := 1.
+			for i, e := range e {
+				if rf() < q[i] {
+					lr *= f[i] / q[i]
+					continue
+				}
+				lr *= (1 - f[i]) / (1 - q[i])
+				if ds.union(e.N1, e.N2) && ds.find(s) == ds.find(t) {
+					return 0
+				}
+			}
+			return lr
+		})
+	return 1 - u, stderr
+}
+
+// ReliabilityExact computes the probability that nodes s and t remain
+// connected when edges of g fail independently.
+//
+// Function failProb gives the failure probability of an edge from its label.
+// Loops are ignored.  The algorithm is factoring, or contraction-deletion,
+// which takes time exponential in the number of edges.  An error is returned
+// if g has more than 20 non-loop edges.
+func (g LabeledUndirected) ReliabilityExact(s, t NI, failProb func(LI) float64) (float64, error) {
+	e, f := g.relEdges(failProb)
+	if len(e) > 20 {
+		return 0, errors.New("ReliabilityExact limited to 20 edges")
+	}
+	comp := make([]NI, g.Order())
+	for i := range comp {
+		comp[i] = NI(i)
+	}
+	return relFactor(comp, e, f, s, t), nil
+}
+
+// relFactor returns the probability that s and t are connected by surviving
+// edges e, with failure probabilities f, where nodes are already contracted
+// to the components given by comp.
+func relFactor(comp []NI, e []LabeledEdge, f []float64, s, t NI) float64 {
+	cs, ct := comp[s], comp[t]
+	if cs == ct {
+		return 1
+	}
+	// drop edges within components
+	var re []LabeledEdge
+	var rf []float64
+	for i, ei := range e {
+		if comp[ei.N1] != comp[ei.N2] {
+			re = append(re, ei)
+			rf = append(rf, f[i])
+		}
+	}
+	if len(re) == 0 {
+		return 0
+	}
+	// deleting the first edge
+	p := 0.
+	if rf[0] > 0 {
+		p = rf[0] * relFactor(comp, re[1:], rf[1:], s, t)
+	}
+	if rf[0] < 1 {
+		// contracting the first edge
+		c := append([]NI{}, comp...)
+		from, to := c[re[0].N1], c[re[0].N2]
+		for i, ci := range c {
+			if ci == from {
+				c[i] = to
+			}
+		}
+		p += (1 - rf[0]) * relFactor(c, re[1:], rf[1:], s, t)
+	}
+	return p
+}
+
+// relEdges returns the non-loop edges of g and the probability for each
+// edge given by prob.
+func (g LabeledUndirected) relEdges(prob func(LI) float64) (e []LabeledEdge, p []float64) {
+	g.Edges(func(l LabeledEdge) {
+		if l.N1 != l.N2 {
+			e = append(e, l)
+			p = append(p, prob(l.LI))
+		}
+	})
+	return
+}
+
+// relEstimate returns the mean of sample values and its standard error.
+// Function sample computes one sample value using random source rf and
+// disjoint set ds, which is reset for each sample.
+func relEstimate(n, samples int, r *rand.Rand, workers int, sample func(rf func() float64, ds disjointSet) float64) (mean, stderr float64) {
+	if samples < 1 {
+		return math.NaN(), math.NaN()
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > samples {
+		workers = samples
+	}
+	rf, ri := rand.Float64, rand.Int63
+	if r != nil {
+		rf, ri = r.Float64, r.Int63
+	}
+	srcs := make([]func() float64, workers)
+	if workers == 1 {
+		srcs[0] = rf
+	} else {
+		for w := range srcs {
+			srcs[w] = rand.New(rand.NewSource(ri())).Float64
+		}
+	}
+	sum := make([]float64, workers)
+	sq := make([]float64, workers)
+	var wg sync.WaitGroup
+	for w := range srcs {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			ds := newDisjointSet(n)
+			for i := w; i < samples; i += workers {
+				for x := range ds.set {
+					ds.set[x] = dsElement{from: -1}
+				}
+				y := sample(srcs[w], ds)
+				sum[w] += y
+				sq[w] += y * y
+			}
+		}(w)
+	}
+	wg.Wait()
+	var s, s2 float64
+	for w := range sum {
+		s += sum[w]
+		s2 += sq[w]
+	}
+	N := float64(samples)
+	mean = s / N
+	if samples > 1 {
+		v := (s2 - N*mean*mean) / (N - 1)
+		if v < 0 {
+			v = 0
+		}
+		stderr = math.Sqrt(v / N)
+	}
+	return
+}
@@ -0,0 +1,129 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledUndirected_ReliabilityExact() {
+	// a bridge network.  labels are failure probabilities in percent.
+	//     1
+	//   / | \
+	//  0  |  3
+	//   \ | /
+	//     2
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 10)
+	g.AddEdge(graph.Edge{0, 2}, 10)
+	g.AddEdge(graph.Edge{1, 2}, 10)
+	g.AddEdge(graph.Edge{1, 3}, 10)
+	g.AddEdge(graph.Edge{2, 3}, 10)
+	f := func(l graph.LI) float64 { return float64(l) / 100 }
+	p, err := g.ReliabilityExact(0, 3, f)
+	fmt.Printf("exact:  %.5f %v\n", p, err)
+	p, se := g.ReliabilityMC(0, 3, f, 100000, rand.New(rand.NewSource(1)), 1)
+	fmt.Printf("sample: %.5f ± %.5f\n", p, se)
+	// Output:
+	// exact:  0.97848 <nil>
+	// sample: 0.97866 ± 0.00046
+}
+
+func TestReliabilityClosedForms(t *testing.T) {
+	f := []float64{.1, .25, .5, .05}
+	fp := func(l graph.LI) float64 { return f[l] }
+	// series path 0-1-2-3-4 survives if all edges survive.
+	var series graph.LabeledUndirected
+	want := 1.
+	for i, fi := range f {
+		series.AddEdge(graph.Edge{graph.NI(i), graph.NI(i + 1)}, graph.LI(i))
+		want *= 1 - fi
+	}
+	if p, err := series.ReliabilityExact(0, 4, fp); err != nil ||
+		math.Abs(p-want) > 1e-12 {
+		t.Fatal("series", p, want, err)
+	}
+	// parallel edges 0-1, with a loop, fail only if all edges fail.
+	var parallel graph.LabeledUndirected
+	want = 1.
+	for i, fi := range f {
+		parallel.AddEdge(graph.Edge{0, 1}, graph.LI(i))
+		want *= fi
+	}
+	parallel.AddEdge(graph.Edge{1, 1}, 0)
+	want = 1 - want
+	if p, err := parallel.ReliabilityExact(0, 1, fp); err != nil ||
+		math.Abs(p-want) > 1e-12 {
+		t.Fatal("parallel", p, want, err)
+	}
+	if p, _ := parallel.ReliabilityExact(1, 1, fp); p != 1 {
+		t.Fatal("s == t", p)
+	}
+	// too many edges
+	var big graph.LabeledUndirected
+	for i := 0; i < 21; i++ {
+		big.AddEdge(graph.Edge{0, 1}, 0)
+	}
+	if _, err := big.ReliabilityExact(0, 1, fp); err == nil {
+		t.Fatal("no error for 21 edges")
+	}
+}
+
+// sampling estimates must agree with ReliabilityExact on random graphs.
+func TestReliabilitySampling(t *testing.T) {
+	r := rand.New(rand.NewSource(32))
+	for trial := 0; trial < 10; trial++ {
+		n := 3 + r.Intn(5)
+		g := graph.LabeledUndirected{make(graph.LabeledAdjacencyList, n)}
+		for m := 0; m < 12; m++ {
+			g.AddEdge(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(r.Intn(50)))
+		}
+		f := func(l graph.LI) float64 { return float64(l) / 100 }
+		exact, err := g.ReliabilityExact(0, 1, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, workers := range []int{1, 3} {
+			seed := r.Int63()
+			p, se := g.ReliabilityMC(0, 1, f, 20000,
+				rand.New(rand.NewSource(seed)), workers)
+			sd := math.Sqrt(exact * (1 - exact) / 20000)
+			if math.Abs(p-exact) > 5*sd {
+				t.Fatal("MC", p, se, "exact", exact)
+			}
+			// same seed, same result
+			p2, se2 := g.ReliabilityMC(0, 1, f, 20000,
+				rand.New(rand.NewSource(seed)), workers)
+			if p2 != p || se2 != se {
+				t.Fatal("not deterministic", p, p2)
+			}
+		}
+	}
+}
+
+// importance sampling must estimate rare disconnection, with probability
+// about 4e-8, to within 10 percent.
+func TestReliabilityIS(t *testing.T) {
+	// two node-disjoint paths 0-1-3 and 0-2-3 with failure probability 1e-4
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 0)
+	g.AddEdge(graph.Edge{1, 3}, 0)
+	g.AddEdge(graph.Edge{0, 2}, 0)
+	g.AddEdge(graph.Edge{2, 3}, 0)
+	f := func(graph.LI) float64 { return 1e-4 }
+	exact, _ := g.ReliabilityExact(0, 3, f)
+	bias := func(graph.LI) float64 { return .3 }
+	p, se := g.ReliabilityIS(0, 3, f, bias, 10000,
+		rand.New(rand.NewSource(33)), 2)
+	u, ue := 1-p, 1-exact
+	if math.Abs(u-ue) > 4*se || math.Abs(u-ue) > .1*ue {
+		t.Fatal("IS unreliability", u, "±", se, "exact", ue)
+	}
+}
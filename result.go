@@ -0,0 +1,172 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// result.go -- binary encoding of single source search results.
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+)
+
+// SearchResult holds the result of a single source search such as Dijkstra
+// or BellmanFord.
+//
+// Paths and Dist are as returned by the search.  Labels and Dist may be nil,
+// for example for results of unweighted searches.
+type SearchResult struct {
+	Start  NI
+	Paths  FromList
+	Labels []LI
+	Dist   []float64
+}
+
+// ChecksumError is returned by DecodeSearchResult when an encoded result
+// was computed on a graph with a different checksum than the one expected.
+type ChecksumError struct {
+	Want, Got uint64
+}
+
+func (e ChecksumError) Error() string {
+	return fmt.Sprintf("stale search result: graph checksum %x, result computed on %x",
+		e.Want, e.Got)
+}
+
+// errCorrupt is returned by DecodeSearchResult for malformed encodings.
+var errCorrupt = errors.New("corrupt search result encoding")
+
+const resultMagic = "gsr\x01"
+
+const (
+	resultLabels = 1 << iota
+	resultDist
+)
+
+// Encode returns a compact binary encoding of r.
+//
+// Argument sum should be the Checksum of the graph searched.  It is stored
+// with the encoding and checked by DecodeSearchResult.
+//
+// Node numbers and path lengths are varint encoded.  Distances are stored
+// as fixed 64 bit values.  Leaves are not stored; they are recomputed on
+// decoding.
+func (r SearchResult) Encode(sum uint64) []byte {
+	p := r.Paths.Paths
+	b := make([]byte, 0, 12+len(p)*4)
+	b = append(b, resultMagic...)
+	var s [8]byte
+	binary.LittleEndian.PutUint64(s[:], sum)
+	b = append(b, s[:]...)
+	var flags byte
+	if r.Labels != nil {
+		flags |= resultLabels
+	}
+	if r.Dist != nil {
+		flags |= resultDist
+	}
+	b = append(b, flags)
+	b = binary.AppendUvarint(b, uint64(len(p)))
+	b = binary.AppendVarint(b, int64(r.Start))
+	b = binary.AppendUvarint(b, uint64(r.Paths.MaxLen))
+	for _, e := range p {
+		b = binary.AppendVarint(b, int64(e.From))
+		b = binary.AppendUvarint(b, uint64(e.Len))
+	}
+	if r.Labels != nil {
+		for i := range p {
+			b = binary.AppendVarint(b, int64(r.Labels[i]))
+		}
+	}
+	if r.Dist != nil {
+		for i := range p {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(r.Dist[i]))
+		}
+	}
+	return binary.LittleEndian.AppendUint32(b, crc32.ChecksumIEEE(b))
+}
+
+// DecodeSearchResult decodes a SearchResult encoded by SearchResult.Encode.
+//
+// Argument sum should be the Checksum of the graph for which the result
+// will be used.  If the result was encoded with a different checksum,
+// DecodeSearchResult returns a ChecksumError.  Other errors indicate a
+// malformed or corrupted encoding.
+//
+// Leaves of the decoded FromList are recomputed.
+func DecodeSearchResult(b []byte, sum uint64) (r SearchResult, err error) {
+	if len(b) < 17 || string(b[:4]) != resultMagic {
+		return r, errCorrupt
+	}
+	body, c := b[:len(b)-4], b[len(b)-4:]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(c) {
+		return r, errCorrupt
+	}
+	if got := binary.LittleEndian.Uint64(body[4:]); got != sum {
+		return r, ChecksumError{Want: sum, Got: got}
+	}
+	flags := body[12]
+	body = body[13:]
+	// each read advances body, recording any failure in err
+	uvarint := func() uint64 {
+		x, n := binary.Uvarint(body)
+		if n <= 0 {
+			err = errCorrupt
+			return 0
+		}
+		body = body[n:]
+		return x
+	}
+	varint := func() int64 {
+		x, n := binary.Varint(body)
+		if n <= 0 {
+			err = errCorrupt
+			return 0
+		}
+		body = body[n:]
+		return x
+	}
+	order := uvarint()
+	// each node takes at least two bytes
+	if err != nil || order > uint64(len(body)/2) {
+		return r, errCorrupt
+	}
+	r.Start = NI(varint())
+	r.Paths = NewFromList(int(order))
+	r.Paths.MaxLen = int(uvarint())
+	p := r.Paths.Paths
+	for i := range p {
+		from := varint()
+		if from < -1 || from >= int64(order) {
+			return r, errCorrupt
+		}
+		p[i] = PathEnd{From: NI(from), Len: int(uvarint())}
+	}
+	if flags&resultLabels != 0 {
+		r.Labels = make([]LI, order)
+		for i := range r.Labels {
+			r.Labels[i] = LI(varint())
+		}
+	}
+	if err != nil {
+		return r, err
+	}
+	if flags&resultDist != 0 {
+		if uint64(len(body)) != order*8 {
+			return r, errCorrupt
+		}
+		r.Dist = make([]float64, order)
+		for i := range r.Dist {
+			r.Dist[i] = math.Float64frombits(binary.LittleEndian.Uint64(body))
+			body = body[8:]
+		}
+	}
+	if len(body) != 0 {
+		return r, errCorrupt
+	}
+	r.Paths.RecalcLeaves()
+	return r, nil
+}
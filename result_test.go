@@ -0,0 +1,128 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDecodeSearchResult() {
+	//   (2)     (3)
+	// 0---->1------>2
+	//  \           ^
+	//   \---------/
+	//      (9)
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 2}, {To: 2, Label: 9}},
+		1: {{To: 2, Label: 3}},
+		2: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	f, labels, dist, _ := g.Dijkstra(0, -1, w)
+	r := graph.SearchResult{Start: 0, Paths: f, Labels: labels, Dist: dist}
+	b := r.Encode(g.Checksum())
+
+	d, err := graph.DecodeSearchResult(b, g.Checksum())
+	fmt.Println(err)
+	fmt.Println(d.Paths.PathToLabeled(2, d.Labels, nil), d.Dist[2])
+
+	g[0][1].Label = 4 // modify graph
+	_, err = graph.DecodeSearchResult(b, g.Checksum())
+	_, stale := err.(graph.ChecksumError)
+	fmt.Println("stale:", stale)
+	// Output:
+	// <nil>
+	// {0 [{1 2} {2 3}]} 5
+	// stale: true
+}
+
+func TestSearchResultRoundTrip(t *testing.T) {
+	tc := r(100, 200, 62)
+	w := func(label graph.LI) float64 { return tc.w[label] }
+	a := tc.l.LabeledAdjacencyList
+	sum := a.Checksum()
+	df, dl, dd, _ := a.Dijkstra(tc.start, -1, w)
+	bf, bl, bd, _ := tc.l.BellmanFord(w, tc.start)
+	af, al, _, _ := a.AStarA(w, tc.start, tc.end, tc.h)
+	for _, r := range []graph.SearchResult{
+		{Start: tc.start, Paths: df, Labels: dl, Dist: dd},
+		{Start: tc.start, Paths: bf, Labels: bl, Dist: bd},
+		{Start: tc.start, Paths: af, Labels: al},
+		{Start: tc.start, Paths: df},
+	} {
+		r.Paths.RecalcLeaves()
+		d, err := graph.DecodeSearchResult(r.Encode(sum), sum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(d, r) {
+			t.Fatal("round trip mismatch")
+		}
+	}
+}
+
+func TestSearchResultReject(t *testing.T) {
+	tc := r(100, 200, 62)
+	w := func(label graph.LI) float64 { return tc.w[label] }
+	a := tc.l.LabeledAdjacencyList
+	f, l, d, _ := a.Dijkstra(tc.start, -1, w)
+	b := graph.SearchResult{Start: tc.start, Paths: f, Labels: l, Dist: d}.
+		Encode(a.Checksum())
+	// mismatched checksum
+	_, err := graph.DecodeSearchResult(b, a.Unlabeled().Checksum())
+	if _, ok := err.(graph.ChecksumError); !ok {
+		t.Fatal("want ChecksumError, got", err)
+	}
+	// corrupted byte
+	c := append([]byte{}, b...)
+	c[len(c)/2] ^= 1
+	if _, err := graph.DecodeSearchResult(c, a.Checksum()); err == nil {
+		t.Fatal("corrupted encoding accepted")
+	} else if _, ok := err.(graph.ChecksumError); ok {
+		t.Fatal("corruption reported as ChecksumError")
+	}
+	// truncated
+	for _, n := range []int{0, 10, len(b) - 1} {
+		if _, err := graph.DecodeSearchResult(b[:n], a.Checksum()); err == nil {
+			t.Fatal("truncated encoding accepted, len", n)
+		}
+	}
+}
+
+// a chain of n nodes with a chord from each node to the node two ahead.
+func chainResult(n int) (graph.LabeledAdjacencyList, graph.SearchResult) {
+	g := make(graph.LabeledAdjacencyList, n)
+	for i := 0; i < n-2; i++ {
+		g[i] = []graph.Half{{graph.NI(i + 1), 1}, {graph.NI(i + 2), 3}}
+	}
+	g[n-2] = []graph.Half{{graph.NI(n - 1), 1}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	f, l, d, _ := g.Dijkstra(0, -1, w)
+	return g, graph.SearchResult{Paths: f, Labels: l, Dist: d}
+}
+
+func BenchmarkSearchResultDecode(b *testing.B) {
+	g, r := chainResult(2e6)
+	sum := g.Checksum()
+	enc := r.Encode(sum)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := graph.DecodeSearchResult(enc, sum); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearchResultRecompute(b *testing.B) {
+	g, _ := chainResult(2e6)
+	w := func(l graph.LI) float64 { return float64(l) }
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Dijkstra(0, -1, w)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// rewire.go -- degree-preserving randomization.
+
+import "math/rand"
+
+// ShuffleEdges randomizes g in place by double edge swaps, preserving the
+// degree of every node.
+//
+// Each of the given number of iterations is an attempt to swap a random pair
+// of edges a-b and c-d, replacing them with a-d and c-b.  A swap is rejected
+// if it would create a loop or an edge parallel to an existing edge, so
+// a simple graph remains simple.  Because rejected attempts still count as
+// iterations, the method always terminates, even for graphs such as stars
+// where no swap is possible.  Loops already present in g are not swapped.
+//
+// Returned is the number of successful swaps.  For a well mixed result,
+// iterations is commonly several times the size of g.
+//
+// If Rand r is nil, the rand package default shared source is used.
+//
+// See also LabeledUndirected.ShuffleEdges.
+func (g Undirected) ShuffleEdges(r *rand.Rand, iterations int) (swaps int) {
+	ri := rand.Intn
+	if r != nil {
+		ri = r.Intn
+	}
+	a := g.AdjacencyList
+	var edges []Edge
+	has := map[Edge]int{}
+	for fr, to := range a {
+		for _, to := range to {
+			if NI(fr) < to {
+				e := Edge{NI(fr), to}
+				edges = append(edges, e)
+				has[e]++
+			}
+		}
+	}
+	if len(edges) < 2 {
+		return
+	}
+	replace := func(n, old, new NI) {
+		for i, to := range a[n] {
+			if to == old {
+				a[n][i] = new
+				return
+			}
+		}
+	}
+	for i := 0; i < iterations; i++ {
+		x, y := ri(len(edges)), ri(len(edges))
+		e1, e2 := edges[x], edges[y]
+		n1, n2 := e1.N1, e1.N2
+		n3, n4 := e2.N1, e2.N2
+		if ri(2) == 0 {
+			n3, n4 = n4, n3
+		}
+		s1, s2, ok := swapEdges(n1, n2, n3, n4, has)
+		if !ok {
+			continue
+		}
+		replace(n1, n2, n4)
+		replace(n2, n1, n3)
+		replace(n3, n4, n2)
+		replace(n4, n3, n1)
+		edges[x], edges[y] = s1, s2
+		swaps++
+	}
+	return
+}
+
+// ShuffleEdges randomizes g in place by double edge swaps, preserving the
+// degree of every node.
+//
+// Labels are carried along with swapped edges:  when labeled edges a-b and
+// c-d are replaced with a-d and c-b, a-d gets the label of a-b and c-b gets
+// the label of c-d.  The multiset of labels at each node thus changes but
+// the multiset of labels of the graph does not.
+//
+// See Undirected.ShuffleEdges for more description.
+func (g LabeledUndirected) ShuffleEdges(r *rand.Rand, iterations int) (swaps int) {
+	ri := rand.Intn
+	if r != nil {
+		ri = r.Intn
+	}
+	a := g.LabeledAdjacencyList
+	var edges []LabeledEdge
+	has := map[Edge]int{}
+	for fr, to := range a {
+		for _, to := range to {
+			if NI(fr) < to.To {
+				e := Edge{NI(fr), to.To}
+				edges = append(edges, LabeledEdge{e, to.Label})
+				has[e]++
+			}
+		}
+	}
+	if len(edges) < 2 {
+		return
+	}
+	replace := func(n NI, old, new Half) {
+		for i, to := range a[n] {
+			if to == old {
+				a[n][i] = new
+				return
+			}
+		}
+	}
+	for i := 0; i < iterations; i++ {
+		x, y := ri(len(edges)), ri(len(edges))
+		e1, e2 := edges[x], edges[y]
+		n1, n2 := e1.N1, e1.N2
+		n3, n4 := e2.N1, e2.N2
+		if ri(2) == 0 {
+			n3, n4 = n4, n3
+		}
+		s1, s2, ok := swapEdges(n1, n2, n3, n4, has)
+		if !ok {
+			continue
+		}
+		l1, l2 := e1.LI, e2.LI
+		replace(n1, Half{n2, l1}, Half{n4, l1})
+		replace(n2, Half{n1, l1}, Half{n3, l2})
+		replace(n3, Half{n4, l2}, Half{n2, l2})
+		replace(n4, Half{n3, l2}, Half{n1, l1})
+		edges[x] = LabeledEdge{s1, e1.LI}
+		edges[y] = LabeledEdge{s2, e2.LI}
+		swaps++
+	}
+	return
+}
+
+// swapEdges validates the swap of edges n1-n2, n3-n4 for n1-n4, n3-n2 and
+// if valid, updates edge counts in has.  Edges are returned with N1 < N2.
+func swapEdges(n1, n2, n3, n4 NI, has map[Edge]int) (s1, s2 Edge, ok bool) {
+	if n1 == n4 || n3 == n2 {
+		return // would create a loop
+	}
+	s1, s2 = ordEdge(n1, n4), ordEdge(n3, n2)
+	if has[s1] > 0 || has[s2] > 0 || s1 == s2 {
+		return // would create a parallel edge
+	}
+	for _, e := range []Edge{ordEdge(n1, n2), ordEdge(n3, n4)} {
+		if has[e]--; has[e] == 0 {
+			delete(has, e)
+		}
+	}
+	has[s1]++
+	has[s2]++
+	return s1, s2, true
+}
+
+func ordEdge(n1, n2 NI) Edge {
+	if n1 > n2 {
+		n1, n2 = n2, n1
+	}
+	return Edge{n1, n2}
+}
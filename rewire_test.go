@@ -0,0 +1,116 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_ShuffleEdges() {
+	// 0---1   4---5
+	// |   |   |   |
+	// 3---2   7---6
+	var g graph.Undirected
+	for _, c := range [][]graph.NI{{0, 1, 2, 3}, {4, 5, 6, 7}} {
+		for i, n := range c {
+			g.AddEdge(n, c[(i+1)%4])
+		}
+	}
+	swaps := g.ShuffleEdges(rand.New(rand.NewSource(1)), 20)
+	fmt.Println("swaps:", swaps)
+	for n, to := range g.AdjacencyList {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// swaps: 12
+	// 0 [2 1]
+	// 1 [0 3]
+	// 2 [0 5]
+	// 3 [1 6]
+	// 4 [7 6]
+	// 5 [7 2]
+	// 6 [4 3]
+	// 7 [5 4]
+}
+
+func degrees(g graph.AdjacencyList) []int {
+	d := make([]int, len(g))
+	for n, to := range g {
+		d[n] = len(to)
+	}
+	return d
+}
+
+func TestShuffleEdges(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	g := graph.GnmUndirected(100, 400, r)
+	d0 := degrees(g.AdjacencyList)
+	c, _ := g.Copy()
+	swaps := g.ShuffleEdges(r, 2000)
+	if swaps < 1000 {
+		t.Fatal("only", swaps, "swaps")
+	}
+	if !reflect.DeepEqual(degrees(g.AdjacencyList), d0) {
+		t.Fatal("degrees changed")
+	}
+	if ok, _, _ := g.IsUndirected(); !ok {
+		t.Fatal("not undirected")
+	}
+	if ok, _ := g.IsSimple(); !ok {
+		t.Fatal("not simple")
+	}
+	if reflect.DeepEqual(g, c) {
+		t.Fatal("not shuffled")
+	}
+	// a star admits no swaps, and terminates
+	var s graph.Undirected
+	for n := graph.NI(1); n < 10; n++ {
+		s.AddEdge(0, n)
+	}
+	if swaps := s.ShuffleEdges(r, 1000); swaps != 0 {
+		t.Fatal(swaps, "swaps in star")
+	}
+	// too few edges
+	var e graph.Undirected
+	e.AddEdge(0, 1)
+	if swaps := e.ShuffleEdges(r, 10); swaps != 0 {
+		t.Fatal(swaps)
+	}
+}
+
+func TestLabeledShuffleEdges(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	g, m := graph.LabeledGnpUndirected(60, .1, r)
+	d0 := degrees(g.Unlabeled())
+	labels := func() []graph.LI {
+		var ls []graph.LI
+		g.Edges(func(e graph.LabeledEdge) {
+			ls = append(ls, e.LI)
+		})
+		sort.Slice(ls, func(i, j int) bool { return ls[i] < ls[j] })
+		return ls
+	}
+	l0 := labels()
+	if swaps := g.ShuffleEdges(r, 5*m); swaps < m {
+		t.Fatal("only", swaps, "swaps")
+	}
+	if !reflect.DeepEqual(degrees(g.Unlabeled()), d0) {
+		t.Fatal("degrees changed")
+	}
+	if ok, _, _ := g.IsUndirected(); !ok {
+		t.Fatal("not undirected")
+	}
+	if ok, _ := g.IsSimple(); !ok {
+		t.Fatal("not simple")
+	}
+	if !reflect.DeepEqual(labels(), l0) {
+		t.Fatal("labels changed")
+	}
+}
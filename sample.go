@@ -0,0 +1,196 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// sample.go -- sampling subgraphs by snowball and forest fire expansion.
+
+import (
+	"math/rand"
+
+	"github.com/soniakeys/bits"
+)
+
+// SnowballSample samples a subgraph of g by snowball expansion.
+//
+// The sample starts with the seed nodes.  In each of the given number of
+// rounds, each node added in the previous round is expanded by adding at
+// most maxPerNode of its to-nodes not yet in the sample, chosen at random.
+// If maxPerNode is less than 1, or if there are no more than maxPerNode
+// such to-nodes, all are added in arc list order.  Expansion stops early if
+// a round adds no nodes.
+//
+// Returned is the subgraph induced by the sampled nodes, with subgraph NIs
+// in order of sampling.  Seed nodes are first, in the order given, ignoring
+// duplicates.
+//
+// If Rand r is nil, the rand package default shared source is used.
+// Otherwise the result is determined by r, so that a Rand with the same
+// seed produces the same sample.
+func (g *Directed) SnowballSample(seeds []NI, rounds, maxPerNode int, r *rand.Rand) *DirectedSubgraph {
+	return g.InduceList(snowball(g.AdjacencyList, seeds, rounds, maxPerNode,
+		newSampleRand(r)))
+}
+
+// SnowballSample samples a subgraph of g by snowball expansion.
+//
+// Nodes are expanded by adding neighbors.  See Directed.SnowballSample.
+func (g *Undirected) SnowballSample(seeds []NI, rounds, maxPerNode int, r *rand.Rand) *UndirectedSubgraph {
+	return g.InduceList(snowball(g.AdjacencyList, seeds, rounds, maxPerNode,
+		newSampleRand(r)))
+}
+
+// ForestFireSample samples a subgraph of g by forest fire expansion
+// (Leskovec and Faloutsos).
+//
+// A fire starts at node seed and spreads to nodes not yet burned.  When a
+// node burns, a number x of its to-nodes is drawn from a geometric
+// distribution with mean pForward/(1-pForward) and a number y of its
+// from-nodes is drawn with mean pBackward/(1-pBackward).  Up to x unburned
+// to-nodes and y unburned from-nodes, chosen at random, catch fire and
+// burn in turn, breadth first.  From-nodes are found with a transpose of g.
+//
+// If the fire dies out before targetOrder nodes have burned, it is
+// reignited at a random unburned neighbor, by an arc in either direction, of
+// the earliest burned node that has one.  Burning stops when targetOrder
+// nodes have burned or when no burned node has an unburned neighbor, that is,
+// when the weakly connected component of seed is exhausted.
+//
+// Returned is the subgraph induced by the burned nodes, with subgraph NIs
+// in the order nodes burned, starting with seed.
+//
+// If Rand r is nil, the rand package default shared source is used.
+// Otherwise the result is determined by r, so that a Rand with the same
+// seed produces the same sample.
+func (g *Directed) ForestFireSample(seed NI, pForward, pBackward float64, targetOrder int, r *rand.Rand) *DirectedSubgraph {
+	t, _ := g.Transpose()
+	return g.InduceList(forestFire(g.AdjacencyList, t.AdjacencyList, seed,
+		pForward, pBackward, targetOrder, newSampleRand(r)))
+}
+
+// ForestFireSample samples a subgraph of g by forest fire expansion
+// (Leskovec and Faloutsos).
+//
+// When a node burns, a number of its neighbors is drawn from a geometric
+// distribution with mean p/(1-p).  Burning stops when targetOrder nodes have
+// burned or when the connected component of seed is exhausted.  See
+// Directed.ForestFireSample.
+func (g *Undirected) ForestFireSample(seed NI, p float64, targetOrder int, r *rand.Rand) *UndirectedSubgraph {
+	return g.InduceList(forestFire(g.AdjacencyList, nil, seed,
+		p, 0, targetOrder, newSampleRand(r)))
+}
+
+// sampleRand holds random functions of a Rand or of the rand package
+// default source.
+type sampleRand struct {
+	intn    func(int) int
+	float64 func() float64
+}
+
+func newSampleRand(r *rand.Rand) sampleRand {
+	if r == nil {
+		return sampleRand{rand.Intn, rand.Float64}
+	}
+	return sampleRand{r.Intn, r.Float64}
+}
+
+// sampleCands appends to c the distinct nodes of to not set in v, setting
+// them in v.
+func sampleCands(to []NI, v bits.Bits, c []NI) []NI {
+	for _, n := range to {
+		if v.Bit(int(n)) == 0 {
+			v.SetBit(int(n), 1)
+			c = append(c, n)
+		}
+	}
+	return c
+}
+
+// choose moves k randomly chosen nodes of c to the front of c and clears
+// the bits in v of the nodes not chosen.  If k is len(c), c is unchanged.
+func (r sampleRand) choose(c []NI, k int, v bits.Bits) {
+	if k >= len(c) {
+		return
+	}
+	for i := 0; i < k; i++ {
+		j := i + r.intn(len(c)-i)
+		c[i], c[j] = c[j], c[i]
+	}
+	for _, n := range c[k:] {
+		v.SetBit(int(n), 0)
+	}
+}
+
+// snowball returns nodes sampled by SnowballSample, in order of sampling.
+func snowball(a AdjacencyList, seeds []NI, rounds, maxPerNode int, r sampleRand) []NI {
+	v := bits.New(len(a))
+	s := sampleCands(seeds, v, nil)
+	var c []NI
+	for lo := 0; rounds > 0 && lo < len(s); rounds-- {
+		hi := len(s)
+		for _, n := range s[lo:hi] {
+			c = sampleCands(a[n], v, c[:0])
+			k := len(c)
+			if maxPerNode > 0 && k > maxPerNode {
+				k = maxPerNode
+			}
+			r.choose(c, k, v)
+			s = append(s, c[:k]...)
+		}
+		lo = hi
+	}
+	return s
+}
+
+// forestFire returns nodes burned by ForestFireSample, in order of burning.
+//
+// Graph in holds from-nodes and may be nil.
+func forestFire(out, in AdjacencyList, seed NI, pf, pb float64, target int, r sampleRand) []NI {
+	v := bits.New(len(out))
+	v.SetBit(int(seed), 1)
+	s := []NI{seed}
+	q := []NI{seed}
+	var c []NI
+	burn := func(to []NI, p float64) {
+		c = sampleCands(to, v, c[:0])
+		k := 0
+		for k < len(c) && r.float64() < p {
+			k++
+		}
+		if rem := target - len(s); k > rem {
+			k = rem
+		}
+		r.choose(c, k, v)
+		s = append(s, c[:k]...)
+		q = append(q, c[:k]...)
+	}
+	scan := 0 // burned nodes before scan have no unburned neighbors
+	for len(s) < target {
+		if len(q) == 0 {
+			// reignite
+			for ; scan < len(s); scan++ {
+				c = sampleCands(out[s[scan]], v, c[:0])
+				if in != nil {
+					c = sampleCands(in[s[scan]], v, c)
+				}
+				if len(c) > 0 {
+					break
+				}
+			}
+			if scan == len(s) {
+				break
+			}
+			r.choose(c, 1, v)
+			s = append(s, c[0])
+			q = append(q, c[0])
+			continue
+		}
+		n := q[0]
+		q = q[1:]
+		burn(out[n], pf)
+		if in != nil {
+			burn(in[n], pb)
+		}
+	}
+	return s
+}
@@ -0,0 +1,188 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUndirected_SnowballSample() {
+	//     1   4
+	//    /   /
+	//   0---3---5---6
+	//    \
+	//     2
+	g := graph.Undirected{graph.AdjacencyList{
+		0: {1, 2, 3},
+		1: {0},
+		2: {0},
+		3: {0, 4, 5},
+		4: {3},
+		5: {3, 6},
+		6: {5},
+	}}
+	// maxPerNode 0 takes all neighbors, so the sample is not random.
+	s := g.SnowballSample([]graph.NI{0}, 2, 0, nil)
+	fmt.Println(s.SuperNI)
+	// Output:
+	// [0 1 2 3 4 5]
+}
+
+func ExampleDirected_ForestFireSample() {
+	// a directed cycle of 10 nodes
+	g := graph.Directed{make(graph.AdjacencyList, 10)}
+	for n := range g.AdjacencyList {
+		g.AdjacencyList[n] = []graph.NI{graph.NI((n + 1) % 10)}
+	}
+	s := g.ForestFireSample(0, .5, .5, 4, rand.New(rand.NewSource(1)))
+	fmt.Println(s.Order(), s.SuperNI[0])
+	// Output:
+	// 4 0
+}
+
+// powerLaw returns a random undirected graph with a power law degree
+// distribution.
+func powerLaw(n int, r *rand.Rand) graph.Undirected {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 100*math.Pow(float64(i+1), -.5) + 1
+	}
+	g, _ := graph.ChungLu(w, r)
+	g.Permute(r.Perm(n))
+	return g
+}
+
+// degreeKS returns the Kolmogorov-Smirnov statistic between degree
+// distributions of a and b, with degrees normalized by mean degree.
+func degreeKS(a, b graph.Undirected) float64 {
+	norm := func(g graph.Undirected) []float64 {
+		d := make([]float64, g.Order())
+		sum := 0.
+		for n := range d {
+			d[n] = float64(g.Degree(graph.NI(n)))
+			sum += d[n]
+		}
+		for n := range d {
+			d[n] /= sum / float64(len(d))
+		}
+		sort.Float64s(d)
+		return d
+	}
+	da, db := norm(a), norm(b)
+	ks := 0.
+	for _, x := range append(append([]float64{}, da...), db...) {
+		fa := float64(sort.SearchFloat64s(da, x+1e-12)) / float64(len(da))
+		fb := float64(sort.SearchFloat64s(db, x+1e-12)) / float64(len(db))
+		ks = math.Max(ks, math.Abs(fa-fb))
+	}
+	return ks
+}
+
+func TestSnowballSample(t *testing.T) {
+	r := rand.New(rand.NewSource(25))
+	g := powerLaw(5000, r)
+	for i := 0; i < 20; i++ {
+		seeds := []graph.NI{graph.NI(r.Intn(5000)), graph.NI(r.Intn(5000))}
+		rounds, k := 1+r.Intn(4), 1+r.Intn(4)
+		seed := r.Int63()
+		s := g.SnowballSample(seeds, rounds, k, rand.New(rand.NewSource(seed)))
+		// bound on sample size
+		max, layer := len(seeds), len(seeds)
+		for j := 0; j < rounds; j++ {
+			layer *= k
+			max += layer
+		}
+		if s.Order() > max {
+			t.Fatal(i, "order", s.Order(), "max", max)
+		}
+		// distinct nodes, each non-seed adjacent to an earlier node
+		seen := map[graph.NI]bool{}
+		for x, n := range s.SuperNI {
+			if seen[n] {
+				t.Fatal(i, "duplicate", n)
+			}
+			seen[n] = true
+			if n == seeds[0] || n == seeds[1] {
+				continue
+			}
+			ok := false
+			for _, nb := range g.AdjacencyList[n] {
+				if sx, in := s.SubNI[nb]; in && int(sx) < x {
+					ok = true
+				}
+			}
+			if !ok {
+				t.Fatal(i, "node", n, "not adjacent to earlier node")
+			}
+		}
+		// deterministic
+		s2 := g.SnowballSample(seeds, rounds, k, rand.New(rand.NewSource(seed)))
+		if !reflect.DeepEqual(s.SuperNI, s2.SuperNI) {
+			t.Fatal(i, "not deterministic")
+		}
+	}
+}
+
+func TestForestFireSample(t *testing.T) {
+	r := rand.New(rand.NewSource(26))
+	n := 20000
+	g := powerLaw(n, r)
+	for i := 0; i < 5; i++ {
+		var seed graph.NI
+		for {
+			seed = graph.NI(r.Intn(n))
+			if len(g.AdjacencyList[seed]) > 0 {
+				break
+			}
+		}
+		rs := r.Int63()
+		s := g.ForestFireSample(seed, .7, n/5, rand.New(rand.NewSource(rs)))
+		if s.Order() != n/5 || s.SuperNI[0] != seed {
+			t.Fatal(i, "order", s.Order(), "want", n/5)
+		}
+		// degree distribution shape is roughly preserved, and better than
+		// by sampling nodes uniformly.
+		ks := degreeKS(g, s.Undirected)
+		p := r.Perm(n)[:n/5]
+		u := make([]graph.NI, len(p))
+		for x, v := range p {
+			u[x] = graph.NI(v)
+		}
+		uks := degreeKS(g, g.InduceList(u).Undirected)
+		if ks > .35 || ks >= uks {
+			t.Fatal(i, "degree KS statistic", ks, "uniform", uks)
+		}
+		s2 := g.ForestFireSample(seed, .7, n/5, rand.New(rand.NewSource(rs)))
+		if !reflect.DeepEqual(s.SuperNI, s2.SuperNI) {
+			t.Fatal(i, "not deterministic")
+		}
+	}
+}
+
+func TestForestFireSampleExhausted(t *testing.T) {
+	// components {0 1 2 3} and {4 5}, weakly connected by direction
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		2: {1, 3},
+		4: {5},
+		5: nil,
+	}}
+	r := rand.New(rand.NewSource(27))
+	for i := 0; i < 20; i++ {
+		// probabilities 0 rely on reignition alone
+		s := g.ForestFireSample(1, 0, 0, 10, r)
+		got := append([]graph.NI{}, s.SuperNI...)
+		sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+		if !reflect.DeepEqual(got, []graph.NI{0, 1, 2, 3}) {
+			t.Fatal(i, got)
+		}
+	}
+}
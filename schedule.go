@@ -0,0 +1,175 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// schedule.go -- scheduling of DAG tasks with precedence constraints.
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// BottomLevels computes the critical path backward pass over a directed
+// acyclic graph of tasks.
+//
+// Nodes of g are tasks, arcs are precedence constraints, and dur gives task
+// durations indexed by node.  Argument ordering must be a topological ordering
+// of g.
+//
+// The bottom level of a task is the length of the longest path from the start
+// of the task through the end of a final task, that is, the task duration plus
+// the maximum bottom level of any successor.  The length of the critical path
+// of g is the maximum bottom level.
+func (g Directed) BottomLevels(ordering []NI, dur []float64) (bl []float64) {
+	a := g.AdjacencyList
+	bl = make([]float64, len(a))
+	for i := len(ordering) - 1; i >= 0; i-- {
+		fr := ordering[i]
+		m := 0.
+		for _, to := range a[fr] {
+			m = math.Max(m, bl[to])
+		}
+		bl[fr] = dur[fr] + m
+	}
+	return
+}
+
+// CriticalPathPriority returns a priority function for ListSchedule that
+// favors tasks on long paths.
+//
+// The priority of a task is its bottom level as computed by BottomLevels.
+func (g Directed) CriticalPathPriority(ordering []NI, dur []float64) func(NI) float64 {
+	bl := g.BottomLevels(ordering, dur)
+	return func(n NI) float64 { return bl[n] }
+}
+
+// LPTPriority returns a priority function for ListSchedule that favors
+// tasks with the longest processing time.
+func LPTPriority(dur []float64) func(NI) float64 {
+	return func(n NI) float64 { return dur[n] }
+}
+
+// ListSchedule schedules a directed acyclic graph of tasks on m identical
+// machines.
+//
+// Nodes of g are tasks, arcs are precedence constraints, and dur gives task
+// durations indexed by node.  Argument ordering must be a topological ordering
+// of g.  A task is ready when all of its predecessors have finished.
+//
+// ListSchedule simulates classic list scheduling:  Whenever a machine is free
+// and tasks are ready, the ready task with the highest priority is started.
+// Ties are broken in favor of lower node numbers.
+//
+// Returned are start times indexed by node and the finish time of the last
+// task to finish.  An error is returned if m < 1.
+//
+// See CriticalPathPriority and LPTPriority for common priority functions and
+// see ScheduleLowerBound for a bound useful in evaluating the result.
+func (g Directed) ListSchedule(ordering []NI, dur []float64, m int, priority func(NI) float64) (start []float64, makespan float64, err error) {
+	if m < 1 {
+		return nil, 0, fmt.Errorf("m = %d machines, need at least 1", m)
+	}
+	a := g.AdjacencyList
+	npred := make([]int, len(a))
+	for _, to := range a {
+		for _, to := range to {
+			npred[to]++
+		}
+	}
+	ready := &readyHeap{}
+	for _, n := range ordering {
+		if npred[n] == 0 {
+			heap.Push(ready, readyTask{n, priority(n)})
+		}
+	}
+	start = make([]float64, len(a))
+	running := &runHeap{}
+	free := m
+	t := 0.
+	finish := func(n NI) {
+		free++
+		for _, to := range a[n] {
+			if npred[to]--; npred[to] == 0 {
+				heap.Push(ready, readyTask{to, priority(to)})
+			}
+		}
+	}
+	for ready.Len() > 0 || running.Len() > 0 {
+		for free > 0 && ready.Len() > 0 {
+			n := heap.Pop(ready).(readyTask).n
+			start[n] = t
+			free--
+			heap.Push(running, runTask{n, t + dur[n]})
+		}
+		e := heap.Pop(running).(runTask)
+		t = e.end
+		finish(e.n)
+		for running.Len() > 0 && (*running)[0].end == t {
+			finish(heap.Pop(running).(runTask).n)
+		}
+	}
+	return start, t, nil
+}
+
+// ScheduleLowerBound returns a lower bound on the makespan of any schedule
+// of a directed acyclic graph of tasks on m machines.
+//
+// The bound is the greater of the critical path length and the total work
+// divided by m.  Arguments are as for ListSchedule.
+func (g Directed) ScheduleLowerBound(ordering []NI, dur []float64, m int) float64 {
+	cp := 0.
+	for _, b := range g.BottomLevels(ordering, dur) {
+		cp = math.Max(cp, b)
+	}
+	w := 0.
+	for _, d := range dur {
+		w += d
+	}
+	return math.Max(cp, w/float64(m))
+}
+
+type readyTask struct {
+	n NI
+	p float64 // priority
+}
+
+// readyHeap is a max-heap on priority.
+type readyHeap []readyTask
+
+type runTask struct {
+	n   NI
+	end float64
+}
+
+// runHeap is a min-heap on end time.
+type runHeap []runTask
+
+// implement container/heap
+func (h readyHeap) Len() int { return len(h) }
+func (h readyHeap) Less(i, j int) bool {
+	if h[i].p != h[j].p {
+		return h[i].p > h[j].p
+	}
+	return h[i].n < h[j].n
+}
+func (h readyHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (p *readyHeap) Push(x interface{}) { *p = append(*p, x.(readyTask)) }
+func (p *readyHeap) Pop() interface{} {
+	h := *p
+	last := len(h) - 1
+	*p = h[:last]
+	return h[last]
+}
+
+func (h runHeap) Len() int            { return len(h) }
+func (h runHeap) Less(i, j int) bool  { return h[i].end < h[j].end }
+func (h runHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (p *runHeap) Push(x interface{}) { *p = append(*p, x.(runTask)) }
+func (p *runHeap) Pop() interface{} {
+	h := *p
+	last := len(h) - 1
+	*p = h[:last]
+	return h[last]
+}
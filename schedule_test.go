@@ -0,0 +1,113 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDirected_ListSchedule() {
+	// 0-->1   2   3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		3: {},
+	}}
+	dur := []float64{3, 3, 3, 3}
+	ord := []graph.NI{0, 1, 2, 3}
+	start, ms, _ := g.ListSchedule(ord, dur, 2, g.CriticalPathPriority(ord, dur))
+	fmt.Println("start:   ", start)
+	fmt.Println("makespan:", ms)
+	fmt.Println("bound:   ", g.ScheduleLowerBound(ord, dur, 2))
+	// Output:
+	// start:    [0 3 0 3]
+	// makespan: 6
+	// bound:    6
+}
+
+func ExampleDirected_BottomLevels() {
+	//   1
+	//  ^ \
+	// /   v
+	// 0-->2-->3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {3},
+		3: {},
+	}}
+	fmt.Println(g.BottomLevels([]graph.NI{0, 1, 2, 3}, []float64{1, 5, 2, 1}))
+	// Output:
+	// [9 8 3 1]
+}
+
+func TestListSchedulePrecedence(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 50; i++ {
+		// random DAG with arcs from lower to higher node numbers
+		n := 2 + r.Intn(30)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		ord := make([]graph.NI, n)
+		dur := make([]float64, n)
+		for fr := range g.AdjacencyList {
+			ord[fr] = graph.NI(fr)
+			dur[fr] = float64(1 + r.Intn(9))
+			for to := fr + 1; to < n; to++ {
+				if r.Intn(5) == 0 {
+					g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(to))
+				}
+			}
+		}
+		m := 1 + r.Intn(4)
+		for _, p := range []func(graph.NI) float64{
+			g.CriticalPathPriority(ord, dur),
+			graph.LPTPriority(dur),
+		} {
+			start, ms, err := g.ListSchedule(ord, dur, m, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for fr, to := range g.AdjacencyList {
+				for _, to := range to {
+					if start[to] < start[fr]+dur[fr] {
+						t.Fatal("precedence violated:", fr, "->", to)
+					}
+				}
+			}
+			// machine count respected at each start time
+			s := append([]float64{}, start...)
+			sort.Float64s(s)
+			for _, t0 := range s {
+				busy := 0
+				for n, st := range start {
+					if st <= t0 && t0 < st+dur[n] {
+						busy++
+					}
+				}
+				if busy > m {
+					t.Fatal(busy, "tasks running at time", t0, "on", m, "machines")
+				}
+			}
+			if lb := g.ScheduleLowerBound(ord, dur, m); ms < lb {
+				t.Fatal("makespan", ms, "below lower bound", lb)
+			}
+		}
+	}
+}
+
+func TestListScheduleNoMachines(t *testing.T) {
+	g := graph.Directed{graph.AdjacencyList{0: {1}, 1: {}}}
+	ord := []graph.NI{0, 1}
+	dur := []float64{1, 1}
+	for _, m := range []int{0, -1} {
+		start, _, err := g.ListSchedule(ord, dur, m, graph.LPTPriority(dur))
+		if err == nil || start != nil {
+			t.Fatal(m, "machines:", start, err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// semiring.go -- optimal paths under a path algebra.
+
+import (
+	"container/heap"
+	"math"
+)
+
+// SemiringPath finds optimal paths from start under a path algebra.
+//
+// The value of a path is computed by combining arc weights w(label) with
+// plus, starting with value one at start.  The value of a node is that of
+// the best path to it, where better(a, b) reports that value a is better
+// than value b.  Nodes not reached by a path better than zero have value
+// zero.  Ordinary shortest paths for example use plus = addition,
+// better = less than, zero = +Inf, and one = 0.
+//
+// The search is Dijkstra's algorithm with plus and better replacing addition
+// and less than.  It is valid only for algebras where better is monotone
+// with respect to plus:  Extending a path must not make it better, that is
+// better(plus(a, w), a) must be false for all path values a and weights w,
+// and extending two paths by the same arc must not reverse their order, that
+// is better(a, b) must imply !better(plus(b, w), plus(a, w)).  Sums of
+// non-negative weights under less than, products of reliabilities in the
+// range [0, 1] under greater than, and minimums under greater than, that is,
+// path widths, all qualify.  For algebras that violate the precondition,
+// results are not meaningful.  Paths are returned as a tree, so each path
+// found is also optimal to each node along it.  Of such paths to a node,
+// one with the minimum number of nodes is returned.
+//
+// Returned are the value of each node and a FromList encoding the paths.
+// Unreached nodes have path length 0 in the FromList.  MaxLen of the
+// FromList is valid.
+//
+// See SemiringShortest, SemiringReliable, and SemiringWidest for common
+// algebras.
+func (g LabeledAdjacencyList) SemiringPath(start NI, zero, one float64, plus func(a, b float64) float64, better func(a, b float64) bool, w WeightFunc) (vals []float64, f FromList) {
+	vals = make([]float64, len(g))
+	for i := range vals {
+		vals[i] = zero
+	}
+	f = NewFromList(len(g))
+	p := f.Paths
+	vals[start] = one
+	p[start] = PathEnd{From: -1, Len: 1}
+	f.MaxLen = 1
+	h := &srHeap{vals: vals, better: better, p: p, fx: make([]int, len(g))}
+	done := make([]bool, len(g))
+	for u := start; ; {
+		done[u] = true
+		nextLen := p[u].Len + 1
+		for _, nb := range g[u] {
+			v := nb.To
+			if done[v] {
+				continue
+			}
+			cand := plus(vals[u], w(nb.Label))
+			switch {
+			case better(cand, vals[v]):
+			case p[v].Len > 0 && cand == vals[v] && nextLen < p[v].Len:
+			default:
+				continue
+			}
+			reached := p[v].Len > 0
+			vals[v] = cand
+			p[v] = PathEnd{From: u, Len: nextLen}
+			if reached {
+				heap.Fix(h, h.fx[v])
+			} else {
+				heap.Push(h, v)
+			}
+			if nextLen > f.MaxLen {
+				f.MaxLen = nextLen
+			}
+		}
+		if h.Len() == 0 {
+			return
+		}
+		u = heap.Pop(h).(NI)
+	}
+}
+
+// SemiringShortest finds shortest paths from start, where the distance of a
+// path is the sum of its arc weights.
+//
+// It is SemiringPath with zero = +Inf and one = 0.  Arc weights must be
+// non-negative.  Distances are as returned by Dijkstra.
+func (g LabeledAdjacencyList) SemiringShortest(start NI, w WeightFunc) (dist []float64, f FromList) {
+	return g.SemiringPath(start, math.Inf(1), 0,
+		func(a, b float64) float64 { return a + b },
+		func(a, b float64) bool { return a < b }, w)
+}
+
+// SemiringReliable finds most reliable paths from start, where the
+// reliability of a path is the product of its arc weights.
+//
+// Arc weights are arc reliabilities, probabilities in the range [0, 1].  It
+// is SemiringPath with zero = 0 and one = 1.  Nodes reachable only with
+// reliability 0 are unreached.
+func (g LabeledAdjacencyList) SemiringReliable(start NI, p WeightFunc) (rel []float64, f FromList) {
+	return g.SemiringPath(start, 0, 1,
+		func(a, b float64) float64 { return a * b },
+		func(a, b float64) bool { return a > b }, p)
+}
+
+// SemiringWidest finds widest paths from start, where the width of a path
+// is its minimum arc weight.
+//
+// It is SemiringPath with zero = -Inf and one = +Inf.  Widths are as
+// returned by Widest.
+func (g LabeledAdjacencyList) SemiringWidest(start NI, w WeightFunc) (width []float64, f FromList) {
+	return g.SemiringPath(start, math.Inf(-1), math.Inf(1), math.Min,
+		func(a, b float64) bool { return a > b }, w)
+}
+
+// srHeap implements container/heap for SemiringPath.  It holds tentative
+// nodes ordered by better on vals, then by path length in p.  Field fx holds
+// the heap index of each node.
+type srHeap struct {
+	nodes  []NI
+	vals   []float64
+	better func(a, b float64) bool
+	p      []PathEnd
+	fx     []int
+}
+
+func (h srHeap) Len() int { return len(h.nodes) }
+func (h srHeap) Less(i, j int) bool {
+	a, b := h.nodes[i], h.nodes[j]
+	return h.better(h.vals[a], h.vals[b]) ||
+		h.vals[a] == h.vals[b] && h.p[a].Len < h.p[b].Len
+}
+func (h srHeap) Swap(i, j int) {
+	h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i]
+	h.fx[h.nodes[i]] = i
+	h.fx[h.nodes[j]] = j
+}
+func (h *srHeap) Push(x interface{}) {
+	n := x.(NI)
+	h.fx[n] = len(h.nodes)
+	h.nodes = append(h.nodes, n)
+}
+func (h *srHeap) Pop() interface{} {
+	last := len(h.nodes) - 1
+	n := h.nodes[last]
+	h.nodes = h.nodes[:last]
+	return n
+}
@@ -0,0 +1,166 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledAdjacencyList_SemiringReliable() {
+	// arcs (reliabilities):
+	//   0->1 (.9)  0->2 (.5)
+	//   1->2 (.8)  2->3 (.9)
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}, {To: 2, Label: 1}},
+		1: {{To: 2, Label: 2}},
+		2: {{To: 3, Label: 3}},
+		3: {},
+	}
+	p := []float64{.9, .5, .8, .9}
+	rel, f := g.SemiringReliable(0, func(l graph.LI) float64 { return p[l] })
+	for n, r := range rel {
+		fmt.Printf("%d  %.3f  %v\n", n, r, f.PathTo(graph.NI(n), nil))
+	}
+	// Output:
+	// 0  1.000  [0]
+	// 1  0.900  [0 1]
+	// 2  0.720  [0 1 2]
+	// 3  0.648  [0 1 2 3]
+}
+
+func ExampleLabeledAdjacencyList_SemiringPath() {
+	// minimax:  minimize the maximum arc weight along a path
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 5}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 3}, {To: 2, Label: 4}},
+		2: {{To: 3, Label: 4}},
+		3: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	vals, f := g.SemiringPath(0, math.Inf(1), math.Inf(-1), math.Max,
+		func(a, b float64) bool { return a < b }, w)
+	fmt.Println(vals[3], f.PathTo(3, nil))
+	// Output:
+	// 4 [0 2 3]
+}
+
+// randomWeighted returns a random directed graph with arc labels indexing
+// the returned weight table, weights drawn by wt.
+func randomWeighted(n, ma int, r *rand.Rand, wt func() float64) (graph.LabeledAdjacencyList, []float64) {
+	d := graph.GnmDirected(n, ma, r)
+	g := make(graph.LabeledAdjacencyList, n)
+	var ws []float64
+	for fr, to := range d.AdjacencyList {
+		for _, to := range to {
+			g[fr] = append(g[fr], graph.Half{to, graph.LI(len(ws))})
+			ws = append(ws, wt())
+		}
+	}
+	return g, ws
+}
+
+func TestSemiringShortest(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 20; i++ {
+		g, wt := randomWeighted(30, 60, r,
+			func() float64 { return float64(r.Intn(10)) })
+		w := func(l graph.LI) float64 { return wt[l] }
+		start := graph.NI(r.Intn(len(g)))
+		df, _, dist, _ := g.Dijkstra(start, -1, w)
+		sv, sf := g.SemiringShortest(start, w)
+		for n := range g {
+			dl, sl := df.Paths[n].Len, sf.Paths[n].Len
+			if dl != sl {
+				t.Fatal(i, n, "path len", sl, "Dijkstra", dl)
+			}
+			if dl == 0 {
+				if !math.IsInf(sv[n], 1) {
+					t.Fatal(i, n, "unreached", sv[n])
+				}
+			} else if sv[n] != dist[n] {
+				t.Fatal(i, n, "dist", sv[n], "Dijkstra", dist[n])
+			}
+		}
+	}
+}
+
+func TestSemiringWidest(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 20; i++ {
+		g, wt := randomWeighted(30, 60, r,
+			func() float64 { return float64(r.Intn(10)) })
+		w := func(l graph.LI) float64 { return wt[l] }
+		start := graph.NI(r.Intn(len(g)))
+		wf, _, width, _ := g.Widest(start, -1, w)
+		sv, sf := g.SemiringWidest(start, w)
+		for n := range g {
+			if sv[n] != width[n] {
+				t.Fatal(i, n, "width", sv[n], "Widest", width[n])
+			}
+			if sf.Paths[n].Len != wf.Paths[n].Len {
+				t.Fatal(i, n, "path len", sf.Paths[n].Len,
+					"Widest", wf.Paths[n].Len)
+			}
+		}
+	}
+}
+
+// TestSemiringReliable checks reliabilities against Dijkstra distances with
+// weights -log(p).
+func TestSemiringReliable(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 20; i++ {
+		g, p := randomWeighted(30, 60, r, func() float64 {
+			if r.Intn(10) == 0 {
+				return 0
+			}
+			return r.Float64()
+		})
+		start := graph.NI(r.Intn(len(g)))
+		rel, f := g.SemiringReliable(start,
+			func(l graph.LI) float64 { return p[l] })
+		// arcs of reliability 0 are removed for Dijkstra
+		h := make(graph.LabeledAdjacencyList, len(g))
+		for fr, to := range g {
+			for _, to := range to {
+				if p[to.Label] > 0 {
+					h[fr] = append(h[fr], to)
+				}
+			}
+		}
+		df, _, dist, _ := h.Dijkstra(start, -1,
+			func(l graph.LI) float64 { return -math.Log(p[l]) })
+		for n := range g {
+			if df.Paths[n].Len == 0 {
+				if rel[n] != 0 || f.Paths[n].Len != 0 {
+					t.Fatal(i, n, "unreached", rel[n])
+				}
+				continue
+			}
+			if want := math.Exp(-dist[n]); math.Abs(rel[n]-want) > 1e-12 {
+				t.Fatal(i, n, "reliability", rel[n], "want", want)
+			}
+			// the returned path has the returned reliability
+			x := 1.
+			for m := graph.NI(n); f.Paths[m].From >= 0; m = f.Paths[m].From {
+				fr := f.Paths[m].From
+				b := 0.
+				for _, h := range g[fr] {
+					if h.To == m && p[h.Label] > b {
+						b = p[h.Label]
+					}
+				}
+				x *= b
+			}
+			if math.Abs(x-rel[n]) > 1e-12 {
+				t.Fatal(i, n, "path reliability", x, "returned", rel[n])
+			}
+		}
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// seriesparallel.go -- recognition and decomposition of two-terminal
+// series-parallel graphs.
+
+import "math"
+
+// SPKind is the kind of a node of a series-parallel decomposition tree.
+type SPKind int8
+
+const (
+	SPEdge     SPKind = iota // a single edge of the graph
+	SPSeries                 // two subgraphs joined at a shared terminal
+	SPParallel               // two subgraphs joined at both terminals
+)
+
+// String returns "edge", "series", or "parallel".
+func (k SPKind) String() string {
+	switch k {
+	case SPEdge:
+		return "edge"
+	case SPSeries:
+		return "series"
+	}
+	return "parallel"
+}
+
+// SPNode is a node of a series-parallel decomposition tree, representing a
+// two-terminal subgraph with terminals N1 and N2.
+//
+// For kind SPEdge, Edge is the edge of the graph and Children is nil.
+// Otherwise Children holds the indexes in SPTree.Nodes of the two subgraphs
+// combined.  For SPSeries, the first child has terminals N1 and the shared
+// node, the second has the shared node and N2, in either order.  For
+// SPParallel, both children have terminals N1 and N2, in either order.
+type SPNode struct {
+	Kind     SPKind
+	N1, N2   NI
+	Edge     LabeledEdge
+	Children []int
+}
+
+// SPTree is a series-parallel decomposition tree.
+//
+// Nodes are in bottom-up order, children before parents, and the root is the
+// last node.  The root has the terminals of the graph as N1 and N2.
+type SPTree struct {
+	Nodes []SPNode
+}
+
+// SeriesParallel recognizes a two-terminal series-parallel graph and
+// constructs its decomposition tree.
+//
+// Graph g is two-terminal series-parallel with terminals s and t if it can be
+// reduced to a single edge from s to t by repeatedly replacing a pair of
+// parallel edges with a single edge (parallel reduction) and replacing a node
+// other than s or t with exactly two edges by a single edge (series
+// reduction).  Loops and isolated nodes are ignored.  Reduction uses a queue
+// of nodes of degree 2 and a map of node pairs to detect parallel edges, in
+// time O(m) for m edges.
+//
+// If g is series-parallel, SeriesParallel returns the decomposition tree and
+// ok = true.  Otherwise it returns ok = false and, if g with an added edge
+// s-t contains a subdivision of K4, a witness subdivision.  The witness is
+// six paths, each a list of nodes, joining pairs of four branch nodes.  A
+// path may use the added edge s-t.  If g with an added edge s-t contains no
+// subdivision of K4, g fails to be series-parallel by not being 2-connected
+// with the added edge, for example with s and t disconnected or with a
+// dangling subgraph, and the witness is nil.  Witness construction takes
+// time O(m^2).
+func (g LabeledUndirected) SeriesParallel(s, t NI) (tree *SPTree, witness [][]NI, ok bool) {
+	var e []LabeledEdge
+	g.Edges(func(l LabeledEdge) {
+		if l.N1 != l.N2 {
+			e = append(e, l)
+		}
+	})
+	if s != t {
+		r := newSPReducer(g.Order(), s, t, false)
+		r.tree = &SPTree{}
+		for _, l := range e {
+			r.addEdge(l.N1, l.N2, r.leaf(l))
+		}
+		r.run()
+		if _, ok := r.pair[spPair(s, t)]; ok && r.live == 1 {
+			return r.tree, nil, true
+		}
+	}
+	return nil, spWitness(g.Order(), e, s, t), false
+}
+
+// Eval evaluates a function of a series-parallel graph bottom up over the
+// decomposition tree.
+//
+// Function edge gives the value of a single edge.  Functions series and
+// parallel combine the values of two subgraphs joined in series and in
+// parallel.
+func (t *SPTree) Eval(edge func(LabeledEdge) float64, series, parallel func(a, b float64) float64) float64 {
+	v := make([]float64, len(t.Nodes))
+	for i, n := range t.Nodes {
+		switch n.Kind {
+		case SPEdge:
+			v[i] = edge(n.Edge)
+		case SPSeries:
+			v[i] = series(v[n.Children[0]], v[n.Children[1]])
+		default:
+			v[i] = parallel(v[n.Children[0]], v[n.Children[1]])
+		}
+	}
+	return v[len(v)-1]
+}
+
+// Reliability computes the probability that the terminals remain connected
+// when edges fail independently.
+//
+// Function failProb gives the failure probability of an edge from its label,
+// as for LabeledUndirected.ReliabilityExact.  The result is exact, computed
+// in time linear in the size of the tree.
+func (t *SPTree) Reliability(failProb func(LI) float64) float64 {
+	return t.Eval(func(e LabeledEdge) float64 { return 1 - failProb(e.LI) },
+		func(a, b float64) float64 { return a * b },
+		func(a, b float64) float64 { return 1 - (1-a)*(1-b) })
+}
+
+// ShortestPath returns the shortest path distance between the terminals,
+// with edge weights given by w.
+func (t *SPTree) ShortestPath(w WeightFunc) float64 {
+	return t.Eval(func(e LabeledEdge) float64 { return w(e.LI) },
+		func(a, b float64) float64 { return a + b },
+		math.Min)
+}
+
+// WidestPath returns the maximum width of a path between the terminals,
+// where the width of a path is the minimum of its edge weights given by w.
+func (t *SPTree) WidestPath(w WeightFunc) float64 {
+	return t.Eval(func(e LabeledEdge) float64 { return w(e.LI) },
+		math.Min, math.Max)
+}
+
+// spReducer reduces a multigraph by series and parallel reductions.
+type spReducer struct {
+	ends  []Edge       // end nodes of each edge
+	item  []int        // tree node of each edge
+	gone  []bool       // true for removed edges
+	inc   [][]int      // edges incident to each node, possibly removed
+	deg   []int        // number of edges incident to each node
+	pair  map[Edge]int // edge between each pair of nodes
+	live  int          // number of edges not removed
+	q     []NI         // nodes to check for reduction
+	s, t  NI           // terminals, not series reduced, or -1
+	prune bool         // remove nodes of degree 1
+	tree  *SPTree      // tree to build, or nil
+}
+
+func newSPReducer(n int, s, t NI, prune bool) *spReducer {
+	return &spReducer{
+		inc:   make([][]int, n),
+		deg:   make([]int, n),
+		pair:  map[Edge]int{},
+		s:     s,
+		t:     t,
+		prune: prune,
+	}
+}
+
+// spPair returns a key for the edge between u and v.
+func spPair(u, v NI) Edge {
+	if u > v {
+		u, v = v, u
+	}
+	return Edge{u, v}
+}
+
+// leaf adds a leaf for edge l to the tree being built and returns its index.
+func (r *spReducer) leaf(l LabeledEdge) int {
+	if r.tree == nil {
+		return -1
+	}
+	r.tree.Nodes = append(r.tree.Nodes,
+		SPNode{Kind: SPEdge, N1: l.N1, N2: l.N2, Edge: l})
+	return len(r.tree.Nodes) - 1
+}
+
+// join adds a node combining tree nodes a and b and returns its index.
+func (r *spReducer) join(k SPKind, n1, n2 NI, a, b int) int {
+	if r.tree == nil {
+		return -1
+	}
+	r.tree.Nodes = append(r.tree.Nodes,
+		SPNode{Kind: k, N1: n1, N2: n2, Children: []int{a, b}})
+	return len(r.tree.Nodes) - 1
+}
+
+// addEdge adds an edge between distinct nodes u and v represented by tree
+// node x, applying a parallel reduction if there is already an edge between
+// u and v.
+func (r *spReducer) addEdge(u, v NI, x int) {
+	k := spPair(u, v)
+	if p, ok := r.pair[k]; ok {
+		r.item[p] = r.join(SPParallel, u, v, r.item[p], x)
+		return
+	}
+	id := len(r.ends)
+	r.ends = append(r.ends, Edge{u, v})
+	r.item = append(r.item, x)
+	r.gone = append(r.gone, false)
+	r.pair[k] = id
+	r.inc[u] = append(r.inc[u], id)
+	r.inc[v] = append(r.inc[v], id)
+	r.deg[u]++
+	r.deg[v]++
+	r.live++
+	r.q = append(r.q, u, v)
+}
+
+// removeEdge removes edge id and returns its tree node.
+func (r *spReducer) removeEdge(id int) int {
+	e := r.ends[id]
+	r.gone[id] = true
+	delete(r.pair, spPair(e.N1, e.N2))
+	r.deg[e.N1]--
+	r.deg[e.N2]--
+	r.live--
+	r.q = append(r.q, e.N1, e.N2)
+	return r.item[id]
+}
+
+// liveEdges returns the edges incident to v that are not removed, compacting
+// the incidence list of v.
+func (r *spReducer) liveEdges(v NI) []int {
+	inc := r.inc[v][:0]
+	for _, id := range r.inc[v] {
+		if !r.gone[id] {
+			inc = append(inc, id)
+		}
+	}
+	r.inc[v] = inc
+	return inc
+}
+
+// run applies reductions until none apply.
+func (r *spReducer) run() {
+	for len(r.q) > 0 {
+		v := r.q[len(r.q)-1]
+		r.q = r.q[:len(r.q)-1]
+		if v == r.s || v == r.t {
+			continue
+		}
+		switch r.deg[v] {
+		case 1:
+			if r.prune {
+				r.removeEdge(r.liveEdges(v)[0])
+			}
+		case 2:
+			inc := r.liveEdges(v)
+			e1, e2 := inc[0], inc[1]
+			x := r.ends[e1].N1
+			if x == v {
+				x = r.ends[e1].N2
+			}
+			y := r.ends[e2].N1
+			if y == v {
+				y = r.ends[e2].N2
+			}
+			a := r.removeEdge(e1)
+			b := r.removeEdge(e2)
+			r.addEdge(x, y, r.join(SPSeries, x, y, a, b))
+		}
+	}
+}
+
+// spHasK4 returns true if the graph of n nodes with edges e has a K4
+// subdivision, where edges with omit set are excluded.
+//
+// A graph has no K4 subdivision if and only if it reduces to no edges by
+// series and parallel reductions and removal of nodes of degree 1.
+func spHasK4(n int, e []Edge, omit []bool) bool {
+	r := newSPReducer(n, -1, -1, true)
+	for i, e := range e {
+		if !omit[i] {
+			r.addEdge(e.N1, e.N2, -1)
+		}
+	}
+	r.run()
+	return r.live > 0
+}
+
+// spWitness returns a K4 subdivision of the graph of n nodes with edges e
+// and an added edge s-t, or nil if there is none.
+func spWitness(n int, e []LabeledEdge, s, t NI) [][]NI {
+	// the added edge is last so that it is used only if needed
+	ends := make([]Edge, 0, len(e)+1)
+	for _, l := range e {
+		ends = append(ends, l.Edge)
+	}
+	if s != t {
+		ends = append(ends, Edge{s, t})
+	}
+	omit := make([]bool, len(ends))
+	if !spHasK4(n, ends, omit) {
+		return nil
+	}
+	// remove edges while a K4 subdivision remains.  what remains is a
+	// K4 subdivision.
+	for i := range ends {
+		omit[i] = true
+		if !spHasK4(n, ends, omit) {
+			omit[i] = false
+		}
+	}
+	a := make(AdjacencyList, n)
+	for i, e := range ends {
+		if !omit[i] {
+			a[e.N1] = append(a[e.N1], e.N2)
+			a[e.N2] = append(a[e.N2], e.N1)
+		}
+	}
+	// trace paths from each branch node to branch nodes of higher number
+	var w [][]NI
+	for b, to := range a {
+		if len(to) != 3 {
+			continue
+		}
+		for _, to := range to {
+			p := []NI{NI(b), to}
+			for len(a[to]) == 2 {
+				nx := a[to][0]
+				if nx == p[len(p)-2] {
+					nx = a[to][1]
+				}
+				p = append(p, nx)
+				to = nx
+			}
+			if to > NI(b) {
+				w = append(w, p)
+			}
+		}
+	}
+	return w
+}
@@ -0,0 +1,272 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledUndirected_SeriesParallel() {
+	// edge labels are failure probabilities in percent
+	//      (10)   (10)
+	//    0 ---- 1 ---- 2
+	//    |             |
+	//     \---- 3 ----/
+	//      (20)   (20)
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 10)
+	g.AddEdge(graph.Edge{1, 2}, 10)
+	g.AddEdge(graph.Edge{0, 3}, 20)
+	g.AddEdge(graph.Edge{3, 2}, 20)
+	tree, _, ok := g.SeriesParallel(0, 2)
+	fmt.Println(ok)
+	for i, n := range tree.Nodes {
+		if n.Kind == graph.SPEdge {
+			fmt.Println(i, n.Kind, n.N1, n.N2, "label", n.Edge.LI)
+		} else {
+			fmt.Println(i, n.Kind, n.N1, n.N2, "children", n.Children)
+		}
+	}
+	f := func(l graph.LI) float64 { return float64(l) / 100 }
+	fmt.Printf("reliability %.4f\n", tree.Reliability(f))
+	// Output:
+	// true
+	// 0 edge 1 0 label 10
+	// 1 edge 2 1 label 10
+	// 2 edge 3 0 label 20
+	// 3 edge 3 2 label 20
+	// 4 series 0 2 children [2 3]
+	// 5 series 0 2 children [0 1]
+	// 6 parallel 0 2 children [4 5]
+	// reliability 0.9316
+}
+
+func ExampleLabeledUndirected_SeriesParallel_k4() {
+	var g graph.LabeledUndirected
+	for n1 := graph.NI(0); n1 < 4; n1++ {
+		for n2 := n1 + 1; n2 < 4; n2++ {
+			g.AddEdge(graph.Edge{n1, n2}, 0)
+		}
+	}
+	_, witness, ok := g.SeriesParallel(0, 1)
+	fmt.Println(ok)
+	for _, p := range witness {
+		fmt.Println(p)
+	}
+	// Output:
+	// false
+	// [0 2]
+	// [0 3]
+	// [0 1]
+	// [1 2]
+	// [1 3]
+	// [2 3]
+}
+
+// ladder returns a ladder of k rungs, with rails 0, 2, 4, ... and
+// 1, 3, 5, ..., and rungs 0-1, 2-3, ...  Labels are 0 for rails and 1 for
+// rungs.
+func ladder(k int) (g graph.LabeledUndirected) {
+	g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, 2*k)
+	for i := 0; i < k; i++ {
+		u, w := graph.NI(2*i), graph.NI(2*i+1)
+		g.AddEdge(graph.Edge{u, w}, 1)
+		if i > 0 {
+			g.AddEdge(graph.Edge{u - 2, u}, 0)
+			g.AddEdge(graph.Edge{w - 2, w}, 0)
+		}
+	}
+	return
+}
+
+func TestSeriesParallelLadder(t *testing.T) {
+	q := []float64{.1, .3} // failure probabilities of rails, rungs
+	f := func(l graph.LI) float64 { return q[l] }
+	for k := 1; k < 8; k++ {
+		g := ladder(k)
+		tree, _, ok := g.SeriesParallel(0, 1)
+		if !ok {
+			t.Fatal(k, "ladder not series-parallel")
+		}
+		// closed form by recurrence from the far rung:  r is the
+		// reliability between the ends of rung i of the ladder beyond it.
+		pr, pg := 1-q[0], 1-q[1]
+		r := pg
+		for i := k - 2; i >= 0; i-- {
+			r = 1 - (1-pg)*(1-pr*pr*r)
+		}
+		if got := tree.Reliability(f); math.Abs(got-r) > 1e-12 {
+			t.Fatal(k, "reliability", got, "want", r)
+		}
+		if k <= 4 {
+			want, _ := g.ReliabilityExact(0, 1, f)
+			if got := tree.Reliability(f); math.Abs(got-want) > 1e-12 {
+				t.Fatal(k, "reliability", got, "exact", want)
+			}
+		}
+		// diagonal corners are not series-parallel terminals beyond k = 2
+		_, w, ok := g.SeriesParallel(0, graph.NI(2*k-1))
+		if ok != (k <= 2) || (k > 2) != (w != nil) {
+			t.Fatal(k, "diagonal terminals", ok, w)
+		}
+	}
+}
+
+func TestSeriesParallelNested(t *testing.T) {
+	// n parallel paths between 0 and 1, path i having i+1 edges.
+	// edge failure probability q.
+	q := .2
+	f := func(graph.LI) float64 { return q }
+	var g graph.LabeledUndirected
+	next := graph.NI(2)
+	fail := 1.
+	for i := 0; i < 5; i++ {
+		fr := graph.NI(0)
+		for j := 0; j < i; j++ {
+			g.AddEdge(graph.Edge{fr, next}, 0)
+			fr = next
+			next++
+		}
+		g.AddEdge(graph.Edge{fr, 1}, 0)
+		fail *= 1 - math.Pow(1-q, float64(i+1))
+	}
+	tree, _, ok := g.SeriesParallel(0, 1)
+	if !ok {
+		t.Fatal("not series-parallel")
+	}
+	if got := tree.Reliability(f); math.Abs(got-(1-fail)) > 1e-12 {
+		t.Fatal("reliability", got, "want", 1-fail)
+	}
+	w := func(graph.LI) float64 { return 1 }
+	if d := tree.ShortestPath(w); d != 1 {
+		t.Fatal("shortest", d)
+	}
+}
+
+func TestSeriesParallelRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(49))
+	wt := make([]float64, 20)
+	for i := range wt {
+		wt[i] = float64(1 + r.Intn(9))
+	}
+	w := func(l graph.LI) float64 { return wt[l] }
+	f := func(l graph.LI) float64 { return wt[l] / 10 }
+	nSP, nWitness := 0, 0
+	for i := 0; i < 500; i++ {
+		// build by random series and parallel compositions, then
+		// sometimes add a random edge.
+		g := graph.LabeledUndirected{graph.LabeledAdjacencyList{nil, nil}}
+		var edges []graph.Edge
+		edges = append(edges, graph.Edge{0, 1})
+		next := graph.NI(2)
+		for m := r.Intn(8); m > 0; m-- {
+			x := r.Intn(len(edges))
+			if r.Intn(2) == 0 {
+				edges = append(edges, edges[x]) // parallel
+			} else { // series
+				e := edges[x]
+				edges[x] = graph.Edge{e.N1, next}
+				edges = append(edges, graph.Edge{next, e.N2})
+				next++
+			}
+		}
+		if r.Intn(2) == 0 {
+			edges = append(edges, graph.Edge{graph.NI(r.Intn(int(next))),
+				graph.NI(r.Intn(int(next)))})
+		}
+		for _, e := range edges {
+			g.AddEdge(e, graph.LI(r.Intn(len(wt))))
+		}
+		tree, witness, ok := g.SeriesParallel(0, 1)
+		if !ok {
+			if witness != nil {
+				nWitness++
+				checkK4Witness(t, i, g, witness, 0, 1)
+			}
+			continue
+		}
+		nSP++
+		if len(edges) <= 12 {
+			want, _ := g.ReliabilityExact(0, 1, f)
+			if got := tree.Reliability(f); math.Abs(got-want) > 1e-12 {
+				t.Fatal(i, "reliability", got, "exact", want)
+			}
+		}
+		_, d := g.DijkstraPath(0, 1, w)
+		if got := tree.ShortestPath(w); got != d {
+			t.Fatal(i, "shortest", got, "Dijkstra", d)
+		}
+		_, wd := g.WidestPath(0, 1, w)
+		if got := tree.WidestPath(w); got != wd {
+			t.Fatal(i, "widest", got, "WidestPath", wd)
+		}
+	}
+	if nSP == 0 || nWitness == 0 {
+		t.Fatal("series-parallel", nSP, "witnesses", nWitness)
+	}
+}
+
+// checkK4Witness checks that witness is a K4 subdivision in g with an
+// added edge s-t.
+func checkK4Witness(t *testing.T, i int, g graph.LabeledUndirected, witness [][]graph.NI, s, tn graph.NI) {
+	if len(witness) != 6 {
+		t.Fatal(i, "witness", witness)
+	}
+	branch := map[graph.NI]int{}
+	inner := map[graph.NI]bool{}
+	pairs := map[graph.Edge]bool{}
+	for _, p := range witness {
+		n1, n2 := p[0], p[len(p)-1]
+		branch[n1]++
+		branch[n2]++
+		if n1 > n2 {
+			n1, n2 = n2, n1
+		}
+		pairs[graph.Edge{n1, n2}] = true
+		for x, n := range p[1:] {
+			fr := p[x]
+			if has, _ := g.HasArc(fr, n); !has &&
+				!(fr == s && n == tn || fr == tn && n == s) {
+				t.Fatal(i, "witness path", p, "no edge", fr, n)
+			}
+			if x < len(p)-2 {
+				if inner[n] {
+					t.Fatal(i, "witness paths not disjoint at", n)
+				}
+				inner[n] = true
+			}
+		}
+	}
+	if len(branch) != 4 || len(pairs) != 6 {
+		t.Fatal(i, "witness", witness)
+	}
+	for b, d := range branch {
+		if d != 3 || inner[b] {
+			t.Fatal(i, "witness", witness)
+		}
+	}
+}
+
+func TestSeriesParallelK4(t *testing.T) {
+	// K4 with each edge subdivided once, terminals on a subdivision node
+	var g graph.LabeledUndirected
+	next := graph.NI(4)
+	for n1 := graph.NI(0); n1 < 4; n1++ {
+		for n2 := n1 + 1; n2 < 4; n2++ {
+			g.AddEdge(graph.Edge{n1, next}, 0)
+			g.AddEdge(graph.Edge{next, n2}, 0)
+			next++
+		}
+	}
+	_, witness, ok := g.SeriesParallel(4, 9)
+	if ok {
+		t.Fatal("subdivided K4 recognized as series-parallel")
+	}
+	checkK4Witness(t, 0, g, witness, 4, 9)
+}
@@ -0,0 +1,214 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// simplify.go -- removal of loops and parallel arcs.
+
+// NonSimpleArc finds an arc that keeps g from being simple.
+//
+// A graph is simple if it has no loops or parallel arcs.  NonSimpleArc
+// returns the first arc, in order of from-node and then arc list index,
+// that is a loop or that goes to the same node as an earlier arc in the
+// same arc list.  The arc is g[fr][x].  If g is simple, the method returns
+// false, -1, -1.
+//
+// Unlike IsSimple, which returns only a node, the result identifies the
+// offending arc.  Time is O(n+m), without copying or sorting arc lists.
+func (g AdjacencyList) NonSimpleArc() (found bool, fr NI, x int) {
+	last := make([]NI, len(g)) // from-node most recently listing each node, +1
+	for n, to := range g {
+		for x, to := range to {
+			if to == NI(n) || last[to] == NI(n)+1 {
+				return true, NI(n), x
+			}
+			last[to] = NI(n) + 1
+		}
+	}
+	return false, -1, -1
+}
+
+// Simplify returns a simple copy of g, with loops removed and parallel arcs
+// replaced by a single arc.
+//
+// Of each set of parallel arcs, the first in the arc list is kept.  Arc
+// lists are otherwise in the same order as in g.  Also returned are the
+// number of loops removed and the number of parallel arcs removed.  Loops
+// are counted only as loops, not as parallel arcs.
+//
+// For an undirected graph the result is undirected, with the same edges as
+// the simple graph of g.
+//
+// See also SimplifyInPlace.
+func (g AdjacencyList) Simplify() (s AdjacencyList, removedLoops, removedParallel int) {
+	s = make(AdjacencyList, len(g))
+	removedLoops, removedParallel = g.simplify(s, false)
+	return
+}
+
+// SimplifyInPlace removes loops and parallel arcs from g.
+//
+// It is Simplify without allocating a new graph.  Arc lists of g are
+// shortened in place, leaving their capacity unchanged.
+func (g AdjacencyList) SimplifyInPlace() (removedLoops, removedParallel int) {
+	return g.simplify(g, true)
+}
+
+// simplify writes simplified arc lists of g to s.  If inPlace is true, s
+// must be g.
+func (g AdjacencyList) simplify(s AdjacencyList, inPlace bool) (loops, par int) {
+	last := make([]NI, len(g)) // from-node most recently listing each node, +1
+	for n, to := range g {
+		var t []NI
+		if inPlace {
+			t = to[:0] // write behind reads
+		}
+		for _, to := range to {
+			switch {
+			case to == NI(n):
+				loops++
+			case last[to] == NI(n)+1:
+				par++
+			default:
+				last[to] = NI(n) + 1
+				t = append(t, to)
+			}
+		}
+		s[n] = t
+	}
+	return
+}
+
+// ParallelPolicy chooses a label for the single arc that replaces a set of
+// parallel arcs.
+//
+// The function is called with the labels of the parallel arcs, in arc list
+// order, and returns the label to keep.  It may return any label, not
+// necessarily one of those passed.
+//
+// See KeepFirstLabel and KeepMinLabel.
+type ParallelPolicy func(labels []LI) LI
+
+// KeepFirstLabel is a ParallelPolicy that keeps the label of the first arc.
+func KeepFirstLabel(labels []LI) LI {
+	return labels[0]
+}
+
+// KeepMinLabel returns a ParallelPolicy that keeps the label of minimum
+// weight w.  Of labels with equal minimum weight, the least label is kept,
+// so the label kept does not depend on the order of labels.
+func KeepMinLabel(w WeightFunc) ParallelPolicy {
+	return func(labels []LI) LI {
+		m := labels[0]
+		mw := w(m)
+		for _, l := range labels[1:] {
+			if lw := w(l); lw < mw || lw == mw && l < m {
+				m, mw = l, lw
+			}
+		}
+		return m
+	}
+}
+
+// NonSimpleArc finds an arc that keeps g from being simple.
+//
+// Labels are not considered.  An arc is parallel to an earlier arc going to
+// the same node regardless of labels.  See AdjacencyList.NonSimpleArc.
+func (g LabeledAdjacencyList) NonSimpleArc() (found bool, fr NI, x int) {
+	last := make([]NI, len(g)) // from-node most recently listing each node, +1
+	for n, to := range g {
+		for x, to := range to {
+			if to.To == NI(n) || last[to.To] == NI(n)+1 {
+				return true, NI(n), x
+			}
+			last[to.To] = NI(n) + 1
+		}
+	}
+	return false, -1, -1
+}
+
+// Simplify returns a simple copy of g, with loops removed and parallel arcs
+// replaced by a single arc.
+//
+// Arcs are parallel if they go to the same node, regardless of labels.
+// The replacing arc takes the position in the arc list of the first of the
+// parallel arcs and the label chosen by policy keep.  A nil keep is
+// KeepFirstLabel.  Also returned are the number of loops removed and the
+// number of parallel arcs removed.  Loops are counted only as loops, not
+// as parallel arcs.
+//
+// For an undirected graph the result is undirected if keep chooses the same
+// label regardless of the order of labels, as KeepMinLabel does for example.
+//
+// See also SimplifyInPlace.
+func (g LabeledAdjacencyList) Simplify(keep ParallelPolicy) (s LabeledAdjacencyList, removedLoops, removedParallel int) {
+	s = make(LabeledAdjacencyList, len(g))
+	removedLoops, removedParallel = g.simplify(s, keep, false)
+	return
+}
+
+// SimplifyInPlace removes loops and parallel arcs from g.
+//
+// It is Simplify without allocating a new graph.  Arc lists of g are
+// shortened in place, leaving their capacity unchanged.
+func (g LabeledAdjacencyList) SimplifyInPlace(keep ParallelPolicy) (removedLoops, removedParallel int) {
+	return g.simplify(g, keep, true)
+}
+
+// simplify writes simplified arc lists of g to s.  If inPlace is true, s
+// must be g.
+func (g LabeledAdjacencyList) simplify(s LabeledAdjacencyList, keep ParallelPolicy, inPlace bool) (loops, par int) {
+	last := make([]NI, len(g)) // from-node most recently listing each node, +1
+	pos := make([]int, len(g)) // index in t of arc to each node
+	var grp []int              // index in t of each arc not a loop
+	var lab []LI               // label of each arc not a loop
+	var cnt, next []int        // group offsets in ls
+	var ls []LI                // labels sorted by group
+	for n, to := range g {
+		var t []Half
+		if inPlace {
+			t = to[:0] // write behind reads
+		}
+		grp, lab = grp[:0], lab[:0]
+		np := par
+		for _, h := range to {
+			switch {
+			case h.To == NI(n):
+				loops++
+				continue
+			case last[h.To] == NI(n)+1:
+				par++
+			default:
+				last[h.To] = NI(n) + 1
+				pos[h.To] = len(t)
+				t = append(t, h)
+			}
+			grp = append(grp, pos[h.To])
+			lab = append(lab, h.Label)
+		}
+		if par > np && keep != nil {
+			// counting sort of labels by group, stable so labels of each
+			// group are in arc list order
+			cnt = append(cnt[:0], make([]int, len(t)+1)...)
+			for _, k := range grp {
+				cnt[k+1]++
+			}
+			for k := 1; k < len(cnt); k++ {
+				cnt[k] += cnt[k-1]
+			}
+			ls = append(ls[:0], lab...)
+			next = append(next[:0], cnt...)
+			for i, k := range grp {
+				ls[next[k]] = lab[i]
+				next[k]++
+			}
+			for k := range t {
+				if cnt[k+1]-cnt[k] > 1 {
+					t[k].Label = keep(ls[cnt[k]:cnt[k+1]])
+				}
+			}
+		}
+		s[n] = t
+	}
+	return
+}
@@ -0,0 +1,164 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleAdjacencyList_NonSimpleArc() {
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2, 0, 2},
+		2: {},
+	}
+	fmt.Println(g.NonSimpleArc())
+	// Output:
+	// true 1 2
+}
+
+func ExampleAdjacencyList_Simplify() {
+	g := graph.AdjacencyList{
+		0: {1, 0, 2, 1},
+		1: {2, 2, 1, 1},
+		2: {},
+	}
+	s, loops, par := g.Simplify()
+	fmt.Println(s)
+	fmt.Println(loops, "loops removed")
+	fmt.Println(par, "parallel arcs removed")
+	// Output:
+	// [[1 2] [2] []]
+	// 3 loops removed
+	// 2 parallel arcs removed
+}
+
+func ExampleLabeledAdjacencyList_Simplify() {
+	g := graph.LabeledAdjacencyList{
+		0: {{1, 30}, {2, 5}, {1, 10}, {1, 20}},
+		2: {},
+	}
+	w := func(l graph.LI) float64 { return float64(l) }
+	s, _, _ := g.Simplify(nil)
+	fmt.Println(s)
+	s, _, _ = g.Simplify(graph.KeepMinLabel(w))
+	fmt.Println(s)
+	s, _, _ = g.Simplify(func(ls []graph.LI) (sum graph.LI) {
+		for _, l := range ls {
+			sum += l
+		}
+		return
+	})
+	fmt.Println(s)
+	// Output:
+	// [[{1 30} {2 5}] [] []]
+	// [[{1 10} {2 5}] [] []]
+	// [[{1 60} {2 5}] [] []]
+}
+
+// TestSimplify checks Simplify, SimplifyInPlace, and NonSimpleArc on random
+// graphs with loops and parallel arcs.
+func TestSimplify(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	w := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 50; i++ {
+		const order = 12
+		g := make(graph.LabeledAdjacencyList, order)
+		for a := r.Intn(60); a > 0; a-- {
+			fr := r.Intn(order)
+			g[fr] = append(g[fr], graph.Half{
+				To:    graph.NI(r.Intn(order)),
+				Label: graph.LI(r.Intn(100)),
+			})
+		}
+		// expected: first occurrence order, min label
+		want := make(graph.LabeledAdjacencyList, order)
+		wantLoops, wantPar := 0, 0
+		for fr, to := range g {
+		arcs:
+			for _, h := range to {
+				if h.To == graph.NI(fr) {
+					wantLoops++
+					continue
+				}
+				for x, k := range want[fr] {
+					if k.To == h.To {
+						wantPar++
+						if h.Label < k.Label {
+							want[fr][x].Label = h.Label
+						}
+						continue arcs
+					}
+				}
+				want[fr] = append(want[fr], h)
+			}
+		}
+		found, fr, x := g.NonSimpleArc()
+		if found != (wantLoops+wantPar > 0) {
+			t.Fatal("NonSimpleArc found", found)
+		}
+		if found {
+			h := g[fr][x]
+			dup := h.To == fr
+			for _, k := range g[fr][:x] {
+				dup = dup || k.To == h.To
+			}
+			if !dup {
+				t.Fatal("NonSimpleArc returned simple arc", fr, x)
+			}
+		}
+		s, loops, par := g.Simplify(graph.KeepMinLabel(w))
+		if loops != wantLoops || par != wantPar {
+			t.Fatal("Simplify counts", loops, par, "want", wantLoops, wantPar)
+		}
+		for fr := range s {
+			if len(s[fr]) == 0 && len(want[fr]) == 0 {
+				continue
+			}
+			if !reflect.DeepEqual(s[fr], want[fr]) {
+				t.Fatal("Simplify", fr, s[fr], "want", want[fr])
+			}
+		}
+		if found, _, _ := s.NonSimpleArc(); found {
+			t.Fatal("Simplify result not simple")
+		}
+		u, uLoops, uPar := g.Unlabeled().Simplify()
+		if uLoops != wantLoops || uPar != wantPar || !u.Equal(s.Unlabeled()) {
+			t.Fatal("unlabeled Simplify")
+		}
+		ip := g.Unlabeled()
+		ip.SimplifyInPlace()
+		if fmt.Sprint(ip) != fmt.Sprint(u) {
+			t.Fatal("unlabeled SimplifyInPlace")
+		}
+		loops, par = g.SimplifyInPlace(graph.KeepMinLabel(w))
+		if loops != wantLoops || par != wantPar || fmt.Sprint(g) != fmt.Sprint(s) {
+			t.Fatal("SimplifyInPlace")
+		}
+	}
+}
+
+// TestKeepMinLabelTies checks that KeepMinLabel breaks ties by label value,
+// so that simplifying an undirected graph gives an undirected result.
+func TestKeepMinLabelTies(t *testing.T) {
+	w := func(l graph.LI) float64 { return float64(l % 2) }
+	keep := graph.KeepMinLabel(w)
+	if l := keep([]graph.LI{5, 4, 2, 3}); l != 2 {
+		t.Fatal("kept", l, "want 2")
+	}
+	// reciprocal arcs of parallel edges listed in different orders
+	g := graph.LabeledAdjacencyList{
+		0: {{1, 4}, {1, 2}},
+		1: {{0, 2}, {0, 4}},
+	}
+	s, _, _ := g.Simplify(keep)
+	if ok, _, _ := s.IsUndirected(); !ok {
+		t.Fatal("not undirected", s)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// sortemit.go -- sorted re-emission of results of emitting methods.
+
+import (
+	"sort"
+
+	"github.com/soniakeys/bits"
+)
+
+// SortCliques runs a clique emitting method and emits its results in sorted
+// order.
+//
+// Function run is called once with a collecting function that it should
+// pass as the emit argument of a method such as BronKerbosch1,
+// BronKerbosch2, or BronKerbosch3.  Emitted cliques are copied and buffered.
+// When run returns, cliques are sorted by size, smallest first, then
+// lexicographically by member nodes in increasing order, and passed to emit
+// as long as emit returns true.
+//
+// Emission order then depends only on the set of cliques and not on the
+// algorithm used to find them.  The cost is that all cliques are held in
+// memory at once, one bitmap of the graph order per clique, and that no
+// clique is emitted until all are found.
+func SortCliques(run func(emit func(bits.Bits) bool), emit func(bits.Bits) bool) {
+	var cs []bits.Bits
+	var ms [][]int
+	run(func(c bits.Bits) bool {
+		var b bits.Bits
+		b.Set(c)
+		cs = append(cs, b)
+		ms = append(ms, b.Slice())
+		return true
+	})
+	reemitSorted(len(cs), func(i, j int) bool {
+		mi, mj := ms[i], ms[j]
+		if len(mi) != len(mj) {
+			return len(mi) < len(mj)
+		}
+		for x, n := range mi {
+			if n != mj[x] {
+				return n < mj[x]
+			}
+		}
+		return false
+	}, func(i int) bool { return emit(cs[i]) })
+}
+
+// SortComponents runs a component emitting method and emits its results in
+// sorted order.
+//
+// Function run is called once with a collecting function that it should
+// pass as the emit argument of a method such as StronglyConnectedComponents.
+// Emitted components are copied and buffered.  When run returns, the member
+// nodes of each component are sorted in increasing order and components
+// are sorted by smallest member.  Components are then passed to emit as
+// long as emit returns true.
+//
+// Emission order then depends only on the set of components and not on the
+// algorithm used to find them.  The cost is that all components are held in
+// memory at once and that no component is emitted until all are found.
+func SortComponents(run func(emit func([]NI) bool), emit func([]NI) bool) {
+	var cs [][]NI
+	run(func(c []NI) bool {
+		s := append([]NI{}, c...)
+		sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+		cs = append(cs, s)
+		return true
+	})
+	reemitSorted(len(cs), func(i, j int) bool {
+		// components are disjoint so first members are distinct, but
+		// be deterministic regardless.
+		ci, cj := cs[i], cs[j]
+		for x := 0; x < len(ci) && x < len(cj); x++ {
+			if ci[x] != cj[x] {
+				return ci[x] < cj[x]
+			}
+		}
+		return len(ci) < len(cj)
+	}, func(i int) bool { return emit(cs[i]) })
+}
+
+// reemitSorted sorts indexes 0 through n-1 of buffered results by less and
+// calls emit with each index as long as emit returns true.
+func reemitSorted(n int, less func(i, j int) bool, emit func(i int) bool) {
+	x := make([]int, n)
+	for i := range x {
+		x[i] = i
+	}
+	sort.SliceStable(x, func(i, j int) bool { return less(x[i], x[j]) })
+	for _, i := range x {
+		if !emit(i) {
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/bits"
+	"github.com/soniakeys/graph"
+)
+
+func ExampleSortCliques() {
+	// 0--4--5-
+	//    |  | \
+	//    3--2--1
+	var g graph.Undirected
+	g.AddEdge(0, 4)
+	g.AddEdge(4, 5)
+	g.AddEdge(4, 3)
+	g.AddEdge(3, 2)
+	g.AddEdge(5, 2)
+	g.AddEdge(5, 1)
+	g.AddEdge(2, 1)
+	for _, bk := range []func(func(bits.Bits) bool){
+		g.BronKerbosch1,
+		func(emit func(bits.Bits) bool) { g.BronKerbosch2(g.BKPivotMaxDegree, emit) },
+		func(emit func(bits.Bits) bool) { g.BronKerbosch3(g.BKPivotMaxDegree, emit) },
+	} {
+		graph.SortCliques(bk, func(c bits.Bits) bool {
+			fmt.Print(c.Slice())
+			return true
+		})
+		fmt.Println()
+	}
+	// Output:
+	// [0 4][2 3][3 4][4 5][1 2 5]
+	// [0 4][2 3][3 4][4 5][1 2 5]
+	// [0 4][2 3][3 4][4 5][1 2 5]
+}
+
+func ExampleSortComponents() {
+	// /---0---\
+	// |   |\--/
+	// |   v
+	// |   5<=>4---\
+	// |   |   |   |
+	// v   v   |   |
+	// 7<=>6   |   |
+	//     |   v   v
+	//     \-->3<--2
+	//         |   ^
+	//         |   |
+	//         \-->1
+	g := graph.Directed{graph.AdjacencyList{
+		0: {0, 5, 7},
+		5: {4, 6},
+		4: {5, 2, 3},
+		7: {6},
+		6: {7, 3},
+		3: {1},
+		1: {2},
+		2: {3},
+	}}
+	graph.SortComponents(g.StronglyConnectedComponents, func(c []graph.NI) bool {
+		fmt.Println(c)
+		return true
+	})
+	// Output:
+	// [0]
+	// [1 2 3]
+	// [4 5]
+	// [6 7]
+}
+
+// collectCliques returns the cliques emitted by bk in sorted order.
+func collectCliques(bk func(func(bits.Bits) bool)) (cs [][]int) {
+	graph.SortCliques(bk, func(c bits.Bits) bool {
+		cs = append(cs, c.Slice())
+		return true
+	})
+	return
+}
+
+func TestSortCliques(t *testing.T) {
+	r := rand.New(rand.NewSource(37))
+	for i := 0; i < 50; i++ {
+		n := 1 + r.Intn(25)
+		g := graph.GnmUndirected(n, r.Intn(n*(n-1)/2+1), r)
+		want := collectCliques(g.BronKerbosch1)
+		for j, bk := range []func(func(bits.Bits) bool){
+			func(emit func(bits.Bits) bool) { g.BronKerbosch2(g.BKPivotMaxDegree, emit) },
+			func(emit func(bits.Bits) bool) { g.BronKerbosch2(g.BKPivotMinP, emit) },
+			func(emit func(bits.Bits) bool) { g.BronKerbosch3(g.BKPivotMaxDegree, emit) },
+			func(emit func(bits.Bits) bool) { g.BronKerbosch3(g.BKPivotMinP, emit) },
+		} {
+			if got := collectCliques(bk); !reflect.DeepEqual(got, want) {
+				t.Fatal(i, "variant", j, got, "want", want)
+			}
+		}
+		// sorted by size, then members
+		for x := 1; x < len(want); x++ {
+			a, b := want[x-1], want[x]
+			if len(a) > len(b) {
+				t.Fatal(i, "order", a, b)
+			}
+			if len(a) == len(b) {
+				y := 0
+				for y < len(a) && a[y] == b[y] {
+					y++
+				}
+				if y == len(a) || a[y] > b[y] {
+					t.Fatal(i, "order", a, b)
+				}
+			}
+		}
+	}
+	// emit returning false stops emission
+	g := graph.GnmUndirected(20, 40, r)
+	k := 0
+	graph.SortCliques(g.BronKerbosch1, func(bits.Bits) bool {
+		k++
+		return k < 3
+	})
+	if k != 3 {
+		t.Fatal("emitted", k, "after stop")
+	}
+}
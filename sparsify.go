@@ -0,0 +1,215 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// sparsify.go -- spectral sparsification by effective resistance sampling.
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// SpectralSparsify returns a sparse reweighted approximation of g.
+//
+// The algorithm is that of Spielman and Srivastava.  Edges are sampled with
+// replacement, ⌈n ln n / ε²⌉ times for a graph of n nodes, each time with
+// probability proportional to w(e)·R(e) where R(e) is the effective
+// resistance between the ends of edge e.  Each time an edge is sampled,
+// w(e) divided by the number of samples and by the probability of the edge is
+// added to its new weight.  The result has at most that many edges, and the
+// expected value of its Laplacian quadratic form, and so of the weight of
+// every cut, is that of g.  Spielman and Srivastava show that with a
+// sufficiently large constant factor on the number of samples, all cut
+// weights are within a factor of 1±ε with high probability.  The factor
+// here is 1, a practical choice, and cut weights of the result are typically
+// but not certainly within 1±ε.  If g has no more edges than the number of
+// samples, it is not sparsified and the result is simply a copy of g with
+// its original weights.
+//
+// Effective resistances are estimated by random projection:  ⌈8 ln n⌉
+// Laplacian systems with random right hand sides are solved by Jacobi
+// preconditioned conjugate gradient iteration, directly over the adjacency
+// lists of g.  The estimates are accurate to within a modest constant factor
+// with high probability, which affects only the variance of the sampling,
+// not its expected value.
+//
+// Edge weights must be positive.  Loops are ignored and dropped from the
+// result.  Parallel edges are sampled as distinct edges.
+//
+// Edge labels in the returned graph s are indexes into the returned weight
+// table newWeights.  If Rand r is nil, the rand package default shared source
+// is used.  Otherwise the result is determined by r.
+//
+// An error is returned if epsilon is not positive.
+func (g LabeledUndirected) SpectralSparsify(w WeightFunc, epsilon float64, r *rand.Rand) (s LabeledUndirected, newWeights []float64, err error) {
+	if !(epsilon > 0) {
+		return LabeledUndirected{}, nil, fmt.Errorf("epsilon = %g, must be positive", epsilon)
+	}
+	rf, ri := rand.Float64, rand.Intn
+	if r != nil {
+		rf, ri = r.Float64, r.Intn
+	}
+	a := g.LabeledAdjacencyList
+	n := len(a)
+	var edges []LabeledEdge
+	for fr, to := range a {
+		for _, to := range to {
+			if NI(fr) < to.To {
+				edges = append(edges, LabeledEdge{Edge{NI(fr), to.To}, to.Label})
+			}
+		}
+	}
+	// number of samples, compared as float64 as it may overflow int
+	// for small epsilon.
+	qf := math.Ceil(float64(n) * math.Log(float64(n)) / (epsilon * epsilon))
+	s = LabeledUndirected{make(LabeledAdjacencyList, n)}
+	if !(qf < float64(len(edges))) {
+		for _, e := range edges {
+			s.AddEdge(e.Edge, LI(len(newWeights)))
+			newWeights = append(newWeights, w(e.LI))
+		}
+		return
+	}
+	q := int(qf)
+	res := g.effectiveResistance(w, edges, ri)
+	// cumulative distribution of w·R
+	cum := make([]float64, len(edges))
+	sum := 0.
+	for i, e := range edges {
+		sum += w(e.LI) * res[i]
+		cum[i] = sum
+	}
+	nw := make([]float64, len(edges))
+	for i := 0; i < q; i++ {
+		x := rf() * sum
+		// binary search for the sampled edge
+		lo, hi := 0, len(cum)-1
+		for lo < hi {
+			m := (lo + hi) / 2
+			if cum[m] > x {
+				hi = m
+			} else {
+				lo = m + 1
+			}
+		}
+		p := w(edges[lo].LI) * res[lo] / sum
+		nw[lo] += w(edges[lo].LI) / (float64(q) * p)
+	}
+	for i, e := range edges {
+		if nw[i] > 0 {
+			s.AddEdge(e.Edge, LI(len(newWeights)))
+			newWeights = append(newWeights, nw[i])
+		}
+	}
+	return
+}
+
+// effectiveResistance estimates effective resistances of edges by random
+// projection, following Spielman and Srivastava.
+func (g LabeledUndirected) effectiveResistance(w WeightFunc, edges []LabeledEdge, ri func(int) int) []float64 {
+	n := len(g.LabeledAdjacencyList)
+	k := int(math.Ceil(8 * math.Log(float64(n))))
+	if k < 1 {
+		k = 1
+	}
+	res := make([]float64, len(edges))
+	b := make([]float64, n)
+	x := make([]float64, n)
+	for i := 0; i < k; i++ {
+		// b = Qᵢ W^½ B, a random ±1 combination of the edge vectors
+		for j := range b {
+			b[j] = 0
+		}
+		for _, e := range edges {
+			c := math.Sqrt(w(e.LI))
+			if ri(2) == 0 {
+				c = -c
+			}
+			b[e.N1] += c
+			b[e.N2] -= c
+		}
+		g.laplacianSolve(w, b, x)
+		for j, e := range edges {
+			d := x[e.N1] - x[e.N2]
+			res[j] += d * d
+		}
+	}
+	for j := range res {
+		res[j] /= float64(k)
+	}
+	return res
+}
+
+// laplacianSolve solves L x = b for the weighted Laplacian L of g, by
+// conjugate gradient iteration with a Jacobi preconditioner.
+//
+// b must sum to zero over each connected component.  x is overwritten.
+func (g LabeledUndirected) laplacianSolve(w WeightFunc, b, x []float64) {
+	a := g.LabeledAdjacencyList
+	n := len(a)
+	deg := make([]float64, n)
+	for fr, to := range a {
+		for _, to := range to {
+			if to.To != NI(fr) {
+				deg[fr] += w(to.Label)
+			}
+		}
+	}
+	mul := func(v, out []float64) { // out = L v
+		for fr, to := range a {
+			s := deg[fr] * v[fr]
+			for _, to := range to {
+				if to.To != NI(fr) {
+					s -= w(to.Label) * v[to.To]
+				}
+			}
+			out[fr] = s
+		}
+	}
+	dot := func(u, v []float64) (s float64) {
+		for i, ui := range u {
+			s += ui * v[i]
+		}
+		return
+	}
+	r := append([]float64{}, b...)
+	z := make([]float64, n)
+	precond := func() {
+		for i, ri := range r {
+			if deg[i] > 0 {
+				z[i] = ri / deg[i]
+			} else {
+				z[i] = 0
+			}
+		}
+	}
+	for i := range x {
+		x[i] = 0
+	}
+	precond()
+	p := append([]float64{}, z...)
+	ap := make([]float64, n)
+	rz := dot(r, z)
+	tol := 1e-10 * dot(b, b)
+	for it := 0; it < 10*n && dot(r, r) > tol; it++ {
+		mul(p, ap)
+		pap := dot(p, ap)
+		if pap <= 0 {
+			break
+		}
+		alpha := rz / pap
+		for i := range x {
+			x[i] += alpha * p[i]
+			r[i] -= alpha * ap[i]
+		}
+		precond()
+		rzNew := dot(r, z)
+		beta := rzNew / rz
+		rz = rzNew
+		for i := range p {
+			p[i] = z[i] + beta*p[i]
+		}
+	}
+}
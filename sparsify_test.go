@@ -0,0 +1,104 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+// cutWeight returns the weight of edges of g crossing the cut given by side.
+func cutWeight(g graph.LabeledUndirected, w graph.WeightFunc, side []bool) (c float64) {
+	for fr, to := range g.LabeledAdjacencyList {
+		for _, to := range to {
+			if side[fr] && !side[to.To] {
+				c += w(to.Label)
+			}
+		}
+	}
+	return
+}
+
+func sparsifyTestGraph(r *rand.Rand) (graph.LabeledUndirected, graph.WeightFunc) {
+	g, m := graph.LabeledGnpUndirected(80, .6, r)
+	wt := make([]float64, m)
+	for i := range wt {
+		wt[i] = 1 + 9*r.Float64()
+	}
+	return g, func(l graph.LI) float64 { return wt[l] }
+}
+
+func TestSpectralSparsify(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	g, w := sparsifyTestGraph(r)
+	const eps = .5
+	s, nw, err := g.SpectralSparsify(w, eps, rand.New(rand.NewSource(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws := func(l graph.LI) float64 { return nw[l] }
+	if s.Order() != g.Order() {
+		t.Fatal("order", s.Order())
+	}
+	if s.Size() >= g.Size() {
+		t.Fatal("not sparsified:", s.Size(), "edges from", g.Size())
+	}
+	if len(nw) != s.Size() {
+		t.Fatal(len(nw), "weights for", s.Size(), "edges")
+	}
+	if ok, _, _ := s.IsUndirected(); !ok {
+		t.Fatal("not undirected")
+	}
+	if !s.IsConnected() {
+		t.Fatal("not connected")
+	}
+	side := make([]bool, g.Order())
+	for i := 0; i < 200; i++ {
+		for n := range side {
+			side[n] = r.Intn(2) == 0
+		}
+		c0 := cutWeight(g, w, side)
+		c1 := cutWeight(s, ws, side)
+		if math.Abs(c1-c0) > eps*c0 {
+			t.Fatal("cut weight", c1, "want", c0)
+		}
+	}
+	// deterministic for seeded Rand
+	s2, nw2, _ := g.SpectralSparsify(w, eps, rand.New(rand.NewSource(3)))
+	if !reflect.DeepEqual(s, s2) || !reflect.DeepEqual(nw, nw2) {
+		t.Fatal("not deterministic")
+	}
+}
+
+func TestSpectralSparsifySparse(t *testing.T) {
+	// a path is already sparse and is returned with original weights
+	var g graph.LabeledUndirected
+	wt := []float64{3, 1, 4, 1}
+	for i := range wt {
+		g.AddEdge(graph.Edge{graph.NI(i), graph.NI(i + 1)}, graph.LI(i))
+	}
+	s, nw, err := g.SpectralSparsify(func(l graph.LI) float64 { return wt[l] },
+		.5, rand.New(rand.NewSource(1)))
+	if err != nil || s.Size() != 4 || !reflect.DeepEqual(nw, wt) {
+		t.Fatal(s, nw, err)
+	}
+	// tiny epsilon, number of samples overflows int
+	s, nw, err = g.SpectralSparsify(func(l graph.LI) float64 { return wt[l] },
+		1e-300, nil)
+	if err != nil || s.Size() != 4 || !reflect.DeepEqual(nw, wt) {
+		t.Fatal("tiny epsilon", s, nw, err)
+	}
+	// epsilon must be positive
+	for _, eps := range []float64{0, -.5, math.NaN()} {
+		if _, _, err := g.SpectralSparsify(func(l graph.LI) float64 {
+			return wt[l]
+		}, eps, nil); err == nil {
+			t.Fatal("no error for epsilon", eps)
+		}
+	}
+}
@@ -77,6 +77,46 @@ func (h Heuristic) Admissible(g LabeledAdjacencyList, w WeightFunc, end NI) (boo
 	return true, ""
 }
 
+// AdmissibleGoals returns true if heuristic h is admissible on graph g
+// relative to a set of goal nodes.
+//
+// AdmissibleGoals is like Admissible but compares h at each node to the
+// shortest distance from the node to the nearest goal node, as required
+// for AStarAMultiGoal.  Goals must be a bits.Bits with Num equal to the order
+// of g.
+func (h Heuristic) AdmissibleGoals(g LabeledAdjacencyList, w WeightFunc, goals bits.Bits) (bool, string) {
+	// invert graph, with a virtual start node having zero weight arcs
+	// to all goals.  arcs are relabeled as indexes into a weight table.
+	v := NI(len(g))
+	inv := make(LabeledAdjacencyList, len(g)+1)
+	var wt []float64
+	for from, nbs := range g {
+		for _, nb := range nbs {
+			inv[nb.To] = append(inv[nb.To],
+				Half{To: NI(from), Label: LI(len(wt))})
+			wt = append(wt, w(nb.Label))
+		}
+	}
+	goals.IterateOnes(func(n int) bool {
+		inv[v] = append(inv[v], Half{To: NI(n), Label: LI(len(wt))})
+		wt = append(wt, 0)
+		return true
+	})
+	f, _, dist, _ := inv.Dijkstra(v, -1, func(l LI) float64 { return wt[l] })
+	// compare h to found shortest paths
+	for n := range g {
+		if f.Paths[n].Len == 0 {
+			continue // no path, any heuristic estimate is fine.
+		}
+		if !(h(NI(n)) <= dist[n]) {
+			return false, fmt.Sprintf("h(%d) = %g, "+
+				"required to be <= found shortest path (%g)",
+				n, h(NI(n)), dist[n])
+		}
+	}
+	return true, ""
+}
+
 // Monotonic returns true if heuristic h is monotonic on weighted graph g.
 //
 // If h is non-monotonic, the string result describes a counter example.
@@ -211,6 +251,151 @@ func (g LabeledAdjacencyList) AStarAPath(start, end NI, h Heuristic, w WeightFun
 	return f.PathToLabeled(end, labels, nil), d
 }
 
+// AStarAMultiStart finds a shortest path to end from any of a number of
+// start nodes.
+//
+// The search is a single AStarA search with all start nodes initially
+// reached, each with path distance 0.  The heuristic h is unchanged from
+// that of AStarA; it estimates distance to end and has no knowledge of
+// start nodes.  With an admissible heuristic the path found is a shortest
+// path over all start nodes.  Duplicate start nodes are allowed and a start
+// node may be the end node, in which case the path is the single node end.
+//
+// The start node from which each reached node descends is tracked as paths
+// are updated.  Among paths of equal distance, the one with fewer nodes is
+// preferred as with AStarA, and between paths of equal distance and equal
+// number of nodes, the path found first is kept along with its start node.
+//
+// If a path is found it returns a FromList encoding the path, the arc labels
+// for path nodes, the total path distance, the start node of the path as
+// bestStart, and ok = true.  The FromList is a forest with a root at each
+// start node.  Otherwise it returns ok = false.
+func (g LabeledAdjacencyList) AStarAMultiStart(w WeightFunc, starts []NI, end NI, h Heuristic) (f FromList, labels []LI, dist float64, bestStart NI, ok bool) {
+	// NOTE: largely duplicate code of AStarA, with origin tracking.
+
+	f = NewFromList(len(g))
+	labels = make([]LI, len(g))
+	d := make([]float64, len(g))
+	r := make([]rNode, len(g))
+	origin := make([]NI, len(g)) // start node each reached node descends from
+	for i := range r {
+		r[i].nx = NI(i)
+	}
+	rp := f.Paths
+	var oh openHeap
+	for _, start := range starts {
+		cr := &r[start]
+		if cr.state == reached {
+			continue // duplicate start
+		}
+		cr.state = reached
+		cr.f = h(start)
+		rp[start] = PathEnd{Len: 1, From: -1}
+		origin[start] = start
+		heap.Push(&oh, cr)
+	}
+	for len(oh) > 0 {
+		bestPath := heap.Pop(&oh).(*rNode)
+		bestNode := bestPath.nx
+		if bestNode == end {
+			return f, labels, d[end], origin[end], true
+		}
+		nextLen := rp[bestNode].Len + 1
+		for _, nb := range g[bestNode] {
+			alt := &r[nb.To]
+			ap := &rp[alt.nx]
+			g := d[bestNode] + w(nb.Label)
+			if alt.state == reached {
+				if g > d[nb.To] || g == d[nb.To] && nextLen >= ap.Len {
+					continue
+				}
+			}
+			*ap = PathEnd{From: bestNode, Len: nextLen}
+			labels[nb.To] = nb.Label
+			origin[nb.To] = origin[bestNode]
+			d[nb.To] = g
+			alt.f = g + h(nb.To)
+			switch {
+			case alt.state != reached:
+				alt.state = reached
+				heap.Push(&oh, alt)
+			case alt.fx < 0:
+				heap.Push(&oh, alt)
+			default:
+				heap.Fix(&oh, alt.fx)
+			}
+		}
+	}
+	return f, labels, 0, -1, false
+}
+
+// AStarAMultiGoal finds a shortest path from start to any of a set of goal
+// nodes.
+//
+// The search is an AStarA search that stops when any goal node is popped
+// from the open set.  The heuristic h should estimate distance to the
+// nearest goal, for example as the minimum over goals of a single goal
+// estimate.  With an admissible heuristic the path found is a shortest
+// path to any goal.  See Heuristic.AdmissibleGoals for validating a
+// heuristic against a goal set.  If start is a goal, the search stops
+// immediately with found = start.
+//
+// Goals must be a bits.Bits with Num equal to the order of g.
+//
+// If a path is found it returns a FromList encoding the path, the arc labels
+// for path nodes, the total path distance, the goal node reached as found,
+// and ok = true.  The path can be recovered with f.PathTo(found, nil).
+// Otherwise it returns ok = false.
+func (g LabeledAdjacencyList) AStarAMultiGoal(w WeightFunc, start NI, goals bits.Bits, h Heuristic) (f FromList, labels []LI, dist float64, found NI, ok bool) {
+	// NOTE: largely duplicate code of AStarA, with a goal set.
+
+	f = NewFromList(len(g))
+	labels = make([]LI, len(g))
+	d := make([]float64, len(g))
+	r := make([]rNode, len(g))
+	for i := range r {
+		r[i].nx = NI(i)
+	}
+	cr := &r[start]
+	cr.state = reached
+	cr.f = h(start)
+	rp := f.Paths
+	rp[start] = PathEnd{Len: 1, From: -1}
+	oh := openHeap{cr}
+	for len(oh) > 0 {
+		bestPath := heap.Pop(&oh).(*rNode)
+		bestNode := bestPath.nx
+		if goals.Bit(int(bestNode)) == 1 {
+			return f, labels, d[bestNode], bestNode, true
+		}
+		nextLen := rp[bestNode].Len + 1
+		for _, nb := range g[bestNode] {
+			alt := &r[nb.To]
+			ap := &rp[alt.nx]
+			g := d[bestNode] + w(nb.Label)
+			if alt.state == reached {
+				if g > d[nb.To] || g == d[nb.To] && nextLen >= ap.Len {
+					continue
+				}
+			}
+			*ap = PathEnd{From: bestNode, Len: nextLen}
+			labels[nb.To] = nb.Label
+			d[nb.To] = g
+			alt.f = g + h(nb.To)
+			switch {
+			case alt.state != reached:
+				alt.state = reached
+				heap.Push(&oh, alt)
+			case alt.fx < 0:
+				heap.Push(&oh, alt)
+			default:
+				heap.Fix(&oh, alt.fx)
+			}
+		}
+	}
+	return f, labels, 0, -1, false
+}
+
 // AStarM is AStarA optimized for monotonic heuristic estimates.
 //
 // Note that this function requires a monotonic heuristic.  Results will
@@ -345,7 +530,9 @@ func (p *openHeap) Pop() interface{} {
 //
 // If the algorithm completes without encountering a negative cycle the method
 // returns shortest paths encoded in a FromList, labels and path distances
-// indexed by node, and return value end = -1.
+// indexed by node, and return value end = -1.  As with Dijkstra, where
+// multiple paths have the same shortest distance, a path with the fewest
+// nodes is returned.
 //
 // If it encounters a negative cycle reachable from start it returns end >= 0.
 // In this case the cycle can be obtained by calling f.BellmanFordCycle(end).
@@ -354,7 +541,8 @@ func (p *openHeap) Pop() interface{} {
 // cycle not reachable from start will not prevent the algorithm from finding
 // shortest paths from start.
 //
-// See also NegativeCycle to find a cycle anywhere in the graph, see
+// See also BellmanFordQueue, usually faster on sparse graphs, see
+// NegativeCycle to find a cycle anywhere in the graph, see
 // NegativeCycles for enumerating all negative cycles, and see
 // HasNegativeCycle for lighter-weight negative cycle detection,
 func (g LabeledDirected) BellmanFord(w WeightFunc, start NI) (f FromList, labels []LI, dist []float64, end NI) {
@@ -377,8 +565,10 @@ func (g LabeledDirected) BellmanFord(w WeightFunc, start NI) (f FromList, labels
 			for _, nb := range nbs {
 				d2 := d1 + w(nb.Label)
 				to := &rp[nb.To]
-				// TODO improve to break ties
-				if fp.Len > 0 && d2 < dist[nb.To] {
+				// ties are broken by path length, as with Dijkstra.  path
+				// lengths only decrease on ties so rounds still terminate.
+				if fp.Len > 0 && (d2 < dist[nb.To] ||
+					d2 == dist[nb.To] && fp.Len+1 < to.Len) {
 					*to = PathEnd{From: NI(from), Len: fp.Len + 1}
 					labels[nb.To] = nb.Label
 					dist[nb.To] = d2
@@ -423,6 +613,78 @@ func (f FromList) BellmanFordCycle(end NI) (c []NI) {
 	return
 }
 
+// BellmanFordQueue finds shortest paths from a start node in a weighted
+// directed graph using a queue-based variant of the Bellman-Ford-Moore
+// algorithm, sometimes called the shortest path faster algorithm (SPFA.)
+//
+// Arguments and return values are as for BellmanFord.  Rather than relaxing
+// all arcs in each round, BellmanFordQueue keeps a FIFO queue of nodes with
+// improved distances and relaxes only arcs from queued nodes.  On sparse
+// graphs where few distances change in each round it is typically much
+// faster than BellmanFord, although the worst case time is the same.
+//
+// Returned distances and path lengths are the same as those returned by
+// BellmanFord, including the tie breaking by path length.  Where multiple
+// paths have the same distance and length, the path returned may differ.
+//
+// A negative cycle reachable from start is detected when a node has been
+// relaxed len(g.LabeledAdjacencyList) times and its path in the FromList
+// leads into a cycle.  Return value end >= 0 then is the node relaxed,
+// and as with BellmanFord, f.BellmanFordCycle(end) returns the negative
+// cycle.
+func (g LabeledDirected) BellmanFordQueue(w WeightFunc, start NI) (f FromList, labels []LI, dist []float64, end NI) {
+	a := g.LabeledAdjacencyList
+	f = NewFromList(len(a))
+	labels = make([]LI, len(a))
+	dist = make([]float64, len(a))
+	inf := math.Inf(1)
+	for i := range dist {
+		dist[i] = inf
+	}
+	rp := f.Paths
+	rp[start] = PathEnd{Len: 1, From: -1}
+	dist[start] = 0
+	// cyclic returns true if the path to n in rp leads into a cycle.
+	cyclic := func(n NI) bool {
+		for _ = range a {
+			if n = rp[n].From; n < 0 {
+				return false
+			}
+		}
+		return true
+	}
+	count := make([]int, len(a)) // number of times each node is relaxed
+	inQ := bits.New(len(a))
+	q := []NI{start}
+	inQ.SetBit(int(start), 1)
+	for len(q) > 0 {
+		from := q[0]
+		q = q[1:]
+		inQ.SetBit(int(from), 0)
+		fp := &rp[from]
+		d1 := dist[from]
+		for _, nb := range a[from] {
+			d2 := d1 + w(nb.Label)
+			to := &rp[nb.To]
+			if !(d2 < dist[nb.To] ||
+				d2 == dist[nb.To] && fp.Len+1 < to.Len) {
+				continue
+			}
+			*to = PathEnd{From: from, Len: fp.Len + 1}
+			labels[nb.To] = nb.Label
+			dist[nb.To] = d2
+			if count[nb.To]++; count[nb.To] >= len(a) && cyclic(nb.To) {
+				return f, labels, dist, nb.To
+			}
+			if inQ.Bit(int(nb.To)) == 0 {
+				inQ.SetBit(int(nb.To), 1)
+				q = append(q, nb.To)
+			}
+		}
+	}
+	return f, labels, dist, -1
+}
+
 // HasNegativeCycle returns true if the graph contains any negative cycle.
 //
 // HasNegativeCycle uses a Bellman-Ford-like algorithm, but finds negative
@@ -468,6 +730,15 @@ func (g LabeledDirected) HasNegativeCycle(w WeightFunc) bool {
 // cycles anywhere in the graph.  If a negative cycle exists, one will be
 // returned.  The result is nil if no negative cycle exists.
 //
+// The search starts with all nodes at distance 0, equivalent to a search from
+// a virtual source with a zero weight arc to every node, so cycles are found
+// regardless of reachability from any particular node.  After n relaxation
+// rounds for a graph of order n, the cycle is found by following predecessor
+// links until a node repeats.  The cycle is returned as a list of arcs, each
+// Half giving the node an arc leads to and the label of the arc.  The arc
+// leads from the node of the preceding Half, or for the first Half, from the
+// node of the last Half.
+//
 // See also NegativeCycles for enumerating all negative cycles, see
 // HasNegativeCycle for lighter-weight cycle detection, and see
 // BellmanFord for single source shortest paths, also with negative cycle
@@ -658,6 +929,227 @@ func (g LabeledDirected) DAGOptimalPaths(start, end NI, ordering []NI, w WeightF
 // slice.   Returned labels are the labels of arcs followed to each node.
 // The number of nodes reached is returned as nReached.
 func (g LabeledAdjacencyList) Dijkstra(start, end NI, w WeightFunc) (f FromList, labels []LI, dist []float64, nReached int) {
+	f, labels, dist, nReached, stopped := g.dijkstra(start, w,
+		func(n NI, _ float64) bool { return n == end })
+	if stopped {
+		nReached = -1
+	}
+	return
+}
+
+// DijkstraTargets finds shortest paths from start to a set of target nodes.
+//
+// DijkstraTargets is like Dijkstra but rather than a single end node,
+// argument targets is a set of nodes.  The search stops once shortest
+// paths to all target nodes reachable from start are found, that is, once
+// all reachable targets are done and no longer tentative.  Paths and
+// distances in the returned FromList and dist slice are valid for target
+// nodes and for any other nodes done by the time the search stops.
+// Return value nDone is the number of these nodes.  Unreachable target
+// nodes have path length 0 in the FromList.
+//
+// Targets must be a bits.Bits with Num equal to the order of g.
+func (g LabeledAdjacencyList) DijkstraTargets(start NI, targets bits.Bits, w WeightFunc) (f FromList, labels []LI, dist []float64, nDone int) {
+	rem := targets.OnesCount()
+	f, labels, dist, nDone, _ = g.dijkstra(start, w, func(n NI, _ float64) bool {
+		if targets.Bit(int(n)) == 1 {
+			rem--
+		}
+		return rem == 0
+	})
+	return
+}
+
+// DijkstraTargetPaths finds shortest paths from start to a list of target
+// nodes.
+//
+// It is a convenience method calling DijkstraTargets and returning paths
+// to each target as returned by FromList.PathToLabeled along with path
+// distances.  Targets not reachable from start are not in the returned maps.
+func (g LabeledAdjacencyList) DijkstraTargetPaths(start NI, targets []NI, w WeightFunc) (paths map[NI]LabeledPath, dist map[NI]float64) {
+	tb := bits.New(len(g))
+	for _, n := range targets {
+		tb.SetBit(int(n), 1)
+	}
+	f, labels, d, _ := g.DijkstraTargets(start, tb, w)
+	paths = map[NI]LabeledPath{}
+	dist = map[NI]float64{}
+	for _, n := range targets {
+		if f.Paths[n].Len > 0 {
+			paths[n] = f.PathToLabeled(n, labels, nil)
+			dist[n] = d[n]
+		}
+	}
+	return
+}
+
+// DijkstraBounded finds shortest paths from start to all nodes within
+// distance maxDist.
+//
+// DijkstraBounded is like Dijkstra with no end node but the search stops
+// once the smallest tentative distance exceeds maxDist.  Paths and
+// distances in the returned FromList and dist slice are complete and correct
+// for all nodes with distance <= maxDist.  Nodes at greater distances are
+// left as unreached, with path length 0 in the FromList, even if they were
+// reached tentatively.  Return value nReached is the number of nodes within
+// distance maxDist.
+func (g LabeledAdjacencyList) DijkstraBounded(start NI, maxDist float64, w WeightFunc) (f FromList, labels []LI, dist []float64, nReached int) {
+	done := bits.New(len(g))
+	f, labels, dist, _, _ = g.dijkstra(start, w, func(n NI, d float64) bool {
+		if d > maxDist {
+			return true
+		}
+		done.SetBit(int(n), 1)
+		return false
+	})
+	// clear nodes beyond maxDist
+	done.IterateZeros(func(n int) bool {
+		f.Paths[n] = PathEnd{}
+		labels[n] = 0
+		dist[n] = 0
+		return true
+	})
+	return f, labels, dist, done.OnesCount()
+}
+
+// WithinDistance returns the set of nodes within distance maxDist of start.
+//
+// It is a convenience method calling DijkstraBounded.
+func (g LabeledAdjacencyList) WithinDistance(start NI, maxDist float64, w WeightFunc) bits.Bits {
+	f, _, _, _ := g.DijkstraBounded(start, maxDist, w)
+	b := bits.New(len(g))
+	for n, p := range f.Paths {
+		if p.Len > 0 {
+			b.SetBit(n, 1)
+		}
+	}
+	return b
+}
+
+// NodeFailureSensitivity finds shortest path distances from start to end
+// when single nodes fail.
+//
+// For each node v, alt[v] is the shortest path distance from start to end in
+// g with node v and its arcs removed, or +Inf if end is then unreachable.
+// alt[start] and alt[end] are +Inf.  Failure of a node not on the shortest
+// path from start to end leaves the distance unchanged.
+//
+// For each node v on the shortest path other than start and end, affected[v]
+// lists the nodes with shortest paths through v, that is, descendants of v
+// in the shortest path tree.  Other elements of affected are nil.
+//
+// Rather than rerunning Dijkstra's algorithm for each failure, distances
+// from a single shortest path tree are reused.  Distances of nodes outside
+// the subtree of v are unaffected by failure of v.  Only the nodes of the
+// subtree are searched again, with the search seeded by arcs into the
+// subtree from nodes outside it.  Arc weights must be non-negative as for
+// Dijkstra.
+func (g LabeledAdjacencyList) NodeFailureSensitivity(start, end NI, w WeightFunc) (alt []float64, affected [][]NI) {
+	f, _, dist, _ := g.Dijkstra(start, -1, w)
+	alt = make([]float64, len(g))
+	affected = make([][]NI, len(g))
+	inf := math.Inf(1)
+	d := inf
+	if f.Paths[end].Len > 0 {
+		d = dist[end]
+	}
+	for v := range alt {
+		alt[v] = d
+	}
+	alt[start] = inf
+	alt[end] = inf
+	path := f.PathTo(end, nil)
+	if len(path) < 3 {
+		return
+	}
+	// children in the shortest path tree, and arcs into each node.
+	children := make([][]NI, len(g))
+	for n, p := range f.Paths {
+		if p.Len > 1 {
+			children[p.From] = append(children[p.From], NI(n))
+		}
+	}
+	type inArc struct {
+		fr NI
+		w  float64
+	}
+	in := make([][]inArc, len(g))
+	for fr, to := range g {
+		if f.Paths[fr].Len == 0 {
+			continue
+		}
+		for _, to := range to {
+			in[to.To] = append(in[to.To], inArc{NI(fr), w(to.Label)})
+		}
+	}
+	inSub := bits.New(len(g))
+	r := make([]tentResult, len(g))
+	for _, v := range path[1 : len(path)-1] {
+		// collect the subtree of v
+		sub := append([]NI{}, children[v]...)
+		for i := 0; i < len(sub); i++ {
+			sub = append(sub, children[sub[i]]...)
+		}
+		affected[v] = sub
+		inSub.ClearAll()
+		inSub.SetBit(int(v), 1)
+		for _, n := range sub {
+			inSub.SetBit(int(n), 1)
+		}
+		// seed tentative distances from arcs into the subtree
+		var t tent
+		for _, n := range sub {
+			hr := &r[n]
+			*hr = tentResult{dist: inf, nx: n}
+			for _, a := range in[n] {
+				if inSub.Bit(int(a.fr)) == 0 {
+					if dn := dist[a.fr] + a.w; dn < hr.dist {
+						hr.dist = dn
+					}
+				}
+			}
+			if hr.dist < inf {
+				heap.Push(&t, hr)
+			}
+		}
+		// search within the subtree, excluding v
+		alt[v] = inf
+		for len(t) > 0 {
+			cr := heap.Pop(&t).(*tentResult)
+			cr.done = true
+			if cr.nx == end {
+				alt[v] = cr.dist
+				break
+			}
+			for _, nb := range g[cr.nx] {
+				if nb.To == v || inSub.Bit(int(nb.To)) == 0 {
+					continue
+				}
+				hr := &r[nb.To]
+				if hr.done {
+					continue
+				}
+				dn := cr.dist + w(nb.Label)
+				if dn >= hr.dist {
+					continue
+				}
+				pushed := hr.dist < inf
+				hr.dist = dn
+				if pushed {
+					heap.Fix(&t, hr.fx)
+				} else {
+					heap.Push(&t, hr)
+				}
+			}
+		}
+	}
+	return
+}
+
+// dijkstra implements Dijkstra and its variants.  Function stop is called
+// with each node and its distance as the node is done.  If it returns true
+// the search stops and dijkstra returns stopped = true.
+func (g LabeledAdjacencyList) dijkstra(start NI, w WeightFunc, stop func(NI, float64) bool) (f FromList, labels []LI, dist []float64, nDone int, stopped bool) {
 	r := make([]tentResult, len(g))
 	for i := range r {
 		r[i].nx = NI(i)
@@ -671,9 +1163,9 @@ func (g LabeledAdjacencyList) Dijkstra(start, end NI, w WeightFunc) (f FromList,
 	cr := &r[current]
 	cr.dist = 0    // distance at start is 0.
 	cr.done = true // mark start done.  it skips the heap.
-	nDone := 1     // accumulated for a return value
+	nDone = 1      // accumulated for a return value
 	var t tent
-	for current != end {
+	for !stop(current, cr.dist) {
 		nextLen := rp[current].Len + 1
 		for _, nb := range g[current] {
 			// d.arcVis++
@@ -709,7 +1201,7 @@ func (g LabeledAdjacencyList) Dijkstra(start, end NI, w WeightFunc) (f FromList,
 		//d.ndVis++
 		if len(t) == 0 {
 			// no more reachable nodes. AllPaths normal return
-			return f, labels, dist, nDone
+			return f, labels, dist, nDone, false
 		}
 		// new current is node with smallest tentative distance
 		cr = heap.Pop(&t).(*tentResult)
@@ -718,8 +1210,8 @@ func (g LabeledAdjacencyList) Dijkstra(start, end NI, w WeightFunc) (f FromList,
 		current = cr.nx
 		dist[current] = cr.dist // store final distance
 	}
-	// normal return for single shortest path search
-	return f, labels, dist, -1
+	// normal return for stopped search
+	return f, labels, dist, nDone, true
 }
 
 // DijkstraPath finds a single shortest path.
@@ -731,6 +1223,243 @@ func (g LabeledAdjacencyList) DijkstraPath(start, end NI, w WeightFunc) (Labeled
 	return f.PathToLabeled(end, labels, nil), dist[end]
 }
 
+// YenKSP finds up to k shortest loopless paths from start to end using
+// Yen's algorithm.
+//
+// Paths are returned in order of increasing distance.  Fewer than k paths
+// are returned if fewer exist.
+//
+// See YenKSPEmit for details.
+func (g LabeledAdjacencyList) YenKSP(start, end NI, k int, w WeightFunc) (paths []LabeledPath) {
+	if k <= 0 {
+		return nil
+	}
+	g.YenKSPEmit(start, end, w, func(p LabeledPath, dist float64) bool {
+		paths = append(paths, p)
+		return len(paths) < k
+	})
+	return
+}
+
+// YenKSPEmit emits loopless paths from start to end in order of increasing
+// distance using Yen's algorithm.
+//
+// Emit is called with each path and its distance.  Paths of equal distance
+// are emitted in order of increasing number of nodes, consistent with the
+// tie breaking of Dijkstra.  Emission continues until all loopless paths
+// have been emitted or until emit returns false.
+//
+// Spur paths are found with Dijkstra so arc weights must be non-negative.
+// Parallel arcs are treated as distinct when they have different labels.
+func (g LabeledAdjacencyList) YenKSPEmit(start, end NI, w WeightFunc, emit func(p LabeledPath, dist float64) bool) {
+	f, labels, dist, _ := g.Dijkstra(start, end, w)
+	if f.Paths[end].Len == 0 {
+		return
+	}
+	type cand struct {
+		p LabeledPath
+		d float64
+	}
+	var acc []LabeledPath // accepted paths
+	var b []cand          // candidate paths
+	p, d := f.PathToLabeled(end, labels, nil), dist[end]
+	h := make(LabeledAdjacencyList, len(g))
+	eq := func(a, b []Half) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i, x := range a {
+			if b[i] != x {
+				return false
+			}
+		}
+		return true
+	}
+	for emit(p, d) {
+		acc = append(acc, p)
+		rd := 0. // root path distance
+		for i := range p.Path {
+			root := p.Path[:i]
+			spur := start
+			if i > 0 {
+				spur = root[i-1].To
+				rd += w(root[i-1].Label)
+			}
+			// remove root path nodes other than the spur node by removing
+			// their arcs.  They may be reached but not passed through.
+			copy(h, g)
+			if i > 0 {
+				h[start] = nil
+				for _, nd := range root[:i-1] {
+					h[nd.To] = nil
+				}
+			}
+			// remove arcs from the spur node taken by accepted paths sharing
+			// the root path
+			var sa []Half
+		arcs:
+			for _, nb := range g[spur] {
+				for _, q := range acc {
+					if len(q.Path) > i && q.Path[i] == nb && eq(q.Path[:i], root) {
+						continue arcs
+					}
+				}
+				sa = append(sa, nb)
+			}
+			h[spur] = sa
+			sf, sl, sd, _ := h.Dijkstra(spur, end, w)
+			if sf.Paths[end].Len == 0 {
+				continue
+			}
+			sp := sf.PathToLabeled(end, sl, nil)
+			c := cand{LabeledPath{start, append(append([]Half{}, root...), sp.Path...)}, rd + sd[end]}
+			dup := false
+			for _, x := range b {
+				if eq(x.p.Path, c.p.Path) {
+					dup = true
+					break
+				}
+			}
+			if !dup {
+				b = append(b, c)
+			}
+		}
+		if len(b) == 0 {
+			return
+		}
+		bx := 0
+		for x, c := range b[1:] {
+			if c.d < b[bx].d || c.d == b[bx].d && len(c.p.Path) < len(b[bx].p.Path) {
+				bx = x + 1
+			}
+		}
+		p, d = b[bx].p, b[bx].d
+		b = append(b[:bx], b[bx+1:]...)
+	}
+}
+
+// Minimax finds minimax paths, paths minimizing the maximum arc weight.
+//
+// The bottleneck of a path is the maximum arc weight along the path.
+// Minimax finds paths from start with minimum bottleneck.  Arc weights may
+// be negative.
+//
+// Paths are returned as a tree, so each path found is also a minimax path
+// to each node along it.  Of such paths to a node, one with the minimum
+// number of nodes is returned.  A path with the same bottleneck and fewer
+// nodes may exist if it passes through a node with a smaller bottleneck by
+// a path that is not minimax to that node.
+//
+// Results are returned as for Dijkstra, with the path bottlenecks returned
+// in place of distances.  The bottleneck at start is -Inf.  Bottlenecks of
+// unreached nodes are +Inf.  If end is a valid node number the search stops
+// when end is reached.  Use end = -1 to find paths to all reachable nodes.
+//
+// See also Widest.
+func (g LabeledAdjacencyList) Minimax(start, end NI, w WeightFunc) (f FromList, labels []LI, bottleneck []float64, nReached int) {
+	r := make([]tentResult, len(g))
+	for i := range r {
+		r[i].nx = NI(i)
+	}
+	f = NewFromList(len(g))
+	labels = make([]LI, len(g))
+	bottleneck = make([]float64, len(g))
+	inf := math.Inf(1)
+	for i := range bottleneck {
+		bottleneck[i] = inf
+	}
+	current := start
+	rp := f.Paths
+	rp[current] = PathEnd{Len: 1, From: -1}
+	cr := &r[current]
+	cr.dist = math.Inf(-1)
+	cr.done = true
+	bottleneck[current] = cr.dist
+	nDone := 1
+	t := mmTent{rp: rp}
+	for current != end {
+		nextLen := rp[current].Len + 1
+		for _, nb := range g[current] {
+			hr := &r[nb.To]
+			if hr.done {
+				continue
+			}
+			b := math.Max(cr.dist, w(nb.Label))
+			vl := rp[nb.To].Len
+			visited := vl > 0
+			if visited {
+				if b > hr.dist || b == hr.dist && nextLen >= vl {
+					continue
+				}
+			}
+			hr.dist = b
+			rp[nb.To].Len = nextLen
+			rp[nb.To].From = current
+			labels[nb.To] = nb.Label
+			if visited {
+				heap.Fix(&t, hr.fx)
+			} else {
+				heap.Push(&t, hr)
+			}
+		}
+		if len(t.tent) == 0 {
+			return f, labels, bottleneck, nDone
+		}
+		cr = heap.Pop(&t).(*tentResult)
+		cr.done = true
+		nDone++
+		current = cr.nx
+		bottleneck[current] = cr.dist
+	}
+	return f, labels, bottleneck, -1
+}
+
+// MinimaxPath finds a single minimax path.
+//
+// Returned is the path as returned by FromList.PathToLabeled and the path
+// bottleneck, the maximum arc weight along the path.
+//
+// See Minimax.
+func (g LabeledAdjacencyList) MinimaxPath(start, end NI, w WeightFunc) (LabeledPath, float64) {
+	f, labels, b, _ := g.Minimax(start, end, w)
+	return f.PathToLabeled(end, labels, nil), b[end]
+}
+
+// Widest finds widest paths, paths maximizing the minimum arc weight.
+//
+// The width, or bottleneck capacity, of a path is the minimum arc weight
+// along the path.  Widest finds paths from start with maximum width.  As
+// for Minimax, each path found is also a widest path to each node along it,
+// and of such paths to a node, one with the minimum number of nodes is
+// returned.
+//
+// Results are returned as for Dijkstra, with path widths returned in place
+// of distances.  The width at start is +Inf.  Widths of unreached nodes are
+// -Inf.  If end is a valid node number the search stops when end is reached.
+// Use end = -1 to find paths to all reachable nodes.
+//
+// See also Minimax.
+func (g LabeledAdjacencyList) Widest(start, end NI, w WeightFunc) (f FromList, labels []LI, width []float64, nReached int) {
+	// maximizing the minimum weight is minimizing the maximum negated weight
+	f, labels, width, nReached = g.Minimax(start, end,
+		func(l LI) float64 { return -w(l) })
+	for i, b := range width {
+		width[i] = -b
+	}
+	return
+}
+
+// WidestPath finds a single widest path.
+//
+// Returned is the path as returned by FromList.PathToLabeled and the path
+// width, the minimum arc weight along the path.
+//
+// See Widest.
+func (g LabeledAdjacencyList) WidestPath(start, end NI, w WeightFunc) (LabeledPath, float64) {
+	f, labels, width, _ := g.Widest(start, end, w)
+	return f.PathToLabeled(end, labels, nil), width[end]
+}
+
 // tent implements container/heap
 func (t tent) Len() int           { return len(t) }
 func (t tent) Less(i, j int) bool { return t[i].dist < t[j].dist }
@@ -759,3 +1488,17 @@ type tentResult struct {
 }
 
 type tent []*tentResult
+
+// mmTent implements container/heap for Minimax.  It orders tentative nodes
+// by bottleneck, then by path length, so that of nodes with equal bottleneck
+// the one with the shortest path is done first.
+type mmTent struct {
+	tent
+	rp []PathEnd
+}
+
+func (t mmTent) Less(i, j int) bool {
+	a, b := t.tent[i], t.tent[j]
+	return a.dist < b.dist ||
+		a.dist == b.dist && t.rp[a.nx].Len < t.rp[b.nx].Len
+}
@@ -7,9 +7,13 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"reflect"
+	"sort"
 	"testing"
 
+	"github.com/soniakeys/bits"
 	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/graphtest"
 )
 
 func ExampleLabeledAdjacencyList_AStarAPath() {
@@ -43,6 +47,162 @@ func ExampleLabeledAdjacencyList_AStarAPath() {
 	// Path distance: 26
 }
 
+func ExampleLabeledAdjacencyList_AStarAMultiStart() {
+	// same graph as AStarAPath example, with starts 0 and 1.
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 7}, {To: 2, Label: 9}, {To: 5, Label: 14}},
+		1: {{To: 2, Label: 10}, {To: 3, Label: 15}},
+		2: {{To: 3, Label: 11}, {To: 5, Label: 2}},
+		3: {{To: 4, Label: 6}},
+		4: {{To: 5, Label: 9}},
+		5: {},
+	}
+	w := func(label graph.LI) float64 { return float64(label) }
+	h4 := []float64{19, 20, 10, 6, 0, 9}
+	h := func(from graph.NI) float64 { return h4[from] }
+	f, labels, d, start, _ := g.AStarAMultiStart(w, []graph.NI{0, 1}, 4, h)
+	fmt.Println("Best start:", start)
+	fmt.Println("Shortest path:", f.PathToLabeled(4, labels, nil))
+	fmt.Println("Path distance:", d)
+	// Output:
+	// Best start: 1
+	// Shortest path: {1 [{3 15} {4 6}]}
+	// Path distance: 21
+}
+
+func TestAStarAMultiStart(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		tc := r(100, 300, seed)
+		w := func(label graph.LI) float64 { return tc.w[label] }
+		rr := rand.New(rand.NewSource(seed))
+		starts := make([]graph.NI, 1+rr.Intn(5))
+		for i := range starts {
+			starts[i] = graph.NI(rr.Intn(100))
+		}
+		starts = append(starts, starts[0]) // duplicate
+		// per-start minimum
+		best := math.Inf(1)
+		for _, s := range starts {
+			if _, _, d, ok := tc.l.AStarA(w, s, tc.end, tc.h); ok && d < best {
+				best = d
+			}
+		}
+		f, labels, d, s, ok := tc.l.AStarAMultiStart(w, starts, tc.end, tc.h)
+		if ok != !math.IsInf(best, 1) {
+			t.Fatal("seed", seed, "ok", ok, "best", best)
+		}
+		if !ok {
+			continue
+		}
+		if math.Abs(d-best) > 1e-12 {
+			t.Fatal("seed", seed, "dist", d, "want", best)
+		}
+		p := f.PathToLabeled(tc.end, labels, nil)
+		if p.Start != s {
+			t.Fatal("seed", seed, "path start", p.Start, "bestStart", s)
+		}
+		if _, _, ds, _ := tc.l.AStarA(w, s, tc.end, tc.h); ds != d {
+			t.Fatal("seed", seed, "bestStart distance", ds, "want", d)
+		}
+	}
+	// start equal to end
+	tc := r(20, 40, 1)
+	w := func(label graph.LI) float64 { return tc.w[label] }
+	f, labels, d, s, ok := tc.l.AStarAMultiStart(w,
+		[]graph.NI{tc.start, tc.end}, tc.end, tc.h)
+	if !ok || d != 0 || s != tc.end ||
+		len(f.PathToLabeled(tc.end, labels, nil).Path) != 0 {
+		t.Fatal("start = end:", ok, d, s)
+	}
+	// no starts
+	if _, _, _, s, ok := tc.l.AStarAMultiStart(w, nil, tc.end, tc.h); ok || s != -1 {
+		t.Fatal("no starts:", s, ok)
+	}
+}
+
+func ExampleLabeledAdjacencyList_AStarAMultiGoal() {
+	// an open 5x5 grid with goals at two corners
+	g := graph.NewGrid(5, 5, nil, graph.DiagonalNoCornerCut)
+	goalRC := [][2]int{{0, 4}, {4, 4}}
+	goals := bits.New(g.Rows * g.Cols)
+	var hs []graph.Heuristic
+	for _, rc := range goalRC {
+		n := g.At(rc[0], rc[1])
+		goals.SetBit(int(n), 1)
+		hs = append(hs, g.Heuristic(n))
+	}
+	// heuristic is estimate to the nearest goal
+	h := func(n graph.NI) float64 {
+		min := math.Inf(1)
+		for _, h := range hs {
+			min = math.Min(min, h(n))
+		}
+		return min
+	}
+	adm, _ := graph.Heuristic(h).AdmissibleGoals(
+		g.G.LabeledAdjacencyList, g.Weight, goals)
+	fmt.Println("admissible:", adm)
+	f, _, d, found, ok := g.G.AStarAMultiGoal(g.Weight, g.At(3, 1), goals, h)
+	fmt.Println("found:", ok)
+	var p [][2]int
+	for _, n := range f.PathTo(found, nil) {
+		r, c := g.RC(n)
+		p = append(p, [2]int{r, c})
+	}
+	fmt.Println("path:", p)
+	fmt.Printf("distance: %.3f\n", d)
+	// Output:
+	// admissible: true
+	// found: true
+	// path: [[3 1] [3 2] [3 3] [4 4]]
+	// distance: 3.414
+}
+
+// AStarAMultiGoal must find the distance to the nearest goal, as found
+// by Dijkstra.
+func TestAStarAMultiGoal(t *testing.T) {
+	tc := r(300, 900, 65)
+	w := func(l graph.LI) float64 { return tc.w[l] }
+	a := tc.l.LabeledAdjacencyList
+	zero := func(graph.NI) float64 { return 0 }
+	rnd := rand.New(rand.NewSource(66))
+	for trial := 0; trial < 50; trial++ {
+		goals := bits.New(len(a))
+		for i := rnd.Intn(4); i >= 0; i-- {
+			goals.SetBit(rnd.Intn(len(a)), 1)
+		}
+		start := graph.NI(rnd.Intn(len(a)))
+		f, _, dist, _ := a.Dijkstra(start, -1, w)
+		want := math.Inf(1)
+		goals.IterateOnes(func(n int) bool {
+			if f.Paths[n].Len > 0 && dist[n] < want {
+				want = dist[n]
+			}
+			return true
+		})
+		_, _, d, found, ok := a.AStarAMultiGoal(w, start, goals, zero)
+		if ok != !math.IsInf(want, 1) {
+			t.Fatal("ok", ok, "want dist", want)
+		}
+		if ok && (d != want || goals.Bit(int(found)) == 0) {
+			t.Fatal("found", found, "dist", d, "want", want)
+		}
+		if goals.Bit(int(start)) == 1 && (found != start || d != 0) {
+			t.Fatal("start is goal, found", found, d)
+		}
+	}
+	// the zero heuristic is admissible, an overestimate is not.
+	goals := bits.New(len(a))
+	goals.SetBit(int(tc.end), 1)
+	if ok, msg := graph.Heuristic(zero).AdmissibleGoals(a, w, goals); !ok {
+		t.Fatal(msg)
+	}
+	big := func(graph.NI) float64 { return 1e9 }
+	if ok, _ := graph.Heuristic(big).AdmissibleGoals(a, w, goals); ok {
+		t.Fatal("overestimate reported admissible")
+	}
+}
+
 func ExampleLabeledAdjacencyList_AStarMPath() {
 	// arcs are directed right:
 	//       -----------------------
@@ -130,6 +290,254 @@ func ExampleHeuristic_Monotonic() {
 	// true
 }
 
+func ExampleLabeledAdjacencyList_YenKSP() {
+	// arcs (weights):
+	//   0->1 (3)  0->2 (2)
+	//   1->3 (4)
+	//   2->1 (1)  2->3 (2)  2->4 (3)
+	//   3->4 (2)  3->5 (1)
+	//   4->5 (2)
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 3}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 4}},
+		2: {{To: 1, Label: 1}, {To: 3, Label: 2}, {To: 4, Label: 3}},
+		3: {{To: 4, Label: 2}, {To: 5, Label: 1}},
+		4: {{To: 5, Label: 2}},
+		5: {},
+	}
+	w := func(label graph.LI) float64 { return float64(label) }
+	for _, p := range g.YenKSP(0, 5, 4, w) {
+		fmt.Println(p.Distance(w), p)
+	}
+	// Output:
+	// 5 {0 [{2 2} {3 2} {5 1}]}
+	// 7 {0 [{2 2} {4 3} {5 2}]}
+	// 8 {0 [{1 3} {3 4} {5 1}]}
+	// 8 {0 [{2 2} {3 2} {4 2} {5 2}]}
+}
+
+func ExampleLabeledAdjacencyList_YenKSPEmit() {
+	// 0--(1)-->1--(1)-->2
+	//  \               ^
+	//   ------(5)------/
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}, {To: 2, Label: 5}},
+		1: {{To: 2, Label: 1}},
+		2: {},
+	}
+	w := func(label graph.LI) float64 { return float64(label) }
+	g.YenKSPEmit(0, 2, w, func(p graph.LabeledPath, d float64) bool {
+		fmt.Println(d, p)
+		return true
+	})
+	// Output:
+	// 2 {0 [{1 1} {2 1}]}
+	// 5 {0 [{2 5}]}
+}
+
+func TestYenKSP(t *testing.T) {
+	tc := r(100, 200, 62)
+	w := func(label graph.LI) float64 { return tc.w[label] }
+	a := tc.l.LabeledAdjacencyList
+	paths := a.YenKSP(tc.start, tc.end, 10, w)
+	if len(paths) == 0 {
+		t.Fatal("no paths")
+	}
+	_, d0 := a.DijkstraPath(tc.start, tc.end, w)
+	if d := paths[0].Distance(w); d != d0 {
+		t.Fatal("first path distance", d, "Dijkstra distance", d0)
+	}
+	last := d0
+	for i, p := range paths {
+		d := p.Distance(w)
+		if d < last {
+			t.Fatal("path", i, "distance decreased")
+		}
+		last = d
+		if err := graphtest.CheckPath(a, p, w, d); err != nil {
+			t.Fatal("path", i, err)
+		}
+		seen := map[graph.NI]bool{p.Start: true}
+		fr := p.Start
+		for _, h := range p.Path {
+			if seen[h.To] {
+				t.Fatal("path", i, "has loop")
+			}
+			seen[h.To] = true
+			fr = h.To
+		}
+		if fr != tc.end {
+			t.Fatal("path", i, "wrong end")
+		}
+		for _, q := range paths[:i] {
+			if fmt.Sprint(q) == fmt.Sprint(p) {
+				t.Fatal("duplicate path", i)
+			}
+		}
+	}
+}
+
+func ExampleLabeledAdjacencyList_WidestPath() {
+	// arcs (capacities):
+	//   0->1 (5)  0->2 (2)
+	//   1->3 (3)  1->2 (4)
+	//   2->3 (4)
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 5}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 3}, {To: 2, Label: 4}},
+		2: {{To: 3, Label: 4}},
+		3: {},
+	}
+	w := func(label graph.LI) float64 { return float64(label) }
+	fmt.Println(g.WidestPath(0, 3, w))
+	// Output:
+	// {0 [{1 5} {2 4} {3 4}]} 4
+}
+
+func ExampleLabeledAdjacencyList_MinimaxPath() {
+	// arcs (weights):
+	//   0->1 (5)  0->2 (2)
+	//   1->3 (3)  1->2 (4)
+	//   2->3 (4)
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 5}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 3}, {To: 2, Label: 4}},
+		2: {{To: 3, Label: 4}},
+		3: {},
+	}
+	w := func(label graph.LI) float64 { return float64(label) }
+	fmt.Println(g.MinimaxPath(0, 3, w))
+	// Output:
+	// {0 [{2 2} {3 4}]} 4
+}
+
+func ExampleLabeledAdjacencyList_Widest() {
+	// arcs (capacities):
+	//   0->1 (5)  0->2 (2)
+	//   1->3 (3)  1->2 (4)
+	//   2->3 (4)
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 5}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 3}, {To: 2, Label: 4}},
+		2: {{To: 3, Label: 4}},
+		3: {},
+	}
+	w := func(label graph.LI) float64 { return float64(label) }
+	f, _, width, _ := g.Widest(0, -1, w)
+	fmt.Println("node  width  from")
+	for n, e := range f.Paths {
+		fmt.Printf("%d  %6.0f  %4d\n", n, width[n], e.From)
+	}
+	// Output:
+	// node  width  from
+	// 0    +Inf    -1
+	// 1       5     0
+	// 2       4     1
+	// 3       4     2
+}
+
+// TestWidestMST checks Widest against the widest path property of
+// maximum spanning trees:  The tree path between two nodes is a widest path.
+func TestWidestMST(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 20; i++ {
+		u := graph.GnmUndirected(40, 80, r)
+		var g graph.LabeledUndirected
+		wt := []float64{}
+		u.SimpleEdges(func(e graph.Edge) {
+			g.AddEdge(e, graph.LI(len(wt)))
+			wt = append(wt, float64(r.Intn(20)))
+		})
+		w := func(l graph.LI) float64 { return wt[l] }
+		mst, _ := g.Kruskal(func(l graph.LI) float64 { return -wt[l] })
+		start := graph.NI(r.Intn(g.Order()))
+		_, _, width, _ := g.Widest(start, -1, w)
+		// widths of tree paths from start
+		tw := make([]float64, g.Order())
+		for i := range tw {
+			tw[i] = math.Inf(-1)
+		}
+		tw[start] = math.Inf(1)
+		q := []graph.NI{start}
+		for len(q) > 0 {
+			fr := q[0]
+			q = q[1:]
+			for _, h := range mst.LabeledAdjacencyList[fr] {
+				if math.IsInf(tw[h.To], -1) {
+					tw[h.To] = math.Min(tw[fr], w(h.Label))
+					q = append(q, h.To)
+				}
+			}
+		}
+		for n := range tw {
+			if tw[n] != width[n] {
+				t.Fatal("node", n, "tree path width", tw[n], "Widest", width[n])
+			}
+		}
+	}
+}
+
+// TestMinimaxLen checks bottlenecks and path lengths found by Minimax and
+// Widest against brute force.  Bottlenecks are found by relaxing arcs until
+// no bottleneck changes.  An arc fr->to is then tight if the bottleneck of
+// fr and the arc weight give the bottleneck of to.  Path lengths must be
+// breadth first distances using only tight arcs.
+func TestMinimaxLen(t *testing.T) {
+	r := rand.New(rand.NewSource(13))
+	for i := 0; i < 200; i++ {
+		d := graph.GnmDirected(15, 40, r)
+		g := make(graph.LabeledAdjacencyList, d.Order())
+		wt := []float64{}
+		for fr, to := range d.AdjacencyList {
+			for _, to := range to {
+				g[fr] = append(g[fr], graph.Half{to, graph.LI(len(wt))})
+				wt = append(wt, float64(r.Intn(3)))
+			}
+		}
+		w := func(l graph.LI) float64 { return wt[l] }
+		neg := func(l graph.LI) float64 { return -wt[l] }
+		start := graph.NI(r.Intn(len(g)))
+		want := make([]float64, len(g))
+		for n := range want {
+			want[n] = math.Inf(1)
+		}
+		want[start] = math.Inf(-1)
+		for changed := true; changed; {
+			changed = false
+			for fr, to := range g {
+				for _, h := range to {
+					if b := math.Max(want[fr], w(h.Label)); b < want[h.To] {
+						want[h.To] = b
+						changed = true
+					}
+				}
+			}
+		}
+		tight := make(graph.AdjacencyList, len(g))
+		for fr, to := range g {
+			for _, h := range to {
+				if !math.IsInf(want[fr], 1) &&
+					math.Max(want[fr], w(h.Label)) == want[h.To] {
+					tight[fr] = append(tight[fr], h.To)
+				}
+			}
+		}
+		dist := tight.Distances(start)
+		f, _, b, _ := g.Minimax(start, -1, w)
+		fw, _, width, _ := g.Widest(start, -1, neg)
+		for n := range g {
+			if b[n] != want[n] || width[n] != -want[n] {
+				t.Fatal("node", n, "bottleneck", b[n], "width", width[n],
+					"want", want[n])
+			}
+			if f.Paths[n].Len != dist[n]+1 || fw.Paths[n].Len != dist[n]+1 {
+				t.Fatal("node", n, "Minimax Len", f.Paths[n].Len,
+					"Widest Len", fw.Paths[n].Len, "want", dist[n]+1)
+			}
+		}
+	}
+}
+
 func ExampleLabeledDirected_BellmanFord() {
 	//              /--------3        4<-------9
 	//              |        ^        |   (6)  ^
@@ -446,11 +854,325 @@ func ExampleLabeledAdjacencyList_Dijkstra_allPaths() {
 	// 5:     [2 5]       2     2    2
 }
 
+func ExampleLabeledAdjacencyList_DijkstraTargetPaths() {
+	//   0--(3)--1--(1)--2
+	//   |               |
+	//  (1)             (1)
+	//   |               |
+	//   3------(5)------4
+	g := graph.LabeledUndirected{}
+	g.AddEdge(graph.Edge{0, 1}, 3)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{0, 3}, 1)
+	g.AddEdge(graph.Edge{2, 4}, 1)
+	g.AddEdge(graph.Edge{3, 4}, 5)
+	w := func(l graph.LI) float64 { return float64(l) }
+	paths, dist := g.DijkstraTargetPaths(0, []graph.NI{2, 3}, w)
+	for _, n := range []graph.NI{2, 3} {
+		fmt.Println(n, paths[n], dist[n])
+	}
+	// Output:
+	// 2 {0 [{1 3} {2 1}]} 4
+	// 3 {0 [{3 1}]} 1
+}
+
+func TestDijkstraTargets(t *testing.T) {
+	tc := r(1000, 3000, 63)
+	w := func(l graph.LI) float64 { return tc.w[l] }
+	a := tc.l.LabeledAdjacencyList
+	all, _, allDist, nAll := a.Dijkstra(tc.start, -1, w)
+	rnd := rand.New(rand.NewSource(64))
+	for trial := 0; trial < 20; trial++ {
+		targets := bits.New(len(a))
+		for i := rnd.Intn(5); i >= 0; i-- {
+			targets.SetBit(rnd.Intn(len(a)), 1)
+		}
+		f, _, dist, nDone := a.DijkstraTargets(tc.start, targets, w)
+		if nDone > nAll {
+			t.Fatal("nDone", nDone, "all", nAll)
+		}
+		targets.IterateOnes(func(n int) bool {
+			if f.Paths[n].Len != all.Paths[n].Len ||
+				f.Paths[n].Len > 0 && dist[n] != allDist[n] {
+				t.Fatal("target", n, f.Paths[n], dist[n],
+					"want", all.Paths[n], allDist[n])
+			}
+			return true
+		})
+	}
+	// a single near target stops the search early
+	targets := bits.New(len(a))
+	targets.SetBit(int(tc.start), 1)
+	if _, _, _, nDone := a.DijkstraTargets(tc.start, targets, w); nDone != 1 {
+		t.Fatal("nDone", nDone)
+	}
+}
+
+func ExampleLabeledAdjacencyList_WithinDistance() {
+	//   0--(3)--1--(1)--2
+	//   |               |
+	//  (1)             (1)
+	//   |               |
+	//   3------(5)------4
+	g := graph.LabeledUndirected{}
+	g.AddEdge(graph.Edge{0, 1}, 3)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{0, 3}, 1)
+	g.AddEdge(graph.Edge{2, 4}, 1)
+	g.AddEdge(graph.Edge{3, 4}, 5)
+	w := func(l graph.LI) float64 { return float64(l) }
+	fmt.Println(g.WithinDistance(0, 4, w))
+	// Output:
+	// 01111
+}
+
+func TestDijkstraBounded(t *testing.T) {
+	tc := r(1000, 3000, 67)
+	w := func(l graph.LI) float64 { return tc.w[l] }
+	a := tc.l.LabeledAdjacencyList
+	all, _, allDist, _ := a.Dijkstra(tc.start, -1, w)
+	// bounds include the exact distance to a node and a bound just below it
+	d := allDist[tc.end]
+	for _, maxDist := range []float64{0, .05, .1, .5, 10, d, math.Nextafter(d, 0)} {
+		f, _, dist, nReached := a.DijkstraBounded(tc.start, maxDist, w)
+		n := 0
+		for i, p := range all.Paths {
+			if p.Len > 0 && allDist[i] <= maxDist {
+				n++
+				if f.Paths[i] != p || dist[i] != allDist[i] {
+					t.Fatal("maxDist", maxDist, "node", i, f.Paths[i], dist[i],
+						"want", p, allDist[i])
+				}
+				continue
+			}
+			// nodes beyond the bound, including those just beyond it,
+			// are left unreached.
+			if f.Paths[i] != (graph.PathEnd{}) || dist[i] != 0 {
+				t.Fatal("maxDist", maxDist, "node", i, "distance",
+					allDist[i], "populated", f.Paths[i], dist[i])
+			}
+		}
+		if nReached != n {
+			t.Fatal("maxDist", maxDist, "nReached", nReached, "want", n)
+		}
+		if got := a.WithinDistance(tc.start, maxDist, w).OnesCount(); got != n {
+			t.Fatal("maxDist", maxDist, "WithinDistance", got, "want", n)
+		}
+	}
+}
+
+func ExampleLabeledAdjacencyList_NodeFailureSensitivity() {
+	//   0--(3)--1--(1)--2
+	//   |               |
+	//  (1)             (1)
+	//   |               |
+	//   3------(5)------4
+	g := graph.LabeledUndirected{}
+	g.AddEdge(graph.Edge{0, 1}, 3)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{0, 3}, 1)
+	g.AddEdge(graph.Edge{2, 4}, 1)
+	g.AddEdge(graph.Edge{3, 4}, 5)
+	w := func(l graph.LI) float64 { return float64(l) }
+	alt, affected := g.NodeFailureSensitivity(0, 4, w)
+	for v, d := range alt {
+		fmt.Println(v, d, affected[v])
+	}
+	// Output:
+	// 0 +Inf []
+	// 1 6 [2 4]
+	// 2 6 [4]
+	// 3 5 []
+	// 4 +Inf []
+}
+
+func TestNodeFailureSensitivity(t *testing.T) {
+	rs := rand.New(rand.NewSource(36))
+	check := func(i int, g graph.LabeledAdjacencyList, start, end graph.NI, w graph.WeightFunc) {
+		alt, affected := g.NodeFailureSensitivity(start, end, w)
+		f, _, _, _ := g.Dijkstra(start, -1, w)
+		onPath := map[graph.NI]bool{}
+		p := f.PathTo(end, nil)
+		for x := 1; x < len(p)-1; x++ {
+			onPath[p[x]] = true
+		}
+		for v := range g {
+			fv := graph.NI(v)
+			want := math.Inf(1)
+			if fv != start && fv != end {
+				// remove node v and search again
+				h := make(graph.LabeledAdjacencyList, len(g))
+				for fr, to := range g {
+					if fr == v {
+						continue
+					}
+					for _, to := range to {
+						if to.To != fv {
+							h[fr] = append(h[fr], to)
+						}
+					}
+				}
+				hf, _, hd, _ := h.Dijkstra(start, end, w)
+				if hf.Paths[end].Len > 0 {
+					want = hd[end]
+				}
+			}
+			if alt[v] != want && math.Abs(alt[v]-want) > 1e-9 {
+				t.Fatal(i, "node", v, "alt", alt[v], "want", want)
+			}
+			// affected nodes are those with tree paths through v
+			var wa []graph.NI
+			if onPath[fv] {
+				for n, pe := range f.Paths {
+					if n != v && pe.Len > 0 {
+						for _, x := range f.PathTo(graph.NI(n), nil) {
+							if x == fv {
+								wa = append(wa, graph.NI(n))
+								break
+							}
+						}
+					}
+				}
+			}
+			got := append([]graph.NI{}, affected[v]...)
+			sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+			if len(got) != len(wa) || len(wa) > 0 && !reflect.DeepEqual(got, wa) {
+				t.Fatal(i, "node", v, "affected", got, "want", wa)
+			}
+		}
+	}
+	for i := 0; i < 10; i++ {
+		tc := r(100, 200+rs.Intn(300), int64(i))
+		w := func(l graph.LI) float64 { return tc.w[l] }
+		check(i, tc.l.LabeledAdjacencyList, tc.start, tc.end, w)
+	}
+	// small sparse graphs, often disconnected by a failure
+	w := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 100; i++ {
+		n := 3 + rs.Intn(12)
+		var g graph.LabeledUndirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		for m := n + rs.Intn(n); m > 0; m-- {
+			g.AddEdge(graph.Edge{graph.NI(rs.Intn(n)), graph.NI(rs.Intn(n))},
+				graph.LI(rs.Intn(4)))
+		}
+		check(100+i, g.LabeledAdjacencyList, 0, graph.NI(n-1), w)
+	}
+}
+
 func TestSSSP(t *testing.T) {
 	r100 := r(100, 200, 62)
 	testSSSP(r100, t)
 }
 
+// BellmanFord breaks distance ties by path length as Dijkstra does.  Node 3
+// is reached first by the longer of two routes of distance 3 and node 4
+// through it, so node 4 is only corrected in a later round.
+func TestBellmanFordTieBreak(t *testing.T) {
+	//   0 -1-> 1 -1-> 2 -1-> 3 -1-> 4
+	//   0 -2-> 5 -1-> 3
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}, {To: 5, Label: 2}},
+		1: {{To: 2, Label: 1}},
+		2: {{To: 3, Label: 1}},
+		3: {{To: 4, Label: 1}},
+		5: {{To: 3, Label: 1}},
+	}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	bf, _, bd, end := g.BellmanFord(w, 0)
+	if end >= 0 {
+		t.Fatal("unexpected negative cycle")
+	}
+	df, _, dd, _ := g.Dijkstra(0, -1, w)
+	for n := range bf.Paths {
+		if bd[n] != dd[n] {
+			t.Fatalf("node %d: BellmanFord dist %g, Dijkstra %g", n, bd[n], dd[n])
+		}
+		if bf.Paths[n].Len != df.Paths[n].Len {
+			t.Fatalf("node %d: BellmanFord len %d, Dijkstra %d",
+				n, bf.Paths[n].Len, df.Paths[n].Len)
+		}
+	}
+	if got := bf.PathTo(4, nil); fmt.Sprint(got) != "[0 5 3 4]" {
+		t.Fatal("path to 4:", got)
+	}
+}
+
+// BellmanFordQueue must agree with BellmanFord on distances, path lengths
+// and negative cycle detection.  Graphs have negative arc weights, and
+// in some trials, negative cycles.
+func TestBellmanFordQueue(t *testing.T) {
+	r := rand.New(rand.NewSource(29))
+	for trial := 0; trial < 1000; trial++ {
+		n := 1 + r.Intn(12)
+		// arc weights are reduced by node potentials, giving negative
+		// weights but no negative cycles unless some weights are lowered.
+		pot := make([]int, n)
+		for i := range pot {
+			pot[i] = r.Intn(20)
+		}
+		lower := trial%2 == 0
+		a := make(graph.LabeledAdjacencyList, n)
+		var wt []float64
+		for i := r.Intn(3 * n); i > 0; i-- {
+			fr, to := r.Intn(n), r.Intn(n)
+			x := r.Intn(4) + pot[fr] - pot[to]
+			if lower && r.Intn(4) == 0 {
+				x -= 3
+			}
+			a[fr] = append(a[fr], graph.Half{graph.NI(to), graph.LI(len(wt))})
+			wt = append(wt, float64(x))
+		}
+		g := graph.LabeledDirected{a}
+		w := func(l graph.LI) float64 { return wt[l] }
+		start := graph.NI(r.Intn(n))
+		bf, _, bd, bEnd := g.BellmanFord(w, start)
+		qf, ql, qd, qEnd := g.BellmanFordQueue(w, start)
+		if (bEnd < 0) != (qEnd < 0) {
+			t.Fatal(a, wt, "end", bEnd, qEnd)
+		}
+		if qEnd >= 0 {
+			c := qf.BellmanFordCycle(qEnd)
+			d := 0.
+			for i, to := range c {
+				fr := c[(i+len(c)-1)%len(c)]
+				if qf.Paths[to].From != fr {
+					t.Fatal(a, wt, "cycle", c)
+				}
+				d += wt[ql[to]]
+			}
+			if len(c) == 0 || d >= 0 {
+				t.Fatal(a, wt, "cycle", c, "weight", d)
+			}
+			continue
+		}
+		for i := range bd {
+			if bd[i] != qd[i] || bf.Paths[i].Len != qf.Paths[i].Len {
+				t.Fatal(a, wt, "node", i, bd[i], qd[i],
+					bf.Paths[i].Len, qf.Paths[i].Len)
+			}
+		}
+	}
+}
+
+func BenchmarkBellmanFord(b *testing.B) {
+	tc := r(10000, 30000, 31)
+	w := func(l graph.LI) float64 { return tc.w[l] }
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tc.l.BellmanFord(w, tc.start)
+	}
+}
+
+func BenchmarkBellmanFordQueue(b *testing.B) {
+	tc := r(10000, 30000, 31)
+	w := func(l graph.LI) float64 { return tc.w[l] }
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tc.l.BellmanFordQueue(w, tc.start)
+	}
+}
+
 func testSSSP(tc testCase, t *testing.T) {
 	w := func(label graph.LI) float64 { return tc.w[label] }
 	f, labels, dist, _ := tc.l.LabeledAdjacencyList.Dijkstra(tc.start, tc.end, w)
@@ -553,3 +1275,117 @@ func r(nNodes, nArcs int, seed int64) testCase {
 	tc.t, tc.m = tc.g.Transpose()
 	return tc
 }
+
+// NegativeCycle must find a negative cycle exactly when HasNegativeCycle
+// reports one, including cycles not reachable from node 0, and the returned
+// witness must be a cycle of the graph with negative weight.
+func TestNegativeCycleWitness(t *testing.T) {
+	r := rand.New(rand.NewSource(27))
+	w := func(l graph.LI) float64 { return float64(l) }
+	for trial := 0; trial < 2000; trial++ {
+		n := 2 + r.Intn(8)
+		a := make(graph.LabeledAdjacencyList, n)
+		for i := r.Intn(3 * n); i > 0; i-- {
+			fr := r.Intn(n)
+			a[fr] = append(a[fr], graph.Half{
+				To:    graph.NI(r.Intn(n)),
+				Label: graph.LI(r.Intn(10) - 3),
+			})
+		}
+		g := graph.LabeledDirected{a}
+		c := g.NegativeCycle(w)
+		if has := g.HasNegativeCycle(w); has != (c != nil) {
+			t.Fatal(a, "HasNegativeCycle", has, "NegativeCycle", c)
+		}
+		if c == nil {
+			continue
+		}
+		sum := 0.
+		seen := map[graph.NI]bool{}
+		for i, h := range c {
+			if seen[h.To] {
+				t.Fatal(a, "cycle", c, "repeats node", h.To)
+			}
+			seen[h.To] = true
+			// h is the arc to h.To from the node of the previous Half
+			fr := c[(i+len(c)-1)%len(c)].To
+			if ok, _ := a.HasArcLabel(fr, h.To, h.Label); !ok {
+				t.Fatal(a, "cycle", c, "no arc", fr, h.To, h.Label)
+			}
+			sum += w(h.Label)
+		}
+		if sum >= 0 {
+			t.Fatal(a, "cycle", c, "weight", sum)
+		}
+	}
+}
+
+// Dijkstra on an induced subgraph, lifted to the supergraph, must match
+// Dijkstra on the supergraph with arcs of excluded nodes removed.
+func TestSubgraphLiftDijkstra(t *testing.T) {
+	rs := rand.New(rand.NewSource(33))
+	for i := 0; i < 20; i++ {
+		tc := r(100, 400, int64(i))
+		g := tc.l
+		w := func(l graph.LI) float64 { return tc.w[l] }
+		in := bits.New(g.Order())
+		for n := 0; n < g.Order(); n++ {
+			if n == int(tc.start) || rs.Intn(4) > 0 {
+				in.SetBit(n, 1)
+			}
+		}
+		s := g.InduceBits(in)
+		f, _, d, _ := s.Dijkstra(s.SubNI[tc.start], -1, w)
+		lf := s.LiftFromList(f)
+		ld := s.LiftDist(d, math.Inf(1))
+		// restricted supergraph
+		rg := make(graph.LabeledAdjacencyList, g.Order())
+		for fr, to := range g.LabeledAdjacencyList {
+			if in.Bit(fr) == 0 {
+				continue
+			}
+			for _, h := range to {
+				if in.Bit(int(h.To)) == 1 {
+					rg[fr] = append(rg[fr], h)
+				}
+			}
+		}
+		wf, _, wd, _ := rg.Dijkstra(tc.start, -1, w)
+		if lf.MaxLen != wf.MaxLen {
+			t.Fatal(i, "MaxLen", lf.MaxLen, wf.MaxLen)
+		}
+		for n, we := range wf.Paths {
+			le := lf.Paths[n]
+			if le.Len != we.Len {
+				t.Fatal(i, "node", n, "Len", le.Len, we.Len)
+			}
+			if we.Len == 0 {
+				if le.From != -1 && in.Bit(n) == 0 {
+					t.Fatal(i, "node", n, "From", le.From)
+				}
+				continue
+			}
+			if math.Abs(ld[n]-wd[n]) > 1e-9 {
+				t.Fatal(i, "node", n, "dist", ld[n], wd[n])
+			}
+			// lifted path must be a path in the supergraph
+			p := lf.PathTo(graph.NI(n), nil)
+			if p[0] != tc.start {
+				t.Fatal(i, "path", p)
+			}
+			for x := 1; x < len(p); x++ {
+				if ok, _ := g.HasArc(p[x-1], p[x]); !ok {
+					t.Fatal(i, "path", p)
+				}
+			}
+		}
+		var out bits.Bits
+		out.Not(in)
+		if got := s.PushBits(out); got.OnesCount() != 0 {
+			t.Fatal(i, "PushBits", got)
+		}
+		if got := s.LiftBits(s.PushBits(in)); !got.Equal(in) {
+			t.Fatal(i, "LiftBits", got)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// summary.go -- overview statistics of graphs, for inspection.
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DirectedSummary holds overview statistics of a directed graph, as
+// returned by Directed.Summarize and LabeledDirected.Summarize.
+//
+// Fields StrongComponents, LargestStrong, and DAG are computed only when
+// requested.  Strong records whether they were computed.
+type DirectedSummary struct {
+	Order    int
+	ArcSize  int
+	Loops    int  // number of loop arcs
+	Parallel bool // true if some arcs have the same from and to nodes
+	Simple   bool // true if no loops and no parallel arcs
+
+	WeakComponents int // number of weakly connected components
+	LargestWeak    int // order of the largest weakly connected component
+
+	MinOutDegree, MaxOutDegree int
+	MinInDegree, MaxInDegree   int
+	MeanDegree                 float64 // mean out-degree, also mean in-degree
+
+	Strong           bool
+	StrongComponents int  // number of strongly connected components
+	LargestStrong    int  // order of the largest strongly connected component
+	DAG              bool // true if the graph is acyclic
+}
+
+// UndirectedSummary holds overview statistics of an undirected graph, as
+// returned by Undirected.Summarize and LabeledUndirected.Summarize.
+//
+// As with Undirected.Degree, loops count twice in node degrees.
+type UndirectedSummary struct {
+	Order    int
+	Size     int  // number of edges
+	Loops    int  // number of loops
+	Parallel bool // true if some edges have the same end nodes
+	Simple   bool // true if no loops and no parallel edges
+
+	Components       int // number of connected components
+	LargestComponent int // order of the largest connected component
+
+	MinDegree, MaxDegree int
+	MeanDegree           float64
+}
+
+// summaryComponents returns the number of sets of ds and the size of the
+// largest.
+func summaryComponents(ds disjointSet) (count, largest int) {
+	size := make([]int, len(ds.set))
+	for n := range ds.set {
+		r := ds.find(NI(n))
+		if size[r] == 0 {
+			count++
+		}
+		size[r]++
+		if size[r] > largest {
+			largest = size[r]
+		}
+	}
+	return
+}
+
+// summaryWriter writes aligned lines of a summary.
+type summaryWriter struct {
+	strings.Builder
+}
+
+func (w *summaryWriter) line(name string, v interface{}) {
+	fmt.Fprintf(w, "%-20s %v\n", name+":", v)
+}
+
+func (w *summaryWriter) degrees(name string, min int, mean float64, max int) {
+	w.line(name, fmt.Sprintf("min %d, mean %.2f, max %d", min, mean, max))
+}
+
+// String returns the statistics formatted with one statistic per line,
+// values aligned.
+func (s DirectedSummary) String() string {
+	var w summaryWriter
+	w.line("order", s.Order)
+	w.line("arc size", s.ArcSize)
+	w.line("loops", s.Loops)
+	w.line("parallel arcs", s.Parallel)
+	w.line("simple", s.Simple)
+	w.line("weak components", s.WeakComponents)
+	w.line("largest weak", s.LargestWeak)
+	w.degrees("out-degree", s.MinOutDegree, s.MeanDegree, s.MaxOutDegree)
+	w.degrees("in-degree", s.MinInDegree, s.MeanDegree, s.MaxInDegree)
+	if s.Strong {
+		w.line("strong components", s.StrongComponents)
+		w.line("largest strong", s.LargestStrong)
+		w.line("DAG", s.DAG)
+	}
+	return w.String()
+}
+
+// String returns the statistics formatted with one statistic per line,
+// values aligned.
+func (s UndirectedSummary) String() string {
+	var w summaryWriter
+	w.line("order", s.Order)
+	w.line("size", s.Size)
+	w.line("loops", s.Loops)
+	w.line("parallel edges", s.Parallel)
+	w.line("simple", s.Simple)
+	w.line("components", s.Components)
+	w.line("largest component", s.LargestComponent)
+	w.degrees("degree", s.MinDegree, s.MeanDegree, s.MaxDegree)
+	return w.String()
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func TestSummarizeDirected(t *testing.T) {
+	r := rand.New(rand.NewSource(39))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(20)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for m := r.Intn(3 * n); m > 0; m-- {
+			fr := r.Intn(n)
+			g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(r.Intn(n)))
+		}
+		s := g.Summarize(true)
+		loop, _ := g.AnyLoop()
+		simple, _ := g.IsSimple()
+		_, nc := g.WeaklyConnectedComponentInts()
+		_, cycle := g.Topological()
+		if s.Order != g.Order() || s.ArcSize != g.ArcSize() ||
+			s.Loops > 0 != loop || s.Simple != simple ||
+			s.WeakComponents != nc || s.DAG != (cycle == nil) {
+			t.Fatal(i, s)
+		}
+		scc, _ := g.Condensation()
+		if s.StrongComponents != len(scc) {
+			t.Fatal(i, "strong components", s.StrongComponents, len(scc))
+		}
+		in := g.InDegree()
+		for fr, to := range g.AdjacencyList {
+			if len(to) < s.MinOutDegree || len(to) > s.MaxOutDegree ||
+				in[fr] < s.MinInDegree || in[fr] > s.MaxInDegree {
+				t.Fatal(i, "node", fr, "degrees", s)
+			}
+		}
+		// labeled version gives the same result
+		var lg graph.LabeledDirected
+		lg.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				lg.LabeledAdjacencyList[fr] =
+					append(lg.LabeledAdjacencyList[fr], graph.Half{to, 0})
+			}
+		}
+		if ls := lg.Summarize(true); ls != s {
+			t.Fatal(i, "labeled", ls, "unlabeled", s)
+		}
+	}
+}
+
+func TestSummarizeUndirected(t *testing.T) {
+	r := rand.New(rand.NewSource(40))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(20)
+		var lg graph.LabeledUndirected
+		lg.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		for m := r.Intn(2 * n); m > 0; m-- {
+			lg.AddEdge(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))}, 0)
+		}
+		g := graph.Undirected{lg.Unlabeled()}
+		s := g.Summarize()
+		_, nc := g.ConnectedComponentInts()
+		simple, _ := g.IsSimple()
+		if s.Order != g.Order() || s.Size != g.Size() ||
+			s.Simple != simple || s.Components != nc {
+			t.Fatal(i, s)
+		}
+		for n := range g.AdjacencyList {
+			if d := g.Degree(graph.NI(n)); d < s.MinDegree || d > s.MaxDegree {
+				t.Fatal(i, "node", n, "degree", d, s)
+			}
+		}
+		if ls := lg.Summarize(); ls != s {
+			t.Fatal(i, "labeled", ls, "unlabeled", s)
+		}
+	}
+}
+
+func TestSummarizePasses(t *testing.T) {
+	passes := 0
+	pass := func() { passes++ }
+	g := graph.GnmDirected(100, 500, rand.New(rand.NewSource(41)))
+	g.SummarizePasses(false, pass)
+	if passes < 1 || passes > 2 {
+		t.Fatal("cheap directed summary took", passes, "passes over arcs")
+	}
+	passes = 0
+	u := graph.GnmUndirected(100, 500, rand.New(rand.NewSource(42)))
+	u.SummarizePasses(pass)
+	if passes < 1 || passes > 2 {
+		t.Fatal("undirected summary took", passes, "passes over arcs")
+	}
+}
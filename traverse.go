@@ -0,0 +1,242 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// traverse.go -- options for breadth first and depth first traversals.
+
+import (
+	"math/rand"
+
+	"github.com/soniakeys/bits"
+)
+
+// traverseConfig holds the state controlling a traversal.
+type traverseConfig struct {
+	// values initialized directly from options
+	arcVisitor     func(n NI, x int)
+	levelVisitor   func(l int, n []NI)
+	nodeVisitor    func(n NI)
+	okArcVisitor   func(n NI, x int) bool
+	okLevelVisitor func(l int, n []NI) bool
+	okNodeVisitor  func(n NI) bool
+	rand           *rand.Rand
+	visBits        *bits.Bits
+	pathBits       *bits.Bits
+	fromList       *FromList
+
+	// other stuff initialized in constructor
+	rp   []PathEnd  // fromList.Paths
+	mask *bits.Bits // for masked traversals, nodes not set are hidden
+}
+
+// newTraverseConfig applies options and marks start as visited.  It returns
+// nil if start was already visited.
+func newTraverseConfig(order int, start NI, opt []TraverseOption) *traverseConfig {
+	cf := &traverseConfig{}
+	for _, o := range opt {
+		o(cf)
+	}
+	// either visBits or fromList are suitable for recording visited nodes.
+	// if neither is specified as an option, allocate bits.
+	if cf.fromList == nil {
+		if cf.visBits == nil {
+			b := bits.New(order)
+			cf.visBits = &b
+		}
+	} else {
+		if cf.fromList.Paths == nil {
+			*cf.fromList = NewFromList(order)
+		}
+		cf.rp = cf.fromList.Paths
+	}
+	if cf.hidden(start) ||
+		cf.visBits != nil && cf.visBits.Bit(int(start)) == 1 ||
+		cf.rp != nil && cf.rp[start].Len > 0 {
+		return nil
+	}
+	cf.visit(start, PathEnd{Len: 1, From: -1})
+	return cf
+}
+
+// hidden returns true if n is hidden by the mask of a masked traversal.
+func (cf *traverseConfig) hidden(n NI) bool {
+	return cf.mask != nil && cf.mask.Bit(int(n)) == 0
+}
+
+// visited returns true if n has been marked as visited.
+func (cf *traverseConfig) visited(n NI) bool {
+	if cf.visBits != nil {
+		return cf.visBits.Bit(int(n)) == 1
+	}
+	return cf.rp[n].Len > 0
+}
+
+// visit marks n as visited, recording its PathEnd p.
+func (cf *traverseConfig) visit(n NI, p PathEnd) {
+	if cf.visBits != nil {
+		cf.visBits.SetBit(int(n), 1)
+	}
+	if cf.rp != nil {
+		cf.rp[n] = p
+		if cf.fromList.MaxLen < p.Len {
+			cf.fromList.MaxLen = p.Len
+		}
+	}
+}
+
+// visitNode calls node visitors, returning false if the traversal should
+// terminate.
+func (cf *traverseConfig) visitNode(n NI) bool {
+	if cf.nodeVisitor != nil {
+		cf.nodeVisitor(n)
+	}
+	return cf.okNodeVisitor == nil || cf.okNodeVisitor(n)
+}
+
+// visitArc calls arc visitors, returning false if the traversal should
+// terminate.
+func (cf *traverseConfig) visitArc(n NI, x int) bool {
+	if cf.arcVisitor != nil {
+		cf.arcVisitor(n, x)
+	}
+	return cf.okArcVisitor == nil || cf.okArcVisitor(n, x)
+}
+
+// masked specifies the node mask of a masked traversal.
+func masked(mask bits.Bits) TraverseOption {
+	return func(c *traverseConfig) { c.mask = &mask }
+}
+
+// A TraverseOption specifies an option for a breadth first or depth first
+// traversal.
+//
+// Values of this type are returned by various TraverseOption constructor
+// functions.  These constructors take optional values to be used
+// in a traversal and wrap them in the TraverseOption type.  This type
+// is actually a function.  The BreadthFirstTraverse and DepthFirstTraverse
+// methods call these functions in order, to initialize state that controls
+// the traversal.
+type TraverseOption func(*traverseConfig)
+
+// ArcVisitor specifies a visitor function to call at each arc.
+//
+// Argument x is the index of the arc in the arc list of n.
+//
+// See also OkArcVisitor.
+func ArcVisitor(v func(n NI, x int)) TraverseOption {
+	return func(c *traverseConfig) {
+		c.arcVisitor = v
+	}
+}
+
+// From specifies a FromList to populate.
+//
+// If f.Paths is nil, a FromList of the order of the graph is allocated.
+// Otherwise nodes with a non-zero path length are considered visited and
+// limit the traversal as with Visited.
+func From(f *FromList) TraverseOption {
+	return func(c *traverseConfig) {
+		c.fromList = f
+	}
+}
+
+// LevelVisitor specifies a visitor function to call at each level of a
+// breadth first traversal.
+//
+// The level visitor function is called before any node or arc visitor
+// functions for nodes of the level.  Levels are numbered by path length in
+// nodes, as in a FromList, so the level of the start node is 1.  Note this
+// differs from TraverseLevels, which numbers levels from 0.
+//
+// See also OkLevelVisitor.
+func LevelVisitor(v func(level int, nodes []NI)) TraverseOption {
+	return func(c *traverseConfig) {
+		c.levelVisitor = v
+	}
+}
+
+// NodeVisitor specifies a visitor function to call at each node.
+//
+// The node visitor function is called before any arc visitor functions
+// for arcs from the node.
+//
+// See also OkNodeVisitor.
+func NodeVisitor(v func(NI)) TraverseOption {
+	return func(c *traverseConfig) {
+		c.nodeVisitor = v
+	}
+}
+
+// OkArcVisitor specifies a visitor function to perform some test at each arc
+// and return a boolean result.
+//
+// As long as v returns a result of true, the traverse progresses to traverse
+// all arcs.  If v returns false, the traverse terminates immediately.
+//
+// See also ArcVisitor.
+func OkArcVisitor(v func(n NI, x int) bool) TraverseOption {
+	return func(c *traverseConfig) {
+		c.okArcVisitor = v
+	}
+}
+
+// OkLevelVisitor specifies a visitor function to call at each level of a
+// breadth first traversal, returning a boolean result.
+//
+// As long as v returns a result of true, the traverse progresses to traverse
+// all nodes.  If v returns false, the traverse terminates immediately.
+//
+// Levels are numbered from 1 as for LevelVisitor.
+//
+// See also LevelVisitor.
+func OkLevelVisitor(v func(level int, nodes []NI) bool) TraverseOption {
+	return func(c *traverseConfig) {
+		c.okLevelVisitor = v
+	}
+}
+
+// OkNodeVisitor specifies a visitor function to perform some test at each node
+// and return a boolean result.
+//
+// As long as v returns a result of true, the traverse progresses to traverse
+// all nodes.  If v returns false, the traverse terminates immediately.
+//
+// See also NodeVisitor.
+func OkNodeVisitor(v func(NI) bool) TraverseOption {
+	return func(c *traverseConfig) {
+		c.okNodeVisitor = v
+	}
+}
+
+// PathBits specifies a bits.Bits value for nodes of the path to the
+// currently visited node in a depth first traversal.
+//
+// A use for PathBits is identifying back arcs in a traverse.
+//
+// Unlike Visited, PathBits are zeroed at the start of a traverse.
+func PathBits(b *bits.Bits) TraverseOption {
+	return func(c *traverseConfig) { c.pathBits = b }
+}
+
+// Rand specifies to traverse arcs from each visited node in random order.
+//
+// For a breadth first traversal, nodes of each level are also visited in
+// random order.
+func Rand(r *rand.Rand) TraverseOption {
+	return func(c *traverseConfig) { c.rand = r }
+}
+
+// Visited specifies a bits.Bits value to record visited nodes.
+//
+// For each node visited, the corresponding bit is set to 1.  Other bits
+// are not modified.
+//
+// The traverse algorithm controls the traverse using a bits.Bits.  If this
+// function is used, argument b will be used as the controlling value.
+//
+// Bits are not zeroed at the start of a traverse, so the initial Bits value
+// passed in should generally be zero.  Non-zero bits will limit the traverse.
+func Visited(b *bits.Bits) TraverseOption {
+	return func(c *traverseConfig) { c.visBits = b }
+}
@@ -0,0 +1,322 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/soniakeys/bits"
+	"github.com/soniakeys/graph"
+)
+
+func ExampleArcVisitor() {
+	//   0
+	//  / \
+	// 1-->2
+	// ^   |
+	// |   v
+	// \---3
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {3},
+		3: {1},
+	}
+	g.DepthFirstTraverse(0, graph.ArcVisitor(func(n graph.NI, x int) {
+		fmt.Println(n, "->", g[n][x])
+	}))
+	// Output:
+	// 0 -> 1
+	// 1 -> 2
+	// 2 -> 3
+	// 3 -> 1
+	// 0 -> 2
+}
+
+func ExampleFrom() {
+	// arcs are directed right:
+	//    1   3---5
+	//   / \ /   /
+	//  2   4---6--\
+	//           \-/
+	g := graph.AdjacencyList{
+		2: {1},
+		1: {4},
+		4: {3, 6},
+		3: {5},
+		6: {5, 6},
+	}
+	var f graph.FromList
+	g.BreadthFirstTraverse(1, graph.From(&f))
+	fmt.Println("Max path length:", f.MaxLen)
+	for n := range g {
+		fmt.Println(n, f.PathTo(graph.NI(n), nil))
+	}
+	// Output:
+	// Max path length: 4
+	// 0 []
+	// 1 [1]
+	// 2 []
+	// 3 [1 4 3]
+	// 4 [1 4]
+	// 5 [1 4 3 5]
+	// 6 [1 4 6]
+}
+
+func ExampleLevelVisitor() {
+	//   0
+	//  / \
+	// 1-->2
+	// ^   |
+	// |   v
+	// \---3
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 2}},
+		1: {{To: 2}},
+		2: {{To: 3}},
+		3: {{To: 1}},
+	}
+	g.BreadthFirstTraverse(0, graph.LevelVisitor(func(l int, n []graph.NI) {
+		fmt.Println(l, n)
+	}))
+	// Output:
+	// 1 [0]
+	// 2 [1 2]
+	// 3 [3]
+}
+
+func ExampleOkNodeVisitor() {
+	//   0
+	//  / \
+	// 1-->2
+	// ^   |
+	// |   v
+	// \---3
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {3},
+		3: {1},
+	}
+	g.DepthFirstTraverse(0, graph.OkNodeVisitor(func(n graph.NI) bool {
+		fmt.Println(n)
+		return n != 2
+	}))
+	// Output:
+	// 0
+	// 1
+	// 2
+}
+
+func ExamplePathBits() {
+	//   0
+	//  / \
+	// 1   2
+	// ^   |
+	// |   v
+	// \---3
+	g := graph.AdjacencyList{
+		0: {1, 2},
+		2: {3},
+		3: {1},
+	}
+	b := bits.New(len(g))
+	fmt.Println("node  path bits")
+	fmt.Println("      (3210)")
+	fmt.Println("----   ----")
+	g.DepthFirstTraverse(0, graph.PathBits(&b),
+		graph.NodeVisitor(func(n graph.NI) {
+			fmt.Printf("%4d   %s\n", n, &b)
+		}))
+	// Output:
+	// node  path bits
+	//       (3210)
+	// ----   ----
+	//    0   0001
+	//    1   0011
+	//    2   0101
+	//    3   1101
+}
+
+func ExampleVisited() {
+	//   0
+	//  / \
+	// 1-->2
+	// ^   |
+	// |   v
+	// \---3
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1}, {To: 2}},
+		1: {{To: 2}},
+		2: {{To: 3}},
+		3: {{To: 1}},
+	}
+	b := bits.New(len(g))
+	fmt.Println("3210")
+	fmt.Println("----")
+	g.DepthFirstTraverse(0, graph.Visited(&b),
+		graph.NodeVisitor(func(graph.NI) {
+			fmt.Println(b)
+		}))
+	// Output:
+	// 3210
+	// ----
+	// 0001
+	// 0011
+	// 0111
+	// 1111
+}
+
+// TestTraverseTiming checks traversal state observed at visitor calls
+// against the documented guarantees, for each traversal, labeled and
+// unlabeled.
+func TestTraverseTiming(t *testing.T) {
+	g := graph.AdjacencyList{
+		0: {1, 2, 2},
+		1: {3, 0},
+		2: {3, 4},
+		3: {5, 3},
+		4: {5},
+		5: {1},
+		6: {0},
+	}
+	lg := make(graph.LabeledAdjacencyList, len(g))
+	for fr, to := range g {
+		for _, to := range to {
+			lg[fr] = append(lg[fr], graph.Half{To: to, Label: -1})
+		}
+	}
+	for _, tc := range []struct {
+		name  string
+		tr    func(start graph.NI, opt ...graph.TraverseOption)
+		depth bool
+	}{
+		{"BreadthFirstTraverse", g.BreadthFirstTraverse, false},
+		{"labeled BreadthFirstTraverse", lg.BreadthFirstTraverse, false},
+		{"DepthFirstTraverse", g.DepthFirstTraverse, true},
+		{"labeled DepthFirstTraverse", lg.DepthFirstTraverse, true},
+	} {
+		for _, r := range []*rand.Rand{nil, rand.New(rand.NewSource(3))} {
+			var f graph.FromList
+			vis := bits.New(len(g))
+			pb := bits.New(len(g))
+			var order []graph.NI
+			checkNode := func(n graph.NI) {
+				if vis.Bit(int(n)) != 1 {
+					t.Fatal(tc.name, "node", n, "not marked visited")
+				}
+				if f.Paths[n].Len == 0 || f.Paths[n].Len > f.MaxLen {
+					t.Fatal(tc.name, "node", n, "PathEnd", f.Paths[n],
+						"MaxLen", f.MaxLen)
+				}
+				if !tc.depth {
+					return
+				}
+				want := bits.New(len(g))
+				for _, p := range f.PathTo(n, nil) {
+					want.SetBit(int(p), 1)
+				}
+				if !pb.Equal(want) {
+					t.Fatal(tc.name, "node", n, "path bits", pb, "want", want)
+				}
+			}
+			opt := []graph.TraverseOption{graph.From(&f), graph.Visited(&vis),
+				graph.NodeVisitor(func(n graph.NI) {
+					checkNode(n)
+					order = append(order, n)
+				}),
+				graph.ArcVisitor(func(n graph.NI, x int) {
+					// arc not yet followed.  n is still current.
+					checkNode(n)
+				}),
+				graph.PathBits(&pb),
+				graph.LevelVisitor(func(l int, fr []graph.NI) {
+					if tc.depth {
+						t.Fatal(tc.name, "level visitor called")
+					}
+					if f.MaxLen != l {
+						t.Fatal(tc.name, "level", l, "MaxLen", f.MaxLen)
+					}
+					for _, n := range fr {
+						if f.Paths[n].Len != l || vis.Bit(int(n)) != 1 {
+							t.Fatal(tc.name, "level", l, "node", n,
+								"PathEnd", f.Paths[n])
+						}
+					}
+				}),
+			}
+			if r != nil {
+				opt = append(opt, graph.Rand(r))
+			}
+			tc.tr(0, opt...)
+			if len(order) != 6 {
+				t.Fatal(tc.name, "visited", order)
+			}
+			for n, p := range f.Paths {
+				if n == 6 {
+					if p.Len != 0 {
+						t.Fatal(tc.name, "unreachable node has", p)
+					}
+					continue
+				}
+				if p.Len == 0 || n != 0 && f.Paths[p.From].Len != p.Len-1 {
+					t.Fatal(tc.name, "node", n, "PathEnd", p)
+				}
+			}
+			if tc.depth && pb.OnesCount() != 0 {
+				t.Fatal(tc.name, "path bits", pb, "after traversal")
+			}
+		}
+		// early termination:  no state updates after a false return
+		stops := []graph.TraverseOption{
+			graph.OkNodeVisitor(func(n graph.NI) bool { return false }),
+			graph.OkArcVisitor(func(n graph.NI, x int) bool { return false }),
+		}
+		if !tc.depth {
+			stops = append(stops, graph.OkLevelVisitor(
+				func(l int, n []graph.NI) bool { return false }))
+		}
+		for _, stop := range stops {
+			var f graph.FromList
+			vis := bits.New(len(g))
+			tc.tr(0, graph.From(&f), graph.Visited(&vis), stop)
+			if c := vis.OnesCount(); c != 1 || f.MaxLen != 1 {
+				t.Fatal(tc.name, "terminated traversal marked", c, "nodes")
+			}
+		}
+		// start already visited
+		vis := bits.New(len(g))
+		vis.SetBit(0, 1)
+		tc.tr(0, graph.Visited(&vis), graph.NodeVisitor(func(n graph.NI) {
+			t.Fatal(tc.name, "visited", n)
+		}))
+	}
+}
+
+// TestTraverseLevelsRand checks that the Rand option changes only the order
+// of nodes within levels of TraverseLevels.
+func TestTraverseLevelsRand(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 20; i++ {
+		g := graph.GnmDirected(50, 150, r).AdjacencyList
+		var want, got []string
+		levels := func(l *[]string) func(int, []graph.NI) bool {
+			return func(level int, frontier []graph.NI) bool {
+				f := append([]graph.NI{}, frontier...)
+				sort.Slice(f, func(i, j int) bool { return f[i] < f[j] })
+				*l = append(*l, fmt.Sprint(level, f))
+				return true
+			}
+		}
+		g.TraverseLevels(0, levels(&want))
+		g.TraverseLevels(0, levels(&got), graph.Rand(r))
+		if !reflect.DeepEqual(got, want) {
+			t.Fatal(i, "levels", got, "want", want)
+		}
+	}
+}
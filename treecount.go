@@ -0,0 +1,264 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// treecount.go -- counting and enumerating spanning trees.
+
+import "math/big"
+
+// SpanningTreeCount returns the number of spanning trees of g.
+//
+// The count is computed with the Matrix-Tree theorem as a determinant of a
+// minor of the Laplacian matrix of g.  The determinant is computed exactly
+// with fraction-free (Bareiss) elimination, in O(n³) arithmetic operations on
+// big integers for a graph of n nodes.
+//
+// Parallel edges are counted as distinct, so a multigraph may have more
+// spanning trees than its underlying simple graph.  Loops are ignored.
+// A disconnected graph has no spanning trees.  The graph of a single node
+// has one, the empty tree.  The graph of no nodes is taken to have none.
+func (g Undirected) SpanningTreeCount() *big.Int {
+	a := g.AdjacencyList
+	if len(a) == 0 {
+		return new(big.Int)
+	}
+	// Laplacian with row and column 0 deleted
+	l := newIntMatrix(len(a) - 1)
+	for fr, to := range a {
+		for _, to := range to {
+			if int(to) == fr {
+				continue
+			}
+			if fr > 0 {
+				l[fr-1][fr-1]++
+				if to > 0 {
+					l[fr-1][to-1]--
+				}
+			}
+		}
+	}
+	return bareissDet(l)
+}
+
+// ArborescenceCount returns the number of spanning arborescences of g
+// rooted at root.
+//
+// An arborescence here is a spanning tree with all arcs directed away from
+// the root, such as one that might be found by Directed.SpanTree.
+//
+// The count is computed with the directed Matrix-Tree theorem as a
+// determinant of a minor of the in-degree Laplacian matrix of g.
+// As with Undirected.SpanningTreeCount the determinant is computed exactly
+// with fraction-free elimination.
+//
+// Parallel arcs are counted as distinct.  Loops are ignored.
+func (g Directed) ArborescenceCount(root NI) *big.Int {
+	a := g.AdjacencyList
+	if len(a) == 0 {
+		return new(big.Int)
+	}
+	// m maps node numbers to rows and columns of the minor.
+	// the root is mapped to -1.
+	m := make([]int, len(a))
+	for i := range m {
+		switch {
+		case i < int(root):
+			m[i] = i
+		case i == int(root):
+			m[i] = -1
+		default:
+			m[i] = i - 1
+		}
+	}
+	l := newIntMatrix(len(a) - 1)
+	for fr, to := range a {
+		for _, to := range to {
+			if int(to) == fr || to == root {
+				continue
+			}
+			l[m[to]][m[to]]++
+			if r := m[fr]; r >= 0 {
+				l[r][m[to]]--
+			}
+		}
+	}
+	return bareissDet(l)
+}
+
+// newIntMatrix returns a zero n×n matrix of int64s.
+func newIntMatrix(n int) [][]int64 {
+	m := make([][]int64, n)
+	for i := range m {
+		m[i] = make([]int64, n)
+	}
+	return m
+}
+
+// bareissDet returns the determinant of square integer matrix m using
+// Bareiss's fraction-free elimination.
+//
+// All intermediate values are determinants of minors of m, so division is
+// exact at each step.  The determinant of a 0×0 matrix is 1.
+func bareissDet(m [][]int64) *big.Int {
+	n := len(m)
+	b := make([][]*big.Int, n)
+	for i, row := range m {
+		b[i] = make([]*big.Int, n)
+		for j, x := range row {
+			b[i][j] = big.NewInt(x)
+		}
+	}
+	neg := false
+	prev := big.NewInt(1)
+	var t big.Int
+	for k := 0; k < n-1; k++ {
+		if b[k][k].Sign() == 0 {
+			// pivot
+			p := k + 1
+			for p < n && b[p][k].Sign() == 0 {
+				p++
+			}
+			if p == n {
+				return new(big.Int)
+			}
+			b[k], b[p] = b[p], b[k]
+			neg = !neg
+		}
+		for i := k + 1; i < n; i++ {
+			for j := k + 1; j < n; j++ {
+				x := b[i][j]
+				x.Mul(x, b[k][k])
+				x.Sub(x, t.Mul(b[i][k], b[k][j]))
+				x.Quo(x, prev)
+			}
+		}
+		prev = b[k][k]
+	}
+	if n == 0 {
+		return big.NewInt(1)
+	}
+	d := new(big.Int).Set(b[n-1][n-1])
+	if neg {
+		d.Neg(d)
+	}
+	return d
+}
+
+// SpanningTrees enumerates the spanning trees of g.
+//
+// Each spanning tree is emitted as a FromList rooted at node 0, with Len,
+// Leaves, and MaxLen populated.  Each FromList is newly allocated and may be
+// retained by the caller.  Enumeration terminates early if emit returns
+// false.  SpanningTrees returns true if enumeration completes, false if it
+// was terminated early.
+//
+// Trees are enumerated by recursive inclusion and exclusion of edges, where
+// an edge is included only if it does not close a cycle and excluded only if
+// the remaining edges still connect the graph.  Every branch of the recursion
+// thus leads to a spanning tree and time between emitted trees is polynomial.
+//
+// As with SpanningTreeCount, parallel edges are taken as distinct and loops
+// are ignored.  In the case of parallel edges, trees differing only in the
+// choice of parallel edge are emitted separately, and so the same FromList
+// may be emitted more than once.  The number of trees emitted is always equal
+// to the count returned by SpanningTreeCount.
+//
+// The number of spanning trees can be exponential in the size of the graph.
+// Use SpanningTreeCount to find the number before enumerating.
+func (g Undirected) SpanningTrees(emit func(FromList) bool) bool {
+	a := g.AdjacencyList
+	n := len(a)
+	if n == 0 {
+		return true
+	}
+	var edges []Edge
+	for fr, to := range a {
+		for _, to := range to {
+			if NI(fr) < to {
+				edges = append(edges, Edge{NI(fr), to})
+			}
+		}
+	}
+	// union-find without path compression so that unions can be undone.
+	parent := make([]NI, n)
+	for i := range parent {
+		parent[i] = -1
+	}
+	find := func(x NI) NI {
+		for parent[x] >= 0 {
+			x = parent[x]
+		}
+		return x
+	}
+	// connects returns true if the chosen edges and edges from index i
+	// connect the graph.
+	cp := make([]NI, n)
+	connects := func(chosen []Edge, i int) bool {
+		for j := range cp {
+			cp[j] = -1
+		}
+		cf := func(x NI) NI {
+			for cp[x] >= 0 {
+				x = cp[x]
+			}
+			return x
+		}
+		nc := n
+		join := func(e Edge) {
+			if r1, r2 := cf(e.N1), cf(e.N2); r1 != r2 {
+				cp[r1] = r2
+				nc--
+			}
+		}
+		for _, e := range chosen {
+			join(e)
+		}
+		for _, e := range edges[i:] {
+			join(e)
+		}
+		return nc == 1
+	}
+	if !connects(nil, 0) {
+		return true
+	}
+	ta := make(AdjacencyList, n)
+	tree := func(chosen []Edge) FromList {
+		for i := range ta {
+			ta[i] = ta[i][:0]
+		}
+		for _, e := range chosen {
+			ta[e.N1] = append(ta[e.N1], e.N2)
+			ta[e.N2] = append(ta[e.N2], e.N1)
+		}
+		var f FromList
+		Undirected{ta}.SpanTree(0, &f)
+		f.RecalcLeaves()
+		f.RecalcLen()
+		return f
+	}
+	chosen := make([]Edge, 0, n-1)
+	var rec func(i int) bool
+	rec = func(i int) bool {
+		if len(chosen) == n-1 {
+			return emit(tree(chosen))
+		}
+		e := edges[i]
+		// include e
+		if r1, r2 := find(e.N1), find(e.N2); r1 != r2 {
+			parent[r1] = r2
+			chosen = append(chosen, e)
+			if !rec(i + 1) {
+				return false
+			}
+			chosen = chosen[:len(chosen)-1]
+			parent[r1] = -1
+		}
+		// exclude e
+		if connects(chosen, i+1) {
+			return rec(i + 1)
+		}
+		return true
+	}
+	return rec(0)
+}
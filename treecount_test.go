@@ -0,0 +1,221 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+	"github.com/soniakeys/graph/graphtest"
+)
+
+func ExampleUndirected_SpanningTreeCount() {
+	// 0---1
+	// |  /|
+	// | / |
+	// |/  |
+	// 2---3
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	g.AddEdge(2, 3)
+	fmt.Println(g.SpanningTreeCount())
+	// Output:
+	// 8
+}
+
+func ExampleUndirected_SpanningTrees() {
+	// 0---1
+	// |  /
+	// | /
+	// |/
+	// 2
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.SpanningTrees(func(f graph.FromList) bool {
+		fmt.Println(f.Paths)
+		return true
+	})
+	// Output:
+	// [{-1 1} {0 2} {0 2}]
+	// [{-1 1} {0 2} {1 3}]
+	// [{-1 1} {2 3} {0 2}]
+}
+
+func ExampleDirected_ArborescenceCount() {
+	// 0-->1<--2
+	//  \  ^   ^
+	//   \ |  /
+	//    v| /
+	//     3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 3},
+		2: {1},
+		3: {1, 2},
+	}}
+	fmt.Println(g.ArborescenceCount(0))
+	fmt.Println(g.ArborescenceCount(1))
+	// Output:
+	// 3
+	// 0
+}
+
+func TestSpanningTreeCountKnown(t *testing.T) {
+	for n := 1; n <= 20; n++ {
+		// complete graph, n^(n-2)
+		var k graph.Undirected
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				k.AddEdge(graph.NI(i), graph.NI(j))
+			}
+		}
+		if n == 1 {
+			k.AdjacencyList = make(graph.AdjacencyList, 1)
+		}
+		want := big.NewInt(1)
+		if n > 2 {
+			want.Exp(big.NewInt(int64(n)), big.NewInt(int64(n-2)), nil)
+		}
+		if got := k.SpanningTreeCount(); got.Cmp(want) != 0 {
+			t.Fatal("K", n, "count", got, "want", want)
+		}
+		// complete digraph, n^(n-2) arborescences at any root
+		kd := graph.Directed{make(graph.AdjacencyList, n)}
+		for i := range kd.AdjacencyList {
+			for j := 0; j < n; j++ {
+				if i != j {
+					kd.AdjacencyList[i] = append(kd.AdjacencyList[i], graph.NI(j))
+				}
+			}
+		}
+		if got := kd.ArborescenceCount(graph.NI(n / 2)); got.Cmp(want) != 0 {
+			t.Fatal("directed K", n, "count", got, "want", want)
+		}
+		if n < 3 {
+			continue
+		}
+		// cycle, n
+		var c graph.Undirected
+		for i := 0; i < n; i++ {
+			c.AddEdge(graph.NI(i), graph.NI((i+1)%n))
+		}
+		if got := c.SpanningTreeCount(); got.Int64() != int64(n) {
+			t.Fatal("C", n, "count", got)
+		}
+		// random tree, 1
+		var tr graph.Undirected
+		for i := 1; i < n; i++ {
+			tr.AddEdge(graph.NI(i), graph.NI(rand.Intn(i)))
+		}
+		if got := tr.SpanningTreeCount(); got.Int64() != 1 {
+			t.Fatal("tree count", got)
+		}
+		// tree with a loop added, still 1; edge removed, 0
+		tr.AddEdge(0, 0)
+		if got := tr.SpanningTreeCount(); got.Int64() != 1 {
+			t.Fatal("tree with loop count", got)
+		}
+		tr.RemoveEdge(1, tr.AdjacencyList[1][0])
+		if got := tr.SpanningTreeCount(); got.Sign() != 0 {
+			t.Fatal("forest count", got)
+		}
+	}
+}
+
+func TestSpanningTreesEnumerate(t *testing.T) {
+	r := rand.New(rand.NewSource(71))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(8)
+		var g graph.Undirected
+		g.AdjacencyList = make(graph.AdjacencyList, n)
+		for j := r.Intn(3 * n); j > 0; j-- {
+			g.AddEdge(graph.NI(r.Intn(n)), graph.NI(r.Intn(n)))
+		}
+		want := g.SpanningTreeCount()
+		count := int64(0)
+		g.SpanningTrees(func(f graph.FromList) bool {
+			count++
+			if err := graphtest.CheckSpanningTree(g, f); err != nil {
+				t.Fatal(f.Paths, err)
+			}
+			if f.Paths[0].From >= 0 {
+				t.Fatal("node 0 not root")
+			}
+			return true
+		})
+		if count != want.Int64() {
+			t.Fatal(g.AdjacencyList, "enumerated", count, "want", want)
+		}
+		// early termination
+		if count > 1 {
+			emitted := 0
+			if g.SpanningTrees(func(graph.FromList) bool {
+				emitted++
+				return emitted < 2
+			}) || emitted != 2 {
+				t.Fatal("emit did not terminate")
+			}
+		}
+	}
+}
+
+func TestArborescenceCountBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(73))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(6)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for j := r.Intn(3 * n); j > 0; j-- {
+			fr := r.Intn(n)
+			g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(r.Intn(n)))
+		}
+		root := graph.NI(r.Intn(n))
+		// each non-root node chooses an in-arc.  count acyclic choices.
+		in := make([][]graph.NI, n)
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				if int(to) != fr {
+					in[to] = append(in[to], graph.NI(fr))
+				}
+			}
+		}
+		p := make([]graph.NI, n)
+		p[root] = -1
+		var count int64
+		var choose func(nd int)
+		choose = func(nd int) {
+			if nd == n {
+				for s := range p {
+					x := graph.NI(s)
+					for k := 0; k < n && x >= 0; k++ {
+						x = p[x]
+					}
+					if x >= 0 {
+						return
+					}
+				}
+				count++
+				return
+			}
+			if graph.NI(nd) == root {
+				choose(nd + 1)
+				return
+			}
+			for _, fr := range in[nd] {
+				p[nd] = fr
+				choose(nd + 1)
+			}
+		}
+		choose(0)
+		if got := g.ArborescenceCount(root); got.Int64() != count {
+			t.Fatal(g.AdjacencyList, "root", root, "count", got, "want", count)
+		}
+	}
+}
@@ -7,7 +7,9 @@ package graph
 // LabeledUndirected.
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/soniakeys/bits"
 )
@@ -108,6 +110,140 @@ func Density(n, m int) float64 {
 	return float64(m) * 2 / (float64(n) * float64(n-1))
 }
 
+// BalancedSeparator finds a node separator that splits g into pieces of at
+// most beta * g.Order() nodes.
+//
+// The separator is seeded from a breadth first level structure rooted at a
+// pseudo-peripheral node of the largest connected component.  Levels near
+// the median level bound a window; nodes at levels below the window and
+// above the window are taken as node sets a and b for Separator.  The window
+// is made as wide as possible while still guaranteeing balance, giving the
+// flow computation the most freedom to find a small separator.
+//
+// Argument beta should be at least .5.  Return value ok is true if every
+// connected component remaining after removal of sep has at most
+// beta * g.Order() nodes.
+//
+// See also Separator.
+func (g Undirected) BalancedSeparator(beta float64) (sep bits.Bits, ok bool) {
+	a := g.AdjacencyList
+	n := len(a)
+	max := int(beta * float64(n))
+	if n == 0 {
+		return bits.New(0), true
+	}
+	reps, orders, _ := g.ConnectedComponentReps()
+	c := 0
+	for i, o := range orders {
+		if o > orders[c] {
+			c = i
+		}
+	}
+	nc := orders[c]
+	lv, far := a.bfLevels(reps[c])
+	lv, far = a.bfLevels(far)
+	cnt := make([]int, lv[far]+1) // number of nodes at each level
+	for _, l := range lv {
+		if l >= 0 {
+			cnt[l]++
+		}
+	}
+	// below[l] is the number of nodes at levels < l
+	below := make([]int, len(cnt)+1)
+	for l, nl := range cnt {
+		below[l+1] = below[l] + nl
+	}
+	k := 0 // median level
+	for below[k+1]*2 < nc {
+		k++
+	}
+	// each side must keep at least need nodes for pieces to fit in max
+	need := nc - max
+	lo, hi := k, k
+	for lo > 0 && below[lo-1] >= need {
+		lo--
+	}
+	for hi < len(cnt)-1 && nc-below[hi+2] >= need {
+		hi++
+	}
+	sa := bits.New(n)
+	sb := bits.New(n)
+	min := n
+	for nd, l := range lv {
+		switch {
+		case l < 0:
+		case l < lo:
+			sa.SetBit(nd, 1)
+		case l > hi:
+			sb.SetBit(nd, 1)
+		case cnt[l] < min:
+			min = cnt[l]
+		}
+	}
+	// any level in the window separates sa and sb so flow is bounded by min
+	if sep, ok = g.Separator(sa, sb, min); !ok {
+		sep = bits.New(n)
+		for nd, l := range lv {
+			if l == k {
+				sep.SetBit(nd, 1)
+			}
+		}
+	}
+	return sep, a.maxComponent(sep) <= max
+}
+
+// bfLevels returns breadth first levels of nodes reachable from start, with
+// -1 for unreached nodes, and a node at the maximum level.
+func (g AdjacencyList) bfLevels(start NI) (lv []int, far NI) {
+	lv = make([]int, len(g))
+	for i := range lv {
+		lv[i] = -1
+	}
+	lv[start] = 0
+	far = start
+	q := []NI{start}
+	for len(q) > 0 {
+		fr := q[0]
+		q = q[1:]
+		far = fr
+		for _, to := range g[fr] {
+			if lv[to] < 0 {
+				lv[to] = lv[fr] + 1
+				q = append(q, to)
+			}
+		}
+	}
+	return
+}
+
+// maxComponent returns the order of the largest connected component of the
+// undirected graph g with nodes of del removed.
+func (g AdjacencyList) maxComponent(del bits.Bits) (max int) {
+	v := bits.New(len(g))
+	v.Set(del)
+	var q []NI
+	for n := v.ZeroFrom(0); n >= 0; n = v.ZeroFrom(n + 1) {
+		v.SetBit(n, 1)
+		q = append(q[:0], NI(n))
+		o := 0
+		for len(q) > 0 {
+			fr := q[0]
+			q = q[1:]
+			o++
+			for _, to := range g[fr] {
+				if v.Bit(int(to)) == 0 {
+					v.SetBit(int(to), 1)
+					q = append(q, to)
+				}
+			}
+		}
+		if o > max {
+			max = o
+		}
+	}
+	return
+}
+
 // An EdgeVisitor is an argument to some traversal methods.
 //
 // Traversal methods call the visitor function for each edge visited.
@@ -123,31 +259,91 @@ type EdgeVisitor func(e Edge)
 // See also Undirected.SimpleEdges for a version that emits only the simple
 // subgraph.
 func (g Undirected) Edges(v EdgeVisitor) {
+	unpaired := make(AdjacencyList, len(g.AdjacencyList))
+	g.Arcs(func(fr NI, _ int, to NI) bool {
+		if to == fr {
+			v(Edge{fr, to}) // output loop
+			return true
+		}
+		// search unpaired arcs
+		ut := unpaired[to]
+		for i, u := range ut {
+			if u == fr { // found reciprocal
+				v(Edge{u, to}) // output edge
+				last := len(ut) - 1
+				ut[i] = ut[last]
+				unpaired[to] = ut[:last]
+				return true
+			}
+		}
+		// reciprocal not found
+		unpaired[fr] = append(unpaired[fr], to)
+		return true
+	})
+	// undefined behavior is that unpaired arcs are silently ignored.
+}
+
+// FundamentalCycles emits a cycle basis of g, the fundamental cycles of a
+// spanning forest.
+//
+// A spanning forest is built as by FromList.  Then for each edge not in the
+// forest, the cycle formed by the edge and the forest path between its
+// nodes is emitted.  The cycles form a basis of the cycle space of g, with
+// one cycle for each edge not in the forest, that is m - n + c cycles for a
+// graph with m edges, n nodes, and c connected components.
+//
+// Each cycle is emitted as a list of nodes as for CanonicalUndirectedCycle,
+// where an edge connects each node to the next and the last node back to
+// the first.  A loop is emitted as a cycle of a single node and a parallel
+// edge as a cycle of two nodes.  Each emitted slice is newly allocated.
+// If emit returns false, FundamentalCycles returns immediately.
+//
+// See also LabeledUndirected.FundamentalCycles.
+func (g Undirected) FundamentalCycles(emit func([]NI) bool) {
 	a := g.AdjacencyList
-	unpaired := make(AdjacencyList, len(a))
-	for fr, to := range a {
-	arc: // for each arc in a
-		for _, to := range to {
-			if to == NI(fr) {
-				v(Edge{NI(fr), to}) // output loop
+	f, _, _ := g.FromList()
+	p := f.Paths
+	tree := bits.New(len(a)) // nodes with edge to parent seen
+	for u, to := range a {
+		n1 := NI(u)
+		for _, n2 := range to {
+			switch {
+			case n2 < n1:
+				continue // edge seen from n2
+			case n2 == n1:
+				if !emit([]NI{n1}) {
+					return
+				}
+				continue
+			case p[n2].From == n1 && tree.Bit(int(n2)) == 0:
+				tree.SetBit(int(n2), 1)
+				continue
+			case p[n1].From == n2 && tree.Bit(u) == 0:
+				tree.SetBit(u, 1)
 				continue
 			}
-			// search unpaired arcs
-			ut := unpaired[to]
-			for i, u := range ut {
-				if u == NI(fr) { // found reciprocal
-					v(Edge{u, to}) // output edge
-					last := len(ut) - 1
-					ut[i] = ut[last]
-					unpaired[to] = ut[:last]
-					continue arc
+			// path from n1 up to common ancestor, then down to n2
+			var up, down []NI
+			for x, y := n1, n2; x != y; {
+				if p[x].Len >= p[y].Len {
+					up = append(up, x)
+					x = p[x].From
+				} else {
+					down = append(down, y)
+					y = p[y].From
+				}
+				if x == y {
+					up = append(up, x)
 				}
 			}
-			// reciprocal not found
-			unpaired[fr] = append(unpaired[fr], to)
+			for i := len(down) - 1; i >= 0; i-- {
+				up = append(up, down[i])
+			}
+			if !emit(up) {
+				return
+			}
 		}
 	}
-	// undefined behavior is that unpaired arcs are silently ignored.
 }
 
 // FromList builds a forest with a tree spanning each connected component.
@@ -200,6 +396,53 @@ func (g Undirected) HasEdge(n1, n2 NI) (has bool, x1, x2 int) {
 	return
 }
 
+// Separator finds a minimum node separator between node sets a and b.
+//
+// A separator is a set of nodes, disjoint from a and b, whose removal leaves
+// no path between a node of a and a node of b.  Separator computes a minimum
+// separator with max flow on a network where each node not in a or b is
+// split into an in-node and an out-node joined by an arc of unit capacity.
+//
+// If the minimum separator has more than maxSize nodes Separator returns
+// ok = false.  Flow computation stops in this case as soon as maxSize + 1
+// node-disjoint paths are found.  Separator also returns ok = false if no
+// separator exists, that is, if a and b share a node or if an edge joins a
+// node of a to a node of b.
+//
+// See also BalancedSeparator.
+func (g Undirected) Separator(a, b bits.Bits, maxSize int) (sep bits.Bits, ok bool) {
+	n := len(g.AdjacencyList)
+	f := make(flowNet, 2*n+2)
+	s, t := NI(2*n), NI(2*n+1)
+	for fr, to := range g.AdjacencyList {
+		in, out := NI(2*fr), NI(2*fr+1)
+		c := 1.
+		if a.Bit(fr) == 1 {
+			c = flowInf
+			f.addArc(s, in, flowInf)
+		}
+		if b.Bit(fr) == 1 {
+			c = flowInf
+			f.addArc(out, t, flowInf)
+		}
+		f.addArc(in, out, c)
+		for _, to := range to {
+			f.addArc(out, 2*to, flowInf)
+		}
+	}
+	if f.maxFlow(s, t, float64(maxSize)) > float64(maxSize) {
+		return sep, false
+	}
+	r := f.reach(s)
+	sep = bits.New(n)
+	for i := 0; i < n; i++ {
+		if r.Bit(2*i) == 1 && r.Bit(2*i+1) == 0 {
+			sep.SetBit(i, 1)
+		}
+	}
+	return sep, true
+}
+
 // SimpleEdges iterates over the edges of the simple subgraph of an undirected
 // graph.
 //
@@ -470,28 +713,226 @@ type LabeledEdgeVisitor func(e LabeledEdge)
 // See also the more simplistic LabeledAdjacencyList.ArcsAsEdges.
 func (g LabeledUndirected) Edges(v LabeledEdgeVisitor) {
 	// similar code in LabeledAdjacencyList.InUndirected
+	unpaired := make(LabeledAdjacencyList, len(g.LabeledAdjacencyList))
+	g.Arcs(func(fr NI, _ int, to Half) bool {
+		if to.To == fr {
+			v(LabeledEdge{Edge{fr, to.To}, to.Label}) // output loop
+			return true
+		}
+		// search unpaired arcs
+		ut := unpaired[to.To]
+		for i, u := range ut {
+			if u.To == fr && u.Label == to.Label { // found reciprocal
+				v(LabeledEdge{Edge{fr, to.To}, to.Label}) // output edge
+				last := len(ut) - 1
+				ut[i] = ut[last]
+				unpaired[to.To] = ut[:last]
+				return true
+			}
+		}
+		// reciprocal not found
+		unpaired[fr] = append(unpaired[fr], to)
+		return true
+	})
+}
+
+// EulerianCycleOrdered finds an Eulerian cycle in an undirected multigraph,
+// following edges in an order given by a comparison function.
+//
+// EulerianCycleOrdered is like EulerianCycle but rather than following arcs
+// in arc list order, each step of the cycle construction follows the least
+// available arc according to less.  Arcs comparing equal are followed in arc
+// list order.
+//
+// The result and any error are as described for EulerianCycle.
+//
+// Internally, EulerianCycleOrdered copies the entire graph g.
+//
+// See also LabeledDirected.EulerianCycleOrdered.
+func (g LabeledUndirected) EulerianCycleOrdered(less func(a, b Half) bool) ([]Half, error) {
+	c := g.sortedCopy(less)
+	if c.Order() == 0 {
+		return nil, nil
+	}
+	e := newLabEulerian(c.LabeledAdjacencyList, c.Size())
+	e.ordered = true
+	e.p[0] = Half{0, -1}
+	for e.s >= 0 {
+		v := e.top()
+		if err := e.pushUndir(); err != nil {
+			return nil, err
+		}
+		if e.top().To != v.To {
+			return nil, errors.New("not Eulerian")
+		}
+		e.keep()
+	}
+	if !e.uv.AllZeros() {
+		return nil, errors.New("not strongly connected")
+	}
+	return e.p, nil
+}
+
+// EulerianPathOrdered finds an Eulerian path in an undirected multigraph,
+// following edges in an order given by a comparison function.
+//
+// EulerianPathOrdered is like EulerianPath but follows arcs in the order of
+// less as described for EulerianCycleOrdered.
+//
+// Internally, EulerianPathOrdered copies the entire graph g.
+func (g LabeledUndirected) EulerianPathOrdered(less func(a, b Half) bool) ([]Half, error) {
+	c := g.sortedCopy(less)
+	if c.Order() == 0 {
+		return nil, nil
+	}
+	start := c.EulerianStart()
+	if start < 0 {
+		start = 0
+	}
+	e := newLabEulerian(c.LabeledAdjacencyList, c.Size())
+	e.ordered = true
+	e.p[0] = Half{start, -1}
+	if err := e.pushUndir(); err != nil {
+		return nil, err
+	}
+	e.keep()
+	for e.s >= 0 {
+		start = e.top().To
+		if err := e.pushUndir(); err != nil {
+			return nil, err
+		}
+		if e.top().To != start {
+			return nil, errors.New("no Eulerian path")
+		}
+		e.keep()
+	}
+	if !e.uv.AllZeros() {
+		return nil, errors.New("no Eulerian path")
+	}
+	return e.p, nil
+}
+
+// sortedCopy returns a copy of g with each arc list stably sorted by less.
+func (g LabeledUndirected) sortedCopy(less func(a, b Half) bool) LabeledUndirected {
+	c, _ := g.Copy()
+	for _, to := range c.LabeledAdjacencyList {
+		sort.SliceStable(to, func(i, j int) bool { return less(to[i], to[j]) })
+	}
+	return c
+}
+
+// FilterEdges constructs a new graph with the edges of g for which keep
+// returns true.
+//
+// Keep is called once for each edge, that is once for each reciprocal arc
+// pair and once for each loop, with n1 <= n2.  Both arcs of an edge are kept
+// or dropped together, so the result is undirected even if keep would
+// return different results for the two orientations.  The result has the
+// same order as g; nodes are not renumbered and may become isolated.  Arcs
+// are in the same order as in g.
+//
+// See also LabeledDirected.FilterArcs.
+func (g LabeledUndirected) FilterEdges(keep func(n1, n2 NI, l LI) bool) LabeledUndirected {
 	a := g.LabeledAdjacencyList
-	unpaired := make(LabeledAdjacencyList, len(a))
+	f := make(LabeledAdjacencyList, len(a))
+	// decisions for reciprocal arcs, keyed by the reciprocal arc.  The key
+	// node is the greater NI.  Parallel edges queue multiple decisions.
+	type arc struct {
+		fr NI
+		Half
+	}
+	pending := map[arc][]bool{}
 	for fr, to := range a {
-	arc: // for each arc in a
+		n1 := NI(fr)
 		for _, to := range to {
-			if to.To == NI(fr) {
-				v(LabeledEdge{Edge{NI(fr), to.To}, to.Label}) // output loop
+			if to.To >= n1 {
+				k := keep(n1, to.To, to.Label)
+				if k {
+					f[fr] = append(f[fr], to)
+				}
+				if to.To > n1 {
+					r := arc{to.To, Half{n1, to.Label}}
+					pending[r] = append(pending[r], k)
+				}
 				continue
 			}
-			// search unpaired arcs
-			ut := unpaired[to.To]
-			for i, u := range ut {
-				if u.To == NI(fr) && u.Label == to.Label { // found reciprocal
-					v(LabeledEdge{Edge{NI(fr), to.To}, to.Label}) // output edge
-					last := len(ut) - 1
-					ut[i] = ut[last]
-					unpaired[to.To] = ut[:last]
-					continue arc
+			// look up decision for reciprocal
+			r := arc{n1, to}
+			if p := pending[r]; len(p) > 0 {
+				if p[0] {
+					f[fr] = append(f[fr], to)
 				}
+				if len(p) == 1 {
+					delete(pending, r)
+				} else {
+					pending[r] = p[1:]
+				}
+				continue
 			}
 			// reciprocal not found
-			unpaired[fr] = append(unpaired[fr], to)
+			if keep(to.To, n1, to.Label) {
+				f[fr] = append(f[fr], to)
+			}
+		}
+	}
+	return LabeledUndirected{f}
+}
+
+// FundamentalCycles emits a cycle basis of g, the fundamental cycles of a
+// spanning forest.
+//
+// Cycles are those of Undirected.FundamentalCycles.  Where g has parallel
+// edges, their labels distinguish the edge in the forest from the others.
+//
+// Each cycle is emitted as a list of half arcs as for
+// CanonicalLabeledUndirectedCycle, where the first half arc leads from the
+// to-node of the last.  The last half arc is that of the edge not in the
+// forest.  A loop is emitted as a single half arc.  Each emitted slice is
+// newly allocated.  If emit returns false, FundamentalCycles returns
+// immediately.
+func (g LabeledUndirected) FundamentalCycles(emit func([]Half) bool) {
+	a := g.LabeledAdjacencyList
+	f, labels, _, _ := g.FromList()
+	p := f.Paths
+	tree := bits.New(len(a)) // nodes with edge to parent seen
+	for u, to := range a {
+		n1 := NI(u)
+		for _, h := range to {
+			n2 := h.To
+			switch {
+			case n2 < n1:
+				continue // edge seen from n2
+			case n2 == n1:
+				if !emit([]Half{h}) {
+					return
+				}
+				continue
+			case p[n2].From == n1 && labels[n2] == h.Label &&
+				tree.Bit(int(n2)) == 0:
+				tree.SetBit(int(n2), 1)
+				continue
+			case p[n1].From == n2 && labels[n1] == h.Label &&
+				tree.Bit(u) == 0:
+				tree.SetBit(u, 1)
+				continue
+			}
+			// half arcs from n1 up to common ancestor, then down to n2
+			var up, down []Half
+			for x, y := n1, n2; x != y; {
+				if p[x].Len >= p[y].Len {
+					up = append(up, Half{p[x].From, labels[x]})
+					x = p[x].From
+				} else {
+					down = append(down, Half{y, labels[y]})
+					y = p[y].From
+				}
+			}
+			for i := len(down) - 1; i >= 0; i-- {
+				up = append(up, down[i])
+			}
+			if !emit(append(up, Half{n1, h.Label})) {
+				return
+			}
 		}
 	}
 }
@@ -804,7 +1245,11 @@ func (e *labEulerian) pushUndir() error {
 			for x, rx := range a2 {
 				if rx.To == u.To && rx.Label == w.Label { // here it is
 					last := len(a2) - 1
-					a2[x] = a2[last]      // someone else can have the seat
+					if e.ordered {
+						copy(a2[x:], a2[x+1:])
+					} else {
+						a2[x] = a2[last] // someone else can have the seat
+					}
 					e.g[w.To] = a2[:last] // and it's gone.
 					goto l
 				}
@@ -77,41 +77,41 @@ func (g LabeledUndirected) Bipartite() (b *LabeledBipartite, oc []NI, ok bool) {
 // There are equivalent labeled and unlabeled versions of this method.
 func (g LabeledUndirected) BipartiteComponent(n NI, c1, c2 bits.Bits) (b bool, n1, n2 int, oc []NI) {
 	a := g.LabeledAdjacencyList
-	b = true
-	var open bool
-	var df func(n NI, c1, c2 *bits.Bits, n1, n2 *int)
-	df = func(n NI, c1, c2 *bits.Bits, n1, n2 *int) {
-		c1.SetBit(int(n), 1)
-		*n1++
-		for _, nb := range a[n] {
-			if c1.Bit(int(nb.To)) == 1 {
-				b = false
-				oc = []NI{nb.To, n}
-				open = true
-				return
-			}
-			if c2.Bit(int(nb.To)) == 1 {
-				continue
-			}
-			df(nb.To, c2, c1, n2, n1)
-			if b {
-				continue
-			}
-			switch {
-			case !open:
-			case n == oc[0]:
-				open = false
-			default:
-				oc = append(oc, n)
+	// depth first, with nodes at even depths colored c1, odd depths c2.
+	type frame struct {
+		n NI  // node
+		x int // index of next arc from n
+	}
+	c := [2]bits.Bits{c1, c2}
+	var count [2]int
+	c1.SetBit(int(n), 1)
+	count[0]++
+	stack := []frame{{n, 0}}
+	for len(stack) > 0 {
+		d := len(stack) - 1
+		top := &stack[d]
+		if top.x == len(a[top.n]) {
+			stack = stack[:d]
+			continue
+		}
+		nb := a[top.n][top.x].To
+		top.x++
+		if c[d%2].Bit(int(nb)) == 1 {
+			// odd cycle.  nb is on the stack, read the cycle back to it.
+			// a loop gives just nb, top.n; that is, nb twice.
+			oc = []NI{nb, top.n}
+			for i := d - 1; i >= 0 && nb != top.n && stack[i].n != nb; i-- {
+				oc = append(oc, stack[i].n)
 			}
-			return
+			return false, 0, 0, oc
+		}
+		if co := c[(d+1)%2]; co.Bit(int(nb)) == 0 {
+			co.SetBit(int(nb), 1)
+			count[(d+1)%2]++
+			stack = append(stack, frame{nb, 0})
 		}
 	}
-	df(n, &c1, &c2, &n1, &n2)
-	if b {
-		return b, n1, n2, nil
-	}
-	return b, 0, 0, oc
+	return true, count[0], count[1], nil
 }
 
 // BronKerbosch1 finds maximal cliques in an undirected graph.
@@ -242,6 +242,20 @@ func (g LabeledUndirected) BKPivotMinP(P, X bits.Bits) NI {
 // See also simpler variant BronKerbosch1 and more sophisticated variant
 // BronKerbosch3.
 func (g LabeledUndirected) BronKerbosch2(pivot func(P, X bits.Bits) NI, emit func(bits.Bits) bool) {
+	all := bits.New(g.Order())
+	all.SetAll()
+	g.BronKerbosch2Masked(all, pivot, emit)
+}
+
+// BronKerbosch2Masked finds maximal cliques in the subgraph of g induced by
+// the nodes set in mask.
+//
+// The result is that of BronKerbosch2 on the induced subgraph, but with node
+// numbers of g and without constructing the subgraph.  Nodes not set in mask
+// are hidden and edges to them are ignored.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledUndirected) BronKerbosch2Masked(mask bits.Bits, pivot func(P, X bits.Bits) NI, emit func(bits.Bits) bool) {
 	a := g.LabeledAdjacencyList
 	var f func(R, P, X bits.Bits) bool
 	f = func(R, P, X bits.Bits) bool {
@@ -288,7 +302,7 @@ func (g LabeledUndirected) BronKerbosch2(pivot func(P, X bits.Bits) NI, emit fun
 	R := bits.New(len(a))
 	P := bits.New(len(a))
 	X := bits.New(len(a))
-	P.SetAll()
+	P.Set(mask)
 	f(R, P, X)
 }
 
@@ -405,18 +419,10 @@ func (g LabeledUndirected) ConnectedComponentBits() func() (order, arcSize int,
 	vg := bits.New(len(a)) // nodes visited in graph
 	vc := bits.New(len(a)) // nodes visited in current component
 	var order, arcSize int
-	var df func(NI)
-	df = func(n NI) {
-		vg.SetBit(int(n), 1)
+	visit := func(n NI) {
 		vc.SetBit(int(n), 1)
 		order++
 		arcSize += len(a[n])
-		for _, nb := range a[n] {
-			if vg.Bit(int(nb.To)) == 0 {
-				df(nb.To)
-			}
-		}
-		return
 	}
 	var n int
 	return func() (o, ma int, b bits.Bits) {
@@ -424,7 +430,7 @@ func (g LabeledUndirected) ConnectedComponentBits() func() (order, arcSize int,
 			if vg.Bit(n) == 0 {
 				vc.ClearAll()
 				order, arcSize = 0, 0
-				df(NI(n))
+				a.dfPre(NI(n), vg, visit)
 				return order, arcSize, vc
 			}
 		}
@@ -446,25 +452,44 @@ func (g LabeledUndirected) ConnectedComponentBits() func() (order, arcSize int,
 func (g LabeledUndirected) ConnectedComponentInts() (ci []int, nc int) {
 	a := g.LabeledAdjacencyList
 	ci = make([]int, len(a))
-	var df func(NI)
-	df = func(nd NI) {
-		ci[nd] = nc
-		for _, to := range a[nd] {
-			if ci[to.To] == 0 {
-				df(to.To)
-			}
-		}
-		return
-	}
+	v := bits.New(len(a))
+	visit := func(nd NI) { ci[nd] = nc }
 	for nd := range a {
 		if ci[nd] == 0 {
 			nc++
-			df(NI(nd))
+			a.dfPre(NI(nd), v, visit)
 		}
 	}
 	return
 }
 
+// ConnectedComponentIntsMasked returns component numbers for the subgraph
+// of g induced by the nodes set in mask.
+//
+// The result is that of ConnectedComponentInts on the induced subgraph, but
+// indexed by node numbers of g and without constructing the subgraph.
+// Nodes not set in mask are hidden, edges to them are ignored, and their
+// component number in ci is 0.  Components are numbered 1 through nc in order
+// of their lowest node.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledUndirected) ConnectedComponentIntsMasked(mask bits.Bits) (ci []int, nc int) {
+	a := g.LabeledAdjacencyList
+	ci = make([]int, len(a))
+	// hidden nodes are marked as visited so traversals do not reach them
+	v := bits.New(len(a))
+	v.Not(mask)
+	visit := func(nd NI) { ci[nd] = nc }
+	mask.IterateOnes(func(nd int) bool {
+		if ci[nd] == 0 {
+			nc++
+			a.dfPre(NI(nd), v, visit)
+		}
+		return true
+	})
+	return
+}
+
 // ConnectedComponentLists returns a function that iterates over connected
 // components of g, returning the member list of each.
 //
@@ -481,24 +506,52 @@ func (g LabeledUndirected) ConnectedComponentLists() func() (nodes []NI, arcSize
 	vg := bits.New(len(a)) // nodes visited in graph
 	var l []NI             // accumulated node list of current component
 	var ma int             // accumulated arc size of current component
-	var df func(NI)
-	df = func(n NI) {
-		vg.SetBit(int(n), 1)
+	visit := func(n NI) {
 		l = append(l, n)
 		ma += len(a[n])
-		for _, nb := range a[n] {
-			if vg.Bit(int(nb.To)) == 0 {
-				df(nb.To)
+	}
+	var n int
+	return func() ([]NI, int) {
+		for ; n < len(a); n++ {
+			if vg.Bit(n) == 0 {
+				l, ma = nil, 0
+				a.dfPre(NI(n), vg, visit)
+				return l, ma
+			}
+		}
+		return nil, 0
+	}
+}
+
+// ConnectedComponentListsMasked returns a function that iterates over
+// connected components of the subgraph of g induced by the nodes set in mask.
+//
+// The result is that of ConnectedComponentLists on the induced subgraph, but
+// with node numbers of g and without constructing the subgraph.  Nodes not
+// set in mask are hidden and arcs to them are ignored and not counted in
+// arc sizes.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledUndirected) ConnectedComponentListsMasked(mask bits.Bits) func() (nodes []NI, arcSize int) {
+	a := g.LabeledAdjacencyList
+	vg := bits.New(len(a)) // nodes visited in graph, or hidden
+	vg.Not(mask)
+	var l []NI // accumulated node list of current component
+	var ma int // accumulated arc size of current component
+	visit := func(n NI) {
+		l = append(l, n)
+		for _, to := range a[n] {
+			if mask.Bit(int(to.To)) == 1 {
+				ma++
 			}
 		}
-		return
 	}
 	var n int
 	return func() ([]NI, int) {
 		for ; n < len(a); n++ {
 			if vg.Bit(n) == 0 {
 				l, ma = nil, 0
-				df(NI(n))
+				a.dfPre(NI(n), vg, visit)
 				return l, ma
 			}
 		}
@@ -526,22 +579,14 @@ func (g LabeledUndirected) ConnectedComponentReps() (reps []NI, orders, arcSizes
 	a := g.LabeledAdjacencyList
 	c := bits.New(len(a))
 	var o, ma int
-	var df func(NI)
-	df = func(n NI) {
-		c.SetBit(int(n), 1)
+	visit := func(n NI) {
 		o++
 		ma += len(a[n])
-		for _, nb := range a[n] {
-			if c.Bit(int(nb.To)) == 0 {
-				df(nb.To)
-			}
-		}
-		return
 	}
 	for n := range a {
 		if c.Bit(n) == 0 {
 			o, ma = 0, 0
-			df(NI(n))
+			a.dfPre(NI(n), c, visit)
 			reps = append(reps, NI(n))
 			orders = append(orders, o)
 			arcSizes = append(arcSizes, ma)
@@ -728,6 +773,26 @@ func (g LabeledUndirected) Degree(n NI) int {
 	return d
 }
 
+// DegreeMasked returns the degree of node n in the subgraph of g induced by
+// the nodes set in mask.
+//
+// Edges to nodes not set in mask are not counted.  As with Degree, loops
+// count twice.  Node n itself need not be set in mask.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledUndirected) DegreeMasked(n NI, mask bits.Bits) int {
+	d := 0
+	for _, to := range g.LabeledAdjacencyList[n] {
+		if mask.Bit(int(to.To)) == 1 {
+			d++
+			if to.To == n {
+				d++
+			}
+		}
+	}
+	return d
+}
+
 // DegreeCentralization returns the degree centralization metric of a graph.
 //
 // Degree of a node is one measure of node centrality and is directly
@@ -764,6 +829,26 @@ func (g LabeledUndirected) Density() float64 {
 	return Density(g.Order(), g.Size())
 }
 
+// DisjointSets returns a DisjointSets with the nodes of each connected
+// component of g in a set.
+//
+// Further edges can be added with Union and connectivity queried with
+// Connected without recomputing components.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+//
+// See also ConnectedComponentInts.
+func (g LabeledUndirected) DisjointSets() DisjointSets {
+	a := g.LabeledAdjacencyList
+	s := NewDisjointSets(len(a))
+	for fr, to := range a {
+		for _, to := range to {
+			s.ds.union(NI(fr), to.To)
+		}
+	}
+	return s
+}
+
 // Eulerian scans an undirected graph to determine if it is Eulerian.
 //
 // If the graph represents an Eulerian cycle, it returns -1, -1, nil.
@@ -911,7 +996,9 @@ func (g LabeledUndirected) EulerianPathD(m int, start NI) ([]Half, error) {
 	e.keep()
 	for e.s >= 0 {
 		start = e.top().To
-		e.push()
+		if err := e.pushUndir(); err != nil {
+			return nil, err
+		}
 		// paths after the first must be cycles though
 		// (as long as there are nodes on the stack)
 		if e.top().To != start {
@@ -1015,6 +1102,44 @@ func (g *LabeledUndirected) InduceBits(t bits.Bits) *LabeledUndirectedSubgraph {
 		}}
 }
 
+// LiftFromList translates a FromList of subgraph s to its supergraph.
+//
+// The result has a PathEnd for each node of s.Super.  Paths of f are placed
+// at the corresponding supergraph NIs, with From values also translated.
+// Supergraph nodes not in s get From -1 and Len 0.  Leaves, if set in f,
+// are translated as with LiftBits.  MaxLen is copied.
+func (s *LabeledUndirectedSubgraph) LiftFromList(f FromList) FromList {
+	return liftFromList(s.SuperNI, s.Super.Order(), f)
+}
+
+// LiftDist translates a slice of per-node values of subgraph s, such as
+// a distance result, to its supergraph.
+//
+// The result has a value for each node of s.Super.  Supergraph nodes not in
+// s are given the value fill.
+func (s *LabeledUndirectedSubgraph) LiftDist(d []float64, fill float64) []float64 {
+	return liftDist(s.SuperNI, s.Super.Order(), d, fill)
+}
+
+// LiftNodes translates a list of subgraph NIs to supergraph NIs.
+func (s *LabeledUndirectedSubgraph) LiftNodes(l []NI) []NI {
+	return liftNodes(s.SuperNI, l)
+}
+
+// LiftBits translates a bitmap of subgraph nodes to a bitmap of supergraph
+// nodes.
+func (s *LabeledUndirectedSubgraph) LiftBits(b bits.Bits) bits.Bits {
+	return liftBits(s.SuperNI, s.Super.Order(), b)
+}
+
+// PushBits translates a bitmap of supergraph nodes to a bitmap of subgraph
+// nodes.
+//
+// Supergraph nodes not in s are ignored.
+func (s *LabeledUndirectedSubgraph) PushBits(super bits.Bits) bits.Bits {
+	return pushBits(s.SuperNI, super)
+}
+
 // IsConnected tests if an undirected graph is a single connected component.
 //
 // There are equivalent labeled and unlabeled versions of this method.
@@ -1026,16 +1151,7 @@ func (g LabeledUndirected) IsConnected() bool {
 		return true
 	}
 	b := bits.New(len(a))
-	var df func(NI)
-	df = func(n NI) {
-		b.SetBit(int(n), 1)
-		for _, to := range a[n] {
-			if b.Bit(int(to.To)) == 0 {
-				df(to.To)
-			}
-		}
-	}
-	df(0)
+	a.dfPre(0, b, func(NI) {})
 	return b.AllOnes()
 }
 
@@ -1050,24 +1166,28 @@ func (g LabeledUndirected) IsTree(root NI) (isTree, allTree bool) {
 	a := g.LabeledAdjacencyList
 	v := bits.New(len(a))
 	v.SetAll()
-	var df func(NI, NI) bool
-	df = func(fr, n NI) bool {
-		if v.Bit(int(n)) == 0 {
-			return false
-		}
-		v.SetBit(int(n), 0)
-		for _, to := range a[n] {
-			if to.To != fr && !df(n, to.To) {
-				return false
-			}
-		}
-		return true
+	type frame struct {
+		fr, n NI  // arc fr->n leading to n, fr = -1 for root
+		x     int // index of next arc from n
 	}
 	v.SetBit(int(root), 0)
-	for _, to := range a[root] {
-		if !df(root, to.To) {
+	stack := []frame{{-1, root, 0}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.x == len(a[top.n]) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		to := a[top.n][top.x].To
+		top.x++
+		if to == top.fr {
+			continue
+		}
+		if v.Bit(int(to)) == 0 {
 			return false, false
 		}
+		v.SetBit(int(to), 0)
+		stack = append(stack, frame{top.n, to, 0})
 	}
 	return true, v.AllZeros()
 }
@@ -1088,6 +1208,58 @@ func (g LabeledUndirected) Size() int {
 	return m2 / 2
 }
 
+// Summarize computes overview statistics of g.
+//
+// All statistics are computed in a single pass over the arcs of g.
+// See UndirectedSummary.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledUndirected) Summarize() UndirectedSummary {
+	return g.summarize(func() {})
+}
+
+// summarize implements Summarize, calling pass at the start of each pass
+// over the arcs of g.
+func (g LabeledUndirected) summarize(pass func()) (s UndirectedSummary) {
+	a := g.LabeledAdjacencyList
+	s.Order = len(a)
+	s.Simple = true
+	if len(a) == 0 {
+		return
+	}
+	mark := make([]NI, len(a)) // value fr+1 marks nodes with arcs from fr
+	ds := newDisjointSet(len(a))
+	m2 := 0
+	s.MinDegree = -1
+	pass()
+	for fr, to := range a {
+		d := len(to)
+		for _, to := range to {
+			if to.To == NI(fr) {
+				s.Loops++
+				d++
+			}
+			if mark[to.To] == NI(fr)+1 {
+				s.Parallel = true
+			}
+			mark[to.To] = NI(fr) + 1
+			ds.union(NI(fr), to.To)
+		}
+		m2 += d
+		if s.MinDegree < 0 || d < s.MinDegree {
+			s.MinDegree = d
+		}
+		if d > s.MaxDegree {
+			s.MaxDegree = d
+		}
+	}
+	s.Size = m2 / 2
+	s.Simple = s.Loops == 0 && !s.Parallel
+	s.MeanDegree = float64(m2) / float64(len(a))
+	s.Components, s.LargestComponent = summaryComponents(ds)
+	return
+}
+
 // Density returns edge density of a bipartite graph.
 //
 // Edge density is number of edges over maximum possible number of edges.
@@ -85,6 +85,22 @@ func ExampleLabeledUndirected_BipartiteComponent_oddCycle() {
 	// odd cycle: [3 4 2]
 }
 
+func ExampleLabeledUndirected_BipartiteComponent_loop() {
+	// 0--1--
+	//     \_/
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 0)
+	g.AddEdge(graph.Edge{1, 1}, 0)
+	c1 := bits.New(g.Order())
+	c2 := bits.New(g.Order())
+	b, _, _, oc := g.BipartiteComponent(0, c1, c2)
+	if !b {
+		fmt.Println("odd cycle:", oc)
+	}
+	// Output:
+	// odd cycle: [1 1]
+}
+
 func ExampleLabeledUndirected_BronKerbosch1() {
 	// 0--4--5-
 	//    |  | \
@@ -169,16 +185,19 @@ func ExampleLabeledUndirected_BronKerbosch2() {
 	g.AddEdge(graph.Edge{5, 2}, 0)
 	g.AddEdge(graph.Edge{5, 1}, 0)
 	g.AddEdge(graph.Edge{2, 1}, 0)
-	g.BronKerbosch2(g.BKPivotMaxDegree, func(c bits.Bits) bool {
+	// sorted for output comparable with other variants
+	graph.SortCliques(func(emit func(bits.Bits) bool) {
+		g.BronKerbosch2(g.BKPivotMaxDegree, emit)
+	}, func(c bits.Bits) bool {
 		fmt.Println(c.Slice())
 		return true
 	})
 	// Output:
 	// [0 4]
 	// [2 3]
-	// [1 2 5]
 	// [3 4]
 	// [4 5]
+	// [1 2 5]
 }
 
 func ExampleLabeledUndirected_BronKerbosch3() {
@@ -193,16 +212,19 @@ func ExampleLabeledUndirected_BronKerbosch3() {
 	g.AddEdge(graph.Edge{5, 2}, 0)
 	g.AddEdge(graph.Edge{5, 1}, 0)
 	g.AddEdge(graph.Edge{2, 1}, 0)
-	g.BronKerbosch3(g.BKPivotMaxDegree, func(c bits.Bits) bool {
+	// sorted for output comparable with other variants
+	graph.SortCliques(func(emit func(bits.Bits) bool) {
+		g.BronKerbosch3(g.BKPivotMaxDegree, emit)
+	}, func(c bits.Bits) bool {
 		fmt.Println(c.Slice())
 		return true
 	})
 	// Output:
-	// [1 2 5]
-	// [4 5]
+	// [0 4]
 	// [2 3]
 	// [3 4]
-	// [0 4]
+	// [4 5]
+	// [1 2 5]
 }
 
 func ExampleLabeledUndirected_ConnectedComponentBits() {
@@ -88,6 +88,22 @@ func ExampleUndirected_BipartiteComponent_oddCycle() {
 	// odd cycle: [3 4 2]
 }
 
+func ExampleUndirected_BipartiteComponent_loop() {
+	// 0--1--
+	//     \_/
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 1)
+	c1 := bits.New(g.Order())
+	c2 := bits.New(g.Order())
+	b, _, _, oc := g.BipartiteComponent(0, c1, c2)
+	if !b {
+		fmt.Println("odd cycle:", oc)
+	}
+	// Output:
+	// odd cycle: [1 1]
+}
+
 func ExampleUndirected_BronKerbosch1() {
 	// 0--4--5-
 	//    |  | \
@@ -172,16 +188,19 @@ func ExampleUndirected_BronKerbosch2() {
 	g.AddEdge(5, 2)
 	g.AddEdge(5, 1)
 	g.AddEdge(2, 1)
-	g.BronKerbosch2(g.BKPivotMaxDegree, func(c bits.Bits) bool {
+	// sorted for output comparable with other variants
+	graph.SortCliques(func(emit func(bits.Bits) bool) {
+		g.BronKerbosch2(g.BKPivotMaxDegree, emit)
+	}, func(c bits.Bits) bool {
 		fmt.Println(c.Slice())
 		return true
 	})
 	// Output:
 	// [0 4]
 	// [2 3]
-	// [1 2 5]
 	// [3 4]
 	// [4 5]
+	// [1 2 5]
 }
 
 func ExampleUndirected_BronKerbosch3() {
@@ -196,16 +215,19 @@ func ExampleUndirected_BronKerbosch3() {
 	g.AddEdge(5, 2)
 	g.AddEdge(5, 1)
 	g.AddEdge(2, 1)
-	g.BronKerbosch3(g.BKPivotMaxDegree, func(c bits.Bits) bool {
+	// sorted for output comparable with other variants
+	graph.SortCliques(func(emit func(bits.Bits) bool) {
+		g.BronKerbosch3(g.BKPivotMaxDegree, emit)
+	}, func(c bits.Bits) bool {
 		fmt.Println(c.Slice())
 		return true
 	})
 	// Output:
-	// [1 2 5]
-	// [4 5]
+	// [0 4]
 	// [2 3]
 	// [3 4]
-	// [0 4]
+	// [4 5]
+	// [1 2 5]
 }
 
 func ExampleUndirected_ConnectedComponentBits() {
@@ -731,6 +753,29 @@ func ExampleUndirected_Size() {
 	// (Arc size = 3)
 }
 
+func ExampleUndirected_Summarize() {
+	//   0--\   3
+	//  / \-/   ||
+	// 1---2    4
+	var g graph.Undirected
+	g.AddEdge(0, 0)
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 0)
+	g.AddEdge(3, 4)
+	g.AddEdge(3, 4)
+	fmt.Print(g.Summarize())
+	// Output:
+	// order:               5
+	// size:                6
+	// loops:               1
+	// parallel edges:      true
+	// simple:              false
+	// components:          2
+	// largest component:   3
+	// degree:              min 2, mean 2.40, max 4
+}
+
 func ExampleUndirectedSubgraph_AddNode() {
 	// supergraph:
 	//    0
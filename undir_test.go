@@ -5,7 +5,13 @@ package graph_test
 
 import (
 	"fmt"
+	"log"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
 
+	"github.com/soniakeys/bits"
 	"github.com/soniakeys/graph"
 )
 
@@ -59,6 +65,123 @@ func ExampleUndirected_AddEdge_more() {
 	// 2 [1 0 0 2]
 }
 
+func ExampleUndirected_BalancedSeparator() {
+	// 0---1---2---3---4---5---6
+	var g graph.Undirected
+	for n := graph.NI(1); n < 7; n++ {
+		g.AddEdge(n-1, n)
+	}
+	sep, ok := g.BalancedSeparator(.5)
+	fmt.Println(sep.Slice(), ok)
+	// Output:
+	// [3] true
+}
+
+// grid returns a k by k grid graph with node r*k+c at row r, column c.
+func grid(k int) (g graph.Undirected) {
+	for r := 0; r < k; r++ {
+		for c := 0; c < k; c++ {
+			n := graph.NI(r*k + c)
+			if c > 0 {
+				g.AddEdge(n-1, n)
+			}
+			if r > 0 {
+				g.AddEdge(n-graph.NI(k), n)
+			}
+		}
+	}
+	return
+}
+
+// separates returns true if no path leads from a to b in g with sep removed.
+func separates(g graph.Undirected, a, b, sep bits.Bits) bool {
+	v := bits.New(g.Order())
+	v.Set(sep)
+	ok := true
+	var df func(graph.NI)
+	df = func(n graph.NI) {
+		v.SetBit(int(n), 1)
+		if b.Bit(int(n)) == 1 {
+			ok = false
+		}
+		for _, to := range g.AdjacencyList[n] {
+			if v.Bit(int(to)) == 0 {
+				df(to)
+			}
+		}
+	}
+	a.IterateOnes(func(n int) bool {
+		if v.Bit(n) == 0 {
+			df(graph.NI(n))
+		}
+		return ok
+	})
+	return ok
+}
+
+func TestSeparatorGrid(t *testing.T) {
+	const k = 12
+	g := grid(k)
+	a := bits.New(g.Order())
+	b := bits.New(g.Order())
+	for r := 0; r < k; r++ {
+		a.SetBit(r*k, 1)
+		b.SetBit(r*k+k-1, 1)
+	}
+	if _, ok := g.Separator(a, b, k-1); ok {
+		t.Fatal("separator smaller than", k, "reported")
+	}
+	sep, ok := g.Separator(a, b, k)
+	if !ok {
+		t.Fatal("no separator found")
+	}
+	if n := sep.OnesCount(); n != k {
+		t.Fatal("separator size", n)
+	}
+	if !separates(g, a, b, sep) {
+		t.Fatal("separator fails to separate")
+	}
+	sep, ok = g.BalancedSeparator(.75)
+	if !ok {
+		t.Fatal("BalancedSeparator not ok")
+	}
+	if n := sep.OnesCount(); n > 2*k {
+		t.Fatal("balanced separator size", n)
+	}
+}
+
+func TestSeparatorSmallCut(t *testing.T) {
+	// two 5-cliques, 0-4 and 6-10, joined only through node 5
+	var g graph.Undirected
+	for _, base := range []graph.NI{0, 6} {
+		for i := graph.NI(0); i < 5; i++ {
+			for j := i + 1; j < 5; j++ {
+				g.AddEdge(base+i, base+j)
+			}
+		}
+	}
+	g.AddEdge(3, 5)
+	g.AddEdge(4, 5)
+	g.AddEdge(5, 6)
+	g.AddEdge(5, 7)
+	a := bits.New(g.Order())
+	b := bits.New(g.Order())
+	a.SetBit(0, 1)
+	b.SetBit(10, 1)
+	sep, ok := g.Separator(a, b, 3)
+	if !ok || sep.Slice()[0] != 5 || sep.OnesCount() != 1 {
+		t.Fatal("separator:", sep.Slice(), ok)
+	}
+	if !separates(g, a, b, sep) {
+		t.Fatal("separator fails to separate")
+	}
+	// adjacent a and b cannot be separated
+	b.SetBit(1, 1)
+	if _, ok := g.Separator(a, b, 10); ok {
+		t.Fatal("separator reported for adjacent sets")
+	}
+}
+
 func ExampleUndirected_Edges() {
 	//    0
 	//   / \\
@@ -128,6 +251,29 @@ func ExampleUndirected_RemoveEdge() {
 	// 2 [1 0]
 }
 
+func ExampleUndirected_Separator() {
+	// 0   3
+	// |\ /|
+	// | 2 |
+	// |/ \|
+	// 1   4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(3, 4)
+	a := bits.New(g.Order())
+	b := bits.New(g.Order())
+	a.SetBit(0, 1)
+	b.SetBit(4, 1)
+	sep, ok := g.Separator(a, b, 2)
+	fmt.Println(sep.Slice(), ok)
+	// Output:
+	// [2] true
+}
+
 func ExampleUndirected_SimpleEdges() {
 	//    0
 	//   / \\
@@ -148,6 +294,75 @@ func ExampleUndirected_SimpleEdges() {
 	// {1 2}
 }
 
+func ExampleUndirected_FundamentalCycles() {
+	//   0--1--4
+	//   |  |  ||
+	//   3--2  5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 0)
+	g.AddEdge(1, 4)
+	g.AddEdge(4, 5)
+	g.AddEdge(4, 5)
+	g.AddEdge(5, 5)
+	g.FundamentalCycles(func(c []graph.NI) bool {
+		fmt.Println(c)
+		return true
+	})
+	// Output:
+	// [2 1 0 3]
+	// [4 5]
+	// [5]
+}
+
+// TestFundamentalCycles checks the number of cycles and that each is a
+// closed walk of g, on random multigraphs.
+func TestFundamentalCycles(t *testing.T) {
+	r := rand.New(rand.NewSource(37))
+	for i := 0; i < 30; i++ {
+		var g graph.LabeledUndirected
+		for e := 0; e < 30; e++ {
+			g.AddEdge(graph.Edge{graph.NI(r.Intn(20)), graph.NI(r.Intn(20))},
+				graph.LI(r.Intn(2)))
+		}
+		_, nc := g.ConnectedComponentInts()
+		want := g.Size() - g.Order() + nc
+		n := 0
+		g.FundamentalCycles(func(c []graph.Half) bool {
+			n++
+			fr := c[len(c)-1].To
+			for _, h := range c {
+				if ok, _, _ := g.HasEdgeLabel(fr, h.To, h.Label); !ok {
+					t.Fatal("no edge", fr, h)
+				}
+				fr = h.To
+			}
+			return true
+		})
+		if n != want {
+			t.Fatal("labeled cycles", n, "want", want)
+		}
+		n = 0
+		u := graph.Undirected{g.Unlabeled()}
+		u.FundamentalCycles(func(c []graph.NI) bool {
+			n++
+			fr := c[len(c)-1]
+			for _, to := range c {
+				if ok, _ := u.HasArc(fr, to); !ok {
+					t.Fatal("no edge", fr, to)
+				}
+				fr = to
+			}
+			return true
+		})
+		if n != want {
+			t.Fatal("cycles", n, "want", want)
+		}
+	}
+}
+
 func ExampleUndirected_FromList() {
 	//    0   5
 	//   / \   \
@@ -468,6 +683,160 @@ func ExampleLabeledUndirected_Edges() {
 	// {2 1} D
 }
 
+func ExampleLabeledUndirected_FilterEdges() {
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 10)
+	g.AddEdge(graph.Edge{1, 2}, 20)
+	g.AddEdge(graph.Edge{2, 2}, 5)
+	g.AddEdge(graph.Edge{2, 3}, 30)
+	f := g.FilterEdges(func(n1, n2 graph.NI, l graph.LI) bool {
+		fmt.Println("keep?", n1, n2, l)
+		return l < 25
+	})
+	for fr, to := range f.LabeledAdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// keep? 0 1 10
+	// keep? 1 2 20
+	// keep? 2 2 5
+	// keep? 2 3 30
+	// 0 [{1 10}]
+	// 1 [{0 10} {2 20}]
+	// 2 [{1 20} {2 5}]
+	// 3 []
+}
+
+// TestFilterEdges checks that an asymmetric predicate is called once per
+// edge and leaves the result undirected.
+func TestFilterEdges(t *testing.T) {
+	r := rand.New(rand.NewSource(17))
+	for i := 0; i < 20; i++ {
+		var g graph.LabeledUndirected
+		for e := 0; e < 40; e++ {
+			g.AddEdge(graph.Edge{graph.NI(r.Intn(10)), graph.NI(r.Intn(10))},
+				graph.LI(r.Intn(3)))
+		}
+		calls := 0
+		f := g.FilterEdges(func(n1, n2 graph.NI, l graph.LI) bool {
+			if n1 > n2 {
+				t.Fatal("not canonical", n1, n2)
+			}
+			calls++
+			return r.Intn(2) == 0
+		})
+		if calls != g.Size() {
+			t.Fatal("calls", calls, "edges", g.Size())
+		}
+		if f.Order() != g.Order() {
+			t.Fatal("order", f.Order())
+		}
+		if u, fr, to := f.IsUndirected(); !u {
+			t.Fatal("not undirected", fr, to)
+		}
+	}
+}
+
+func ExampleLabeledUndirected_EulerianPathOrdered() {
+	//    0
+	//  a/|\
+	//  /b| \
+	//  \ | /c
+	//   \|/
+	//    1
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 'a')
+	g.AddEdge(graph.Edge{0, 1}, 'b')
+	g.AddEdge(graph.Edge{0, 1}, 'c')
+	c, err := g.EulerianPathOrdered(func(a, b graph.Half) bool {
+		return a.Label < b.Label
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Print(c[0].To)
+	for _, to := range c[1:] {
+		fmt.Printf(" --%c-- %d", to.Label, to.To)
+	}
+	fmt.Println()
+	// Output:
+	// 0 --a-- 1 --b-- 0 --c-- 1
+}
+
+func TestLabeledUndirected_EulerianOrdered(t *testing.T) {
+	r := rand.New(rand.NewSource(67))
+	less := func(a, b graph.Half) bool { return a.Label < b.Label }
+	for i := 0; i < 200; i++ {
+		// random walk visiting all nodes, closed for a cycle or with a
+		// random tail for a path.
+		n := 1 + r.Intn(8)
+		walk := []graph.NI{0}
+		for _, n := range r.Perm(n - 1) {
+			walk = append(walk, graph.NI(n+1))
+		}
+		for j := r.Intn(2 * n); j > 0; j-- {
+			walk = append(walk, graph.NI(r.Intn(n)))
+		}
+		walk = append(walk, 0)
+		tail := r.Intn(3)
+		for j := 0; j < tail; j++ {
+			walk = append(walk, graph.NI(r.Intn(n)))
+		}
+		m := len(walk) - 1
+		lab := r.Perm(m)
+		g := graph.LabeledUndirected{make(graph.LabeledAdjacencyList, n)}
+		for j, fr := range walk[:m] {
+			g.AddEdge(graph.Edge{fr, walk[j+1]}, graph.LI(lab[j]))
+		}
+		c, _ := g.Copy()
+		var p []graph.Half
+		var err error
+		if tail == 0 {
+			p, err = g.EulerianCycleOrdered(less)
+		} else {
+			p, err = g.EulerianPathOrdered(less)
+		}
+		if err != nil {
+			t.Fatal(g, err)
+		}
+		if err := checkEulerian(g.LabeledAdjacencyList, p, m, tail == 0); err != nil {
+			t.Fatal(g, p, err)
+		}
+		if !reflect.DeepEqual(c, g) {
+			t.Fatal("receiver modified")
+		}
+		// result must not depend on arc list order
+		for _, to := range c.LabeledAdjacencyList {
+			r.Shuffle(len(to), func(i, j int) { to[i], to[j] = to[j], to[i] })
+		}
+		var q []graph.Half
+		if tail == 0 {
+			q, _ = c.EulerianCycleOrdered(less)
+		} else {
+			q, _ = c.EulerianPathOrdered(less)
+		}
+		if !reflect.DeepEqual(p, q) {
+			t.Fatal(g, p, q, "result depends on arc order")
+		}
+	}
+}
+
+func TestLabeledUndirected_EulerianPath(t *testing.T) {
+	// a path with a cycle to splice in after the first path.
+	// EulerianPath once panicked on this graph.
+	var g graph.LabeledUndirected
+	for i, e := range []graph.Edge{{0, 1}, {1, 2}, {2, 3}, {3, 1}, {1, 4}} {
+		g.AddEdge(e, graph.LI(i))
+	}
+	p, err := g.EulerianPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkEulerian(g.LabeledAdjacencyList, p, 5, false); err != nil {
+		t.Fatal(p, err)
+	}
+}
+
 func ExampleLabeledUndirected_HasEdge() {
 	var g graph.LabeledUndirected
 	g.AddEdge(graph.Edge{7, 8}, 'A')
@@ -504,6 +873,21 @@ func ExampleLabeledUndirected_HasEdgeLabel() {
 	// true true
 }
 
+func ExampleLabeledUndirected_FundamentalCycles() {
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 1)
+	g.AddEdge(graph.Edge{1, 2}, 2)
+	g.AddEdge(graph.Edge{2, 0}, 3)
+	g.AddEdge(graph.Edge{0, 1}, 4)
+	g.FundamentalCycles(func(c []graph.Half) bool {
+		fmt.Println(c)
+		return true
+	})
+	// Output:
+	// [{1 1} {0 4}]
+	// [{0 1} {2 3} {1 2}]
+}
+
 func ExampleLabeledUndirected_FromList() {
 	//      0
 	// 'A' / \ 'B'
@@ -717,3 +1101,138 @@ func ExampleBiconnectedComponents_Find() {
 	// Leaves: [4 11 9]
 }
 */
+
+// masked methods must give the same results as the same methods on the
+// subgraph constructed by InduceBits.
+func TestMaskedEquivalence(t *testing.T) {
+	r := rand.New(rand.NewSource(59))
+	for trial := 0; trial < 20; trial++ {
+		g := graph.GnmUndirected(40, 50, r)
+		mask := bits.New(40)
+		for n := 0; n < 40; n++ {
+			if r.Intn(3) > 0 {
+				mask.SetBit(n, 1)
+			}
+		}
+		s := g.InduceBits(mask)
+		sup := s.SuperNI
+		// components
+		ci, nc := g.ConnectedComponentIntsMasked(mask)
+		sci, snc := s.ConnectedComponentInts()
+		if nc != snc {
+			t.Fatal("nc", nc, "want", snc)
+		}
+		for sn, c := range sci {
+			if ci[sup[sn]] != c {
+				t.Fatal("ci", ci, "want", sci, "mapped by", sup)
+			}
+		}
+		for n, c := range ci {
+			if c != 0 && mask.Bit(n) == 0 || c == 0 && mask.Bit(n) == 1 {
+				t.Fatal("ci", ci, "mask", mask)
+			}
+		}
+		cl := g.ConnectedComponentListsMasked(mask)
+		scl := s.ConnectedComponentLists()
+		for {
+			l, ma := cl()
+			sl, sma := scl()
+			if len(l) != len(sl) || ma != sma {
+				t.Fatal("component", l, ma, "want", sl, sma)
+			}
+			if l == nil {
+				break
+			}
+			for i, sn := range sl {
+				if l[i] != sup[sn] {
+					t.Fatal("component", l, "want", sl)
+				}
+			}
+		}
+		// degrees
+		for sn, n := range sup {
+			if d, sd := g.DegreeMasked(n, mask), s.Degree(graph.NI(sn)); d != sd {
+				t.Fatal("degree", n, d, "want", sd)
+			}
+		}
+		// traversals
+		start := sup[0]
+		var got, want []graph.NI
+		trav := func(n graph.NI) { got = append(got, n) }
+		strav := func(n graph.NI) { want = append(want, sup[n]) }
+		g.DepthFirstMasked(start, mask, graph.NodeVisitor(trav))
+		s.DepthFirst(0, strav)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatal("DepthFirst", got, "want", want)
+		}
+		got, want = nil, nil
+		var gf, sf graph.FromList
+		g.BreadthFirstMasked(start, mask, graph.NodeVisitor(trav),
+			graph.From(&gf))
+		s.BreadthFirstTraverse(0, graph.NodeVisitor(strav), graph.From(&sf))
+		if !reflect.DeepEqual(got, want) {
+			t.Fatal("BreadthFirst", got, "want", want)
+		}
+		if gf.MaxLen != sf.MaxLen {
+			t.Fatal("BreadthFirst MaxLen", gf.MaxLen, "want", sf.MaxLen)
+		}
+		for sn, p := range sf.Paths {
+			if gp := gf.Paths[sup[sn]]; gp.Len != p.Len {
+				t.Fatal("BreadthFirst path length", sup[sn], gp, "want", p)
+			}
+		}
+		// cliques
+		var cl1, cl2 []string
+		g.BronKerbosch2Masked(mask, g.BKPivotMaxDegree, func(c bits.Bits) bool {
+			cl1 = append(cl1, c.String())
+			return true
+		})
+		s.BronKerbosch2(s.BKPivotMaxDegree, func(c bits.Bits) bool {
+			m := bits.New(40)
+			c.IterateOnes(func(sn int) bool {
+				m.SetBit(int(sup[sn]), 1)
+				return true
+			})
+			cl2 = append(cl2, m.String())
+			return true
+		})
+		sort.Strings(cl1)
+		sort.Strings(cl2)
+		if !reflect.DeepEqual(cl1, cl2) {
+			t.Fatal("cliques", cl1, "want", cl2)
+		}
+	}
+}
+
+// k-core peeling, repeatedly removing nodes of degree less than k.
+func benchmarkPeel(b *testing.B, masked bool) {
+	g := graph.GnmUndirected(2000, 6000, rand.New(rand.NewSource(3)))
+	const k = 6
+	for i := 0; i < b.N; i++ {
+		mask := bits.New(g.Order())
+		mask.SetAll()
+		for changed := true; changed; {
+			changed = false
+			if masked {
+				mask.IterateOnes(func(n int) bool {
+					if g.DegreeMasked(graph.NI(n), mask) < k {
+						mask.SetBit(n, 0)
+						changed = true
+					}
+					return true
+				})
+				continue
+			}
+			s := g.InduceBits(mask)
+			for sn, n := range s.SuperNI {
+				if s.Degree(graph.NI(sn)) < k {
+					mask.SetBit(int(n), 0)
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkPeelMasked(b *testing.B)  { benchmarkPeel(b, true) }
+func BenchmarkPeelInduced(b *testing.B) { benchmarkPeel(b, false) }
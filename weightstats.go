@@ -0,0 +1,172 @@
+// Copyright 2018 Sonia Keys
+// License MIT: https://opensource.org/licenses/MIT
+
+package graph
+
+// weightstats.go -- summary statistics of arc weights.
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// WeightStats holds summary statistics of arc weights.
+//
+// Count, Min, Max, and Mean are always exact.  Percentiles are computed from
+// a sorted list of weights that is either all weights, or for WeightSample,
+// a random sample of them.  See Exact.
+type WeightStats struct {
+	Count          int
+	Min, Max, Mean float64
+	sorted         []float64
+}
+
+// WeightStats computes summary statistics of the weights of all arcs of g.
+//
+// All weights are gathered and sorted so percentiles are exact.  Memory is
+// O(a) for a arcs.  For very large graphs see WeightSample.
+func (g LabeledAdjacencyList) WeightStats(w WeightFunc) WeightStats {
+	var all []float64
+	for _, to := range g {
+		for _, to := range to {
+			all = append(all, w(to.Label))
+		}
+	}
+	return newWeightStats(all)
+}
+
+// WeightSample computes summary statistics of the weights of arcs of g,
+// with percentiles approximated from a random sample.
+//
+// Count, Min, Max, and Mean are exact.  Percentiles are computed from a
+// uniform random sample of at most size weights, kept in a reservoir as the
+// arcs are scanned.  Memory is thus O(size) regardless of the size of g.
+// The approximation is in rank:  for a sample of size k, the fraction of
+// weights below a computed p-th percentile has a standard deviation of about
+// sqrt(q(1-q)/k) where q = p/100, or at most .5/sqrt(k).  A sample size of
+// 10000 for example gives percentiles typically within half a percentile
+// of the exact ones.
+//
+// If all weights fit in the sample, the result is the same as that of
+// WeightStats.  If Rand rr is nil, the rand package default shared source
+// is used.
+func (g LabeledAdjacencyList) WeightSample(w WeightFunc, size int, rr *rand.Rand) WeightStats {
+	ri := rand.Int63n
+	if rr != nil {
+		ri = rr.Int63n
+	}
+	var res []float64
+	s := WeightStats{Min: math.Inf(1), Max: math.Inf(-1)}
+	sum := 0.
+	for _, to := range g {
+		for _, to := range to {
+			x := w(to.Label)
+			sum += x
+			if x < s.Min {
+				s.Min = x
+			}
+			if x > s.Max {
+				s.Max = x
+			}
+			// Vitter's algorithm R
+			if s.Count < size {
+				res = append(res, x)
+			} else if j := ri(int64(s.Count) + 1); j < int64(size) {
+				res[j] = x
+			}
+			s.Count++
+		}
+	}
+	if s.Count == 0 {
+		return WeightStats{}
+	}
+	s.Mean = sum / float64(s.Count)
+	sort.Float64s(res)
+	s.sorted = res
+	return s
+}
+
+func newWeightStats(all []float64) WeightStats {
+	if len(all) == 0 {
+		return WeightStats{}
+	}
+	sort.Float64s(all)
+	sum := 0.
+	for _, x := range all {
+		sum += x
+	}
+	return WeightStats{
+		Count:  len(all),
+		Min:    all[0],
+		Max:    all[len(all)-1],
+		Mean:   sum / float64(len(all)),
+		sorted: all,
+	}
+}
+
+// Exact returns true if percentiles are computed from all weights, false if
+// they are computed from a sample.
+func (s WeightStats) Exact() bool {
+	return len(s.sorted) == s.Count
+}
+
+// Percentile returns the p-th percentile of weights, for p from 0 to 100.
+//
+// The value is interpolated linearly between the closest ranks.  Percentile
+// 0 is Min, 100 is Max, and 50 is the median.  The result is NaN if there
+// are no weights.
+func (s WeightStats) Percentile(p float64) float64 {
+	a := s.sorted
+	if len(a) == 0 {
+		return math.NaN()
+	}
+	switch {
+	case p <= 0:
+		return s.Min
+	case p >= 100:
+		return s.Max
+	}
+	h := p / 100 * float64(len(a)-1)
+	i := int(h)
+	if i+1 >= len(a) {
+		return a[len(a)-1]
+	}
+	return a[i] + (h-float64(i))*(a[i+1]-a[i])
+}
+
+// Quantiles returns bins+1 values dividing the weights into bins of equal
+// frequency.
+//
+// The first value is Min and the last is Max.  Values are non-decreasing.
+func (s WeightStats) Quantiles(bins int) []float64 {
+	q := make([]float64, bins+1)
+	for i := range q {
+		q[i] = s.Percentile(100 * float64(i) / float64(bins))
+	}
+	return q
+}
+
+// Quantize assigns arc weights to bins of approximately equal frequency.
+//
+// Returned function binOf returns the bin, from 0 through bins-1, of the arc
+// with label l.  Bin assignment is monotone in weight; that is, an arc with
+// a greater weight is never assigned a lesser bin.  Returned edges has
+// bins+1 values as returned by WeightStats.Quantiles.  An arc is assigned bin
+// b where edges[b] <= w(l) < edges[b+1], except that a weight equal to Max
+// is assigned the last bin.  A bins value less than 1 is taken as 1.
+//
+// With many equal weights, some bins may be empty.  binOf may be useful
+// for example for styling arcs in graph output, as with dot.EdgeAttr.
+func (g LabeledAdjacencyList) Quantize(w WeightFunc, bins int) (binOf func(LI) int, edges []float64) {
+	if bins < 1 {
+		bins = 1
+	}
+	edges = g.WeightStats(w).Quantiles(bins)
+	inner := edges[1:bins]
+	binOf = func(l LI) int {
+		x := w(l)
+		return sort.Search(len(inner), func(i int) bool { return inner[i] > x })
+	}
+	return
+}
@@ -0,0 +1,154 @@
+// Copyright 2018 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledAdjacencyList_WeightStats() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}, {To: 2, Label: 1}},
+		1: {{To: 2, Label: 2}},
+		2: {{To: 0, Label: 3}, {To: 3, Label: 4}},
+	}
+	wt := []float64{4, 1, 3, 10, 2}
+	s := g.WeightStats(func(l graph.LI) float64 { return wt[l] })
+	fmt.Println(s.Count, s.Min, s.Max, s.Mean)
+	fmt.Printf("%.3g %.3g %.3g\n",
+		s.Percentile(50), s.Percentile(25), s.Percentile(90))
+	fmt.Println(s.Quantiles(2))
+	// Output:
+	// 5 1 10 4
+	// 3 2 7.6
+	// [1 3 10]
+}
+
+func ExampleLabeledAdjacencyList_Quantize() {
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 0}, {To: 2, Label: 1}, {To: 3, Label: 2}},
+		1: {{To: 2, Label: 3}, {To: 3, Label: 4}},
+		2: {{To: 3, Label: 5}},
+	}
+	wt := []float64{.5, 100, 3, 2, 40, 8}
+	binOf, edges := g.Quantize(func(l graph.LI) float64 { return wt[l] }, 3)
+	fmt.Printf("%.4g\n", edges)
+	for l, w := range wt {
+		fmt.Println(w, binOf(graph.LI(l)))
+	}
+	// Output:
+	// [0.5 2.667 18.67 100]
+	// 0.5 0
+	// 100 2
+	// 3 1
+	// 2 0
+	// 40 2
+	// 8 1
+}
+
+// labeled star graph with n arcs, labels 0 through n-1.
+func weightStar(n int) graph.LabeledAdjacencyList {
+	g := graph.LabeledAdjacencyList{0: make([]graph.Half, n)}
+	for i := range g[0] {
+		g[0][i] = graph.Half{To: 0, Label: graph.LI(i)}
+	}
+	return g
+}
+
+func TestWeightStats(t *testing.T) {
+	rnd := rand.New(rand.NewSource(59))
+	const n = 100000
+	dists := []struct {
+		name string
+		wt   []float64
+		cdf  func(float64) float64 // inverse of cdf, that is, quantile
+	}{
+		{"uniform", make([]float64, n),
+			func(q float64) float64 { return q }},
+		{"exponential", make([]float64, n),
+			func(q float64) float64 { return -math.Log(1 - q) }},
+		{"pareto", make([]float64, n), // heavily skewed
+			func(q float64) float64 { return math.Pow(1-q, -1/1.2) }},
+	}
+	for i := range dists[0].wt {
+		q := rnd.Float64()
+		for _, d := range dists {
+			d.wt[i] = d.cdf(q)
+		}
+	}
+	g := weightStar(n)
+	for _, d := range dists {
+		wt := d.wt
+		w := func(l graph.LI) float64 { return wt[l] }
+		exact := g.WeightStats(w)
+		if !exact.Exact() || exact.Count != n {
+			t.Fatal(d.name, exact.Count)
+		}
+		sample := g.WeightSample(w, 10000, rnd)
+		if sample.Exact() || sample.Count != n || sample.Min != exact.Min ||
+			sample.Max != exact.Max ||
+			math.Abs(sample.Mean-exact.Mean) > 1e-9*math.Abs(exact.Mean) {
+			t.Fatal(d.name, sample, exact)
+		}
+		for _, p := range []float64{1, 10, 25, 50, 75, 90, 99} {
+			q := p / 100
+			// exact percentiles of n samples, within a few standard
+			// deviations of rank error
+			tol := 4 * math.Sqrt(q*(1-q)/n)
+			if x := exact.Percentile(p); x < d.cdf(q-tol) || x > d.cdf(q+tol) {
+				t.Fatal(d.name, "exact", p, x, d.cdf(q))
+			}
+			// sample rank error is dominated by the sample size
+			tol = 4 * math.Sqrt(q*(1-q)/10000)
+			if x := sample.Percentile(p); x < d.cdf(q-tol) || x > d.cdf(q+tol) {
+				t.Fatal(d.name, "sample", p, x, d.cdf(q))
+			}
+		}
+		// monotone bins of approximately equal frequency
+		const bins = 8
+		binOf, edges := g.Quantize(w, bins)
+		if len(edges) != bins+1 {
+			t.Fatal(d.name, edges)
+		}
+		count := make([]int, bins)
+		for l := range wt {
+			b := binOf(graph.LI(l))
+			count[b]++
+			for _, x := range []int{l - 1, l + 1} {
+				if x < 0 || x == n {
+					continue
+				}
+				if wt[x] > wt[l] && binOf(graph.LI(x)) < b ||
+					wt[x] < wt[l] && binOf(graph.LI(x)) > b {
+					t.Fatal(d.name, "not monotone", wt[x], wt[l])
+				}
+			}
+		}
+		for _, c := range count {
+			if c < n/bins-1 || c > n/bins+1 {
+				t.Fatal(d.name, "bin counts", count)
+			}
+		}
+	}
+	// empty
+	var e graph.LabeledAdjacencyList
+	if s := e.WeightStats(nil); s.Count != 0 || !math.IsNaN(s.Percentile(50)) {
+		t.Fatal(s)
+	}
+	if s := e.WeightSample(nil, 10, nil); s.Count != 0 {
+		t.Fatal(s)
+	}
+	// small graph, sample holds all
+	wt := []float64{3, 1, 2}
+	w := func(l graph.LI) float64 { return wt[l] }
+	if s := weightStar(3).WeightSample(w, 10, nil); !s.Exact() ||
+		s.Percentile(50) != 2 {
+		t.Fatal(s)
+	}
+}